@@ -3,6 +3,7 @@ package dto
 import (
 	"Goshop/domain/entity"
 	"errors"
+	"time"
 )
 
 type CustomerRequestDto struct {
@@ -16,6 +17,8 @@ type CustomerResponseDto struct {
 	FirstName string `json:"first_name"`
 	LastName  string `json:"last_name"`
 	Email     string `json:"email"`
+	// DeletedAt : date d'archivage, seulement pour un client archivé
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 func ToCustomerRequest(c *entity.Customer) *CustomerRequestDto {
@@ -44,6 +47,7 @@ func ToCustomerResponse(c *entity.Customer) *CustomerResponseDto {
 		FirstName: c.FirstName,
 		LastName:  c.LastName,
 		Email:     c.Email,
+		DeletedAt: c.DeletedAt,
 	}
 }
 
@@ -6,4 +6,6 @@ package dto
 type CustomerFilter struct {
 	Name  *string `json:"name,omitempty"`  // filtre partiel sur prénom OU nom
 	Email *string `json:"email,omitempty"` // filtre exact ou partiel sur email
+	// IncludeDeleted : clients archivés inclus (back-office uniquement)
+	IncludeDeleted bool `json:"include_deleted,omitempty"`
 }
@@ -4,12 +4,15 @@ import "errors"
 
 type OrderItemRequestDto struct {
 	ProductID string `json:"product_id"`
+	// VariantID : absent, l'article porte sur la variante par défaut du produit
+	VariantID string `json:"variant_id,omitempty"`
 	Quantity  int    `json:"quantity"`
 }
 
 type OrderItemResponseDto struct {
 	ID            string `json:"id"`
 	ProductID     string `json:"product_id"`
+	VariantID     string `json:"variant_id,omitempty"`
 	Quantity      int    `json:"quantity"`
 	PriceCents    int64  `json:"price_cents"`
 	SubTotalCents int64  `json:"sub_total_cents"`
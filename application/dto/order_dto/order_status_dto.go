@@ -0,0 +1,51 @@
+package orderdto
+
+import (
+	"Goshop/domain/entity"
+	"errors"
+	"time"
+)
+
+// application/dto/order_dto/order_status_dto.go
+
+// UpdateOrderStatusRequestDto est le corps de PATCH /api/orders/{id}/status
+type UpdateOrderStatusRequestDto struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// CancelOrderRequestDto est le corps (optionnel) de POST /api/orders/{id}/cancel
+type CancelOrderRequestDto struct {
+	Reason string `json:"reason"`
+}
+
+type OrderStatusHistoryResponseDto struct {
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	ChangedBy  string    `json:"changed_by"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (r *UpdateOrderStatusRequestDto) Validate() error {
+	if r.Status == "" {
+		return errors.New("status is required")
+	}
+
+	if !entity.IsValidOrderStatus(r.Status) {
+		return errors.New("unknown status")
+	}
+
+	if len(r.Reason) > 500 {
+		return errors.New("reason must be at most 500 characters")
+	}
+
+	return nil
+}
+
+func (r *CancelOrderRequestDto) Validate() error {
+	if len(r.Reason) > 500 {
+		return errors.New("reason must be at most 500 characters")
+	}
+	return nil
+}
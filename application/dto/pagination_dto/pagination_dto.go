@@ -0,0 +1,69 @@
+// application/dto/pagination_dto/pagination_dto.go
+package paginationdto
+
+import "time"
+
+// Cursor identifie une position dans une liste triée par (created_at DESC, id DESC).
+// Le couple est unique, ce qui rend la pagination stable même quand des lignes
+// sont insérées ou supprimées entre deux pages.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// CursorPage est la demande de page transmise aux repositories.
+//   - After nil : première page
+//   - Backward false : éléments plus anciens que After (page suivante)
+//   - Backward true : éléments plus récents que After (page précédente)
+type CursorPage struct {
+	Limit    int
+	After    *Cursor
+	Backward bool
+}
+
+// CursorResult est une page produite par un usecase, avec la position de son
+// premier et de son dernier élément pour construire les curseurs suivants.
+type CursorResult[T any] struct {
+	Items   []T
+	First   *Cursor
+	Last    *Cursor
+	HasMore bool // il reste des éléments dans le sens de lecture
+}
+
+// PageInfo décrit la pagination dans l'enveloppe de réponse
+type PageInfo struct {
+	Limit      int    `json:"limit"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// Page est l'enveloppe commune des listes paginées par curseur
+type Page[T any] struct {
+	Data       []T      `json:"data"`
+	Pagination PageInfo `json:"pagination"`
+}
+
+// TrimPage retire l'élément sentinelle d'une page demandée avec limit+1
+// éléments et indique s'il en reste. Les éléments sont dans l'ordre
+// d'affichage : en lecture arrière, l'élément en trop est le premier.
+func TrimPage[T any](items []T, limit int, backward bool) ([]T, bool) {
+	if len(items) <= limit {
+		return items, false
+	}
+	if backward {
+		return items[len(items)-limit:], true
+	}
+	return items[:limit], true
+}
+
+// NewCursorResult construit une page à partir des éléments lus (déjà tronqués
+// par TrimPage), de leur position et de leur représentation de sortie.
+func NewCursorResult[T, R any](items []T, hasMore bool, position func(T) Cursor, data []R) *CursorResult[R] {
+	result := &CursorResult[R]{Items: data, HasMore: hasMore}
+	if len(items) > 0 {
+		first, last := position(items[0]), position(items[len(items)-1])
+		result.First, result.Last = &first, &last
+	}
+	return result
+}
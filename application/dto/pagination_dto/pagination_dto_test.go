@@ -0,0 +1,27 @@
+package paginationdto_test
+
+import (
+	"testing"
+
+	paginationdto "Goshop/application/dto/pagination_dto"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrimPage(t *testing.T) {
+	// Éléments dans l'ordre d'affichage, limit+1 demandés
+	items := []string{"a", "b", "c", "d"}
+
+	page, hasMore := paginationdto.TrimPage(items, 3, false)
+	assert.Equal(t, []string{"a", "b", "c"}, page)
+	assert.True(t, hasMore)
+
+	// En lecture arrière, l'élément en trop est le plus récent (le premier)
+	page, hasMore = paginationdto.TrimPage(items, 3, true)
+	assert.Equal(t, []string{"b", "c", "d"}, page)
+	assert.True(t, hasMore)
+
+	page, hasMore = paginationdto.TrimPage(items[:2], 3, false)
+	assert.Equal(t, []string{"a", "b"}, page)
+	assert.False(t, hasMore)
+}
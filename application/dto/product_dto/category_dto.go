@@ -0,0 +1,96 @@
+// application/dto/product_dto/category_dto.go
+package dto
+
+import (
+	"Goshop/domain/entity"
+	"errors"
+	"strings"
+)
+
+// CategoryRequest : création (POST) ou remplacement (PUT) d'une catégorie
+type CategoryRequest struct {
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`                // minuscules, chiffres et tirets
+	ParentID string `json:"parent_id,omitempty"` // vide : catégorie racine
+}
+
+func (r *CategoryRequest) Validate() error {
+	name := strings.TrimSpace(r.Name)
+	if name == "" {
+		return errors.New("name is required")
+	}
+	if len(name) > 100 {
+		return errors.New("name must be at most 100 characters")
+	}
+	if !entity.IsValidCategorySlug(r.Slug) {
+		return errors.New("slug must contain only lowercase letters, digits and single dashes")
+	}
+	return nil
+}
+
+// CategoryRef : élément d'un fil d'Ariane
+type CategoryRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type CategoryResponse struct {
+	ID         string              `json:"id"`
+	Name       string              `json:"name"`
+	Slug       string              `json:"slug"`
+	ParentID   *string             `json:"parent_id"`
+	Path       string              `json:"path"`
+	Breadcrumb []CategoryRef       `json:"breadcrumb,omitempty"` // de la racine à la catégorie
+	Children   []*CategoryResponse `json:"children,omitempty"`
+	CreatedAt  string              `json:"created_at"`
+	UpdatedAt  string              `json:"updated_at"`
+}
+
+// ProductCategoryResponse : catégorie d'un produit et son fil d'Ariane
+type ProductCategoryResponse struct {
+	ID         string        `json:"id"`
+	Name       string        `json:"name"`
+	Slug       string        `json:"slug"`
+	Breadcrumb []CategoryRef `json:"breadcrumb"`
+}
+
+func NewCategoryRefs(categories []*entity.Category) []CategoryRef {
+	refs := make([]CategoryRef, 0, len(categories))
+	for _, c := range categories {
+		refs = append(refs, CategoryRef{ID: c.ID, Name: c.Name, Slug: c.Slug})
+	}
+	return refs
+}
+
+func NewCategoryResponse(c *entity.Category) *CategoryResponse {
+	resp := &CategoryResponse{
+		ID:        c.ID,
+		Name:      c.Name,
+		Slug:      c.Slug,
+		Path:      c.Path,
+		CreatedAt: c.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt: c.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if c.ParentID != "" {
+		parentID := c.ParentID
+		resp.ParentID = &parentID
+	}
+	return resp
+}
+
+// NewCategoryTree imbrique les catégories, triées par path, sous leur parent
+func NewCategoryTree(categories []*entity.Category) []*CategoryResponse {
+	roots := []*CategoryResponse{}
+	byID := make(map[string]*CategoryResponse, len(categories))
+	for _, c := range categories {
+		node := NewCategoryResponse(c)
+		byID[c.ID] = node
+		if parent, ok := byID[c.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	return roots
+}
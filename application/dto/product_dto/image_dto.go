@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"errors"
+
+	"Goshop/domain/entity"
+)
+
+type ImageResponse struct {
+	ID string `json:"id"`
+	// URL : contenu de l'image, servi par l'API
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	SizeBytes   int64  `json:"size_bytes"`
+	// Position : 0 pour l'image principale
+	Position  int    `json:"position"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ReorderImagesRequest : toutes les images du produit, dans le nouvel ordre
+type ReorderImagesRequest struct {
+	ImageIDs []string `json:"image_ids"`
+}
+
+func (r *ReorderImagesRequest) Validate() error {
+	if len(r.ImageIDs) == 0 || len(r.ImageIDs) > entity.MaxProductImages {
+		return errors.New("image_ids must list the product images")
+	}
+	seen := make(map[string]bool, len(r.ImageIDs))
+	for _, id := range r.ImageIDs {
+		if id == "" || seen[id] {
+			return errors.New("image_ids must not contain empty or duplicate ids")
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+func NewImageResponse(image *entity.ProductImage) ImageResponse {
+	return ImageResponse{
+		ID:          image.ID,
+		URL:         "/api/products/" + image.ProductID + "/images/" + image.ID,
+		ContentType: image.ContentType,
+		SizeBytes:   image.SizeBytes,
+		Position:    image.Position,
+		CreatedAt:   image.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -1,12 +1,19 @@
 package dto
 
-import "errors"
+import (
+	"errors"
+
+	"Goshop/domain/entity"
+)
 
 type CreateProductRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	PriceCents  int64  `json:"price_cents"`
-	Stock       int    `json:"stock"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	PriceCents  int64    `json:"price_cents"`
+	Stock       int      `json:"stock"`
+	CategoryIDs []string `json:"category_ids,omitempty"`
+	// SKU de la variante par défaut, "SKU-<id>" si absent
+	SKU string `json:"sku,omitempty"`
 }
 
 type ProductResponse struct {
@@ -17,13 +24,48 @@ type ProductResponse struct {
 	Stock       int    `json:"stock"`
 	CreatedAt   string `json:"created_at"`
 	UpdatedAt   string `json:"updated_at"`
+	// DeletedAt : date d'archivage, seulement pour un produit archivé
+	// (?include_deleted=true)
+	DeletedAt string `json:"deleted_at,omitempty"`
+
+	// Categories : catégories du produit avec leur fil d'Ariane
+	Categories []ProductCategoryResponse `json:"categories,omitempty"`
+
+	// Variants : déclinaisons du produit, variante par défaut en tête
+	Variants []VariantResponse `json:"variants,omitempty"`
+
+	// PrimaryImage : première image du produit (GET /api/products/{id}/images
+	// pour la liste complète)
+	PrimaryImage *ImageResponse `json:"primary_image,omitempty"`
+}
+
+// NewProductResponse : réponse sans catégories, variantes ni image (chargées
+// à part)
+func NewProductResponse(p *entity.Product) *ProductResponse {
+	resp := &ProductResponse{
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		PriceCents:  p.PriceCents,
+		Stock:       p.Stock,
+		CreatedAt:   p.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:   p.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if p.DeletedAt != nil {
+		resp.DeletedAt = p.DeletedAt.Format("2006-01-02 15:04:05")
+	}
+	return resp
 }
 
 type UpdateProductRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	PriceCents  int64  `json:"price_cents"`
-	Stock       int    `json:"stock"`
+	// Stock : stock de la variante par défaut ; le stock renvoyé reste le
+	// total de toutes les variantes
+	Stock int `json:"stock"`
+	// CategoryIDs : absent, les catégories sont conservées ; [] les retire toutes
+	CategoryIDs *[]string `json:"category_ids,omitempty"`
 }
 
 func (p *CreateProductRequest) Validate() error {
@@ -39,6 +81,10 @@ func (p *CreateProductRequest) Validate() error {
 		return errors.New("stock cannot be negative")
 	}
 
+	if p.SKU != "" && !entity.IsValidSKU(p.SKU) {
+		return errors.New("sku must be 1 to 64 letters, digits, '.', '_' or '-'")
+	}
+
 	return nil
 }
 
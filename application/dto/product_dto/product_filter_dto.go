@@ -0,0 +1,95 @@
+// application/dto/product_dto/product_filter_dto.go
+package dto
+
+import (
+	"Goshop/domain/entity"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Clés de tri acceptées par GET /api/products (préfixe "-" = ordre décroissant)
+const (
+	ProductSortCreatedAt = "created_at"
+	ProductSortPrice     = "price"
+	ProductSortName      = "name"
+	ProductSortRelevance = "relevance" // uniquement avec une recherche plein texte
+)
+
+const ProductQueryMaxLength = 200
+
+var allowedProductSorts = map[string]bool{
+	ProductSortCreatedAt: true,
+	ProductSortPrice:     true,
+	ProductSortName:      true,
+	ProductSortRelevance: true,
+}
+
+// ProductFilter représente les critères optionnels de recherche du catalogue.
+// Les pointeurs distinguent "non fourni" de "valeur zéro".
+type ProductFilter struct {
+	Query         *string    `json:"q,omitempty"`             // recherche plein texte sur nom + description
+	MinPriceCents *int64     `json:"min_price,omitempty"`     // bornes incluses
+	MaxPriceCents *int64     `json:"max_price,omitempty"`     //
+	InStock       bool       `json:"in_stock,omitempty"`      // stock > 0 uniquement
+	CreatedAfter  *time.Time `json:"created_after,omitempty"` // strictement après
+	CategorySlug  *string    `json:"category,omitempty"`      // catégorie et ses descendants
+	// IncludeDeleted : produits archivés inclus (back-office uniquement)
+	IncludeDeleted bool `json:"include_deleted,omitempty"`
+
+	SortBy   string `json:"sort_by,omitempty"` // une des clés ProductSort*, vide = défaut
+	SortDesc bool   `json:"sort_desc,omitempty"`
+}
+
+// SetSort valide une clé de tri du type "price" ou "-price"
+func (f *ProductFilter) SetSort(raw string) error {
+	raw = strings.TrimSpace(raw)
+	desc := strings.HasPrefix(raw, "-")
+	key := strings.TrimPrefix(raw, "-")
+
+	if !allowedProductSorts[key] {
+		return errors.New("sort must be one of created_at, price, name, relevance (prefix with - for descending)")
+	}
+
+	f.SortBy = key
+	f.SortDesc = desc
+	return nil
+}
+
+// EffectiveSort renvoie le tri appliqué : pertinence si une recherche est
+// fournie, sinon les produits les plus récents d'abord.
+func (f ProductFilter) EffectiveSort() (key string, desc bool) {
+	if f.SortBy != "" {
+		return f.SortBy, f.SortDesc
+	}
+	if f.HasQuery() {
+		return ProductSortRelevance, true
+	}
+	return ProductSortCreatedAt, true
+}
+
+func (f ProductFilter) HasQuery() bool {
+	return f.Query != nil && strings.TrimSpace(*f.Query) != ""
+}
+
+func (f ProductFilter) Validate() error {
+	if f.Query != nil && len(*f.Query) > ProductQueryMaxLength {
+		return errors.New("q is too long")
+	}
+	if f.MinPriceCents != nil && *f.MinPriceCents < 0 {
+		return errors.New("min_price cannot be negative")
+	}
+	if f.MaxPriceCents != nil && *f.MaxPriceCents < 0 {
+		return errors.New("max_price cannot be negative")
+	}
+	if f.MinPriceCents != nil && f.MaxPriceCents != nil && *f.MinPriceCents > *f.MaxPriceCents {
+		return errors.New("min_price cannot be greater than max_price")
+	}
+	if f.CategorySlug != nil && !entity.IsValidCategorySlug(*f.CategorySlug) {
+		return errors.New("category must be a category slug")
+	}
+	if f.SortBy == ProductSortRelevance && !f.HasQuery() {
+		return errors.New("sort=relevance requires q")
+	}
+	return nil
+}
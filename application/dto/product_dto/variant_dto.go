@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"errors"
+
+	"Goshop/domain/entity"
+)
+
+// VariantRequest : création ou modification d'une variante
+type VariantRequest struct {
+	SKU     string            `json:"sku"`
+	Options map[string]string `json:"options"`
+	// PriceCents : absent, la variante est vendue au prix du produit
+	PriceCents *int64 `json:"price_cents,omitempty"`
+	Stock      int    `json:"stock"`
+}
+
+type VariantResponse struct {
+	ID      string            `json:"id"`
+	SKU     string            `json:"sku"`
+	Options map[string]string `json:"options"`
+	// PriceCents : prix de vente effectif de la variante
+	PriceCents int64 `json:"price_cents"`
+	// PriceOverrideCents : prix propre à la variante, absent si elle hérite
+	// du prix du produit
+	PriceOverrideCents *int64 `json:"price_override_cents,omitempty"`
+	Stock              int    `json:"stock"`
+	IsDefault          bool   `json:"is_default"`
+}
+
+func (r *VariantRequest) Validate() error {
+	if !entity.IsValidSKU(r.SKU) {
+		return errors.New("sku must be 1 to 64 letters, digits, '.', '_' or '-'")
+	}
+	if !entity.IsValidVariantOptions(r.Options) {
+		return errors.New("options must be at most 5 lowercase names with non-empty values")
+	}
+	if r.PriceCents != nil && *r.PriceCents <= 0 {
+		return errors.New("price must be greater than 0")
+	}
+	if r.Stock < 0 {
+		return errors.New("stock cannot be negative")
+	}
+	return nil
+}
+
+func NewVariantResponse(v *entity.ProductVariant, productPriceCents int64) VariantResponse {
+	options := v.Options
+	if options == nil {
+		options = map[string]string{}
+	}
+	return VariantResponse{
+		ID:                 v.ID,
+		SKU:                v.SKU,
+		Options:            options,
+		PriceCents:         v.EffectivePrice(productPriceCents),
+		PriceOverrideCents: v.PriceCents,
+		Stock:              v.Stock,
+		IsDefault:          v.IsDefault,
+	}
+}
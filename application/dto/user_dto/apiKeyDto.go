@@ -0,0 +1,48 @@
+package userdto
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// CreateAPIKeyRequest : nouvelle clé d'API d'un client machine
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`               // permissions accordées à la clé
+	RateLimit int        `json:"rate_limit,omitempty"` // requêtes par minute (défaut : 600)
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func (r *CreateAPIKeyRequest) Validate() error {
+	name := strings.TrimSpace(r.Name)
+	if name == "" {
+		return errors.New("name is required")
+	}
+	if len(name) > 100 {
+		return errors.New("name must be at most 100 characters")
+	}
+	if r.RateLimit < 0 {
+		return errors.New("rate_limit must be positive")
+	}
+	return nil
+}
+
+// APIKeyResponse : clé d'API sans son secret
+type APIKeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	RateLimit  int        `json:"rate_limit"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateAPIKeyResponse : la clé en clair n'est retournée qu'à la création
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
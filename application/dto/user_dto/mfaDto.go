@@ -0,0 +1,45 @@
+package userdto
+
+import (
+	"errors"
+	"strings"
+)
+
+// LoginMFARequest : seconde étape d'un login à deux facteurs
+type LoginMFARequest struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"` // code TOTP ou code de récupération
+}
+
+func (r *LoginMFARequest) Validate() error {
+	if strings.TrimSpace(r.MFAToken) == "" {
+		return errors.New("mfa_token is required")
+	}
+	if strings.TrimSpace(r.Code) == "" {
+		return errors.New("code is required")
+	}
+	return nil
+}
+
+type ConfirmMFARequest struct {
+	Code string `json:"code"`
+}
+
+func (r *ConfirmMFARequest) Validate() error {
+	if strings.TrimSpace(r.Code) == "" {
+		return errors.New("code is required")
+	}
+	return nil
+}
+
+// MFAEnrollResponse : secret à saisir dans l'application d'authentification,
+// ou URI otpauth:// à afficher en QR code
+type MFAEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// MFAConfirmResponse : codes de récupération, affichés une seule fois
+type MFAConfirmResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -0,0 +1,74 @@
+package userdto
+
+import (
+	"errors"
+	"strings"
+)
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+func (r *ForgotPasswordRequest) Validate() error {
+	if !strings.Contains(r.Email, "@") {
+		return errors.New("invalid email")
+	}
+	return nil
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// Validate applique au nouveau mot de passe les règles de l'inscription
+func (r *ResetPasswordRequest) Validate() error {
+	if strings.TrimSpace(r.Token) == "" {
+		return errors.New("token is required")
+	}
+	if len(r.Password) < 6 {
+		return errors.New("password must be at least 6 chars")
+	}
+	return nil
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+func (r *VerifyEmailRequest) Validate() error {
+	if strings.TrimSpace(r.Token) == "" {
+		return errors.New("token is required")
+	}
+	return nil
+}
+
+// ChangePasswordRequest : le refresh token (facultatif) désigne la session à
+// conserver, les autres sont révoquées
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+	RefreshToken    string `json:"refresh_token,omitempty"`
+}
+
+func (r *ChangePasswordRequest) Validate() error {
+	if r.CurrentPassword == "" {
+		return errors.New("current password is required")
+	}
+	if len(r.NewPassword) < 6 {
+		return errors.New("password must be at least 6 chars")
+	}
+	return nil
+}
+
+// DeleteAccountRequest : confirmation de l'effacement par le mot de passe
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+func (r *DeleteAccountRequest) Validate() error {
+	if r.Password == "" {
+		return errors.New("password is required")
+	}
+	return nil
+}
@@ -11,6 +11,22 @@ type RegisterUserRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 	Name     string `json:"name,omitempty"`
+
+	// Profil client créé avec le compte (à défaut, déduit de Name)
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+}
+
+// ProfileNames retourne prénom et nom du profil client. Sans first_name ni
+// last_name, Name est découpé au premier espace ("Ada Lovelace").
+func (r *RegisterUserRequest) ProfileNames() (firstName, lastName string) {
+	firstName = strings.TrimSpace(r.FirstName)
+	lastName = strings.TrimSpace(r.LastName)
+	if firstName != "" || lastName != "" {
+		return firstName, lastName
+	}
+	firstName, lastName, _ = strings.Cut(strings.TrimSpace(r.Name), " ")
+	return firstName, strings.TrimSpace(lastName)
 }
 
 type LoginRequest struct {
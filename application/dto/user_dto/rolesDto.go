@@ -0,0 +1,23 @@
+package userdto
+
+import (
+	"errors"
+	"strings"
+)
+
+type GrantRoleRequest struct {
+	Role string `json:"role"`
+}
+
+func (r *GrantRoleRequest) Validate() error {
+	if strings.TrimSpace(r.Role) == "" {
+		return errors.New("role is required")
+	}
+	return nil
+}
+
+type UserRolesResponse struct {
+	UserID      string   `json:"user_id"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
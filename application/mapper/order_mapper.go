@@ -12,6 +12,7 @@ func ToOrderEntity(req *orderdto.OrderRequestDto) *entity.Order {
 	for i, it := range req.Items {
 		items[i] = &entity.OrderItem{
 			ProductID: it.ProductID,
+			VariantID: it.VariantID,
 			Quantity:  int(it.Quantity),
 		}
 	}
@@ -29,6 +30,7 @@ func ToOrderResponse(order *entity.Order) *orderdto.OrderResponseDto {
 		items[i] = &orderitemdto.OrderItemResponseDto{
 			ID:            it.ID,
 			ProductID:     it.ProductID,
+			VariantID:     it.VariantID,
 			Quantity:      int(it.Quantity),
 			PriceCents:    it.PriceCents,
 			SubTotalCents: int64(it.SubTotal_Cents),
@@ -43,3 +45,19 @@ func ToOrderResponse(order *entity.Order) *orderdto.OrderResponseDto {
 		Items:      items,
 	}
 }
+
+func ToOrderStatusHistoryResponse(history []*entity.OrderStatusHistory) []*orderdto.OrderStatusHistoryResponseDto {
+	response := make([]*orderdto.OrderStatusHistoryResponseDto, len(history))
+
+	for i, h := range history {
+		response[i] = &orderdto.OrderStatusHistoryResponseDto{
+			FromStatus: h.FromStatus,
+			ToStatus:   h.ToStatus,
+			ChangedBy:  h.ChangedBy,
+			Reason:     h.Reason,
+			CreatedAt:  h.CreatedAt,
+		}
+	}
+
+	return response
+}
@@ -23,6 +23,22 @@ var (
 		Name: "goshop_auth_register_total",
 		Help: "Total number of successful user registrations",
 	})
+	// Rejeu d'un refresh token déjà tourné : la famille de sessions est révoquée
+	AuthRefreshReuseDetectedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "goshop_auth_refresh_reuse_detected_total",
+		Help: "Total number of replayed refresh tokens leading to a session family revocation",
+	})
+	// Tentatives de login refusées par la protection force brute
+	// (reason : account_locked, throttled, ip_blocked)
+	AuthLoginBlockedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "goshop_auth_login_blocked_total",
+		Help: "Total number of login attempts rejected by brute-force protection",
+	}, []string{"reason"})
+	// Comptes verrouillés après trop d'échecs
+	AuthAccountLockoutsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "goshop_auth_account_lockouts_total",
+		Help: "Total number of temporary account lockouts",
+	})
 )
 
 // NOUVEAUX HISTOGRAMMES
@@ -54,6 +70,16 @@ var (
 		Name: "goshop_products_created_total",
 		Help: "Total number of products created",
 	})
+	// Lectures GET /api/products/{id} servies par le cache Redis, ou non
+	// (absent, expiré ou Redis indisponible)
+	ProductCacheHitsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "goshop_product_cache_hits_total",
+		Help: "Total number of product reads served from the cache",
+	})
+	ProductCacheMissesTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "goshop_product_cache_misses_total",
+		Help: "Total number of product reads that fell through to the database",
+	})
 )
 
 var (
@@ -87,6 +113,17 @@ var (
 		Name: "goshop_orders_revenue_cents_total",
 		Help: "Total revenue generated by orders (in cents)",
 	})
+	OrdersCancelledTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "goshop_orders_cancelled_total",
+		Help: "Total number of cancelled orders",
+	})
+	OrdersStatusTransitionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "goshop_orders_status_transitions_total",
+			Help: "Total number of order status transitions",
+		},
+		[]string{"from", "to"},
+	)
 )
 
 var (
@@ -120,4 +157,13 @@ var (
 		},
 		[]string{"method", "path", "status"},
 	)
+
+	// outcome : executed, replayed, mismatch, in_progress
+	IdempotentRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "goshop_idempotent_requests_total",
+			Help: "Total number of requests carrying an Idempotency-Key, by outcome",
+		},
+		[]string{"outcome"},
+	)
 )
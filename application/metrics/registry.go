@@ -17,6 +17,9 @@ func RegisterMetrics() {
 		prometheus.MustRegister(AuthLoginTotal)
 		prometheus.MustRegister(AuthLoginFailedTotal)
 		prometheus.MustRegister(AuthRegisterTotal)
+		prometheus.MustRegister(AuthRefreshReuseDetectedTotal)
+		prometheus.MustRegister(AuthLoginBlockedTotal)
+		prometheus.MustRegister(AuthAccountLockoutsTotal)
 
 		// Histogrammes auth
 		prometheus.MustRegister(AuthLoginDuration)
@@ -25,6 +28,8 @@ func RegisterMetrics() {
 
 		// Produits
 		prometheus.MustRegister(ProductsCreatedTotal)
+		prometheus.MustRegister(ProductCacheHitsTotal)
+		prometheus.MustRegister(ProductCacheMissesTotal)
 		prometheus.MustRegister(ProductsCreateDuration)
 		prometheus.MustRegister(ProductsGetDuration)
 		prometheus.MustRegister(ProductsListDuration)
@@ -32,11 +37,14 @@ func RegisterMetrics() {
 		// Commandes
 		prometheus.MustRegister(OrdersCreatedTotal)
 		prometheus.MustRegister(OrdersRevenueCentsTotal)
+		prometheus.MustRegister(OrdersCancelledTotal)
+		prometheus.MustRegister(OrdersStatusTransitionsTotal)
 		prometheus.MustRegister(OrdersCreateDuration)
 		prometheus.MustRegister(OrdersGetDuration)
 		prometheus.MustRegister(OrdersListDuration)
 
 		// HTTP
 		prometheus.MustRegister(HTTPRequestDuration)
+		prometheus.MustRegister(IdempotentRequestsTotal)
 	})
 }
@@ -0,0 +1,224 @@
+package authusecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	authentity "Goshop/domain/auth_entity"
+	userentity "Goshop/domain/entity/user_entity"
+	authrepository "Goshop/domain/repository/auth_repository"
+	"Goshop/interfaces/utils"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultAPIKeyRateLimit : requêtes par minute d'une clé créée sans limite explicite
+	DefaultAPIKeyRateLimit = 600
+
+	// apiKeyScheme préfixe chaque clé ("gsk_<prefix>_<secret>") pour qu'une
+	// clé divulguée soit facile à reconnaître
+	apiKeyScheme     = "gsk_"
+	apiKeyPrefixLen  = 6  // octets, 12 caractères hex
+	apiKeySecretLen  = 32 // octets
+	apiKeyRateWindow = time.Minute
+)
+
+// NewAPIKey : clé d'API demandée par un administrateur
+type NewAPIKey struct {
+	Name      string
+	Scopes    []string
+	RateLimit int       // requêtes par minute, DefaultAPIKeyRateLimit si zéro
+	ExpiresAt time.Time // zéro : pas d'expiration
+}
+
+// APIKeyUsecase : gestion des clés d'API (back-office) et authentification
+// des clients machine
+type APIKeyUsecase struct {
+	keys    authrepository.APIKeyRepository
+	counter authrepository.RateCounter
+	now     func() time.Time
+	newID   func() string
+}
+
+func NewAPIKeyUsecase(keys authrepository.APIKeyRepository, counter authrepository.RateCounter, now func() time.Time) *APIKeyUsecase {
+	return &APIKeyUsecase{
+		keys:    keys,
+		counter: counter,
+		now:     now,
+		newID:   uuid.NewString,
+	}
+}
+
+// Create enregistre une nouvelle clé et retourne sa valeur en clair : elle
+// n'est affichée qu'une fois, seul le hash du secret est stocké.
+func (uc *APIKeyUsecase) Create(ctx context.Context, createdBy string, in NewAPIKey) (*authentity.APIKey, string, error) {
+	logger := zerolog.Ctx(ctx)
+
+	for _, scope := range in.Scopes {
+		if !userentity.IsKnownPermission(scope) {
+			return nil, "", utils.ErrAPIKeyScopeInvalid
+		}
+	}
+	if in.RateLimit < 0 {
+		return nil, "", utils.ErrValidationFailed
+	}
+	if in.RateLimit == 0 {
+		in.RateLimit = DefaultAPIKeyRateLimit
+	}
+	now := uc.now()
+	if !in.ExpiresAt.IsZero() && !in.ExpiresAt.After(now) {
+		return nil, "", utils.ErrValidationFailed
+	}
+
+	prefix, secret, err := newAPIKeySecret()
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "api_key_create").Msg("Failed to generate API key")
+		return nil, "", utils.ErrInternalServer
+	}
+
+	scopes := in.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	key := &authentity.APIKey{
+		ID:         uc.newID(),
+		Name:       strings.TrimSpace(in.Name),
+		Prefix:     prefix,
+		SecretHash: hashUserToken(secret),
+		Scopes:     scopes,
+		RateLimit:  in.RateLimit,
+		ExpiresAt:  in.ExpiresAt,
+		CreatedBy:  createdBy,
+		CreatedAt:  now,
+	}
+	if err := uc.keys.Create(ctx, key); err != nil {
+		logger.Error().Err(err).Str("operation", "api_key_create").Msg("Failed to save API key")
+		return nil, "", utils.ErrInternalServer
+	}
+
+	utils.SecurityAudit(ctx, utils.AUDIT_API_KEY_CREATED).
+		Str("actor_id", maskUserID(createdBy)).
+		Str("api_key_id", key.ID).
+		Str("api_key_prefix", key.Prefix).
+		Strs("scopes", key.Scopes).
+		Msg("API key created")
+
+	return key, apiKeyScheme + prefix + "_" + secret, nil
+}
+
+// List retourne toutes les clés, révoquées comprises
+func (uc *APIKeyUsecase) List(ctx context.Context) ([]*authentity.APIKey, error) {
+	keys, err := uc.keys.List(ctx)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Str("operation", "api_key_list").Msg("Failed to list API keys")
+		return nil, utils.ErrInternalServer
+	}
+	return keys, nil
+}
+
+// Revoke désactive définitivement une clé
+func (uc *APIKeyUsecase) Revoke(ctx context.Context, actorID, id string) error {
+	err := uc.keys.Revoke(ctx, id)
+	if errors.Is(err, authrepository.ErrAPIKeyNotFound) {
+		return utils.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Str("operation", "api_key_revoke").Msg("Failed to revoke API key")
+		return utils.ErrInternalServer
+	}
+
+	utils.SecurityAudit(ctx, utils.AUDIT_API_KEY_REVOKED).
+		Str("actor_id", maskUserID(actorID)).
+		Str("api_key_id", id).
+		Msg("API key revoked")
+	return nil
+}
+
+// Authenticate vérifie une clé présentée par un client et décompte la requête
+// de sa limite par minute. utils.ErrAPIKeyInvalid pour une clé inconnue,
+// révoquée ou expirée ; utils.ErrAPIKeyRateLimited (avec Retry-After) au-delà
+// de la limite.
+func (uc *APIKeyUsecase) Authenticate(ctx context.Context, raw string) (*authentity.APIKey, error) {
+	logger := zerolog.Ctx(ctx)
+
+	prefix, secret, ok := parseAPIKey(raw)
+	if !ok {
+		return nil, utils.ErrAPIKeyInvalid
+	}
+
+	key, err := uc.keys.FindByPrefix(ctx, prefix)
+	if errors.Is(err, authrepository.ErrAPIKeyNotFound) {
+		return nil, utils.ErrAPIKeyInvalid
+	}
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "api_key_auth").Msg("Failed to load API key")
+		return nil, utils.ErrInternalServer
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashUserToken(secret)), []byte(key.SecretHash)) != 1 {
+		logger.Warn().
+			Str("operation", "api_key_auth").
+			Str("api_key_prefix", prefix).
+			Msg("API key secret mismatch")
+		return nil, utils.ErrAPIKeyInvalid
+	}
+
+	now := uc.now()
+	if !key.Active(now) {
+		return nil, utils.ErrAPIKeyInvalid
+	}
+
+	count, err := uc.counter.Incr(ctx, "apikey:"+key.ID, apiKeyRateWindow)
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "api_key_auth").Msg("Failed to count API key request")
+		return nil, utils.ErrInternalServer
+	}
+	if count > int64(key.RateLimit) {
+		// Fenêtres alignées sur l'horloge : la suivante commence à la minute pleine
+		wait := now.Truncate(apiKeyRateWindow).Add(apiKeyRateWindow).Sub(now)
+		return nil, utils.WithRetryAfter(utils.ErrAPIKeyRateLimited, wait)
+	}
+
+	// Suivi d'usage seulement : un échec n'empêche pas la requête
+	if err := uc.keys.TouchLastUsed(ctx, key.ID, now); err != nil {
+		logger.Warn().Err(err).Str("operation", "api_key_auth").Msg("Failed to record API key usage")
+	}
+
+	return key, nil
+}
+
+// newAPIKeySecret génère la partie publique (recherche en base) et le secret
+// (256 bits) d'une clé
+func newAPIKeySecret() (prefix, secret string, err error) {
+	p := make([]byte, apiKeyPrefixLen)
+	if _, err := rand.Read(p); err != nil {
+		return "", "", err
+	}
+	s := make([]byte, apiKeySecretLen)
+	if _, err := rand.Read(s); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(p), base64.RawURLEncoding.EncodeToString(s), nil
+}
+
+// parseAPIKey découpe "gsk_<prefix>_<secret>". Le préfixe est en hex : le
+// premier "_" qui suit le sépare du secret, qui peut lui-même en contenir.
+func parseAPIKey(raw string) (prefix, secret string, ok bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(raw), apiKeyScheme)
+	if !ok {
+		return "", "", false
+	}
+	prefix, secret, ok = strings.Cut(rest, "_")
+	if !ok || len(prefix) != 2*apiKeyPrefixLen || secret == "" {
+		return "", "", false
+	}
+	return prefix, secret, true
+}
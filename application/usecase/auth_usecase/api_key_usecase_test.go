@@ -0,0 +1,180 @@
+package authusecase_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	authusecase "Goshop/application/usecase/auth_usecase"
+	authentity "Goshop/domain/auth_entity"
+	userentity "Goshop/domain/entity/user_entity"
+	authrepository "Goshop/domain/repository/auth_repository"
+	"Goshop/interfaces/utils"
+	mockrepo "Goshop/mocks/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// Clé de test : préfixe "0123456789ab", secret "s3cr_et"
+const testAPIKey = "gsk_0123456789ab_s3cr_et"
+
+func newAPIKeyUsecase(ctrl *gomock.Controller) (*authusecase.APIKeyUsecase, *mockrepo.MockAPIKeyRepository, *mockrepo.MockRateCounter) {
+	keys := mockrepo.NewMockAPIKeyRepository(ctrl)
+	counter := mockrepo.NewMockRateCounter(ctrl)
+	return authusecase.NewAPIKeyUsecase(keys, counter, func() time.Time { return tokenNow }), keys, counter
+}
+
+func storedAPIKey() *authentity.APIKey {
+	return &authentity.APIKey{
+		ID:         "key-1",
+		Prefix:     "0123456789ab",
+		SecretHash: sha256Hex("s3cr_et"),
+		Scopes:     []string{userentity.PermProductsWrite},
+		RateLimit:  2,
+	}
+}
+
+func TestAPIKeyUsecase_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, keys, _ := newAPIKeyUsecase(ctrl)
+
+	var stored *authentity.APIKey
+	keys.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, k *authentity.APIKey) error {
+			stored = k
+			return nil
+		})
+
+	key, raw, err := uc.Create(context.Background(), "admin-1", authusecase.NewAPIKey{
+		Name:   " catalog sync ",
+		Scopes: []string{userentity.PermProductsWrite},
+	})
+
+	require.NoError(t, err)
+	assert.Same(t, stored, key)
+	assert.Regexp(t, regexp.MustCompile(`^gsk_[0-9a-f]{12}_[A-Za-z0-9_-]{43}$`), raw)
+	assert.Equal(t, "catalog sync", key.Name)
+	assert.Equal(t, raw[4:16], key.Prefix)
+	assert.Equal(t, sha256Hex(raw[17:]), key.SecretHash)
+	assert.Equal(t, authusecase.DefaultAPIKeyRateLimit, key.RateLimit)
+	assert.Equal(t, "admin-1", key.CreatedBy)
+	assert.NotEmpty(t, key.ID)
+}
+
+func TestAPIKeyUsecase_Create_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		in   authusecase.NewAPIKey
+		want error
+	}{
+		{"unknown scope", authusecase.NewAPIKey{Name: "k", Scopes: []string{"everything"}}, utils.ErrAPIKeyScopeInvalid},
+		{"negative rate limit", authusecase.NewAPIKey{Name: "k", RateLimit: -1}, utils.ErrValidationFailed},
+		{"already expired", authusecase.NewAPIKey{Name: "k", ExpiresAt: tokenNow.Add(-time.Second)}, utils.ErrValidationFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			uc, _, _ := newAPIKeyUsecase(ctrl)
+
+			_, _, err := uc.Create(context.Background(), "admin-1", tt.in)
+
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestAPIKeyUsecase_Revoke(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, keys, _ := newAPIKeyUsecase(ctrl)
+
+	keys.EXPECT().Revoke(gomock.Any(), "key-1").Return(nil)
+	keys.EXPECT().Revoke(gomock.Any(), "key-2").Return(authrepository.ErrAPIKeyNotFound)
+
+	assert.NoError(t, uc.Revoke(context.Background(), "admin-1", "key-1"))
+	assert.ErrorIs(t, uc.Revoke(context.Background(), "admin-1", "key-2"), utils.ErrAPIKeyNotFound)
+}
+
+func TestAPIKeyUsecase_Authenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, keys, counter := newAPIKeyUsecase(ctrl)
+
+	keys.EXPECT().FindByPrefix(gomock.Any(), "0123456789ab").Return(storedAPIKey(), nil)
+	counter.EXPECT().Incr(gomock.Any(), "apikey:key-1", time.Minute).Return(int64(1), nil)
+	keys.EXPECT().TouchLastUsed(gomock.Any(), "key-1", tokenNow).Return(nil)
+
+	key, err := uc.Authenticate(context.Background(), testAPIKey)
+
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", key.ID)
+}
+
+func TestAPIKeyUsecase_Authenticate_TouchFailureIgnored(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, keys, counter := newAPIKeyUsecase(ctrl)
+
+	keys.EXPECT().FindByPrefix(gomock.Any(), gomock.Any()).Return(storedAPIKey(), nil)
+	counter.EXPECT().Incr(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(1), nil)
+	keys.EXPECT().TouchLastUsed(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("db down"))
+
+	_, err := uc.Authenticate(context.Background(), testAPIKey)
+
+	assert.NoError(t, err)
+}
+
+func TestAPIKeyUsecase_Authenticate_Rejected(t *testing.T) {
+	revoked := storedAPIKey()
+	revoked.RevokedAt = tokenNow.Add(-time.Hour)
+	expired := storedAPIKey()
+	expired.ExpiresAt = tokenNow
+
+	tests := []struct {
+		name  string
+		raw   string
+		found *authentity.APIKey
+		err   error
+	}{
+		{"malformed", "not-a-key", nil, nil},
+		{"short prefix", "gsk_0123_secret", nil, nil},
+		{"unknown prefix", testAPIKey, nil, authrepository.ErrAPIKeyNotFound},
+		{"wrong secret", "gsk_0123456789ab_other", storedAPIKey(), nil},
+		{"revoked", testAPIKey, revoked, nil},
+		{"expired", testAPIKey, expired, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			uc, keys, _ := newAPIKeyUsecase(ctrl)
+
+			if strings.HasPrefix(tt.raw, "gsk_0123456789ab_") {
+				keys.EXPECT().FindByPrefix(gomock.Any(), "0123456789ab").Return(tt.found, tt.err)
+			}
+
+			_, err := uc.Authenticate(context.Background(), tt.raw)
+
+			assert.ErrorIs(t, err, utils.ErrAPIKeyInvalid)
+		})
+	}
+}
+
+func TestAPIKeyUsecase_Authenticate_RateLimited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	keys := mockrepo.NewMockAPIKeyRepository(ctrl)
+	counter := mockrepo.NewMockRateCounter(ctrl)
+	now := tokenNow.Add(45 * time.Second)
+	uc := authusecase.NewAPIKeyUsecase(keys, counter, func() time.Time { return now })
+
+	keys.EXPECT().FindByPrefix(gomock.Any(), gomock.Any()).Return(storedAPIKey(), nil)
+	counter.EXPECT().Incr(gomock.Any(), "apikey:key-1", time.Minute).Return(int64(3), nil)
+
+	_, err := uc.Authenticate(context.Background(), testAPIKey)
+
+	assert.ErrorIs(t, err, utils.ErrAPIKeyRateLimited)
+	var retryErr *utils.RetryAfterError
+	require.ErrorAs(t, err, &retryErr)
+	assert.Equal(t, 15*time.Second, retryErr.After)
+}
@@ -0,0 +1,141 @@
+// application/usecase/auth_usecase/change_password_usecase.go
+package authusecase
+
+import (
+	"context"
+	"errors"
+
+	authentity "Goshop/domain/auth_entity"
+	userentity "Goshop/domain/entity/user_entity"
+	authrepository "Goshop/domain/repository/auth_repository"
+	userrepository "Goshop/domain/repository/user_repository"
+	"Goshop/interfaces/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ChangePasswordUsecase : changement de mot de passe de l'utilisateur connecté
+type ChangePasswordUsecase struct {
+	users         userrepository.UserRepository
+	tokens        authrepository.UserTokenRepository
+	sessions      authrepository.RefreshSessionRepository
+	validateToken func(string) (jwt.MapClaims, error)
+}
+
+func NewChangePasswordUsecase(
+	users userrepository.UserRepository,
+	tokens authrepository.UserTokenRepository,
+	sessions authrepository.RefreshSessionRepository,
+	validateToken func(string) (jwt.MapClaims, error),
+) *ChangePasswordUsecase {
+	return &ChangePasswordUsecase{
+		users:         users,
+		tokens:        tokens,
+		sessions:      sessions,
+		validateToken: validateToken,
+	}
+}
+
+// Execute vérifie le mot de passe actuel, le remplace et révoque les autres
+// sessions. La session du refresh token présenté (facultatif) est conservée ;
+// sans refresh token valide, toutes les sessions sont révoquées. Retourne le
+// nombre de sessions révoquées.
+func (uc *ChangePasswordUsecase) Execute(ctx context.Context, userID, currentPassword, newPassword, refreshToken string) (int, error) {
+	logger := zerolog.Ctx(ctx)
+	maskedUserID := maskUserID(userID)
+
+	user, err := uc.users.FindUserByID(userID)
+	if errors.Is(err, userrepository.ErrUserNotFound) {
+		return 0, utils.ErrUnauthorized
+	}
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "change_password").Msg("Failed to load user")
+		return 0, utils.ErrInternalServer
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)) != nil {
+		logger.Warn().
+			Str("operation", "change_password").
+			Str("user_id", maskedUserID).
+			Msg("Wrong current password")
+		return 0, utils.ErrCurrentPasswordInvalid
+	}
+	if currentPassword == newPassword {
+		return 0, utils.ErrPasswordUnchanged
+	}
+
+	hashed, err := userentity.HashPassword(newPassword)
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "change_password").Msg("Failed to hash password")
+		return 0, utils.ErrInternalServer
+	}
+	if err := uc.users.UpdatePassword(userID, hashed); err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "change_password").
+			Str("user_id", maskedUserID).
+			Msg("Failed to update password")
+		return 0, utils.ErrInternalServer
+	}
+
+	// Un lien de réinitialisation envoyé avant le changement ne doit plus servir
+	if err := uc.tokens.RevokeForUser(ctx, userID, authentity.UserTokenPasswordReset); err != nil {
+		logger.Error().Err(err).Str("operation", "change_password").Msg("Failed to revoke password reset tokens")
+	}
+
+	var revoked int
+	if family := uc.currentFamily(ctx, userID, refreshToken); family != "" {
+		revoked, err = uc.sessions.RevokeOtherSessions(userID, family)
+	} else {
+		revoked, err = uc.sessions.RevokeAllForUser(userID)
+	}
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "change_password").
+			Str("user_id", maskedUserID).
+			Msg("Password changed but sessions could not be revoked")
+		return 0, utils.ErrInternalServer
+	}
+
+	utils.SecurityAudit(ctx, utils.AUDIT_PASSWORD_CHANGED).
+		Str("user_id", maskedUserID).
+		Int("revoked_sessions", revoked).
+		Msg("Password changed, other sessions revoked")
+
+	return revoked, nil
+}
+
+// currentFamily : famille de la session du refresh token, si ce token est
+// valide, actif et appartient à l'utilisateur ("" sinon)
+func (uc *ChangePasswordUsecase) currentFamily(ctx context.Context, userID, refreshToken string) string {
+	if refreshToken == "" {
+		return ""
+	}
+
+	claims, err := uc.validateToken(refreshToken)
+	if err != nil {
+		return ""
+	}
+	if t, _ := claims["type"].(string); t != "refresh" {
+		return ""
+	}
+	if sub, _ := claims["sub"].(string); sub != userID {
+		return ""
+	}
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		return ""
+	}
+
+	session, err := uc.sessions.FindByID(jti)
+	if err != nil || session.UserID != userID || session.Revoked {
+		zerolog.Ctx(ctx).Debug().
+			Str("operation", "change_password").
+			Msg("Refresh token does not match an active session, revoking all sessions")
+		return ""
+	}
+	return familyOf(session)
+}
@@ -0,0 +1,111 @@
+package authusecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	authusecase "Goshop/application/usecase/auth_usecase"
+	authentity "Goshop/domain/auth_entity"
+	userentity "Goshop/domain/entity/user_entity"
+	"Goshop/interfaces/utils"
+	mockrepo "Goshop/mocks/repository"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type changePasswordMocks struct {
+	users    *mockrepo.MockUserRepository
+	tokens   *mockrepo.MockUserTokenRepository
+	sessions *mockrepo.MockRefreshSessionRepository
+}
+
+func newChangePassword(t *testing.T, validate func(string) (jwt.MapClaims, error)) (*changePasswordMocks, *authusecase.ChangePasswordUsecase) {
+	ctrl := gomock.NewController(t)
+	m := &changePasswordMocks{
+		users:    mockrepo.NewMockUserRepository(ctrl),
+		tokens:   mockrepo.NewMockUserTokenRepository(ctrl),
+		sessions: mockrepo.NewMockRefreshSessionRepository(ctrl),
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("old-pass"), bcrypt.MinCost)
+	require.NoError(t, err)
+	m.users.EXPECT().FindUserByID("user-1").
+		Return(&userentity.UserEntity{ID: "user-1", Password: string(hash)}, nil)
+
+	return m, authusecase.NewChangePasswordUsecase(m.users, m.tokens, m.sessions, validate)
+}
+
+// expectPasswordUpdate : nouveau hash enregistré, liens de réinitialisation invalidés
+func (m *changePasswordMocks) expectPasswordUpdate(t *testing.T) {
+	m.users.EXPECT().UpdatePassword("user-1", gomock.Any()).
+		DoAndReturn(func(_ string, hash string) error {
+			assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte("new-pass")))
+			return nil
+		})
+	m.tokens.EXPECT().RevokeForUser(gomock.Any(), "user-1", authentity.UserTokenPasswordReset).Return(nil)
+}
+
+func TestChangePassword_KeepsCurrentSession(t *testing.T) {
+	m, uc := newChangePassword(t, refreshClaims("user-1", "jti-2"))
+	m.expectPasswordUpdate(t)
+
+	m.sessions.EXPECT().FindByID("jti-2").
+		Return(&authentity.RefreshSession{ID: "jti-2", UserID: "user-1", FamilyID: "jti-0"}, nil)
+	m.sessions.EXPECT().RevokeOtherSessions("user-1", "jti-0").Return(2, nil)
+
+	revoked, err := uc.Execute(context.Background(), "user-1", "old-pass", "new-pass", "refresh-token")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, revoked)
+}
+
+func TestChangePassword_WithoutRefreshTokenRevokesAll(t *testing.T) {
+	for name, token := range map[string]string{"sans refresh token": "", "refresh token invalide": "forged"} {
+		t.Run(name, func(t *testing.T) {
+			m, uc := newChangePassword(t, func(string) (jwt.MapClaims, error) {
+				return nil, errors.New("invalid signature")
+			})
+			m.expectPasswordUpdate(t)
+			m.sessions.EXPECT().RevokeAllForUser("user-1").Return(3, nil)
+
+			revoked, err := uc.Execute(context.Background(), "user-1", "old-pass", "new-pass", token)
+			assert.NoError(t, err)
+			assert.Equal(t, 3, revoked)
+		})
+	}
+}
+
+func TestChangePassword_OtherUserRefreshTokenRevokesAll(t *testing.T) {
+	m, uc := newChangePassword(t, refreshClaims("user-2", "jti-9"))
+	m.expectPasswordUpdate(t)
+	m.sessions.EXPECT().RevokeAllForUser("user-1").Return(1, nil)
+
+	_, err := uc.Execute(context.Background(), "user-1", "old-pass", "new-pass", "refresh-token")
+	assert.NoError(t, err)
+}
+
+func TestChangePassword_Rejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		next    string
+		wantErr error
+	}{
+		{"mot de passe actuel faux", "wrong", "new-pass", utils.ErrCurrentPasswordInvalid},
+		{"mot de passe inchangé", "old-pass", "old-pass", utils.ErrPasswordUnchanged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Ni mise à jour ni révocation
+			_, uc := newChangePassword(t, refreshClaims("user-1", "jti-2"))
+
+			_, err := uc.Execute(context.Background(), "user-1", tt.current, tt.next, "")
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// application/usecase/auth_usecase/email_verification_usecase.go
+package authusecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	authentity "Goshop/domain/auth_entity"
+	"Goshop/domain/mailer"
+	authrepository "Goshop/domain/repository/auth_repository"
+	userrepository "Goshop/domain/repository/user_repository"
+	"Goshop/interfaces/utils"
+
+	"github.com/rs/zerolog"
+)
+
+// EmailVerificationTokenTTL : durée de validité d'un lien de vérification
+const EmailVerificationTokenTTL = 48 * time.Hour
+
+// EmailVerificationUsecase : confirmation de l'adresse email après inscription
+type EmailVerificationUsecase struct {
+	users     userrepository.UserRepository
+	tokens    authrepository.UserTokenRepository
+	mailer    mailer.Mailer
+	publicURL string
+	now       func() time.Time
+}
+
+func NewEmailVerificationUsecase(
+	users userrepository.UserRepository,
+	tokens authrepository.UserTokenRepository,
+	mail mailer.Mailer,
+	publicURL string,
+	now func() time.Time,
+) *EmailVerificationUsecase {
+	return &EmailVerificationUsecase{
+		users:     users,
+		tokens:    tokens,
+		mailer:    mail,
+		publicURL: publicURL,
+		now:       now,
+	}
+}
+
+// SendVerification envoie le lien de vérification ; un lien déjà envoyé est invalidé
+func (uc *EmailVerificationUsecase) SendVerification(ctx context.Context, userID, email string) error {
+	token, err := issueUserToken(ctx, uc.tokens, userID, authentity.UserTokenEmailVerification, uc.now().Add(EmailVerificationTokenTTL))
+	if err != nil {
+		return fmt.Errorf("issue email verification token: %w", err)
+	}
+
+	err = uc.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Confirm your GoShop email address",
+		Body: fmt.Sprintf("Welcome to GoShop!\n\n"+
+			"Confirm your email address by following this link within %d hours:\n\n%s\n",
+			int(EmailVerificationTokenTTL.Hours()), tokenLink(uc.publicURL, "/verify-email", token)),
+	})
+	if err != nil {
+		return fmt.Errorf("send email verification: %w", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("operation", "email_verification").
+		Str("user_id", maskUserID(userID)).
+		Msg("Email verification sent")
+	return nil
+}
+
+// Verify consomme le jeton et marque l'email de son utilisateur comme vérifié
+func (uc *EmailVerificationUsecase) Verify(ctx context.Context, token string) error {
+	logger := zerolog.Ctx(ctx)
+
+	if token == "" {
+		return utils.ErrEmailVerificationTokenInvalid
+	}
+
+	consumed, err := uc.tokens.Consume(ctx, hashUserToken(token), authentity.UserTokenEmailVerification)
+	if errors.Is(err, authrepository.ErrUserTokenInvalid) {
+		logger.Warn().Str("operation", "email_verify").Msg("Invalid, expired or reused email verification token")
+		return utils.ErrEmailVerificationTokenInvalid
+	}
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "email_verify").Msg("Failed to consume email verification token")
+		return utils.ErrInternalServer
+	}
+
+	if err := uc.users.MarkEmailVerified(consumed.UserID); err != nil {
+		if errors.Is(err, userrepository.ErrUserNotFound) {
+			return utils.ErrEmailVerificationTokenInvalid
+		}
+		logger.Error().Err(err).Str("operation", "email_verify").Msg("Failed to mark email as verified")
+		return utils.ErrInternalServer
+	}
+
+	logger.Info().
+		Str("operation", "email_verify").
+		Str("user_id", maskUserID(consumed.UserID)).
+		Msg("Email verified")
+	return nil
+}
@@ -0,0 +1,197 @@
+// application/usecase/auth_usecase/login_guard.go
+package authusecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"Goshop/application/metrics"
+	authrepository "Goshop/domain/repository/auth_repository"
+	"Goshop/interfaces/utils"
+
+	"github.com/rs/zerolog"
+)
+
+// LoginGuardConfig : seuils de la protection force brute du login
+type LoginGuardConfig struct {
+	MaxAccountFailures int           // échecs avant verrouillage du compte
+	MaxIPFailures      int           // échecs (tous comptes confondus) avant blocage de l'IP
+	LockDuration       time.Duration // durée du verrouillage / blocage
+	FailureWindow      time.Duration // un compteur expire après cette durée sans échec
+	DelayAfter         int           // échecs tolérés avant le premier délai
+	BaseDelay          time.Duration // délai imposé au premier palier, doublé ensuite
+	MaxDelay           time.Duration // plafond du délai progressif
+}
+
+func DefaultLoginGuardConfig() LoginGuardConfig {
+	return LoginGuardConfig{
+		MaxAccountFailures: 10,
+		MaxIPFailures:      100,
+		LockDuration:       15 * time.Minute,
+		FailureWindow:      15 * time.Minute,
+		DelayAfter:         3,
+		BaseDelay:          time.Second,
+		MaxDelay:           30 * time.Second,
+	}
+}
+
+// LoginGuard : protection force brute du login, par compte et par IP.
+//
+//   - au-delà de DelayAfter échecs, le compte doit attendre un délai progressif
+//     (BaseDelay, doublé à chaque échec) entre deux tentatives ;
+//   - à MaxAccountFailures échecs, le compte est verrouillé LockDuration
+//     (ACCOUNT_LOCKED, même avec le bon mot de passe) ;
+//   - à MaxIPFailures échecs, l'IP est bloquée LockDuration (credential stuffing).
+//
+// Un email inconnu est suivi comme un compte existant : les réponses ne
+// permettent pas de deviner quels emails sont inscrits. Si le stockage des
+// compteurs est indisponible, le login reste ouvert (erreur loguée).
+type LoginGuard struct {
+	attempts authrepository.LoginAttemptRepository
+	cfg      LoginGuardConfig
+	now      func() time.Time
+}
+
+func NewLoginGuard(attempts authrepository.LoginAttemptRepository, cfg LoginGuardConfig, now func() time.Time) *LoginGuard {
+	return &LoginGuard{attempts: attempts, cfg: cfg, now: now}
+}
+
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Check refuse la tentative (avant vérification du mot de passe) si l'IP est
+// bloquée, le compte verrouillé ou le délai progressif pas encore écoulé
+func (g *LoginGuard) Check(ctx context.Context, email, ip string) error {
+	logger := zerolog.Ctx(ctx)
+	now := g.now()
+
+	if ip != "" {
+		state, err := g.attempts.Get(ctx, ipKey(ip))
+		if err != nil {
+			logger.Error().Err(err).Str("operation", "login_guard").Msg("Failed to load IP login attempts")
+		} else if state.LockedAt(now) {
+			metrics.AuthLoginBlockedTotal.WithLabelValues("ip_blocked").Inc()
+			return utils.WithRetryAfter(utils.ErrLoginThrottled, state.LockedUntil.Sub(now))
+		}
+	}
+
+	state, err := g.attempts.Get(ctx, accountKey(email))
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "login_guard").Msg("Failed to load account login attempts")
+		return nil
+	}
+
+	if state.LockedAt(now) {
+		metrics.AuthLoginBlockedTotal.WithLabelValues("account_locked").Inc()
+		return utils.WithRetryAfter(utils.ErrAccountLocked, state.LockedUntil.Sub(now))
+	}
+
+	if delay := g.delay(state.Failures); delay > 0 {
+		if wait := state.LastFailure.Add(delay).Sub(now); wait > 0 {
+			metrics.AuthLoginBlockedTotal.WithLabelValues("throttled").Inc()
+			logger.Warn().
+				Str("operation", "login_guard").
+				Str("email", maskEmail(email)).
+				Int("failures", state.Failures).
+				Dur("retry_after", wait).
+				Msg("Login attempt before progressive delay elapsed")
+			return utils.WithRetryAfter(utils.ErrLoginThrottled, wait)
+		}
+	}
+
+	return nil
+}
+
+// delay : délai minimal entre deux tentatives après `failures` échecs
+func (g *LoginGuard) delay(failures int) time.Duration {
+	if g.cfg.BaseDelay <= 0 || failures < g.cfg.DelayAfter {
+		return 0
+	}
+	step := failures - g.cfg.DelayAfter
+	if step > 30 {
+		return g.cfg.MaxDelay
+	}
+	d := g.cfg.BaseDelay << step
+	if d > g.cfg.MaxDelay {
+		return g.cfg.MaxDelay
+	}
+	return d
+}
+
+// RegisterFailure compte un échec pour le compte et l'IP. Retourne
+// ErrAccountLocked si cet échec déclenche le verrouillage du compte.
+func (g *LoginGuard) RegisterFailure(ctx context.Context, email, ip string) error {
+	logger := zerolog.Ctx(ctx)
+	now := g.now()
+
+	if ip != "" {
+		state, err := g.attempts.RegisterFailure(ctx, ipKey(ip), g.cfg.FailureWindow)
+		if err != nil {
+			logger.Error().Err(err).Str("operation", "login_guard").Msg("Failed to register IP login failure")
+		} else if state.Failures >= g.cfg.MaxIPFailures && !state.LockedAt(now) {
+			if err := g.attempts.Lock(ctx, ipKey(ip), now.Add(g.cfg.LockDuration)); err != nil {
+				logger.Error().Err(err).Str("operation", "login_guard").Msg("Failed to block IP")
+			} else {
+				utils.SecurityAudit(ctx, utils.AUDIT_LOGIN_IP_BLOCKED).
+					Str("ip", ip).
+					Int("failures", state.Failures).
+					Dur("lock_duration", g.cfg.LockDuration).
+					Msg("IP blocked after too many failed logins")
+			}
+		}
+	}
+
+	state, err := g.attempts.RegisterFailure(ctx, accountKey(email), g.cfg.FailureWindow)
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "login_guard").Msg("Failed to register account login failure")
+		return nil
+	}
+	if state.Failures < g.cfg.MaxAccountFailures || state.LockedAt(now) {
+		return nil
+	}
+
+	if err := g.attempts.Lock(ctx, accountKey(email), now.Add(g.cfg.LockDuration)); err != nil {
+		logger.Error().Err(err).Str("operation", "login_guard").Msg("Failed to lock account")
+		return nil
+	}
+
+	metrics.AuthAccountLockoutsTotal.Inc()
+	utils.SecurityAudit(ctx, utils.AUDIT_ACCOUNT_LOCKED).
+		Str("email", maskEmail(email)).
+		Str("ip", ip).
+		Int("failures", state.Failures).
+		Dur("lock_duration", g.cfg.LockDuration).
+		Msg("Account locked after too many failed logins")
+
+	return utils.WithRetryAfter(utils.ErrAccountLocked, g.cfg.LockDuration)
+}
+
+// RegisterSuccess remet à zéro les échecs du compte. Le compteur de l'IP est
+// conservé : un attaquant ne doit pas le vider en se connectant à son propre compte.
+func (g *LoginGuard) RegisterSuccess(ctx context.Context, email string) {
+	if err := g.attempts.Reset(ctx, accountKey(email)); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Str("operation", "login_guard").Msg("Failed to reset login attempts")
+	}
+}
+
+// Unlock lève le verrouillage du compte et efface ses échecs (admin)
+func (g *LoginGuard) Unlock(ctx context.Context, email string) error {
+	return g.attempts.Reset(ctx, accountKey(email))
+}
+
+func maskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok {
+		return "invalid_email"
+	}
+	if len(local) > 3 {
+		local = local[:3]
+	}
+	return local + "***@" + domain
+}
@@ -0,0 +1,159 @@
+package authusecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	authusecase "Goshop/application/usecase/auth_usecase"
+	authentity "Goshop/domain/auth_entity"
+	"Goshop/interfaces/utils"
+	mockrepo "Goshop/mocks/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var guardNow = time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+func newGuard(repo *mockrepo.MockLoginAttemptRepository) *authusecase.LoginGuard {
+	return authusecase.NewLoginGuard(repo, authusecase.DefaultLoginGuardConfig(), func() time.Time { return guardNow })
+}
+
+// retryAfter extrait le délai d'une erreur de blocage
+func retryAfter(t *testing.T, err error) time.Duration {
+	t.Helper()
+	var retryErr *utils.RetryAfterError
+	require.ErrorAs(t, err, &retryErr)
+	return retryErr.After
+}
+
+func TestLoginGuard_Check(t *testing.T) {
+	tests := []struct {
+		name      string
+		ip        authentity.LoginAttempts
+		account   authentity.LoginAttempts
+		wantErr   error
+		wantRetry time.Duration
+	}{
+		{"aucun échec", authentity.LoginAttempts{}, authentity.LoginAttempts{}, nil, 0},
+		{"échecs sous le seuil de délai",
+			authentity.LoginAttempts{},
+			authentity.LoginAttempts{Failures: 2, LastFailure: guardNow},
+			nil, 0},
+		{"délai progressif en cours",
+			authentity.LoginAttempts{},
+			authentity.LoginAttempts{Failures: 5, LastFailure: guardNow.Add(-time.Second)},
+			utils.ErrLoginThrottled, 3 * time.Second},
+		{"délai progressif écoulé",
+			authentity.LoginAttempts{},
+			authentity.LoginAttempts{Failures: 5, LastFailure: guardNow.Add(-5 * time.Second)},
+			nil, 0},
+		{"délai plafonné",
+			authentity.LoginAttempts{},
+			authentity.LoginAttempts{Failures: 9, LastFailure: guardNow},
+			utils.ErrLoginThrottled, 30 * time.Second},
+		{"compte verrouillé",
+			authentity.LoginAttempts{},
+			authentity.LoginAttempts{Failures: 10, LastFailure: guardNow, LockedUntil: guardNow.Add(10 * time.Minute)},
+			utils.ErrAccountLocked, 10 * time.Minute},
+		{"verrouillage expiré",
+			authentity.LoginAttempts{},
+			authentity.LoginAttempts{Failures: 1, LastFailure: guardNow.Add(-time.Hour), LockedUntil: guardNow.Add(-time.Minute)},
+			nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mockrepo.NewMockLoginAttemptRepository(ctrl)
+			repo.EXPECT().Get(gomock.Any(), "ip:203.0.113.7").Return(tt.ip, nil)
+			repo.EXPECT().Get(gomock.Any(), "account:alice@example.com").Return(tt.account, nil)
+
+			err := newGuard(repo).Check(context.Background(), " Alice@Example.com", "203.0.113.7")
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantRetry, retryAfter(t, err))
+		})
+	}
+}
+
+func TestLoginGuard_Check_BlockedIP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockLoginAttemptRepository(ctrl)
+
+	// IP bloquée : le compte n'est même pas consulté
+	repo.EXPECT().Get(gomock.Any(), "ip:203.0.113.7").
+		Return(authentity.LoginAttempts{Failures: 100, LockedUntil: guardNow.Add(time.Minute)}, nil)
+
+	err := newGuard(repo).Check(context.Background(), "alice@example.com", "203.0.113.7")
+	assert.ErrorIs(t, err, utils.ErrLoginThrottled)
+	assert.Equal(t, time.Minute, retryAfter(t, err))
+}
+
+func TestLoginGuard_Check_StoreUnavailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockLoginAttemptRepository(ctrl)
+
+	repo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(authentity.LoginAttempts{}, errors.New("connection refused")).Times(2)
+
+	assert.NoError(t, newGuard(repo).Check(context.Background(), "alice@example.com", "203.0.113.7"))
+}
+
+func TestLoginGuard_RegisterFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockLoginAttemptRepository(ctrl)
+	window := authusecase.DefaultLoginGuardConfig().FailureWindow
+
+	repo.EXPECT().RegisterFailure(gomock.Any(), "ip:203.0.113.7", window).Return(authentity.LoginAttempts{Failures: 4}, nil)
+	repo.EXPECT().RegisterFailure(gomock.Any(), "account:alice@example.com", window).Return(authentity.LoginAttempts{Failures: 3}, nil)
+
+	assert.NoError(t, newGuard(repo).RegisterFailure(context.Background(), "alice@example.com", "203.0.113.7"))
+}
+
+func TestLoginGuard_RegisterFailure_LocksAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockLoginAttemptRepository(ctrl)
+	cfg := authusecase.DefaultLoginGuardConfig()
+
+	repo.EXPECT().RegisterFailure(gomock.Any(), "account:alice@example.com", cfg.FailureWindow).
+		Return(authentity.LoginAttempts{Failures: cfg.MaxAccountFailures}, nil)
+	repo.EXPECT().Lock(gomock.Any(), "account:alice@example.com", guardNow.Add(cfg.LockDuration)).Return(nil)
+
+	// Sans IP connue, seul le compte est suivi
+	err := newGuard(repo).RegisterFailure(context.Background(), "alice@example.com", "")
+	assert.ErrorIs(t, err, utils.ErrAccountLocked)
+	assert.Equal(t, cfg.LockDuration, retryAfter(t, err))
+}
+
+func TestLoginGuard_RegisterFailure_BlocksIP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockLoginAttemptRepository(ctrl)
+	cfg := authusecase.DefaultLoginGuardConfig()
+
+	repo.EXPECT().RegisterFailure(gomock.Any(), "ip:203.0.113.7", cfg.FailureWindow).
+		Return(authentity.LoginAttempts{Failures: cfg.MaxIPFailures}, nil)
+	repo.EXPECT().Lock(gomock.Any(), "ip:203.0.113.7", guardNow.Add(cfg.LockDuration)).Return(nil)
+	repo.EXPECT().RegisterFailure(gomock.Any(), "account:bob@example.com", cfg.FailureWindow).
+		Return(authentity.LoginAttempts{Failures: 1}, nil)
+
+	// Le blocage de l'IP ne verrouille pas le compte visé
+	assert.NoError(t, newGuard(repo).RegisterFailure(context.Background(), "bob@example.com", "203.0.113.7"))
+}
+
+func TestLoginGuard_RegisterSuccessAndUnlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockLoginAttemptRepository(ctrl)
+
+	// Seul le compteur du compte est remis à zéro, jamais celui de l'IP
+	repo.EXPECT().Reset(gomock.Any(), "account:alice@example.com").Return(nil).Times(2)
+
+	guard := newGuard(repo)
+	guard.RegisterSuccess(context.Background(), "Alice@example.com")
+	assert.NoError(t, guard.Unlock(context.Background(), "alice@example.com"))
+}
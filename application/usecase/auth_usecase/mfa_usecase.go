@@ -0,0 +1,222 @@
+// application/usecase/auth_usecase/mfa_usecase.go
+package authusecase
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	authrepository "Goshop/domain/repository/auth_repository"
+	userrepository "Goshop/domain/repository/user_repository"
+	"Goshop/interfaces/utils"
+
+	"github.com/rs/zerolog"
+)
+
+// MFARecoveryCodeCount : codes de récupération remis à l'activation du 2FA
+const MFARecoveryCodeCount = 10
+
+// MFAEnrollment : secret à saisir (ou URI à scanner) dans l'application d'authentification
+type MFAEnrollment struct {
+	Secret string
+	URI    string
+}
+
+// MFAUsecase : authentification à deux facteurs TOTP (enrôlement,
+// confirmation, vérification du second facteur au login)
+type MFAUsecase struct {
+	users userrepository.UserRepository
+	mfa   authrepository.MFARepository
+	now   func() time.Time
+}
+
+func NewMFAUsecase(users userrepository.UserRepository, mfa authrepository.MFARepository, now func() time.Time) *MFAUsecase {
+	return &MFAUsecase{
+		users: users,
+		mfa:   mfa,
+		now:   now,
+	}
+}
+
+// Enroll génère un nouveau secret en attente de confirmation. Un enrôlement
+// non confirmé est remplacé ; un 2FA actif ne l'est pas.
+func (uc *MFAUsecase) Enroll(ctx context.Context, userID string) (*MFAEnrollment, error) {
+	logger := zerolog.Ctx(ctx)
+
+	user, err := uc.users.FindUserByID(userID)
+	if errors.Is(err, userrepository.ErrUserNotFound) {
+		return nil, utils.ErrUnauthorized
+	}
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "mfa_enroll").Msg("Failed to load user")
+		return nil, utils.ErrInternalServer
+	}
+
+	secret, err := newTOTPSecret()
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "mfa_enroll").Msg("Failed to generate TOTP secret")
+		return nil, utils.ErrInternalServer
+	}
+
+	err = uc.mfa.SavePending(ctx, userID, secret)
+	if errors.Is(err, authrepository.ErrMFAAlreadyEnabled) {
+		return nil, utils.ErrMFAAlreadyEnabled
+	}
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "mfa_enroll").Msg("Failed to save pending MFA")
+		return nil, utils.ErrInternalServer
+	}
+
+	logger.Info().
+		Str("operation", "mfa_enroll").
+		Str("user_id", maskUserID(userID)).
+		Msg("MFA enrolment started")
+
+	return &MFAEnrollment{Secret: secret, URI: totpURI(user.Email, secret)}, nil
+}
+
+// Confirm active le 2FA avec un premier code et retourne les codes de
+// récupération en clair : ils ne sont affichés qu'une fois.
+func (uc *MFAUsecase) Confirm(ctx context.Context, userID, code string) ([]string, error) {
+	logger := zerolog.Ctx(ctx)
+	maskedUserID := maskUserID(userID)
+
+	m, err := uc.mfa.Find(ctx, userID)
+	if errors.Is(err, authrepository.ErrMFANotFound) {
+		return nil, utils.ErrMFANotEnrolled
+	}
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "mfa_confirm").Msg("Failed to load MFA")
+		return nil, utils.ErrInternalServer
+	}
+	if m.Enabled() {
+		return nil, utils.ErrMFAAlreadyEnabled
+	}
+
+	step, ok := verifyTOTP(m.Secret, normalizeMFACode(code), uc.now())
+	if !ok {
+		logger.Warn().
+			Str("operation", "mfa_confirm").
+			Str("user_id", maskedUserID).
+			Msg("MFA confirmation code rejected")
+		return nil, utils.ErrMFAConfirmationFailed
+	}
+
+	codes, hashes, err := newRecoveryCodes(MFARecoveryCodeCount)
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "mfa_confirm").Msg("Failed to generate recovery codes")
+		return nil, utils.ErrInternalServer
+	}
+
+	err = uc.mfa.Enable(ctx, userID, step, hashes)
+	if errors.Is(err, authrepository.ErrMFANotFound) {
+		// Confirmé entre-temps par une requête concurrente
+		return nil, utils.ErrMFAAlreadyEnabled
+	}
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "mfa_confirm").Msg("Failed to enable MFA")
+		return nil, utils.ErrInternalServer
+	}
+
+	utils.SecurityAudit(ctx, utils.AUDIT_MFA_ENABLED).
+		Str("user_id", maskedUserID).
+		Msg("Two-factor authentication enabled")
+
+	return codes, nil
+}
+
+// Enabled : le 2FA de l'utilisateur est actif (enrôlement confirmé)
+func (uc *MFAUsecase) Enabled(ctx context.Context, userID string) (bool, error) {
+	m, err := uc.mfa.Find(ctx, userID)
+	if errors.Is(err, authrepository.ErrMFANotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return m.Enabled(), nil
+}
+
+// Verify contrôle le second facteur d'un login : code TOTP ou, à défaut, code
+// de récupération. Chaque code ne sert qu'une fois ; utils.ErrMFACodeInvalid
+// si le code est refusé.
+func (uc *MFAUsecase) Verify(ctx context.Context, userID, code string) error {
+	logger := zerolog.Ctx(ctx)
+	maskedUserID := maskUserID(userID)
+	code = normalizeMFACode(code)
+
+	m, err := uc.mfa.Find(ctx, userID)
+	if errors.Is(err, authrepository.ErrMFANotFound) {
+		return utils.ErrMFACodeInvalid
+	}
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "mfa_verify").Msg("Failed to load MFA")
+		return utils.ErrInternalServer
+	}
+	if !m.Enabled() {
+		return utils.ErrMFACodeInvalid
+	}
+
+	if step, ok := verifyTOTP(m.Secret, code, uc.now()); ok {
+		err := uc.mfa.UseStep(ctx, userID, step)
+		if errors.Is(err, authrepository.ErrMFAStepReused) {
+			logger.Warn().
+				Str("operation", "mfa_verify").
+				Str("user_id", maskedUserID).
+				Msg("TOTP code replayed")
+			return utils.ErrMFACodeInvalid
+		}
+		if err != nil {
+			logger.Error().Err(err).Str("operation", "mfa_verify").Msg("Failed to record TOTP step")
+			return utils.ErrInternalServer
+		}
+		return nil
+	}
+
+	// Un code à 6 chiffres refusé n'est pas un code de récupération
+	if len(code) == totpDigits {
+		return utils.ErrMFACodeInvalid
+	}
+
+	err = uc.mfa.ConsumeRecoveryCode(ctx, userID, hashUserToken(code))
+	if errors.Is(err, authrepository.ErrRecoveryCodeInvalid) {
+		return utils.ErrMFACodeInvalid
+	}
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "mfa_verify").Msg("Failed to consume recovery code")
+		return utils.ErrInternalServer
+	}
+
+	utils.SecurityAudit(ctx, utils.AUDIT_MFA_RECOVERY_USED).
+		Str("user_id", maskedUserID).
+		Msg("Login completed with a recovery code")
+	return nil
+}
+
+// normalizeMFACode : espaces et tirets ignorés, casse indifférente
+// ("ABCD-EFGH" et "abcdefgh" désignent le même code de récupération)
+func normalizeMFACode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(code))
+}
+
+// recoveryCodeBytes : 80 bits aléatoires par code de récupération. Le code
+// est stocké sous forme de hash SHA-256 sans sel, comme les autres jetons :
+// seule sa longueur le protège d'une recherche exhaustive si la table fuite.
+const recoveryCodeBytes = 10
+
+// newRecoveryCodes génère n codes "xxxx-xxxx-xxxx-xxxx" et les hash de leur
+// forme normalisée, seuls stockés
+func newRecoveryCodes(n int) (codes, hashes []string, err error) {
+	for i := 0; i < n; i++ {
+		b := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(totpEncoding.EncodeToString(b))
+		codes = append(codes, raw[:4]+"-"+raw[4:8]+"-"+raw[8:12]+"-"+raw[12:])
+		hashes = append(hashes, hashUserToken(raw))
+	}
+	return codes, hashes, nil
+}
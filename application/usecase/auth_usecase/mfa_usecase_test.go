@@ -0,0 +1,217 @@
+package authusecase_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	authusecase "Goshop/application/usecase/auth_usecase"
+	authentity "Goshop/domain/auth_entity"
+	userentity "Goshop/domain/entity/user_entity"
+	authrepository "Goshop/domain/repository/auth_repository"
+	"Goshop/interfaces/utils"
+	mockrepo "Goshop/mocks/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// Secret de test (base32 de "12345678901234567890")
+const mfaSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// totpCode calcule le code attendu à l'instant t, indépendamment de l'implémentation
+func totpCode(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	require.NoError(t, err)
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(at.Unix()/30))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	return fmt.Sprintf("%06d", (binary.BigEndian.Uint32(sum[offset:])&0x7fffffff)%1000000)
+}
+
+func newMFAUsecase(ctrl *gomock.Controller) (*authusecase.MFAUsecase, *mockrepo.MockUserRepository, *mockrepo.MockMFARepository) {
+	users := mockrepo.NewMockUserRepository(ctrl)
+	mfa := mockrepo.NewMockMFARepository(ctrl)
+	return authusecase.NewMFAUsecase(users, mfa, func() time.Time { return tokenNow }), users, mfa
+}
+
+func pendingMFA() *authentity.UserMFA {
+	return &authentity.UserMFA{UserID: "user-1", Secret: mfaSecret}
+}
+
+func enabledMFA() *authentity.UserMFA {
+	return &authentity.UserMFA{UserID: "user-1", Secret: mfaSecret, EnabledAt: tokenNow.Add(-time.Hour)}
+}
+
+func TestMFAUsecase_Enroll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, users, mfa := newMFAUsecase(ctrl)
+
+	users.EXPECT().FindUserByID("user-1").Return(&userentity.UserEntity{ID: "user-1", Email: "alice@example.com"}, nil)
+	var saved string
+	mfa.EXPECT().SavePending(gomock.Any(), "user-1", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, secret string) error {
+			saved = secret
+			return nil
+		})
+
+	enrollment, err := uc.Enroll(context.Background(), "user-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, saved, enrollment.Secret)
+	assert.Len(t, enrollment.Secret, 32)
+	assert.True(t, strings.HasPrefix(enrollment.URI, "otpauth://totp/GoShop:alice@example.com?"))
+	assert.Contains(t, enrollment.URI, "secret="+saved)
+}
+
+func TestMFAUsecase_Enroll_AlreadyEnabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, users, mfa := newMFAUsecase(ctrl)
+
+	users.EXPECT().FindUserByID("user-1").Return(&userentity.UserEntity{ID: "user-1", Email: "alice@example.com"}, nil)
+	mfa.EXPECT().SavePending(gomock.Any(), "user-1", gomock.Any()).Return(authrepository.ErrMFAAlreadyEnabled)
+
+	_, err := uc.Enroll(context.Background(), "user-1")
+
+	assert.ErrorIs(t, err, utils.ErrMFAAlreadyEnabled)
+}
+
+func TestMFAUsecase_Confirm(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, _, mfa := newMFAUsecase(ctrl)
+
+	mfa.EXPECT().Find(gomock.Any(), "user-1").Return(pendingMFA(), nil)
+	var stored []string
+	mfa.EXPECT().Enable(gomock.Any(), "user-1", tokenNow.Unix()/30, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ int64, hashes []string) error {
+			stored = hashes
+			return nil
+		})
+
+	codes, err := uc.Confirm(context.Background(), "user-1", totpCode(t, mfaSecret, tokenNow))
+
+	require.NoError(t, err)
+	require.Len(t, codes, authusecase.MFARecoveryCodeCount)
+	for i, code := range codes {
+		assert.Regexp(t, regexp.MustCompile(`^[a-z2-7]{4}(-[a-z2-7]{4}){3}$`), code)
+		// Seul le hash de la forme normalisée est stocké
+		assert.Equal(t, sha256Hex(strings.ReplaceAll(code, "-", "")), stored[i])
+	}
+}
+
+func TestMFAUsecase_Confirm_Rejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		mfa     *authentity.UserMFA
+		findErr error
+		code    string
+		wantErr error
+	}{
+		{"pas d'enrôlement", nil, authrepository.ErrMFANotFound, "123456", utils.ErrMFANotEnrolled},
+		{"déjà actif", enabledMFA(), nil, "123456", utils.ErrMFAAlreadyEnabled},
+		{"mauvais code", pendingMFA(), nil, "000000", utils.ErrMFAConfirmationFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			uc, _, mfa := newMFAUsecase(ctrl)
+
+			// Enable n'est jamais appelé
+			mfa.EXPECT().Find(gomock.Any(), "user-1").Return(tt.mfa, tt.findErr)
+
+			_, err := uc.Confirm(context.Background(), "user-1", tt.code)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestMFAUsecase_Enabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, _, mfa := newMFAUsecase(ctrl)
+
+	mfa.EXPECT().Find(gomock.Any(), "none").Return(nil, authrepository.ErrMFANotFound)
+	mfa.EXPECT().Find(gomock.Any(), "pending").Return(pendingMFA(), nil)
+	mfa.EXPECT().Find(gomock.Any(), "enabled").Return(enabledMFA(), nil)
+
+	for userID, want := range map[string]bool{"none": false, "pending": false, "enabled": true} {
+		enabled, err := uc.Enabled(context.Background(), userID)
+		assert.NoError(t, err)
+		assert.Equal(t, want, enabled, userID)
+	}
+}
+
+func TestMFAUsecase_Verify_TOTP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, _, mfa := newMFAUsecase(ctrl)
+
+	// Code de la période précédente : toléré (décalage d'horloge)
+	previous := tokenNow.Add(-30 * time.Second)
+	mfa.EXPECT().Find(gomock.Any(), "user-1").Return(enabledMFA(), nil)
+	mfa.EXPECT().UseStep(gomock.Any(), "user-1", previous.Unix()/30).Return(nil)
+
+	err := uc.Verify(context.Background(), "user-1", totpCode(t, mfaSecret, previous))
+
+	assert.NoError(t, err)
+}
+
+func TestMFAUsecase_Verify_TOTPReplay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, _, mfa := newMFAUsecase(ctrl)
+
+	mfa.EXPECT().Find(gomock.Any(), "user-1").Return(enabledMFA(), nil)
+	mfa.EXPECT().UseStep(gomock.Any(), "user-1", tokenNow.Unix()/30).Return(authrepository.ErrMFAStepReused)
+
+	err := uc.Verify(context.Background(), "user-1", totpCode(t, mfaSecret, tokenNow))
+
+	assert.ErrorIs(t, err, utils.ErrMFACodeInvalid)
+}
+
+func TestMFAUsecase_Verify_WrongTOTP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, _, mfa := newMFAUsecase(ctrl)
+
+	// Ni UseStep ni code de récupération
+	mfa.EXPECT().Find(gomock.Any(), "user-1").Return(enabledMFA(), nil)
+
+	err := uc.Verify(context.Background(), "user-1", "000000")
+
+	assert.ErrorIs(t, err, utils.ErrMFACodeInvalid)
+}
+
+func TestMFAUsecase_Verify_RecoveryCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, _, mfa := newMFAUsecase(ctrl)
+
+	mfa.EXPECT().Find(gomock.Any(), "user-1").Return(enabledMFA(), nil).Times(2)
+	mfa.EXPECT().ConsumeRecoveryCode(gomock.Any(), "user-1", sha256Hex("abcd2345efgh67ij")).Return(nil)
+	mfa.EXPECT().ConsumeRecoveryCode(gomock.Any(), "user-1", sha256Hex("abcd2345efgh67ij")).Return(authrepository.ErrRecoveryCodeInvalid)
+
+	// Saisie tolérante : majuscules et espaces
+	assert.NoError(t, uc.Verify(context.Background(), "user-1", " ABCD-2345-EFGH-67IJ "))
+	assert.ErrorIs(t, uc.Verify(context.Background(), "user-1", "abcd-2345-efgh-67ij"), utils.ErrMFACodeInvalid)
+}
+
+func TestMFAUsecase_Verify_NotEnabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uc, _, mfa := newMFAUsecase(ctrl)
+
+	mfa.EXPECT().Find(gomock.Any(), "user-1").Return(pendingMFA(), nil)
+
+	err := uc.Verify(context.Background(), "user-1", totpCode(t, mfaSecret, tokenNow))
+
+	assert.ErrorIs(t, err, utils.ErrMFACodeInvalid)
+}
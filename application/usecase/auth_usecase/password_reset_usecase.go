@@ -0,0 +1,177 @@
+// application/usecase/auth_usecase/password_reset_usecase.go
+package authusecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	authentity "Goshop/domain/auth_entity"
+	userentity "Goshop/domain/entity/user_entity"
+	"Goshop/domain/mailer"
+	authrepository "Goshop/domain/repository/auth_repository"
+	userrepository "Goshop/domain/repository/user_repository"
+	"Goshop/interfaces/utils"
+
+	"github.com/rs/zerolog"
+)
+
+// PasswordResetTokenTTL : durée de validité d'un lien de réinitialisation
+const PasswordResetTokenTTL = time.Hour
+
+// PasswordResetUsecase : mot de passe oublié. Le lien envoyé par email porte
+// un jeton à usage unique ; la réinitialisation révoque toutes les sessions.
+type PasswordResetUsecase struct {
+	users     userrepository.UserRepository
+	tokens    authrepository.UserTokenRepository
+	sessions  authrepository.RefreshSessionRepository
+	mailer    mailer.Mailer
+	publicURL string
+	now       func() time.Time
+	// pending : envois de lien en cours, voir Wait
+	pending sync.WaitGroup
+}
+
+func NewPasswordResetUsecase(
+	users userrepository.UserRepository,
+	tokens authrepository.UserTokenRepository,
+	sessions authrepository.RefreshSessionRepository,
+	mail mailer.Mailer,
+	publicURL string,
+	now func() time.Time,
+) *PasswordResetUsecase {
+	return &PasswordResetUsecase{
+		users:     users,
+		tokens:    tokens,
+		sessions:  sessions,
+		mailer:    mail,
+		publicURL: publicURL,
+		now:       now,
+	}
+}
+
+// RequestReset envoie le lien de réinitialisation. Un email inconnu n'est pas
+// une erreur ; pour un email inscrit, le jeton et l'email sont produits en
+// arrière-plan et leurs échecs seulement logués : ni la réponse ni son temps
+// ne révèlent quels emails sont inscrits.
+func (uc *PasswordResetUsecase) RequestReset(ctx context.Context, email string) error {
+	logger := zerolog.Ctx(ctx)
+	email = userentity.NormalizeEmail(email)
+
+	user, err := uc.users.FindUserByEmail(email)
+	if errors.Is(err, userrepository.ErrUserNotFound) {
+		logger.Info().
+			Str("operation", "password_forgot").
+			Str("email", maskEmail(email)).
+			Msg("Password reset requested for unknown email")
+		return nil
+	}
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "password_forgot").Msg("Failed to load user")
+		return utils.ErrInternalServer
+	}
+
+	uc.pending.Add(1)
+	go func() {
+		defer uc.pending.Done()
+		uc.sendResetLink(context.WithoutCancel(ctx), user)
+	}()
+	return nil
+}
+
+// Wait attend la fin des envois de lien en cours
+func (uc *PasswordResetUsecase) Wait() {
+	uc.pending.Wait()
+}
+
+// sendResetLink émet le jeton et envoie le lien ; les erreurs sont loguées
+func (uc *PasswordResetUsecase) sendResetLink(ctx context.Context, user *userentity.UserEntity) {
+	logger := zerolog.Ctx(ctx)
+
+	token, err := issueUserToken(ctx, uc.tokens, user.ID, authentity.UserTokenPasswordReset, uc.now().Add(PasswordResetTokenTTL))
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "password_forgot").Msg("Failed to issue password reset token")
+		return
+	}
+
+	err = uc.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your GoShop password",
+		Body: fmt.Sprintf("Hello,\n\n"+
+			"Someone asked to reset the password of your GoShop account.\n"+
+			"Follow this link within %d minutes to choose a new password:\n\n%s\n\n"+
+			"If you did not ask for it, ignore this email: your password stays unchanged.\n",
+			int(PasswordResetTokenTTL.Minutes()), tokenLink(uc.publicURL, "/reset-password", token)),
+	})
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "password_forgot").
+			Str("user_id", maskUserID(user.ID)).
+			Msg("Failed to send password reset email")
+		return
+	}
+
+	logger.Info().
+		Str("operation", "password_forgot").
+		Str("user_id", maskUserID(user.ID)).
+		Msg("Password reset email sent")
+}
+
+// ResetPassword consomme le jeton, remplace le mot de passe et révoque toutes
+// les sessions de refresh de l'utilisateur
+func (uc *PasswordResetUsecase) ResetPassword(ctx context.Context, token, newPassword string) error {
+	logger := zerolog.Ctx(ctx)
+
+	if token == "" {
+		return utils.ErrResetTokenInvalid
+	}
+
+	consumed, err := uc.tokens.Consume(ctx, hashUserToken(token), authentity.UserTokenPasswordReset)
+	if errors.Is(err, authrepository.ErrUserTokenInvalid) {
+		logger.Warn().Str("operation", "password_reset").Msg("Invalid, expired or reused password reset token")
+		return utils.ErrResetTokenInvalid
+	}
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "password_reset").Msg("Failed to consume password reset token")
+		return utils.ErrInternalServer
+	}
+	maskedUserID := maskUserID(consumed.UserID)
+
+	hashed, err := userentity.HashPassword(newPassword)
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "password_reset").Msg("Failed to hash password")
+		return utils.ErrInternalServer
+	}
+
+	if err := uc.users.UpdatePassword(consumed.UserID, hashed); err != nil {
+		if errors.Is(err, userrepository.ErrUserNotFound) {
+			return utils.ErrResetTokenInvalid
+		}
+		logger.Error().
+			Err(err).
+			Str("operation", "password_reset").
+			Str("user_id", maskedUserID).
+			Msg("Failed to update password")
+		return utils.ErrInternalServer
+	}
+
+	revoked, err := uc.sessions.RevokeAllForUser(consumed.UserID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "password_reset").
+			Str("user_id", maskedUserID).
+			Msg("Password changed but sessions could not be revoked")
+		return utils.ErrInternalServer
+	}
+
+	utils.SecurityAudit(ctx, utils.AUDIT_PASSWORD_RESET).
+		Str("user_id", maskedUserID).
+		Int("revoked_sessions", revoked).
+		Msg("Password reset through emailed link, all sessions revoked")
+
+	return nil
+}
@@ -0,0 +1,235 @@
+package authusecase_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	authusecase "Goshop/application/usecase/auth_usecase"
+	authentity "Goshop/domain/auth_entity"
+	userentity "Goshop/domain/entity/user_entity"
+	"Goshop/domain/mailer"
+	authrepository "Goshop/domain/repository/auth_repository"
+	userrepository "Goshop/domain/repository/user_repository"
+	"Goshop/interfaces/utils"
+	mockrepo "Goshop/mocks/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var tokenNow = time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+// fakeMailer garde les emails envoyés
+type fakeMailer struct {
+	sent []mailer.Message
+	err  error
+}
+
+func (m *fakeMailer) Send(_ context.Context, msg mailer.Message) error {
+	m.sent = append(m.sent, msg)
+	return m.err
+}
+
+// blockingMailer bloque l'envoi jusqu'à la fermeture de release
+type blockingMailer struct {
+	release chan struct{}
+	sent    []mailer.Message
+	ctxErr  error
+}
+
+func (m *blockingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	<-m.release
+	m.sent = append(m.sent, msg)
+	m.ctxErr = ctx.Err()
+	return nil
+}
+
+var linkToken = regexp.MustCompile(`\?token=(\S+)`)
+
+// tokenFromMail extrait le jeton en clair du lien envoyé
+func tokenFromMail(t *testing.T, msg mailer.Message) string {
+	t.Helper()
+	m := linkToken.FindStringSubmatch(msg.Body)
+	require.Len(t, m, 2, "no token link in %q", msg.Body)
+	token, err := url.QueryUnescape(m[1])
+	require.NoError(t, err)
+	return token
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+type resetMocks struct {
+	users    *mockrepo.MockUserRepository
+	tokens   *mockrepo.MockUserTokenRepository
+	sessions *mockrepo.MockRefreshSessionRepository
+	mail     *fakeMailer
+	uc       *authusecase.PasswordResetUsecase
+}
+
+func newResetUsecase(t *testing.T) *resetMocks {
+	ctrl := gomock.NewController(t)
+	m := &resetMocks{
+		users:    mockrepo.NewMockUserRepository(ctrl),
+		tokens:   mockrepo.NewMockUserTokenRepository(ctrl),
+		sessions: mockrepo.NewMockRefreshSessionRepository(ctrl),
+		mail:     &fakeMailer{},
+	}
+	m.uc = authusecase.NewPasswordResetUsecase(m.users, m.tokens, m.sessions, m.mail,
+		"https://shop.example.com/", func() time.Time { return tokenNow })
+	return m
+}
+
+func TestPasswordReset_RequestReset(t *testing.T) {
+	m := newResetUsecase(t)
+
+	var stored *authentity.UserToken
+	m.users.EXPECT().FindUserByEmail("alice@example.com").
+		Return(&userentity.UserEntity{ID: "user-1", Email: "alice@example.com"}, nil)
+	revoke := m.tokens.EXPECT().RevokeForUser(gomock.Any(), "user-1", authentity.UserTokenPasswordReset).Return(nil)
+	m.tokens.EXPECT().Create(gomock.Any(), gomock.Any()).After(revoke).
+		DoAndReturn(func(_ context.Context, token *authentity.UserToken) error {
+			stored = token
+			return nil
+		})
+
+	require.NoError(t, m.uc.RequestReset(context.Background(), " Alice@Example.com "))
+	m.uc.Wait()
+
+	require.Len(t, m.mail.sent, 1)
+	msg := m.mail.sent[0]
+	assert.Equal(t, "alice@example.com", msg.To)
+	assert.Contains(t, msg.Body, "https://shop.example.com/reset-password?token=")
+
+	// Seul le hash du jeton envoyé est stocké
+	require.NotNil(t, stored)
+	raw := tokenFromMail(t, msg)
+	assert.Equal(t, sha256Hex(raw), stored.TokenHash)
+	assert.NotContains(t, stored.TokenHash, raw)
+	assert.Equal(t, "user-1", stored.UserID)
+	assert.Equal(t, tokenNow.Add(authusecase.PasswordResetTokenTTL), stored.ExpiresAt)
+}
+
+func TestPasswordReset_RequestReset_UnknownEmail(t *testing.T) {
+	m := newResetUsecase(t)
+
+	m.users.EXPECT().FindUserByEmail("ghost@example.com").Return(nil, userrepository.ErrUserNotFound)
+
+	assert.NoError(t, m.uc.RequestReset(context.Background(), "ghost@example.com"))
+	assert.Empty(t, m.mail.sent)
+}
+
+func TestPasswordReset_RequestReset_MailFailureIsHidden(t *testing.T) {
+	m := newResetUsecase(t)
+	m.mail.err = errors.New("smtp: connection refused")
+
+	m.users.EXPECT().FindUserByEmail("alice@example.com").
+		Return(&userentity.UserEntity{ID: "user-1", Email: "alice@example.com"}, nil)
+	m.tokens.EXPECT().RevokeForUser(gomock.Any(), "user-1", authentity.UserTokenPasswordReset).Return(nil)
+	m.tokens.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	// Même réponse qu'un email inconnu
+	assert.NoError(t, m.uc.RequestReset(context.Background(), "alice@example.com"))
+	m.uc.Wait()
+}
+
+func TestPasswordReset_RequestReset_DoesNotWaitForMail(t *testing.T) {
+	m := newResetUsecase(t)
+	release := make(chan struct{})
+	blocking := &blockingMailer{release: release}
+	m.uc = authusecase.NewPasswordResetUsecase(m.users, m.tokens, m.sessions, blocking,
+		"https://shop.example.com/", func() time.Time { return tokenNow })
+
+	m.users.EXPECT().FindUserByEmail("alice@example.com").
+		Return(&userentity.UserEntity{ID: "user-1", Email: "alice@example.com"}, nil)
+	m.tokens.EXPECT().RevokeForUser(gomock.Any(), "user-1", authentity.UserTokenPasswordReset).Return(nil)
+	m.tokens.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	// La réponse part avant l'envoi, comme pour un email inconnu
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, m.uc.RequestReset(ctx, "alice@example.com"))
+	cancel()
+	close(release)
+	m.uc.Wait()
+
+	require.Len(t, blocking.sent, 1)
+	assert.NoError(t, blocking.ctxErr, "sending must survive the end of the request")
+}
+
+func TestPasswordReset_ResetPassword(t *testing.T) {
+	m := newResetUsecase(t)
+
+	m.tokens.EXPECT().Consume(gomock.Any(), sha256Hex("raw-token"), authentity.UserTokenPasswordReset).
+		Return(&authentity.UserToken{UserID: "user-1", Purpose: authentity.UserTokenPasswordReset}, nil)
+	update := m.users.EXPECT().UpdatePassword("user-1", gomock.Any()).
+		DoAndReturn(func(_ string, hash string) error {
+			assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte("n3w-passw0rd")))
+			return nil
+		})
+	m.sessions.EXPECT().RevokeAllForUser("user-1").After(update).Return(3, nil)
+
+	assert.NoError(t, m.uc.ResetPassword(context.Background(), "raw-token", "n3w-passw0rd"))
+}
+
+func TestPasswordReset_ResetPassword_InvalidToken(t *testing.T) {
+	m := newResetUsecase(t)
+
+	// Jeton inconnu, expiré ou déjà utilisé : rien n'est modifié
+	m.tokens.EXPECT().Consume(gomock.Any(), sha256Hex("used-token"), authentity.UserTokenPasswordReset).
+		Return(nil, authrepository.ErrUserTokenInvalid)
+
+	err := m.uc.ResetPassword(context.Background(), "used-token", "n3w-passw0rd")
+	assert.ErrorIs(t, err, utils.ErrResetTokenInvalid)
+
+	assert.ErrorIs(t, m.uc.ResetPassword(context.Background(), "", "n3w-passw0rd"), utils.ErrResetTokenInvalid)
+}
+
+func TestEmailVerification_SendAndVerify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	users := mockrepo.NewMockUserRepository(ctrl)
+	tokens := mockrepo.NewMockUserTokenRepository(ctrl)
+	mail := &fakeMailer{}
+	uc := authusecase.NewEmailVerificationUsecase(users, tokens, mail, "https://shop.example.com",
+		func() time.Time { return tokenNow })
+
+	var stored *authentity.UserToken
+	tokens.EXPECT().RevokeForUser(gomock.Any(), "user-1", authentity.UserTokenEmailVerification).Return(nil)
+	tokens.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, token *authentity.UserToken) error {
+			stored = token
+			return nil
+		})
+
+	require.NoError(t, uc.SendVerification(context.Background(), "user-1", "alice@example.com"))
+	require.Len(t, mail.sent, 1)
+	assert.Contains(t, mail.sent[0].Body, "https://shop.example.com/verify-email?token=")
+	assert.Equal(t, tokenNow.Add(authusecase.EmailVerificationTokenTTL), stored.ExpiresAt)
+
+	raw := tokenFromMail(t, mail.sent[0])
+	tokens.EXPECT().Consume(gomock.Any(), stored.TokenHash, authentity.UserTokenEmailVerification).
+		Return(stored, nil)
+	users.EXPECT().MarkEmailVerified("user-1").Return(nil)
+
+	assert.NoError(t, uc.Verify(context.Background(), raw))
+}
+
+func TestEmailVerification_Verify_InvalidToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tokens := mockrepo.NewMockUserTokenRepository(ctrl)
+	uc := authusecase.NewEmailVerificationUsecase(mockrepo.NewMockUserRepository(ctrl), tokens, &fakeMailer{}, "", time.Now)
+
+	tokens.EXPECT().Consume(gomock.Any(), gomock.Any(), authentity.UserTokenEmailVerification).
+		Return(nil, authrepository.ErrUserTokenInvalid)
+
+	assert.ErrorIs(t, uc.Verify(context.Background(), "expired"), utils.ErrEmailVerificationTokenInvalid)
+}
@@ -6,9 +6,11 @@ import (
 	"errors"
 	"time"
 
+	"Goshop/application/metrics"
 	"Goshop/config/setupLogging"
 	authentity "Goshop/domain/auth_entity"
 	authrepository "Goshop/domain/repository/auth_repository"
+	userrepository "Goshop/domain/repository/user_repository"
 	"Goshop/interfaces/utils"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -17,8 +19,9 @@ import (
 
 type RefreshUsecase struct {
 	repo               authrepository.RefreshSessionRepository
+	users              userrepository.UserRepository
 	validateToken      func(string) (jwt.MapClaims, error)
-	generateAccess     func(string) (string, error)
+	generateAccess     func(string, []string, []string) (string, error)
 	generateRefresh    func(string, string) (string, error)
 	now                func() time.Time
 	newJTI             func() string
@@ -28,8 +31,9 @@ type RefreshUsecase struct {
 
 func NewRefreshUsecase(
 	repo authrepository.RefreshSessionRepository,
+	users userrepository.UserRepository,
 	validateToken func(string) (jwt.MapClaims, error),
-	generateAccess func(string) (string, error),
+	generateAccess func(string, []string, []string) (string, error),
 	generateRefresh func(string, string) (string, error),
 	now func() time.Time,
 	newJTI func() string,
@@ -38,6 +42,7 @@ func NewRefreshUsecase(
 ) *RefreshUsecase {
 	return &RefreshUsecase{
 		repo:               repo,
+		users:              users,
 		validateToken:      validateToken,
 		generateAccess:     generateAccess,
 		generateRefresh:    generateRefresh,
@@ -123,6 +128,11 @@ func (uc *RefreshUsecase) Execute(ctx context.Context, oldRefreshToken string) (
 
 	// 6. Vérifications de session
 	if session.Revoked {
+		// Token déjà tourné présenté à nouveau : il a fuité (ou le client
+		// légitime a été devancé). Toute la famille est fermée.
+		if session.ReplacedBy != "" {
+			return "", "", uc.revokeFamily(ctx, session)
+		}
 		logger.Warn().
 			Time("revoked_at", session.CreatedAt).
 			Msg("Refresh token already revoked")
@@ -151,49 +161,68 @@ func (uc *RefreshUsecase) Execute(ctx context.Context, oldRefreshToken string) (
 		Bool("session_revoked", session.Revoked).
 		Msg("Session validation passed")
 
-	// 7. Révoque l'ancien token
-	logger.Debug().Msg("Revoking old refresh token")
-	if err := uc.repo.Revoke(jti); err != nil {
+	// 7. Recharge les rôles : un rôle accordé ou retiré s'applique au prochain refresh
+	user, err := uc.users.FindUserByID(sub)
+	if err != nil {
+		if errors.Is(err, userrepository.ErrUserNotFound) {
+			logger.Warn().
+				Str("user_id", maskedUserID).
+				Msg("Refresh token belongs to a deleted user")
+			return "", "", utils.ErrRefreshTokenInvalid
+		}
 		logger.Error().
 			Err(err).
 			Stack().
-			Msg("Failed to revoke refresh token")
+			Msg("Failed to load user roles")
 		return "", "", utils.ErrInternalServer
 	}
 
-	logger.Info().Msg("Old refresh token revoked successfully")
-
-	// 8. Crée la nouvelle session
+	// 8. Rotation atomique : révoque l'ancienne session et crée la suivante
+	// dans la même famille
 	newJti := uc.newJTI()
 	expiresAt := now.Add(uc.refreshExpiryDelta)
 
 	newSession := &authentity.RefreshSession{
-		ID:        newJti,
-		UserID:    sub,
-		ExpiresAt: expiresAt,
-		Revoked:   false,
-		CreatedAt: now,
+		ID:         newJti,
+		UserID:     sub,
+		ExpiresAt:  expiresAt,
+		Revoked:    false,
+		CreatedAt:  session.CreatedAt,
+		FamilyID:   familyOf(session),
+		ParentID:   session.ID,
+		LastUsedAt: now,
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IPAddress,
+	}
+	// L'appareil est celui qui présente le token (refresh hors requête HTTP : inchangé)
+	if userAgent, ip := utils.ClientInfoFromContext(ctx); ip != "" {
+		newSession.UserAgent = userAgent
+		newSession.IPAddress = ip
 	}
 
 	maskedNewJTI := maskJTI(newJti)
 	logger.Debug().
 		Str("new_jti", maskedNewJTI).
 		Time("new_expires_at", expiresAt).
-		Msg("Creating new refresh session")
+		Msg("Rotating refresh session")
 
-	if err := uc.repo.Create(newSession); err != nil {
+	if err := uc.repo.Rotate(jti, newSession); err != nil {
+		if errors.Is(err, authrepository.ErrSessionAlreadyRotated) {
+			// Une requête concurrente a consommé le token entre la lecture et la rotation
+			return "", "", uc.handleLostRotation(ctx, jti)
+		}
 		logger.Error().
 			Err(err).
 			Stack().
-			Msg("Failed to create new refresh session")
+			Msg("Failed to rotate refresh session")
 		return "", "", utils.ErrInternalServer
 	}
 
-	logger.Debug().Msg("New refresh session created successfully")
+	logger.Debug().Msg("Refresh session rotated successfully")
 
-	// 9. Génère les tokens
+	// 10. Génère les tokens
 	logger.Debug().Msg("Generating new access token")
-	access, err := uc.generateAccess(sub)
+	access, err := uc.generateAccess(sub, user.Roles, user.Permissions)
 	if err != nil {
 		logger.Error().
 			Err(err).
@@ -223,6 +252,60 @@ func (uc *RefreshUsecase) Execute(ctx context.Context, oldRefreshToken string) (
 	return access, refresh, nil
 }
 
+// handleLostRotation : la session a changé d'état depuis FindByID. Tournée
+// entre-temps, c'est un rejeu ; simplement révoquée (logout), non.
+func (uc *RefreshUsecase) handleLostRotation(ctx context.Context, jti string) error {
+	session, err := uc.repo.FindByID(jti)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("jti", maskJTI(jti)).
+			Msg("Failed to reload refresh session after concurrent rotation")
+		return utils.ErrInternalServer
+	}
+	if session.ReplacedBy != "" {
+		return uc.revokeFamily(ctx, session)
+	}
+	return utils.ErrRefreshTokenRevoked
+}
+
+// revokeFamily révoque toutes les sessions issues du même login que la
+// session rejouée et trace l'événement d'audit sécurité
+func (uc *RefreshUsecase) revokeFamily(ctx context.Context, session *authentity.RefreshSession) error {
+	familyID := familyOf(session)
+
+	revoked, err := uc.repo.RevokeFamily(familyID)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Stack().
+			Str("security_event", utils.AUDIT_REFRESH_TOKEN_REUSE).
+			Str("family_id", maskJTI(familyID)).
+			Msg("Failed to revoke refresh session family")
+		return utils.ErrInternalServer
+	}
+
+	metrics.AuthRefreshReuseDetectedTotal.Inc()
+
+	utils.SecurityAudit(ctx, utils.AUDIT_REFRESH_TOKEN_REUSE).
+		Str("user_id", maskUserID(session.UserID)).
+		Str("jti", maskJTI(session.ID)).
+		Str("replaced_by", maskJTI(session.ReplacedBy)).
+		Str("family_id", maskJTI(familyID)).
+		Int("revoked_sessions", revoked).
+		Msg("Refresh token reuse detected, session family revoked")
+
+	return utils.ErrRefreshTokenReused
+}
+
+// familyOf : une session antérieure aux familles est la racine de la sienne
+func familyOf(session *authentity.RefreshSession) string {
+	if session.FamilyID != "" {
+		return session.FamilyID
+	}
+	return session.ID
+}
+
 // Helpers de masquage (inchangés)
 func maskUserID(userID string) string {
 	if len(userID) <= 8 {
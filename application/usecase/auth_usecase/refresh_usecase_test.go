@@ -0,0 +1,148 @@
+package authusecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authusecase "Goshop/application/usecase/auth_usecase"
+	authentity "Goshop/domain/auth_entity"
+	userentity "Goshop/domain/entity/user_entity"
+	authrepository "Goshop/domain/repository/auth_repository"
+	"Goshop/interfaces/utils"
+	mockrepo "Goshop/mocks/repository"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var refreshNow = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newRefreshUsecase(repo *mockrepo.MockRefreshSessionRepository, users *mockrepo.MockUserRepository) *authusecase.RefreshUsecase {
+	return authusecase.NewRefreshUsecase(
+		repo,
+		users,
+		refreshClaims("user-1", "jti-1"),
+		func(sub string, _ []string, _ []string) (string, error) { return "access-" + sub, nil },
+		func(sub, jti string) (string, error) { return "refresh-" + jti, nil },
+		func() time.Time { return refreshNow },
+		func() string { return "jti-2" },
+		time.Hour,
+	)
+}
+
+func activeSession() *authentity.RefreshSession {
+	return &authentity.RefreshSession{
+		ID:        "jti-1",
+		UserID:    "user-1",
+		ExpiresAt: refreshNow.Add(time.Hour),
+		CreatedAt: refreshNow.Add(-time.Minute),
+		FamilyID:  "jti-0",
+		ParentID:  "jti-0",
+	}
+}
+
+func TestRefreshUsecase_RotatesWithinFamily(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	users := mockrepo.NewMockUserRepository(ctrl)
+
+	repo.EXPECT().FindByID("jti-1").Return(activeSession(), nil)
+	users.EXPECT().FindUserByID("user-1").Return(&userentity.UserEntity{ID: "user-1"}, nil)
+	repo.EXPECT().Rotate("jti-1", gomock.Any()).DoAndReturn(func(_ string, next *authentity.RefreshSession) error {
+		assert.Equal(t, "jti-2", next.ID)
+		assert.Equal(t, "jti-0", next.FamilyID)
+		assert.Equal(t, "jti-1", next.ParentID)
+		assert.Equal(t, refreshNow.Add(time.Hour), next.ExpiresAt)
+		// Date du login conservée, appareil mis à jour depuis la requête
+		assert.Equal(t, refreshNow.Add(-time.Minute), next.CreatedAt)
+		assert.Equal(t, refreshNow, next.LastUsedAt)
+		assert.Equal(t, "GoShop-Android/3.0", next.UserAgent)
+		assert.Equal(t, "198.51.100.4", next.IPAddress)
+		return nil
+	})
+
+	ctx := utils.WithClientInfo(context.Background(), "GoShop-Android/3.0", "198.51.100.4")
+	access, refresh, err := newRefreshUsecase(repo, users).Execute(ctx, "refresh-token")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "access-user-1", access)
+	assert.Equal(t, "refresh-jti-2", refresh)
+}
+
+func TestRefreshUsecase_ReplayRevokesFamily(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	users := mockrepo.NewMockUserRepository(ctrl)
+
+	rotated := activeSession()
+	rotated.Revoked = true
+	rotated.ReplacedBy = "jti-2"
+
+	repo.EXPECT().FindByID("jti-1").Return(rotated, nil)
+	repo.EXPECT().RevokeFamily("jti-0").Return(2, nil)
+
+	_, _, err := newRefreshUsecase(repo, users).Execute(context.Background(), "refresh-token")
+
+	assert.ErrorIs(t, err, utils.ErrRefreshTokenReused)
+}
+
+func TestRefreshUsecase_LoggedOutTokenIsNotReuse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	users := mockrepo.NewMockUserRepository(ctrl)
+
+	// Révoquée par un logout : jamais tournée, la famille n'est pas touchée
+	revoked := activeSession()
+	revoked.Revoked = true
+
+	repo.EXPECT().FindByID("jti-1").Return(revoked, nil)
+
+	_, _, err := newRefreshUsecase(repo, users).Execute(context.Background(), "refresh-token")
+
+	assert.ErrorIs(t, err, utils.ErrRefreshTokenRevoked)
+}
+
+func TestRefreshUsecase_ConcurrentRotationRevokesFamily(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	users := mockrepo.NewMockUserRepository(ctrl)
+
+	rotated := activeSession()
+	rotated.Revoked = true
+	rotated.ReplacedBy = "jti-9"
+
+	// Deux refresh simultanés du même token : le second perd la rotation
+	gomock.InOrder(
+		repo.EXPECT().FindByID("jti-1").Return(activeSession(), nil),
+		repo.EXPECT().FindByID("jti-1").Return(rotated, nil),
+	)
+	users.EXPECT().FindUserByID("user-1").Return(&userentity.UserEntity{ID: "user-1"}, nil)
+	repo.EXPECT().Rotate("jti-1", gomock.Any()).Return(authrepository.ErrSessionAlreadyRotated)
+	repo.EXPECT().RevokeFamily("jti-0").Return(2, nil)
+
+	_, _, err := newRefreshUsecase(repo, users).Execute(context.Background(), "refresh-token")
+
+	assert.ErrorIs(t, err, utils.ErrRefreshTokenReused)
+}
+
+func TestRefreshUsecase_LegacySessionIsOwnFamily(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	users := mockrepo.NewMockUserRepository(ctrl)
+
+	legacy := activeSession()
+	legacy.FamilyID = ""
+	legacy.ParentID = ""
+
+	repo.EXPECT().FindByID("jti-1").Return(legacy, nil)
+	users.EXPECT().FindUserByID("user-1").Return(&userentity.UserEntity{ID: "user-1"}, nil)
+	repo.EXPECT().Rotate("jti-1", gomock.Any()).DoAndReturn(func(_ string, next *authentity.RefreshSession) error {
+		assert.Equal(t, "jti-1", next.FamilyID)
+		return nil
+	})
+
+	_, _, err := newRefreshUsecase(repo, users).Execute(context.Background(), "refresh-token")
+
+	assert.NoError(t, err)
+}
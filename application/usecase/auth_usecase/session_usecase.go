@@ -0,0 +1,196 @@
+// application/usecase/auth_usecase/session_usecase.go
+package authusecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	authentity "Goshop/domain/auth_entity"
+	authrepository "Goshop/domain/repository/auth_repository"
+	"Goshop/interfaces/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog"
+)
+
+// SessionUsecase : sessions de refresh de l'utilisateur (liste, logout).
+// Les access tokens déjà émis restent valides jusqu'à leur expiration.
+//
+// Une session côté utilisateur est une famille de rotation : son ID public
+// est le family_id, stable d'un refresh à l'autre.
+type SessionUsecase struct {
+	repo          authrepository.RefreshSessionRepository
+	validateToken func(string) (jwt.MapClaims, error)
+}
+
+func NewSessionUsecase(
+	repo authrepository.RefreshSessionRepository,
+	validateToken func(string) (jwt.MapClaims, error),
+) *SessionUsecase {
+	return &SessionUsecase{
+		repo:          repo,
+		validateToken: validateToken,
+	}
+}
+
+// Logout révoque la session du refresh token présenté. Le token doit
+// appartenir à l'utilisateur connecté ; une session déjà révoquée ou expirée
+// n'est pas une erreur (logout idempotent).
+func (uc *SessionUsecase) Logout(ctx context.Context, userID, refreshToken string) error {
+	logger := zerolog.Ctx(ctx)
+	start := time.Now()
+
+	claims, err := uc.validateToken(refreshToken)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Str("operation", "logout").
+			Msg("Invalid refresh token presented at logout")
+		return utils.ErrRefreshTokenInvalid
+	}
+
+	if t, _ := claims["type"].(string); t != "refresh" {
+		return utils.ErrTokenTypeInvalid
+	}
+
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		return utils.ErrTokenJTIInvalid
+	}
+
+	session, err := uc.repo.FindByID(jti)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Str("operation", "logout").
+			Str("jti", maskJTI(jti)).
+			Msg("Refresh session not found at logout")
+		return utils.ErrRefreshTokenNotFound
+	}
+
+	// Le token d'un autre utilisateur ne permet pas de fermer sa session
+	if sub, _ := claims["sub"].(string); sub != userID || session.UserID != userID {
+		logger.Warn().
+			Str("operation", "logout").
+			Str("user_id", maskUserID(userID)).
+			Msg("Refresh token does not belong to the current user")
+		return utils.ErrRefreshTokenInvalid
+	}
+
+	if session.Revoked {
+		return nil
+	}
+
+	if err := uc.repo.Revoke(jti); err != nil {
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("operation", "logout").
+			Msg("Failed to revoke refresh session")
+		return utils.ErrInternalServer
+	}
+
+	logger.Info().
+		Str("operation", "logout").
+		Str("user_id", maskUserID(userID)).
+		Str("jti", maskJTI(jti)).
+		Dur("duration_ms", time.Since(start)).
+		Msg("Refresh session revoked")
+
+	return nil
+}
+
+// LogoutAll révoque toutes les sessions de l'utilisateur et retourne leur nombre
+func (uc *SessionUsecase) LogoutAll(ctx context.Context, userID string) (int, error) {
+	logger := zerolog.Ctx(ctx)
+	start := time.Now()
+
+	revoked, err := uc.repo.RevokeAllForUser(userID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("operation", "logout_all").
+			Str("user_id", maskUserID(userID)).
+			Msg("Failed to revoke refresh sessions")
+		return 0, utils.ErrInternalServer
+	}
+
+	logger.Info().
+		Str("operation", "logout_all").
+		Str("user_id", maskUserID(userID)).
+		Int("revoked_sessions", revoked).
+		Dur("duration_ms", time.Since(start)).
+		Msg("All refresh sessions revoked")
+
+	return revoked, nil
+}
+
+// ListSessions retourne les sessions actives (une par appareil connecté),
+// utilisées le plus récemment d'abord
+func (uc *SessionUsecase) ListSessions(ctx context.Context, userID string) ([]*authentity.RefreshSession, error) {
+	logger := zerolog.Ctx(ctx)
+
+	sessions, err := uc.repo.ListActiveForUser(userID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("operation", "list_sessions").
+			Str("user_id", maskUserID(userID)).
+			Msg("Failed to list refresh sessions")
+		return nil, utils.ErrInternalServer
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession ferme la session sessionID (family_id) de l'utilisateur.
+// Une session inconnue, déjà fermée ou appartenant à un autre utilisateur
+// donne ErrSessionNotFound.
+func (uc *SessionUsecase) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	logger := zerolog.Ctx(ctx)
+	start := time.Now()
+
+	// La racine de la famille porte l'ID public et le propriétaire
+	root, err := uc.repo.FindByID(sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return utils.ErrSessionNotFound
+		}
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("operation", "revoke_session").
+			Msg("Failed to load refresh session")
+		return utils.ErrInternalServer
+	}
+	if root.UserID != userID || root.FamilyID != root.ID {
+		return utils.ErrSessionNotFound
+	}
+
+	revoked, err := uc.repo.RevokeFamily(sessionID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("operation", "revoke_session").
+			Str("user_id", maskUserID(userID)).
+			Msg("Failed to revoke refresh session family")
+		return utils.ErrInternalServer
+	}
+	if revoked == 0 {
+		return utils.ErrSessionNotFound
+	}
+
+	logger.Info().
+		Str("operation", "revoke_session").
+		Str("user_id", maskUserID(userID)).
+		Str("session_id", maskJTI(sessionID)).
+		Dur("duration_ms", time.Since(start)).
+		Msg("Refresh session revoked")
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package authusecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	authusecase "Goshop/application/usecase/auth_usecase"
+	authentity "Goshop/domain/auth_entity"
+	"Goshop/interfaces/utils"
+	mockrepo "Goshop/mocks/repository"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// refreshClaims simule la validation d'un refresh token
+func refreshClaims(sub, jti string) func(string) (jwt.MapClaims, error) {
+	return func(string) (jwt.MapClaims, error) {
+		return jwt.MapClaims{"sub": sub, "jti": jti, "type": "refresh"}, nil
+	}
+}
+
+func TestSessionUsecase_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockRefreshSessionRepository(ctrl)
+
+	repo.EXPECT().FindByID("jti-1").Return(&authentity.RefreshSession{ID: "jti-1", UserID: "user-1"}, nil)
+	repo.EXPECT().Revoke("jti-1").Return(nil)
+
+	uc := authusecase.NewSessionUsecase(repo, refreshClaims("user-1", "jti-1"))
+
+	assert.NoError(t, uc.Logout(context.Background(), "user-1", "refresh-token"))
+}
+
+func TestSessionUsecase_Logout_AlreadyRevoked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockRefreshSessionRepository(ctrl)
+
+	// Idempotent : pas de nouvelle révocation
+	repo.EXPECT().FindByID("jti-1").Return(&authentity.RefreshSession{ID: "jti-1", UserID: "user-1", Revoked: true}, nil)
+
+	uc := authusecase.NewSessionUsecase(repo, refreshClaims("user-1", "jti-1"))
+
+	assert.NoError(t, uc.Logout(context.Background(), "user-1", "refresh-token"))
+}
+
+func TestSessionUsecase_Logout_OtherUserToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockRefreshSessionRepository(ctrl)
+
+	repo.EXPECT().FindByID("jti-1").Return(&authentity.RefreshSession{ID: "jti-1", UserID: "user-2"}, nil)
+
+	uc := authusecase.NewSessionUsecase(repo, refreshClaims("user-2", "jti-1"))
+
+	err := uc.Logout(context.Background(), "user-1", "refresh-token")
+	assert.ErrorIs(t, err, utils.ErrRefreshTokenInvalid)
+}
+
+func TestSessionUsecase_Logout_AccessTokenRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockRefreshSessionRepository(ctrl)
+
+	uc := authusecase.NewSessionUsecase(repo, func(string) (jwt.MapClaims, error) {
+		return jwt.MapClaims{"sub": "user-1", "type": "access"}, nil
+	})
+
+	err := uc.Logout(context.Background(), "user-1", "access-token")
+	assert.ErrorIs(t, err, utils.ErrTokenTypeInvalid)
+}
+
+func TestSessionUsecase_LogoutAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockRefreshSessionRepository(ctrl)
+
+	repo.EXPECT().RevokeAllForUser("user-1").Return(3, nil)
+
+	uc := authusecase.NewSessionUsecase(repo, refreshClaims("user-1", ""))
+
+	revoked, err := uc.LogoutAll(context.Background(), "user-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, revoked)
+}
+
+func TestSessionUsecase_LogoutAll_RepositoryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockRefreshSessionRepository(ctrl)
+
+	repo.EXPECT().RevokeAllForUser("user-1").Return(0, errors.New("connection refused"))
+
+	uc := authusecase.NewSessionUsecase(repo, refreshClaims("user-1", ""))
+
+	_, err := uc.LogoutAll(context.Background(), "user-1")
+	assert.ErrorIs(t, err, utils.ErrInternalServer)
+}
+
+func TestSessionUsecase_ListSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockRefreshSessionRepository(ctrl)
+
+	active := []*authentity.RefreshSession{
+		{ID: "jti-3", UserID: "user-1", FamilyID: "jti-1", UserAgent: "GoShop-iOS/2.1", IPAddress: "203.0.113.7"},
+		{ID: "jti-2", UserID: "user-1", FamilyID: "jti-2"},
+	}
+	repo.EXPECT().ListActiveForUser("user-1").Return(active, nil)
+
+	uc := authusecase.NewSessionUsecase(repo, refreshClaims("user-1", ""))
+
+	sessions, err := uc.ListSessions(context.Background(), "user-1")
+	assert.NoError(t, err)
+	assert.Equal(t, active, sessions)
+}
+
+func TestSessionUsecase_RevokeSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockRefreshSessionRepository(ctrl)
+
+	// La racine est déjà tournée : seule la famille compte
+	repo.EXPECT().FindByID("jti-1").Return(&authentity.RefreshSession{ID: "jti-1", UserID: "user-1", FamilyID: "jti-1", Revoked: true, ReplacedBy: "jti-3"}, nil)
+	repo.EXPECT().RevokeFamily("jti-1").Return(1, nil)
+
+	uc := authusecase.NewSessionUsecase(repo, refreshClaims("user-1", ""))
+
+	assert.NoError(t, uc.RevokeSession(context.Background(), "user-1", "jti-1"))
+}
+
+func TestSessionUsecase_RevokeSession_NotFound(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(repo *mockrepo.MockRefreshSessionRepository)
+	}{
+		{"session inconnue", func(repo *mockrepo.MockRefreshSessionRepository) {
+			repo.EXPECT().FindByID("jti-1").Return(nil, sql.ErrNoRows)
+		}},
+		{"session d'un autre utilisateur", func(repo *mockrepo.MockRefreshSessionRepository) {
+			repo.EXPECT().FindByID("jti-1").Return(&authentity.RefreshSession{ID: "jti-1", UserID: "user-2", FamilyID: "jti-1"}, nil)
+		}},
+		{"JTI qui n'identifie pas une famille", func(repo *mockrepo.MockRefreshSessionRepository) {
+			repo.EXPECT().FindByID("jti-1").Return(&authentity.RefreshSession{ID: "jti-1", UserID: "user-1", FamilyID: "jti-0"}, nil)
+		}},
+		{"session déjà fermée", func(repo *mockrepo.MockRefreshSessionRepository) {
+			repo.EXPECT().FindByID("jti-1").Return(&authentity.RefreshSession{ID: "jti-1", UserID: "user-1", FamilyID: "jti-1", Revoked: true}, nil)
+			repo.EXPECT().RevokeFamily("jti-1").Return(0, nil)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mockrepo.NewMockRefreshSessionRepository(ctrl)
+			tt.setup(repo)
+
+			uc := authusecase.NewSessionUsecase(repo, refreshClaims("user-1", ""))
+
+			err := uc.RevokeSession(context.Background(), "user-1", "jti-1")
+			assert.ErrorIs(t, err, utils.ErrSessionNotFound)
+		})
+	}
+}
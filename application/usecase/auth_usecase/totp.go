@@ -0,0 +1,97 @@
+// application/usecase/auth_usecase/totp.go
+package authusecase
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Paramètres TOTP (RFC 6238) : ceux par défaut des applications
+// d'authentification (Google Authenticator, Authy, 1Password...)
+const (
+	totpIssuer     = "GoShop"
+	totpDigits     = 6
+	totpPeriod     = 30 * time.Second
+	totpSkewSteps  = 1  // pas de temps acceptés de part et d'autre (décalage d'horloge)
+	totpSecretSize = 20 // 160 bits, taille recommandée pour HMAC-SHA1 (RFC 4226)
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newTOTPSecret génère un secret aléatoire encodé en base32
+func newTOTPSecret() (string, error) {
+	b := make([]byte, totpSecretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(b), nil
+}
+
+// hotp : RFC 4226, troncature dynamique d'un HMAC-SHA1 du compteur
+func hotp(key []byte, counter uint64, digits int) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, code%mod)
+}
+
+// totpStep : pas de temps (compteur HOTP) de l'instant t
+func totpStep(t time.Time) int64 {
+	return t.Unix() / int64(totpPeriod/time.Second)
+}
+
+// verifyTOTP cherche le code dans la fenêtre [now - skew, now + skew] et
+// retourne le pas de temps correspondant. Le plus récent gagne : l'appelant
+// refuse ensuite tout pas déjà utilisé (anti-rejeu).
+func verifyTOTP(secret, code string, now time.Time) (int64, bool) {
+	if len(code) != totpDigits {
+		return 0, false
+	}
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false
+	}
+
+	current := totpStep(now)
+	for step := current + totpSkewSteps; step >= current-totpSkewSteps; step-- {
+		if step < 0 {
+			break
+		}
+		expected := hotp(key, uint64(step), totpDigits)
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// totpURI : URI otpauth:// à afficher en QR code (format Key Uri de Google
+// Authenticator), le compte affiché étant l'email de l'utilisateur
+func totpURI(account, secret string) string {
+	label := url.PathEscape(totpIssuer + ":" + account)
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", totpIssuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprint(totpDigits))
+	q.Set("period", fmt.Sprint(int(totpPeriod/time.Second)))
+	return "otpauth://totp/" + label + "?" + q.Encode()
+}
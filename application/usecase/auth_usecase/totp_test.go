@@ -0,0 +1,98 @@
+package authusecase
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Secret des vecteurs de test des RFC 4226 et 6238 (HMAC-SHA1)
+var rfcSecret = []byte("12345678901234567890")
+
+func TestHOTP_RFC4226Vectors(t *testing.T) {
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, code := range want {
+		assert.Equal(t, code, hotp(rfcSecret, uint64(counter), 6), "counter %d", counter)
+	}
+}
+
+func TestTOTP_RFC6238Vectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	for _, tt := range tests {
+		step := totpStep(time.Unix(tt.unix, 0))
+		assert.Equal(t, tt.code, hotp(rfcSecret, uint64(step), 8), "T=%d", tt.unix)
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	secret := totpEncoding.EncodeToString(rfcSecret)
+	now := time.Unix(1234567890, 0)
+	current := totpStep(now)
+	code := func(step int64) string { return hotp(rfcSecret, uint64(step), totpDigits) }
+
+	step, ok := verifyTOTP(secret, code(current), now)
+	assert.True(t, ok)
+	assert.Equal(t, current, step)
+
+	// Décalage d'horloge d'un pas toléré dans les deux sens
+	step, ok = verifyTOTP(secret, code(current-1), now)
+	assert.True(t, ok)
+	assert.Equal(t, current-1, step)
+	_, ok = verifyTOTP(secret, code(current+1), now)
+	assert.True(t, ok)
+
+	// Au-delà, refusé
+	_, ok = verifyTOTP(secret, code(current-2), now)
+	assert.False(t, ok)
+	_, ok = verifyTOTP(secret, code(current+2), now)
+	assert.False(t, ok)
+
+	// Secret en minuscules accepté, code mal formé refusé
+	_, ok = verifyTOTP(strings.ToLower(secret), code(current), now)
+	assert.True(t, ok)
+	_, ok = verifyTOTP(secret, "12345", now)
+	assert.False(t, ok)
+	_, ok = verifyTOTP("not base32!", code(current), now)
+	assert.False(t, ok)
+}
+
+func TestNewTOTPSecret(t *testing.T) {
+	a, err := newTOTPSecret()
+	require.NoError(t, err)
+	b, err := newTOTPSecret()
+	require.NoError(t, err)
+
+	key, err := totpEncoding.DecodeString(a)
+	require.NoError(t, err)
+	assert.Len(t, key, totpSecretSize)
+	assert.NotContains(t, a, "=")
+	assert.NotEqual(t, a, b)
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := totpURI("alice+shop@example.com", "JBSWY3DPEHPK3PXP")
+
+	u, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, "/GoShop:alice+shop@example.com", u.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", u.Query().Get("secret"))
+	assert.Equal(t, "GoShop", u.Query().Get("issuer"))
+	assert.Equal(t, "6", u.Query().Get("digits"))
+	assert.Equal(t, "30", u.Query().Get("period"))
+}
@@ -0,0 +1,61 @@
+// application/usecase/auth_usecase/user_token.go
+package authusecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"time"
+
+	authentity "Goshop/domain/auth_entity"
+	authrepository "Goshop/domain/repository/auth_repository"
+)
+
+// newUserToken génère un jeton aléatoire de 256 bits. La valeur en clair part
+// dans l'email ; seul son hash est stocké.
+func newUserToken() (raw, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	raw = base64.RawURLEncoding.EncodeToString(b)
+	return raw, hashUserToken(raw), nil
+}
+
+func hashUserToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// issueUserToken invalide les jetons précédents de même usage puis en crée un
+// nouveau : seul le dernier lien envoyé reste utilisable
+func issueUserToken(ctx context.Context, tokens authrepository.UserTokenRepository, userID, purpose string, expiresAt time.Time) (string, error) {
+	if err := tokens.RevokeForUser(ctx, userID, purpose); err != nil {
+		return "", err
+	}
+
+	raw, hash, err := newUserToken()
+	if err != nil {
+		return "", err
+	}
+
+	err = tokens.Create(ctx, &authentity.UserToken{
+		TokenHash: hash,
+		UserID:    userID,
+		Purpose:   purpose,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// tokenLink : lien du frontend portant le jeton ("https://shop/reset-password?token=...")
+func tokenLink(publicURL, path, token string) string {
+	return strings.TrimRight(publicURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
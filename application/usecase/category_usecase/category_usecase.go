@@ -0,0 +1,237 @@
+// application/usecase/category_usecase/category_usecase.go
+package categoryusecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	dto "Goshop/application/dto/product_dto"
+	"Goshop/domain/entity"
+	"Goshop/domain/repository"
+	"Goshop/interfaces/utils"
+
+	"github.com/rs/zerolog"
+)
+
+// CategoryDetails : une catégorie avec son fil d'Ariane et ses enfants directs
+type CategoryDetails struct {
+	Category   *entity.Category
+	Breadcrumb []*entity.Category
+	Children   []*entity.Category
+}
+
+// CategoryUsecase gère l'arbre des catégories du catalogue. Les catégories
+// sont désignées par leur slug dans les URLs, par leur ID dans les liens
+// (parent, catégories d'un produit).
+type CategoryUsecase struct {
+	repo      repository.CategoryRepository
+	txManager repository.TxManager
+}
+
+func NewCategoryUsecase(repo repository.CategoryRepository, txManager repository.TxManager) *CategoryUsecase {
+	return &CategoryUsecase{repo: repo, txManager: txManager}
+}
+
+func (uc *CategoryUsecase) Create(ctx context.Context, req dto.CategoryRequest) (*entity.Category, error) {
+	logger := zerolog.Ctx(ctx)
+
+	if !entity.IsValidCategorySlug(req.Slug) {
+		return nil, utils.ErrValidationFailed
+	}
+
+	var parent *entity.Category
+	if req.ParentID != "" {
+		var err error
+		if parent, err = uc.findParent(ctx, uc.repo, req.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	category := &entity.Category{
+		Name:     strings.TrimSpace(req.Name),
+		Slug:     req.Slug,
+		ParentID: req.ParentID,
+		Path:     entity.CategoryPath(parent, req.Slug),
+	}
+	if err := uc.repo.Create(ctx, category); err != nil {
+		if errors.Is(err, repository.ErrCategorySlugTaken) {
+			return nil, utils.ErrCategorySlugTaken
+		}
+		logger.Error().Err(err).Str("operation", "category_create").Str("slug", req.Slug).Msg("Failed to create category")
+		return nil, utils.ErrInternalServer
+	}
+
+	logger.Info().
+		Str("operation", "category_create").
+		Str("category_id", category.ID).
+		Str("path", category.Path).
+		Msg("Category created")
+
+	return category, nil
+}
+
+// List renvoie tout l'arbre, trié par path
+func (uc *CategoryUsecase) List(ctx context.Context) ([]*entity.Category, error) {
+	categories, err := uc.repo.FindAll(ctx)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Str("operation", "category_list").Msg("Failed to list categories")
+		return nil, utils.ErrInternalServer
+	}
+	return categories, nil
+}
+
+func (uc *CategoryUsecase) Get(ctx context.Context, slug string) (*CategoryDetails, error) {
+	logger := zerolog.Ctx(ctx)
+
+	category, err := uc.findBySlug(ctx, uc.repo, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	breadcrumb, err := uc.repo.FindBreadcrumb(ctx, category.Path)
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "category_get").Str("slug", slug).Msg("Failed to load category breadcrumb")
+		return nil, utils.ErrInternalServer
+	}
+
+	children, err := uc.repo.FindChildren(ctx, category.ID)
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "category_get").Str("slug", slug).Msg("Failed to load subcategories")
+		return nil, utils.ErrInternalServer
+	}
+
+	return &CategoryDetails{Category: category, Breadcrumb: breadcrumb, Children: children}, nil
+}
+
+// Update renomme et/ou déplace une catégorie ; ses descendants suivent.
+// Une catégorie ne peut pas être placée sous elle-même ou sous l'un de ses
+// descendants.
+func (uc *CategoryUsecase) Update(ctx context.Context, slug string, req dto.CategoryRequest) (*entity.Category, error) {
+	logger := zerolog.Ctx(ctx)
+
+	if !entity.IsValidCategorySlug(req.Slug) {
+		return nil, utils.ErrValidationFailed
+	}
+
+	tx, err := uc.txManager.BeginTx(ctx)
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "category_update").Msg("Failed to begin transaction")
+		return nil, utils.ErrTransactionBegin
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+				logger.Error().Err(rollbackErr).Str("operation", "category_update").Msg("Failed to rollback transaction")
+			}
+		}
+	}()
+
+	repo := uc.repo.WithTX(tx)
+
+	// Sans verrou, deux déplacements croisés (A sous B, B sous A) passeraient
+	// chacun le contrôle de cycle et formeraient une boucle une fois committés
+	if err = repo.LockTree(ctx); err != nil {
+		logger.Error().Err(err).Str("operation", "category_update").Msg("Failed to lock category tree")
+		return nil, utils.ErrInternalServer
+	}
+
+	category, err := uc.findBySlug(ctx, repo, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	var parent *entity.Category
+	if req.ParentID != "" {
+		if parent, err = uc.findParent(ctx, repo, req.ParentID); err != nil {
+			return nil, err
+		}
+		if category.Contains(parent) {
+			err = utils.ErrCategoryInvalidParent
+			return nil, err
+		}
+	}
+
+	oldPath := category.Path
+	category.Name = strings.TrimSpace(req.Name)
+	category.Slug = req.Slug
+	category.ParentID = req.ParentID
+	category.Path = entity.CategoryPath(parent, req.Slug)
+
+	if err = repo.Update(ctx, category, oldPath); err != nil {
+		if errors.Is(err, repository.ErrCategorySlugTaken) {
+			return nil, utils.ErrCategorySlugTaken
+		}
+		logger.Error().Err(err).Str("operation", "category_update").Str("category_id", category.ID).Msg("Failed to update category")
+		return nil, utils.ErrInternalServer
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.Error().Err(err).Str("operation", "category_update").Str("category_id", category.ID).Msg("Failed to commit category update")
+		return nil, utils.ErrTransactionCommit
+	}
+
+	logger.Info().
+		Str("operation", "category_update").
+		Str("category_id", category.ID).
+		Str("old_path", oldPath).
+		Str("path", category.Path).
+		Msg("Category updated")
+
+	return category, nil
+}
+
+// Delete supprime une catégorie sans enfant ; ses produits sont seulement
+// détachés
+func (uc *CategoryUsecase) Delete(ctx context.Context, slug string) error {
+	logger := zerolog.Ctx(ctx)
+
+	category, err := uc.findBySlug(ctx, uc.repo, slug)
+	if err != nil {
+		return err
+	}
+
+	err = uc.repo.Delete(ctx, category.ID)
+	switch {
+	case errors.Is(err, repository.ErrCategoryHasChildren):
+		return utils.ErrCategoryHasChildren
+	case errors.Is(err, repository.ErrCategoryNotFound):
+		return utils.ErrCategoryNotFound
+	case err != nil:
+		logger.Error().Err(err).Str("operation", "category_delete").Str("category_id", category.ID).Msg("Failed to delete category")
+		return utils.ErrInternalServer
+	}
+
+	logger.Info().
+		Str("operation", "category_delete").
+		Str("category_id", category.ID).
+		Str("path", category.Path).
+		Msg("Category deleted")
+
+	return nil
+}
+
+func (uc *CategoryUsecase) findBySlug(ctx context.Context, repo repository.CategoryRepository, slug string) (*entity.Category, error) {
+	category, err := repo.FindBySlug(ctx, slug)
+	if errors.Is(err, repository.ErrCategoryNotFound) {
+		return nil, utils.ErrCategoryNotFound
+	}
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Str("operation", "category_find").Str("slug", slug).Msg("Failed to find category")
+		return nil, utils.ErrInternalServer
+	}
+	return category, nil
+}
+
+func (uc *CategoryUsecase) findParent(ctx context.Context, repo repository.CategoryRepository, id string) (*entity.Category, error) {
+	parent, err := repo.FindByID(ctx, id)
+	if errors.Is(err, repository.ErrCategoryNotFound) {
+		return nil, utils.ErrCategoryInvalidParent
+	}
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Str("operation", "category_find_parent").Str("parent_id", id).Msg("Failed to find parent category")
+		return nil, utils.ErrInternalServer
+	}
+	return parent, nil
+}
@@ -0,0 +1,166 @@
+package categoryusecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	dto "Goshop/application/dto/product_dto"
+	categoryusecase "Goshop/application/usecase/category_usecase"
+	"Goshop/domain/entity"
+	domainrepo "Goshop/domain/repository"
+	"Goshop/interfaces/utils"
+	"Goshop/mocks/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func electronics() *entity.Category {
+	return &entity.Category{ID: "cat-1", Name: "Électronique", Slug: "electronique", Path: "electronique"}
+}
+
+func laptops() *entity.Category {
+	return &entity.Category{ID: "cat-2", Name: "Portables", Slug: "portables", ParentID: "cat-1", Path: "electronique/portables"}
+}
+
+func TestCategoryUsecase_CreateUnderParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockCategoryRepository(ctrl)
+
+	mockRepo.EXPECT().FindByID(gomock.Any(), "cat-1").Return(electronics(), nil)
+	mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, c *entity.Category) error {
+			assert.Equal(t, "electronique/portables", c.Path)
+			assert.Equal(t, "Portables", c.Name)
+			c.ID = "cat-2"
+			return nil
+		})
+
+	uc := categoryusecase.NewCategoryUsecase(mockRepo, repository.NewMockTxManager(ctrl))
+	created, err := uc.Create(context.Background(), dto.CategoryRequest{Name: " Portables ", Slug: "portables", ParentID: "cat-1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "cat-2", created.ID)
+}
+
+func TestCategoryUsecase_CreateErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockCategoryRepository(ctrl)
+	uc := categoryusecase.NewCategoryUsecase(mockRepo, repository.NewMockTxManager(ctrl))
+
+	_, err := uc.Create(context.Background(), dto.CategoryRequest{Name: "X", Slug: "Bad Slug"})
+	assert.ErrorIs(t, err, utils.ErrValidationFailed)
+
+	mockRepo.EXPECT().FindByID(gomock.Any(), "missing").Return(nil, domainrepo.ErrCategoryNotFound)
+	_, err = uc.Create(context.Background(), dto.CategoryRequest{Name: "X", Slug: "x", ParentID: "missing"})
+	assert.ErrorIs(t, err, utils.ErrCategoryInvalidParent)
+
+	mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domainrepo.ErrCategorySlugTaken)
+	_, err = uc.Create(context.Background(), dto.CategoryRequest{Name: "Électronique", Slug: "electronique"})
+	assert.ErrorIs(t, err, utils.ErrCategorySlugTaken)
+}
+
+func TestCategoryUsecase_Get(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockCategoryRepository(ctrl)
+
+	mockRepo.EXPECT().FindBySlug(gomock.Any(), "portables").Return(laptops(), nil)
+	mockRepo.EXPECT().FindBreadcrumb(gomock.Any(), "electronique/portables").Return([]*entity.Category{electronics(), laptops()}, nil)
+	mockRepo.EXPECT().FindChildren(gomock.Any(), "cat-2").Return([]*entity.Category{}, nil)
+	mockRepo.EXPECT().FindBySlug(gomock.Any(), "inconnue").Return(nil, domainrepo.ErrCategoryNotFound)
+
+	uc := categoryusecase.NewCategoryUsecase(mockRepo, repository.NewMockTxManager(ctrl))
+
+	details, err := uc.Get(context.Background(), "portables")
+	require.NoError(t, err)
+	assert.Len(t, details.Breadcrumb, 2)
+
+	_, err = uc.Get(context.Background(), "inconnue")
+	assert.ErrorIs(t, err, utils.ErrCategoryNotFound)
+}
+
+func TestCategoryUsecase_UpdateMovesCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockCategoryRepository(ctrl)
+	mockRepoTx := repository.NewMockCategoryRepository(ctrl)
+	mockTxManager := repository.NewMockTxManager(ctrl)
+	mockTx := repository.NewMockTx(ctrl)
+
+	computing := &entity.Category{ID: "cat-3", Name: "Informatique", Slug: "informatique", Path: "informatique"}
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTX(mockTx).Return(mockRepoTx)
+	lock := mockRepoTx.EXPECT().LockTree(gomock.Any()).Return(nil)
+	mockRepoTx.EXPECT().FindBySlug(gomock.Any(), "portables").After(lock).Return(laptops(), nil)
+	mockRepoTx.EXPECT().FindByID(gomock.Any(), "cat-3").Return(computing, nil)
+	mockRepoTx.EXPECT().Update(gomock.Any(), gomock.Any(), "electronique/portables").
+		DoAndReturn(func(_ context.Context, c *entity.Category, _ string) error {
+			assert.Equal(t, "informatique/portables", c.Path)
+			return nil
+		})
+	mockTx.EXPECT().Commit().Return(nil)
+
+	uc := categoryusecase.NewCategoryUsecase(mockRepo, mockTxManager)
+	updated, err := uc.Update(context.Background(), "portables", dto.CategoryRequest{Name: "Portables", Slug: "portables", ParentID: "cat-3"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "cat-3", updated.ParentID)
+}
+
+func TestCategoryUsecase_UpdateRejectsCycle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockCategoryRepository(ctrl)
+	mockRepoTx := repository.NewMockCategoryRepository(ctrl)
+	mockTxManager := repository.NewMockTxManager(ctrl)
+	mockTx := repository.NewMockTx(ctrl)
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTX(mockTx).Return(mockRepoTx)
+	mockRepoTx.EXPECT().LockTree(gomock.Any()).Return(nil)
+	mockRepoTx.EXPECT().FindBySlug(gomock.Any(), "electronique").Return(electronics(), nil)
+	// Placer "electronique" sous son propre enfant formerait un cycle
+	mockRepoTx.EXPECT().FindByID(gomock.Any(), "cat-2").Return(laptops(), nil)
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	uc := categoryusecase.NewCategoryUsecase(mockRepo, mockTxManager)
+	_, err := uc.Update(context.Background(), "electronique", dto.CategoryRequest{Name: "Électronique", Slug: "electronique", ParentID: "cat-2"})
+
+	assert.ErrorIs(t, err, utils.ErrCategoryInvalidParent)
+}
+
+func TestCategoryUsecase_UpdateLockFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockCategoryRepository(ctrl)
+	mockRepoTx := repository.NewMockCategoryRepository(ctrl)
+	mockTxManager := repository.NewMockTxManager(ctrl)
+	mockTx := repository.NewMockTx(ctrl)
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTX(mockTx).Return(mockRepoTx)
+	mockRepoTx.EXPECT().LockTree(gomock.Any()).Return(assert.AnError)
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	uc := categoryusecase.NewCategoryUsecase(mockRepo, mockTxManager)
+	_, err := uc.Update(context.Background(), "portables", dto.CategoryRequest{Name: "Portables", Slug: "portables", ParentID: "cat-3"})
+
+	assert.ErrorIs(t, err, utils.ErrInternalServer)
+}
+
+func TestCategoryUsecase_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockCategoryRepository(ctrl)
+	uc := categoryusecase.NewCategoryUsecase(mockRepo, repository.NewMockTxManager(ctrl))
+
+	mockRepo.EXPECT().FindBySlug(gomock.Any(), "electronique").Return(electronics(), nil)
+	mockRepo.EXPECT().Delete(gomock.Any(), "cat-1").Return(domainrepo.ErrCategoryHasChildren)
+	assert.ErrorIs(t, uc.Delete(context.Background(), "electronique"), utils.ErrCategoryHasChildren)
+
+	mockRepo.EXPECT().FindBySlug(gomock.Any(), "portables").Return(laptops(), nil)
+	mockRepo.EXPECT().Delete(gomock.Any(), "cat-2").Return(nil)
+	assert.NoError(t, uc.Delete(context.Background(), "portables"))
+
+	mockRepo.EXPECT().FindBySlug(gomock.Any(), "portables").Return(nil, errors.New("connection reset"))
+	assert.ErrorIs(t, uc.Delete(context.Background(), "portables"), utils.ErrInternalServer)
+}
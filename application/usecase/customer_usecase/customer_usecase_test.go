@@ -5,9 +5,11 @@ import (
 	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	customerusecase "Goshop/application/usecase/customer_usecase"
 	"Goshop/domain/entity"
+	"Goshop/interfaces/utils"
 	mockrepo "Goshop/mocks/repository"
 
 	"github.com/stretchr/testify/assert"
@@ -173,6 +175,53 @@ func TestGetCustomerByIdUsecase_NotFound(t *testing.T) {
 	assert.Contains(t, err.Error(), "customer not found")
 }
 
+// customerContext : utilisateur connecté sans permission ":any", rattaché à `customerID`
+func customerContext(customerID string) context.Context {
+	ctx := utils.WithUser(context.Background(), "user-1", "customer")
+	ctx = utils.WithUserPermissions(ctx, []string{"orders:write"})
+	return utils.WithCustomerID(ctx, customerID)
+}
+
+func TestGetCustomerByIdUsecase_OwnProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+	mockTx := mockrepo.NewMockTx(ctrl)
+	mockRepoTx := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+
+	customer := &entity.Customer{ID: "cust-123", FirstName: "John", LastName: "Doe"}
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTX(mockTx).Return(mockRepoTx)
+	mockRepoTx.EXPECT().FindByCustomerID(gomock.Any(), "cust-123").Return(customer, nil)
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Rollback().Return(nil).AnyTimes()
+
+	uc := customerusecase.NewGetCustomerByIdUsecase(mockRepo, mockTxManager)
+
+	result, err := uc.Execute(customerContext("cust-123"), "cust-123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, customer, result)
+}
+
+func TestGetCustomerByIdUsecase_OtherCustomerDenied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Aucun accès base : le refus précède la lecture
+	mockRepo := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+
+	uc := customerusecase.NewGetCustomerByIdUsecase(mockRepo, mockTxManager)
+
+	_, err := uc.Execute(customerContext("cust-123"), "cust-456")
+
+	assert.ErrorIs(t, err, utils.ErrCustomerNotFound)
+}
+
 //
 // -----------------------------------------------------------
 // GET ALL CUSTOMERS
@@ -307,6 +356,25 @@ func TestUpdateCustomerUsecase_NotFound(t *testing.T) {
 	assert.Contains(t, err.Error(), "customer not found")
 }
 
+func TestUpdateCustomerUsecase_OtherCustomerDenied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+
+	uc := customerusecase.NewUpdateCustomerUsecase(mockRepo, mockTxManager)
+
+	_, err := uc.Execute(customerContext("cust-123"), &entity.Customer{
+		ID:        "cust-456",
+		FirstName: "Jane",
+		LastName:  "Doe",
+		Email:     "jane@example.com",
+	})
+
+	assert.ErrorIs(t, err, utils.ErrCustomerNotFound)
+}
+
 //
 // -----------------------------------------------------------
 // DELETE CUSTOMER
@@ -365,3 +433,122 @@ func TestDeleteCustomerUsecase_BeginTxError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to begin transaction")
 }
+
+//
+// -----------------------------------------------------------
+// ARCHIVED CUSTOMERS
+// -----------------------------------------------------------
+//
+
+func TestGetCustomerByIdUsecase_IncludingDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+	mockTx := mockrepo.NewMockTx(ctrl)
+	mockRepoTx := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+
+	deletedAt := time.Now()
+	customer := &entity.Customer{ID: "cust-123", FirstName: "John", LastName: "Doe", DeletedAt: &deletedAt}
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTX(mockTx).Return(mockRepoTx)
+	mockRepoTx.EXPECT().FindByCustomerIDIncludingDeleted(gomock.Any(), "cust-123").Return(customer, nil)
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Rollback().Return(nil).AnyTimes()
+
+	uc := customerusecase.NewGetCustomerByIdUsecase(mockRepo, mockTxManager)
+
+	result, err := uc.ExecuteIncludingDeleted(context.Background(), "cust-123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, customer, result)
+}
+
+func TestGetCustomerByIdUsecase_IncludingDeletedDeniedToCustomer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+
+	uc := customerusecase.NewGetCustomerByIdUsecase(mockRepo, mockTxManager)
+
+	// Même son propre profil : un client ne voit pas les profils archivés
+	_, err := uc.ExecuteIncludingDeleted(customerContext("cust-123"), "cust-123")
+
+	assert.ErrorIs(t, err, utils.ErrForbidden)
+}
+
+func TestRestoreCustomerUsecase_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+	mockTx := mockrepo.NewMockTx(ctrl)
+	mockRepo := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+	mockRepoTx := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+
+	deletedAt := time.Now()
+	archived := &entity.Customer{ID: "id1", Email: "john@mail.com", DeletedAt: &deletedAt}
+	restored := &entity.Customer{ID: "id1", Email: "john@mail.com"}
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTX(mockTx).Return(mockRepoTx)
+	mockRepoTx.EXPECT().FindByCustomerIDIncludingDeleted(gomock.Any(), "id1").Return(archived, nil)
+	mockRepoTx.EXPECT().RestoreCustomer(gomock.Any(), "id1").Return(restored, nil)
+	mockTx.EXPECT().Commit().Return(nil)
+
+	uc := customerusecase.NewRestoreCustomerUsecase(mockRepo, mockTxManager)
+
+	result, err := uc.Execute(context.Background(), "id1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, restored, result)
+}
+
+func TestRestoreCustomerUsecase_ActiveCustomerUnchanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+	mockTx := mockrepo.NewMockTx(ctrl)
+	mockRepo := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+	mockRepoTx := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+
+	active := &entity.Customer{ID: "id1", Email: "john@mail.com"}
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTX(mockTx).Return(mockRepoTx)
+	mockRepoTx.EXPECT().FindByCustomerIDIncludingDeleted(gomock.Any(), "id1").Return(active, nil)
+	mockTx.EXPECT().Commit().Return(nil)
+
+	uc := customerusecase.NewRestoreCustomerUsecase(mockRepo, mockTxManager)
+
+	result, err := uc.Execute(context.Background(), "id1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, active, result)
+}
+
+func TestRestoreCustomerUsecase_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+	mockTx := mockrepo.NewMockTx(ctrl)
+	mockRepo := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+	mockRepoTx := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTX(mockTx).Return(mockRepoTx)
+	mockRepoTx.EXPECT().FindByCustomerIDIncludingDeleted(gomock.Any(), "unknown").Return(nil, sql.ErrNoRows)
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	uc := customerusecase.NewRestoreCustomerUsecase(mockRepo, mockTxManager)
+
+	_, err := uc.Execute(context.Background(), "unknown")
+
+	assert.ErrorIs(t, err, utils.ErrCustomerNotFound)
+}
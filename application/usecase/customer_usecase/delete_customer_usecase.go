@@ -7,7 +7,9 @@ import (
 	"errors"
 	"time"
 
+	userentity "Goshop/domain/entity/user_entity"
 	"Goshop/domain/repository"
+	"Goshop/interfaces/utils"
 
 	"github.com/rs/zerolog"
 )
@@ -47,6 +49,15 @@ func (uc *DeleteCustomerUsecase) Execute(ctx context.Context, id string) error {
 		Str("customer_id", id).
 		Msg("Starting customer deletion process")
 
+	// Un utilisateur sans permission ":any" ne supprime que son propre profil
+	if !utils.CanAccessCustomer(ctx, id, userentity.PermCustomersWrite) {
+		logger.Warn().
+			Str("operation", "execute").
+			Str("customer_id", id).
+			Msg("Deletion of another user's customer denied")
+		return utils.ErrCustomerNotFound
+	}
+
 	// Début de la transaction
 	logger.Debug().
 		Str("operation", "execute").
@@ -134,7 +145,7 @@ func (uc *DeleteCustomerUsecase) Execute(ctx context.Context, id string) error {
 
 	deleteErr := repo.DeleteCustomer(ctx, id)
 	if deleteErr != nil {
-		if deleteErr == sql.ErrNoRows {
+		if errors.Is(deleteErr, sql.ErrNoRows) {
 			logger.Warn().
 				Err(deleteErr).
 				Dur("duration_before_error", time.Since(start)).
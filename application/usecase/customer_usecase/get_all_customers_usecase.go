@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"time"
 
+	dto "Goshop/application/dto/customer_dto"
+	paginationdto "Goshop/application/dto/pagination_dto"
 	"Goshop/domain/entity"
 	"Goshop/domain/repository"
 
@@ -131,6 +133,61 @@ func (uc *GetAllCustomersUsecase) Execute(ctx context.Context) ([]*entity.Custom
 	return customers, nil
 }
 
+// ExecuteWithPagination renvoie une page de clients (mode offset) et le nombre
+// total de clients correspondant au filtre.
+func (uc *GetAllCustomersUsecase) ExecuteWithPagination(ctx context.Context, limit, offset int, filter dto.CustomerFilter) ([]*entity.Customer, int, error) {
+	logger := zerolog.Ctx(ctx)
+
+	customers, err := uc.repo.FindAllCustomersWithPagination(ctx, limit, offset, filter)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute_with_pagination").
+			Int("limit", limit).
+			Int("offset", offset).
+			Msg("Failed to retrieve customers page from repository")
+		return nil, 0, fmt.Errorf("failed to retrieve customers: %w", err)
+	}
+
+	total, err := uc.repo.CountAllCustomers(ctx, filter)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute_with_pagination").
+			Msg("Failed to count customers")
+		return nil, 0, fmt.Errorf("failed to count customers: %w", err)
+	}
+
+	return customers, total, nil
+}
+
+// ExecuteCursor renvoie une page de clients après le curseur, triée par
+// (created_at DESC, id DESC).
+func (uc *GetAllCustomersUsecase) ExecuteCursor(ctx context.Context, page paginationdto.CursorPage, filter dto.CustomerFilter) (*paginationdto.CursorResult[*entity.Customer], error) {
+	logger := zerolog.Ctx(ctx)
+
+	// Un élément de plus pour savoir s'il reste une page
+	query := page
+	query.Limit = page.Limit + 1
+
+	customers, err := uc.repo.FindAllCustomersByCursor(ctx, query, filter)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute_cursor").
+			Int("limit", page.Limit).
+			Bool("backward", page.Backward).
+			Msg("Failed to retrieve customers page from repository")
+		return nil, fmt.Errorf("failed to retrieve customers: %w", err)
+	}
+
+	customers, hasMore := paginationdto.TrimPage(customers, page.Limit, page.Backward)
+
+	return paginationdto.NewCursorResult(customers, hasMore, func(c *entity.Customer) paginationdto.Cursor {
+		return paginationdto.Cursor{CreatedAt: c.CreatedAt, ID: c.ID}
+	}, customers), nil
+}
+
 // analyzeCustomers — utilise uc.logger, pas de paramètre logger
 func (uc *GetAllCustomersUsecase) analyzeCustomers(ctx context.Context, customers []*entity.Customer) {
 	logger := zerolog.Ctx(ctx)
@@ -1,5 +1,4 @@
 // application/usecase/customer_usecase/get_customer_by_id_usecase.go
-// application/usecase/customer_usecase/get_customer_by_id_usecase.go
 package customerusecase
 
 import (
@@ -9,7 +8,9 @@ import (
 	"time"
 
 	"Goshop/domain/entity"
+	userentity "Goshop/domain/entity/user_entity"
 	"Goshop/domain/repository"
+	"Goshop/interfaces/utils"
 
 	"github.com/rs/zerolog"
 )
@@ -33,6 +34,23 @@ func NewGetCustomerByIdUsecase(
 }
 
 func (uc *GetCustomerByIdUsecase) Execute(ctx context.Context, id string) (*entity.Customer, error) {
+	return uc.execute(ctx, id, false)
+}
+
+// ExecuteIncludingDeleted renvoie aussi un client archivé ; réservé aux
+// utilisateurs ayant customers:read:any
+func (uc *GetCustomerByIdUsecase) ExecuteIncludingDeleted(ctx context.Context, id string) (*entity.Customer, error) {
+	if _, restricted := utils.OwnCustomerScope(ctx, userentity.PermCustomersReadAny); restricted {
+		zerolog.Ctx(ctx).Warn().
+			Str("operation", "execute_including_deleted").
+			Str("customer_id", id).
+			Msg("Reading archived customers denied")
+		return nil, utils.ErrForbidden
+	}
+	return uc.execute(ctx, id, true)
+}
+
+func (uc *GetCustomerByIdUsecase) execute(ctx context.Context, id string, includeDeleted bool) (*entity.Customer, error) {
 	logger := zerolog.Ctx(ctx)
 	if id == "" {
 		logger.Warn().
@@ -48,6 +66,15 @@ func (uc *GetCustomerByIdUsecase) Execute(ctx context.Context, id string) (*enti
 		Str("customer_id", id).
 		Msg("Starting customer retrieval by ID")
 
+	// Un utilisateur sans permission ":any" n'accède qu'à son propre profil
+	if !utils.CanAccessCustomer(ctx, id, userentity.PermCustomersReadAny) {
+		logger.Warn().
+			Str("operation", "execute").
+			Str("customer_id", id).
+			Msg("Access to another user's customer denied")
+		return nil, utils.ErrCustomerNotFound
+	}
+
 	// ✅ AJOUT : utilise une transaction (lecture cohérente)
 	logger.Debug().
 		Str("operation", "execute").
@@ -83,7 +110,11 @@ func (uc *GetCustomerByIdUsecase) Execute(ctx context.Context, id string) (*enti
 		Msg("Repository attached to transaction")
 
 	// ✅ Lecture via le repository transactionnel
-	customer, err := repo.FindByCustomerID(ctx, id)
+	find := repo.FindByCustomerID
+	if includeDeleted {
+		find = repo.FindByCustomerIDIncludingDeleted
+	}
+	customer, err := find(ctx, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			logger.Warn().
@@ -0,0 +1,133 @@
+// application/usecase/customer_usecase/restore_customer_usecase.go
+package customerusecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"Goshop/domain/entity"
+	userentity "Goshop/domain/entity/user_entity"
+	"Goshop/domain/repository"
+	"Goshop/interfaces/utils"
+
+	"github.com/rs/zerolog"
+)
+
+// RestoreCustomerUsecase annule l'archivage d'un client (DELETE)
+type RestoreCustomerUsecase struct {
+	repo      repository.CustomerRepositoryInterface
+	txManager repository.TxManager
+}
+
+func NewRestoreCustomerUsecase(
+	repo repository.CustomerRepositoryInterface,
+	txManager repository.TxManager,
+) *RestoreCustomerUsecase {
+	return &RestoreCustomerUsecase{
+		repo:      repo,
+		txManager: txManager,
+	}
+}
+
+// Execute restaure le client. Restaurer un client actif ne change rien.
+func (uc *RestoreCustomerUsecase) Execute(ctx context.Context, id string) (*entity.Customer, error) {
+	logger := zerolog.Ctx(ctx)
+	if id == "" {
+		logger.Warn().
+			Str("operation", "execute").
+			Msg("Customer ID is empty")
+		return nil, errors.New("customer ID is required")
+	}
+
+	start := time.Now()
+
+	if !utils.CanAccessCustomer(ctx, id, userentity.PermCustomersWrite) {
+		logger.Warn().
+			Str("operation", "execute").
+			Str("customer_id", id).
+			Msg("Restore of another user's customer denied")
+		return nil, utils.ErrCustomerNotFound
+	}
+
+	tx, err := uc.txManager.BeginTx(ctx)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("operation", "execute").
+			Str("customer_id", id).
+			Msg("Failed to begin transaction")
+		return nil, errors.New("failed to begin transaction")
+	}
+
+	var rollbackErr error
+	defer func() {
+		if rollbackErr != nil {
+			if rErr := tx.Rollback(); rErr != nil && rErr != sql.ErrTxDone {
+				logger.Error().
+					Err(rErr).
+					Str("operation", "execute").
+					Str("customer_id", id).
+					Msg("Failed to rollback transaction")
+			}
+		}
+	}()
+
+	repo := uc.repo.WithTX(tx)
+
+	customer, findErr := repo.FindByCustomerIDIncludingDeleted(ctx, id)
+	if findErr != nil {
+		if errors.Is(findErr, sql.ErrNoRows) {
+			logger.Warn().
+				Err(findErr).
+				Str("operation", "execute").
+				Str("customer_id", id).
+				Msg("Customer not found for restore")
+			rollbackErr = utils.ErrCustomerNotFound
+			return nil, rollbackErr
+		}
+
+		logger.Error().
+			Err(findErr).
+			Stack().
+			Str("operation", "execute").
+			Str("customer_id", id).
+			Msg("Failed to find customer for restore")
+		rollbackErr = findErr
+		return nil, rollbackErr
+	}
+
+	if customer.DeletedAt != nil {
+		customer, rollbackErr = repo.RestoreCustomer(ctx, id)
+		if rollbackErr != nil {
+			logger.Error().
+				Err(rollbackErr).
+				Stack().
+				Str("operation", "execute").
+				Str("customer_id", id).
+				Msg("Failed to restore customer")
+			return nil, rollbackErr
+		}
+	}
+
+	if commitErr := tx.Commit(); commitErr != nil {
+		logger.Error().
+			Err(commitErr).
+			Stack().
+			Str("operation", "execute").
+			Str("customer_id", id).
+			Msg("Failed to commit transaction")
+		rollbackErr = commitErr
+		return nil, rollbackErr
+	}
+
+	logger.Info().
+		Str("operation", "execute").
+		Str("customer_id", id).
+		Dur("total_duration_ms", time.Since(start)).
+		Msg("Customer restored successfully")
+
+	return customer, nil
+}
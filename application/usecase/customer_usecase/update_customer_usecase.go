@@ -9,7 +9,9 @@ import (
 	"time"
 
 	"Goshop/domain/entity"
+	userentity "Goshop/domain/entity/user_entity"
 	"Goshop/domain/repository"
+	"Goshop/interfaces/utils"
 
 	"github.com/rs/zerolog"
 )
@@ -55,6 +57,15 @@ func (uc *UpdateCustomerUsecase) Execute(ctx context.Context, customer *entity.C
 		Str("customer_id", customer.ID).
 		Msg("Starting customer update process")
 
+	// Un utilisateur sans permission ":any" n'accède qu'à son propre profil
+	if !utils.CanAccessCustomer(ctx, customer.ID, userentity.PermCustomersWrite) {
+		logger.Warn().
+			Str("operation", "execute").
+			Str("customer_id", customer.ID).
+			Msg("Access to another user's customer denied")
+		return nil, utils.ErrCustomerNotFound
+	}
+
 	// Validation des données de mise à jour
 	if err := uc.validateUpdateData(ctx, customer); err != nil {
 		// Logging déjà fait dans validateUpdateData
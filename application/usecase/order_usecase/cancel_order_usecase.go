@@ -0,0 +1,244 @@
+// application/usecase/order_usecase/cancel_order_usecase.go
+package orderusecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"Goshop/application/metrics"
+	productuscase "Goshop/application/usecase/product_uscase"
+	"Goshop/domain/entity"
+	userentity "Goshop/domain/entity/user_entity"
+	"Goshop/domain/repository"
+	"Goshop/interfaces/utils"
+
+	"github.com/rs/zerolog"
+)
+
+type CancelOrderUsecase struct {
+	txManager    repository.TxManager
+	orderRepo    repository.OrderRepository
+	productRepo  repository.ProductRepository
+	productCache repository.ProductCache
+	variantRepo  repository.ProductVariantRepository
+	//logger      *setupLogging.Logger
+}
+
+func NewCancelOrderUsecase(
+	txManager repository.TxManager,
+	orderRepo repository.OrderRepository,
+	productRepo repository.ProductRepository,
+	//logger *setupLogging.Logger,
+) *CancelOrderUsecase {
+	return &CancelOrderUsecase{
+		txManager:   txManager,
+		orderRepo:   orderRepo,
+		productRepo: productRepo,
+		//logger:      logger.WithComponent("cancel_order_usecase"),
+	}
+}
+
+// WithProductCache : les produits remis en stock sont retirés du cache après
+// le commit
+func (uc *CancelOrderUsecase) WithProductCache(cache repository.ProductCache) *CancelOrderUsecase {
+	uc.productCache = cache
+	return uc
+}
+
+// WithVariants : le stock des variantes commandées est aussi restauré
+func (uc *CancelOrderUsecase) WithVariants(variants repository.ProductVariantRepository) *CancelOrderUsecase {
+	uc.variantRepo = variants
+	return uc
+}
+
+// Execute annule la commande et remet en stock chaque article, le tout dans
+// une seule transaction.
+//
+// La transition est appliquée AVANT la remise en stock : UpdateStatus est gardé
+// par le statut courant et pose un verrou sur la ligne orders. Deux annulations
+// concurrentes sont donc sérialisées et seule la première restocke.
+func (uc *CancelOrderUsecase) Execute(ctx context.Context, id, reason string) (*entity.Order, error) {
+	logger := zerolog.Ctx(ctx)
+	start := time.Now()
+
+	logger.Info().
+		Str("operation", "execute").
+		Str("order_id", id).
+		Msg("Starting order cancellation")
+
+	if id == "" {
+		logger.Warn().Msg("Empty order ID provided")
+		return nil, utils.ErrOrderNotFound
+	}
+
+	// 1. Début de la transaction
+	tx, err := uc.txManager.BeginTx(ctx)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("operation", "execute").
+			Str("order_id", id).
+			Msg("Failed to begin transaction")
+		return nil, utils.ErrTransactionBegin
+	}
+
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			logger.Error().
+				Err(rollbackErr).
+				Str("operation", "execute").
+				Str("order_id", id).
+				Msg("Failed to rollback transaction")
+		}
+	}()
+
+	orderRepo := uc.orderRepo.WithTX(tx)
+	productRepo := uc.productRepo.WithTX(tx)
+
+	// 2. Charger la commande et ses articles
+	order, err := orderRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Warn().
+				Err(err).
+				Str("order_id", id).
+				Msg("Order not found")
+			return nil, utils.ErrOrderNotFound
+		}
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("order_id", id).
+			Msg("Failed to retrieve order")
+		return nil, utils.ErrOrderCancelFail
+	}
+
+	// Seul le client propriétaire (ou orders:write:any) peut annuler
+	if !utils.CanAccessCustomer(ctx, order.CustomerID, userentity.PermOrdersWriteAny) {
+		logger.Warn().
+			Str("order_id", id).
+			Msg("Cancellation of another customer's order denied")
+		return nil, utils.ErrOrderNotFound
+	}
+
+	// 3. La commande est-elle encore annulable ?
+	from := entity.NormalizeOrderStatus(order.Status)
+	if err := entity.ValidateOrderTransition(from, entity.OrderStatusCancelled); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("order_id", id).
+			Str("current_status", from).
+			Msg("Order is not cancellable")
+		return nil, utils.ErrOrderNotCancellable
+	}
+
+	// 4. Transition gardée : verrouille la commande
+	cancelled, err := orderRepo.UpdateStatus(ctx, id, order.Status, entity.OrderStatusCancelled)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Une autre transaction a modifié la commande entre-temps
+			logger.Warn().
+				Err(err).
+				Str("order_id", id).
+				Str("expected_status", from).
+				Msg("Order status changed concurrently, cancellation aborted")
+			return nil, utils.ErrOrderNotCancellable
+		}
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("order_id", id).
+			Msg("Failed to update order status")
+		return nil, utils.ErrOrderCancelFail
+	}
+
+	// 5. Remise en stock, dans un ordre déterministe
+	productIDs, quantities := quantitiesByProduct(order.Items)
+	for _, productID := range productIDs {
+		if err := productRepo.RestoreStock(ctx, productID, quantities[productID]); err != nil {
+			logger.Error().
+				Err(err).
+				Stack().
+				Str("order_id", id).
+				Str("product_id", productID).
+				Int("quantity", quantities[productID]).
+				Msg("Failed to restore product stock")
+			return nil, utils.ErrOrderCancelFail
+		}
+	}
+
+	// Puis les variantes, une fois leurs produits verrouillés ; les articles
+	// antérieurs aux variantes n'en ont pas
+	if uc.variantRepo != nil {
+		variantRepo := uc.variantRepo.WithTX(tx)
+		variantIDs, variantQuantities := quantitiesByVariant(order.Items)
+		for _, variantID := range variantIDs {
+			if err := variantRepo.RestoreStock(ctx, variantID, variantQuantities[variantID]); err != nil {
+				logger.Error().
+					Err(err).
+					Stack().
+					Str("order_id", id).
+					Str("variant_id", variantID).
+					Int("quantity", variantQuantities[variantID]).
+					Msg("Failed to restore variant stock")
+				return nil, utils.ErrOrderCancelFail
+			}
+		}
+	}
+
+	logger.Debug().
+		Str("order_id", id).
+		Int("products_restocked", len(productIDs)).
+		Msg("Stock restored for all order items")
+
+	// 6. Historique avec le motif d'annulation
+	changedBy, ok := utils.GetUserID(ctx)
+	if !ok {
+		changedBy = "system"
+	}
+
+	history := &entity.OrderStatusHistory{
+		OrderID:    id,
+		FromStatus: from,
+		ToStatus:   entity.OrderStatusCancelled,
+		ChangedBy:  changedBy,
+		Reason:     reason,
+	}
+	if err := orderRepo.AddStatusHistory(ctx, history); err != nil {
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("order_id", id).
+			Msg("Failed to record cancellation history")
+		return nil, utils.ErrOrderCancelFail
+	}
+
+	// 7. Commit
+	if err := tx.Commit(); err != nil {
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("order_id", id).
+			Msg("Failed to commit transaction")
+		return nil, utils.ErrTransactionCommit
+	}
+
+	productuscase.InvalidateProducts(ctx, uc.productCache, productIDs...)
+
+	cancelled.Items = order.Items
+	metrics.OrdersCancelledTotal.Inc()
+	metrics.OrdersStatusTransitionsTotal.WithLabelValues(from, entity.OrderStatusCancelled).Inc()
+
+	logger.Info().
+		Str("order_id", id).
+		Str("from_status", from).
+		Str("changed_by", changedBy).
+		Str("reason", reason).
+		Dur("total_duration_ms", time.Since(start)).
+		Msg("Order cancelled successfully")
+
+	return cancelled, nil
+}
@@ -0,0 +1,119 @@
+package orderusecase_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	orderusecase "Goshop/application/usecase/order_usecase"
+	"Goshop/domain/entity"
+	"Goshop/infrastructure/postgres/customer"
+	"Goshop/infrastructure/postgres/order"
+	"Goshop/infrastructure/postgres/product"
+	txmanager "Goshop/infrastructure/postgres/tx_manager"
+	"Goshop/interfaces/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Plusieurs annulations concurrentes de la même commande : une seule doit
+// réussir, et le stock doit revenir exactement à sa valeur initiale.
+func TestCancelOrderUsecase_Integration_ConcurrentCancels(t *testing.T) {
+	productRepo := product.NewProductRepositoryInfrastructure(db)
+	customerRepo := customer.NewCustomerRepoInfrastructurePostgres(db)
+	orderRepo := order.NewOrderPostgresInfra(db)
+	orderItemRepo := order.NewOrderItemPostgresInfra(db)
+	txManager := txmanager.NewTxManagerPostgresInfra(db)
+
+	createUsecase := orderusecase.NewCreateOrderUsecase(txManager, productRepo, customerRepo, orderItemRepo, orderRepo)
+	cancelUsecase := orderusecase.NewCancelOrderUsecase(txManager, orderRepo, productRepo)
+
+	createdCustomer, err := customerRepo.Create(ctx, &entity.Customer{
+		FirstName: "Cancel",
+		LastName:  "Test",
+		Email:     fmt.Sprintf("cancel_%d@test.com", time.Now().UnixNano()),
+	})
+	require.NoError(t, err)
+
+	const initialStock = 50
+	productA := &entity.Product{Name: "Lampe", Description: "Cancel test A", PriceCents: 1000, Stock: initialStock}
+	productB := &entity.Product{Name: "Tapis", Description: "Cancel test B", PriceCents: 2500, Stock: initialStock}
+	require.NoError(t, productRepo.Create(ctx, productA))
+	require.NoError(t, productRepo.Create(ctx, productB))
+
+	// 5 commandes multi-produits
+	const ordersCount = 5
+	orderIDs := make([]string, 0, ordersCount)
+	for i := 0; i < ordersCount; i++ {
+		created, err := createUsecase.Execute(ctx, &entity.Order{
+			CustomerID: createdCustomer.ID,
+			Items: []*entity.OrderItem{
+				{ProductID: productA.ID, Quantity: 2},
+				{ProductID: productB.ID, Quantity: 3},
+			},
+		})
+		require.NoError(t, err)
+		orderIDs = append(orderIDs, created.ID)
+	}
+
+	afterOrders, err := productRepo.FindByID(ctx, productA.ID)
+	require.NoError(t, err)
+	assert.Equal(t, initialStock-2*ordersCount, afterOrders.Stock)
+
+	// Chaque commande est annulée 10 fois en parallèle
+	const attemptsPerOrder = 10
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		successes  = map[string]int{}
+		unexpected []error
+	)
+
+	for _, id := range orderIDs {
+		for i := 0; i < attemptsPerOrder; i++ {
+			wg.Add(1)
+			go func(orderID string) {
+				defer wg.Done()
+				_, err := cancelUsecase.Execute(ctx, orderID, "concurrent cancel test")
+
+				mu.Lock()
+				defer mu.Unlock()
+				switch {
+				case err == nil:
+					successes[orderID]++
+				case errors.Is(err, utils.ErrOrderNotCancellable):
+					// attendu pour les perdants
+				default:
+					unexpected = append(unexpected, err)
+				}
+			}(id)
+		}
+	}
+	wg.Wait()
+
+	assert.Empty(t, unexpected, "aucune erreur inattendue")
+	for _, id := range orderIDs {
+		assert.Equal(t, 1, successes[id], "une seule annulation doit réussir pour %s", id)
+
+		o, err := orderRepo.FindByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, entity.OrderStatusCancelled, o.Status)
+
+		history, err := orderRepo.FindStatusHistory(ctx, id)
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, "concurrent cancel test", history[0].Reason)
+	}
+
+	// Le stock est exactement revenu à sa valeur initiale
+	finalA, err := productRepo.FindByID(ctx, productA.ID)
+	require.NoError(t, err)
+	finalB, err := productRepo.FindByID(ctx, productB.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, initialStock, finalA.Stock)
+	assert.Equal(t, initialStock, finalB.Stock)
+}
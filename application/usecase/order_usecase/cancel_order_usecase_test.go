@@ -0,0 +1,213 @@
+package orderusecase_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	orderusecase "Goshop/application/usecase/order_usecase"
+	"Goshop/domain/entity"
+	"Goshop/interfaces/utils"
+	mockrepo "Goshop/mocks/repository"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCancelOrderUsecase_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+	mockTx := mockrepo.NewMockTx(ctrl)
+	mockOrderRepo := mockrepo.NewMockOrderRepository(ctrl)
+	mockOrderRepoTx := mockrepo.NewMockOrderRepository(ctrl)
+	mockProductRepo := mockrepo.NewMockProductRepository(ctrl)
+	mockProductRepoTx := mockrepo.NewMockProductRepository(ctrl)
+
+	existing := &entity.Order{
+		ID:     "order-1",
+		Status: entity.OrderStatusPaid,
+		Items: []*entity.OrderItem{
+			{ProductID: "prod-b", Quantity: 1},
+			{ProductID: "prod-a", Quantity: 2},
+			{ProductID: "prod-b", Quantity: 3},
+		},
+	}
+	cancelled := &entity.Order{ID: "order-1", Status: entity.OrderStatusCancelled}
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockOrderRepo.EXPECT().WithTX(mockTx).Return(mockOrderRepoTx)
+	mockProductRepo.EXPECT().WithTX(mockTx).Return(mockProductRepoTx)
+	mockOrderRepoTx.EXPECT().FindByID(gomock.Any(), "order-1").Return(existing, nil)
+	mockOrderRepoTx.EXPECT().UpdateStatus(gomock.Any(), "order-1", entity.OrderStatusPaid, entity.OrderStatusCancelled).
+		Return(cancelled, nil)
+
+	// Quantités regroupées par produit, restockées dans l'ordre des IDs
+	gomock.InOrder(
+		mockProductRepoTx.EXPECT().RestoreStock(gomock.Any(), "prod-a", 2).Return(nil),
+		mockProductRepoTx.EXPECT().RestoreStock(gomock.Any(), "prod-b", 4).Return(nil),
+	)
+
+	mockOrderRepoTx.EXPECT().AddStatusHistory(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, h *entity.OrderStatusHistory) error {
+			assert.Equal(t, entity.OrderStatusPaid, h.FromStatus)
+			assert.Equal(t, entity.OrderStatusCancelled, h.ToStatus)
+			assert.Equal(t, "customer changed their mind", h.Reason)
+			return nil
+		})
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Rollback().Return(sql.ErrTxDone).AnyTimes()
+
+	uc := orderusecase.NewCancelOrderUsecase(mockTxManager, mockOrderRepo, mockProductRepo)
+
+	result, err := uc.Execute(context.Background(), "order-1", "customer changed their mind")
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.OrderStatusCancelled, result.Status)
+	assert.Len(t, result.Items, 3)
+}
+
+func TestCancelOrderUsecase_RestoresVariantStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+	mockTx := mockrepo.NewMockTx(ctrl)
+	mockOrderRepo := mockrepo.NewMockOrderRepository(ctrl)
+	mockOrderRepoTx := mockrepo.NewMockOrderRepository(ctrl)
+	mockProductRepo := mockrepo.NewMockProductRepository(ctrl)
+	mockProductRepoTx := mockrepo.NewMockProductRepository(ctrl)
+	mockVariantRepo := mockrepo.NewMockProductVariantRepository(ctrl)
+	mockVariantRepoTx := mockrepo.NewMockProductVariantRepository(ctrl)
+
+	existing := &entity.Order{
+		ID:     "order-1",
+		Status: entity.OrderStatusPending,
+		Items: []*entity.OrderItem{
+			{ProductID: "prod-a", VariantID: "var-a-l", Quantity: 1},
+			{ProductID: "prod-a", VariantID: "var-a-default", Quantity: 2},
+			{ProductID: "prod-b", Quantity: 1}, // article antérieur aux variantes
+		},
+	}
+	cancelled := &entity.Order{ID: "order-1", Status: entity.OrderStatusCancelled}
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockOrderRepo.EXPECT().WithTX(mockTx).Return(mockOrderRepoTx)
+	mockProductRepo.EXPECT().WithTX(mockTx).Return(mockProductRepoTx)
+	mockVariantRepo.EXPECT().WithTX(mockTx).Return(mockVariantRepoTx).AnyTimes()
+	mockOrderRepoTx.EXPECT().FindByID(gomock.Any(), "order-1").Return(existing, nil)
+	mockOrderRepoTx.EXPECT().UpdateStatus(gomock.Any(), "order-1", entity.OrderStatusPending, entity.OrderStatusCancelled).
+		Return(cancelled, nil)
+
+	// Produits d'abord (verrous), puis leurs variantes
+	gomock.InOrder(
+		mockProductRepoTx.EXPECT().RestoreStock(gomock.Any(), "prod-a", 3).Return(nil),
+		mockProductRepoTx.EXPECT().RestoreStock(gomock.Any(), "prod-b", 1).Return(nil),
+		mockVariantRepoTx.EXPECT().RestoreStock(gomock.Any(), "var-a-default", 2).Return(nil),
+		mockVariantRepoTx.EXPECT().RestoreStock(gomock.Any(), "var-a-l", 1).Return(nil),
+	)
+
+	mockOrderRepoTx.EXPECT().AddStatusHistory(gomock.Any(), gomock.Any()).Return(nil)
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Rollback().Return(sql.ErrTxDone).AnyTimes()
+
+	uc := orderusecase.NewCancelOrderUsecase(mockTxManager, mockOrderRepo, mockProductRepo).
+		WithVariants(mockVariantRepo)
+
+	_, err := uc.Execute(context.Background(), "order-1", "")
+
+	assert.NoError(t, err)
+}
+
+func TestCancelOrderUsecase_NotCancellable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+	mockTx := mockrepo.NewMockTx(ctrl)
+	mockOrderRepo := mockrepo.NewMockOrderRepository(ctrl)
+	mockOrderRepoTx := mockrepo.NewMockOrderRepository(ctrl)
+	mockProductRepo := mockrepo.NewMockProductRepository(ctrl)
+	mockProductRepoTx := mockrepo.NewMockProductRepository(ctrl)
+
+	shipped := &entity.Order{ID: "order-1", Status: entity.OrderStatusShipped}
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockOrderRepo.EXPECT().WithTX(mockTx).Return(mockOrderRepoTx)
+	mockProductRepo.EXPECT().WithTX(mockTx).Return(mockProductRepoTx)
+	mockOrderRepoTx.EXPECT().FindByID(gomock.Any(), "order-1").Return(shipped, nil)
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	uc := orderusecase.NewCancelOrderUsecase(mockTxManager, mockOrderRepo, mockProductRepo)
+
+	result, err := uc.Execute(context.Background(), "order-1", "")
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, utils.ErrOrderNotCancellable)
+}
+
+func TestCancelOrderUsecase_OtherCustomerDenied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+	mockTx := mockrepo.NewMockTx(ctrl)
+	mockOrderRepo := mockrepo.NewMockOrderRepository(ctrl)
+	mockOrderRepoTx := mockrepo.NewMockOrderRepository(ctrl)
+	mockProductRepo := mockrepo.NewMockProductRepository(ctrl)
+	mockProductRepoTx := mockrepo.NewMockProductRepository(ctrl)
+
+	other := &entity.Order{ID: "order-1", CustomerID: "cust-2", Status: entity.OrderStatusPending}
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockOrderRepo.EXPECT().WithTX(mockTx).Return(mockOrderRepoTx)
+	mockProductRepo.EXPECT().WithTX(mockTx).Return(mockProductRepoTx)
+	mockOrderRepoTx.EXPECT().FindByID(gomock.Any(), "order-1").Return(other, nil)
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	uc := orderusecase.NewCancelOrderUsecase(mockTxManager, mockOrderRepo, mockProductRepo)
+
+	// Client "cust-1" sans orders:write:any : la commande d'un autre reste invisible
+	ctx := utils.WithCustomerID(utils.WithUser(context.Background(), "user-1", "customer"), "cust-1")
+	result, err := uc.Execute(ctx, "order-1", "")
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, utils.ErrOrderNotFound)
+}
+
+func TestCancelOrderUsecase_LostRace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+	mockTx := mockrepo.NewMockTx(ctrl)
+	mockOrderRepo := mockrepo.NewMockOrderRepository(ctrl)
+	mockOrderRepoTx := mockrepo.NewMockOrderRepository(ctrl)
+	mockProductRepo := mockrepo.NewMockProductRepository(ctrl)
+	mockProductRepoTx := mockrepo.NewMockProductRepository(ctrl)
+
+	pending := &entity.Order{
+		ID:     "order-1",
+		Status: entity.OrderStatusPending,
+		Items:  []*entity.OrderItem{{ProductID: "prod-a", Quantity: 1}},
+	}
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockOrderRepo.EXPECT().WithTX(mockTx).Return(mockOrderRepoTx)
+	mockProductRepo.EXPECT().WithTX(mockTx).Return(mockProductRepoTx)
+	mockOrderRepoTx.EXPECT().FindByID(gomock.Any(), "order-1").Return(pending, nil)
+	// Une annulation concurrente est passée avant nous : aucune ligne mise à jour
+	mockOrderRepoTx.EXPECT().UpdateStatus(gomock.Any(), "order-1", entity.OrderStatusPending, entity.OrderStatusCancelled).
+		Return(nil, fmt.Errorf("order order-1 not in status PENDING: %w", sql.ErrNoRows))
+	// Pas de RestoreStock attendu : le stock ne doit pas être rendu deux fois
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	uc := orderusecase.NewCancelOrderUsecase(mockTxManager, mockOrderRepo, mockProductRepo)
+
+	result, err := uc.Execute(context.Background(), "order-1", "")
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, utils.ErrOrderNotCancellable)
+}
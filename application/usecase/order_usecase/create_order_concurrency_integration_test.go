@@ -0,0 +1,152 @@
+package orderusecase_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	orderusecase "Goshop/application/usecase/order_usecase"
+	"Goshop/domain/entity"
+	"Goshop/infrastructure/postgres/customer"
+	"Goshop/infrastructure/postgres/order"
+	"Goshop/infrastructure/postgres/product"
+	txmanager "Goshop/infrastructure/postgres/tx_manager"
+	"Goshop/interfaces/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newConcurrencyFixture(t *testing.T) (*orderusecase.CreateOrderUsecase, string) {
+	t.Helper()
+
+	productRepo := product.NewProductRepositoryInfrastructure(db)
+	customerRepo := customer.NewCustomerRepoInfrastructurePostgres(db)
+	orderRepo := order.NewOrderPostgresInfra(db)
+	orderItemRepo := order.NewOrderItemPostgresInfra(db)
+	txManager := txmanager.NewTxManagerPostgresInfra(db)
+
+	createdCustomer, err := customerRepo.Create(ctx, &entity.Customer{
+		FirstName: "Concurrency",
+		LastName:  "Test",
+		Email:     fmt.Sprintf("concurrency_%d@test.com", time.Now().UnixNano()),
+	})
+	require.NoError(t, err)
+
+	return orderusecase.NewCreateOrderUsecase(txManager, productRepo, customerRepo, orderItemRepo, orderRepo), createdCustomer.ID
+}
+
+// Des centaines de commandes parallèles sur un seul produit : exactement
+// `initialStock` commandes passent, le stock tombe à zéro et jamais en dessous.
+func TestCreateOrderUsecase_Integration_NoOversell(t *testing.T) {
+	usecase, customerID := newConcurrencyFixture(t)
+	productRepo := product.NewProductRepositoryInfrastructure(db)
+
+	const (
+		initialStock = 100
+		attempts     = 300
+	)
+	p := &entity.Product{Name: "Console", Description: "Oversell test", PriceCents: 30000, Stock: initialStock}
+	require.NoError(t, productRepo.Create(ctx, p))
+
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		successes  int
+		rejected   int
+		unexpected []error
+	)
+
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := usecase.Execute(ctx, &entity.Order{
+				CustomerID: customerID,
+				Items:      []*entity.OrderItem{{ProductID: p.ID, Quantity: 1}},
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				successes++
+			case errors.Is(err, utils.ErrOrderInsufficientStock):
+				rejected++
+			default:
+				unexpected = append(unexpected, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Empty(t, unexpected, "aucune erreur inattendue")
+	assert.Equal(t, initialStock, successes, "autant de commandes que d'unités en stock")
+	assert.Equal(t, attempts-initialStock, rejected)
+
+	final, err := productRepo.FindByID(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, final.Stock, "le stock ne doit ni dériver ni passer sous zéro")
+
+	// Les unités vendues correspondent exactement aux lignes de commande
+	var sold int
+	err = db.QueryRowContext(ctx, `SELECT COALESCE(SUM(quantity), 0) FROM order_items WHERE product_id = $1`, p.ID).Scan(&sold)
+	require.NoError(t, err)
+	assert.Equal(t, initialStock, sold)
+}
+
+// Commandes multi-produits dont les articles arrivent dans des ordres opposés :
+// le verrouillage trié par ID évite les deadlocks et le stock reste exact.
+func TestCreateOrderUsecase_Integration_NoDeadlockOnMultiItemOrders(t *testing.T) {
+	usecase, customerID := newConcurrencyFixture(t)
+	productRepo := product.NewProductRepositoryInfrastructure(db)
+
+	const (
+		initialStock = 1000
+		attempts     = 200
+	)
+	productA := &entity.Product{Name: "Clavier", Description: "Deadlock test A", PriceCents: 5000, Stock: initialStock}
+	productB := &entity.Product{Name: "Souris", Description: "Deadlock test B", PriceCents: 2000, Stock: initialStock}
+	require.NoError(t, productRepo.Create(ctx, productA))
+	require.NoError(t, productRepo.Create(ctx, productB))
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+
+	for i := 0; i < attempts; i++ {
+		items := []*entity.OrderItem{
+			{ProductID: productA.ID, Quantity: 2},
+			{ProductID: productB.ID, Quantity: 1},
+		}
+		if i%2 == 1 {
+			items[0], items[1] = items[1], items[0]
+		}
+
+		wg.Add(1)
+		go func(items []*entity.OrderItem) {
+			defer wg.Done()
+			_, err := usecase.Execute(ctx, &entity.Order{CustomerID: customerID, Items: items})
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(items)
+	}
+	wg.Wait()
+
+	assert.Empty(t, errs, "aucune commande ne doit échouer (deadlock ou autre)")
+
+	finalA, err := productRepo.FindByID(ctx, productA.ID)
+	require.NoError(t, err)
+	finalB, err := productRepo.FindByID(ctx, productB.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, initialStock-2*attempts, finalA.Stock)
+	assert.Equal(t, initialStock-attempts, finalB.Stock)
+}
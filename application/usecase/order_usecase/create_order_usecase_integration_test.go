@@ -5,9 +5,11 @@ import (
 	"Goshop/domain/entity"
 	"Goshop/infrastructure/postgres"
 	"Goshop/infrastructure/postgres/customer"
+	"Goshop/infrastructure/postgres/migrate"
 	"Goshop/infrastructure/postgres/order"
 	"Goshop/infrastructure/postgres/product"
 	txmanager "Goshop/infrastructure/postgres/tx_manager"
+	"Goshop/migrations"
 	"context"
 	"database/sql"
 	"fmt"
@@ -45,6 +47,14 @@ func setupTestDB() *sql.DB {
 		log.Fatalf("erreur de connexion la base de test : %v", err)
 	}
 
+	migrator, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		log.Fatalf("lecture des migrations : %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		log.Fatalf("application des migrations : %v", err)
+	}
+
 	log.Println("âœ… Connexion PostgreSQL rÃ©ussie")
 	return db
 }
@@ -2,11 +2,15 @@
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
 	"testing"
 
 	orderusecase "Goshop/application/usecase/order_usecase"
 	"Goshop/domain/entity"
+	"Goshop/domain/repository"
+	"Goshop/interfaces/utils"
 	mockrepo "Goshop/mocks/repository"
 
 	"github.com/stretchr/testify/assert"
@@ -70,13 +74,10 @@ func TestCreateOrderUsecase_Success(t *testing.T) {
 	mockCustomerRepoTx.EXPECT().FindByCustomerID(gomock.Any(), "cust-1").
 		Return(customer, nil).Times(1)
 
-	// Product found
-	mockProductRepoTx.EXPECT().FindByID(gomock.Any(), "prod-1").
+	// Stock reserved
+	mockProductRepoTx.EXPECT().ReserveStock(gomock.Any(), "prod-1", 2).
 		Return(product, nil).Times(1)
 
-	// Stock update OK
-	mockProductRepoTx.EXPECT().Update(gomock.Any(), gomock.Any()).Return(product, nil).Times(1)
-
 	// Create order OK
 	mockOrderRepoTx.EXPECT().Create(gomock.Any(), gomock.Any()).
 		DoAndReturn(func(ctx context.Context, o *entity.Order) (*entity.Order, error) {
@@ -108,6 +109,249 @@ func TestCreateOrderUsecase_Success(t *testing.T) {
 	assert.Equal(t, "order-123", result.ID)
 }
 
+// -----------------------------
+//
+//	RESERVATION ORDER
+//
+// -----------------------------
+func TestCreateOrderUsecase_ReservesStockInProductOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+	mockTx := mockrepo.NewMockTx(ctrl)
+
+	mockProductRepo := mockrepo.NewMockProductRepository(ctrl)
+	mockCustomerRepo := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+	mockOrderRepo := mockrepo.NewMockOrderRepository(ctrl)
+	mockOrderItemRepo := mockrepo.NewMockOrderItemRepository(ctrl)
+
+	mockProductRepoTx := mockrepo.NewMockProductRepository(ctrl)
+	mockCustomerRepoTx := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+	mockOrderRepoTx := mockrepo.NewMockOrderRepository(ctrl)
+	mockOrderItemRepoTx := mockrepo.NewMockOrderItemRepository(ctrl)
+
+	order := &entity.Order{
+		CustomerID: "cust-1",
+		Items: []*entity.OrderItem{
+			{ProductID: "prod-b", Quantity: 1},
+			{ProductID: "prod-a", Quantity: 2},
+			{ProductID: "prod-b", Quantity: 3},
+		},
+	}
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockProductRepo.EXPECT().WithTX(mockTx).Return(mockProductRepoTx)
+	mockCustomerRepo.EXPECT().WithTX(mockTx).Return(mockCustomerRepoTx)
+	mockOrderRepo.EXPECT().WithTX(mockTx).Return(mockOrderRepoTx)
+	mockOrderItemRepo.EXPECT().WithTX(mockTx).Return(mockOrderItemRepoTx)
+
+	mockCustomerRepoTx.EXPECT().FindByCustomerID(gomock.Any(), "cust-1").Return(&entity.Customer{ID: "cust-1"}, nil)
+
+	// Une réservation par produit, quantités regroupées, dans l'ordre des IDs
+	gomock.InOrder(
+		mockProductRepoTx.EXPECT().ReserveStock(gomock.Any(), "prod-a", 2).
+			Return(&entity.Product{ID: "prod-a", PriceCents: 1000}, nil),
+		mockProductRepoTx.EXPECT().ReserveStock(gomock.Any(), "prod-b", 4).
+			Return(&entity.Product{ID: "prod-b", PriceCents: 500}, nil),
+	)
+
+	mockOrderRepoTx.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, o *entity.Order) (*entity.Order, error) {
+			assert.Equal(t, int64(2*1000+4*500), o.TotalCents)
+			o.ID = "order-1"
+			return o, nil
+		})
+	mockOrderItemRepoTx.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&entity.OrderItem{}, nil).Times(3)
+
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Rollback().Return(sql.ErrTxDone).AnyTimes()
+
+	uc := orderusecase.NewCreateOrderUsecase(mockTxManager, mockProductRepo, mockCustomerRepo, mockOrderItemRepo, mockOrderRepo)
+
+	result, err := uc.Execute(context.Background(), order)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(500), result.Items[0].PriceCents)
+	assert.Equal(t, int64(2000), result.Items[1].SubTotal_Cents)
+	assert.Equal(t, int64(1500), result.Items[2].SubTotal_Cents)
+}
+
+// -----------------------------
+//
+//	PRODUCT CACHE
+//
+// -----------------------------
+func TestCreateOrderUsecase_InvalidatesProductCacheAfterCommit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+	mockTx := mockrepo.NewMockTx(ctrl)
+	mockProductRepo := mockrepo.NewMockProductRepository(ctrl)
+	mockCustomerRepo := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+	mockOrderRepo := mockrepo.NewMockOrderRepository(ctrl)
+	mockOrderItemRepo := mockrepo.NewMockOrderItemRepository(ctrl)
+	mockProductRepoTx := mockrepo.NewMockProductRepository(ctrl)
+	mockCustomerRepoTx := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+	mockOrderRepoTx := mockrepo.NewMockOrderRepository(ctrl)
+	mockOrderItemRepoTx := mockrepo.NewMockOrderItemRepository(ctrl)
+	mockCache := mockrepo.NewMockProductCache(ctrl)
+
+	order := &entity.Order{
+		CustomerID: "cust-1",
+		Items: []*entity.OrderItem{
+			{ProductID: "prod-b", Quantity: 1},
+			{ProductID: "prod-a", Quantity: 2},
+		},
+	}
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockProductRepo.EXPECT().WithTX(mockTx).Return(mockProductRepoTx)
+	mockCustomerRepo.EXPECT().WithTX(mockTx).Return(mockCustomerRepoTx)
+	mockOrderRepo.EXPECT().WithTX(mockTx).Return(mockOrderRepoTx)
+	mockOrderItemRepo.EXPECT().WithTX(mockTx).Return(mockOrderItemRepoTx)
+
+	mockCustomerRepoTx.EXPECT().FindByCustomerID(gomock.Any(), "cust-1").Return(&entity.Customer{ID: "cust-1"}, nil)
+	mockProductRepoTx.EXPECT().ReserveStock(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, id string, _ int) (*entity.Product, error) {
+			return &entity.Product{ID: id, PriceCents: 1000}, nil
+		}).Times(2)
+	mockOrderRepoTx.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&entity.Order{ID: "order-1", CustomerID: "cust-1"}, nil)
+	mockOrderItemRepoTx.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&entity.OrderItem{}, nil).Times(2)
+	mockTx.EXPECT().Rollback().Return(sql.ErrTxDone).AnyTimes()
+
+	// Le stock en cache n'est invalidé qu'une fois la réservation committée
+	gomock.InOrder(
+		mockTx.EXPECT().Commit().Return(nil),
+		mockCache.EXPECT().Delete(gomock.Any(), "prod-a").Return(nil),
+		mockCache.EXPECT().Delete(gomock.Any(), "prod-b").Return(nil),
+	)
+
+	uc := orderusecase.NewCreateOrderUsecase(mockTxManager, mockProductRepo, mockCustomerRepo, mockOrderItemRepo, mockOrderRepo).
+		WithProductCache(mockCache)
+
+	_, err := uc.Execute(context.Background(), order)
+
+	assert.NoError(t, err)
+}
+
+// -----------------------------
+//
+//	VARIANTS
+//
+// -----------------------------
+func TestCreateOrderUsecase_ReservesVariantStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+	mockTx := mockrepo.NewMockTx(ctrl)
+	mockProductRepo := mockrepo.NewMockProductRepository(ctrl)
+	mockCustomerRepo := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+	mockOrderRepo := mockrepo.NewMockOrderRepository(ctrl)
+	mockOrderItemRepo := mockrepo.NewMockOrderItemRepository(ctrl)
+	mockVariantRepo := mockrepo.NewMockProductVariantRepository(ctrl)
+	mockProductRepoTx := mockrepo.NewMockProductRepository(ctrl)
+	mockCustomerRepoTx := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+	mockOrderRepoTx := mockrepo.NewMockOrderRepository(ctrl)
+	mockOrderItemRepoTx := mockrepo.NewMockOrderItemRepository(ctrl)
+	mockVariantRepoTx := mockrepo.NewMockProductVariantRepository(ctrl)
+
+	override := int64(2500)
+	order := &entity.Order{
+		CustomerID: "cust-1",
+		Items: []*entity.OrderItem{
+			{ProductID: "prod-a", VariantID: "var-a-l", Quantity: 1},
+			{ProductID: "prod-a", Quantity: 2}, // variante par défaut
+			{ProductID: "prod-a", VariantID: "var-a-l", Quantity: 1},
+		},
+	}
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockProductRepo.EXPECT().WithTX(mockTx).Return(mockProductRepoTx)
+	mockCustomerRepo.EXPECT().WithTX(mockTx).Return(mockCustomerRepoTx)
+	mockOrderRepo.EXPECT().WithTX(mockTx).Return(mockOrderRepoTx)
+	mockOrderItemRepo.EXPECT().WithTX(mockTx).Return(mockOrderItemRepoTx)
+	mockVariantRepo.EXPECT().WithTX(mockTx).Return(mockVariantRepoTx)
+
+	mockCustomerRepoTx.EXPECT().FindByCustomerID(gomock.Any(), "cust-1").Return(&entity.Customer{ID: "cust-1"}, nil)
+
+	// Le produit est verrouillé avant ses variantes, réservées dans l'ordre des IDs
+	gomock.InOrder(
+		mockProductRepoTx.EXPECT().ReserveStock(gomock.Any(), "prod-a", 4).
+			Return(&entity.Product{ID: "prod-a", PriceCents: 2000}, nil),
+		mockVariantRepoTx.EXPECT().FindDefault(gomock.Any(), "prod-a").
+			Return(&entity.ProductVariant{ID: "var-a-default", ProductID: "prod-a", IsDefault: true}, nil),
+		mockVariantRepoTx.EXPECT().ReserveStock(gomock.Any(), "prod-a", "var-a-default", 2).
+			Return(&entity.ProductVariant{ID: "var-a-default", ProductID: "prod-a"}, nil),
+		mockVariantRepoTx.EXPECT().ReserveStock(gomock.Any(), "prod-a", "var-a-l", 2).
+			Return(&entity.ProductVariant{ID: "var-a-l", ProductID: "prod-a", PriceCents: &override}, nil),
+	)
+
+	mockOrderRepoTx.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, o *entity.Order) (*entity.Order, error) {
+			assert.Equal(t, int64(2*2500+2*2000), o.TotalCents)
+			o.ID = "order-1"
+			return o, nil
+		})
+	mockOrderItemRepoTx.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, item *entity.OrderItem) (*entity.OrderItem, error) {
+			assert.NotEmpty(t, item.VariantID)
+			return item, nil
+		}).Times(3)
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Rollback().Return(sql.ErrTxDone).AnyTimes()
+
+	uc := orderusecase.NewCreateOrderUsecase(mockTxManager, mockProductRepo, mockCustomerRepo, mockOrderItemRepo, mockOrderRepo).
+		WithVariants(mockVariantRepo)
+
+	result, err := uc.Execute(context.Background(), order)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2500), result.Items[0].PriceCents)
+	assert.Equal(t, "var-a-default", result.Items[1].VariantID)
+	assert.Equal(t, int64(4000), result.Items[1].SubTotal_Cents)
+}
+
+func TestCreateOrderUsecase_VariantOfAnotherProduct(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+	mockTx := mockrepo.NewMockTx(ctrl)
+	mockProductRepo := mockrepo.NewMockProductRepository(ctrl)
+	mockCustomerRepo := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+	mockOrderRepo := mockrepo.NewMockOrderRepository(ctrl)
+	mockOrderItemRepo := mockrepo.NewMockOrderItemRepository(ctrl)
+	mockVariantRepo := mockrepo.NewMockProductVariantRepository(ctrl)
+	mockProductRepoTx := mockrepo.NewMockProductRepository(ctrl)
+	mockCustomerRepoTx := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+	mockVariantRepoTx := mockrepo.NewMockProductVariantRepository(ctrl)
+
+	order := &entity.Order{
+		CustomerID: "cust-1",
+		Items:      []*entity.OrderItem{{ProductID: "prod-a", VariantID: "var-b-m", Quantity: 1}},
+	}
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockProductRepo.EXPECT().WithTX(mockTx).Return(mockProductRepoTx)
+	mockCustomerRepo.EXPECT().WithTX(mockTx).Return(mockCustomerRepoTx)
+	mockOrderRepo.EXPECT().WithTX(mockTx).Return(mockrepo.NewMockOrderRepository(ctrl))
+	mockOrderItemRepo.EXPECT().WithTX(mockTx).Return(mockrepo.NewMockOrderItemRepository(ctrl))
+	mockVariantRepo.EXPECT().WithTX(mockTx).Return(mockVariantRepoTx)
+
+	mockCustomerRepoTx.EXPECT().FindByCustomerID(gomock.Any(), "cust-1").Return(&entity.Customer{ID: "cust-1"}, nil)
+	mockProductRepoTx.EXPECT().ReserveStock(gomock.Any(), "prod-a", 1).Return(&entity.Product{ID: "prod-a", PriceCents: 2000}, nil)
+	mockVariantRepoTx.EXPECT().ReserveStock(gomock.Any(), "prod-a", "var-b-m", 1).
+		Return(nil, fmt.Errorf("variant var-b-m: %w", repository.ErrVariantNotFound))
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	uc := orderusecase.NewCreateOrderUsecase(mockTxManager, mockProductRepo, mockCustomerRepo, mockOrderItemRepo, mockOrderRepo).
+		WithVariants(mockVariantRepo)
+
+	_, err := uc.Execute(context.Background(), order)
+
+	assert.ErrorIs(t, err, utils.ErrOrderInvalidVariant)
+}
+
 // -----------------------------
 //
 //	CLIENT NOT FOUND
@@ -200,7 +444,8 @@ func TestCreateOrderUsecase_ProductNotFound(t *testing.T) {
 
 	mockCustomerRepoTx.EXPECT().FindByCustomerID(gomock.Any(), "cust").Return(&entity.Customer{}, nil)
 
-	mockProductRepoTx.EXPECT().FindByID(gomock.Any(), "p-404").Return(nil, errors.New("not found"))
+	mockProductRepoTx.EXPECT().ReserveStock(gomock.Any(), "p-404", 1).
+		Return(nil, fmt.Errorf("product p-404 not found: %w", sql.ErrNoRows))
 
 	mockTx.EXPECT().Rollback().Return(nil).AnyTimes()
 
@@ -215,8 +460,7 @@ func TestCreateOrderUsecase_ProductNotFound(t *testing.T) {
 
 	_, err := uc.Execute(context.Background(), order)
 
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
+	assert.ErrorIs(t, err, utils.ErrProductNotFound)
 }
 
 // -----------------------------
@@ -243,11 +487,6 @@ func TestCreateOrderUsecase_InsufficientStock(t *testing.T) {
 		},
 	}
 
-	product := &entity.Product{
-		ID:    "prod-1",
-		Stock: 1,
-	}
-
 	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
 
 	mockProductRepoTx := mockrepo.NewMockProductRepository(ctrl)
@@ -262,7 +501,8 @@ func TestCreateOrderUsecase_InsufficientStock(t *testing.T) {
 
 	mockCustomerRepoTx.EXPECT().FindByCustomerID(gomock.Any(), "cust").Return(&entity.Customer{}, nil)
 
-	mockProductRepoTx.EXPECT().FindByID(gomock.Any(), "prod-1").Return(product, nil)
+	mockProductRepoTx.EXPECT().ReserveStock(gomock.Any(), "prod-1", 99).
+		Return(nil, fmt.Errorf("product prod-1 has 1 in stock, 99 requested: %w", repository.ErrInsufficientStock))
 
 	mockTx.EXPECT().Rollback().Return(nil).AnyTimes()
 
@@ -278,16 +518,15 @@ func TestCreateOrderUsecase_InsufficientStock(t *testing.T) {
 
 	_, err := uc.Execute(context.Background(), order)
 
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "not enough stock")
+	assert.ErrorIs(t, err, utils.ErrOrderInsufficientStock)
 }
 
 // -----------------------------
 //
-//	ERROR UPDATE PRODUCT
+//	ERROR RESERVE STOCK
 //
 // -----------------------------
-func TestCreateOrderUsecase_UpdateStockError(t *testing.T) {
+func TestCreateOrderUsecase_ReserveStockError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -306,12 +545,6 @@ func TestCreateOrderUsecase_UpdateStockError(t *testing.T) {
 		},
 	}
 
-	product := &entity.Product{
-		ID:         "prod-1",
-		PriceCents: 20000,
-		Stock:      5,
-	}
-
 	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
 
 	mockProductRepoTx := mockrepo.NewMockProductRepository(ctrl)
@@ -325,9 +558,7 @@ func TestCreateOrderUsecase_UpdateStockError(t *testing.T) {
 	mockOrderItemRepo.EXPECT().WithTX(mockTx).Return(mockOrderItemRepoTx)
 
 	mockCustomerRepoTx.EXPECT().FindByCustomerID(gomock.Any(), "cust").Return(&entity.Customer{}, nil)
-	mockProductRepoTx.EXPECT().FindByID(gomock.Any(), "prod-1").Return(product, nil)
-
-	mockProductRepoTx.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil, errors.New("update error"))
+	mockProductRepoTx.EXPECT().ReserveStock(gomock.Any(), "prod-1", 2).Return(nil, errors.New("update error"))
 
 	mockTx.EXPECT().Rollback().Return(nil).AnyTimes()
 
@@ -344,7 +575,7 @@ func TestCreateOrderUsecase_UpdateStockError(t *testing.T) {
 	_, err := uc.Execute(context.Background(), order)
 
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "failed to update stock")
+	assert.Contains(t, err.Error(), "failed to reserve stock")
 }
 
 // -----------------------------
@@ -392,11 +623,8 @@ func TestCreateOrderUsecase_CreateOrderError(t *testing.T) {
 	// Customer OK
 	mockCustomerRepoTx.EXPECT().FindByCustomerID(gomock.Any(), "cust").Return(&entity.Customer{}, nil)
 
-	// Product OK
-	mockProductRepoTx.EXPECT().FindByID(gomock.Any(), "prod-1").Return(product, nil)
-
-	// Update stock OK
-	mockProductRepoTx.EXPECT().Update(gomock.Any(), gomock.Any()).Return(product, nil)
+	// Stock reserved OK
+	mockProductRepoTx.EXPECT().ReserveStock(gomock.Any(), "prod-1", gomock.Any()).Return(product, nil)
 
 	// âŒ CREATE ORDER FAILS
 	mockOrderRepoTx.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, errors.New("order creation failed"))
@@ -463,8 +691,7 @@ func TestCreateOrderUsecase_OrderItemError(t *testing.T) {
 	mockOrderItemRepo.EXPECT().WithTX(mockTx).Return(mockOrderItemRepoTx)
 
 	mockCustomerRepoTx.EXPECT().FindByCustomerID(gomock.Any(), "cust").Return(&entity.Customer{}, nil)
-	mockProductRepoTx.EXPECT().FindByID(gomock.Any(), "prod-1").Return(product, nil)
-	mockProductRepoTx.EXPECT().Update(gomock.Any(), gomock.Any()).Return(product, nil)
+	mockProductRepoTx.EXPECT().ReserveStock(gomock.Any(), "prod-1", gomock.Any()).Return(product, nil)
 
 	mockOrderRepoTx.EXPECT().Create(gomock.Any(), gomock.Any()).Return(createdOrder, nil)
 
@@ -565,8 +792,7 @@ func TestCreateOrderUsecase_CommitError(t *testing.T) {
 	mockOrderItemRepo.EXPECT().WithTX(mockTx).Return(mockOrderItemRepoTx)
 
 	mockCustomerRepoTx.EXPECT().FindByCustomerID(gomock.Any(), "cust").Return(&entity.Customer{}, nil)
-	mockProductRepoTx.EXPECT().FindByID(gomock.Any(), "prod-1").Return(product, nil)
-	mockProductRepoTx.EXPECT().Update(gomock.Any(), gomock.Any()).Return(product, nil)
+	mockProductRepoTx.EXPECT().ReserveStock(gomock.Any(), "prod-1", gomock.Any()).Return(product, nil)
 	mockOrderRepoTx.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&entity.Order{ID: "o1"}, nil)
 	mockOrderItemRepoTx.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&entity.OrderItem{}, nil)
 
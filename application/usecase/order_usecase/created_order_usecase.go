@@ -10,8 +10,11 @@ import (
 	"time"
 
 	"Goshop/application/metrics"
+	productuscase "Goshop/application/usecase/product_uscase"
 	"Goshop/domain/entity"
+	userentity "Goshop/domain/entity/user_entity"
 	"Goshop/domain/repository"
+	"Goshop/interfaces/utils"
 
 	"github.com/rs/zerolog"
 )
@@ -22,6 +25,8 @@ type CreateOrderUsecase struct {
 	customerRepo  repository.CustomerRepositoryInterface
 	orderItemRepo repository.OrderItemRepository
 	orderRepo     repository.OrderRepository
+	productCache  repository.ProductCache
+	variantRepo   repository.ProductVariantRepository
 	//logger        *setupLogging.Logger
 }
 
@@ -43,6 +48,20 @@ func NewCreateOrderUsecase(
 	}
 }
 
+// WithProductCache : les produits dont le stock est réservé sont retirés du
+// cache après le commit
+func (ouc *CreateOrderUsecase) WithProductCache(cache repository.ProductCache) *CreateOrderUsecase {
+	ouc.productCache = cache
+	return ouc
+}
+
+// WithVariants : chaque article décrémente aussi le stock de sa variante
+// (variante par défaut sans variant_id) et prend le prix de la variante
+func (ouc *CreateOrderUsecase) WithVariants(variants repository.ProductVariantRepository) *CreateOrderUsecase {
+	ouc.variantRepo = variants
+	return ouc
+}
+
 func (ouc *CreateOrderUsecase) Execute(ctx context.Context, order *entity.Order) (*entity.Order, error) {
 	logger := zerolog.Ctx(ctx)
 	start := time.Now()
@@ -53,6 +72,23 @@ func (ouc *CreateOrderUsecase) Execute(ctx context.Context, order *entity.Order)
 		Int("items_count", len(order.Items)).
 		Msg("Starting order creation process")
 
+	// Sans orders:write:any, on ne commande que pour son propre profil client
+	if own, restricted := utils.OwnCustomerScope(ctx, userentity.PermOrdersWriteAny); restricted {
+		if own == "" {
+			logger.Warn().
+				Str("operation", "execute").
+				Msg("No customer profile linked to the current user")
+			return nil, utils.ErrCustomerProfileMissing
+		}
+		if own != order.CustomerID {
+			logger.Warn().
+				Str("operation", "execute").
+				Str("customer_id", order.CustomerID).
+				Msg("Order creation for another customer denied")
+			return nil, utils.ErrForbidden
+		}
+	}
+
 	// 1. Début de la transaction
 	logger.Debug().
 		Str("operation", "execute").
@@ -69,21 +105,14 @@ func (ouc *CreateOrderUsecase) Execute(ctx context.Context, order *entity.Order)
 		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 
+	// Rollback systématique : sans effet (sql.ErrTxDone) une fois le commit passé
 	defer func() {
-		if err != nil {
-			if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
-				logger.Error().
-					Err(rollbackErr).
-					Str("original_error", err.Error()).
-					Str("operation", "execute").
-					Str("customer_id", order.CustomerID).
-					Msg("Failed to rollback transaction")
-			} else {
-				logger.Debug().
-					Str("operation", "execute").
-					Str("customer_id", order.CustomerID).
-					Msg("Transaction rolled back due to error")
-			}
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			logger.Error().
+				Err(rollbackErr).
+				Str("operation", "execute").
+				Str("customer_id", order.CustomerID).
+				Msg("Failed to rollback transaction")
 		}
 	}()
 
@@ -128,62 +157,72 @@ func (ouc *CreateOrderUsecase) Execute(ctx context.Context, order *entity.Order)
 		Str("customer_name", customer.FirstName+" "+customer.LastName).
 		Msg("Customer verified")
 
-	// 4. Traiter chaque item
-	var totalCents int64
+	// 4. Réserver le stock, produit par produit dans l'ordre des IDs
 	logger.Info().
 		Str("operation", "execute").
 		Str("customer_id", order.CustomerID).
-		Msg("Processing order items")
+		Msg("Reserving stock for order items")
 
-	for i, item := range order.Items {
-		itemLogger := logger.With().
+	productIDs, quantities := quantitiesByProduct(order.Items)
+	products := make(map[string]*entity.Product, len(productIDs))
+
+	for _, productID := range productIDs {
+		productLogger := logger.With().
 			Str("operation", "execute").
 			Str("customer_id", order.CustomerID).
-			Int("item_index", i).
-			Str("product_id", item.ProductID).
-			Int("quantity", item.Quantity).
+			Str("product_id", productID).
+			Int("quantity", quantities[productID]).
 			Logger()
 
-		itemLogger.Debug().Msg("Processing order item")
-
-		product, err := productRepo.FindByID(ctx, item.ProductID)
+		product, err := productRepo.ReserveStock(ctx, productID, quantities[productID])
 		if err != nil {
-			if err == sql.ErrNoRows {
-				itemLogger.Warn().Msg("Product not found")
-				return nil, errors.New("product not found")
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				productLogger.Warn().Err(err).Msg("Product not found")
+				return nil, utils.ErrProductNotFound
+			case errors.Is(err, repository.ErrInsufficientStock):
+				productLogger.Warn().Err(err).Msg("Insufficient stock for product")
+				return nil, utils.ErrOrderInsufficientStock
 			}
-			itemLogger.Error().Err(err).Msg("Failed to retrieve product")
-			return nil, fmt.Errorf("failed to retrieve product: %w", err)
-		}
-
-		if product.Stock < int(item.Quantity) {
-			itemLogger.Warn().
-				Int("available_stock", product.Stock).
-				Int("requested_quantity", item.Quantity).
-				Str("product_name", product.Name).
-				Msg("Insufficient stock for product")
-			return nil, errors.New("not enough stock for product")
+			productLogger.Error().Err(err).Stack().Msg("Failed to reserve product stock")
+			return nil, fmt.Errorf("failed to reserve stock for product: %w", err)
 		}
 
-		item.PriceCents = product.PriceCents
-		item.SubTotal_Cents = product.PriceCents * int64(item.Quantity)
-		totalCents += item.SubTotal_Cents
+		productLogger.Debug().
+			Str("product_name", product.Name).
+			Int("new_stock", product.Stock).
+			Msg("Stock reserved")
+		products[productID] = product
+	}
 
-		product.Stock -= item.Quantity
-		if _, err := productRepo.Update(ctx, product); err != nil {
-			itemLogger.Error().
-				Err(err).
-				Str("product_name", product.Name).
-				Msg("Failed to update product stock")
-			return nil, fmt.Errorf("failed to update stock for product: %w", err)
+	// 4 bis. Réserver le stock des variantes, produits déjà verrouillés
+	var variants map[string]*entity.ProductVariant
+	if ouc.variantRepo != nil {
+		variants, err = reserveVariants(ctx, ouc.variantRepo.WithTX(tx), order.Items)
+		if err != nil {
+			switch {
+			case errors.Is(err, repository.ErrVariantNotFound):
+				logger.Warn().Err(err).Str("customer_id", order.CustomerID).Msg("Unknown variant for order item")
+				return nil, utils.ErrOrderInvalidVariant
+			case errors.Is(err, repository.ErrInsufficientStock):
+				logger.Warn().Err(err).Str("customer_id", order.CustomerID).Msg("Insufficient stock for variant")
+				return nil, utils.ErrOrderInsufficientStock
+			}
+			logger.Error().Err(err).Stack().Str("customer_id", order.CustomerID).Msg("Failed to reserve variant stock")
+			return nil, fmt.Errorf("failed to reserve stock for variant: %w", err)
 		}
+	}
 
-		itemLogger.Debug().
-			Str("product_name", product.Name).
-			Int64("unit_price", product.PriceCents).
-			Int64("subtotal", item.SubTotal_Cents).
-			Int("new_stock", product.Stock).
-			Msg("Order item processed successfully")
+	// 5. Calculer les montants avec les prix lus pendant la réservation
+	var totalCents int64
+	for _, item := range order.Items {
+		price := products[item.ProductID].PriceCents
+		if variant, ok := variants[item.VariantID]; ok {
+			price = variant.EffectivePrice(price)
+		}
+		item.PriceCents = price
+		item.SubTotal_Cents = price * int64(item.Quantity)
+		totalCents += item.SubTotal_Cents
 	}
 
 	logger.Info().
@@ -192,9 +231,9 @@ func (ouc *CreateOrderUsecase) Execute(ctx context.Context, order *entity.Order)
 		Int64("total_amount", totalCents).
 		Msg("All order items processed")
 
-	// 5. Créer la commande
+	// 6. Créer la commande
 	order.TotalCents = totalCents
-	order.Status = "PENDING"
+	order.Status = entity.OrderStatusPending
 	order.CreatedAt = time.Now()
 	order.UpdatedAt = time.Now()
 
@@ -220,7 +259,7 @@ func (ouc *CreateOrderUsecase) Execute(ctx context.Context, order *entity.Order)
 		Str("customer_id", createdOrder.CustomerID).
 		Msg("Order created in repository")
 
-	// 6. Créer les items de commande
+	// 7. Créer les items de commande
 	logger.Debug().
 		Str("operation", "execute").
 		Str("customer_id", order.CustomerID).
@@ -244,7 +283,7 @@ func (ouc *CreateOrderUsecase) Execute(ctx context.Context, order *entity.Order)
 		Int("order_items_created", len(order.Items)).
 		Msg("All order items created successfully")
 
-	// 7. Commit de la transaction
+	// 8. Commit de la transaction
 	logger.Debug().
 		Str("operation", "execute").
 		Str("customer_id", order.CustomerID).
@@ -264,7 +303,9 @@ func (ouc *CreateOrderUsecase) Execute(ctx context.Context, order *entity.Order)
 		Str("customer_id", order.CustomerID).
 		Msg("Transaction committed successfully")
 
-	// 8. Log de succès final
+	productuscase.InvalidateProducts(ctx, ouc.productCache, productIDs...)
+
+	// 9. Log de succès final
 	duration := time.Since(start)
 	logger.Info().
 		Str("order_id", createdOrder.ID).
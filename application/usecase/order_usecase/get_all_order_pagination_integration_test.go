@@ -0,0 +1,99 @@
+package orderusecase_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	orderdto "Goshop/application/dto/order_dto"
+	orderusecase "Goshop/application/usecase/order_usecase"
+	"Goshop/domain/entity"
+	"Goshop/infrastructure/postgres/customer"
+	"Goshop/infrastructure/postgres/order"
+	"Goshop/infrastructure/postgres/product"
+	txmanager "Goshop/infrastructure/postgres/tx_manager"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Des commandes de 3 articles parcourues 2 par 2 : chaque page contient
+// exactement `limit` commandes complètes, sans doublon ni trou, et le total
+// correspond au nombre de commandes retournées.
+func TestGetAllOrderUsecase_Integration_PaginationWithMultiItemOrders(t *testing.T) {
+	productRepo := product.NewProductRepositoryInfrastructure(db)
+	customerRepo := customer.NewCustomerRepoInfrastructurePostgres(db)
+	orderRepo := order.NewOrderPostgresInfra(db)
+	orderItemRepo := order.NewOrderItemPostgresInfra(db)
+	txManager := txmanager.NewTxManagerPostgresInfra(db)
+
+	createOrder := orderusecase.NewCreateOrderUsecase(txManager, productRepo, customerRepo, orderItemRepo, orderRepo)
+	getAllOrders := orderusecase.NewGetAllOrderUsecase(orderRepo, txManager)
+
+	createdCustomer, err := customerRepo.Create(ctx, &entity.Customer{
+		FirstName: "Pagination",
+		LastName:  "Test",
+		Email:     fmt.Sprintf("pagination_%d@test.com", time.Now().UnixNano()),
+	})
+	require.NoError(t, err)
+
+	products := make([]*entity.Product, 3)
+	for i := range products {
+		products[i] = &entity.Product{
+			Name:        fmt.Sprintf("Pagination %d", i),
+			Description: "Pagination test",
+			PriceCents:  int64(1000 * (i + 1)),
+			Stock:       100,
+		}
+		require.NoError(t, productRepo.Create(ctx, products[i]))
+	}
+
+	const ordersCount = 5
+	created := map[string]bool{}
+	for i := 0; i < ordersCount; i++ {
+		items := []*entity.OrderItem{}
+		for _, p := range products {
+			items = append(items, &entity.OrderItem{ProductID: p.ID, Quantity: 1})
+		}
+		o, err := createOrder.Execute(ctx, &entity.Order{CustomerID: createdCustomer.ID, Items: items})
+		require.NoError(t, err)
+		created[o.ID] = true
+	}
+
+	filter := orderdto.OrderFilter{CustomerID: &createdCustomer.ID}
+	const limit = 2
+
+	seen := map[string]bool{}
+	var previous *entity.Order
+	for offset := 0; offset < ordersCount+limit; offset += limit {
+		orders, total, err := getAllOrders.ExecuteWithPagination(ctx, limit, offset, filter)
+		require.NoError(t, err)
+		assert.Equal(t, ordersCount, total)
+
+		expected := ordersCount - offset
+		if expected > limit {
+			expected = limit
+		}
+		if expected < 0 {
+			expected = 0
+		}
+		require.Len(t, orders, expected, "offset %d : la limite porte sur les commandes", offset)
+
+		for _, o := range orders {
+			assert.False(t, seen[o.ID], "commande %s présente sur deux pages", o.ID)
+			seen[o.ID] = true
+			assert.Len(t, o.Items, len(products), "commande %s tronquée", o.ID)
+
+			// Ordre stable : created_at DESC, id DESC d'une page à l'autre
+			if previous != nil {
+				assert.False(t, o.CreatedAt.After(previous.CreatedAt))
+				if o.CreatedAt.Equal(previous.CreatedAt) {
+					assert.Less(t, o.ID, previous.ID)
+				}
+			}
+			previous = o
+		}
+	}
+
+	assert.Equal(t, created, seen, "toutes les commandes sont parcourues une seule fois")
+}
@@ -6,8 +6,12 @@ import (
 	"fmt"
 	"time"
 
+	orderdto "Goshop/application/dto/order_dto"
+	paginationdto "Goshop/application/dto/pagination_dto"
 	"Goshop/domain/entity"
+	userentity "Goshop/domain/entity/user_entity"
 	"Goshop/domain/repository"
+	"Goshop/interfaces/utils"
 
 	"github.com/rs/zerolog"
 )
@@ -39,6 +43,11 @@ func (uc *GetAllOrderUsecase) Execute(ctx context.Context) ([]*entity.Order, err
 		Str("operation", "execute").
 		Msg("Starting retrieval of all orders")
 
+	own, restricted := utils.OwnCustomerScope(ctx, userentity.PermOrdersReadAny)
+	if restricted && own == "" {
+		return nil, utils.ErrCustomerProfileMissing
+	}
+
 	// 1. Début de la transaction (lecture seule)
 	logger.Debug().
 		Str("operation", "execute").
@@ -86,6 +95,11 @@ func (uc *GetAllOrderUsecase) Execute(ctx context.Context) ([]*entity.Order, err
 		return nil, fmt.Errorf("failed to fetch orders: %w", err)
 	}
 
+	// Sans orders:read:any, seules les commandes du client courant sont visibles
+	if restricted {
+		orders = ordersOfCustomer(orders, own)
+	}
+
 	logger.Debug().
 		Str("operation", "execute").
 		Int("orders_count", len(orders)).
@@ -135,6 +149,101 @@ func (uc *GetAllOrderUsecase) Execute(ctx context.Context) ([]*entity.Order, err
 	return orders, nil
 }
 
+// ExecuteWithPagination renvoie une page de commandes (mode offset) et le
+// nombre total de commandes correspondant au filtre.
+func (uc *GetAllOrderUsecase) ExecuteWithPagination(ctx context.Context, limit, offset int, filter orderdto.OrderFilter) ([]*entity.Order, int, error) {
+	logger := zerolog.Ctx(ctx)
+
+	filter, err := scopeOrderFilter(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	orders, err := uc.repo.FindAllWithPagination(ctx, limit, offset, filter)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute_with_pagination").
+			Int("limit", limit).
+			Int("offset", offset).
+			Msg("Failed to retrieve orders page from repository")
+		return nil, 0, fmt.Errorf("failed to fetch orders: %w", err)
+	}
+
+	total, err := uc.repo.CountAll(ctx, filter)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute_with_pagination").
+			Msg("Failed to count orders")
+		return nil, 0, fmt.Errorf("failed to count orders: %w", err)
+	}
+
+	return orders, total, nil
+}
+
+// ExecuteCursor renvoie une page de commandes après le curseur, triée par
+// (created_at DESC, id DESC).
+func (uc *GetAllOrderUsecase) ExecuteCursor(ctx context.Context, page paginationdto.CursorPage, filter orderdto.OrderFilter) (*paginationdto.CursorResult[*entity.Order], error) {
+	logger := zerolog.Ctx(ctx)
+
+	filter, err := scopeOrderFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Une commande de plus pour savoir s'il reste une page
+	query := page
+	query.Limit = page.Limit + 1
+
+	orders, err := uc.repo.FindAllByCursor(ctx, query, filter)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute_cursor").
+			Int("limit", page.Limit).
+			Bool("backward", page.Backward).
+			Msg("Failed to retrieve orders page from repository")
+		return nil, fmt.Errorf("failed to fetch orders: %w", err)
+	}
+
+	orders, hasMore := paginationdto.TrimPage(orders, page.Limit, page.Backward)
+
+	return paginationdto.NewCursorResult(orders, hasMore, func(o *entity.Order) paginationdto.Cursor {
+		return paginationdto.Cursor{CreatedAt: o.CreatedAt, ID: o.ID}
+	}, orders), nil
+}
+
+// scopeOrderFilter restreint le filtre au client courant quand l'utilisateur
+// n'a pas orders:read:any. Filtrer sur un autre client est refusé.
+func scopeOrderFilter(ctx context.Context, filter orderdto.OrderFilter) (orderdto.OrderFilter, error) {
+	own, restricted := utils.OwnCustomerScope(ctx, userentity.PermOrdersReadAny)
+	if !restricted {
+		return filter, nil
+	}
+	if own == "" {
+		return filter, utils.ErrCustomerProfileMissing
+	}
+	if filter.CustomerID != nil && *filter.CustomerID != own {
+		zerolog.Ctx(ctx).Warn().
+			Str("customer_id", *filter.CustomerID).
+			Msg("Order listing for another customer denied")
+		return filter, utils.ErrForbidden
+	}
+	filter.CustomerID = &own
+	return filter, nil
+}
+
+func ordersOfCustomer(orders []*entity.Order, customerID string) []*entity.Order {
+	owned := make([]*entity.Order, 0, len(orders))
+	for _, order := range orders {
+		if order.CustomerID == customerID {
+			owned = append(owned, order)
+		}
+	}
+	return owned
+}
+
 // analyzeOrders — sans logger en paramètre (utilise uc.logger)
 func (uc *GetAllOrderUsecase) analyzeOrders(ctx context.Context, orders []*entity.Order) {
 	logger := zerolog.Ctx(ctx)
@@ -0,0 +1,95 @@
+package orderusecase_test
+
+import (
+	"context"
+	"testing"
+
+	orderdto "Goshop/application/dto/order_dto"
+	orderusecase "Goshop/application/usecase/order_usecase"
+	"Goshop/domain/entity"
+	"Goshop/interfaces/utils"
+	mockrepo "Goshop/mocks/repository"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// ownerContext : client connecté sans orders:read:any
+func ownerContext(customerID string) context.Context {
+	ctx := utils.WithUser(context.Background(), "user-1", "customer")
+	ctx = utils.WithUserPermissions(ctx, []string{"orders:write"})
+	return utils.WithCustomerID(ctx, customerID)
+}
+
+func TestGetAllOrderUsecase_Pagination_ScopedToCurrentCustomer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockrepo.NewMockOrderRepository(ctrl)
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+
+	status := entity.OrderStatusPending
+	own := "cust-1"
+	expected := orderdto.OrderFilter{Status: &status, CustomerID: &own}
+
+	orders := []*entity.Order{{ID: "order-1", CustomerID: own}}
+	mockRepo.EXPECT().FindAllWithPagination(gomock.Any(), 10, 0, expected).Return(orders, nil)
+	mockRepo.EXPECT().CountAll(gomock.Any(), expected).Return(1, nil)
+
+	uc := orderusecase.NewGetAllOrderUsecase(mockRepo, mockTxManager)
+
+	result, total, err := uc.ExecuteWithPagination(ownerContext(own), 10, 0, orderdto.OrderFilter{Status: &status})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, orders, result)
+}
+
+func TestGetAllOrderUsecase_Pagination_OtherCustomerFilterDenied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockrepo.NewMockOrderRepository(ctrl)
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+
+	other := "cust-2"
+	uc := orderusecase.NewGetAllOrderUsecase(mockRepo, mockTxManager)
+
+	_, _, err := uc.ExecuteWithPagination(ownerContext("cust-1"), 10, 0, orderdto.OrderFilter{CustomerID: &other})
+
+	assert.ErrorIs(t, err, utils.ErrForbidden)
+}
+
+func TestGetAllOrderUsecase_Pagination_NoCustomerProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockrepo.NewMockOrderRepository(ctrl)
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+
+	uc := orderusecase.NewGetAllOrderUsecase(mockRepo, mockTxManager)
+
+	_, _, err := uc.ExecuteWithPagination(ownerContext(""), 10, 0, orderdto.OrderFilter{})
+
+	assert.ErrorIs(t, err, utils.ErrCustomerProfileMissing)
+}
+
+func TestGetAllOrderUsecase_Pagination_AnyPermissionKeepsFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockrepo.NewMockOrderRepository(ctrl)
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+
+	ctx := utils.WithUser(context.Background(), "user-2", "manager")
+	ctx = utils.WithUserPermissions(ctx, []string{"orders:read:any"})
+
+	mockRepo.EXPECT().FindAllWithPagination(gomock.Any(), 10, 0, orderdto.OrderFilter{}).Return(nil, nil)
+	mockRepo.EXPECT().CountAll(gomock.Any(), orderdto.OrderFilter{}).Return(0, nil)
+
+	uc := orderusecase.NewGetAllOrderUsecase(mockRepo, mockTxManager)
+
+	_, _, err := uc.ExecuteWithPagination(ctx, 10, 0, orderdto.OrderFilter{})
+
+	assert.NoError(t, err)
+}
@@ -4,11 +4,14 @@ package orderusecase
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"Goshop/domain/entity"
+	userentity "Goshop/domain/entity/user_entity"
 	"Goshop/domain/repository"
+	"Goshop/interfaces/utils"
 
 	"github.com/rs/zerolog"
 )
@@ -88,7 +91,7 @@ func (uc *GetOrderByIdUsecase) Execute(ctx context.Context, id string) (*entity.
 
 	order, err := repo.FindByID(ctx, id)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, sql.ErrNoRows) {
 			logger.Warn().
 				Err(err).
 				Dur("duration_before_error", time.Since(start)).
@@ -106,6 +109,15 @@ func (uc *GetOrderByIdUsecase) Execute(ctx context.Context, id string) (*entity.
 		return nil, fmt.Errorf("failed to retrieve order: %w", err)
 	}
 
+	// Commande d'un autre client : 404 pour ne pas en révéler l'existence
+	if !utils.CanAccessCustomer(ctx, order.CustomerID, userentity.PermOrdersReadAny) {
+		logger.Warn().
+			Str("operation", "execute").
+			Str("order_id", id).
+			Msg("Access to another customer's order denied")
+		return nil, utils.ErrOrderNotFound
+	}
+
 	logger.Debug().
 		Str("operation", "execute").
 		Str("order_id", order.ID).
@@ -0,0 +1,85 @@
+// application/usecase/order_usecase/get_order_status_history_usecase.go
+package orderusecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"Goshop/domain/entity"
+	userentity "Goshop/domain/entity/user_entity"
+	"Goshop/domain/repository"
+	"Goshop/interfaces/utils"
+
+	"github.com/rs/zerolog"
+)
+
+type GetOrderStatusHistoryUsecase struct {
+	repo repository.OrderRepository
+	//logger    *setupLogging.Logger
+}
+
+func NewGetOrderStatusHistoryUsecase(
+	repo repository.OrderRepository,
+	//logger *setupLogging.Logger,
+) *GetOrderStatusHistoryUsecase {
+	return &GetOrderStatusHistoryUsecase{
+		repo: repo,
+		//logger:    logger.WithComponent("get_order_status_history_usecase"),
+	}
+}
+
+func (uc *GetOrderStatusHistoryUsecase) Execute(ctx context.Context, id string) ([]*entity.OrderStatusHistory, error) {
+	logger := zerolog.Ctx(ctx)
+	start := time.Now()
+
+	if id == "" {
+		logger.Warn().Msg("Empty order ID provided")
+		return nil, utils.ErrOrderNotFound
+	}
+
+	// La commande doit exister, sinon on renvoie 404 plutôt qu'une liste vide
+	order, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Warn().
+				Err(err).
+				Str("order_id", id).
+				Msg("Order not found")
+			return nil, utils.ErrOrderNotFound
+		}
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("order_id", id).
+			Msg("Failed to retrieve order")
+		return nil, utils.ErrInternalServer
+	}
+
+	// Commande d'un autre client : 404 pour ne pas en révéler l'existence
+	if !utils.CanAccessCustomer(ctx, order.CustomerID, userentity.PermOrdersReadAny) {
+		logger.Warn().
+			Str("order_id", id).
+			Msg("Access to another customer's order denied")
+		return nil, utils.ErrOrderNotFound
+	}
+
+	history, err := uc.repo.FindStatusHistory(ctx, id)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("order_id", id).
+			Msg("Failed to retrieve order status history")
+		return nil, utils.ErrInternalServer
+	}
+
+	logger.Info().
+		Str("order_id", id).
+		Int("entries", len(history)).
+		Dur("total_duration_ms", time.Since(start)).
+		Msg("Order status history retrieved")
+
+	return history, nil
+}
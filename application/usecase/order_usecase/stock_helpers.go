@@ -0,0 +1,89 @@
+package orderusecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"Goshop/domain/entity"
+	"Goshop/domain/repository"
+)
+
+// quantitiesByProduct regroupe les quantités par produit et retourne les IDs
+// triés : toutes les transactions verrouillent les lignes products dans le même
+// ordre, ce qui évite les deadlocks entre commandes multi-produits.
+func quantitiesByProduct(items []*entity.OrderItem) ([]string, map[string]int) {
+	quantities := make(map[string]int, len(items))
+	for _, item := range items {
+		quantities[item.ProductID] += item.Quantity
+	}
+
+	ids := make([]string, 0, len(quantities))
+	for id := range quantities {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids, quantities
+}
+
+// quantitiesByVariant regroupe les quantités par variante (articles sans
+// variante ignorés) et retourne les IDs triés
+func quantitiesByVariant(items []*entity.OrderItem) ([]string, map[string]int) {
+	quantities := make(map[string]int, len(items))
+	for _, item := range items {
+		if item.VariantID != "" {
+			quantities[item.VariantID] += item.Quantity
+		}
+	}
+
+	ids := make([]string, 0, len(quantities))
+	for id := range quantities {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids, quantities
+}
+
+// reserveVariants réserve le stock des variantes commandées, une fois les
+// lignes products verrouillées par l'appelant. Les articles sans variant_id
+// reçoivent la variante par défaut de leur produit. Renvoie
+// repository.ErrVariantNotFound si une variante n'existe pas ou appartient à
+// un autre produit que celui de l'article.
+func reserveVariants(ctx context.Context, variants repository.ProductVariantRepository, items []*entity.OrderItem) (map[string]*entity.ProductVariant, error) {
+	defaults := make(map[string]string)
+	owners := make(map[string]string, len(items))
+	for _, item := range items {
+		if item.VariantID == "" {
+			id, ok := defaults[item.ProductID]
+			if !ok {
+				variant, err := variants.FindDefault(ctx, item.ProductID)
+				if err != nil {
+					return nil, fmt.Errorf("default variant of product %s: %w", item.ProductID, err)
+				}
+				id = variant.ID
+				defaults[item.ProductID] = id
+			}
+			item.VariantID = id
+		}
+
+		// Une même variante ne peut pas être commandée sous deux produits
+		if owner, ok := owners[item.VariantID]; ok && owner != item.ProductID {
+			return nil, fmt.Errorf("variant %s ordered for products %s and %s: %w", item.VariantID, owner, item.ProductID, repository.ErrVariantNotFound)
+		}
+		owners[item.VariantID] = item.ProductID
+	}
+
+	ids, quantities := quantitiesByVariant(items)
+	reserved := make(map[string]*entity.ProductVariant, len(ids))
+	for _, id := range ids {
+		variant, err := variants.ReserveStock(ctx, owners[id], id, quantities[id])
+		if err != nil {
+			return nil, err
+		}
+		reserved[id] = variant
+	}
+
+	return reserved, nil
+}
@@ -0,0 +1,191 @@
+// application/usecase/order_usecase/update_order_status_usecase.go
+package orderusecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"Goshop/application/metrics"
+	"Goshop/domain/entity"
+	"Goshop/domain/repository"
+	"Goshop/interfaces/utils"
+
+	"github.com/rs/zerolog"
+)
+
+type UpdateOrderStatusUsecase struct {
+	repo      repository.OrderRepository
+	txManager repository.TxManager
+	//logger    *setupLogging.Logger
+}
+
+func NewUpdateOrderStatusUsecase(
+	repo repository.OrderRepository,
+	txManager repository.TxManager,
+	//logger *setupLogging.Logger,
+) *UpdateOrderStatusUsecase {
+	return &UpdateOrderStatusUsecase{
+		repo:      repo,
+		txManager: txManager,
+		//logger:    logger.WithComponent("update_order_status_usecase"),
+	}
+}
+
+// Execute fait passer la commande au statut `status` si la machine à états
+// l'autorise, et trace le changement dans order_status_history.
+func (uc *UpdateOrderStatusUsecase) Execute(ctx context.Context, id, status, reason string) (*entity.Order, error) {
+	logger := zerolog.Ctx(ctx)
+	start := time.Now()
+	status = entity.NormalizeOrderStatus(status)
+
+	logger.Info().
+		Str("operation", "execute").
+		Str("order_id", id).
+		Str("target_status", status).
+		Msg("Starting order status update")
+
+	if id == "" {
+		logger.Warn().Msg("Empty order ID provided")
+		return nil, utils.ErrOrderNotFound
+	}
+
+	if !entity.IsValidOrderStatus(status) {
+		logger.Warn().
+			Str("operation", "execute").
+			Str("order_id", id).
+			Str("target_status", status).
+			Msg("Unknown target status")
+		return nil, utils.ErrOrderInvalidStatus
+	}
+
+	// L'annulation remet le stock en place : elle passe par CancelOrderUsecase
+	if status == entity.OrderStatusCancelled {
+		logger.Warn().
+			Str("operation", "execute").
+			Str("order_id", id).
+			Msg("Cancellation must go through CancelOrderUsecase")
+		return nil, utils.ErrOrderInvalidTransition
+	}
+
+	// 1. Début de la transaction
+	tx, err := uc.txManager.BeginTx(ctx)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("operation", "execute").
+			Str("order_id", id).
+			Msg("Failed to begin transaction")
+		return nil, utils.ErrTransactionBegin
+	}
+
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			logger.Error().
+				Err(rollbackErr).
+				Str("operation", "execute").
+				Str("order_id", id).
+				Msg("Failed to rollback transaction")
+		}
+	}()
+
+	repo := uc.repo.WithTX(tx)
+
+	// 2. Charger la commande
+	order, err := repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Warn().
+				Err(err).
+				Str("order_id", id).
+				Msg("Order not found")
+			return nil, utils.ErrOrderNotFound
+		}
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("order_id", id).
+			Msg("Failed to retrieve order")
+		return nil, utils.ErrOrderUpdateFail
+	}
+
+	// 3. Vérifier la transition
+	from := entity.NormalizeOrderStatus(order.Status)
+	if err := entity.ValidateOrderTransition(from, status); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("order_id", id).
+			Str("from_status", from).
+			Str("to_status", status).
+			Strs("allowed", entity.AllowedOrderTransitions(from)).
+			Msg("Order status transition rejected")
+		return nil, utils.ErrOrderInvalidTransition
+	}
+
+	// 4. Appliquer la transition (gardée par le statut courant)
+	updated, err := repo.UpdateStatus(ctx, id, order.Status, status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Warn().
+				Err(err).
+				Str("order_id", id).
+				Str("from_status", from).
+				Str("to_status", status).
+				Msg("Order status changed concurrently")
+			return nil, utils.ErrOrderStatusConflict
+		}
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("order_id", id).
+			Msg("Failed to update order status")
+		return nil, utils.ErrOrderUpdateFail
+	}
+
+	// 5. Historique
+	changedBy, ok := utils.GetUserID(ctx)
+	if !ok {
+		changedBy = "system"
+	}
+
+	history := &entity.OrderStatusHistory{
+		OrderID:    id,
+		FromStatus: from,
+		ToStatus:   status,
+		ChangedBy:  changedBy,
+		Reason:     reason,
+	}
+	if err := repo.AddStatusHistory(ctx, history); err != nil {
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("order_id", id).
+			Msg("Failed to record order status history")
+		return nil, utils.ErrOrderUpdateFail
+	}
+
+	// 6. Commit
+	if err := tx.Commit(); err != nil {
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("order_id", id).
+			Msg("Failed to commit transaction")
+		return nil, utils.ErrTransactionCommit
+	}
+
+	updated.Items = order.Items
+	metrics.OrdersStatusTransitionsTotal.WithLabelValues(from, status).Inc()
+
+	logger.Info().
+		Str("order_id", id).
+		Str("from_status", from).
+		Str("to_status", status).
+		Str("changed_by", changedBy).
+		Dur("total_duration_ms", time.Since(start)).
+		Msg("Order status updated successfully")
+
+	return updated, nil
+}
@@ -0,0 +1,143 @@
+package orderusecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	orderusecase "Goshop/application/usecase/order_usecase"
+	"Goshop/domain/entity"
+	"Goshop/interfaces/utils"
+	mockrepo "Goshop/mocks/repository"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUpdateOrderStatusUsecase_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+	mockTx := mockrepo.NewMockTx(ctrl)
+	mockOrderRepo := mockrepo.NewMockOrderRepository(ctrl)
+	mockOrderRepoTx := mockrepo.NewMockOrderRepository(ctrl)
+
+	existing := &entity.Order{ID: "order-1", CustomerID: "cust-1", Status: entity.OrderStatusPending}
+	updated := &entity.Order{ID: "order-1", CustomerID: "cust-1", Status: entity.OrderStatusPaid}
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockOrderRepo.EXPECT().WithTX(mockTx).Return(mockOrderRepoTx)
+	mockOrderRepoTx.EXPECT().FindByID(gomock.Any(), "order-1").Return(existing, nil)
+	mockOrderRepoTx.EXPECT().UpdateStatus(gomock.Any(), "order-1", entity.OrderStatusPending, entity.OrderStatusPaid).
+		Return(updated, nil)
+	mockOrderRepoTx.EXPECT().AddStatusHistory(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, h *entity.OrderStatusHistory) error {
+			assert.Equal(t, "order-1", h.OrderID)
+			assert.Equal(t, entity.OrderStatusPending, h.FromStatus)
+			assert.Equal(t, entity.OrderStatusPaid, h.ToStatus)
+			assert.Equal(t, "user-42", h.ChangedBy)
+			assert.Equal(t, "payment captured", h.Reason)
+			return nil
+		})
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Rollback().Return(sql.ErrTxDone).AnyTimes()
+
+	uc := orderusecase.NewUpdateOrderStatusUsecase(mockOrderRepo, mockTxManager)
+
+	ctx := utils.WithUserID(context.Background(), "user-42")
+	result, err := uc.Execute(ctx, "order-1", "paid", "payment captured")
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.OrderStatusPaid, result.Status)
+}
+
+func TestUpdateOrderStatusUsecase_InvalidTransition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+	mockTx := mockrepo.NewMockTx(ctrl)
+	mockOrderRepo := mockrepo.NewMockOrderRepository(ctrl)
+	mockOrderRepoTx := mockrepo.NewMockOrderRepository(ctrl)
+
+	existing := &entity.Order{ID: "order-1", Status: entity.OrderStatusPending}
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockOrderRepo.EXPECT().WithTX(mockTx).Return(mockOrderRepoTx)
+	mockOrderRepoTx.EXPECT().FindByID(gomock.Any(), "order-1").Return(existing, nil)
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	uc := orderusecase.NewUpdateOrderStatusUsecase(mockOrderRepo, mockTxManager)
+
+	// PENDING -> SHIPPED n'est pas autorisé
+	result, err := uc.Execute(context.Background(), "order-1", entity.OrderStatusShipped, "")
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, utils.ErrOrderInvalidTransition)
+}
+
+func TestUpdateOrderStatusUsecase_UnknownStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+	mockOrderRepo := mockrepo.NewMockOrderRepository(ctrl)
+
+	uc := orderusecase.NewUpdateOrderStatusUsecase(mockOrderRepo, mockTxManager)
+
+	result, err := uc.Execute(context.Background(), "order-1", "LOST", "")
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, utils.ErrOrderInvalidStatus)
+}
+
+func TestUpdateOrderStatusUsecase_OrderNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+	mockTx := mockrepo.NewMockTx(ctrl)
+	mockOrderRepo := mockrepo.NewMockOrderRepository(ctrl)
+	mockOrderRepoTx := mockrepo.NewMockOrderRepository(ctrl)
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockOrderRepo.EXPECT().WithTX(mockTx).Return(mockOrderRepoTx)
+	mockOrderRepoTx.EXPECT().FindByID(gomock.Any(), "missing").Return(nil, sql.ErrNoRows)
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	uc := orderusecase.NewUpdateOrderStatusUsecase(mockOrderRepo, mockTxManager)
+
+	result, err := uc.Execute(context.Background(), "missing", entity.OrderStatusPaid, "")
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, utils.ErrOrderNotFound)
+}
+
+func TestUpdateOrderStatusUsecase_ConcurrentUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mockrepo.NewMockTxManager(ctrl)
+	mockTx := mockrepo.NewMockTx(ctrl)
+	mockOrderRepo := mockrepo.NewMockOrderRepository(ctrl)
+	mockOrderRepoTx := mockrepo.NewMockOrderRepository(ctrl)
+
+	existing := &entity.Order{ID: "order-1", Status: entity.OrderStatusPaid}
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockOrderRepo.EXPECT().WithTX(mockTx).Return(mockOrderRepoTx)
+	mockOrderRepoTx.EXPECT().FindByID(gomock.Any(), "order-1").Return(existing, nil)
+	// Une autre requête a changé le statut entre la lecture et l'écriture
+	mockOrderRepoTx.EXPECT().UpdateStatus(gomock.Any(), "order-1", entity.OrderStatusPaid, entity.OrderStatusFulfilled).
+		Return(nil, errors.Join(errors.New("order not in status PAID"), sql.ErrNoRows))
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	uc := orderusecase.NewUpdateOrderStatusUsecase(mockOrderRepo, mockTxManager)
+
+	result, err := uc.Execute(context.Background(), "order-1", entity.OrderStatusFulfilled, "")
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, utils.ErrOrderStatusConflict)
+}
@@ -16,8 +16,10 @@ import (
 )
 
 type CreateProductUsecase struct {
-	repo      repository.ProductRepository
-	txManager repository.TxManager
+	repo       repository.ProductRepository
+	txManager  repository.TxManager
+	categories repository.CategoryRepository
+	variants   repository.ProductVariantRepository
 	//logger    *setupLogging.Logger
 }
 
@@ -33,6 +35,18 @@ func NewCreateProductUsecase(
 	}
 }
 
+// WithCategories permet de rattacher le produit à des catégories (category_ids)
+func (uc *CreateProductUsecase) WithCategories(categories repository.CategoryRepository) *CreateProductUsecase {
+	uc.categories = categories
+	return uc
+}
+
+// WithVariants crée la variante par défaut du produit, qui porte son stock
+func (uc *CreateProductUsecase) WithVariants(variants repository.ProductVariantRepository) *CreateProductUsecase {
+	uc.variants = variants
+	return uc
+}
+
 func (uc *CreateProductUsecase) Execute(ctx context.Context, input dto.CreateProductRequest) (*dto.ProductResponse, error) {
 	start := time.Now()
 	logger := zerolog.Ctx(ctx)
@@ -150,6 +164,38 @@ func (uc *CreateProductUsecase) Execute(ctx context.Context, input dto.CreatePro
 		Str("product_name", product.Name).
 		Msg("Product created successfully in repository")
 
+	// Variante par défaut créée dans la même transaction
+	var defaultVariant *entity.ProductVariant
+	if uc.variants != nil {
+		defaultVariant, err = createDefaultVariant(ctx, uc.variants.WithTX(tx), product, input.SKU)
+		if err != nil {
+			logger.Warn().
+				Err(err).
+				Str("operation", "execute").
+				Str("product_id", product.ID).
+				Str("sku", input.SKU).
+				Msg("Failed to create default variant")
+			return nil, variantErr(err, utils.ErrProductCreateFail)
+		}
+	}
+
+	// Catégories rattachées dans la même transaction
+	if len(input.CategoryIDs) > 0 {
+		var categoryRepo repository.CategoryRepository
+		if uc.categories != nil {
+			categoryRepo = uc.categories.WithTX(tx)
+		}
+		if err = setProductCategories(ctx, categoryRepo, product.ID, input.CategoryIDs); err != nil {
+			logger.Warn().
+				Err(err).
+				Str("operation", "execute").
+				Str("product_id", product.ID).
+				Strs("category_ids", input.CategoryIDs).
+				Msg("Failed to link product categories")
+			return nil, categoryErr(err, utils.ErrProductCreateFail)
+		}
+	}
+
 	// Commit de la transaction
 	logger.Debug().
 		Str("operation", "execute").
@@ -172,14 +218,21 @@ func (uc *CreateProductUsecase) Execute(ctx context.Context, input dto.CreatePro
 		Msg("Transaction committed successfully")
 
 	// Préparation de la réponse
-	response := &dto.ProductResponse{
-		ID:          product.ID,
-		Name:        product.Name,
-		Description: product.Description,
-		PriceCents:  product.PriceCents,
-		Stock:       product.Stock,
-		CreatedAt:   product.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt:   product.UpdatedAt.Format("2006-01-02 15:04:05"),
+	response := dto.NewProductResponse(product)
+	if defaultVariant != nil {
+		response.Variants = []dto.VariantResponse{dto.NewVariantResponse(defaultVariant, product.PriceCents)}
+	}
+
+	// Le produit est créé : un échec de lecture des catégories ne doit pas
+	// pousser le client à recommencer
+	if len(input.CategoryIDs) > 0 {
+		if err := LoadProductCategories(ctx, uc.categories, response); err != nil {
+			logger.Warn().
+				Err(err).
+				Str("operation", "execute").
+				Str("product_id", product.ID).
+				Msg("Failed to load product categories for response")
+		}
 	}
 
 	// Log de succès (sans métriques de perf)
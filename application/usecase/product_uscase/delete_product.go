@@ -15,6 +15,7 @@ import (
 type DeleteProductUsecase struct {
 	repo      repository.ProductRepository
 	txManager repository.TxManager
+	cache     repository.ProductCache
 	//logger    *setupLogging.Logger
 }
 
@@ -30,6 +31,15 @@ func NewDeleteProductUsecase(
 	}
 }
 
+// WithCache : le produit supprimé est retiré du cache après le commit
+func (uc *DeleteProductUsecase) WithCache(cache repository.ProductCache) *DeleteProductUsecase {
+	uc.cache = cache
+	return uc
+}
+
+// Execute archive le produit : il disparaît du catalogue mais reste référencé
+// par les commandes, et ses variantes et images sont conservées pour une
+// éventuelle restauration. PurgeProductUsecase les supprime définitivement.
 func (uc *DeleteProductUsecase) Execute(ctx context.Context, id string) error {
 	logger := zerolog.Ctx(ctx)
 	if id == "" {
@@ -156,6 +166,8 @@ func (uc *DeleteProductUsecase) Execute(ctx context.Context, id string) error {
 		Str("product_name", product.Name).
 		Msg("Transaction committed successfully")
 
+	InvalidateProducts(ctx, uc.cache, id)
+
 	// Log de succès
 	duration := time.Since(start)
 	logger.Info().
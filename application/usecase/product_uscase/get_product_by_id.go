@@ -3,18 +3,32 @@ package productuscase
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	dto "Goshop/application/dto/product_dto"
+	"Goshop/application/metrics"
+	"Goshop/domain/entity"
 	"Goshop/domain/repository"
 	"Goshop/interfaces/utils"
 
 	"github.com/rs/zerolog"
+	"golang.org/x/sync/singleflight"
 )
 
 type GetProductByIdUsecase struct {
 	repo      repository.ProductRepository
 	txManager repository.TxManager
+	cache     repository.ProductCache
+	// loads regroupe les lectures en base concurrentes d'un même produit
+	// absent du cache : une seule requête SQL par produit et par replica
+	loads singleflight.Group
+	// categories : fil d'Ariane des catégories, lu hors cache
+	categories repository.CategoryRepository
+	// variants : variantes et leur stock, lues hors cache
+	variants repository.ProductVariantRepository
+	// images : image principale, lue hors cache
+	images repository.ProductImageRepository
 	//logger    *setupLogging.Logger
 }
 
@@ -30,7 +44,45 @@ func NewGetProductByIdUsecase(
 	}
 }
 
+// WithCache active la lecture à travers le cache (nil : pas de cache)
+func (uc *GetProductByIdUsecase) WithCache(cache repository.ProductCache) *GetProductByIdUsecase {
+	uc.cache = cache
+	return uc
+}
+
+// WithCategories ajoute les catégories du produit à la réponse
+func (uc *GetProductByIdUsecase) WithCategories(categories repository.CategoryRepository) *GetProductByIdUsecase {
+	uc.categories = categories
+	return uc
+}
+
+// WithVariants ajoute les variantes du produit à la réponse
+func (uc *GetProductByIdUsecase) WithVariants(variants repository.ProductVariantRepository) *GetProductByIdUsecase {
+	uc.variants = variants
+	return uc
+}
+
+// WithImages ajoute l'image principale du produit à la réponse
+func (uc *GetProductByIdUsecase) WithImages(images repository.ProductImageRepository) *GetProductByIdUsecase {
+	uc.images = images
+	return uc
+}
+
 func (uc *GetProductByIdUsecase) Execute(ctx context.Context, id string) (*dto.ProductResponse, error) {
+	return uc.execute(ctx, id, uc.load)
+}
+
+// ExecuteIncludingDeleted renvoie aussi un produit archivé (back-office).
+// Les produits archivés ne sont jamais en cache : lecture directe en base.
+func (uc *GetProductByIdUsecase) ExecuteIncludingDeleted(ctx context.Context, id string) (*dto.ProductResponse, error) {
+	return uc.execute(ctx, id, uc.repo.FindByIDIncludingDeleted)
+}
+
+func (uc *GetProductByIdUsecase) execute(
+	ctx context.Context,
+	id string,
+	find func(ctx context.Context, id string) (*entity.Product, error),
+) (*dto.ProductResponse, error) {
 	logger := zerolog.Ctx(ctx)
 	if id == "" {
 		logger.Warn().
@@ -52,7 +104,7 @@ func (uc *GetProductByIdUsecase) Execute(ctx context.Context, id string) (*dto.P
 		Str("product_id", id).
 		Msg("Fetching product from repository")
 
-	product, err := uc.repo.FindByID(ctx, id)
+	product, err := find(ctx, id)
 	if err != nil {
 		logger.Warn().
 			Err(err).
@@ -70,14 +122,33 @@ func (uc *GetProductByIdUsecase) Execute(ctx context.Context, id string) (*dto.P
 		Msg("Product retrieved from repository")
 
 	// Construction de la réponse
-	response := &dto.ProductResponse{
-		ID:          product.ID,
-		Name:        product.Name,
-		Description: product.Description,
-		PriceCents:  product.PriceCents,
-		Stock:       product.Stock,
-		CreatedAt:   product.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt:   product.UpdatedAt.Format("2006-01-02 15:04:05"),
+	response := dto.NewProductResponse(product)
+
+	if err := LoadProductCategories(ctx, uc.categories, response); err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute").
+			Str("product_id", id).
+			Msg("Failed to load product categories")
+		return nil, utils.ErrInternalServer
+	}
+
+	if err := LoadProductVariants(ctx, uc.variants, response); err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute").
+			Str("product_id", id).
+			Msg("Failed to load product variants")
+		return nil, utils.ErrInternalServer
+	}
+
+	if err := LoadPrimaryImages(ctx, uc.images, response); err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute").
+			Str("product_id", id).
+			Msg("Failed to load product image")
+		return nil, utils.ErrInternalServer
 	}
 
 	// Log de succès
@@ -93,3 +164,48 @@ func (uc *GetProductByIdUsecase) Execute(ctx context.Context, id string) (*dto.P
 
 	return response, nil
 }
+
+// load lit le produit dans le cache, sinon en base puis le met en cache.
+// Cache absent ou en erreur : lecture en base, la requête n'échoue pas.
+func (uc *GetProductByIdUsecase) load(ctx context.Context, id string) (*entity.Product, error) {
+	logger := zerolog.Ctx(ctx)
+
+	if uc.cache == nil {
+		return uc.repo.FindByID(ctx, id)
+	}
+
+	product, err := uc.cache.Get(ctx, id)
+	if err == nil {
+		metrics.ProductCacheHitsTotal.Inc()
+		return product, nil
+	}
+	metrics.ProductCacheMissesTotal.Inc()
+	if !errors.Is(err, repository.ErrCacheMiss) {
+		logger.Warn().
+			Err(err).
+			Str("operation", "cache_get").
+			Str("product_id", id).
+			Msg("Product cache unavailable, reading from database")
+	}
+
+	// La lecture partagée ne dépend pas de l'annulation de la première requête
+	loadCtx := context.WithoutCancel(ctx)
+	v, err, _ := uc.loads.Do(id, func() (interface{}, error) {
+		product, err := uc.repo.FindByID(loadCtx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := uc.cache.Set(loadCtx, product, ProductCacheTTLSeconds); err != nil {
+			logger.Warn().
+				Err(err).
+				Str("operation", "cache_set").
+				Str("product_id", id).
+				Msg("Failed to cache product")
+		}
+		return product, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*entity.Product), nil
+}
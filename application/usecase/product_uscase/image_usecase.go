@@ -0,0 +1,281 @@
+// application/usecase/product_uscase/image_usecase.go
+package productuscase
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	dto "Goshop/application/dto/product_dto"
+	"Goshop/domain/entity"
+	"Goshop/domain/repository"
+	"Goshop/domain/storage"
+	"Goshop/interfaces/utils"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// ImageUsecase gère les images d'un produit. Le contenu est écrit dans le
+// BlobStore avant la ligne product_images : une panne entre les deux laisse
+// au pire un fichier orphelin, jamais une image sans contenu.
+type ImageUsecase struct {
+	products  repository.ProductRepository
+	images    repository.ProductImageRepository
+	blobs     storage.BlobStore
+	txManager repository.TxManager
+	maxBytes  int64
+	newID     func() string
+}
+
+// NewImageUsecase : maxBytes <= 0 pour entity.DefaultMaxImageBytes
+func NewImageUsecase(
+	products repository.ProductRepository,
+	images repository.ProductImageRepository,
+	blobs storage.BlobStore,
+	txManager repository.TxManager,
+	maxBytes int64,
+) *ImageUsecase {
+	if maxBytes <= 0 {
+		maxBytes = entity.DefaultMaxImageBytes
+	}
+	return &ImageUsecase{
+		products:  products,
+		images:    images,
+		blobs:     blobs,
+		txManager: txManager,
+		maxBytes:  maxBytes,
+		newID:     uuid.NewString,
+	}
+}
+
+// MaxBytes : taille maximale d'une image acceptée par Upload
+func (uc *ImageUsecase) MaxBytes() int64 {
+	return uc.maxBytes
+}
+
+// List renvoie les images du produit, image principale en tête
+func (uc *ImageUsecase) List(ctx context.Context, productID string) ([]dto.ImageResponse, error) {
+	if _, err := uc.products.FindByID(ctx, productID); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Str("operation", "image_list").Str("product_id", productID).Msg("Product not found")
+		return nil, utils.ErrProductNotFound
+	}
+
+	images, err := uc.images.FindByProduct(ctx, productID)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Str("operation", "image_list").Str("product_id", productID).Msg("Failed to list images")
+		return nil, utils.ErrInternalServer
+	}
+	return toImageResponses(images), nil
+}
+
+// Upload ajoute une image en dernière position. Le type est détecté sur le
+// contenu : seuls JPEG, PNG, GIF et WebP sont acceptés.
+func (uc *ImageUsecase) Upload(ctx context.Context, productID string, data []byte) (*dto.ImageResponse, error) {
+	logger := zerolog.Ctx(ctx).With().Str("operation", "image_upload").Str("product_id", productID).Logger()
+
+	if len(data) == 0 {
+		return nil, utils.ErrImageMissing
+	}
+	if int64(len(data)) > uc.maxBytes {
+		return nil, utils.ErrImageTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := entity.ImageExtension(contentType)
+	if !ok {
+		logger.Warn().Str("detected_type", contentType).Msg("Unsupported image type")
+		return nil, utils.ErrImageUnsupportedType
+	}
+
+	// Produit inconnu : rien n'est écrit dans le stockage
+	if _, err := uc.products.FindByID(ctx, productID); err != nil {
+		logger.Warn().Err(err).Msg("Product not found")
+		return nil, utils.ErrProductNotFound
+	}
+
+	image := &entity.ProductImage{
+		ID:          uc.newID(),
+		ProductID:   productID,
+		ContentType: contentType,
+		SizeBytes:   int64(len(data)),
+	}
+	image.BlobKey = entity.ProductImageKey(productID, image.ID, ext)
+
+	if err := uc.blobs.Put(ctx, image.BlobKey, contentType, bytes.NewReader(data), image.SizeBytes); err != nil {
+		logger.Error().Err(err).Str("blob_key", image.BlobKey).Msg("Failed to store image")
+		return nil, utils.ErrImageUploadFail
+	}
+
+	err := uc.inProductTx(ctx, "image_upload", productID, func(images repository.ProductImageRepository) error {
+		existing, err := images.FindByProduct(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if len(existing) >= entity.MaxProductImages {
+			return utils.ErrImageLimitReached
+		}
+		return images.Create(ctx, image)
+	})
+	if err != nil {
+		DeleteBlobs(ctx, uc.blobs, image.BlobKey)
+		return nil, err
+	}
+
+	logger.Info().
+		Str("image_id", image.ID).
+		Str("content_type", contentType).
+		Int64("size_bytes", image.SizeBytes).
+		Int("position", image.Position).
+		Msg("Product image uploaded")
+
+	resp := dto.NewImageResponse(image)
+	return &resp, nil
+}
+
+// Open renvoie le contenu d'une image du produit ; Body à fermer
+func (uc *ImageUsecase) Open(ctx context.Context, productID, imageID string) (*storage.Blob, error) {
+	image, err := uc.images.FindByID(ctx, imageID)
+	if errors.Is(err, repository.ErrImageNotFound) || (err == nil && image.ProductID != productID) {
+		return nil, utils.ErrImageNotFound
+	}
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Str("operation", "image_open").Str("image_id", imageID).Msg("Failed to read image")
+		return nil, utils.ErrInternalServer
+	}
+
+	blob, err := uc.blobs.Get(ctx, image.BlobKey)
+	if errors.Is(err, storage.ErrBlobNotFound) {
+		zerolog.Ctx(ctx).Error().Str("operation", "image_open").Str("blob_key", image.BlobKey).Msg("Image blob missing from storage")
+		return nil, utils.ErrImageNotFound
+	}
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Str("operation", "image_open").Str("blob_key", image.BlobKey).Msg("Failed to read image blob")
+		return nil, utils.ErrInternalServer
+	}
+	if blob.ContentType == "" {
+		blob.ContentType = image.ContentType
+	}
+	return blob, nil
+}
+
+// Reorder fixe l'ordre des images ; la première devient l'image principale
+func (uc *ImageUsecase) Reorder(ctx context.Context, productID string, req dto.ReorderImagesRequest) ([]dto.ImageResponse, error) {
+	var reordered []*entity.ProductImage
+	err := uc.inProductTx(ctx, "image_reorder", productID, func(images repository.ProductImageRepository) error {
+		current, err := images.FindByProduct(ctx, productID)
+		if err != nil {
+			return err
+		}
+		byID := make(map[string]*entity.ProductImage, len(current))
+		for _, image := range current {
+			byID[image.ID] = image
+		}
+		if len(req.ImageIDs) != len(current) {
+			return utils.ErrImageInvalidOrder
+		}
+		for position, id := range req.ImageIDs {
+			image, ok := byID[id]
+			if !ok {
+				return utils.ErrImageInvalidOrder
+			}
+			image.Position = position
+			reordered = append(reordered, image)
+		}
+		return images.Reorder(ctx, productID, req.ImageIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("operation", "image_reorder").
+		Str("product_id", productID).
+		Str("primary_image_id", req.ImageIDs[0]).
+		Msg("Product images reordered")
+
+	return toImageResponses(reordered), nil
+}
+
+// Delete supprime l'image puis son contenu ; les images suivantes remontent
+func (uc *ImageUsecase) Delete(ctx context.Context, productID, imageID string) error {
+	var blobKey string
+	err := uc.inProductTx(ctx, "image_delete", productID, func(images repository.ProductImageRepository) error {
+		image, err := images.FindByID(ctx, imageID)
+		if err != nil {
+			return err
+		}
+		if image.ProductID != productID {
+			return repository.ErrImageNotFound
+		}
+		blobKey = image.BlobKey
+		return images.Delete(ctx, productID, imageID)
+	})
+	if err != nil {
+		return err
+	}
+
+	DeleteBlobs(ctx, uc.blobs, blobKey)
+
+	zerolog.Ctx(ctx).Info().
+		Str("operation", "image_delete").
+		Str("product_id", productID).
+		Str("image_id", imageID).
+		Msg("Product image deleted")
+
+	return nil
+}
+
+// inProductTx exécute fn sur le produit verrouillé puis valide la transaction
+func (uc *ImageUsecase) inProductTx(ctx context.Context, operation, productID string, fn func(repository.ProductImageRepository) error) error {
+	logger := zerolog.Ctx(ctx).With().Str("operation", operation).Str("product_id", productID).Logger()
+
+	tx, err := uc.txManager.BeginTx(ctx)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to begin transaction")
+		return utils.ErrTransactionBegin
+	}
+	// Rollback systématique : sans effet (sql.ErrTxDone) une fois le commit passé
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			logger.Error().Err(rollbackErr).Msg("Failed to rollback transaction")
+		}
+	}()
+
+	images := uc.images.WithTX(tx)
+
+	if err := images.LockProduct(ctx, productID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return utils.ErrProductNotFound
+		}
+		logger.Error().Err(err).Msg("Failed to lock product")
+		return utils.ErrInternalServer
+	}
+
+	if err := fn(images); err != nil {
+		var appErr *utils.AppError
+		switch {
+		case errors.As(err, &appErr):
+			return appErr
+		case errors.Is(err, repository.ErrImageNotFound):
+			return utils.ErrImageNotFound
+		}
+		logger.Error().Err(err).Msg("Product image write failed")
+		return utils.ErrInternalServer
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error().Err(err).Msg("Failed to commit transaction")
+		return utils.ErrTransactionCommit
+	}
+	return nil
+}
+
+func toImageResponses(images []*entity.ProductImage) []dto.ImageResponse {
+	responses := make([]dto.ImageResponse, 0, len(images))
+	for _, image := range images {
+		responses = append(responses, dto.NewImageResponse(image))
+	}
+	return responses
+}
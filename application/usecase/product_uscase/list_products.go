@@ -3,16 +3,23 @@ package productuscase
 
 import (
 	"context"
+	"errors"
 
+	paginationdto "Goshop/application/dto/pagination_dto"
 	dto "Goshop/application/dto/product_dto"
+	"Goshop/domain/entity"
 	"Goshop/domain/repository"
+	"Goshop/interfaces/utils"
 
 	"github.com/rs/zerolog"
 )
 
 type ListProductUsecase struct {
-	repo      repository.ProductRepository
-	txManager repository.TxManager
+	repo       repository.ProductRepository
+	txManager  repository.TxManager
+	categories repository.CategoryRepository
+	variants   repository.ProductVariantRepository
+	images     repository.ProductImageRepository
 	//logger    *setupLogging.Logger
 }
 
@@ -28,7 +35,28 @@ func NewListProductUsecase(
 	}
 }
 
-func (pruc *ListProductUsecase) Execute(ctx context.Context, limit, offset int) ([]*dto.ProductResponse, error) {
+// WithCategories ajoute les catégories de chaque produit aux réponses et
+// vérifie l'existence de la catégorie filtrée
+func (pruc *ListProductUsecase) WithCategories(categories repository.CategoryRepository) *ListProductUsecase {
+	pruc.categories = categories
+	return pruc
+}
+
+// WithVariants ajoute les variantes de chaque produit aux réponses
+func (pruc *ListProductUsecase) WithVariants(variants repository.ProductVariantRepository) *ListProductUsecase {
+	pruc.variants = variants
+	return pruc
+}
+
+// WithImages ajoute l'image principale de chaque produit aux réponses
+func (pruc *ListProductUsecase) WithImages(images repository.ProductImageRepository) *ListProductUsecase {
+	pruc.images = images
+	return pruc
+}
+
+// Execute renvoie une page de produits correspondant au filtre ainsi que le
+// nombre total de produits correspondants (pour calculer le nombre de pages).
+func (pruc *ListProductUsecase) Execute(ctx context.Context, limit, offset int, filter dto.ProductFilter) ([]*dto.ProductResponse, int, error) {
 	logger := zerolog.Ctx(ctx)
 	// ✅ Utilise pruc.logger directement — pas de .With().Logger()
 	logger.Debug().
@@ -37,7 +65,11 @@ func (pruc *ListProductUsecase) Execute(ctx context.Context, limit, offset int)
 		Int("offset", offset).
 		Msg("Executing list products use case")
 
-	products, err := pruc.repo.FindAll(ctx, limit, offset)
+	if err := pruc.checkCategory(ctx, filter); err != nil {
+		return nil, 0, err
+	}
+
+	products, err := pruc.repo.FindAll(ctx, limit, offset, filter)
 	if err != nil {
 		logger.Error().
 			Err(err).
@@ -45,7 +77,16 @@ func (pruc *ListProductUsecase) Execute(ctx context.Context, limit, offset int)
 			Int("limit", limit).
 			Int("offset", offset).
 			Msg("Failed to retrieve products from repository")
-		return nil, err
+		return nil, 0, err
+	}
+
+	total, err := pruc.repo.CountAll(ctx, filter)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute").
+			Msg("Failed to count products")
+		return nil, 0, err
 	}
 
 	logger.Debug().
@@ -53,6 +94,7 @@ func (pruc *ListProductUsecase) Execute(ctx context.Context, limit, offset int)
 		Int("limit", limit).
 		Int("offset", offset).
 		Int("product_count", len(products)).
+		Int("total", total).
 		Msg("Products retrieved from repository")
 
 	if len(products) == 0 {
@@ -61,20 +103,30 @@ func (pruc *ListProductUsecase) Execute(ctx context.Context, limit, offset int)
 			Int("limit", limit).
 			Int("offset", offset).
 			Msg("No products found")
-		return []*dto.ProductResponse{}, nil
+		return []*dto.ProductResponse{}, total, nil
 	}
 
-	responses := make([]*dto.ProductResponse, 0, len(products))
-	for _, product := range products {
-		responses = append(responses, &dto.ProductResponse{
-			ID:          product.ID,
-			Name:        product.Name,
-			Description: product.Description,
-			PriceCents:  product.PriceCents,
-			Stock:       product.Stock,
-			CreatedAt:   product.CreatedAt.Format("2006-01-02 15:04:05"),
-			UpdatedAt:   product.UpdatedAt.Format("2006-01-02 15:04:05"),
-		})
+	responses := toProductResponses(products)
+	if err := LoadProductCategories(ctx, pruc.categories, responses...); err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute").
+			Msg("Failed to load product categories")
+		return nil, 0, err
+	}
+	if err := LoadProductVariants(ctx, pruc.variants, responses...); err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute").
+			Msg("Failed to load product variants")
+		return nil, 0, err
+	}
+	if err := LoadPrimaryImages(ctx, pruc.images, responses...); err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute").
+			Msg("Failed to load product images")
+		return nil, 0, err
 	}
 
 	logger.Debug().
@@ -84,5 +136,94 @@ func (pruc *ListProductUsecase) Execute(ctx context.Context, limit, offset int)
 		Int("response_count", len(responses)).
 		Msg("Products converted to DTO")
 
-	return responses, nil
+	return responses, total, nil
+}
+
+// ExecuteCursor renvoie une page de produits après le curseur, triée par
+// (created_at DESC, id DESC). Le tri demandé dans le filtre est ignoré.
+func (pruc *ListProductUsecase) ExecuteCursor(ctx context.Context, page paginationdto.CursorPage, filter dto.ProductFilter) (*paginationdto.CursorResult[*dto.ProductResponse], error) {
+	logger := zerolog.Ctx(ctx)
+
+	if err := pruc.checkCategory(ctx, filter); err != nil {
+		return nil, err
+	}
+
+	// Un élément de plus pour savoir s'il reste une page
+	query := page
+	query.Limit = page.Limit + 1
+
+	products, err := pruc.repo.FindAllByCursor(ctx, query, filter)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute_cursor").
+			Int("limit", page.Limit).
+			Bool("backward", page.Backward).
+			Msg("Failed to retrieve products page from repository")
+		return nil, err
+	}
+
+	products, hasMore := paginationdto.TrimPage(products, page.Limit, page.Backward)
+
+	result := paginationdto.NewCursorResult(products, hasMore, func(p *entity.Product) paginationdto.Cursor {
+		return paginationdto.Cursor{CreatedAt: p.CreatedAt, ID: p.ID}
+	}, toProductResponses(products))
+
+	if err := LoadProductCategories(ctx, pruc.categories, result.Items...); err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute_cursor").
+			Msg("Failed to load product categories")
+		return nil, err
+	}
+	if err := LoadProductVariants(ctx, pruc.variants, result.Items...); err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute_cursor").
+			Msg("Failed to load product variants")
+		return nil, err
+	}
+	if err := LoadPrimaryImages(ctx, pruc.images, result.Items...); err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute_cursor").
+			Msg("Failed to load product images")
+		return nil, err
+	}
+
+	logger.Debug().
+		Str("operation", "execute_cursor").
+		Int("product_count", len(products)).
+		Bool("has_more", hasMore).
+		Msg("Products page retrieved")
+
+	return result, nil
+}
+
+// checkCategory : une catégorie inconnue est une 404, pas une liste vide
+func (pruc *ListProductUsecase) checkCategory(ctx context.Context, filter dto.ProductFilter) error {
+	if filter.CategorySlug == nil || pruc.categories == nil {
+		return nil
+	}
+	_, err := pruc.categories.FindBySlug(ctx, *filter.CategorySlug)
+	if errors.Is(err, repository.ErrCategoryNotFound) {
+		return utils.ErrCategoryNotFound
+	}
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("operation", "check_category").
+			Str("category", *filter.CategorySlug).
+			Msg("Failed to find category")
+		return err
+	}
+	return nil
+}
+
+func toProductResponses(products []*entity.Product) []*dto.ProductResponse {
+	responses := make([]*dto.ProductResponse, 0, len(products))
+	for _, product := range products {
+		responses = append(responses, dto.NewProductResponse(product))
+	}
+	return responses
 }
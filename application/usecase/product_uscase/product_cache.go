@@ -0,0 +1,32 @@
+// application/usecase/product_uscase/product_cache.go
+package productuscase
+
+import (
+	"context"
+
+	"Goshop/domain/repository"
+
+	"github.com/rs/zerolog"
+)
+
+// ProductCacheTTLSeconds : durée de vie d'un produit en cache. Les écritures
+// invalident l'entrée ; le TTL borne la péremption d'une lecture concurrente
+// à une mise à jour qui réécrirait l'ancienne valeur.
+const ProductCacheTTLSeconds = 300
+
+// InvalidateProducts retire les produits du cache après une écriture
+// committée. Un échec est seulement logué : l'entrée expirera d'elle-même.
+func InvalidateProducts(ctx context.Context, cache repository.ProductCache, ids ...string) {
+	if cache == nil {
+		return
+	}
+	for _, id := range ids {
+		if err := cache.Delete(ctx, id); err != nil {
+			zerolog.Ctx(ctx).Warn().
+				Err(err).
+				Str("operation", "invalidate_cache").
+				Str("product_id", id).
+				Msg("Failed to invalidate product cache entry")
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package productuscase_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	productuscase "Goshop/application/usecase/product_uscase"
+	"Goshop/domain/entity"
+	domainrepo "Goshop/domain/repository"
+	"Goshop/interfaces/utils"
+	"Goshop/mocks/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func cachedTestProduct() *entity.Product {
+	return &entity.Product{ID: "p-123", Name: "MacBook Pro", PriceCents: 350000, Stock: 5}
+}
+
+func TestGetProductByIdUsecase_CacheHit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockProductRepository(ctrl)
+	mockCache := repository.NewMockProductCache(ctrl)
+
+	// Pas d'accès à la base sur un hit
+	mockCache.EXPECT().Get(gomock.Any(), "p-123").Return(cachedTestProduct(), nil)
+
+	uc := productuscase.NewGetProductByIdUsecase(mockRepo, repository.NewMockTxManager(ctrl)).WithCache(mockCache)
+	result, err := uc.Execute(context.Background(), "p-123")
+
+	require.NoError(t, err)
+	assert.Equal(t, "MacBook Pro", result.Name)
+}
+
+func TestGetProductByIdUsecase_CacheMissPopulatesCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockProductRepository(ctrl)
+	mockCache := repository.NewMockProductCache(ctrl)
+
+	product := cachedTestProduct()
+	mockCache.EXPECT().Get(gomock.Any(), "p-123").Return(nil, domainrepo.ErrCacheMiss)
+	mockRepo.EXPECT().FindByID(gomock.Any(), "p-123").Return(product, nil)
+	mockCache.EXPECT().Set(gomock.Any(), product, productuscase.ProductCacheTTLSeconds).Return(nil)
+
+	uc := productuscase.NewGetProductByIdUsecase(mockRepo, repository.NewMockTxManager(ctrl)).WithCache(mockCache)
+	result, err := uc.Execute(context.Background(), "p-123")
+
+	require.NoError(t, err)
+	assert.Equal(t, "p-123", result.ID)
+}
+
+func TestGetProductByIdUsecase_CacheDownFallsBackToDatabase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockProductRepository(ctrl)
+	mockCache := repository.NewMockProductCache(ctrl)
+
+	redisDown := errors.New("dial tcp: connection refused")
+	mockCache.EXPECT().Get(gomock.Any(), "p-123").Return(nil, redisDown)
+	mockRepo.EXPECT().FindByID(gomock.Any(), "p-123").Return(cachedTestProduct(), nil)
+	mockCache.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any()).Return(redisDown)
+
+	uc := productuscase.NewGetProductByIdUsecase(mockRepo, repository.NewMockTxManager(ctrl)).WithCache(mockCache)
+	result, err := uc.Execute(context.Background(), "p-123")
+
+	require.NoError(t, err)
+	assert.Equal(t, "p-123", result.ID)
+}
+
+func TestGetProductByIdUsecase_NotFoundIsNotCached(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockProductRepository(ctrl)
+	mockCache := repository.NewMockProductCache(ctrl)
+
+	mockCache.EXPECT().Get(gomock.Any(), "404").Return(nil, domainrepo.ErrCacheMiss)
+	mockRepo.EXPECT().FindByID(gomock.Any(), "404").Return(nil, errors.New("not found"))
+
+	uc := productuscase.NewGetProductByIdUsecase(mockRepo, repository.NewMockTxManager(ctrl)).WithCache(mockCache)
+	_, err := uc.Execute(context.Background(), "404")
+
+	assert.ErrorIs(t, err, utils.ErrProductNotFound)
+}
+
+func TestGetProductByIdUsecase_SingleFlight(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockProductRepository(ctrl)
+	mockCache := repository.NewMockProductCache(ctrl)
+
+	const readers = 20
+	var arrived sync.WaitGroup
+	arrived.Add(readers)
+
+	mockCache.EXPECT().Get(gomock.Any(), "p-123").
+		DoAndReturn(func(context.Context, string) (*entity.Product, error) {
+			arrived.Done()
+			return nil, domainrepo.ErrCacheMiss
+		}).Times(readers)
+	// Une seule lecture en base pour toutes les requêtes concurrentes
+	mockRepo.EXPECT().FindByID(gomock.Any(), "p-123").
+		DoAndReturn(func(context.Context, string) (*entity.Product, error) {
+			arrived.Wait()
+			time.Sleep(10 * time.Millisecond)
+			return cachedTestProduct(), nil
+		}).Times(1)
+	mockCache.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	uc := productuscase.NewGetProductByIdUsecase(mockRepo, repository.NewMockTxManager(ctrl)).WithCache(mockCache)
+
+	var wg sync.WaitGroup
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := uc.Execute(context.Background(), "p-123")
+			assert.NoError(t, err)
+			assert.Equal(t, "p-123", result.ID)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestUpdateProductUsecase_InvalidatesCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockProductRepository(ctrl)
+	mockTxManager := repository.NewMockTxManager(ctrl)
+	mockTx := repository.NewMockTx(ctrl)
+	mockRepoWithTx := repository.NewMockProductRepository(ctrl)
+	mockCache := repository.NewMockProductCache(ctrl)
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTX(mockTx).Return(mockRepoWithTx)
+	mockRepoWithTx.EXPECT().FindByID(gomock.Any(), "p-123").Return(cachedTestProduct(), nil)
+	mockRepoWithTx.EXPECT().Update(gomock.Any(), gomock.Any()).Return(cachedTestProduct(), nil)
+	// Invalidation après le commit seulement
+	gomock.InOrder(
+		mockTx.EXPECT().Commit().Return(nil),
+		mockCache.EXPECT().Delete(gomock.Any(), "p-123").Return(nil),
+	)
+
+	uc := productuscase.NewUpdateProductUsecase(mockRepo, mockTxManager).WithCache(mockCache)
+	_, err := uc.Execute(context.Background(), &entity.Product{ID: "p-123", Name: "MacBook Air", PriceCents: 150000, Stock: 3})
+
+	assert.NoError(t, err)
+}
+
+func TestDeleteProductUsecase_InvalidatesCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockProductRepository(ctrl)
+	mockTxManager := repository.NewMockTxManager(ctrl)
+	mockTx := repository.NewMockTx(ctrl)
+	mockRepoWithTx := repository.NewMockProductRepository(ctrl)
+	mockCache := repository.NewMockProductCache(ctrl)
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTX(mockTx).Return(mockRepoWithTx)
+	mockRepoWithTx.EXPECT().FindByID(gomock.Any(), "p-123").Return(cachedTestProduct(), nil)
+	mockRepoWithTx.EXPECT().Delete(gomock.Any(), "p-123").Return(nil)
+	gomock.InOrder(
+		mockTx.EXPECT().Commit().Return(nil),
+		// Un échec d'invalidation ne fait pas échouer la suppression
+		mockCache.EXPECT().Delete(gomock.Any(), "p-123").Return(errors.New("redis down")),
+	)
+
+	uc := productuscase.NewDeleteProductUsecase(mockRepo, mockTxManager).WithCache(mockCache)
+
+	assert.NoError(t, uc.Execute(context.Background(), "p-123"))
+}
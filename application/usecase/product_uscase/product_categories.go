@@ -0,0 +1,83 @@
+// application/usecase/product_uscase/product_categories.go
+package productuscase
+
+import (
+	"context"
+	"errors"
+
+	dto "Goshop/application/dto/product_dto"
+	"Goshop/domain/repository"
+	"Goshop/interfaces/utils"
+)
+
+// LoadProductCategories complète les réponses avec les catégories de chaque
+// produit et leur fil d'Ariane, en une seule requête. Sans dépôt de
+// catégories (nil), les réponses sont laissées telles quelles.
+func LoadProductCategories(ctx context.Context, categories repository.CategoryRepository, responses ...*dto.ProductResponse) error {
+	if categories == nil || len(responses) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(responses))
+	for _, r := range responses {
+		ids = append(ids, r.ID)
+	}
+
+	links, err := categories.FindProductCategories(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	byProduct := make(map[string][]dto.ProductCategoryResponse, len(responses))
+	for _, link := range links {
+		byProduct[link.ProductID] = append(byProduct[link.ProductID], dto.ProductCategoryResponse{
+			ID:         link.Category.ID,
+			Name:       link.Category.Name,
+			Slug:       link.Category.Slug,
+			Breadcrumb: dto.NewCategoryRefs(link.Breadcrumb),
+		})
+	}
+	for _, r := range responses {
+		r.Categories = byProduct[r.ID]
+	}
+	return nil
+}
+
+// setProductCategories remplace les catégories d'un produit après avoir
+// vérifié qu'elles existent toutes (doublons ignorés)
+func setProductCategories(ctx context.Context, categories repository.CategoryRepository, productID string, categoryIDs []string) error {
+	ids := make([]string, 0, len(categoryIDs))
+	seen := make(map[string]bool, len(categoryIDs))
+	for _, id := range categoryIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) > 0 {
+		if categories == nil {
+			return utils.ErrProductInvalidCategory
+		}
+		found, err := categories.FindByIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		if len(found) != len(ids) {
+			return utils.ErrProductInvalidCategory
+		}
+	}
+	if categories == nil {
+		return nil
+	}
+	return categories.SetProductCategories(ctx, productID, ids)
+}
+
+// categoryErr conserve les erreurs métier et masque les erreurs techniques
+func categoryErr(err, fallback error) error {
+	var appErr *utils.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return fallback
+}
@@ -0,0 +1,115 @@
+package productuscase_test
+
+import (
+	"context"
+	"testing"
+
+	dto "Goshop/application/dto/product_dto"
+	productuscase "Goshop/application/usecase/product_uscase"
+	"Goshop/domain/entity"
+	domainrepo "Goshop/domain/repository"
+	"Goshop/interfaces/utils"
+	"Goshop/mocks/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	electronicsCategory = &entity.Category{ID: "cat-1", Name: "Électronique", Slug: "electronique", Path: "electronique"}
+	laptopsCategory     = &entity.Category{ID: "cat-2", Name: "Portables", Slug: "portables", ParentID: "cat-1", Path: "electronique/portables"}
+)
+
+func TestLoadProductCategories(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockCategories := repository.NewMockCategoryRepository(ctrl)
+
+	mockCategories.EXPECT().FindProductCategories(gomock.Any(), []string{"p-1", "p-2"}).Return([]*entity.ProductCategory{
+		{ProductID: "p-1", Category: laptopsCategory, Breadcrumb: []*entity.Category{electronicsCategory, laptopsCategory}},
+	}, nil)
+
+	responses := []*dto.ProductResponse{{ID: "p-1"}, {ID: "p-2"}}
+	require.NoError(t, productuscase.LoadProductCategories(context.Background(), mockCategories, responses...))
+
+	require.Len(t, responses[0].Categories, 1)
+	assert.Equal(t, "portables", responses[0].Categories[0].Slug)
+	assert.Equal(t, []dto.CategoryRef{
+		{ID: "cat-1", Name: "Électronique", Slug: "electronique"},
+		{ID: "cat-2", Name: "Portables", Slug: "portables"},
+	}, responses[0].Categories[0].Breadcrumb)
+	assert.Empty(t, responses[1].Categories)
+}
+
+func TestCreateProductUsecase_LinksCategories(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockProductRepository(ctrl)
+	mockRepoTx := repository.NewMockProductRepository(ctrl)
+	mockCategories := repository.NewMockCategoryRepository(ctrl)
+	mockCategoriesTx := repository.NewMockCategoryRepository(ctrl)
+	mockTxManager := repository.NewMockTxManager(ctrl)
+	mockTx := repository.NewMockTx(ctrl)
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTX(mockTx).Return(mockRepoTx)
+	mockRepoTx.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, p *entity.Product) error {
+			p.ID = "p-1"
+			return nil
+		})
+	mockCategories.EXPECT().WithTX(mockTx).Return(mockCategoriesTx)
+	// Doublon ignoré
+	mockCategoriesTx.EXPECT().FindByIDs(gomock.Any(), []string{"cat-2"}).Return([]*entity.Category{laptopsCategory}, nil)
+	mockCategoriesTx.EXPECT().SetProductCategories(gomock.Any(), "p-1", []string{"cat-2"}).Return(nil)
+	mockTx.EXPECT().Commit().Return(nil)
+	mockCategories.EXPECT().FindProductCategories(gomock.Any(), []string{"p-1"}).Return([]*entity.ProductCategory{
+		{ProductID: "p-1", Category: laptopsCategory, Breadcrumb: []*entity.Category{electronicsCategory, laptopsCategory}},
+	}, nil)
+
+	uc := productuscase.NewCreateProductUsecase(mockRepo, mockTxManager).WithCategories(mockCategories)
+	resp, err := uc.Execute(context.Background(), dto.CreateProductRequest{
+		Name: "MacBook Air", PriceCents: 150000, Stock: 3, CategoryIDs: []string{"cat-2", "cat-2"},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Categories, 1)
+	assert.Len(t, resp.Categories[0].Breadcrumb, 2)
+}
+
+func TestCreateProductUsecase_UnknownCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockProductRepository(ctrl)
+	mockRepoTx := repository.NewMockProductRepository(ctrl)
+	mockCategories := repository.NewMockCategoryRepository(ctrl)
+	mockCategoriesTx := repository.NewMockCategoryRepository(ctrl)
+	mockTxManager := repository.NewMockTxManager(ctrl)
+	mockTx := repository.NewMockTx(ctrl)
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTX(mockTx).Return(mockRepoTx)
+	mockRepoTx.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	mockCategories.EXPECT().WithTX(mockTx).Return(mockCategoriesTx)
+	mockCategoriesTx.EXPECT().FindByIDs(gomock.Any(), []string{"cat-2", "nope"}).Return([]*entity.Category{laptopsCategory}, nil)
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	uc := productuscase.NewCreateProductUsecase(mockRepo, mockTxManager).WithCategories(mockCategories)
+	_, err := uc.Execute(context.Background(), dto.CreateProductRequest{
+		Name: "MacBook Air", PriceCents: 150000, Stock: 3, CategoryIDs: []string{"cat-2", "nope"},
+	})
+
+	assert.ErrorIs(t, err, utils.ErrProductInvalidCategory)
+}
+
+func TestListProductUsecase_UnknownCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockCategories := repository.NewMockCategoryRepository(ctrl)
+
+	mockCategories.EXPECT().FindBySlug(gomock.Any(), "inconnue").Return(nil, domainrepo.ErrCategoryNotFound)
+
+	uc := productuscase.NewListProductUsecase(repository.NewMockProductRepository(ctrl), repository.NewMockTxManager(ctrl)).
+		WithCategories(mockCategories)
+	slug := "inconnue"
+	_, _, err := uc.Execute(context.Background(), 10, 0, dto.ProductFilter{CategorySlug: &slug})
+
+	assert.ErrorIs(t, err, utils.ErrCategoryNotFound)
+}
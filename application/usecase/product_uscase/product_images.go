@@ -0,0 +1,60 @@
+// application/usecase/product_uscase/product_images.go
+package productuscase
+
+import (
+	"context"
+
+	dto "Goshop/application/dto/product_dto"
+	"Goshop/domain/repository"
+	"Goshop/domain/storage"
+
+	"github.com/rs/zerolog"
+)
+
+// LoadPrimaryImages complète les réponses avec l'image principale de chaque
+// produit, en une seule requête. Sans dépôt d'images (nil), les réponses
+// sont laissées telles quelles.
+func LoadPrimaryImages(ctx context.Context, images repository.ProductImageRepository, responses ...*dto.ProductResponse) error {
+	if images == nil || len(responses) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(responses))
+	byID := make(map[string]*dto.ProductResponse, len(responses))
+	for _, r := range responses {
+		ids = append(ids, r.ID)
+		byID[r.ID] = r
+		r.PrimaryImage = nil
+	}
+
+	found, err := images.FindPrimary(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, image := range found {
+		if r, ok := byID[image.ProductID]; ok {
+			resp := dto.NewImageResponse(image)
+			r.PrimaryImage = &resp
+		}
+	}
+	return nil
+}
+
+// DeleteBlobs supprime des contenus après le commit de la suppression de
+// leurs images. Un échec est seulement logué : le fichier reste orphelin,
+// sans être référencé.
+func DeleteBlobs(ctx context.Context, blobs storage.BlobStore, keys ...string) {
+	if blobs == nil {
+		return
+	}
+	for _, key := range keys {
+		if err := blobs.Delete(ctx, key); err != nil {
+			zerolog.Ctx(ctx).Warn().
+				Err(err).
+				Str("operation", "delete_blob").
+				Str("blob_key", key).
+				Msg("Failed to delete image blob")
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package productuscase_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	dto "Goshop/application/dto/product_dto"
+	productuscase "Goshop/application/usecase/product_uscase"
+	"Goshop/domain/entity"
+	"Goshop/domain/storage"
+	"Goshop/interfaces/utils"
+	"Goshop/mocks/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var pngImage = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDRfake")
+
+// memBlobs : BlobStore en mémoire
+type memBlobs struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	putErr  error
+}
+
+func newMemBlobs() *memBlobs {
+	return &memBlobs{objects: map[string][]byte{}}
+}
+
+func (m *memBlobs) Put(_ context.Context, key, _ string, body io.Reader, _ int64) error {
+	if m.putErr != nil {
+		return m.putErr
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[key] = data
+	return nil
+}
+
+func (m *memBlobs) Get(_ context.Context, key string) (*storage.Blob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, storage.ErrBlobNotFound
+	}
+	return &storage.Blob{Body: io.NopCloser(bytes.NewReader(data)), Size: int64(len(data))}, nil
+}
+
+func (m *memBlobs) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, key)
+	return nil
+}
+
+type imageMocks struct {
+	products  *repository.MockProductRepository
+	images    *repository.MockProductImageRepository
+	imagesTx  *repository.MockProductImageRepository
+	txManager *repository.MockTxManager
+	tx        *repository.MockTx
+	blobs     *memBlobs
+}
+
+func newImageMocks(t *testing.T) *imageMocks {
+	ctrl := gomock.NewController(t)
+	return &imageMocks{
+		products:  repository.NewMockProductRepository(ctrl),
+		images:    repository.NewMockProductImageRepository(ctrl),
+		imagesTx:  repository.NewMockProductImageRepository(ctrl),
+		txManager: repository.NewMockTxManager(ctrl),
+		tx:        repository.NewMockTx(ctrl),
+		blobs:     newMemBlobs(),
+	}
+}
+
+func (m *imageMocks) usecase() *productuscase.ImageUsecase {
+	return productuscase.NewImageUsecase(m.products, m.images, m.blobs, m.txManager, 64)
+}
+
+// expectTx : transaction ouverte et produit verrouillé
+func (m *imageMocks) expectTx(productID string) {
+	m.txManager.EXPECT().BeginTx(gomock.Any()).Return(m.tx, nil)
+	m.images.EXPECT().WithTX(m.tx).Return(m.imagesTx)
+	m.imagesTx.EXPECT().LockProduct(gomock.Any(), productID).Return(nil)
+	m.tx.EXPECT().Rollback().Return(sql.ErrTxDone).AnyTimes()
+}
+
+func TestImageUsecase_Upload(t *testing.T) {
+	m := newImageMocks(t)
+	m.products.EXPECT().FindByID(gomock.Any(), "p-1").Return(&entity.Product{ID: "p-1"}, nil)
+	m.expectTx("p-1")
+	m.imagesTx.EXPECT().FindByProduct(gomock.Any(), "p-1").Return([]*entity.ProductImage{{ID: "img-0"}}, nil)
+	m.imagesTx.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, image *entity.ProductImage) error {
+			assert.Equal(t, "image/png", image.ContentType)
+			assert.Equal(t, int64(len(pngImage)), image.SizeBytes)
+			assert.Equal(t, "products/p-1/"+image.ID+".png", image.BlobKey)
+			image.Position = 1
+			return nil
+		})
+	m.tx.EXPECT().Commit().Return(nil)
+
+	resp, err := m.usecase().Upload(context.Background(), "p-1", pngImage)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.Position)
+	assert.Equal(t, "/api/products/p-1/images/"+resp.ID, resp.URL)
+	assert.Equal(t, pngImage, m.blobs.objects["products/p-1/"+resp.ID+".png"])
+}
+
+func TestImageUsecase_UploadRejected(t *testing.T) {
+	m := newImageMocks(t)
+	uc := m.usecase()
+
+	// Refusés avant toute lecture en base
+	_, err := uc.Upload(context.Background(), "p-1", []byte("<svg xmlns='http://www.w3.org/2000/svg'></svg>"))
+	assert.ErrorIs(t, err, utils.ErrImageUnsupportedType)
+
+	_, err = uc.Upload(context.Background(), "p-1", append(pngImage, make([]byte, 64)...))
+	assert.ErrorIs(t, err, utils.ErrImageTooLarge)
+
+	_, err = uc.Upload(context.Background(), "p-1", nil)
+	assert.ErrorIs(t, err, utils.ErrImageMissing)
+
+	// Produit inconnu : rien n'est stocké
+	m.products.EXPECT().FindByID(gomock.Any(), "p-9").Return(nil, sql.ErrNoRows)
+	_, err = uc.Upload(context.Background(), "p-9", pngImage)
+	assert.ErrorIs(t, err, utils.ErrProductNotFound)
+	assert.Empty(t, m.blobs.objects)
+}
+
+func TestImageUsecase_UploadRemovesBlobOnFailure(t *testing.T) {
+	m := newImageMocks(t)
+	m.products.EXPECT().FindByID(gomock.Any(), "p-1").Return(&entity.Product{ID: "p-1"}, nil).Times(2)
+
+	// Limite atteinte
+	full := make([]*entity.ProductImage, entity.MaxProductImages)
+	m.expectTx("p-1")
+	m.imagesTx.EXPECT().FindByProduct(gomock.Any(), "p-1").Return(full, nil)
+
+	_, err := m.usecase().Upload(context.Background(), "p-1", pngImage)
+	assert.ErrorIs(t, err, utils.ErrImageLimitReached)
+	assert.Empty(t, m.blobs.objects)
+
+	// Échec de l'insertion
+	m.expectTx("p-1")
+	m.imagesTx.EXPECT().FindByProduct(gomock.Any(), "p-1").Return(nil, nil)
+	m.imagesTx.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("connection reset"))
+
+	_, err = m.usecase().Upload(context.Background(), "p-1", pngImage)
+	assert.ErrorIs(t, err, utils.ErrInternalServer)
+	assert.Empty(t, m.blobs.objects)
+}
+
+func TestImageUsecase_UploadStorageFailure(t *testing.T) {
+	m := newImageMocks(t)
+	m.blobs.putErr = errors.New("bucket unreachable")
+	m.products.EXPECT().FindByID(gomock.Any(), "p-1").Return(&entity.Product{ID: "p-1"}, nil)
+
+	_, err := m.usecase().Upload(context.Background(), "p-1", pngImage)
+	assert.ErrorIs(t, err, utils.ErrImageUploadFail)
+}
+
+func TestImageUsecase_Open(t *testing.T) {
+	m := newImageMocks(t)
+	m.blobs.objects["products/p-1/img-1.png"] = pngImage
+	image := &entity.ProductImage{ID: "img-1", ProductID: "p-1", BlobKey: "products/p-1/img-1.png", ContentType: "image/png"}
+	m.images.EXPECT().FindByID(gomock.Any(), "img-1").Return(image, nil).Times(2)
+	uc := m.usecase()
+
+	blob, err := uc.Open(context.Background(), "p-1", "img-1")
+	require.NoError(t, err)
+	defer blob.Body.Close()
+	assert.Equal(t, "image/png", blob.ContentType)
+
+	// Image d'un autre produit
+	_, err = uc.Open(context.Background(), "p-2", "img-1")
+	assert.ErrorIs(t, err, utils.ErrImageNotFound)
+}
+
+func TestImageUsecase_Reorder(t *testing.T) {
+	current := []*entity.ProductImage{
+		{ID: "img-1", ProductID: "p-1", Position: 0},
+		{ID: "img-2", ProductID: "p-1", Position: 1},
+	}
+
+	m := newImageMocks(t)
+	m.expectTx("p-1")
+	m.imagesTx.EXPECT().FindByProduct(gomock.Any(), "p-1").Return(current, nil)
+	m.imagesTx.EXPECT().Reorder(gomock.Any(), "p-1", []string{"img-2", "img-1"}).Return(nil)
+	m.tx.EXPECT().Commit().Return(nil)
+
+	resp, err := m.usecase().Reorder(context.Background(), "p-1", dto.ReorderImagesRequest{ImageIDs: []string{"img-2", "img-1"}})
+	require.NoError(t, err)
+	require.Len(t, resp, 2)
+	assert.Equal(t, "img-2", resp[0].ID)
+	assert.Equal(t, 0, resp[0].Position)
+}
+
+func TestImageUsecase_ReorderInvalid(t *testing.T) {
+	current := []*entity.ProductImage{
+		{ID: "img-1", ProductID: "p-1", Position: 0},
+		{ID: "img-2", ProductID: "p-1", Position: 1},
+	}
+
+	// Liste incomplète, image inconnue : rien n'est modifié
+	for _, ids := range [][]string{{"img-1"}, {"img-1", "img-9"}} {
+		m := newImageMocks(t)
+		m.expectTx("p-1")
+		m.imagesTx.EXPECT().FindByProduct(gomock.Any(), "p-1").Return(current, nil)
+
+		_, err := m.usecase().Reorder(context.Background(), "p-1", dto.ReorderImagesRequest{ImageIDs: ids})
+		assert.ErrorIs(t, err, utils.ErrImageInvalidOrder, ids)
+	}
+}
+
+func TestImageUsecase_Delete(t *testing.T) {
+	m := newImageMocks(t)
+	m.blobs.objects["products/p-1/img-1.png"] = pngImage
+	m.expectTx("p-1")
+	m.imagesTx.EXPECT().FindByID(gomock.Any(), "img-1").
+		Return(&entity.ProductImage{ID: "img-1", ProductID: "p-1", BlobKey: "products/p-1/img-1.png"}, nil)
+	m.imagesTx.EXPECT().Delete(gomock.Any(), "p-1", "img-1").Return(nil)
+	m.tx.EXPECT().Commit().Return(nil)
+
+	require.NoError(t, m.usecase().Delete(context.Background(), "p-1", "img-1"))
+	assert.Empty(t, m.blobs.objects)
+}
+
+func TestImageUsecase_DeleteOtherProduct(t *testing.T) {
+	m := newImageMocks(t)
+	m.blobs.objects["products/p-2/img-1.png"] = pngImage
+	m.expectTx("p-1")
+	m.imagesTx.EXPECT().FindByID(gomock.Any(), "img-1").
+		Return(&entity.ProductImage{ID: "img-1", ProductID: "p-2", BlobKey: "products/p-2/img-1.png"}, nil)
+
+	err := m.usecase().Delete(context.Background(), "p-1", "img-1")
+	assert.ErrorIs(t, err, utils.ErrImageNotFound)
+	assert.Len(t, m.blobs.objects, 1)
+}
+
+func TestLoadPrimaryImages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockImages := repository.NewMockProductImageRepository(ctrl)
+	mockImages.EXPECT().FindPrimary(gomock.Any(), []string{"p-1", "p-2"}).Return([]*entity.ProductImage{
+		{ID: "img-1", ProductID: "p-1", ContentType: "image/png", Position: 0},
+	}, nil)
+
+	responses := []*dto.ProductResponse{{ID: "p-1"}, {ID: "p-2"}}
+	require.NoError(t, productuscase.LoadPrimaryImages(context.Background(), mockImages, responses...))
+
+	require.NotNil(t, responses[0].PrimaryImage)
+	assert.Equal(t, "/api/products/p-1/images/img-1", responses[0].PrimaryImage.URL)
+	assert.Nil(t, responses[1].PrimaryImage)
+}
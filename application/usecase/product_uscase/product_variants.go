@@ -0,0 +1,77 @@
+// application/usecase/product_uscase/product_variants.go
+package productuscase
+
+import (
+	"context"
+	"errors"
+
+	dto "Goshop/application/dto/product_dto"
+	"Goshop/domain/entity"
+	"Goshop/domain/repository"
+	"Goshop/interfaces/utils"
+)
+
+// LoadProductVariants complète les réponses avec les variantes de chaque
+// produit, en une seule requête. Sans dépôt de variantes (nil), les réponses
+// sont laissées telles quelles.
+func LoadProductVariants(ctx context.Context, variants repository.ProductVariantRepository, responses ...*dto.ProductResponse) error {
+	if variants == nil || len(responses) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(responses))
+	byID := make(map[string]*dto.ProductResponse, len(responses))
+	for _, r := range responses {
+		ids = append(ids, r.ID)
+		byID[r.ID] = r
+		r.Variants = nil
+	}
+
+	found, err := variants.FindByProducts(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range found {
+		if r, ok := byID[v.ProductID]; ok {
+			r.Variants = append(r.Variants, dto.NewVariantResponse(v, r.PriceCents))
+		}
+	}
+	return nil
+}
+
+// createDefaultVariant crée la variante par défaut d'un nouveau produit, qui
+// porte tout son stock
+func createDefaultVariant(ctx context.Context, variants repository.ProductVariantRepository, product *entity.Product, sku string) (*entity.ProductVariant, error) {
+	if sku == "" {
+		sku = entity.DefaultVariantSKU(product.ID)
+	}
+
+	variant := &entity.ProductVariant{
+		ProductID: product.ID,
+		SKU:       sku,
+		Options:   map[string]string{},
+		Stock:     product.Stock,
+		IsDefault: true,
+	}
+	if err := variants.Create(ctx, variant); err != nil {
+		return nil, err
+	}
+	return variant, nil
+}
+
+// variantErr traduit les erreurs du dépôt de variantes en erreurs métier ;
+// les erreurs techniques sont remplacées par fallback
+func variantErr(err, fallback error) error {
+	switch {
+	case errors.Is(err, repository.ErrVariantNotFound):
+		return utils.ErrVariantNotFound
+	case errors.Is(err, repository.ErrVariantSKUTaken):
+		return utils.ErrVariantSKUTaken
+	case errors.Is(err, repository.ErrVariantOptionsTaken):
+		return utils.ErrVariantOptionsTaken
+	case errors.Is(err, repository.ErrVariantInUse):
+		return utils.ErrVariantInUse
+	}
+	return categoryErr(err, fallback)
+}
@@ -0,0 +1,219 @@
+package productuscase_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	dto "Goshop/application/dto/product_dto"
+	productuscase "Goshop/application/usecase/product_uscase"
+	"Goshop/domain/entity"
+	domainrepo "Goshop/domain/repository"
+	"Goshop/interfaces/utils"
+	"Goshop/mocks/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLoadProductVariants(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockVariants := repository.NewMockProductVariantRepository(ctrl)
+
+	override := int64(2500)
+	mockVariants.EXPECT().FindByProducts(gomock.Any(), []string{"p-1", "p-2"}).Return([]*entity.ProductVariant{
+		{ID: "v-1", ProductID: "p-1", SKU: "SKU-p-1", Options: map[string]string{}, Stock: 3, IsDefault: true},
+		{ID: "v-2", ProductID: "p-1", SKU: "TSHIRT-L", Options: map[string]string{"size": "L"}, PriceCents: &override, Stock: 2},
+	}, nil)
+
+	responses := []*dto.ProductResponse{{ID: "p-1", PriceCents: 2000}, {ID: "p-2", PriceCents: 900}}
+	require.NoError(t, productuscase.LoadProductVariants(context.Background(), mockVariants, responses...))
+
+	require.Len(t, responses[0].Variants, 2)
+	assert.True(t, responses[0].Variants[0].IsDefault)
+	assert.Equal(t, int64(2000), responses[0].Variants[0].PriceCents)
+	assert.Equal(t, int64(2500), responses[0].Variants[1].PriceCents)
+	assert.Empty(t, responses[1].Variants)
+}
+
+func TestCreateProductUsecase_CreatesDefaultVariant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockProductRepository(ctrl)
+	mockRepoTx := repository.NewMockProductRepository(ctrl)
+	mockVariants := repository.NewMockProductVariantRepository(ctrl)
+	mockVariantsTx := repository.NewMockProductVariantRepository(ctrl)
+	mockTxManager := repository.NewMockTxManager(ctrl)
+	mockTx := repository.NewMockTx(ctrl)
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTX(mockTx).Return(mockRepoTx)
+	mockRepoTx.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, p *entity.Product) error {
+			p.ID = "p-1"
+			return nil
+		})
+	mockVariants.EXPECT().WithTX(mockTx).Return(mockVariantsTx)
+	mockVariantsTx.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, v *entity.ProductVariant) error {
+			assert.Equal(t, "p-1", v.ProductID)
+			assert.Equal(t, "TSHIRT", v.SKU)
+			assert.Equal(t, 7, v.Stock)
+			assert.True(t, v.IsDefault)
+			v.ID = "v-1"
+			return nil
+		})
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Rollback().Return(sql.ErrTxDone).AnyTimes()
+
+	uc := productuscase.NewCreateProductUsecase(mockRepo, mockTxManager).WithVariants(mockVariants)
+	resp, err := uc.Execute(context.Background(), dto.CreateProductRequest{
+		Name: "T-shirt", PriceCents: 2000, Stock: 7, SKU: "TSHIRT",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Variants, 1)
+	assert.Equal(t, "v-1", resp.Variants[0].ID)
+	assert.Equal(t, int64(2000), resp.Variants[0].PriceCents)
+}
+
+func TestCreateProductUsecase_DefaultVariantSKUTaken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockProductRepository(ctrl)
+	mockRepoTx := repository.NewMockProductRepository(ctrl)
+	mockVariants := repository.NewMockProductVariantRepository(ctrl)
+	mockVariantsTx := repository.NewMockProductVariantRepository(ctrl)
+	mockTxManager := repository.NewMockTxManager(ctrl)
+	mockTx := repository.NewMockTx(ctrl)
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTX(mockTx).Return(mockRepoTx)
+	mockRepoTx.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	mockVariants.EXPECT().WithTX(mockTx).Return(mockVariantsTx)
+	mockVariantsTx.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domainrepo.ErrVariantSKUTaken)
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	uc := productuscase.NewCreateProductUsecase(mockRepo, mockTxManager).WithVariants(mockVariants)
+	_, err := uc.Execute(context.Background(), dto.CreateProductRequest{
+		Name: "T-shirt", PriceCents: 2000, Stock: 7, SKU: "TSHIRT",
+	})
+
+	assert.ErrorIs(t, err, utils.ErrVariantSKUTaken)
+}
+
+func TestUpdateProductUsecase_StockGoesToDefaultVariant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockProductRepository(ctrl)
+	mockRepoTx := repository.NewMockProductRepository(ctrl)
+	mockVariants := repository.NewMockProductVariantRepository(ctrl)
+	mockVariantsTx := repository.NewMockProductVariantRepository(ctrl)
+	mockTxManager := repository.NewMockTxManager(ctrl)
+	mockTx := repository.NewMockTx(ctrl)
+
+	existing := &entity.Product{ID: "p-1", Name: "T-shirt", PriceCents: 2000, Stock: 9}
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTX(mockTx).Return(mockRepoTx)
+	mockRepoTx.EXPECT().FindByID(gomock.Any(), "p-1").Return(existing, nil)
+	mockRepoTx.EXPECT().Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, p *entity.Product) (*entity.Product, error) {
+			return p, nil
+		})
+	mockVariants.EXPECT().WithTX(mockTx).Return(mockVariantsTx)
+	// 4 unités sur la variante par défaut, 5 sur les autres : total 9
+	mockVariantsTx.EXPECT().SetDefaultStock(gomock.Any(), "p-1", 4).Return(nil)
+	mockVariantsTx.EXPECT().RefreshProductStock(gomock.Any(), "p-1").Return(9, nil)
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Rollback().Return(sql.ErrTxDone).AnyTimes()
+
+	uc := productuscase.NewUpdateProductUsecase(mockRepo, mockTxManager).WithVariants(mockVariants)
+	result, err := uc.Execute(context.Background(), &entity.Product{ID: "p-1", Name: "T-shirt", PriceCents: 2000, Stock: 4})
+
+	require.NoError(t, err)
+	assert.Equal(t, 9, result.Stock)
+}
+
+func TestVariantUsecase_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockProducts := repository.NewMockProductRepository(ctrl)
+	mockProductsTx := repository.NewMockProductRepository(ctrl)
+	mockVariants := repository.NewMockProductVariantRepository(ctrl)
+	mockVariantsTx := repository.NewMockProductVariantRepository(ctrl)
+	mockTxManager := repository.NewMockTxManager(ctrl)
+	mockTx := repository.NewMockTx(ctrl)
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockVariants.EXPECT().WithTX(mockTx).Return(mockVariantsTx)
+	mockProducts.EXPECT().WithTX(mockTx).Return(mockProductsTx)
+
+	// Produit verrouillé avant toute écriture sur ses variantes
+	gomock.InOrder(
+		mockVariantsTx.EXPECT().LockProduct(gomock.Any(), "p-1").Return(nil),
+		mockProductsTx.EXPECT().FindByID(gomock.Any(), "p-1").Return(&entity.Product{ID: "p-1", PriceCents: 2000}, nil),
+		mockVariantsTx.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, v *entity.ProductVariant) error {
+				assert.Equal(t, "p-1", v.ProductID)
+				assert.False(t, v.IsDefault)
+				v.ID = "v-2"
+				return nil
+			}),
+		mockVariantsTx.EXPECT().RefreshProductStock(gomock.Any(), "p-1").Return(7, nil),
+		mockTx.EXPECT().Commit().Return(nil),
+	)
+	mockTx.EXPECT().Rollback().Return(sql.ErrTxDone).AnyTimes()
+
+	uc := productuscase.NewVariantUsecase(mockProducts, mockVariants, mockTxManager)
+	resp, err := uc.Create(context.Background(), "p-1", dto.VariantRequest{
+		SKU: "TSHIRT-L", Options: map[string]string{"size": "L"}, Stock: 4,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "v-2", resp.ID)
+	assert.Equal(t, int64(2000), resp.PriceCents)
+	assert.Nil(t, resp.PriceOverrideCents)
+}
+
+func TestVariantUsecase_CreateUnknownProduct(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockVariants := repository.NewMockProductVariantRepository(ctrl)
+	mockVariantsTx := repository.NewMockProductVariantRepository(ctrl)
+	mockTxManager := repository.NewMockTxManager(ctrl)
+	mockTx := repository.NewMockTx(ctrl)
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockVariants.EXPECT().WithTX(mockTx).Return(mockVariantsTx)
+	mockVariantsTx.EXPECT().LockProduct(gomock.Any(), "p-9").Return(sql.ErrNoRows)
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	uc := productuscase.NewVariantUsecase(repository.NewMockProductRepository(ctrl), mockVariants, mockTxManager)
+	_, err := uc.Create(context.Background(), "p-9", dto.VariantRequest{
+		SKU: "TSHIRT-L", Options: map[string]string{"size": "L"},
+	})
+
+	assert.ErrorIs(t, err, utils.ErrProductNotFound)
+}
+
+func TestVariantUsecase_DeleteDefaultVariant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockProducts := repository.NewMockProductRepository(ctrl)
+	mockProductsTx := repository.NewMockProductRepository(ctrl)
+	mockVariants := repository.NewMockProductVariantRepository(ctrl)
+	mockVariantsTx := repository.NewMockProductVariantRepository(ctrl)
+	mockTxManager := repository.NewMockTxManager(ctrl)
+	mockTx := repository.NewMockTx(ctrl)
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil).Times(2)
+	mockVariants.EXPECT().WithTX(mockTx).Return(mockVariantsTx).Times(2)
+	mockProducts.EXPECT().WithTX(mockTx).Return(mockProductsTx).Times(2)
+	mockVariantsTx.EXPECT().LockProduct(gomock.Any(), "p-1").Return(nil).Times(2)
+	mockProductsTx.EXPECT().FindByID(gomock.Any(), "p-1").Return(&entity.Product{ID: "p-1"}, nil).Times(2)
+	mockVariantsTx.EXPECT().FindByID(gomock.Any(), "v-1").Return(&entity.ProductVariant{ID: "v-1", ProductID: "p-1", IsDefault: true}, nil)
+	// Variante d'un autre produit : introuvable sous p-1
+	mockVariantsTx.EXPECT().FindByID(gomock.Any(), "v-7").Return(&entity.ProductVariant{ID: "v-7", ProductID: "p-2"}, nil)
+	mockTx.EXPECT().Rollback().Return(nil).Times(2)
+
+	uc := productuscase.NewVariantUsecase(mockProducts, mockVariants, mockTxManager)
+
+	assert.ErrorIs(t, uc.Delete(context.Background(), "p-1", "v-1"), utils.ErrVariantIsDefault)
+	assert.ErrorIs(t, uc.Delete(context.Background(), "p-1", "v-7"), utils.ErrVariantNotFound)
+}
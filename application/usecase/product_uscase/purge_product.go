@@ -0,0 +1,157 @@
+// application/usecase/product_uscase/purge_product_usecase.go
+package productuscase
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"Goshop/domain/repository"
+	"Goshop/domain/storage"
+	"Goshop/interfaces/utils"
+
+	"github.com/rs/zerolog"
+)
+
+// PurgeProductUsecase supprime définitivement un produit archivé : ses
+// images et leur contenu dans tous les cas, la ligne elle-même (variantes et
+// catégories comprises) seulement s'il n'a jamais été commandé
+type PurgeProductUsecase struct {
+	repo      repository.ProductRepository
+	txManager repository.TxManager
+	cache     repository.ProductCache
+	// images / blobs : contenus des images supprimés après le commit
+	images repository.ProductImageRepository
+	blobs  storage.BlobStore
+}
+
+func NewPurgeProductUsecase(
+	repo repository.ProductRepository,
+	txManager repository.TxManager,
+) *PurgeProductUsecase {
+	return &PurgeProductUsecase{
+		repo:      repo,
+		txManager: txManager,
+	}
+}
+
+// WithCache : le produit purgé est retiré du cache après le commit
+func (uc *PurgeProductUsecase) WithCache(cache repository.ProductCache) *PurgeProductUsecase {
+	uc.cache = cache
+	return uc
+}
+
+// WithImages : les images du produit sont supprimées en base puis retirées
+// du stockage une fois la purge committée
+func (uc *PurgeProductUsecase) WithImages(images repository.ProductImageRepository, blobs storage.BlobStore) *PurgeProductUsecase {
+	uc.images = images
+	uc.blobs = blobs
+	return uc
+}
+
+// Execute purge le produit et indique si sa ligne a été supprimée (false :
+// produit commandé, conservé archivé et sans images pour l'historique).
+// Seul un produit archivé peut être purgé.
+func (uc *PurgeProductUsecase) Execute(ctx context.Context, id string) (bool, error) {
+	logger := zerolog.Ctx(ctx)
+	if id == "" {
+		logger.Warn().
+			Str("operation", "execute").
+			Msg("Product ID is empty")
+		return false, utils.ErrProductNotFound
+	}
+
+	start := time.Now()
+
+	tx, err := uc.txManager.BeginTx(ctx)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute").
+			Str("product_id", id).
+			Msg("Failed to begin transaction for product purge")
+		return false, utils.ErrTransactionBegin
+	}
+
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+				logger.Error().
+					Err(rollbackErr).
+					Str("operation", "execute").
+					Str("product_id", id).
+					Msg("Failed to rollback transaction after error")
+			}
+		}
+	}()
+
+	repo := uc.repo.WithTX(tx)
+
+	product, err := repo.FindByIDIncludingDeleted(ctx, id)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Str("operation", "execute").
+			Str("product_id", id).
+			Msg("Product not found for purge")
+		return false, utils.ErrProductNotFound
+	}
+
+	if product.DeletedAt == nil {
+		err = utils.ErrProductNotArchived
+		logger.Warn().
+			Str("operation", "execute").
+			Str("product_id", id).
+			Msg("Only archived products can be purged")
+		return false, err
+	}
+
+	// Contenus des images à supprimer après le commit
+	var blobKeys []string
+	if uc.images != nil {
+		blobKeys, err = uc.images.WithTX(tx).DeleteByProduct(ctx, id)
+		if err != nil {
+			logger.Error().
+				Err(err).
+				Stack().
+				Str("operation", "execute").
+				Str("product_id", id).
+				Msg("Failed to delete product images before purge")
+			return false, utils.ErrProductPurgeFail
+		}
+	}
+
+	purged, err := repo.Purge(ctx, id)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("operation", "execute").
+			Str("product_id", id).
+			Msg("Failed to purge product")
+		return false, utils.ErrProductPurgeFail
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute").
+			Str("product_id", id).
+			Msg("Failed to commit purge transaction")
+		return false, utils.ErrTransactionCommit
+	}
+
+	InvalidateProducts(ctx, uc.cache, id)
+	DeleteBlobs(ctx, uc.blobs, blobKeys...)
+
+	logger.Info().
+		Str("operation", "execute").
+		Str("product_id", id).
+		Str("product_name", product.Name).
+		Bool("row_deleted", purged).
+		Int("images_deleted", len(blobKeys)).
+		Dur("duration_ms", time.Since(start)).
+		Msg("Product purged successfully")
+
+	return purged, nil
+}
@@ -0,0 +1,111 @@
+package productuscase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	productuscase "Goshop/application/usecase/product_uscase"
+	"Goshop/domain/entity"
+	"Goshop/interfaces/utils"
+	"Goshop/mocks/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// purgeMocks : dépendances de PurgeProductUsecase, produit "p-1" archivé
+type purgeMocks struct {
+	repoTx   *repository.MockProductRepository
+	imagesTx *repository.MockProductImageRepository
+	tx       *repository.MockTx
+	blobs    *memBlobs
+	uc       *productuscase.PurgeProductUsecase
+}
+
+func newPurgeMocks(t *testing.T, product *entity.Product) purgeMocks {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockProductRepository(ctrl)
+	mockImages := repository.NewMockProductImageRepository(ctrl)
+	mockTxManager := repository.NewMockTxManager(ctrl)
+	m := purgeMocks{
+		repoTx:   repository.NewMockProductRepository(ctrl),
+		imagesTx: repository.NewMockProductImageRepository(ctrl),
+		tx:       repository.NewMockTx(ctrl),
+		blobs:    newMemBlobs(),
+	}
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(m.tx, nil)
+	mockRepo.EXPECT().WithTX(m.tx).Return(m.repoTx)
+	mockImages.EXPECT().WithTX(m.tx).Return(m.imagesTx).AnyTimes()
+	m.repoTx.EXPECT().FindByIDIncludingDeleted(gomock.Any(), product.ID).Return(product, nil)
+
+	m.uc = productuscase.NewPurgeProductUsecase(mockRepo, mockTxManager).WithImages(mockImages, m.blobs)
+	return m
+}
+
+func archivedProduct() *entity.Product {
+	deletedAt := time.Now()
+	return &entity.Product{ID: "p-1", Name: "T-shirt", DeletedAt: &deletedAt}
+}
+
+func TestPurgeProductUsecase_RemovesImageBlobs(t *testing.T) {
+	m := newPurgeMocks(t, archivedProduct())
+	m.blobs.objects["products/p-1/img-1.png"] = pngImage
+	m.blobs.objects["products/p-1/img-2.gif"] = []byte("GIF89a")
+	m.blobs.objects["products/p-2/img-3.png"] = pngImage
+
+	m.imagesTx.EXPECT().DeleteByProduct(gomock.Any(), "p-1").
+		Return([]string{"products/p-1/img-1.png", "products/p-1/img-2.gif"}, nil)
+	m.repoTx.EXPECT().Purge(gomock.Any(), "p-1").Return(true, nil)
+	m.tx.EXPECT().Commit().Return(nil)
+
+	deleted, err := m.uc.Execute(context.Background(), "p-1")
+
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	assert.Len(t, m.blobs.objects, 1)
+	assert.Contains(t, m.blobs.objects, "products/p-2/img-3.png")
+}
+
+func TestPurgeProductUsecase_OrderedProductKeepsRow(t *testing.T) {
+	m := newPurgeMocks(t, archivedProduct())
+	m.blobs.objects["products/p-1/img-1.png"] = pngImage
+
+	// Produit commandé : la ligne reste, les images partent quand même
+	m.imagesTx.EXPECT().DeleteByProduct(gomock.Any(), "p-1").Return([]string{"products/p-1/img-1.png"}, nil)
+	m.repoTx.EXPECT().Purge(gomock.Any(), "p-1").Return(false, nil)
+	m.tx.EXPECT().Commit().Return(nil)
+
+	deleted, err := m.uc.Execute(context.Background(), "p-1")
+
+	require.NoError(t, err)
+	assert.False(t, deleted)
+	assert.Empty(t, m.blobs.objects)
+}
+
+func TestPurgeProductUsecase_ActiveProductRejected(t *testing.T) {
+	m := newPurgeMocks(t, &entity.Product{ID: "p-1", Name: "T-shirt"})
+	m.blobs.objects["products/p-1/img-1.png"] = pngImage
+	m.tx.EXPECT().Rollback().Return(nil)
+
+	_, err := m.uc.Execute(context.Background(), "p-1")
+
+	assert.ErrorIs(t, err, utils.ErrProductNotArchived)
+	assert.Len(t, m.blobs.objects, 1)
+}
+
+func TestPurgeProductUsecase_BlobsKeptWhenPurgeFails(t *testing.T) {
+	m := newPurgeMocks(t, archivedProduct())
+	m.blobs.objects["products/p-1/img-1.png"] = pngImage
+
+	m.imagesTx.EXPECT().DeleteByProduct(gomock.Any(), "p-1").Return([]string{"products/p-1/img-1.png"}, nil)
+	m.repoTx.EXPECT().Purge(gomock.Any(), "p-1").Return(false, assert.AnError)
+	m.tx.EXPECT().Rollback().Return(nil)
+
+	_, err := m.uc.Execute(context.Background(), "p-1")
+
+	assert.ErrorIs(t, err, utils.ErrProductPurgeFail)
+	assert.Len(t, m.blobs.objects, 1)
+}
@@ -0,0 +1,126 @@
+// application/usecase/product_uscase/restore_product_usecase.go
+package productuscase
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"Goshop/domain/entity"
+	"Goshop/domain/repository"
+	"Goshop/interfaces/utils"
+
+	"github.com/rs/zerolog"
+)
+
+// RestoreProductUsecase remet au catalogue un produit archivé, avec ses
+// variantes et ses images
+type RestoreProductUsecase struct {
+	repo      repository.ProductRepository
+	txManager repository.TxManager
+	cache     repository.ProductCache
+}
+
+func NewRestoreProductUsecase(
+	repo repository.ProductRepository,
+	txManager repository.TxManager,
+) *RestoreProductUsecase {
+	return &RestoreProductUsecase{
+		repo:      repo,
+		txManager: txManager,
+	}
+}
+
+// WithCache : le produit restauré est retiré du cache après le commit
+func (uc *RestoreProductUsecase) WithCache(cache repository.ProductCache) *RestoreProductUsecase {
+	uc.cache = cache
+	return uc
+}
+
+// Execute restaure le produit. Restaurer un produit actif ne change rien.
+func (uc *RestoreProductUsecase) Execute(ctx context.Context, id string) (*entity.Product, error) {
+	logger := zerolog.Ctx(ctx)
+	if id == "" {
+		logger.Warn().
+			Str("operation", "execute").
+			Msg("Product ID is empty")
+		return nil, utils.ErrProductNotFound
+	}
+
+	start := time.Now()
+
+	tx, err := uc.txManager.BeginTx(ctx)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute").
+			Str("product_id", id).
+			Msg("Failed to begin transaction for product restore")
+		return nil, utils.ErrTransactionBegin
+	}
+
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+				logger.Error().
+					Err(rollbackErr).
+					Str("operation", "execute").
+					Str("product_id", id).
+					Msg("Failed to rollback transaction after error")
+			}
+		}
+	}()
+
+	repo := uc.repo.WithTX(tx)
+
+	product, err := repo.FindByIDIncludingDeleted(ctx, id)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Str("operation", "execute").
+			Str("product_id", id).
+			Msg("Product not found for restore")
+		return nil, utils.ErrProductNotFound
+	}
+
+	if product.DeletedAt == nil {
+		if err = tx.Commit(); err != nil {
+			return nil, utils.ErrTransactionCommit
+		}
+		logger.Info().
+			Str("operation", "execute").
+			Str("product_id", id).
+			Msg("Product is not archived, nothing to restore")
+		return product, nil
+	}
+
+	restored, err := repo.Restore(ctx, id)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute").
+			Str("product_id", id).
+			Msg("Failed to restore product")
+		return nil, utils.ErrProductRestoreFail
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "execute").
+			Str("product_id", id).
+			Msg("Failed to commit restore transaction")
+		return nil, utils.ErrTransactionCommit
+	}
+
+	InvalidateProducts(ctx, uc.cache, id)
+
+	logger.Info().
+		Str("operation", "execute").
+		Str("product_id", id).
+		Str("product_name", restored.Name).
+		Dur("duration_ms", time.Since(start)).
+		Msg("Product restored successfully")
+
+	return restored, nil
+}
@@ -0,0 +1,90 @@
+package productuscase_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	productuscase "Goshop/application/usecase/product_uscase"
+	"Goshop/domain/entity"
+	"Goshop/interfaces/utils"
+	"Goshop/mocks/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRestoreProductUsecase_RestoresArchivedProduct(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockProductRepository(ctrl)
+	mockRepoTx := repository.NewMockProductRepository(ctrl)
+	mockTxManager := repository.NewMockTxManager(ctrl)
+	mockTx := repository.NewMockTx(ctrl)
+
+	deletedAt := time.Now()
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTX(mockTx).Return(mockRepoTx)
+	mockRepoTx.EXPECT().FindByIDIncludingDeleted(gomock.Any(), "p-1").
+		Return(&entity.Product{ID: "p-1", Name: "T-shirt", DeletedAt: &deletedAt}, nil)
+	mockRepoTx.EXPECT().Restore(gomock.Any(), "p-1").Return(&entity.Product{ID: "p-1", Name: "T-shirt"}, nil)
+	mockTx.EXPECT().Commit().Return(nil)
+
+	restored, err := productuscase.NewRestoreProductUsecase(mockRepo, mockTxManager).Execute(context.Background(), "p-1")
+
+	require.NoError(t, err)
+	assert.Nil(t, restored.DeletedAt)
+}
+
+func TestRestoreProductUsecase_ActiveProductUnchanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockProductRepository(ctrl)
+	mockRepoTx := repository.NewMockProductRepository(ctrl)
+	mockTxManager := repository.NewMockTxManager(ctrl)
+	mockTx := repository.NewMockTx(ctrl)
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTX(mockTx).Return(mockRepoTx)
+	mockRepoTx.EXPECT().FindByIDIncludingDeleted(gomock.Any(), "p-1").
+		Return(&entity.Product{ID: "p-1", Name: "T-shirt"}, nil)
+	mockTx.EXPECT().Commit().Return(nil)
+
+	restored, err := productuscase.NewRestoreProductUsecase(mockRepo, mockTxManager).Execute(context.Background(), "p-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "p-1", restored.ID)
+}
+
+func TestRestoreProductUsecase_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockProductRepository(ctrl)
+	mockRepoTx := repository.NewMockProductRepository(ctrl)
+	mockTxManager := repository.NewMockTxManager(ctrl)
+	mockTx := repository.NewMockTx(ctrl)
+
+	mockTxManager.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockRepo.EXPECT().WithTX(mockTx).Return(mockRepoTx)
+	mockRepoTx.EXPECT().FindByIDIncludingDeleted(gomock.Any(), "unknown").Return(nil, sql.ErrNoRows)
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	_, err := productuscase.NewRestoreProductUsecase(mockRepo, mockTxManager).Execute(context.Background(), "unknown")
+
+	assert.ErrorIs(t, err, utils.ErrProductNotFound)
+}
+
+func TestGetProductByIdUsecase_IncludingDeletedBypassesCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repository.NewMockProductRepository(ctrl)
+	mockCache := repository.NewMockProductCache(ctrl)
+
+	deletedAt := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	mockRepo.EXPECT().FindByIDIncludingDeleted(gomock.Any(), "p-1").
+		Return(&entity.Product{ID: "p-1", Name: "T-shirt", DeletedAt: &deletedAt}, nil)
+
+	uc := productuscase.NewGetProductByIdUsecase(mockRepo, nil).WithCache(mockCache)
+	response, err := uc.ExecuteIncludingDeleted(context.Background(), "p-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "2025-03-01 09:00:00", response.DeletedAt)
+}
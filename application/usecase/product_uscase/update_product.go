@@ -16,6 +16,11 @@ import (
 type UpdateProductUsecase struct {
 	repo      repository.ProductRepository
 	txManager repository.TxManager
+	cache     repository.ProductCache
+	// categories : nil tant que WithCategories n'est pas appelé
+	categories repository.CategoryRepository
+	// variants : le stock saisi devient celui de la variante par défaut
+	variants repository.ProductVariantRepository
 	//logger    *setupLogging.Logger
 }
 
@@ -31,7 +36,32 @@ func NewUpdateProductUsecase(
 	}
 }
 
+// WithCache : le produit modifié est retiré du cache après le commit
+func (uc *UpdateProductUsecase) WithCache(cache repository.ProductCache) *UpdateProductUsecase {
+	uc.cache = cache
+	return uc
+}
+
+// WithCategories permet de remplacer les catégories du produit
+func (uc *UpdateProductUsecase) WithCategories(categories repository.CategoryRepository) *UpdateProductUsecase {
+	uc.categories = categories
+	return uc
+}
+
+// WithVariants : le stock saisi est celui de la variante par défaut et le
+// stock du produit reste la somme de ses variantes
+func (uc *UpdateProductUsecase) WithVariants(variants repository.ProductVariantRepository) *UpdateProductUsecase {
+	uc.variants = variants
+	return uc
+}
+
 func (uc *UpdateProductUsecase) Execute(ctx context.Context, product *entity.Product) (*entity.Product, error) {
+	return uc.ExecuteWithCategories(ctx, product, nil)
+}
+
+// ExecuteWithCategories met à jour le produit et, si categoryIDs n'est pas
+// nil, remplace ses catégories dans la même transaction ([] les retire toutes)
+func (uc *UpdateProductUsecase) ExecuteWithCategories(ctx context.Context, product *entity.Product, categoryIDs *[]string) (*entity.Product, error) {
 	logger := zerolog.Ctx(ctx)
 	if product == nil {
 		logger.Warn().
@@ -148,6 +178,44 @@ func (uc *UpdateProductUsecase) Execute(ctx context.Context, product *entity.Pro
 		Str("product_name", updatedProduct.Name).
 		Msg("Product updated successfully in repository")
 
+	if uc.variants != nil {
+		variantRepo := uc.variants.WithTX(tx)
+		if err = variantRepo.SetDefaultStock(ctx, updatedProduct.ID, product.Stock); err != nil {
+			logger.Error().
+				Err(err).
+				Stack().
+				Str("operation", "execute").
+				Str("product_id", updatedProduct.ID).
+				Msg("Failed to update default variant stock")
+			return nil, utils.ErrProductUpdateFail
+		}
+		if updatedProduct.Stock, err = variantRepo.RefreshProductStock(ctx, updatedProduct.ID); err != nil {
+			logger.Error().
+				Err(err).
+				Stack().
+				Str("operation", "execute").
+				Str("product_id", updatedProduct.ID).
+				Msg("Failed to refresh product stock")
+			return nil, utils.ErrProductUpdateFail
+		}
+	}
+
+	if categoryIDs != nil {
+		var categoryRepo repository.CategoryRepository
+		if uc.categories != nil {
+			categoryRepo = uc.categories.WithTX(tx)
+		}
+		if err = setProductCategories(ctx, categoryRepo, updatedProduct.ID, *categoryIDs); err != nil {
+			logger.Warn().
+				Err(err).
+				Str("operation", "execute").
+				Str("product_id", updatedProduct.ID).
+				Strs("category_ids", *categoryIDs).
+				Msg("Failed to replace product categories")
+			return nil, categoryErr(err, utils.ErrProductUpdateFail)
+		}
+	}
+
 	// Commit de la transaction
 	logger.Debug().
 		Str("operation", "execute").
@@ -169,6 +237,8 @@ func (uc *UpdateProductUsecase) Execute(ctx context.Context, product *entity.Pro
 		Str("product_id", updatedProduct.ID).
 		Msg("Transaction committed successfully")
 
+	InvalidateProducts(ctx, uc.cache, updatedProduct.ID)
+
 	// Log de succès
 	duration := time.Since(start)
 	logger.Info().
@@ -0,0 +1,224 @@
+// application/usecase/product_uscase/variant_usecase.go
+package productuscase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	dto "Goshop/application/dto/product_dto"
+	"Goshop/domain/entity"
+	"Goshop/domain/repository"
+	"Goshop/interfaces/utils"
+
+	"github.com/rs/zerolog"
+)
+
+// VariantUsecase gère les variantes d'un produit (back-office). Chaque
+// écriture verrouille d'abord le produit puis recalcule son stock total dans
+// la même transaction.
+type VariantUsecase struct {
+	products  repository.ProductRepository
+	variants  repository.ProductVariantRepository
+	txManager repository.TxManager
+	cache     repository.ProductCache
+}
+
+func NewVariantUsecase(
+	products repository.ProductRepository,
+	variants repository.ProductVariantRepository,
+	txManager repository.TxManager,
+) *VariantUsecase {
+	return &VariantUsecase{products: products, variants: variants, txManager: txManager}
+}
+
+// WithCache : le produit dont le stock total change est retiré du cache
+func (uc *VariantUsecase) WithCache(cache repository.ProductCache) *VariantUsecase {
+	uc.cache = cache
+	return uc
+}
+
+// List renvoie les variantes du produit, variante par défaut en tête
+func (uc *VariantUsecase) List(ctx context.Context, productID string) ([]dto.VariantResponse, error) {
+	product, err := uc.products.FindByID(ctx, productID)
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Str("operation", "variant_list").Str("product_id", productID).Msg("Product not found")
+		return nil, utils.ErrProductNotFound
+	}
+
+	variants, err := uc.variants.FindByProducts(ctx, []string{productID})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Str("operation", "variant_list").Str("product_id", productID).Msg("Failed to list variants")
+		return nil, utils.ErrInternalServer
+	}
+
+	responses := make([]dto.VariantResponse, 0, len(variants))
+	for _, v := range variants {
+		responses = append(responses, dto.NewVariantResponse(v, product.PriceCents))
+	}
+	return responses, nil
+}
+
+// Create ajoute une variante ; seule la variante par défaut peut n'avoir
+// aucune option
+func (uc *VariantUsecase) Create(ctx context.Context, productID string, req dto.VariantRequest) (*dto.VariantResponse, error) {
+	if len(req.Options) == 0 {
+		return nil, utils.ErrValidationFailed
+	}
+
+	variant := &entity.ProductVariant{
+		ProductID:  productID,
+		SKU:        req.SKU,
+		Options:    req.Options,
+		PriceCents: req.PriceCents,
+		Stock:      req.Stock,
+	}
+
+	var resp dto.VariantResponse
+	err := uc.inProductTx(ctx, "variant_create", productID, func(variants repository.ProductVariantRepository, product *entity.Product) error {
+		if err := variants.Create(ctx, variant); err != nil {
+			return err
+		}
+		resp = dto.NewVariantResponse(variant, product.PriceCents)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("operation", "variant_create").
+		Str("product_id", productID).
+		Str("variant_id", variant.ID).
+		Str("sku", variant.SKU).
+		Msg("Product variant created")
+
+	return &resp, nil
+}
+
+// Update remplace SKU, options, prix et stock d'une variante
+func (uc *VariantUsecase) Update(ctx context.Context, productID, variantID string, req dto.VariantRequest) (*dto.VariantResponse, error) {
+	var resp dto.VariantResponse
+	err := uc.inProductTx(ctx, "variant_update", productID, func(variants repository.ProductVariantRepository, product *entity.Product) error {
+		variant, err := findProductVariant(ctx, variants, productID, variantID)
+		if err != nil {
+			return err
+		}
+		if !variant.IsDefault && len(req.Options) == 0 {
+			return utils.ErrValidationFailed
+		}
+
+		variant.SKU = req.SKU
+		variant.Options = req.Options
+		variant.PriceCents = req.PriceCents
+		variant.Stock = req.Stock
+		if err := variants.Update(ctx, variant); err != nil {
+			return err
+		}
+		resp = dto.NewVariantResponse(variant, product.PriceCents)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("operation", "variant_update").
+		Str("product_id", productID).
+		Str("variant_id", variantID).
+		Msg("Product variant updated")
+
+	return &resp, nil
+}
+
+// Delete supprime une variante qu'aucune commande ne référence ; la variante
+// par défaut ne se supprime qu'avec le produit
+func (uc *VariantUsecase) Delete(ctx context.Context, productID, variantID string) error {
+	err := uc.inProductTx(ctx, "variant_delete", productID, func(variants repository.ProductVariantRepository, _ *entity.Product) error {
+		variant, err := findProductVariant(ctx, variants, productID, variantID)
+		if err != nil {
+			return err
+		}
+		if variant.IsDefault {
+			return utils.ErrVariantIsDefault
+		}
+		return variants.Delete(ctx, variantID)
+	})
+	if err != nil {
+		return err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("operation", "variant_delete").
+		Str("product_id", productID).
+		Str("variant_id", variantID).
+		Msg("Product variant deleted")
+
+	return nil
+}
+
+// inProductTx exécute fn sur le produit verrouillé, recalcule son stock total
+// puis valide la transaction et invalide le cache du produit
+func (uc *VariantUsecase) inProductTx(ctx context.Context, operation, productID string, fn func(repository.ProductVariantRepository, *entity.Product) error) error {
+	logger := zerolog.Ctx(ctx).With().Str("operation", operation).Str("product_id", productID).Logger()
+
+	tx, err := uc.txManager.BeginTx(ctx)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to begin transaction")
+		return utils.ErrTransactionBegin
+	}
+	// Rollback systématique : sans effet (sql.ErrTxDone) une fois le commit passé
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			logger.Error().Err(rollbackErr).Msg("Failed to rollback transaction")
+		}
+	}()
+
+	variants := uc.variants.WithTX(tx)
+
+	if err := variants.LockProduct(ctx, productID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return utils.ErrProductNotFound
+		}
+		logger.Error().Err(err).Msg("Failed to lock product")
+		return utils.ErrInternalServer
+	}
+	product, err := uc.products.WithTX(tx).FindByID(ctx, productID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to read locked product")
+		return utils.ErrInternalServer
+	}
+
+	if err := fn(variants, product); err != nil {
+		appErr := variantErr(err, utils.ErrInternalServer)
+		if appErr == utils.ErrInternalServer {
+			logger.Error().Err(err).Msg("Product variant write failed")
+		}
+		return appErr
+	}
+
+	if _, err := variants.RefreshProductStock(ctx, productID); err != nil {
+		logger.Error().Err(err).Msg("Failed to refresh product stock")
+		return utils.ErrInternalServer
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error().Err(err).Msg("Failed to commit transaction")
+		return utils.ErrTransactionCommit
+	}
+
+	InvalidateProducts(ctx, uc.cache, productID)
+	return nil
+}
+
+// findProductVariant : une variante d'un autre produit est introuvable ici
+func findProductVariant(ctx context.Context, variants repository.ProductVariantRepository, productID, variantID string) (*entity.ProductVariant, error) {
+	variant, err := variants.FindByID(ctx, variantID)
+	if err != nil {
+		return nil, err
+	}
+	if variant.ProductID != productID {
+		return nil, repository.ErrVariantNotFound
+	}
+	return variant, nil
+}
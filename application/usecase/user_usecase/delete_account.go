@@ -0,0 +1,65 @@
+// application/usecase/user_usecase/delete_account.go
+package userusecase
+
+import (
+	"context"
+	"errors"
+
+	userrepository "Goshop/domain/repository/user_repository"
+	"Goshop/interfaces/utils"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// DeleteAccountUsecase : effacement du compte par son propriétaire (RGPD).
+// Le profil client est anonymisé, les commandes sont conservées.
+type DeleteAccountUsecase struct {
+	repo userrepository.UserRepository
+}
+
+func NewDeleteAccountUsecase(repo userrepository.UserRepository) *DeleteAccountUsecase {
+	return &DeleteAccountUsecase{repo: repo}
+}
+
+// Execute exige le mot de passe : un access token volé ne suffit pas à
+// effacer le compte. Les access tokens déjà émis restent valides jusqu'à
+// leur expiration mais ne désignent plus aucun utilisateur.
+func (uc *DeleteAccountUsecase) Execute(ctx context.Context, userID, password string) error {
+	logger := zerolog.Ctx(ctx)
+
+	user, err := uc.repo.FindUserByID(userID)
+	if errors.Is(err, userrepository.ErrUserNotFound) {
+		return utils.ErrUserNotFound
+	}
+	if err != nil {
+		logger.Error().Err(err).Str("operation", "delete_account").Msg("Failed to load user")
+		return utils.ErrInternalServer
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		logger.Warn().
+			Str("operation", "delete_account").
+			Str("user_id", maskUserID(userID)).
+			Msg("Wrong password for account deletion")
+		return utils.ErrCurrentPasswordInvalid
+	}
+
+	if err := uc.repo.DeleteUser(userID); err != nil {
+		if errors.Is(err, userrepository.ErrUserNotFound) {
+			return utils.ErrUserNotFound
+		}
+		logger.Error().
+			Err(err).
+			Str("operation", "delete_account").
+			Str("user_id", maskUserID(userID)).
+			Msg("Failed to delete account")
+		return utils.ErrInternalServer
+	}
+
+	utils.SecurityAudit(ctx, utils.AUDIT_ACCOUNT_DELETED).
+		Str("user_id", maskUserID(userID)).
+		Msg("Account deleted by its owner, customer profile anonymised")
+
+	return nil
+}
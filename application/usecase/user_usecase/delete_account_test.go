@@ -0,0 +1,56 @@
+package userusecase_test
+
+import (
+	"testing"
+
+	userusecase "Goshop/application/usecase/user_usecase"
+	userentity "Goshop/domain/entity/user_entity"
+	userrepository "Goshop/domain/repository/user_repository"
+	"Goshop/interfaces/utils"
+	mockrepo "Goshop/mocks/repository"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func hashedUser(t *testing.T, id, password string) *userentity.UserEntity {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	assert.NoError(t, err)
+	return &userentity.UserEntity{ID: id, Email: "alice@example.com", Password: string(hash)}
+}
+
+func TestDeleteAccountUsecase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	uc := userusecase.NewDeleteAccountUsecase(repo)
+
+	repo.EXPECT().FindUserByID("user-1").Return(hashedUser(t, "user-1", "pass123"), nil)
+	repo.EXPECT().DeleteUser("user-1").Return(nil)
+
+	assert.NoError(t, uc.Execute(createContextWithLogger(), "user-1", "pass123"))
+}
+
+func TestDeleteAccountUsecase_WrongPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	uc := userusecase.NewDeleteAccountUsecase(repo)
+
+	// Pas d'effacement sans le bon mot de passe
+	repo.EXPECT().FindUserByID("user-1").Return(hashedUser(t, "user-1", "pass123"), nil)
+
+	err := uc.Execute(createContextWithLogger(), "user-1", "wrong")
+	assert.ErrorIs(t, err, utils.ErrCurrentPasswordInvalid)
+}
+
+func TestDeleteAccountUsecase_AlreadyDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	uc := userusecase.NewDeleteAccountUsecase(repo)
+
+	repo.EXPECT().FindUserByID("user-1").Return(nil, userrepository.ErrUserNotFound)
+
+	err := uc.Execute(createContextWithLogger(), "user-1", "pass123")
+	assert.ErrorIs(t, err, utils.ErrUserNotFound)
+}
@@ -3,29 +3,95 @@ package userusecase
 
 import (
 	"context"
+	"errors"
 	"strings"
+	"sync"
 	"time"
 
 	"Goshop/application/metrics" // ← AJOUTÉ
 	"Goshop/config/setupLogging"
 
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 	"golang.org/x/crypto/bcrypt"
 
+	authentity "Goshop/domain/auth_entity"
+	userentity "Goshop/domain/entity/user_entity"
+	authrepository "Goshop/domain/repository/auth_repository"
 	userrepository "Goshop/domain/repository/user_repository"
 	"Goshop/interfaces/utils"
 )
 
+// LoginGuard : protection force brute appliquée autour de la vérification du
+// mot de passe (voir authusecase.LoginGuard)
+type LoginGuard interface {
+	// Check est appelé avant la vérification du mot de passe
+	Check(ctx context.Context, email, ip string) error
+	// RegisterFailure retourne une erreur si l'échec verrouille le compte
+	RegisterFailure(ctx context.Context, email, ip string) error
+	RegisterSuccess(ctx context.Context, email string)
+}
+
+// MFAVerifier : second facteur TOTP (voir authusecase.MFAUsecase)
+type MFAVerifier interface {
+	Enabled(ctx context.Context, userID string) (bool, error)
+	// Verify retourne utils.ErrMFACodeInvalid si le code est refusé
+	Verify(ctx context.Context, userID, code string) error
+}
+
+// LoginResult : paire access / refresh token, ou token intermédiaire
+// "mfa_pending" quand le compte exige un second facteur
+type LoginResult struct {
+	AccessToken  string
+	RefreshToken string
+	MFAToken     string // non vide : le login se termine par ExecuteMFA
+}
+
+func (r *LoginResult) MFARequired() bool {
+	return r.MFAToken != ""
+}
+
+// dummyPasswordHash : hash comparé quand l'email est inconnu, pour que la
+// réponse prenne autant de temps qu'un mauvais mot de passe. Produit par
+// userentity.HashPassword : même coût bcrypt que l'inscription et les
+// changements de mot de passe (BCRYPT_COST / APP_ENV)
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := userentity.HashPassword("goshop-unknown-account")
+	return []byte(hash)
+})
+
 type LoginUsecase struct {
-	repo          userrepository.UserRepository
-	generateToken func(string) (string, error)
+	repo            userrepository.UserRepository
+	sessions        authrepository.RefreshSessionRepository
+	guard           LoginGuard
+	mfa             MFAVerifier
+	generateToken   func(string, []string, []string) (string, error)
+	generateRefresh func(string, string) (string, error)
+	generateMFA     func(string) (string, error)
+	validateToken   func(string) (jwt.MapClaims, error)
+	now             func() time.Time
+	newJTI          func() string
+	refreshExpiry   time.Duration
 	//logger        *setupLogging.Logger
 }
 
-func NewLoginUsecase(repo userrepository.UserRepository, logger *setupLogging.Logger) *LoginUsecase {
+// NewLoginUsecase : chaque login ouvre une session de refresh (refresh_sessions)
+// et retourne une paire access / refresh token. guard peut être nil (pas de
+// protection force brute), mfa aussi (pas de second facteur).
+func NewLoginUsecase(repo userrepository.UserRepository, sessions authrepository.RefreshSessionRepository, guard LoginGuard, mfa MFAVerifier, logger *setupLogging.Logger) *LoginUsecase {
 	return &LoginUsecase{
-		repo:          repo,
-		generateToken: utils.GenerateAccessToken,
+		repo:            repo,
+		sessions:        sessions,
+		guard:           guard,
+		mfa:             mfa,
+		generateToken:   utils.GenerateAccessToken,
+		generateRefresh: utils.GenerateRefreshToken,
+		generateMFA:     utils.GenerateMFAToken,
+		validateToken:   utils.ValidateToken,
+		now:             time.Now,
+		newJTI:          uuid.NewString,
+		refreshExpiry:   utils.RefreshTokenTTL(),
 		//logger:        logger.WithComponent("login_usecase"),
 	}
 }
@@ -54,16 +120,34 @@ func maskUsersID(id string) string {
 	return id[:4] + "..." + id[len(id)-4:]
 }
 
-func (uc *LoginUsecase) Execute(ctx context.Context, email, password string) (string, error) {
+// Execute authentifie l'utilisateur et retourne la paire access / refresh
+// token. Si le 2FA est actif, seul un token "mfa_pending" est retourné.
+func (uc *LoginUsecase) Execute(ctx context.Context, email, password string) (*LoginResult, error) {
 	start := time.Now()
 	logger := zerolog.Ctx(ctx)
 
+	email = userentity.NormalizeEmail(email)
 	maskedEmail := maskEmails(email)
 	logger.Info().
 		Str("operation", "login").
 		Str("email", maskedEmail).
 		Msg("🔐 Début authentification utilisateur")
 
+	// 0. Protection force brute : compte verrouillé, IP bloquée ou délai en cours
+	_, ip := utils.ClientInfoFromContext(ctx)
+	if uc.guard != nil {
+		if err := uc.guard.Check(ctx, email, ip); err != nil {
+			logger.Warn().
+				Err(err).
+				Str("operation", "login").
+				Str("email", maskedEmail).
+				Str("error_type", "login_blocked").
+				Msg("⛔ Tentative de connexion refusée (force brute)")
+			metrics.AuthLoginFailedTotal.Inc()
+			return nil, err
+		}
+	}
+
 	// 1. Recherche de l'utilisateur
 	logger.Debug().
 		Str("operation", "login").
@@ -80,10 +164,13 @@ func (uc *LoginUsecase) Execute(ctx context.Context, email, password string) (st
 				Dur("duration_ms", time.Since(start)).
 				Msg("❌ Utilisateur non trouvé")
 
+			// Temps constant : même coût bcrypt qu'un mauvais mot de passe
+			_ = bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+
 			// ✅ Incrémenter métrique d'échec
 			metrics.AuthLoginFailedTotal.Inc()
 
-			return "", utils.ErrInvalidCredentials
+			return nil, uc.registerFailure(ctx, email, ip)
 		}
 
 		logger.Error().
@@ -98,7 +185,7 @@ func (uc *LoginUsecase) Execute(ctx context.Context, email, password string) (st
 		// ✅ Incrémenter métrique d'échec (erreur système)
 		metrics.AuthLoginFailedTotal.Inc()
 
-		return "", utils.ErrInternalServer
+		return nil, utils.ErrInternalServer
 	}
 
 	maskedUserID := maskUsersID(user.ID)
@@ -130,7 +217,7 @@ func (uc *LoginUsecase) Execute(ctx context.Context, email, password string) (st
 		// ✅ Incrémenter métrique d'échec
 		metrics.AuthLoginFailedTotal.Inc()
 
-		return "", utils.ErrInvalidCredentials
+		return nil, uc.registerFailure(ctx, email, ip)
 	}
 
 	logger.Debug().
@@ -140,15 +227,53 @@ func (uc *LoginUsecase) Execute(ctx context.Context, email, password string) (st
 		Dur("password_check_duration_ms", time.Since(passwordStart)).
 		Msg("✅ Mot de passe validé")
 
+	// 2 bis. Second facteur : le token intermédiaire s'échange via ExecuteMFA.
+	// Les échecs ne sont remis à zéro qu'une fois le second facteur validé.
+	if uc.mfa != nil {
+		enabled, err := uc.mfa.Enabled(ctx, user.ID)
+		if err != nil {
+			logger.Error().
+				Err(err).
+				Str("operation", "login").
+				Str("user_id", maskedUserID).
+				Str("error_type", "database_error").
+				Msg("❌ Erreur lecture du second facteur")
+			metrics.AuthLoginFailedTotal.Inc()
+			return nil, utils.ErrInternalServer
+		}
+		if enabled {
+			mfaToken, err := uc.generateMFA(user.ID)
+			if err != nil {
+				logger.Error().
+					Err(err).
+					Str("operation", "login").
+					Str("user_id", maskedUserID).
+					Str("error_type", "token_generation_error").
+					Msg("❌ Erreur génération token mfa_pending")
+				metrics.AuthLoginFailedTotal.Inc()
+				return nil, utils.ErrInternalServer
+			}
+
+			logger.Info().
+				Str("operation", "login").
+				Str("email", maskedEmail).
+				Str("user_id", maskedUserID).
+				Dur("total_duration_ms", time.Since(start)).
+				Msg("🔐 Mot de passe validé, second facteur requis")
+			return &LoginResult{MFAToken: mfaToken}, nil
+		}
+	}
+
 	// 3. Génération du token
 	tokenStart := time.Now()
 	logger.Debug().
 		Str("operation", "login").
 		Str("email", maskedEmail).
 		Str("user_id", maskedUserID).
+		Strs("roles", user.Roles).
 		Msg("🔄 Génération token JWT")
 
-	token, err := uc.generateToken(user.ID)
+	token, err := uc.generateToken(user.ID, user.Roles, user.Permissions)
 	if err != nil {
 		logger.Error().
 			Err(err).
@@ -163,7 +288,18 @@ func (uc *LoginUsecase) Execute(ctx context.Context, email, password string) (st
 		// ✅ Incrémenter métrique d'échec (erreur système)
 		metrics.AuthLoginFailedTotal.Inc()
 
-		return "", utils.ErrInternalServer
+		return nil, utils.ErrInternalServer
+	}
+
+	// 4. Ouverture de la session de refresh
+	refresh, err := uc.openSession(ctx, user.ID)
+	if err != nil {
+		metrics.AuthLoginFailedTotal.Inc()
+		return nil, err
+	}
+
+	if uc.guard != nil {
+		uc.guard.RegisterSuccess(ctx, email)
 	}
 
 	logger.Info().
@@ -178,5 +314,167 @@ func (uc *LoginUsecase) Execute(ctx context.Context, email, password string) (st
 	// ✅ Incrémenter métrique de succès
 	metrics.AuthLoginTotal.Inc()
 
-	return token, nil
+	return &LoginResult{AccessToken: token, RefreshToken: refresh}, nil
+}
+
+// ExecuteMFA termine un login à deux facteurs : le token "mfa_pending" et un
+// code TOTP (ou de récupération) valides donnent la paire access / refresh
+// token. Un mauvais code compte comme un échec de login (force brute).
+func (uc *LoginUsecase) ExecuteMFA(ctx context.Context, mfaToken, code string) (*LoginResult, error) {
+	logger := zerolog.Ctx(ctx)
+
+	userID := uc.mfaSubject(mfaToken)
+	if userID == "" || uc.mfa == nil {
+		logger.Warn().
+			Str("operation", "login_mfa").
+			Str("error_type", "invalid_mfa_token").
+			Msg("❌ Token mfa_pending invalide ou expiré")
+		metrics.AuthLoginFailedTotal.Inc()
+		return nil, utils.ErrMFATokenInvalid
+	}
+	maskedUserID := maskUsersID(userID)
+
+	user, err := uc.repo.FindUserByID(userID)
+	if errors.Is(err, userrepository.ErrUserNotFound) {
+		metrics.AuthLoginFailedTotal.Inc()
+		return nil, utils.ErrMFATokenInvalid
+	}
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "login_mfa").
+			Str("user_id", maskedUserID).
+			Str("error_type", "database_error").
+			Msg("❌ Erreur base de données lors de la recherche utilisateur")
+		metrics.AuthLoginFailedTotal.Inc()
+		return nil, utils.ErrInternalServer
+	}
+
+	// Même protection force brute que le mot de passe (compte et IP)
+	_, ip := utils.ClientInfoFromContext(ctx)
+	if uc.guard != nil {
+		if err := uc.guard.Check(ctx, user.Email, ip); err != nil {
+			metrics.AuthLoginFailedTotal.Inc()
+			return nil, err
+		}
+	}
+
+	if err := uc.mfa.Verify(ctx, user.ID, code); err != nil {
+		metrics.AuthLoginFailedTotal.Inc()
+		if !errors.Is(err, utils.ErrMFACodeInvalid) {
+			return nil, err
+		}
+		logger.Warn().
+			Str("operation", "login_mfa").
+			Str("user_id", maskedUserID).
+			Str("error_type", "invalid_mfa_code").
+			Msg("❌ Code du second facteur refusé")
+		if uc.guard != nil {
+			if err := uc.guard.RegisterFailure(ctx, user.Email, ip); err != nil {
+				return nil, err
+			}
+		}
+		return nil, utils.ErrMFACodeInvalid
+	}
+
+	token, err := uc.generateToken(user.ID, user.Roles, user.Permissions)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "login_mfa").
+			Str("user_id", maskedUserID).
+			Str("error_type", "token_generation_error").
+			Msg("❌ Erreur génération token")
+		metrics.AuthLoginFailedTotal.Inc()
+		return nil, utils.ErrInternalServer
+	}
+
+	refresh, err := uc.openSession(ctx, user.ID)
+	if err != nil {
+		metrics.AuthLoginFailedTotal.Inc()
+		return nil, err
+	}
+
+	if uc.guard != nil {
+		uc.guard.RegisterSuccess(ctx, user.Email)
+	}
+
+	logger.Info().
+		Str("operation", "login_mfa").
+		Str("user_id", maskedUserID).
+		Msg("✅ Authentification à deux facteurs réussie, token généré")
+	metrics.AuthLoginTotal.Inc()
+
+	return &LoginResult{AccessToken: token, RefreshToken: refresh}, nil
+}
+
+// mfaSubject : utilisateur du token "mfa_pending" ("" si le token est
+// invalide, expiré ou d'un autre type)
+func (uc *LoginUsecase) mfaSubject(mfaToken string) string {
+	if mfaToken == "" {
+		return ""
+	}
+	claims, err := uc.validateToken(mfaToken)
+	if err != nil {
+		return ""
+	}
+	if t, _ := claims["type"].(string); t != "mfa_pending" {
+		return ""
+	}
+	sub, _ := claims["sub"].(string)
+	return sub
+}
+
+// registerFailure compte l'échec auprès de la protection force brute.
+// ErrInvalidCredentials, sauf si cet échec verrouille le compte.
+func (uc *LoginUsecase) registerFailure(ctx context.Context, email, ip string) error {
+	if uc.guard == nil {
+		return utils.ErrInvalidCredentials
+	}
+	if err := uc.guard.RegisterFailure(ctx, email, ip); err != nil {
+		return err
+	}
+	return utils.ErrInvalidCredentials
+}
+
+// openSession enregistre une nouvelle session de refresh et signe le refresh
+// token correspondant (jti = ID de la session)
+func (uc *LoginUsecase) openSession(ctx context.Context, userID string) (string, error) {
+	logger := zerolog.Ctx(ctx)
+
+	now := uc.now()
+	userAgent, ip := utils.ClientInfoFromContext(ctx)
+	session := &authentity.RefreshSession{
+		ID:         uc.newJTI(),
+		UserID:     userID,
+		ExpiresAt:  now.Add(uc.refreshExpiry),
+		CreatedAt:  now,
+		LastUsedAt: now,
+		UserAgent:  userAgent,
+		IPAddress:  ip,
+	}
+	// Un login ouvre une nouvelle famille de rotation
+	session.FamilyID = session.ID
+
+	if err := uc.sessions.Create(session); err != nil {
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("operation", "login").
+			Str("user_id", maskUsersID(userID)).
+			Msg("❌ Erreur création session de refresh")
+		return "", utils.ErrInternalServer
+	}
+
+	refresh, err := uc.generateRefresh(userID, session.ID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "login").
+			Str("user_id", maskUsersID(userID)).
+			Msg("❌ Erreur génération refresh token")
+		return "", utils.ErrInternalServer
+	}
+
+	return refresh, nil
 }
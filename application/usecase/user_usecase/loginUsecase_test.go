@@ -2,10 +2,13 @@
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	userusecase "Goshop/application/usecase/user_usecase"
 	"Goshop/config/setupLogging"
+	authentity "Goshop/domain/auth_entity"
 	userentity "Goshop/domain/entity/user_entity"
 	userrepository "Goshop/domain/repository/user_repository"
 	"Goshop/interfaces/utils"
@@ -28,9 +31,13 @@ func TestLoginUsecase_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mockrepo.NewMockUserRepository(ctrl)
+	sessions := mockrepo.NewMockRefreshSessionRepository(ctrl)
 	uc := userusecase.NewLoginUsecase(
 		repo,                         // 1er paramètre: repo
-		setupLogging.GetTestLogger(), // 2ème paramètre: logger (DERNIER)
+		sessions,                     // 2ème paramètre: sessions de refresh
+		nil,                          // 3ème paramètre: pas de protection force brute
+		nil,                          // 4ème paramètre: pas de second facteur
+		setupLogging.GetTestLogger(), // 5ème paramètre: logger (DERNIER)
 	)
 
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.DefaultCost)
@@ -44,10 +51,47 @@ func TestLoginUsecase_Success(t *testing.T) {
 		FindUserByEmail("test@example.com").
 		Return(fakeUser, nil)
 
-	token, err := uc.Execute(createContextWithLogger(), "test@example.com", "password")
+	// Le login ouvre une session de refresh dont l'ID est le jti du refresh token
+	var session *authentity.RefreshSession
+	sessions.EXPECT().
+		Create(gomock.Any()).
+		DoAndReturn(func(s *authentity.RefreshSession) error {
+			session = s
+			return nil
+		})
 
+	res, err := uc.Execute(createContextWithLogger(), "test@example.com", "password")
+
+	assert.NoError(t, err)
+	assert.False(t, res.MFARequired())
+	assert.NotEmpty(t, res.AccessToken)
+	assert.Equal(t, "123", session.UserID)
+	assert.False(t, session.Revoked)
+	assert.True(t, session.ExpiresAt.After(session.CreatedAt))
+
+	claims, err := utils.ValidateToken(res.RefreshToken)
 	assert.NoError(t, err)
-	assert.NotEmpty(t, token)
+	assert.Equal(t, "refresh", claims["type"])
+	assert.Equal(t, session.ID, claims["jti"])
+}
+
+func TestLoginUsecase_SessionCreateError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	sessions := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	uc := userusecase.NewLoginUsecase(repo, sessions, nil, nil, setupLogging.GetTestLogger())
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	repo.EXPECT().
+		FindUserByEmail("test@example.com").
+		Return(&userentity.UserEntity{ID: "123", Email: "test@example.com", Password: string(hashedPassword)}, nil)
+	sessions.EXPECT().Create(gomock.Any()).Return(errors.New("connection refused"))
+
+	_, err := uc.Execute(createContextWithLogger(), "test@example.com", "password")
+
+	assert.Equal(t, utils.ErrInternalServer, err)
 }
 
 func TestLoginUsecase_EmailNotFound(t *testing.T) {
@@ -55,9 +99,13 @@ func TestLoginUsecase_EmailNotFound(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mockrepo.NewMockUserRepository(ctrl)
+	sessions := mockrepo.NewMockRefreshSessionRepository(ctrl) // aucune session ouverte
 	uc := userusecase.NewLoginUsecase(
 		repo,                         // 1er paramètre: repo
-		setupLogging.GetTestLogger(), // 2ème paramètre: logger
+		sessions,                     // 2ème paramètre: sessions de refresh
+		nil,                          // 3ème paramètre: pas de protection force brute
+		nil,                          // 4ème paramètre: pas de second facteur
+		setupLogging.GetTestLogger(), // 5ème paramètre: logger
 	)
 
 	repo.EXPECT().
@@ -75,9 +123,13 @@ func TestLoginUsecase_InvalidPassword(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mockrepo.NewMockUserRepository(ctrl)
+	sessions := mockrepo.NewMockRefreshSessionRepository(ctrl) // aucune session ouverte
 	uc := userusecase.NewLoginUsecase(
 		repo,                         // 1er paramètre: repo
-		setupLogging.GetTestLogger(), // 2ème paramètre: logger
+		sessions,                     // 2ème paramètre: sessions de refresh
+		nil,                          // 3ème paramètre: pas de protection force brute
+		nil,                          // 4ème paramètre: pas de second facteur
+		setupLogging.GetTestLogger(), // 5ème paramètre: logger
 	)
 
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("correctpassword"), bcrypt.DefaultCost)
@@ -96,3 +148,261 @@ func TestLoginUsecase_InvalidPassword(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, utils.ErrInvalidCredentials, err)
 }
+
+func TestLoginUsecase_TokenCarriesRolesAndPermissions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	sessions := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	uc := userusecase.NewLoginUsecase(repo, sessions, nil, nil, setupLogging.GetTestLogger())
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	repo.EXPECT().
+		FindUserByEmail("admin@example.com").
+		Return(&userentity.UserEntity{
+			ID:          "admin-1",
+			Email:       "admin@example.com",
+			Password:    string(hashedPassword),
+			Roles:       []string{"admin"},
+			Permissions: []string{"products:write", "users:roles:write"},
+		}, nil)
+
+	sessions.EXPECT().Create(gomock.Any()).Return(nil)
+
+	res, err := uc.Execute(createContextWithLogger(), "admin@example.com", "password")
+	assert.NoError(t, err)
+
+	claims, err := utils.ValidateToken(res.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"admin"}, utils.ClaimStrings(claims, utils.CLAIM_ROLES))
+	assert.Equal(t, []string{"products:write", "users:roles:write"}, utils.ClaimStrings(claims, utils.CLAIM_PERMISSIONS))
+}
+
+// fakeLoginGuard enregistre les appels de la protection force brute
+type fakeLoginGuard struct {
+	checkErr   error
+	failureErr error
+	failures   []string // "email|ip"
+	successes  []string
+}
+
+func (g *fakeLoginGuard) Check(ctx context.Context, email, ip string) error {
+	return g.checkErr
+}
+
+func (g *fakeLoginGuard) RegisterFailure(ctx context.Context, email, ip string) error {
+	g.failures = append(g.failures, email+"|"+ip)
+	return g.failureErr
+}
+
+func (g *fakeLoginGuard) RegisterSuccess(ctx context.Context, email string) {
+	g.successes = append(g.successes, email)
+}
+
+func loginContextFromIP(ip string) context.Context {
+	return utils.WithClientInfo(createContextWithLogger(), "GoShop-Test", ip)
+}
+
+func TestLoginUsecase_Guard_BlockedBeforePasswordCheck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Ni recherche utilisateur ni session : le blocage intervient avant
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	sessions := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	guard := &fakeLoginGuard{checkErr: utils.WithRetryAfter(utils.ErrAccountLocked, time.Minute)}
+	uc := userusecase.NewLoginUsecase(repo, sessions, guard, nil, setupLogging.GetTestLogger())
+
+	_, err := uc.Execute(loginContextFromIP("203.0.113.7"), "test@example.com", "password")
+
+	assert.ErrorIs(t, err, utils.ErrAccountLocked)
+	assert.Empty(t, guard.failures)
+}
+
+func TestLoginUsecase_Guard_UnknownEmailCountsAsFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	sessions := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	guard := &fakeLoginGuard{}
+	uc := userusecase.NewLoginUsecase(repo, sessions, guard, nil, setupLogging.GetTestLogger())
+
+	repo.EXPECT().FindUserByEmail("unknown@mail.com").Return(nil, userrepository.ErrUserNotFound)
+
+	_, err := uc.Execute(loginContextFromIP("203.0.113.7"), "unknown@mail.com", "xxx")
+
+	assert.Equal(t, utils.ErrInvalidCredentials, err)
+	assert.Equal(t, []string{"unknown@mail.com|203.0.113.7"}, guard.failures)
+}
+
+func TestLoginUsecase_Guard_FailureLocksAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	sessions := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	guard := &fakeLoginGuard{failureErr: utils.WithRetryAfter(utils.ErrAccountLocked, 15*time.Minute)}
+	uc := userusecase.NewLoginUsecase(repo, sessions, guard, nil, setupLogging.GetTestLogger())
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("correctpassword"), bcrypt.MinCost)
+	repo.EXPECT().
+		FindUserByEmail("test@example.com").
+		Return(&userentity.UserEntity{ID: "123", Email: "test@example.com", Password: string(hashedPassword)}, nil)
+
+	_, err := uc.Execute(loginContextFromIP("203.0.113.7"), "test@example.com", "wrongpassword")
+
+	assert.ErrorIs(t, err, utils.ErrAccountLocked)
+}
+
+func TestLoginUsecase_Guard_SuccessResetsFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	sessions := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	guard := &fakeLoginGuard{}
+	uc := userusecase.NewLoginUsecase(repo, sessions, guard, nil, setupLogging.GetTestLogger())
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	repo.EXPECT().
+		FindUserByEmail("test@example.com").
+		Return(&userentity.UserEntity{ID: "123", Email: "test@example.com", Password: string(hashedPassword)}, nil)
+	sessions.EXPECT().Create(gomock.Any()).Return(nil)
+
+	_, err := uc.Execute(loginContextFromIP("203.0.113.7"), "test@example.com", "password")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test@example.com"}, guard.successes)
+	assert.Empty(t, guard.failures)
+}
+
+// fakeMFAVerifier : second facteur actif pour les utilisateurs de enabled
+type fakeMFAVerifier struct {
+	enabled   map[string]bool
+	verifyErr error
+	verified  []string // "userID|code"
+}
+
+func (f *fakeMFAVerifier) Enabled(ctx context.Context, userID string) (bool, error) {
+	return f.enabled[userID], nil
+}
+
+func (f *fakeMFAVerifier) Verify(ctx context.Context, userID, code string) error {
+	f.verified = append(f.verified, userID+"|"+code)
+	return f.verifyErr
+}
+
+func TestLoginUsecase_MFA_PasswordStepReturnsPendingToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Aucune session ouverte avant le second facteur
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	sessions := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	guard := &fakeLoginGuard{}
+	mfa := &fakeMFAVerifier{enabled: map[string]bool{"123": true}}
+	uc := userusecase.NewLoginUsecase(repo, sessions, guard, mfa, setupLogging.GetTestLogger())
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	repo.EXPECT().
+		FindUserByEmail("test@example.com").
+		Return(&userentity.UserEntity{ID: "123", Email: "test@example.com", Password: string(hashedPassword)}, nil)
+
+	res, err := uc.Execute(loginContextFromIP("203.0.113.7"), "test@example.com", "password")
+
+	assert.NoError(t, err)
+	assert.True(t, res.MFARequired())
+	assert.Empty(t, res.AccessToken)
+	assert.Empty(t, res.RefreshToken)
+	// Les échecs ne sont pas remis à zéro par le seul mot de passe
+	assert.Empty(t, guard.successes)
+
+	claims, err := utils.ValidateToken(res.MFAToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "mfa_pending", claims["type"])
+	assert.Equal(t, "123", claims["sub"])
+}
+
+func TestLoginUsecase_ExecuteMFA_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	sessions := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	guard := &fakeLoginGuard{}
+	mfa := &fakeMFAVerifier{enabled: map[string]bool{"123": true}}
+	uc := userusecase.NewLoginUsecase(repo, sessions, guard, mfa, setupLogging.GetTestLogger())
+
+	mfaToken, err := utils.GenerateMFAToken("123")
+	assert.NoError(t, err)
+
+	repo.EXPECT().
+		FindUserByID("123").
+		Return(&userentity.UserEntity{ID: "123", Email: "test@example.com", Roles: []string{"customer"}}, nil)
+	sessions.EXPECT().Create(gomock.Any()).Return(nil)
+
+	res, err := uc.ExecuteMFA(loginContextFromIP("203.0.113.7"), mfaToken, "123456")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"123|123456"}, mfa.verified)
+	assert.Equal(t, []string{"test@example.com"}, guard.successes)
+
+	claims, err := utils.ValidateToken(res.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "access", claims["type"])
+	assert.Equal(t, []string{"customer"}, utils.ClaimStrings(claims, utils.CLAIM_ROLES))
+	assert.NotEmpty(t, res.RefreshToken)
+}
+
+func TestLoginUsecase_ExecuteMFA_WrongCodeCountsAsFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	sessions := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	guard := &fakeLoginGuard{}
+	mfa := &fakeMFAVerifier{enabled: map[string]bool{"123": true}, verifyErr: utils.ErrMFACodeInvalid}
+	uc := userusecase.NewLoginUsecase(repo, sessions, guard, mfa, setupLogging.GetTestLogger())
+
+	mfaToken, _ := utils.GenerateMFAToken("123")
+	repo.EXPECT().
+		FindUserByID("123").
+		Return(&userentity.UserEntity{ID: "123", Email: "test@example.com"}, nil)
+
+	_, err := uc.ExecuteMFA(loginContextFromIP("203.0.113.7"), mfaToken, "000000")
+
+	assert.ErrorIs(t, err, utils.ErrMFACodeInvalid)
+	assert.Equal(t, []string{"test@example.com|203.0.113.7"}, guard.failures)
+
+	// L'échec qui verrouille le compte est remonté tel quel
+	guard.failureErr = utils.WithRetryAfter(utils.ErrAccountLocked, 15*time.Minute)
+	repo.EXPECT().
+		FindUserByID("123").
+		Return(&userentity.UserEntity{ID: "123", Email: "test@example.com"}, nil)
+
+	_, err = uc.ExecuteMFA(loginContextFromIP("203.0.113.7"), mfaToken, "000000")
+
+	assert.ErrorIs(t, err, utils.ErrAccountLocked)
+}
+
+func TestLoginUsecase_ExecuteMFA_RejectsOtherTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Ni recherche utilisateur ni vérification du code
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	sessions := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	mfa := &fakeMFAVerifier{}
+	uc := userusecase.NewLoginUsecase(repo, sessions, nil, mfa, setupLogging.GetTestLogger())
+
+	access, _ := utils.GenerateAccessToken("123", nil, nil)
+	refresh, _ := utils.GenerateRefreshToken("123", "jti-1")
+
+	for _, token := range []string{"", "not-a-jwt", access, refresh} {
+		_, err := uc.ExecuteMFA(createContextWithLogger(), token, "123456")
+		assert.ErrorIs(t, err, utils.ErrMFATokenInvalid)
+	}
+	assert.Empty(t, mfa.verified)
+}
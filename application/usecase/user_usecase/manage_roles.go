@@ -0,0 +1,134 @@
+// application/usecase/user_usecase/manage_roles.go
+package userusecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	userentity "Goshop/domain/entity/user_entity"
+	userrepository "Goshop/domain/repository/user_repository"
+	"Goshop/interfaces/utils"
+
+	"github.com/rs/zerolog"
+)
+
+// ManageRolesUsecase : attribution et retrait des rôles (endpoint admin).
+// Les changements s'appliquent au prochain access token de l'utilisateur.
+type ManageRolesUsecase struct {
+	repo userrepository.UserRepository
+}
+
+func NewManageRolesUsecase(repo userrepository.UserRepository) *ManageRolesUsecase {
+	return &ManageRolesUsecase{repo: repo}
+}
+
+// GetAccess retourne l'utilisateur avec ses rôles et permissions
+func (uc *ManageRolesUsecase) GetAccess(ctx context.Context, userID string) (*userentity.UserEntity, error) {
+	logger := zerolog.Ctx(ctx)
+
+	user, err := uc.repo.FindUserByID(userID)
+	if err != nil {
+		if errors.Is(err, userrepository.ErrUserNotFound) {
+			return nil, utils.ErrUserNotFound
+		}
+		logger.Error().
+			Err(err).
+			Str("operation", "get_access").
+			Str("user_id", maskUserID(userID)).
+			Msg("Failed to load user roles")
+		return nil, utils.ErrInternalServer
+	}
+
+	return user, nil
+}
+
+// Grant attribue `role` à l'utilisateur (idempotent)
+func (uc *ManageRolesUsecase) Grant(ctx context.Context, actorID, userID, role string) (*userentity.UserEntity, error) {
+	logger := zerolog.Ctx(ctx)
+	start := time.Now()
+
+	role = normalizeRole(role)
+	if role == "" {
+		return nil, utils.ErrValidationFailed
+	}
+
+	if _, err := uc.GetAccess(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.GrantRole(userID, role, actorID); err != nil {
+		switch {
+		case errors.Is(err, userrepository.ErrRoleNotFound):
+			return nil, utils.ErrRoleNotFound
+		case errors.Is(err, userrepository.ErrUserNotFound):
+			return nil, utils.ErrUserNotFound
+		}
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("operation", "grant_role").
+			Str("role", role).
+			Msg("Failed to grant role")
+		return nil, utils.ErrRoleUpdateFail
+	}
+
+	logger.Info().
+		Str("operation", "grant_role").
+		Str("actor_id", maskUserID(actorID)).
+		Str("user_id", maskUserID(userID)).
+		Str("role", role).
+		Dur("duration_ms", time.Since(start)).
+		Msg("Role granted")
+
+	return uc.GetAccess(ctx, userID)
+}
+
+// Revoke retire `role` à l'utilisateur (idempotent). Un admin ne peut pas
+// retirer son propre rôle admin, pour ne pas perdre l'accès à cet endpoint.
+func (uc *ManageRolesUsecase) Revoke(ctx context.Context, actorID, userID, role string) (*userentity.UserEntity, error) {
+	logger := zerolog.Ctx(ctx)
+	start := time.Now()
+
+	role = normalizeRole(role)
+	if role == "" {
+		return nil, utils.ErrValidationFailed
+	}
+
+	if actorID == userID && role == userentity.RoleAdmin {
+		logger.Warn().
+			Str("operation", "revoke_role").
+			Str("user_id", maskUserID(userID)).
+			Msg("Admin tried to revoke its own admin role")
+		return nil, utils.ErrRoleSelfRevoke
+	}
+
+	if _, err := uc.GetAccess(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.RevokeRole(userID, role); err != nil {
+		logger.Error().
+			Err(err).
+			Stack().
+			Str("operation", "revoke_role").
+			Str("role", role).
+			Msg("Failed to revoke role")
+		return nil, utils.ErrRoleUpdateFail
+	}
+
+	logger.Info().
+		Str("operation", "revoke_role").
+		Str("actor_id", maskUserID(actorID)).
+		Str("user_id", maskUserID(userID)).
+		Str("role", role).
+		Dur("duration_ms", time.Since(start)).
+		Msg("Role revoked")
+
+	return uc.GetAccess(ctx, userID)
+}
+
+func normalizeRole(role string) string {
+	return strings.ToLower(strings.TrimSpace(role))
+}
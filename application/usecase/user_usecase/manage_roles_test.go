@@ -0,0 +1,101 @@
+package userusecase_test
+
+import (
+	"testing"
+
+	userusecase "Goshop/application/usecase/user_usecase"
+	userentity "Goshop/domain/entity/user_entity"
+	userrepository "Goshop/domain/repository/user_repository"
+	"Goshop/interfaces/utils"
+	mockrepo "Goshop/mocks/repository"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestManageRolesUsecase_Grant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	uc := userusecase.NewManageRolesUsecase(repo)
+
+	before := &userentity.UserEntity{ID: "user-2", Roles: []string{"customer"}}
+	after := &userentity.UserEntity{
+		ID:          "user-2",
+		Roles:       []string{"customer", "manager"},
+		Permissions: []string{"orders:write", "products:write"},
+	}
+
+	gomock.InOrder(
+		repo.EXPECT().FindUserByID("user-2").Return(before, nil),
+		repo.EXPECT().GrantRole("user-2", "manager", "admin-1").Return(nil),
+		repo.EXPECT().FindUserByID("user-2").Return(after, nil),
+	)
+
+	user, err := uc.Grant(createContextWithLogger(), "admin-1", "user-2", "  Manager ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"customer", "manager"}, user.Roles)
+}
+
+func TestManageRolesUsecase_Grant_UnknownRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	uc := userusecase.NewManageRolesUsecase(repo)
+
+	repo.EXPECT().FindUserByID("user-2").Return(&userentity.UserEntity{ID: "user-2"}, nil)
+	repo.EXPECT().GrantRole("user-2", "superuser", "admin-1").Return(userrepository.ErrRoleNotFound)
+
+	_, err := uc.Grant(createContextWithLogger(), "admin-1", "user-2", "superuser")
+
+	assert.ErrorIs(t, err, utils.ErrRoleNotFound)
+}
+
+func TestManageRolesUsecase_Grant_UserNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	uc := userusecase.NewManageRolesUsecase(repo)
+
+	repo.EXPECT().FindUserByID("missing").Return(nil, userrepository.ErrUserNotFound)
+
+	_, err := uc.Grant(createContextWithLogger(), "admin-1", "missing", "manager")
+
+	assert.ErrorIs(t, err, utils.ErrUserNotFound)
+}
+
+func TestManageRolesUsecase_Revoke_OwnAdminRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	uc := userusecase.NewManageRolesUsecase(repo)
+
+	// Aucun appel repository attendu
+	_, err := uc.Revoke(createContextWithLogger(), "admin-1", "admin-1", "admin")
+
+	assert.ErrorIs(t, err, utils.ErrRoleSelfRevoke)
+}
+
+func TestManageRolesUsecase_Revoke(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	uc := userusecase.NewManageRolesUsecase(repo)
+
+	gomock.InOrder(
+		repo.EXPECT().FindUserByID("user-2").Return(&userentity.UserEntity{ID: "user-2", Roles: []string{"customer", "manager"}}, nil),
+		repo.EXPECT().RevokeRole("user-2", "manager").Return(nil),
+		repo.EXPECT().FindUserByID("user-2").Return(&userentity.UserEntity{ID: "user-2", Roles: []string{"customer"}}, nil),
+	)
+
+	user, err := uc.Revoke(createContextWithLogger(), "admin-1", "user-2", "manager")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"customer"}, user.Roles)
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
-	"golang.org/x/crypto/bcrypt"
 
 	"Goshop/application/metrics"
 	"Goshop/config/setupLogging"
@@ -17,14 +16,21 @@ import (
 	"Goshop/interfaces/utils"
 )
 
+// EmailVerifier envoie le lien de vérification de l'email après l'inscription
+type EmailVerifier interface {
+	SendVerification(ctx context.Context, userID, email string) error
+}
+
 type RegisterUsecase struct {
-	repo userrepository.UserRepository
+	repo     userrepository.UserRepository
+	verifier EmailVerifier // nil : pas d'email de vérification
 	//logger *setupLogging.Logger
 }
 
-func NewRegisterUsecase(repo userrepository.UserRepository, logger *setupLogging.Logger) *RegisterUsecase {
+func NewRegisterUsecase(repo userrepository.UserRepository, verifier EmailVerifier, logger *setupLogging.Logger) *RegisterUsecase {
 	return &RegisterUsecase{
-		repo: repo,
+		repo:     repo,
+		verifier: verifier,
 		//logger: logger.WithComponent("register_usecase"),
 	}
 }
@@ -44,10 +50,18 @@ func maskUserID(userID string) string {
 	return userID
 }
 
+// Execute crée un compte sans nom : le profil client reprend la partie
+// locale de l'email comme prénom.
 func (uc *RegisterUsecase) Execute(ctx context.Context, email, password string) (*userentity.UserEntity, error) {
+	return uc.ExecuteWithProfile(ctx, email, password, "", "")
+}
+
+// ExecuteWithProfile crée le compte et le profil client qui lui est rattaché
+func (uc *RegisterUsecase) ExecuteWithProfile(ctx context.Context, email, password, firstName, lastName string) (*userentity.UserEntity, error) {
 	start := time.Now()
 	logger := zerolog.Ctx(ctx)
 
+	email = userentity.NormalizeEmail(email)
 	maskedEmail := maskEmail(email)
 
 	// ✅ Pas de logger local — utilise uc.logger directement
@@ -96,7 +110,7 @@ func (uc *RegisterUsecase) Execute(ctx context.Context, email, password string)
 		Str("email", maskedEmail).
 		Msg("🔒 Hash du mot de passe")
 
-	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashed, err := userentity.HashPassword(password)
 	if err != nil {
 		logger.Error().
 			Err(err).
@@ -114,10 +128,15 @@ func (uc *RegisterUsecase) Execute(ctx context.Context, email, password string)
 		Msg("✅ Mot de passe hashé")
 
 	// 3. Création de l'entité
+	if firstName == "" && lastName == "" {
+		firstName, _, _ = strings.Cut(email, "@")
+	}
 	user := &userentity.UserEntity{
-		ID:       uuid.NewString(),
-		Email:    email,
-		Password: string(hashed),
+		ID:        uuid.NewString(),
+		Email:     email,
+		Password:  hashed,
+		FirstName: firstName,
+		LastName:  lastName,
 	}
 
 	maskedUserID := maskUserID(user.ID)
@@ -166,5 +185,16 @@ func (uc *RegisterUsecase) Execute(ctx context.Context, email, password string)
 		Msg("🎉 Utilisateur créé avec succès")
 	metrics.AuthRegisterTotal.Inc()
 
+	// 5. Email de vérification : un échec n'annule pas l'inscription
+	if uc.verifier != nil {
+		if err := uc.verifier.SendVerification(ctx, created.ID, created.Email); err != nil {
+			logger.Error().
+				Err(err).
+				Str("operation", "register").
+				Str("user_id", maskUserID(created.ID)).
+				Msg("❌ Échec envoi email de vérification")
+		}
+	}
+
 	return created, nil
 }
@@ -2,6 +2,7 @@
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	userusecase "Goshop/application/usecase/user_usecase"
@@ -13,6 +14,7 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // createContextWithLogger crée un contexte avec un logger silencieux pour les tests
@@ -28,7 +30,8 @@ func TestRegisterUsecase_Success(t *testing.T) {
 	repo := mockrepo.NewMockUserRepository(ctrl)
 	uc := userusecase.NewRegisterUsecase(
 		repo,                         // 1er paramètre: repo
-		setupLogging.GetTestLogger(), // 2ème paramètre: logger (DERNIER)
+		nil,                          // 2ème paramètre: pas d'email de vérification
+		setupLogging.GetTestLogger(), // 3ème paramètre: logger (DERNIER)
 	)
 
 	repo.EXPECT().
@@ -46,3 +49,117 @@ func TestRegisterUsecase_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "new@mail.com", user.Email)
 }
+
+func TestRegisterUsecase_NormalizesEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	uc := userusecase.NewRegisterUsecase(repo, nil, setupLogging.GetTestLogger())
+
+	// L'email est enregistré sous la forme recherchée par la connexion et la
+	// réinitialisation du mot de passe
+	repo.EXPECT().
+		FindUserByEmail("new@mail.com").
+		Return(nil, userrepository.ErrUserNotFound)
+	repo.EXPECT().
+		CreateUser(gomock.Any()).
+		DoAndReturn(func(u *userentity.UserEntity) (*userentity.UserEntity, error) {
+			assert.Equal(t, "new@mail.com", u.Email)
+			return u, nil
+		})
+
+	_, err := uc.Execute(RecreateContextWithLogger(), " New@Mail.COM ", "pass123")
+	assert.NoError(t, err)
+}
+
+func TestRegisterUsecase_HashesWithConfiguredCost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Setenv("BCRYPT_COST", "5")
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	uc := userusecase.NewRegisterUsecase(repo, nil, setupLogging.GetTestLogger())
+
+	repo.EXPECT().FindUserByEmail("new@mail.com").Return(nil, userrepository.ErrUserNotFound)
+	repo.EXPECT().
+		CreateUser(gomock.Any()).
+		DoAndReturn(func(u *userentity.UserEntity) (*userentity.UserEntity, error) {
+			// Même coût que userentity.HashPassword (et que le hash factice du login)
+			cost, err := bcrypt.Cost([]byte(u.Password))
+			assert.NoError(t, err)
+			assert.Equal(t, 5, cost)
+			return u, nil
+		})
+
+	_, err := uc.Execute(RecreateContextWithLogger(), "new@mail.com", "pass123")
+	assert.NoError(t, err)
+}
+
+func TestRegisterUsecase_CustomerProfileNames(t *testing.T) {
+	tests := []struct {
+		name              string
+		firstName         string
+		lastName          string
+		expectedFirstName string
+		expectedLastName  string
+	}{
+		{"noms fournis", "Ada", "Lovelace", "Ada", "Lovelace"},
+		{"sans nom : partie locale de l'email", "", "", "new", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mockrepo.NewMockUserRepository(ctrl)
+			uc := userusecase.NewRegisterUsecase(repo, nil, setupLogging.GetTestLogger())
+
+			repo.EXPECT().
+				FindUserByEmail("new@mail.com").
+				Return(nil, userrepository.ErrUserNotFound)
+
+			// Le profil client est créé avec le compte
+			repo.EXPECT().
+				CreateUser(gomock.Any()).
+				DoAndReturn(func(user *userentity.UserEntity) (*userentity.UserEntity, error) {
+					assert.Equal(t, tt.expectedFirstName, user.FirstName)
+					assert.Equal(t, tt.expectedLastName, user.LastName)
+					return user, nil
+				})
+
+			_, err := uc.ExecuteWithProfile(RecreateContextWithLogger(), "new@mail.com", "pass123", tt.firstName, tt.lastName)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// fakeEmailVerifier enregistre les envois de lien de vérification
+type fakeEmailVerifier struct {
+	sent []string
+	err  error
+}
+
+func (f *fakeEmailVerifier) SendVerification(_ context.Context, userID, email string) error {
+	f.sent = append(f.sent, userID+" "+email)
+	return f.err
+}
+
+func TestRegisterUsecase_SendsEmailVerification(t *testing.T) {
+	for name, sendErr := range map[string]error{"envoyé": nil, "échec d'envoi": errors.New("smtp down")} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mockrepo.NewMockUserRepository(ctrl)
+			verifier := &fakeEmailVerifier{err: sendErr}
+			uc := userusecase.NewRegisterUsecase(repo, verifier, setupLogging.GetTestLogger())
+
+			repo.EXPECT().FindUserByEmail("new@mail.com").Return(nil, userrepository.ErrUserNotFound)
+			repo.EXPECT().CreateUser(gomock.Any()).Return(&userentity.UserEntity{ID: "123", Email: "new@mail.com"}, nil)
+
+			// Un échec d'envoi n'annule pas l'inscription
+			user, err := uc.Execute(RecreateContextWithLogger(), "new@mail.com", "pass123")
+			assert.NoError(t, err)
+			assert.Equal(t, "123", user.ID)
+			assert.Equal(t, []string{"123 new@mail.com"}, verifier.sent)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// application/usecase/user_usecase/unlock_account.go
+package userusecase
+
+import (
+	"context"
+	"time"
+
+	userrepository "Goshop/domain/repository/user_repository"
+	"Goshop/interfaces/utils"
+
+	"github.com/rs/zerolog"
+)
+
+// AccountUnlocker lève le verrouillage force brute d'un compte
+// (voir authusecase.LoginGuard)
+type AccountUnlocker interface {
+	Unlock(ctx context.Context, email string) error
+}
+
+// UnlockAccountUsecase : déverrouillage d'un compte par un admin, sans
+// attendre la fin du verrouillage temporaire
+type UnlockAccountUsecase struct {
+	manageRoles *ManageRolesUsecase
+	unlocker    AccountUnlocker
+}
+
+func NewUnlockAccountUsecase(repo userrepository.UserRepository, unlocker AccountUnlocker) *UnlockAccountUsecase {
+	return &UnlockAccountUsecase{
+		manageRoles: NewManageRolesUsecase(repo),
+		unlocker:    unlocker,
+	}
+}
+
+// Execute efface les échecs de connexion et le verrouillage de l'utilisateur
+// (idempotent : un compte non verrouillé n'est pas une erreur)
+func (uc *UnlockAccountUsecase) Execute(ctx context.Context, actorID, userID string) error {
+	logger := zerolog.Ctx(ctx)
+	start := time.Now()
+
+	user, err := uc.manageRoles.GetAccess(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.unlocker.Unlock(ctx, user.Email); err != nil {
+		logger.Error().
+			Err(err).
+			Str("operation", "unlock_account").
+			Str("user_id", maskUserID(userID)).
+			Msg("Failed to unlock account")
+		return utils.ErrInternalServer
+	}
+
+	utils.SecurityAudit(ctx, utils.AUDIT_ACCOUNT_UNLOCKED).
+		Str("actor_id", maskUserID(actorID)).
+		Str("user_id", maskUserID(userID)).
+		Dur("duration_ms", time.Since(start)).
+		Msg("Account unlocked by an administrator")
+
+	return nil
+}
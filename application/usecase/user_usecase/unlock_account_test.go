@@ -0,0 +1,70 @@
+package userusecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	userusecase "Goshop/application/usecase/user_usecase"
+	userentity "Goshop/domain/entity/user_entity"
+	userrepository "Goshop/domain/repository/user_repository"
+	"Goshop/interfaces/utils"
+	mockrepo "Goshop/mocks/repository"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type fakeUnlocker struct {
+	err      error
+	unlocked []string
+}
+
+func (u *fakeUnlocker) Unlock(ctx context.Context, email string) error {
+	u.unlocked = append(u.unlocked, email)
+	return u.err
+}
+
+func TestUnlockAccountUsecase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	unlocker := &fakeUnlocker{}
+	uc := userusecase.NewUnlockAccountUsecase(repo, unlocker)
+
+	repo.EXPECT().FindUserByID("user-2").Return(&userentity.UserEntity{ID: "user-2", Email: "alice@example.com"}, nil)
+
+	assert.NoError(t, uc.Execute(createContextWithLogger(), "admin-1", "user-2"))
+	assert.Equal(t, []string{"alice@example.com"}, unlocker.unlocked)
+}
+
+func TestUnlockAccountUsecase_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		findErr   error
+		unlockErr error
+		wantErr   error
+	}{
+		{"utilisateur inconnu", userrepository.ErrUserNotFound, nil, utils.ErrUserNotFound},
+		{"stockage indisponible", nil, errors.New("connection refused"), utils.ErrInternalServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mockrepo.NewMockUserRepository(ctrl)
+			unlocker := &fakeUnlocker{err: tt.unlockErr}
+			uc := userusecase.NewUnlockAccountUsecase(repo, unlocker)
+
+			if tt.findErr != nil {
+				repo.EXPECT().FindUserByID("user-2").Return(nil, tt.findErr)
+			} else {
+				repo.EXPECT().FindUserByID("user-2").Return(&userentity.UserEntity{ID: "user-2", Email: "alice@example.com"}, nil)
+			}
+
+			err := uc.Execute(createContextWithLogger(), "admin-1", "user-2")
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
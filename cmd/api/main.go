@@ -18,6 +18,8 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -27,7 +29,11 @@ import (
 
 	"Goshop/config"
 	"Goshop/config/setupLogging"
+	"Goshop/domain/mailer"
+	"Goshop/domain/storage"
+	mailerinfra "Goshop/infrastructure/mailer"
 	"Goshop/infrastructure/postgres"
+	storageinfra "Goshop/infrastructure/storage"
 	"Goshop/interfaces/utils"
 	"Goshop/internal/app"
 
@@ -70,9 +76,93 @@ func main() {
 
 	appLogger.Info().Msg("✅ Connexion à la base de données établie")
 
+	// Redis (optionnel) : compteurs d'échecs de login et cache d'idempotence
+	redisAddr := net.JoinHostPort(cfg.RedisHost, strconv.Itoa(cfg.RedisPort))
+	if err := utils.InitRedis(redisAddr); err != nil {
+		appLogger.Warn().
+			Err(err).
+			Str("redis_addr", redisAddr).
+			Msg("Redis indisponible : repli sur Postgres jusqu'à son retour")
+	} else {
+		appLogger.Info().Str("redis_addr", redisAddr).Msg("✅ Connexion Redis établie")
+	}
+
+	// Signature des curseurs de pagination
+	if cfg.CursorSecret == "" {
+		appLogger.Warn().Msg("CURSOR_SECRET absent : clé aléatoire, les curseurs ne survivent pas à un redémarrage")
+	}
+	utils.InitCursorSecret(cfg.CursorSecret)
+
+	// Claims et durées de vie des tokens
+	if err := utils.InitJWTConfig(utils.JWTConfig{
+		Issuer:     cfg.JWTIssuer,
+		Audience:   cfg.JWTAudience,
+		AccessTTL:  cfg.AccessTokenTTL,
+		RefreshTTL: cfg.RefreshTokenTTL,
+		Leeway:     cfg.JWTLeeway,
+	}); err != nil {
+		appLogger.Fatal().
+			Err(err).
+			Msg("❌ Configuration JWT invalide")
+	}
+	if cfg.JWTKeyGracePeriod < cfg.RefreshTokenTTL {
+		appLogger.Warn().
+			Dur("grace_period", cfg.JWTKeyGracePeriod).
+			Dur("refresh_ttl", cfg.RefreshTokenTTL).
+			Msg("JWT_KEY_GRACE_PERIOD < REFRESH_TOKEN_TTL : une rotation de clé invalidera des refresh tokens")
+	}
+
+	// Clés de signature JWT : pas de démarrage sans clé active
+	jwtKeys, err := utils.LoadJWTKeys(cfg.JWTKeys, cfg.JWTKeyGracePeriod)
+	if err != nil {
+		appLogger.Fatal().
+			Err(err).
+			Msg("❌ Clés JWT absentes ou invalides (JWT_KEYS)")
+	}
+	utils.InitJWTKeys(jwtKeys)
+
+	signingKey, _ := jwtKeys.SigningKey(time.Now())
+	appLogger.Info().
+		Str("kid", signingKey.KID).
+		Str("alg", signingKey.Algorithm).
+		Int("published_keys", len(jwtKeys.JWKS(time.Now()).Keys)).
+		Msg("✅ Clés JWT chargées")
+
+	// Emails transactionnels (mot de passe oublié, vérification de l'email)
+	mail, err := newMailer(cfg)
+	if err != nil {
+		appLogger.Fatal().
+			Err(err).
+			Str("mailer", cfg.Mailer).
+			Msg("❌ Configuration du mailer invalide")
+	}
+	if cfg.Mailer == "log" && cfg.Environment == "production" {
+		appLogger.Warn().Msg("MAILER=log en production : les emails (et leurs liens) ne sont que logués")
+	}
+	appLogger.Info().
+		Str("mailer", cfg.Mailer).
+		Str("public_url", cfg.PublicURL).
+		Msg("✅ Mailer configuré")
+
+	// Contenu des images produit
+	blobs, err := newBlobStore(cfg)
+	if err != nil {
+		appLogger.Fatal().
+			Err(err).
+			Str("storage", cfg.Storage).
+			Msg("❌ Configuration du stockage invalide")
+	}
+	appLogger.Info().
+		Str("storage", cfg.Storage).
+		Int64("image_max_bytes", cfg.ImageMaxBytes).
+		Msg("✅ Stockage des images configuré")
+
 	// 4. Créer l'application avec logging
 	appLogger.Info().Msg("Initialisation de l'application...")
-	appInstance := app.NewApp(db, appLogger)
+	appInstance := app.NewApp(db, appLogger,
+		app.MailConfig{Mailer: mail, PublicURL: cfg.PublicURL},
+		app.MediaConfig{Blobs: blobs, MaxImageBytes: cfg.ImageMaxBytes},
+	)
 
 	// 5. Configurer le serveur
 	server := &http.Server{
@@ -122,3 +212,46 @@ func main() {
 
 	appLogger.Info().Msg("✅ Serveur arrêté proprement")
 }
+
+// newMailer construit le mailer choisi par MAILER. L'envoi SMTP se fait en
+// arrière-plan pour ne pas retarder les réponses HTTP.
+func newMailer(cfg *config.Config) (mailer.Mailer, error) {
+	switch cfg.Mailer {
+	case "log":
+		return mailerinfra.NewLogMailer(), nil
+	case "file":
+		return mailerinfra.NewFileMailer(cfg.MailerDir)
+	case "smtp":
+		smtpMailer, err := mailerinfra.NewSMTPMailer(mailerinfra.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return mailerinfra.NewAsyncMailer(smtpMailer, 30*time.Second), nil
+	default:
+		return nil, fmt.Errorf("unknown MAILER %q (log, file or smtp)", cfg.Mailer)
+	}
+}
+
+// newBlobStore construit le stockage des images choisi par STORAGE
+func newBlobStore(cfg *config.Config) (storage.BlobStore, error) {
+	switch cfg.Storage {
+	case "local":
+		return storageinfra.NewLocalBlobStore(cfg.StorageDir)
+	case "s3":
+		return storageinfra.NewS3BlobStore(storageinfra.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		})
+	default:
+		return nil, fmt.Errorf("unknown STORAGE %q (local or s3)", cfg.Storage)
+	}
+}
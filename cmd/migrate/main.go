@@ -0,0 +1,169 @@
+// cmd/migrate/main.go
+//
+// Applique les migrations SQL embarquées (migrations/*.sql).
+//
+//	migrate up             applique les migrations en attente
+//	migrate down [N]       annule les N dernières migrations (1 par défaut)
+//	migrate status         affiche l'état de chaque migration
+//	migrate redo           annule puis réapplique la dernière migration
+//	migrate create <name>  crée une paire de fichiers up/down dans -dir
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+
+	"Goshop/config"
+	"Goshop/config/setupLogging"
+	"Goshop/infrastructure/postgres"
+	"Goshop/infrastructure/postgres/migrate"
+	"Goshop/migrations"
+
+	_ "github.com/lib/pq"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage: migrate [flags] <command> [args]
+
+Commands:
+  up             apply all pending migrations
+  down [N]       roll back the last N migrations (default 1)
+  status         show applied and pending migrations
+  redo           roll back then re-apply the last migration
+  create <name>  create a new up/down migration pair in -dir
+
+Flags:
+`)
+	flag.PrintDefaults()
+}
+
+func main() {
+	dir := flag.String("dir", "migrations", "migrations directory (used by create)")
+	flag.Usage = usage
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	loggingConfig := setupLogging.GetDefaultConfig()
+	logger := setupLogging.NewLogger(loggingConfig).WithComponent("migrate")
+
+	// create ne touche pas à la base
+	if args[0] == "create" {
+		if len(args) != 2 {
+			usage()
+			os.Exit(2)
+		}
+		upPath, downPath, err := migrate.Create(*dir, args[1])
+		if err != nil {
+			logger.Fatal().Err(err).Msg("❌ Création de la migration impossible")
+		}
+		fmt.Println(upPath)
+		fmt.Println(downPath)
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	ctx = logger.NewContext(ctx)
+
+	cfg := config.LoadConfig()
+	db, err := postgres.Connect(cfg.GetDBConnString())
+	if err != nil {
+		logger.Fatal().
+			Err(err).
+			Str("db_host", cfg.DBHost).
+			Str("db_name", cfg.DBName).
+			Msg("❌ Connexion à la base de données impossible")
+	}
+	defer db.Close()
+
+	migrator, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("❌ Lecture des migrations impossible")
+	}
+
+	if err := run(ctx, migrator, args); err != nil {
+		logger.Error().Err(err).Str("command", args[0]).Msg("❌ Migration échouée")
+		db.Close()
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, migrator *migrate.Migrator, args []string) error {
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+		for _, m := range applied {
+			fmt.Printf("applied  %s\n", m)
+		}
+
+	case "down":
+		n := 1
+		if len(args) > 1 {
+			parsed, err := strconv.Atoi(args[1])
+			if err != nil || parsed < 1 {
+				return fmt.Errorf("down: invalid count %q", args[1])
+			}
+			n = parsed
+		}
+		reverted, err := migrator.Down(ctx, n)
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			fmt.Println("no migration to roll back")
+		}
+		for _, m := range reverted {
+			fmt.Printf("reverted %s\n", m)
+		}
+
+	case "redo":
+		redone, err := migrator.Redo(ctx)
+		if err != nil {
+			return err
+		}
+		if redone == nil {
+			fmt.Println("no migration to redo")
+			return nil
+		}
+		fmt.Printf("redone   %s\n", redone)
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "-"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%03d\t%s\t%s\t%s\n", s.Version, s.Name, s.State, appliedAt)
+		}
+		return w.Flush()
+
+	default:
+		usage()
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -30,6 +31,46 @@ type Config struct {
 	// Redis
 	RedisHost string `mapstructure:"REDIS_HOST"`
 	RedisPort int    `mapstructure:"REDIS_PORT"`
+
+	// Clé HMAC des curseurs de pagination (partagée entre les replicas)
+	CursorSecret string `mapstructure:"CURSOR_SECRET"` // sensible
+
+	// Clés de signature JWT : `kid:fichier-ou-env:VAR[@activation]`, séparées par des virgules
+	JWTKeys string `mapstructure:"JWT_KEYS"`
+	// Durée pendant laquelle une clé remplacée vérifie encore les tokens
+	JWTKeyGracePeriod time.Duration `mapstructure:"JWT_KEY_GRACE_PERIOD"`
+
+	// Claims standards (iss / aud) émis et exigés, tolérance de décalage d'horloge
+	JWTIssuer   string        `mapstructure:"JWT_ISSUER"`
+	JWTAudience string        `mapstructure:"JWT_AUDIENCE"`
+	JWTLeeway   time.Duration `mapstructure:"JWT_LEEWAY"`
+
+	// Durées de vie des tokens (la session de refresh suit le refresh token)
+	AccessTokenTTL  time.Duration `mapstructure:"ACCESS_TOKEN_TTL"`
+	RefreshTokenTTL time.Duration `mapstructure:"REFRESH_TOKEN_TTL"`
+
+	// Emails transactionnels : log (défaut), file (.eml dans MAILER_DIR) ou smtp
+	Mailer       string `mapstructure:"MAILER"`
+	MailerDir    string `mapstructure:"MAILER_DIR"`
+	SMTPHost     string `mapstructure:"SMTP_HOST"`
+	SMTPPort     int    `mapstructure:"SMTP_PORT"`
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"` // sensible
+	SMTPFrom     string `mapstructure:"SMTP_FROM"`
+
+	// URL publique du frontend, base des liens envoyés par email
+	PublicURL string `mapstructure:"APP_PUBLIC_URL"`
+
+	// Images produit : local (défaut, fichiers dans STORAGE_DIR) ou s3
+	// (AWS S3 ou compatible, MinIO par exemple)
+	Storage       string `mapstructure:"STORAGE"`
+	StorageDir    string `mapstructure:"STORAGE_DIR"`
+	S3Endpoint    string `mapstructure:"S3_ENDPOINT"`
+	S3Region      string `mapstructure:"S3_REGION"`
+	S3Bucket      string `mapstructure:"S3_BUCKET"`
+	S3AccessKey   string `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey   string `mapstructure:"S3_SECRET_KEY"` // sensible
+	ImageMaxBytes int64  `mapstructure:"IMAGE_MAX_BYTES"`
 }
 
 // LoadConfig charge la configuration depuis le bon fichier .env
@@ -54,6 +95,7 @@ func LoadConfig() *Config {
 	port := mustParseInt(getEnv("APP_PORT", "8080"))
 	dbPort := mustParseInt(getEnv("DB_PORT", "5432"))
 	redisPort := mustParseInt(getEnv("REDIS_PORT", "6379"))
+	refreshTTL := mustParseDuration(getEnv("REFRESH_TOKEN_TTL", "168h"))
 
 	// Récupère le mot de passe (obligatoire sauf en test)
 	dbPassword := os.Getenv("DB_PASSWORD")
@@ -75,6 +117,38 @@ func LoadConfig() *Config {
 		DBSSLMode:   getEnv("DB_SSLMODE", "disable"),
 		RedisHost:   getEnv("REDIS_HOST", "localhost"),
 		RedisPort:   redisPort,
+
+		CursorSecret: os.Getenv("CURSOR_SECRET"),
+
+		// Par défaut, une clé remplacée vérifie aussi longtemps que vit un refresh token
+		JWTKeys:           os.Getenv("JWT_KEYS"),
+		JWTKeyGracePeriod: mustParseDuration(getEnv("JWT_KEY_GRACE_PERIOD", refreshTTL.String())),
+
+		JWTIssuer:   getEnv("JWT_ISSUER", "goshop"),
+		JWTAudience: getEnv("JWT_AUDIENCE", "goshop-api"),
+		JWTLeeway:   mustParseDuration(getEnv("JWT_LEEWAY", "30s")),
+
+		AccessTokenTTL:  mustParseDuration(getEnv("ACCESS_TOKEN_TTL", "15m")),
+		RefreshTokenTTL: refreshTTL,
+
+		Mailer:       getEnv("MAILER", "log"),
+		MailerDir:    getEnv("MAILER_DIR", "tmp/mails"),
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     mustParseInt(getEnv("SMTP_PORT", "587")),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		SMTPFrom:     getEnv("SMTP_FROM", "GoShop <no-reply@goshop.dev>"),
+
+		PublicURL: getEnv("APP_PUBLIC_URL", "http://localhost:8080"),
+
+		Storage:       getEnv("STORAGE", "local"),
+		StorageDir:    getEnv("STORAGE_DIR", "tmp/blobs"),
+		S3Endpoint:    os.Getenv("S3_ENDPOINT"),
+		S3Region:      getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:      os.Getenv("S3_BUCKET"),
+		S3AccessKey:   os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey:   os.Getenv("S3_SECRET_KEY"),
+		ImageMaxBytes: int64(mustParseInt(getEnv("IMAGE_MAX_BYTES", "5242880"))),
 	}
 }
 
@@ -98,6 +172,21 @@ func (c *Config) SafeForLogging() map[string]interface{} {
 		"has_db_password": c.DBPassword != "",
 		"redis_host":      c.RedisHost,
 		"redis_port":      c.RedisPort,
+		"has_cursor_key":  c.CursorSecret != "",
+		"has_jwt_keys":    c.JWTKeys != "",
+		"jwt_issuer":      c.JWTIssuer,
+		"jwt_audience":    c.JWTAudience,
+		"access_ttl":      c.AccessTokenTTL.String(),
+		"refresh_ttl":     c.RefreshTokenTTL.String(),
+		"mailer":          c.Mailer,
+		"smtp_host":       c.SMTPHost,
+		"has_smtp_secret": c.SMTPPassword != "",
+		"public_url":      c.PublicURL,
+		"storage":         c.Storage,
+		"s3_endpoint":     c.S3Endpoint,
+		"s3_bucket":       c.S3Bucket,
+		"has_s3_secret":   c.S3SecretKey != "",
+		"image_max_bytes": c.ImageMaxBytes,
 	}
 }
 
@@ -117,3 +206,12 @@ func mustParseInt(s string) int {
 	}
 	return i
 }
+
+// mustParseDuration convertit une durée ("15m", "168h") ou arrête l'application.
+func mustParseDuration(s string) time.Duration {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		log.Fatalf("❌ Durée invalide: %s (ex: 15m, 168h)", s)
+	}
+	return d
+}
@@ -0,0 +1,28 @@
+package authentity
+
+import "time"
+
+// APIKey : clé d'API d'un client machine, gérée par les administrateurs.
+// Les requêtes authentifiées par une clé n'ont ni rôle ni profil client :
+// leurs droits sont les permissions listées dans Scopes.
+type APIKey struct {
+	ID         string
+	Name       string
+	Prefix     string // partie publique de la clé, sert à la retrouver
+	SecretHash string // SHA-256 du secret
+	Scopes     []string
+	RateLimit  int       // requêtes par minute
+	ExpiresAt  time.Time // zéro : pas d'expiration
+	LastUsedAt time.Time // zéro : jamais utilisée
+	RevokedAt  time.Time // zéro : active
+	CreatedBy  string
+	CreatedAt  time.Time
+}
+
+// Active : ni révoquée ni expirée à `now`
+func (k *APIKey) Active(now time.Time) bool {
+	if !k.RevokedAt.IsZero() {
+		return false
+	}
+	return k.ExpiresAt.IsZero() || now.Before(k.ExpiresAt)
+}
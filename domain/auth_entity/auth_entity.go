@@ -15,5 +15,15 @@ type RefreshSession struct {
 	UserID    string    // ID de l'utilisateur propriétaire du token
 	ExpiresAt time.Time // Date d’expiration
 	Revoked   bool      // Si le token est invalidé
-	CreatedAt time.Time // Date de création
+	CreatedAt time.Time // Date du login (conservée à chaque rotation)
+
+	// Rotation : toutes les sessions issues d'un même login forment une famille
+	FamilyID   string // JTI de la session racine (login)
+	ParentID   string // JTI de la session remplacée ("" pour la racine)
+	ReplacedBy string // JTI de la session suivante, renseigné à la rotation
+
+	// Appareil : capturé au login puis mis à jour à chaque refresh
+	LastUsedAt time.Time // Date du dernier login / refresh
+	UserAgent  string    // User-Agent HTTP du client
+	IPAddress  string    // IP de la connexion
 }
@@ -0,0 +1,16 @@
+package authentity
+
+import "time"
+
+// LoginAttempts : échecs de connexion récents d'une clé de suivi (un compte
+// ou une adresse IP). Le compteur expire après une période sans nouvel échec.
+type LoginAttempts struct {
+	Failures    int       // échecs consécutifs depuis le dernier succès
+	LastFailure time.Time // date du dernier échec
+	LockedUntil time.Time // verrouillage temporaire (zéro : pas de verrouillage)
+}
+
+// LockedAt indique si la clé est verrouillée à l'instant `now`
+func (a LoginAttempts) LockedAt(now time.Time) bool {
+	return now.Before(a.LockedUntil)
+}
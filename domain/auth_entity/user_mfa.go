@@ -0,0 +1,17 @@
+package authentity
+
+import "time"
+
+// UserMFA : second facteur TOTP (RFC 6238) d'un utilisateur. Le secret est
+// créé à l'enrôlement ; le 2FA n'est actif qu'après confirmation d'un premier code.
+type UserMFA struct {
+	UserID       string
+	Secret       string    // base32 sans padding, tel qu'affiché dans l'URI otpauth://
+	EnabledAt    time.Time // zéro tant que l'enrôlement n'est pas confirmé
+	LastUsedStep int64     // dernier pas de temps accepté (anti-rejeu)
+	CreatedAt    time.Time
+}
+
+func (m *UserMFA) Enabled() bool {
+	return !m.EnabledAt.IsZero()
+}
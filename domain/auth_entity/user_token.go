@@ -0,0 +1,20 @@
+package authentity
+
+import "time"
+
+// Usages d'un UserToken (colonne purpose)
+const (
+	UserTokenPasswordReset     = "password_reset"
+	UserTokenEmailVerification = "email_verification"
+)
+
+// UserToken : jeton à usage unique envoyé par email. Le jeton en clair n'est
+// connu que du destinataire ; seul son hash SHA-256 est stocké.
+type UserToken struct {
+	TokenHash string
+	UserID    string
+	Purpose   string    // UserTokenPasswordReset ou UserTokenEmailVerification
+	ExpiresAt time.Time
+	UsedAt    time.Time // zéro tant que le jeton n'a pas servi
+	CreatedAt time.Time
+}
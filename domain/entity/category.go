@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// CategoryPathSeparator sépare les slugs dans Category.Path
+const CategoryPathSeparator = "/"
+
+var categorySlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Category : nœud de l'arbre du catalogue
+type Category struct {
+	ID       string
+	Name     string
+	Slug     string
+	ParentID string // vide pour une catégorie racine
+	// Path : slugs des ancêtres puis de la catégorie ("electronique/portables")
+	Path      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ProductCategory : catégorie d'un produit avec son fil d'Ariane
+type ProductCategory struct {
+	ProductID  string
+	Category   *Category
+	Breadcrumb []*Category // de la racine à la catégorie incluse
+}
+
+// IsValidCategorySlug : minuscules, chiffres et tirets simples, 100 caractères au plus
+func IsValidCategorySlug(slug string) bool {
+	return len(slug) <= 100 && categorySlugPattern.MatchString(slug)
+}
+
+// CategoryPath calcule le path d'une catégorie placée sous parent (nil : racine)
+func CategoryPath(parent *Category, slug string) string {
+	if parent == nil {
+		return slug
+	}
+	return parent.Path + CategoryPathSeparator + slug
+}
+
+// Contains indique si other est la catégorie elle-même ou l'un de ses descendants
+func (c *Category) Contains(other *Category) bool {
+	return other.Path == c.Path || strings.HasPrefix(other.Path, c.Path+CategoryPathSeparator)
+}
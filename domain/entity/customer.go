@@ -9,4 +9,6 @@ type Customer struct {
 	Email     string    `json:"email"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// DeletedAt : client archivé (DELETE) ; nil pour un client actif
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
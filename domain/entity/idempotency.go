@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+// États d'une clé d'idempotence
+const (
+	IdempotencyStatusInProgress = "IN_PROGRESS"
+	IdempotencyStatusCompleted  = "COMPLETED"
+)
+
+// IdempotencyRecord mémorise la première exécution d'une requête portant un
+// header Idempotency-Key, pour rejouer la même réponse aux tentatives suivantes.
+type IdempotencyRecord struct {
+	Key         string
+	Scope       string // utilisateur propriétaire de la clé
+	Method      string
+	Path        string
+	Fingerprint string // sha256 de la méthode, du chemin et du corps
+	Status      string
+
+	ResponseStatus      int
+	ResponseContentType string
+	ResponseHeaders     map[string][]string // headers rejoués avec la réponse, hors Content-Type
+	ResponseBody        []byte
+
+	LockedAt  time.Time
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+func (r *IdempotencyRecord) IsCompleted() bool {
+	return r.Status == IdempotencyStatusCompleted
+}
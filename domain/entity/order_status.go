@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Statuts possibles d'une commande
+const (
+	OrderStatusPending   = "PENDING"
+	OrderStatusPaid      = "PAID"
+	OrderStatusFulfilled = "FULFILLED"
+	OrderStatusShipped   = "SHIPPED"
+	OrderStatusDelivered = "DELIVERED"
+	OrderStatusCancelled = "CANCELLED"
+	OrderStatusRefunded  = "REFUNDED"
+)
+
+var (
+	ErrUnknownOrderStatus     = errors.New("unknown order status")
+	ErrInvalidOrderTransition = errors.New("invalid order status transition")
+)
+
+// orderTransitions décrit la machine à états : pour chaque statut,
+// les statuts atteignables. CANCELLED et REFUNDED sont terminaux.
+var orderTransitions = map[string][]string{
+	OrderStatusPending:   {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:      {OrderStatusFulfilled, OrderStatusCancelled, OrderStatusRefunded},
+	OrderStatusFulfilled: {OrderStatusShipped, OrderStatusCancelled},
+	OrderStatusShipped:   {OrderStatusDelivered},
+	OrderStatusDelivered: {OrderStatusRefunded},
+	OrderStatusCancelled: {},
+	OrderStatusRefunded:  {},
+}
+
+// NormalizeOrderStatus met le statut au format canonique (majuscules)
+func NormalizeOrderStatus(status string) string {
+	return strings.ToUpper(strings.TrimSpace(status))
+}
+
+// IsValidOrderStatus indique si le statut fait partie de la machine à états
+func IsValidOrderStatus(status string) bool {
+	_, ok := orderTransitions[NormalizeOrderStatus(status)]
+	return ok
+}
+
+// AllowedOrderTransitions retourne les statuts atteignables depuis `from`
+func AllowedOrderTransitions(from string) []string {
+	next := orderTransitions[NormalizeOrderStatus(from)]
+	out := make([]string, len(next))
+	copy(out, next)
+	return out
+}
+
+// ValidateOrderTransition vérifie qu'un passage from -> to est autorisé
+func ValidateOrderTransition(from, to string) error {
+	from = NormalizeOrderStatus(from)
+	to = NormalizeOrderStatus(to)
+
+	if !IsValidOrderStatus(from) {
+		return fmt.Errorf("%w: %q", ErrUnknownOrderStatus, from)
+	}
+	if !IsValidOrderStatus(to) {
+		return fmt.Errorf("%w: %q", ErrUnknownOrderStatus, to)
+	}
+
+	for _, allowed := range orderTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s -> %s", ErrInvalidOrderTransition, from, to)
+}
+
+// CanTransitionTo indique si la commande peut passer au statut donné
+func (o *Order) CanTransitionTo(status string) bool {
+	return ValidateOrderTransition(o.Status, status) == nil
+}
+
+// OrderStatusHistory trace chaque changement de statut d'une commande
+type OrderStatusHistory struct {
+	ID         string    `json:"id"`
+	OrderID    string    `json:"order_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	ChangedBy  string    `json:"changed_by"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
+}
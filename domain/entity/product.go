@@ -10,4 +10,5 @@ type Product struct {
 	Stock       int
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	DeletedAt   *time.Time // archivé (DELETE) ; nil pour un produit actif
 }
@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+const (
+	// DefaultMaxImageBytes : taille maximale d'une image téléversée (5 Mo)
+	DefaultMaxImageBytes = 5 << 20
+	// MaxProductImages : nombre maximal d'images par produit
+	MaxProductImages = 20
+)
+
+// Types d'image acceptés, détectés sur le contenu du fichier (jamais sur le
+// nom ou le Content-Type annoncé par le client), et leur extension
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// ProductImage : image d'un produit, son contenu est dans le BlobStore.
+// La première image (Position 0) est l'image principale.
+type ProductImage struct {
+	ID          string
+	ProductID   string
+	BlobKey     string
+	ContentType string
+	SizeBytes   int64
+	Position    int
+	CreatedAt   time.Time
+}
+
+// ImageExtension renvoie l'extension d'un type d'image accepté
+func ImageExtension(contentType string) (string, bool) {
+	ext, ok := imageExtensions[contentType]
+	return ext, ok
+}
+
+// ProductImageKey : clé du contenu d'une image dans le BlobStore
+func ProductImageKey(productID, imageID, ext string) string {
+	return "products/" + productID + "/" + imageID + ext
+}
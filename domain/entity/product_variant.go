@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"regexp"
+	"time"
+)
+
+// MaxVariantOptions : nombre maximal d'options (taille, couleur...) par variante
+const MaxVariantOptions = 5
+
+var (
+	skuPattern          = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+	variantOptionKeyPat = regexp.MustCompile(`^[a-z][a-z0-9_]{0,29}$`)
+)
+
+// ProductVariant : déclinaison vendable d'un produit, avec son propre SKU et
+// son propre stock. Le stock du produit est la somme de celui de ses variantes.
+type ProductVariant struct {
+	ID        string
+	ProductID string
+	SKU       string
+	// Options : valeurs d'options, {"size": "M", "colour": "bleu"}
+	Options map[string]string
+	// PriceCents : prix propre à la variante, nil pour le prix du produit
+	PriceCents *int64
+	Stock      int
+	// IsDefault : variante des commandes passées sans variant_id
+	IsDefault bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// EffectivePrice renvoie le prix de vente de la variante
+func (v *ProductVariant) EffectivePrice(productPriceCents int64) int64 {
+	if v.PriceCents != nil {
+		return *v.PriceCents
+	}
+	return productPriceCents
+}
+
+// DefaultVariantSKU : SKU attribué à la variante par défaut quand aucun n'est
+// fourni (même règle que la migration des produits existants)
+func DefaultVariantSKU(productID string) string {
+	return "SKU-" + productID
+}
+
+// IsValidSKU : lettres, chiffres, '.', '_' et '-', 64 caractères au plus
+func IsValidSKU(sku string) bool {
+	return skuPattern.MatchString(sku)
+}
+
+// IsValidVariantOptions : au plus MaxVariantOptions options, noms en
+// minuscules ("size", "colour"), valeurs non vides de 50 caractères au plus
+func IsValidVariantOptions(options map[string]string) bool {
+	if len(options) > MaxVariantOptions {
+		return false
+	}
+	for key, value := range options {
+		if !variantOptionKeyPat.MatchString(key) || value == "" || len(value) > 50 {
+			return false
+		}
+	}
+	return true
+}
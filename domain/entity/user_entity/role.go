@@ -0,0 +1,74 @@
+package userentity
+
+// Rôles connus (table roles)
+const (
+	RoleAdmin    = "admin"
+	RoleManager  = "manager"
+	RoleCustomer = "customer"
+)
+
+// Permissions fines (table permissions). Le suffixe ":any" autorise
+// l'accès aux ressources des autres utilisateurs.
+const (
+	PermProductsWrite    = "products:write"
+	PermCustomersReadAny = "customers:read:any"
+	PermCustomersWrite   = "customers:write"
+	PermOrdersReadAny    = "orders:read:any"
+	PermOrdersWrite      = "orders:write"
+	PermOrdersWriteAny   = "orders:write:any"
+	PermUsersRolesWrite  = "users:roles:write"
+)
+
+// AllPermissions : permissions connues (table permissions), attribuables aux
+// rôles comme aux clés d'API
+var AllPermissions = []string{
+	PermProductsWrite,
+	PermCustomersReadAny,
+	PermCustomersWrite,
+	PermOrdersReadAny,
+	PermOrdersWrite,
+	PermOrdersWriteAny,
+	PermUsersRolesWrite,
+}
+
+// IsKnownPermission indique si la permission existe
+func IsKnownPermission(permission string) bool {
+	for _, p := range AllPermissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultRole est attribué à chaque nouvel utilisateur
+const DefaultRole = RoleCustomer
+
+// rolePriority ordonne les rôles du plus au moins privilégié
+var rolePriority = []string{RoleAdmin, RoleManager, RoleCustomer}
+
+// PrimaryRole retourne le rôle le plus privilégié parmi `roles`
+// (rôle unique exposé dans /auth/me et injecté via WithUserRole).
+func PrimaryRole(roles []string) string {
+	for _, candidate := range rolePriority {
+		for _, role := range roles {
+			if role == candidate {
+				return role
+			}
+		}
+	}
+	if len(roles) > 0 {
+		return roles[0]
+	}
+	return ""
+}
+
+// HasRole indique si l'utilisateur possède le rôle
+func (u *UserEntity) HasRole(role string) bool {
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
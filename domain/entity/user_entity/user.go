@@ -3,6 +3,7 @@ package userentity
 import (
 	"os"
 	"strconv"
+	"strings"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -11,6 +12,20 @@ type UserEntity struct {
 	ID       string
 	Email    string
 	Password string
+
+	// Profil client créé à l'inscription (customers.user_id)
+	FirstName string
+	LastName  string
+
+	// Chargés avec l'utilisateur : rôles attribués et permissions qui en découlent
+	Roles       []string
+	Permissions []string
+}
+
+// NormalizeEmail : forme sous laquelle l'email est enregistré et recherché
+// (inscription, connexion, réinitialisation du mot de passe)
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
 
 func getBcryptCost() int {
@@ -0,0 +1,17 @@
+// Package mailer définit l'envoi d'emails transactionnels (réinitialisation
+// du mot de passe, vérification de l'email). Implémentations dans
+// infrastructure/mailer.
+package mailer
+
+import "context"
+
+// Message : email texte brut à un destinataire
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,31 @@
+package authrepository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	authentity "Goshop/domain/auth_entity"
+)
+
+//go:generate mockgen -destination=../../../mocks/repository/mock_api_key_repository.go -package=repository -source=api_key_repository.go APIKeyRepository RateCounter
+
+// ErrAPIKeyNotFound : clé inconnue (ou déjà révoquée pour Revoke)
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *authentity.APIKey) error
+	FindByPrefix(ctx context.Context, prefix string) (*authentity.APIKey, error)
+	// List retourne toutes les clés, révoquées comprises, les plus récentes d'abord
+	List(ctx context.Context) ([]*authentity.APIKey, error)
+	Revoke(ctx context.Context, id string) error
+	// TouchLastUsed enregistre la dernière utilisation, au plus une écriture par minute et par clé
+	TouchLastUsed(ctx context.Context, id string, at time.Time) error
+}
+
+// RateCounter : compteurs de requêtes par fenêtre fixe
+type RateCounter interface {
+	// Incr compte une requête pour `key` et retourne le nombre de requêtes de
+	// la fenêtre courante (fenêtres de durée `window` alignées sur l'horloge)
+	Incr(ctx context.Context, key string, window time.Duration) (int64, error)
+}
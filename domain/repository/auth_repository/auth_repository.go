@@ -1,9 +1,33 @@
 package authrepository
 
-import authentity "Goshop/domain/auth_entity"
+import (
+	"errors"
+
+	authentity "Goshop/domain/auth_entity"
+)
+
+//go:generate mockgen -destination=../../../mocks/repository/mock_refresh_session_repository.go -package=repository -source=auth_repository.go RefreshSessionRepository
+
+// ErrSessionAlreadyRotated : la session à remplacer n'est plus active
+// (déjà révoquée ou tournée par une requête concurrente)
+var ErrSessionAlreadyRotated = errors.New("refresh session already revoked or rotated")
 
 type RefreshSessionRepository interface {
 	Create(session *authentity.RefreshSession) error
 	FindByID(id string) (*authentity.RefreshSession, error)
 	Revoke(id string) error
+	// Rotate révoque la session oldID et crée next dans une même transaction.
+	// Retourne ErrSessionAlreadyRotated si oldID n'est plus active.
+	Rotate(oldID string, next *authentity.RefreshSession) error
+	// RevokeFamily révoque toutes les sessions actives d'une famille
+	// et retourne le nombre de sessions révoquées
+	RevokeFamily(familyID string) (int, error)
+	// RevokeAllForUser révoque toutes les sessions actives de l'utilisateur
+	// et retourne le nombre de sessions révoquées
+	RevokeAllForUser(userID string) (int, error)
+	// RevokeOtherSessions révoque les sessions actives de l'utilisateur sauf
+	// celles de la famille keepFamilyID (appareil courant)
+	RevokeOtherSessions(userID, keepFamilyID string) (int, error)
+	// ListActiveForUser : sessions ni révoquées ni expirées, plus récentes d'abord
+	ListActiveForUser(userID string) ([]*authentity.RefreshSession, error)
 }
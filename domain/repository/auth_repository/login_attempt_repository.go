@@ -0,0 +1,24 @@
+package authrepository
+
+import (
+	"context"
+	"time"
+
+	authentity "Goshop/domain/auth_entity"
+)
+
+//go:generate mockgen -destination=../../../mocks/repository/mock_login_attempt_repository.go -package=repository -source=login_attempt_repository.go LoginAttemptRepository
+
+// LoginAttemptRepository : compteurs d'échecs de connexion par clé
+// ("account:<email>" ou "ip:<adresse>")
+type LoginAttemptRepository interface {
+	// Get retourne l'état de la clé (valeur zéro si aucun échec récent)
+	Get(ctx context.Context, key string) (authentity.LoginAttempts, error)
+	// RegisterFailure ajoute un échec et retourne l'état mis à jour. Le
+	// compteur expire `window` après le dernier échec (ou à la fin du verrouillage).
+	RegisterFailure(ctx context.Context, key string, window time.Duration) (authentity.LoginAttempts, error)
+	// Lock verrouille la clé jusqu'à `until`
+	Lock(ctx context.Context, key string, until time.Time) error
+	// Reset efface les échecs et le verrouillage de la clé
+	Reset(ctx context.Context, key string) error
+}
@@ -0,0 +1,35 @@
+package authrepository
+
+import (
+	"context"
+	"errors"
+
+	authentity "Goshop/domain/auth_entity"
+)
+
+//go:generate mockgen -destination=../../../mocks/repository/mock_mfa_repository.go -package=repository -source=mfa_repository.go MFARepository
+
+var (
+	// ErrMFANotFound : aucun enrôlement (ou aucun enrôlement en attente pour Enable)
+	ErrMFANotFound = errors.New("mfa not enrolled")
+	// ErrMFAAlreadyEnabled : le 2FA est déjà actif, le secret ne peut être remplacé
+	ErrMFAAlreadyEnabled = errors.New("mfa already enabled")
+	// ErrMFAStepReused : un code de ce pas de temps (ou d'un pas plus récent) a déjà servi
+	ErrMFAStepReused = errors.New("totp code already used")
+	// ErrRecoveryCodeInvalid : code de récupération inconnu ou déjà utilisé
+	ErrRecoveryCodeInvalid = errors.New("recovery code unknown or already used")
+)
+
+type MFARepository interface {
+	Find(ctx context.Context, userID string) (*authentity.UserMFA, error)
+	// SavePending enregistre (ou remplace) un secret en attente de confirmation ;
+	// ErrMFAAlreadyEnabled si le 2FA est actif
+	SavePending(ctx context.Context, userID, secret string) error
+	// Enable active le 2FA en attente et remplace les codes de récupération ;
+	// step est le pas de temps du code de confirmation
+	Enable(ctx context.Context, userID string, step int64, recoveryCodeHashes []string) error
+	// UseStep enregistre le pas de temps d'un code accepté, de façon atomique
+	UseStep(ctx context.Context, userID string, step int64) error
+	// ConsumeRecoveryCode marque le code utilisé, de façon atomique
+	ConsumeRecoveryCode(ctx context.Context, userID, codeHash string) error
+}
@@ -0,0 +1,22 @@
+package authrepository
+
+import (
+	"context"
+	"errors"
+
+	authentity "Goshop/domain/auth_entity"
+)
+
+//go:generate mockgen -destination=../../../mocks/repository/mock_user_token_repository.go -package=repository -source=user_token_repository.go UserTokenRepository
+
+// ErrUserTokenInvalid : jeton inconnu, expiré ou déjà utilisé
+var ErrUserTokenInvalid = errors.New("user token unknown, expired or already used")
+
+type UserTokenRepository interface {
+	Create(ctx context.Context, token *authentity.UserToken) error
+	// Consume marque le jeton utilisé et le retourne, de façon atomique : de
+	// deux requêtes simultanées, une seule l'obtient. ErrUserTokenInvalid sinon.
+	Consume(ctx context.Context, tokenHash, purpose string) (*authentity.UserToken, error)
+	// RevokeForUser invalide les jetons encore utilisables de l'utilisateur pour cet usage
+	RevokeForUser(ctx context.Context, userID, purpose string) error
+}
@@ -0,0 +1,55 @@
+// domain/repository/category_repository.go
+package repository
+
+import (
+	"Goshop/domain/entity"
+	"context"
+	"errors"
+)
+
+var (
+	ErrCategoryNotFound    = errors.New("category not found")
+	ErrCategorySlugTaken   = errors.New("category slug already used")
+	ErrCategoryHasChildren = errors.New("category has subcategories")
+)
+
+//go:generate mockgen -destination=../../mocks/repository/mock_category_repository.go -package=repository . CategoryRepository
+
+type CategoryRepository interface {
+	// Create renvoie ErrCategorySlugTaken si le slug existe déjà
+	Create(ctx context.Context, category *entity.Category) error
+	FindByID(ctx context.Context, id string) (*entity.Category, error)
+	FindBySlug(ctx context.Context, slug string) (*entity.Category, error)
+
+	// FindByIDs ignore les IDs inconnus : l'appelant compare les longueurs
+	FindByIDs(ctx context.Context, ids []string) ([]*entity.Category, error)
+
+	// FindAll renvoie tout l'arbre, trié par path (parents avant enfants)
+	FindAll(ctx context.Context) ([]*entity.Category, error)
+	FindChildren(ctx context.Context, parentID string) ([]*entity.Category, error)
+
+	// FindBreadcrumb renvoie les ancêtres de la catégorie de path donné,
+	// de la racine à la catégorie incluse
+	FindBreadcrumb(ctx context.Context, path string) ([]*entity.Category, error)
+
+	// Update enregistre nom, slug, parent et path, et réécrit le path des
+	// descendants quand il a changé (oldPath : path avant modification)
+	Update(ctx context.Context, category *entity.Category, oldPath string) error
+
+	// LockTree verrouille l'arbre jusqu'à la fin de la transaction : les
+	// déplacements concurrents sont sérialisés (à appeler via WithTX)
+	LockTree(ctx context.Context) error
+
+	// Delete renvoie ErrCategoryHasChildren si la catégorie a des enfants ;
+	// les liens avec les produits sont supprimés
+	Delete(ctx context.Context, id string) error
+
+	// SetProductCategories remplace les catégories d'un produit
+	SetProductCategories(ctx context.Context, productID string, categoryIDs []string) error
+
+	// FindProductCategories charge en une requête les catégories des produits
+	// donnés, avec leur fil d'Ariane
+	FindProductCategories(ctx context.Context, productIDs []string) ([]*entity.ProductCategory, error)
+
+	WithTX(tx Tx) CategoryRepository
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	dto "Goshop/application/dto/customer_dto"
+	paginationdto "Goshop/application/dto/pagination_dto"
 	"Goshop/domain/entity"
 
 	"context"
@@ -9,19 +10,33 @@ import (
 
 //go:generate mockgen -destination=../../mocks/repository/mock_customer_repository.go -package=repository . CustomerRepositoryInterface
 
+// CustomerRepositoryInterface : sauf mention contraire, les lectures ignorent
+// les clients archivés (deleted_at renseigné).
 type CustomerRepositoryInterface interface {
 	Create(ctx context.Context, customer *entity.Customer) (*entity.Customer, error)
 	FindByCustomerID(ctx context.Context, id string) (*entity.Customer, error)
+	// FindByCustomerIDIncludingDeleted renvoie aussi un client archivé
+	FindByCustomerIDIncludingDeleted(ctx context.Context, id string) (*entity.Customer, error)
+	// FindByEmail inclut les clients archivés : l'email reste réservé
 	FindByEmail(ctx context.Context, email string) (*entity.Customer, error)
+	// FindByUserID : profil client du compte utilisateur (sql.ErrNoRows si aucun)
+	FindByUserID(ctx context.Context, userID string) (*entity.Customer, error)
 	FindAllCustomers(ctx context.Context) ([]*entity.Customer, error)
 	UpdateCustomer(ctx context.Context, customer *entity.Customer) (*entity.Customer, error)
+	// DeleteCustomer archive le client ; sql.ErrNoRows si aucun client actif
 	DeleteCustomer(ctx context.Context, id string) error
+	// RestoreCustomer annule l'archivage ; sql.ErrNoRows si le client n'est pas archivé
+	RestoreCustomer(ctx context.Context, id string) (*entity.Customer, error)
 
 	// ✅ Nouvelles méthodes pour pagination, tri et comptage
 	FindAllCustomersWithPagination(ctx context.Context, limit, offset int, filter dto.CustomerFilter) ([]*entity.Customer, error)
 	CountAllCustomers(ctx context.Context, filter dto.CustomerFilter) (int, error)
 	FindAllCustomersWithSorting(ctx context.Context, sortBy, order string) ([]*entity.Customer, error)
 
+	// FindAllCustomersByCursor renvoie au plus page.Limit clients après le
+	// curseur, triés par (created_at DESC, id DESC).
+	FindAllCustomersByCursor(ctx context.Context, page paginationdto.CursorPage, filter dto.CustomerFilter) ([]*entity.Customer, error)
+
 	// permet d'utiliser txmanager
 
 	WithTX(tx Tx) CustomerRepositoryInterface
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"Goshop/domain/entity"
+	"context"
+	"time"
+)
+
+//go:generate mockgen -destination=../../mocks/repository/mock_idempotency_repository.go -package=repository . IdempotencyRepository
+
+type IdempotencyRepository interface {
+	// Reserve enregistre la clé à l'état IN_PROGRESS. Si la clé existe déjà
+	// (et n'est ni expirée ni abandonnée depuis plus de lockTimeout), l'entrée
+	// existante est renvoyée avec created = false.
+	Reserve(ctx context.Context, record *entity.IdempotencyRecord, lockTimeout time.Duration) (existing *entity.IdempotencyRecord, created bool, err error)
+
+	// Heartbeat repousse locked_at d'une clé IN_PROGRESS : tant que son
+	// détenteur s'exécute, la clé n'est pas considérée abandonnée
+	Heartbeat(ctx context.Context, scope, key string) error
+
+	// Complete stocke la réponse et passe la clé à l'état COMPLETED
+	Complete(ctx context.Context, record *entity.IdempotencyRecord) error
+
+	// Release supprime une clé IN_PROGRESS pour que le client puisse réessayer
+	Release(ctx context.Context, scope, key string) error
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	orderdto "Goshop/application/dto/order_dto"
+	paginationdto "Goshop/application/dto/pagination_dto"
 	"Goshop/domain/entity"
 	"context"
 )
@@ -14,7 +15,17 @@ type OrderRepository interface {
 	FindAll(ctx context.Context) ([]*entity.Order, error)
 	FindAllWithPagination(ctx context.Context, limit, offset int, filter orderdto.OrderFilter) ([]*entity.Order, error)
 	CountAll(ctx context.Context, filter orderdto.OrderFilter) (int, error)
+
+	// FindAllByCursor renvoie au plus page.Limit commandes (avec leurs lignes)
+	// après le curseur, triées par (created_at DESC, id DESC).
+	FindAllByCursor(ctx context.Context, page paginationdto.CursorPage, filter orderdto.OrderFilter) ([]*entity.Order, error)
 	CountByCustomerID(ctx context.Context, customerID string) (int, error)
 
+	// UpdateStatus applique la transition uniquement si la commande est encore
+	// au statut `from` (retourne sql.ErrNoRows sinon).
+	UpdateStatus(ctx context.Context, id, from, to string) (*entity.Order, error)
+	AddStatusHistory(ctx context.Context, history *entity.OrderStatusHistory) error
+	FindStatusHistory(ctx context.Context, orderID string) ([]*entity.OrderStatusHistory, error)
+
 	WithTX(tx Tx) OrderRepository
 }
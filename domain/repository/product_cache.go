@@ -4,14 +4,19 @@ package repository
 import (
 	"Goshop/domain/entity"
 	"context"
+	"errors"
 )
 
 //go:generate mockgen -destination=../../mocks/repository/mock_product_cache.go -package=repository . ProductCache
 
+// ErrCacheMiss : produit absent du cache (jamais mis en cache ou expiré)
+var ErrCacheMiss = errors.New("cache miss")
+
 // ProductCache définit les opérations de cache pour les produits
 type ProductCache interface {
 	// Get récupère un produit du cache par ID
-	// Retourne le produit ou une erreur (ex: cache miss, timeout, etc.)
+	// Retourne le produit, ErrCacheMiss s'il est absent, ou une autre erreur
+	// (timeout, cache indisponible, etc.)
 	Get(ctx context.Context, id string) (*entity.Product, error)
 
 	// Set stocke un produit dans le cache avec une durée d'expiration
@@ -0,0 +1,46 @@
+// domain/repository/product_image_repository.go
+package repository
+
+import (
+	"Goshop/domain/entity"
+	"context"
+	"errors"
+)
+
+var ErrImageNotFound = errors.New("product image not found")
+
+//go:generate mockgen -destination=../../mocks/repository/mock_product_image_repository.go -package=repository . ProductImageRepository
+
+// ProductImageRepository : métadonnées des images produit, ordonnées par
+// position (0 = image principale). Le contenu est dans le BlobStore ; images
+// et contenu sont conservés quand le produit est archivé, jusqu'à sa purge.
+type ProductImageRepository interface {
+	// LockProduct verrouille la ligne products avant de modifier ses images ;
+	// sql.ErrNoRows si le produit n'existe pas ou est archivé
+	LockProduct(ctx context.Context, productID string) error
+
+	// Create ajoute l'image (ID fourni par l'appelant) après la dernière
+	Create(ctx context.Context, image *entity.ProductImage) error
+	FindByID(ctx context.Context, id string) (*entity.ProductImage, error)
+
+	// FindByProduct renvoie les images du produit par position
+	FindByProduct(ctx context.Context, productID string) ([]*entity.ProductImage, error)
+
+	// FindPrimary charge en une requête l'image principale de chaque produit
+	// (les produits sans image sont absents du résultat)
+	FindPrimary(ctx context.Context, productIDs []string) ([]*entity.ProductImage, error)
+
+	// Delete supprime l'image et referme l'ordre des suivantes ;
+	// ErrImageNotFound si elle n'appartient pas au produit
+	Delete(ctx context.Context, productID, id string) error
+
+	// DeleteByProduct supprime toutes les images du produit et renvoie les
+	// clés de leur contenu, à retirer du BlobStore après le commit
+	DeleteByProduct(ctx context.Context, productID string) ([]string, error)
+
+	// Reorder donne aux images la position de leur ID dans imageIDs, qui
+	// doit contenir toutes les images du produit
+	Reorder(ctx context.Context, productID string, imageIDs []string) error
+
+	WithTX(tx Tx) ProductImageRepository
+}
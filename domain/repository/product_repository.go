@@ -1,18 +1,54 @@
 package repository
 
 import (
+	paginationdto "Goshop/application/dto/pagination_dto"
+	productdto "Goshop/application/dto/product_dto"
 	"Goshop/domain/entity"
 	"context"
+	"errors"
 )
 
+// ErrInsufficientStock est renvoyée par ReserveStock quand le stock disponible
+// ne couvre pas la quantité demandée.
+var ErrInsufficientStock = errors.New("insufficient stock")
+
 //go:generate mockgen -destination=../../mocks/repository/mock_product_repository.go -package=repository . ProductRepository
 
+// Les lectures ignorent les produits archivés (deleted_at renseigné), sauf
+// FindByIDIncludingDeleted et les listes avec filter.IncludeDeleted.
 type ProductRepository interface {
 	Create(ctx context.Context, product *entity.Product) error
 	FindByID(ctx context.Context, id string) (*entity.Product, error)
-	FindAll(ctx context.Context, limit, offset int) ([]*entity.Product, error)
+	// FindByIDIncludingDeleted : produit actif ou archivé
+	FindByIDIncludingDeleted(ctx context.Context, id string) (*entity.Product, error)
+	FindAll(ctx context.Context, limit, offset int, filter productdto.ProductFilter) ([]*entity.Product, error)
+	CountAll(ctx context.Context, filter productdto.ProductFilter) (int, error)
+
+	// FindAllByCursor renvoie au plus page.Limit produits après le curseur,
+	// triés par (created_at DESC, id DESC).
+	FindAllByCursor(ctx context.Context, page paginationdto.CursorPage, filter productdto.ProductFilter) ([]*entity.Product, error)
 	Update(ctx context.Context, product *entity.Product) (*entity.Product, error)
+
+	// Delete archive le produit (deleted_at = NOW()) : ses commandes, variantes
+	// et images restent en place. sql.ErrNoRows si aucun produit actif.
 	Delete(ctx context.Context, id string) error
 
+	// Restore remet en ligne un produit archivé (sql.ErrNoRows sinon)
+	Restore(ctx context.Context, id string) (*entity.Product, error)
+
+	// Purge supprime définitivement un produit archivé jamais commandé
+	// (variantes, catégories et images partent en cascade) et renvoie true.
+	// Un produit référencé par des commandes reste archivé : false.
+	Purge(ctx context.Context, id string) (bool, error)
+
+	// ReserveStock décrémente le stock de `quantity` unités seulement s'il est
+	// suffisant, en une seule requête (verrou de ligne posé par l'UPDATE).
+	// Renvoie sql.ErrNoRows si le produit n'existe pas et ErrInsufficientStock
+	// si le stock est trop bas.
+	ReserveStock(ctx context.Context, id string, quantity int) (*entity.Product, error)
+
+	// RestoreStock remet `quantity` unités en stock (annulation de commande)
+	RestoreStock(ctx context.Context, id string, quantity int) error
+
 	WithTX(tx Tx) ProductRepository
 }
@@ -0,0 +1,61 @@
+// domain/repository/product_variant_repository.go
+package repository
+
+import (
+	"Goshop/domain/entity"
+	"context"
+	"errors"
+)
+
+var (
+	ErrVariantNotFound     = errors.New("product variant not found")
+	ErrVariantSKUTaken     = errors.New("product variant sku already used")
+	ErrVariantOptionsTaken = errors.New("product variant options already used")
+	ErrVariantInUse        = errors.New("product variant referenced by orders")
+)
+
+//go:generate mockgen -destination=../../mocks/repository/mock_product_variant_repository.go -package=repository . ProductVariantRepository
+
+// ProductVariantRepository : variantes des produits et leur stock.
+//
+// Ordre des verrous : la ligne products est toujours verrouillée avant celles
+// de ses variantes (ProductRepository.ReserveStock/Update ou LockProduct).
+// Seul le détenteur du verrou produit touche donc aux variantes, et l'ordre
+// des IDs produits suffit à éviter les deadlocks.
+type ProductVariantRepository interface {
+	// Create renvoie ErrVariantSKUTaken ou ErrVariantOptionsTaken
+	Create(ctx context.Context, variant *entity.ProductVariant) error
+	FindByID(ctx context.Context, id string) (*entity.ProductVariant, error)
+	FindDefault(ctx context.Context, productID string) (*entity.ProductVariant, error)
+
+	// FindByProducts charge en une requête les variantes des produits donnés,
+	// variante par défaut en tête puis par date de création
+	FindByProducts(ctx context.Context, productIDs []string) ([]*entity.ProductVariant, error)
+
+	// Update enregistre SKU, options, prix et stock
+	Update(ctx context.Context, variant *entity.ProductVariant) error
+
+	// Delete renvoie ErrVariantInUse si des commandes référencent la variante
+	Delete(ctx context.Context, id string) error
+
+	// SetDefaultStock fixe le stock de la variante par défaut du produit
+	SetDefaultStock(ctx context.Context, productID string, stock int) error
+
+	// ReserveStock décrémente le stock de la variante du produit donné s'il
+	// est suffisant. Renvoie ErrVariantNotFound (variante absente ou d'un
+	// autre produit) ou ErrInsufficientStock.
+	ReserveStock(ctx context.Context, productID, id string, quantity int) (*entity.ProductVariant, error)
+
+	// RestoreStock remet `quantity` unités en stock (annulation de commande)
+	RestoreStock(ctx context.Context, id string, quantity int) error
+
+	// LockProduct verrouille la ligne products avant une écriture sur ses
+	// variantes ; sql.ErrNoRows si le produit n'existe pas ou est archivé
+	LockProduct(ctx context.Context, productID string) error
+
+	// RefreshProductStock recalcule products.stock à partir des variantes et
+	// renvoie le nouveau total
+	RefreshProductStock(ctx context.Context, productID string) (int, error)
+
+	WithTX(tx Tx) ProductVariantRepository
+}
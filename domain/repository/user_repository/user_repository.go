@@ -9,6 +9,7 @@ var (
 	ErrUserNotFound      = errors.New("user not found")
 	ErrUserAlreadyExists = errors.New("user already exists")
 	ErrUserCreateFailed  = errors.New("failed to create user")
+	ErrRoleNotFound      = errors.New("role not found")
 )
 
 //go:generate mockgen -destination=../../../mocks/repository/mock_user_repository.go -package=repository -source=user_repository.go UserRepository
@@ -17,4 +18,19 @@ type UserRepository interface {
 	CreateUser(user *userentity.UserEntity) (*userentity.UserEntity, error)
 	FindUserByEmail(email string) (*userentity.UserEntity, error)
 	FindUserByID(id string) (*userentity.UserEntity, error)
+
+	// GrantRole est idempotent ; ErrRoleNotFound si le rôle n'existe pas
+	GrantRole(userID, role, grantedBy string) error
+	// RevokeRole est idempotent
+	RevokeRole(userID, role string) error
+
+	// UpdatePassword remplace le hash du mot de passe ; ErrUserNotFound si l'utilisateur n'existe pas
+	UpdatePassword(userID, passwordHash string) error
+	// MarkEmailVerified enregistre la confirmation de l'email (la première date
+	// est conservée) et rattache au compte le client non rattaché de même
+	// email ; ErrUserNotFound si l'utilisateur n'existe pas
+	MarkEmailVerified(userID string) error
+	// DeleteUser efface le compte (droit à l'effacement) : le profil client est
+	// anonymisé et ses commandes conservées ; ErrUserNotFound si l'utilisateur n'existe pas
+	DeleteUser(userID string) error
 }
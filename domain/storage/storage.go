@@ -0,0 +1,38 @@
+// Package storage définit le stockage des fichiers binaires (images des
+// produits). Implémentations dans infrastructure/storage.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+// Blob : contenu lu dans le stockage, Body à fermer par l'appelant
+type Blob struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+}
+
+type BlobStore interface {
+	// Put enregistre le contenu sous `key`, en remplaçant un contenu existant
+	Put(ctx context.Context, key, contentType string, body io.Reader, size int64) error
+	// Get renvoie ErrBlobNotFound si la clé est absente
+	Get(ctx context.Context, key string) (*Blob, error)
+	// Delete est sans effet sur une clé absente
+	Delete(ctx context.Context, key string) error
+}
+
+// Segments séparés par des "/", sans "." ni ".." : une clé ne sort jamais du
+// répertoire ou du bucket
+var keyRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*(/[A-Za-z0-9][A-Za-z0-9._-]*)*$`)
+
+// IsValidKey : clé utilisable telle quelle comme chemin de fichier ou d'URL
+func IsValidKey(key string) bool {
+	return len(key) <= 512 && keyRe.MatchString(key) && !strings.Contains(key, "..")
+}
@@ -0,0 +1,101 @@
+package mailerinfra
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"Goshop/domain/mailer"
+
+	"github.com/rs/zerolog"
+)
+
+const devMailFrom = "GoShop <no-reply@goshop.local>"
+
+// FileMailer écrit chaque email dans un fichier .eml du répertoire `dir`
+// (développement local, tests e2e : les liens se lisent dans les fichiers)
+type FileMailer struct {
+	dir string
+	now func() time.Time
+
+	mu  sync.Mutex
+	seq int
+}
+
+func NewFileMailer(dir string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("mail directory: %w", err)
+	}
+	return &FileMailer{dir: dir, now: time.Now}, nil
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg mailer.Message) error {
+	now := m.now()
+	data, err := formatMessage(devMailFrom, msg, now)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.seq++
+	name := fmt.Sprintf("%s-%04d.eml", now.UTC().Format("20060102T150405"), m.seq)
+	m.mu.Unlock()
+
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write mail: %w", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("mail_file", path).
+		Str("subject", msg.Subject).
+		Msg("📧 Email écrit sur disque")
+	return nil
+}
+
+// LogMailer n'envoie rien : l'email complet est logué. Développement
+// uniquement, les liens contiennent des jetons secrets.
+type LogMailer struct{}
+
+func NewLogMailer() LogMailer {
+	return LogMailer{}
+}
+
+func (LogMailer) Send(ctx context.Context, msg mailer.Message) error {
+	zerolog.Ctx(ctx).Info().
+		Str("to", msg.To).
+		Str("subject", msg.Subject).
+		Str("body", msg.Body).
+		Msg("📧 Email non envoyé (MAILER=log)")
+	return nil
+}
+
+// AsyncMailer envoie en arrière-plan : la réponse HTTP n'attend pas le
+// serveur SMTP et son temps de réponse ne révèle pas si un email est parti.
+// Les erreurs d'envoi sont seulement loguées.
+type AsyncMailer struct {
+	inner   mailer.Mailer
+	timeout time.Duration
+}
+
+func NewAsyncMailer(inner mailer.Mailer, timeout time.Duration) *AsyncMailer {
+	return &AsyncMailer{inner: inner, timeout: timeout}
+}
+
+func (m *AsyncMailer) Send(ctx context.Context, msg mailer.Message) error {
+	logger := zerolog.Ctx(ctx)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), m.timeout)
+		defer cancel()
+		if err := m.inner.Send(ctx, msg); err != nil {
+			logger.Error().
+				Err(err).
+				Str("subject", msg.Subject).
+				Msg("❌ Échec d'envoi d'email")
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,91 @@
+package mailerinfra
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"Goshop/domain/mailer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatMessage(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	body := "Bonjour,\n\nRéinitialisez votre mot de passe : https://shop.example.com/reset-password?token=abc_-123\n"
+
+	raw, err := formatMessage("GoShop <no-reply@goshop.dev>", mailer.Message{
+		To:      "alice@example.com",
+		Subject: "Réinitialisation",
+		Body:    body,
+	}, now)
+	require.NoError(t, err)
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Réinitialisation", subject)
+	assert.Equal(t, "alice@example.com", msg.Header.Get("To"))
+	assert.Equal(t, "text/plain; charset=UTF-8", msg.Header.Get("Content-Type"))
+
+	date, err := msg.Header.Date()
+	require.NoError(t, err)
+	assert.True(t, date.Equal(now))
+
+	decoded, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	require.NoError(t, err)
+	assert.Equal(t, strings.ReplaceAll(body, "\n", "\r\n"), string(decoded))
+}
+
+func TestFormatMessage_RejectsHeaderInjection(t *testing.T) {
+	for name, msg := range map[string]mailer.Message{
+		"destinataire": {To: "alice@example.com\r\nBcc: victim@example.com", Subject: "Hi"},
+		"sujet":        {To: "alice@example.com", Subject: "Hi\nBcc: victim@example.com"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := formatMessage(devMailFrom, msg, time.Now())
+			assert.ErrorIs(t, err, ErrInvalidHeader)
+		})
+	}
+}
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mails")
+	m, err := NewFileMailer(dir)
+	require.NoError(t, err)
+
+	for _, to := range []string{"alice@example.com", "bob@example.com"} {
+		require.NoError(t, m.Send(context.Background(), mailer.Message{To: to, Subject: "Hello", Body: "Hi " + to}))
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	content, err := os.ReadFile(files[1])
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "To: bob@example.com")
+}
+
+func TestNewSMTPMailer_Validation(t *testing.T) {
+	_, err := NewSMTPMailer(SMTPConfig{From: "no-reply@goshop.dev"})
+	assert.Error(t, err, "host manquant")
+
+	_, err = NewSMTPMailer(SMTPConfig{Host: "smtp.example.com", From: "not an address"})
+	assert.Error(t, err, "expéditeur invalide")
+
+	m, err := NewSMTPMailer(SMTPConfig{Host: "smtp.example.com", From: "GoShop <no-reply@goshop.dev>"})
+	require.NoError(t, err)
+	assert.Equal(t, "no-reply@goshop.dev", m.envelope)
+	assert.Equal(t, 587, m.cfg.Port)
+}
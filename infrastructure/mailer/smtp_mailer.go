@@ -0,0 +1,105 @@
+package mailerinfra
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"Goshop/domain/mailer"
+)
+
+const defaultSMTPTimeout = 10 * time.Second
+
+type SMTPConfig struct {
+	Host     string
+	Port     int    // 587 : soumission avec STARTTLS
+	Username string // vide : pas d'authentification
+	Password string
+	From     string // "GoShop <no-reply@goshop.dev>"
+	Timeout  time.Duration
+}
+
+// SMTPMailer : envoi via un relais SMTP. STARTTLS est utilisé dès que le
+// serveur le propose ; l'authentification PLAIN exige TLS (sauf localhost).
+type SMTPMailer struct {
+	cfg      SMTPConfig
+	envelope string // adresse nue de From (MAIL FROM)
+	now      func() time.Time
+}
+
+func NewSMTPMailer(cfg SMTPConfig) (*SMTPMailer, error) {
+	if cfg.Host == "" {
+		return nil, errors.New("SMTP_HOST is required")
+	}
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP_FROM %q: %w", cfg.From, err)
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultSMTPTimeout
+	}
+	return &SMTPMailer{cfg: cfg, envelope: from.Address, now: time.Now}, nil
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg mailer.Message) error {
+	data, err := formatMessage(m.cfg.From, msg, m.now())
+	if err != nil {
+		return err
+	}
+
+	dialer := net.Dialer{Timeout: m.cfg.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port)))
+	if err != nil {
+		return fmt.Errorf("smtp dial: %w", err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = m.now().Add(m.cfg.Timeout)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.cfg.Host, MinVersion: tls.VersionTLS12}); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+	if m.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	if err := c.Mail(m.envelope); err != nil {
+		return fmt.Errorf("smtp MAIL FROM: %w", err)
+	}
+	if err := c.Rcpt(msg.To); err != nil {
+		return fmt.Errorf("smtp RCPT TO: %w", err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("smtp write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp write: %w", err)
+	}
+	return c.Quit()
+}
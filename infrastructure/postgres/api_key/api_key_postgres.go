@@ -0,0 +1,131 @@
+package apikey
+
+import (
+	authentity "Goshop/domain/auth_entity"
+	authrepository "Goshop/domain/repository/auth_repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const apiKeyColumns = `id, name, prefix, secret_hash, scopes, rate_limit, expires_at, last_used_at, revoked_at, created_by, created_at`
+
+type APIKeyPostgres struct {
+	db *sql.DB
+}
+
+func NewAPIKeyPostgres(db *sql.DB) authrepository.APIKeyRepository {
+	return &APIKeyPostgres{db: db}
+}
+
+func (p *APIKeyPostgres) Create(ctx context.Context, key *authentity.APIKey) error {
+	query := `
+	INSERT INTO api_keys (id, name, prefix, secret_hash, scopes, rate_limit, expires_at, created_by)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING created_at
+	`
+	err := p.db.QueryRowContext(ctx, query,
+		key.ID, key.Name, key.Prefix, key.SecretHash, pq.Array(key.Scopes), key.RateLimit,
+		nullTime(key.ExpiresAt), nullString(key.CreatedBy),
+	).Scan(&key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+	return nil
+}
+
+func (p *APIKeyPostgres) FindByPrefix(ctx context.Context, prefix string) (*authentity.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE prefix = $1`
+	key, err := scanAPIKey(p.db.QueryRowContext(ctx, query, prefix))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, authrepository.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find api key: %w", err)
+	}
+	return key, nil
+}
+
+func (p *APIKeyPostgres) List(ctx context.Context) ([]*authentity.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys ORDER BY created_at DESC, id`
+	rows, err := p.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []*authentity.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (p *APIKeyPostgres) Revoke(ctx context.Context, id string) error {
+	res, err := p.db.ExecContext(ctx, `
+	UPDATE api_keys SET revoked_at = NOW()
+	WHERE id = $1 AND revoked_at IS NULL
+	`, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return authrepository.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// TouchLastUsed : la condition sur last_used_at évite une écriture par requête
+// pour les clés très sollicitées
+func (p *APIKeyPostgres) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	_, err := p.db.ExecContext(ctx, `
+	UPDATE api_keys SET last_used_at = $2
+	WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $2 - INTERVAL '1 minute')
+	`, id, at)
+	if err != nil {
+		return fmt.Errorf("failed to update api key last use: %w", err)
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAPIKey(row rowScanner) (*authentity.APIKey, error) {
+	var key authentity.APIKey
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+	var createdBy sql.NullString
+	err := row.Scan(
+		&key.ID, &key.Name, &key.Prefix, &key.SecretHash, pq.Array(&key.Scopes), &key.RateLimit,
+		&expiresAt, &lastUsedAt, &revokedAt, &createdBy, &key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	key.ExpiresAt = expiresAt.Time
+	key.LastUsedAt = lastUsedAt.Time
+	key.RevokedAt = revokedAt.Time
+	key.CreatedBy = createdBy.String
+	return &key, nil
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
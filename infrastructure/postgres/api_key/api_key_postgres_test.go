@@ -0,0 +1,102 @@
+package apikey_test
+
+import (
+	authentity "Goshop/domain/auth_entity"
+	authrepository "Goshop/domain/repository/auth_repository"
+	apikey "Goshop/infrastructure/postgres/api_key"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const secretHash = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+var apiKeyColumns = []string{"id", "name", "prefix", "secret_hash", "scopes", "rate_limit", "expires_at", "last_used_at", "revoked_at", "created_by", "created_at"}
+
+func TestAPIKeyPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	created := time.Now()
+	mock.ExpectQuery(`INSERT INTO api_keys`).
+		WithArgs("key-1", "warehouse", "a1b2c3d4e5f6", secretHash, `{"orders:read:any","products:write"}`, 600, nil, "admin-1").
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(created))
+
+	key := &authentity.APIKey{
+		ID: "key-1", Name: "warehouse", Prefix: "a1b2c3d4e5f6", SecretHash: secretHash,
+		Scopes: []string{"orders:read:any", "products:write"}, RateLimit: 600, CreatedBy: "admin-1",
+	}
+	err = apikey.NewAPIKeyPostgres(db).Create(context.Background(), key)
+
+	assert.NoError(t, err)
+	assert.Equal(t, created, key.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyPostgres_FindByPrefix(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := apikey.NewAPIKeyPostgres(db)
+	expires := time.Now().Add(24 * time.Hour)
+	created := time.Now().Add(-time.Hour)
+
+	mock.ExpectQuery(`SELECT .+ FROM api_keys WHERE prefix = \$1`).
+		WithArgs("a1b2c3d4e5f6").
+		WillReturnRows(sqlmock.NewRows(apiKeyColumns).
+			AddRow("key-1", "warehouse", "a1b2c3d4e5f6", secretHash, `{orders:read:any,products:write}`, 600, expires, nil, nil, nil, created))
+	mock.ExpectQuery(`FROM api_keys WHERE prefix = \$1`).
+		WithArgs("unknown").
+		WillReturnError(sql.ErrNoRows)
+
+	key, err := repo.FindByPrefix(context.Background(), "a1b2c3d4e5f6")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders:read:any", "products:write"}, key.Scopes)
+	assert.Equal(t, expires, key.ExpiresAt)
+	assert.True(t, key.LastUsedAt.IsZero())
+	assert.True(t, key.RevokedAt.IsZero())
+	assert.Empty(t, key.CreatedBy)
+
+	_, err = repo.FindByPrefix(context.Background(), "unknown")
+	assert.ErrorIs(t, err, authrepository.ErrAPIKeyNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyPostgres_Revoke(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := apikey.NewAPIKeyPostgres(db)
+	mock.ExpectExec(`UPDATE api_keys SET revoked_at = NOW\(\)\s+WHERE id = \$1 AND revoked_at IS NULL`).
+		WithArgs("key-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE api_keys SET revoked_at`).
+		WithArgs("key-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.Revoke(context.Background(), "key-1"))
+	assert.ErrorIs(t, repo.Revoke(context.Background(), "key-1"), authrepository.ErrAPIKeyNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyPostgres_TouchLastUsed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectExec(`UPDATE api_keys SET last_used_at = \$2\s+WHERE id = \$1 AND \(last_used_at IS NULL OR last_used_at < \$2 - INTERVAL '1 minute'\)`).
+		WithArgs("key-1", now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, apikey.NewAPIKeyPostgres(db).TouchLastUsed(context.Background(), "key-1", now))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
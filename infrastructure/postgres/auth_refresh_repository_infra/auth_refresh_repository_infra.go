@@ -4,9 +4,23 @@ import (
 	authentity "Goshop/domain/auth_entity"
 	authrepository "Goshop/domain/repository/auth_repository"
 	"database/sql"
+	"fmt"
 	"time"
 )
 
+// Colonnes lues par FindByID / ListActiveForUser (voir scanSession)
+const sessionColumns = `id, user_id, expires_at, revoked, created_at,
+	family_id, COALESCE(parent_id, ''), COALESCE(replaced_by, ''),
+	last_used_at, user_agent, ip_address`
+
+// Une session sans famille est la racine de la sienne
+const insertSessionQuery = `
+	INSERT INTO refresh_sessions (id, user_id, expires_at, revoked, created_at, family_id, parent_id,
+		last_used_at, user_agent, ip_address)
+	VALUES ($1, $2, $3, $4, $5, COALESCE(NULLIF($6, ''), $1), NULLIF($7, ''), $8, $9, $10)
+	ON CONFLICT (id) DO UPDATE SET revoked = EXCLUDED.revoked, expires_at = EXCLUDED.expires_at
+	`
+
 type RefreshSessionPostgres struct {
 	db *sql.DB
 }
@@ -16,26 +30,126 @@ func NewRefreshSessionPostgres(db *sql.DB) authrepository.RefreshSessionReposito
 }
 
 func (r *RefreshSessionPostgres) Create(session *authentity.RefreshSession) error {
-	query := `
-	INSERT INTO refresh_sessions (id, user_id, expires_at, revoked, created_at)
-	VALUES ($1, $2, $3, $4, $5)
-	ON CONFLICT (id) DO UPDATE SET revoked = EXCLUDED.revoked, expires_at = EXCLUDED.expires_at
-	`
-	_, err := r.db.Exec(query, session.ID, session.UserID, session.ExpiresAt.UTC(), session.Revoked, session.CreatedAt.UTC())
+	_, err := r.db.Exec(insertSessionQuery, sessionArgs(session)...)
 	return err
 }
 
 func (r *RefreshSessionPostgres) FindByID(id string) (*authentity.RefreshSession, error) {
 	query := `
-	SELECT id, user_id, expires_at, revoked, created_at
+	SELECT ` + sessionColumns + `
 	FROM refresh_sessions
 	WHERE id = $1
 	`
-	row := r.db.QueryRow(query, id)
+	return scanSession(r.db.QueryRow(query, id))
+}
+
+func (r *RefreshSessionPostgres) Revoke(id string) error {
+	query := `
+	UPDATE refresh_sessions SET revoked = true WHERE id = $1
+	`
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+// Rotate : la révocation conditionnelle (revoked = false) sérialise deux
+// rotations concurrentes du même token ; seule la première insère la suite.
+func (r *RefreshSessionPostgres) Rotate(oldID string, next *authentity.RefreshSession) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin rotation: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+	UPDATE refresh_sessions SET revoked = true, replaced_by = $2
+	WHERE id = $1 AND revoked = false
+	`, oldID, next.ID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return authrepository.ErrSessionAlreadyRotated
+	}
+
+	if _, err := tx.Exec(insertSessionQuery, sessionArgs(next)...); err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (r *RefreshSessionPostgres) RevokeFamily(familyID string) (int, error) {
+	query := `
+	UPDATE refresh_sessions SET revoked = true
+	WHERE family_id = $1 AND revoked = false
+	`
+	return execCount(r.db, query, familyID)
+}
+
+func (r *RefreshSessionPostgres) RevokeAllForUser(userID string) (int, error) {
+	query := `
+	UPDATE refresh_sessions SET revoked = true
+	WHERE user_id = $1 AND revoked = false
+	`
+	return execCount(r.db, query, userID)
+}
+
+func (r *RefreshSessionPostgres) RevokeOtherSessions(userID, keepFamilyID string) (int, error) {
+	query := `
+	UPDATE refresh_sessions SET revoked = true
+	WHERE user_id = $1 AND revoked = false AND family_id <> $2
+	`
+	return execCount(r.db, query, userID, keepFamilyID)
+}
+
+func (r *RefreshSessionPostgres) ListActiveForUser(userID string) ([]*authentity.RefreshSession, error) {
+	query := `
+	SELECT ` + sessionColumns + `
+	FROM refresh_sessions
+	WHERE user_id = $1 AND revoked = false AND expires_at > NOW()
+	ORDER BY last_used_at DESC, id DESC
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*authentity.RefreshSession{}
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, rows.Err()
+}
+
+func sessionArgs(s *authentity.RefreshSession) []any {
+	lastUsedAt := s.LastUsedAt
+	if lastUsedAt.IsZero() {
+		lastUsedAt = s.CreatedAt
+	}
+	return []any{s.ID, s.UserID, s.ExpiresAt.UTC(), s.Revoked, s.CreatedAt.UTC(), s.FamilyID, s.ParentID,
+		lastUsedAt.UTC(), s.UserAgent, s.IPAddress}
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSession(row rowScanner) (*authentity.RefreshSession, error) {
 	var s authentity.RefreshSession
 	var expiresAt time.Time
 	var createdAt time.Time
-	if err := row.Scan(&s.ID, &s.UserID, &expiresAt, &s.Revoked, &createdAt); err != nil {
+	if err := row.Scan(&s.ID, &s.UserID, &expiresAt, &s.Revoked, &createdAt,
+		&s.FamilyID, &s.ParentID, &s.ReplacedBy,
+		&s.LastUsedAt, &s.UserAgent, &s.IPAddress); err != nil {
 		return nil, err
 	}
 	s.ExpiresAt = expiresAt
@@ -43,10 +157,14 @@ func (r *RefreshSessionPostgres) FindByID(id string) (*authentity.RefreshSession
 	return &s, nil
 }
 
-func (r *RefreshSessionPostgres) Revoke(id string) error {
-	query := `
-	UPDATE refresh_sessions SET revoked = true WHERE id = $1
-	`
-	_, err := r.db.Exec(query, id)
-	return err
+func execCount(db *sql.DB, query string, args ...any) (int, error) {
+	res, err := db.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(n), nil
 }
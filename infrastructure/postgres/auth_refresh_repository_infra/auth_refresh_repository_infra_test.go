@@ -0,0 +1,127 @@
+package authrefreshrepositoryinfra_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	authentity "Goshop/domain/auth_entity"
+	authrepository "Goshop/domain/repository/auth_repository"
+	authrefreshrepositoryinfra "Goshop/infrastructure/postgres/auth_refresh_repository_infra"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func nextSession() *authentity.RefreshSession {
+	now := time.Now()
+	return &authentity.RefreshSession{
+		ID:        "jti-2",
+		UserID:    "user-1",
+		ExpiresAt: now.Add(time.Hour),
+		CreatedAt: now,
+		FamilyID:  "jti-0",
+		ParentID:  "jti-1",
+
+		LastUsedAt: now,
+		UserAgent:  "GoShop-iOS/2.1",
+		IPAddress:  "203.0.113.7",
+	}
+}
+
+func TestRefreshSessionPostgres_Rotate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := authrefreshrepositoryinfra.NewRefreshSessionPostgres(db)
+	next := nextSession()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE refresh_sessions SET revoked = true, replaced_by = \$2`).
+		WithArgs("jti-1", "jti-2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO refresh_sessions`).
+		WithArgs("jti-2", "user-1", next.ExpiresAt.UTC(), false, next.CreatedAt.UTC(), "jti-0", "jti-1",
+			next.LastUsedAt.UTC(), "GoShop-iOS/2.1", "203.0.113.7").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.Rotate("jti-1", next))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshSessionPostgres_Rotate_AlreadyRotated(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := authrefreshrepositoryinfra.NewRefreshSessionPostgres(db)
+
+	// La session n'est plus active : aucune ligne révoquée, rien n'est inséré
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE refresh_sessions SET revoked = true, replaced_by = \$2`).
+		WithArgs("jti-1", "jti-2").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = repo.Rotate("jti-1", nextSession())
+
+	assert.ErrorIs(t, err, authrepository.ErrSessionAlreadyRotated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshSessionPostgres_Rotate_InsertFails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := authrefreshrepositoryinfra.NewRefreshSessionPostgres(db)
+
+	// L'échec de l'insertion annule aussi la révocation
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE refresh_sessions SET revoked = true, replaced_by = \$2`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO refresh_sessions`).
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	assert.Error(t, repo.Rotate("jti-1", nextSession()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshSessionPostgres_RevokeFamily(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := authrefreshrepositoryinfra.NewRefreshSessionPostgres(db)
+
+	mock.ExpectExec(`UPDATE refresh_sessions SET revoked = true\s+WHERE family_id = \$1`).
+		WithArgs("jti-0").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	revoked, err := repo.RevokeFamily("jti-0")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, revoked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshSessionPostgres_RevokeOtherSessions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := authrefreshrepositoryinfra.NewRefreshSessionPostgres(db)
+
+	mock.ExpectExec(`UPDATE refresh_sessions SET revoked = true\s+WHERE user_id = \$1 AND revoked = false AND family_id <> \$2`).
+		WithArgs("user-1", "jti-0").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	revoked, err := repo.RevokeOtherSessions("user-1", "jti-0")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, revoked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
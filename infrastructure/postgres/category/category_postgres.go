@@ -0,0 +1,297 @@
+package category
+
+import (
+	"Goshop/domain/entity"
+	"Goshop/domain/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+const categoryColumns = `id, name, slug, parent_id, path, created_at, updated_at`
+
+// categoryTreeLockID identifie le verrou pg_advisory_xact_lock de l'arbre des
+// catégories
+const categoryTreeLockID int64 = 7_310_245_119
+
+type CategoryPostgres struct {
+	db *sql.DB
+	tx repository.Tx
+}
+
+func NewCategoryPostgres(db *sql.DB) repository.CategoryRepository {
+	return &CategoryPostgres{db: db}
+}
+
+func (cp *CategoryPostgres) WithTX(tx repository.Tx) repository.CategoryRepository {
+	return &CategoryPostgres{tx: tx, db: cp.db}
+}
+
+func (cp *CategoryPostgres) queryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if cp.tx != nil {
+		return cp.tx.QueryRowContext(ctx, query, args...)
+	}
+	return cp.db.QueryRowContext(ctx, query, args...)
+}
+
+func (cp *CategoryPostgres) queryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if cp.tx != nil {
+		return cp.tx.QueryContext(ctx, query, args...)
+	}
+	return cp.db.QueryContext(ctx, query, args...)
+}
+
+func (cp *CategoryPostgres) execContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if cp.tx != nil {
+		return cp.tx.ExecContext(ctx, query, args...)
+	}
+	return cp.db.ExecContext(ctx, query, args...)
+}
+
+func (cp *CategoryPostgres) Create(ctx context.Context, c *entity.Category) error {
+	query := `INSERT INTO categories (name, slug, parent_id, path)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id, created_at, updated_at;`
+	err := cp.queryRowContext(ctx, query, c.Name, c.Slug, nullString(c.ParentID), c.Path).
+		Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+	if isUniqueViolation(err) {
+		return repository.ErrCategorySlugTaken
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create category: %w", err)
+	}
+	return nil
+}
+
+func (cp *CategoryPostgres) FindByID(ctx context.Context, id string) (*entity.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE id = $1`
+	return cp.findOne(ctx, query, id)
+}
+
+func (cp *CategoryPostgres) FindBySlug(ctx context.Context, slug string) (*entity.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE slug = $1`
+	return cp.findOne(ctx, query, slug)
+}
+
+func (cp *CategoryPostgres) findOne(ctx context.Context, query string, arg string) (*entity.Category, error) {
+	c, err := scanCategory(cp.queryRowContext(ctx, query, arg))
+	if errors.Is(err, sql.ErrNoRows) || isInvalidUUID(err) {
+		return nil, repository.ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find category: %w", err)
+	}
+	return c, nil
+}
+
+func (cp *CategoryPostgres) FindByIDs(ctx context.Context, ids []string) ([]*entity.Category, error) {
+	if len(ids) == 0 {
+		return []*entity.Category{}, nil
+	}
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE id = ANY($1::uuid[]) ORDER BY path`
+	categories, err := cp.findMany(ctx, query, pq.Array(ids))
+	if isInvalidUUID(err) {
+		// Un ID mal formé ne peut désigner aucune catégorie
+		return []*entity.Category{}, nil
+	}
+	return categories, err
+}
+
+func (cp *CategoryPostgres) FindAll(ctx context.Context) ([]*entity.Category, error) {
+	return cp.findMany(ctx, `SELECT `+categoryColumns+` FROM categories ORDER BY path`)
+}
+
+func (cp *CategoryPostgres) FindChildren(ctx context.Context, parentID string) ([]*entity.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE parent_id = $1 ORDER BY name, id`
+	return cp.findMany(ctx, query, parentID)
+}
+
+func (cp *CategoryPostgres) FindBreadcrumb(ctx context.Context, path string) ([]*entity.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories
+	WHERE $1 = path OR $1 LIKE path || '/%'
+	ORDER BY length(path)`
+	return cp.findMany(ctx, query, path)
+}
+
+func (cp *CategoryPostgres) findMany(ctx context.Context, query string, args ...interface{}) ([]*entity.Category, error) {
+	rows, err := cp.queryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch categories: %w", err)
+	}
+	defer rows.Close()
+
+	categories := []*entity.Category{}
+	for rows.Next() {
+		c, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category row: %w", err)
+		}
+		categories = append(categories, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return categories, nil
+}
+
+func (cp *CategoryPostgres) Update(ctx context.Context, c *entity.Category, oldPath string) error {
+	query := `
+	UPDATE categories
+	SET name = $1, slug = $2, parent_id = $3, path = $4, updated_at = NOW()
+	WHERE id = $5
+	RETURNING updated_at;`
+	err := cp.queryRowContext(ctx, query, c.Name, c.Slug, nullString(c.ParentID), c.Path, c.ID).Scan(&c.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.ErrCategoryNotFound
+	}
+	if isUniqueViolation(err) {
+		return repository.ErrCategorySlugTaken
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update category: %w", err)
+	}
+
+	if oldPath == c.Path {
+		return nil
+	}
+
+	// Les descendants gardent leur suffixe, seul le préfixe change
+	_, err = cp.execContext(ctx, `
+	UPDATE categories
+	SET path = $1 || substr(path, length($2) + 1), updated_at = NOW()
+	WHERE path LIKE $2 || '/%'`, c.Path, oldPath)
+	if err != nil {
+		return fmt.Errorf("failed to move subcategories of %s: %w", c.ID, err)
+	}
+	return nil
+}
+
+// LockTree : verrou consultatif libéré au commit / rollback. Les lectures
+// suivantes de la transaction voient les déplacements committés avant lui.
+func (cp *CategoryPostgres) LockTree(ctx context.Context) error {
+	if _, err := cp.execContext(ctx, `SELECT pg_advisory_xact_lock($1)`, categoryTreeLockID); err != nil {
+		return fmt.Errorf("failed to lock category tree: %w", err)
+	}
+	return nil
+}
+
+func (cp *CategoryPostgres) Delete(ctx context.Context, id string) error {
+	res, err := cp.execContext(ctx, `DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		// parent_id ON DELETE RESTRICT : la catégorie a encore des enfants
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return repository.ErrCategoryHasChildren
+		}
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return repository.ErrCategoryNotFound
+	}
+	return nil
+}
+
+func (cp *CategoryPostgres) SetProductCategories(ctx context.Context, productID string, categoryIDs []string) error {
+	if _, err := cp.execContext(ctx, `DELETE FROM product_categories WHERE product_id = $1`, productID); err != nil {
+		return fmt.Errorf("failed to clear categories of product %s: %w", productID, err)
+	}
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+
+	_, err := cp.execContext(ctx, `
+	INSERT INTO product_categories (product_id, category_id)
+	SELECT $1::uuid, unnest($2::uuid[])
+	ON CONFLICT DO NOTHING`, productID, pq.Array(categoryIDs))
+	if err != nil {
+		return fmt.Errorf("failed to link categories to product %s: %w", productID, err)
+	}
+	return nil
+}
+
+func (cp *CategoryPostgres) FindProductCategories(ctx context.Context, productIDs []string) ([]*entity.ProductCategory, error) {
+	if len(productIDs) == 0 {
+		return []*entity.ProductCategory{}, nil
+	}
+
+	// Une ligne par (produit, catégorie, ancêtre) : les ancêtres d'une même
+	// catégorie se suivent, du plus court path au plus long
+	query := `
+	SELECT pc.product_id,
+		c.id, c.name, c.slug, c.parent_id, c.path, c.created_at, c.updated_at,
+		a.id, a.name, a.slug, a.parent_id, a.path, a.created_at, a.updated_at
+	FROM product_categories pc
+	JOIN categories c ON c.id = pc.category_id
+	JOIN categories a ON c.path = a.path OR c.path LIKE a.path || '/%'
+	WHERE pc.product_id = ANY($1::uuid[])
+	ORDER BY pc.product_id, c.path, length(a.path)`
+
+	rows, err := cp.queryContext(ctx, query, pq.Array(productIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product categories: %w", err)
+	}
+	defer rows.Close()
+
+	result := []*entity.ProductCategory{}
+	var current *entity.ProductCategory
+	for rows.Next() {
+		var productID string
+		var c, a entity.Category
+		var cParent, aParent sql.NullString
+		err := rows.Scan(&productID,
+			&c.ID, &c.Name, &c.Slug, &cParent, &c.Path, &c.CreatedAt, &c.UpdatedAt,
+			&a.ID, &a.Name, &a.Slug, &aParent, &a.Path, &a.CreatedAt, &a.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product category row: %w", err)
+		}
+		c.ParentID = cParent.String
+		a.ParentID = aParent.String
+
+		if current == nil || current.ProductID != productID || current.Category.ID != c.ID {
+			current = &entity.ProductCategory{ProductID: productID, Category: &c}
+			result = append(result, current)
+		}
+		current.Breadcrumb = append(current.Breadcrumb, &a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return result, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCategory(row rowScanner) (*entity.Category, error) {
+	var c entity.Category
+	var parentID sql.NullString
+	if err := row.Scan(&c.ID, &c.Name, &c.Slug, &parentID, &c.Path, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	c.ParentID = parentID.String
+	return &c, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// isInvalidUUID : identifiant qui n'est pas un UUID (invalid_text_representation)
+func isInvalidUUID(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "22P02"
+}
@@ -0,0 +1,202 @@
+package category_test
+
+import (
+	"Goshop/domain/entity"
+	"Goshop/domain/repository"
+	"Goshop/infrastructure/postgres/category"
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var categoryColumns = []string{"id", "name", "slug", "parent_id", "path", "created_at", "updated_at"}
+
+func TestCategoryPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := category.NewCategoryPostgres(db)
+	now := time.Now()
+
+	mock.ExpectQuery(`INSERT INTO categories`).
+		WithArgs("Portables", "portables", "cat-1", "electronique/portables").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow("cat-2", now, now))
+	mock.ExpectQuery(`INSERT INTO categories`).
+		WithArgs("Électronique", "electronique", nil, "electronique").
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	c := &entity.Category{Name: "Portables", Slug: "portables", ParentID: "cat-1", Path: "electronique/portables"}
+	require.NoError(t, repo.Create(context.Background(), c))
+	assert.Equal(t, "cat-2", c.ID)
+
+	err = repo.Create(context.Background(), &entity.Category{Name: "Électronique", Slug: "electronique", Path: "electronique"})
+	assert.ErrorIs(t, err, repository.ErrCategorySlugTaken)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryPostgres_FindBySlug(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := category.NewCategoryPostgres(db)
+	mock.ExpectQuery(`FROM categories WHERE slug = \$1`).
+		WithArgs("electronique").
+		WillReturnRows(sqlmock.NewRows(categoryColumns).
+			AddRow("cat-1", "Électronique", "electronique", nil, "electronique", time.Now(), time.Now()))
+	mock.ExpectQuery(`FROM categories WHERE slug = \$1`).
+		WithArgs("inconnue").
+		WillReturnError(sql.ErrNoRows)
+
+	c, err := repo.FindBySlug(context.Background(), "electronique")
+	require.NoError(t, err)
+	assert.Empty(t, c.ParentID)
+
+	_, err = repo.FindBySlug(context.Background(), "inconnue")
+	assert.ErrorIs(t, err, repository.ErrCategoryNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryPostgres_UpdateMovesDescendants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := category.NewCategoryPostgres(db)
+	mock.ExpectQuery(`UPDATE categories\s+SET name = \$1, slug = \$2, parent_id = \$3, path = \$4`).
+		WithArgs("Ordinateurs portables", "ordinateurs-portables", "cat-3", "informatique/ordinateurs-portables", "cat-2").
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(time.Now()))
+	mock.ExpectExec(regexp.QuoteMeta(`SET path = $1 || substr(path, length($2) + 1), updated_at = NOW()
+	WHERE path LIKE $2 || '/%'`)).
+		WithArgs("informatique/ordinateurs-portables", "electronique/portables").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	c := &entity.Category{
+		ID: "cat-2", Name: "Ordinateurs portables", Slug: "ordinateurs-portables",
+		ParentID: "cat-3", Path: "informatique/ordinateurs-portables",
+	}
+	assert.NoError(t, repo.Update(context.Background(), c, "electronique/portables"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryPostgres_UpdateSamePath(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := category.NewCategoryPostgres(db)
+	// Simple renommage : pas de réécriture des descendants
+	mock.ExpectQuery(`UPDATE categories`).
+		WithArgs("Portables", "portables", "cat-1", "electronique/portables", "cat-2").
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(time.Now()))
+
+	c := &entity.Category{ID: "cat-2", Name: "Portables", Slug: "portables", ParentID: "cat-1", Path: "electronique/portables"}
+	assert.NoError(t, repo.Update(context.Background(), c, "electronique/portables"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryPostgres_LockTree(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(\$1\)`).
+		WithArgs(int64(7_310_245_119)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	require.NoError(t, category.NewCategoryPostgres(db).WithTX(tx).LockTree(context.Background()))
+	require.NoError(t, tx.Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryPostgres_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := category.NewCategoryPostgres(db)
+	mock.ExpectExec(`DELETE FROM categories WHERE id = \$1`).
+		WithArgs("cat-1").
+		WillReturnError(&pq.Error{Code: "23503"})
+	mock.ExpectExec(`DELETE FROM categories WHERE id = \$1`).
+		WithArgs("cat-9").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM categories WHERE id = \$1`).
+		WithArgs("cat-2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.ErrorIs(t, repo.Delete(context.Background(), "cat-1"), repository.ErrCategoryHasChildren)
+	assert.ErrorIs(t, repo.Delete(context.Background(), "cat-9"), repository.ErrCategoryNotFound)
+	assert.NoError(t, repo.Delete(context.Background(), "cat-2"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryPostgres_SetProductCategories(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := category.NewCategoryPostgres(db)
+	mock.ExpectExec(`DELETE FROM product_categories WHERE product_id = \$1`).
+		WithArgs("p-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO product_categories`).
+		WithArgs("p-1", `{"cat-1","cat-2"}`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	// Liste vide : les liens sont seulement retirés
+	mock.ExpectExec(`DELETE FROM product_categories WHERE product_id = \$1`).
+		WithArgs("p-2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.SetProductCategories(context.Background(), "p-1", []string{"cat-1", "cat-2"}))
+	assert.NoError(t, repo.SetProductCategories(context.Background(), "p-2", []string{}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryPostgres_FindProductCategories(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := category.NewCategoryPostgres(db)
+	now := time.Now()
+	cols := append([]string{"product_id"}, append(categoryColumns, categoryColumns...)...)
+
+	mock.ExpectQuery(`FROM product_categories pc`).
+		WithArgs(`{"p-1","p-2"}`).
+		WillReturnRows(sqlmock.NewRows(cols).
+			AddRow("p-1",
+				"cat-2", "Portables", "portables", "cat-1", "electronique/portables", now, now,
+				"cat-1", "Électronique", "electronique", nil, "electronique", now, now).
+			AddRow("p-1",
+				"cat-2", "Portables", "portables", "cat-1", "electronique/portables", now, now,
+				"cat-2", "Portables", "portables", "cat-1", "electronique/portables", now, now).
+			AddRow("p-2",
+				"cat-1", "Électronique", "electronique", nil, "electronique", now, now,
+				"cat-1", "Électronique", "electronique", nil, "electronique", now, now))
+
+	result, err := repo.FindProductCategories(context.Background(), []string{"p-1", "p-2"})
+
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, "p-1", result[0].ProductID)
+	assert.Equal(t, "portables", result[0].Category.Slug)
+	require.Len(t, result[0].Breadcrumb, 2)
+	assert.Equal(t, "electronique", result[0].Breadcrumb[0].Slug)
+	assert.Equal(t, "portables", result[0].Breadcrumb[1].Slug)
+	assert.Equal(t, "p-2", result[1].ProductID)
+	assert.Len(t, result[1].Breadcrumb, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
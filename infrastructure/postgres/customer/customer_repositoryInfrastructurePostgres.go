@@ -2,8 +2,10 @@ package customer
 
 import (
 	dto "Goshop/application/dto/customer_dto"
+	paginationdto "Goshop/application/dto/pagination_dto"
 	"Goshop/domain/entity"
 	"Goshop/domain/repository"
+	"Goshop/infrastructure/postgres"
 	"context"
 	"database/sql"
 	"fmt"
@@ -28,9 +30,31 @@ func (cr *CustomerRepoInfrastructurePostgres) WithTX(tx repository.Tx) repositor
 	}
 }
 
-func (cr *CustomerRepoInfrastructurePostgres) CountAllCustomers(ctx context.Context, filter dto.CustomerFilter) (int, error) {
-	baseQuery := `SELECT COUNT(*) FROM customers`
+const customerColumns = `id, first_name, last_name, email, created_at, updated_at, deleted_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCustomer(row rowScanner) (*entity.Customer, error) {
+	c := &entity.Customer{}
+	var deletedAt sql.NullTime
+	if err := row.Scan(&c.ID, &c.FirstName, &c.LastName, &c.Email, &c.CreatedAt, &c.UpdatedAt, &deletedAt); err != nil {
+		return nil, err
+	}
+	if deletedAt.Valid {
+		c.DeletedAt = &deletedAt.Time
+	}
+	return c, nil
+}
+
+// buildCustomerWhere construit la clause WHERE commune au comptage et aux listes.
+// Les clients archivés sont exclus sauf si filter.IncludeDeleted.
+func buildCustomerWhere(filter dto.CustomerFilter) (string, []interface{}) {
 	conditions := []string{}
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
 	args := []interface{}{}
 	argPos := 1
 
@@ -44,13 +68,17 @@ func (cr *CustomerRepoInfrastructurePostgres) CountAllCustomers(ctx context.Cont
 	if filter.Email != nil {
 		conditions = append(conditions, fmt.Sprintf("email ILIKE $%d", argPos))
 		args = append(args, "%"+*filter.Email+"%")
-		// argPos++ // non utilisé après
 	}
 
-	query := baseQuery
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
+	if len(conditions) == 0 {
+		return "", args
 	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func (cr *CustomerRepoInfrastructurePostgres) CountAllCustomers(ctx context.Context, filter dto.CustomerFilter) (int, error) {
+	whereClause, args := buildCustomerWhere(filter)
+	query := `SELECT COUNT(*) FROM customers` + whereClause
 
 	var count int
 	err := cr.queryRowContext(ctx, query, args...).Scan(&count)
@@ -65,58 +93,72 @@ func (cr *CustomerRepoInfrastructurePostgres) FindAllCustomersWithPagination(
 	limit, offset int,
 	filter dto.CustomerFilter,
 ) ([]*entity.Customer, error) {
-	baseQuery := `
-		SELECT id, first_name, last_name, email, created_at, updated_at
-		FROM customers`
-	conditions := []string{}
-	args := []interface{}{}
-	argPos := 1
+	whereClause, args := buildCustomerWhere(filter)
+	argPos := len(args) + 1
 
-	if filter.Name != nil {
-		conditions = append(conditions, fmt.Sprintf("(first_name ILIKE $%d OR last_name ILIKE $%d)", argPos, argPos))
-		args = append(args, "%"+*filter.Name+"%")
-		argPos++
-	}
+	// Ajouter ORDER + LIMIT/OFFSET (id en départage pour des pages stables)
+	query := `
+		SELECT ` + customerColumns + `
+		FROM customers` + whereClause + " ORDER BY created_at DESC, id DESC LIMIT $" + strconv.Itoa(argPos) + " OFFSET $" + strconv.Itoa(argPos+1)
+	args = append(args, limit, offset)
 
-	if filter.Email != nil {
-		conditions = append(conditions, fmt.Sprintf("email ILIKE $%d", argPos))
-		args = append(args, "%"+*filter.Email+"%")
-		argPos++
+	rows, err := cr.queryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch paginated customers: %w", err)
 	}
+	defer rows.Close()
+
+	return scanCustomers(rows)
+}
 
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = " WHERE " + strings.Join(conditions, " AND ")
+func (cr *CustomerRepoInfrastructurePostgres) FindAllCustomersByCursor(
+	ctx context.Context,
+	page paginationdto.CursorPage,
+	filter dto.CustomerFilter,
+) ([]*entity.Customer, error) {
+	whereClause, args := buildCustomerWhere(filter)
+
+	keyset, orderBy, keysetArgs := postgres.KeysetClause(page, "", len(args)+1)
+	if keyset != "" {
+		if whereClause == "" {
+			whereClause = " WHERE " + keyset
+		} else {
+			whereClause += " AND " + keyset
+		}
+		args = append(args, keysetArgs...)
 	}
 
-	// Ajouter ORDER + LIMIT/OFFSET
-	query := baseQuery + whereClause + " ORDER BY created_at DESC LIMIT $" + strconv.Itoa(argPos) + " OFFSET $" + strconv.Itoa(argPos+1)
-	args = append(args, limit, offset)
+	query := `
+		SELECT ` + customerColumns + `
+		FROM customers` + whereClause + orderBy + " LIMIT $" + strconv.Itoa(len(args)+1)
+	args = append(args, page.Limit)
 
 	rows, err := cr.queryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch paginated customers: %w", err)
+		return nil, fmt.Errorf("failed to fetch customers page: %w", err)
 	}
 	defer rows.Close()
 
-	var customers []*entity.Customer
+	customers, err := scanCustomers(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	postgres.RestoreOrder(customers, page)
+	return customers, nil
+}
+
+func scanCustomers(rows *sql.Rows) ([]*entity.Customer, error) {
+	customers := []*entity.Customer{}
 	for rows.Next() {
-		c := &entity.Customer{}
-		err := rows.Scan(
-			&c.ID,
-			&c.FirstName,
-			&c.LastName,
-			&c.Email,
-			&c.CreatedAt,
-			&c.UpdatedAt,
-		)
+		c, err := scanCustomer(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan customer: %w", err)
 		}
 		customers = append(customers, c)
 	}
 
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("row iteration error: %w", err)
 	}
 
@@ -149,9 +191,10 @@ func (cr *CustomerRepoInfrastructurePostgres) FindAllCustomersWithSorting(ctx co
 	}
 
 	query := fmt.Sprintf(`
-		SELECT id, first_name, last_name, email, created_at, updated_at
+		SELECT %s
 		FROM customers
-		ORDER BY %s %s`, column, direction)
+		WHERE deleted_at IS NULL
+		ORDER BY %s %s`, customerColumns, column, direction)
 
 	rows, err := cr.queryContext(ctx, query)
 	if err != nil {
@@ -161,15 +204,7 @@ func (cr *CustomerRepoInfrastructurePostgres) FindAllCustomersWithSorting(ctx co
 
 	var customers []*entity.Customer
 	for rows.Next() {
-		c := &entity.Customer{}
-		err := rows.Scan(
-			&c.ID,
-			&c.FirstName,
-			&c.LastName,
-			&c.Email,
-			&c.CreatedAt,
-			&c.UpdatedAt,
-		)
+		c, err := scanCustomer(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan customer: %w", err)
 		}
@@ -219,27 +254,18 @@ func (cr *CustomerRepoInfrastructurePostgres) Create(ctx context.Context, custom
 	return customer, err
 }
 func (cr *CustomerRepoInfrastructurePostgres) FindByCustomerID(ctx context.Context, id string) (*entity.Customer, error) {
-	customer := entity.Customer{}
-	query := `SELECT id, first_name, last_name, email, created_at, updated_at FROM
-	customers WHERE id=$1`
-	err := cr.queryRowContext(ctx, query, id).Scan(
-		&customer.ID,
-		&customer.FirstName,
-		&customer.LastName,
-		&customer.Email,
-		&customer.CreatedAt,
-		&customer.UpdatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, err
-	}
+	query := `SELECT ` + customerColumns + ` FROM
+	customers WHERE id=$1 AND deleted_at IS NULL`
+	return scanCustomer(cr.queryRowContext(ctx, query, id))
+}
 
-	return &customer, nil
+func (cr *CustomerRepoInfrastructurePostgres) FindByCustomerIDIncludingDeleted(ctx context.Context, id string) (*entity.Customer, error) {
+	query := `SELECT ` + customerColumns + ` FROM customers WHERE id=$1`
+	return scanCustomer(cr.queryRowContext(ctx, query, id))
 }
 
 func (cr *CustomerRepoInfrastructurePostgres) FindAllCustomers(ctx context.Context) ([]*entity.Customer, error) {
-	query := `SELECT id, first_name, last_name, email, created_at, updated_at FROM customers `
+	query := `SELECT ` + customerColumns + ` FROM customers WHERE deleted_at IS NULL`
 	rows, err := cr.queryContext(ctx, query)
 	if err != nil {
 		return nil, err
@@ -249,15 +275,8 @@ func (cr *CustomerRepoInfrastructurePostgres) FindAllCustomers(ctx context.Conte
 	var customers []*entity.Customer
 
 	for rows.Next() {
-		customer := &entity.Customer{}
-		if err := rows.Scan(
-			&customer.ID,
-			&customer.FirstName,
-			&customer.LastName,
-			&customer.Email,
-			&customer.CreatedAt,
-			&customer.UpdatedAt,
-		); err != nil {
+		customer, err := scanCustomer(rows)
+		if err != nil {
 			return nil, err
 		}
 		customers = append(customers, customer)
@@ -271,7 +290,7 @@ func (cr *CustomerRepoInfrastructurePostgres) UpdateCustomer(ctx context.Context
 	query := `
     UPDATE customers
     SET first_name = $1, last_name = $2, email = $3, updated_at = NOW()
-    WHERE id = $4
+    WHERE id = $4 AND deleted_at IS NULL
     RETURNING first_name, last_name, email, created_at, updated_at, id
     `
 
@@ -292,28 +311,41 @@ func (cr *CustomerRepoInfrastructurePostgres) UpdateCustomer(ctx context.Context
 	return customer, err
 }
 
+// DeleteCustomer archive le client : ses commandes restent en base
 func (cr *CustomerRepoInfrastructurePostgres) DeleteCustomer(ctx context.Context, id string) error {
 	log.Printf("🔍 DeleteCustomer appelé avec ID: '%s'", id)
-	query := `DELETE FROM customers WHERE id=$1`
-	_, err := cr.execContext(ctx, query, id)
+	query := `UPDATE customers SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+	result, err := cr.execContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to archive customer: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to archive customer: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("customer %s: %w", id, sql.ErrNoRows)
+	}
+	return nil
+}
 
-	return err
+// RestoreCustomer annule l'archivage (sql.ErrNoRows si le client n'est pas archivé)
+func (cr *CustomerRepoInfrastructurePostgres) RestoreCustomer(ctx context.Context, id string) (*entity.Customer, error) {
+	query := `UPDATE customers
+	SET deleted_at = NULL, updated_at = NOW()
+	WHERE id = $1 AND deleted_at IS NOT NULL
+	RETURNING ` + customerColumns
+	return scanCustomer(cr.queryRowContext(ctx, query, id))
 }
 
 func (cr *CustomerRepoInfrastructurePostgres) FindByEmail(ctx context.Context, email string) (*entity.Customer, error) {
 	log.Printf("🔍 FindByEmail appelé avec email: '%s'", email)
 
-	customer := &entity.Customer{}
-	query := `SELECT id, first_name, last_name, email, created_at, updated_at FROM customers WHERE email = $1`
+	// Les clients archivés sont inclus : leur email reste réservé
+	query := `SELECT ` + customerColumns + ` FROM customers WHERE email = $1`
 
-	err := cr.queryRowContext(ctx, query, email).Scan(
-		&customer.ID,
-		&customer.FirstName,
-		&customer.LastName,
-		&customer.Email,
-		&customer.CreatedAt,
-		&customer.UpdatedAt,
-	)
+	customer, err := scanCustomer(cr.queryRowContext(ctx, query, email))
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -328,3 +360,18 @@ func (cr *CustomerRepoInfrastructurePostgres) FindByEmail(ctx context.Context, e
 	log.Printf("✅ Customer trouvé par email %s: ID=%s", email, customer.ID)
 	return customer, nil
 }
+
+// FindByUserID retourne le profil client rattaché à un compte utilisateur
+func (cr *CustomerRepoInfrastructurePostgres) FindByUserID(ctx context.Context, userID string) (*entity.Customer, error) {
+	query := `SELECT ` + customerColumns + ` FROM customers WHERE user_id = $1 AND deleted_at IS NULL`
+
+	customer, err := scanCustomer(cr.queryRowContext(ctx, query, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to find customer by user id: %w", err)
+	}
+
+	return customer, nil
+}
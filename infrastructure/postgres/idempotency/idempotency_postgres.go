@@ -0,0 +1,162 @@
+package idempotency
+
+import (
+	"Goshop/domain/entity"
+	"Goshop/domain/repository"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Nombre de tentatives de Reserve quand la clé disparaît entre l'INSERT et le SELECT
+const reserveAttempts = 3
+
+type IdempotencyPostgres struct {
+	db *sql.DB
+}
+
+func NewIdempotencyPostgres(db *sql.DB) repository.IdempotencyRepository {
+	return &IdempotencyPostgres{db: db}
+}
+
+func (ip *IdempotencyPostgres) Reserve(ctx context.Context, record *entity.IdempotencyRecord, lockTimeout time.Duration) (*entity.IdempotencyRecord, bool, error) {
+	// L'INSERT est atomique : de deux requêtes simultanées, une seule obtient la
+	// clé. Une clé expirée, ou restée IN_PROGRESS au-delà de lockTimeout (process
+	// tué en pleine requête), est reprise par le nouvel appelant.
+	query := `
+	INSERT INTO idempotency_keys (scope, idempotency_key, method, path, fingerprint, status, locked_at, created_at, expires_at)
+	VALUES ($1, $2, $3, $4, $5, 'IN_PROGRESS', NOW(), NOW(), $6)
+	ON CONFLICT (scope, idempotency_key) DO UPDATE
+	SET method = EXCLUDED.method,
+	    path = EXCLUDED.path,
+	    fingerprint = EXCLUDED.fingerprint,
+	    status = 'IN_PROGRESS',
+	    response_status = NULL,
+	    response_content_type = '',
+	    response_headers = NULL,
+	    response_body = NULL,
+	    locked_at = NOW(),
+	    created_at = NOW(),
+	    expires_at = EXCLUDED.expires_at
+	WHERE idempotency_keys.expires_at < NOW()
+	   OR (idempotency_keys.status = 'IN_PROGRESS'
+	       AND idempotency_keys.locked_at < NOW() - $7 * INTERVAL '1 millisecond')
+	RETURNING locked_at, created_at;`
+
+	for attempt := 0; attempt < reserveAttempts; attempt++ {
+		err := ip.db.QueryRowContext(ctx, query,
+			record.Scope, record.Key, record.Method, record.Path, record.Fingerprint,
+			record.ExpiresAt, lockTimeout.Milliseconds(),
+		).Scan(&record.LockedAt, &record.CreatedAt)
+		if err == nil {
+			record.Status = entity.IdempotencyStatusInProgress
+			return nil, true, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+		}
+
+		// La clé est détenue par une autre requête : on la relit
+		existing, err := ip.find(ctx, record.Scope, record.Key)
+		if err == nil {
+			return existing, false, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, false, err
+		}
+		// Libérée entre-temps (Release) : nouvelle tentative
+	}
+
+	return nil, false, fmt.Errorf("failed to reserve idempotency key %s after %d attempts", record.Key, reserveAttempts)
+}
+
+func (ip *IdempotencyPostgres) find(ctx context.Context, scope, key string) (*entity.IdempotencyRecord, error) {
+	query := `
+	SELECT scope, idempotency_key, method, path, fingerprint, status,
+	       COALESCE(response_status, 0), response_content_type, response_headers, response_body,
+	       locked_at, created_at, expires_at
+	FROM idempotency_keys
+	WHERE scope = $1 AND idempotency_key = $2;`
+
+	rec := &entity.IdempotencyRecord{}
+	var headers []byte
+	err := ip.db.QueryRowContext(ctx, query, scope, key).Scan(
+		&rec.Scope, &rec.Key, &rec.Method, &rec.Path, &rec.Fingerprint, &rec.Status,
+		&rec.ResponseStatus, &rec.ResponseContentType, &headers, &rec.ResponseBody,
+		&rec.LockedAt, &rec.CreatedAt, &rec.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("idempotency key %s not found: %w", key, sql.ErrNoRows)
+		}
+		return nil, fmt.Errorf("failed to read idempotency key: %w", err)
+	}
+	if len(headers) > 0 {
+		if err := json.Unmarshal(headers, &rec.ResponseHeaders); err != nil {
+			return nil, fmt.Errorf("failed to decode idempotent response headers: %w", err)
+		}
+	}
+
+	return rec, nil
+}
+
+func (ip *IdempotencyPostgres) Complete(ctx context.Context, record *entity.IdempotencyRecord) error {
+	query := `
+	UPDATE idempotency_keys
+	SET status = 'COMPLETED', response_status = $1, response_content_type = $2, response_headers = $3, response_body = $4
+	WHERE scope = $5 AND idempotency_key = $6 AND status = 'IN_PROGRESS';`
+
+	var headers []byte
+	if len(record.ResponseHeaders) > 0 {
+		var err error
+		if headers, err = json.Marshal(record.ResponseHeaders); err != nil {
+			return fmt.Errorf("failed to encode idempotent response headers: %w", err)
+		}
+	}
+
+	res, err := ip.db.ExecContext(ctx, query,
+		record.ResponseStatus, record.ResponseContentType, headers, record.ResponseBody,
+		record.Scope, record.Key,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("idempotency key %s is no longer in progress: %w", record.Key, sql.ErrNoRows)
+	}
+
+	record.Status = entity.IdempotencyStatusCompleted
+	return nil
+}
+
+func (ip *IdempotencyPostgres) Heartbeat(ctx context.Context, scope, key string) error {
+	query := `UPDATE idempotency_keys SET locked_at = NOW() WHERE scope = $1 AND idempotency_key = $2 AND status = 'IN_PROGRESS'`
+	res, err := ip.db.ExecContext(ctx, query, scope, key)
+	if err != nil {
+		return fmt.Errorf("failed to refresh idempotency key: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("idempotency key %s is no longer in progress: %w", key, sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (ip *IdempotencyPostgres) Release(ctx context.Context, scope, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE scope = $1 AND idempotency_key = $2 AND status = 'IN_PROGRESS'`
+	if _, err := ip.db.ExecContext(ctx, query, scope, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package idempotency_test
+
+import (
+	"Goshop/domain/entity"
+	"Goshop/infrastructure/postgres/idempotency"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRecord() *entity.IdempotencyRecord {
+	return &entity.IdempotencyRecord{
+		Key:         "key-1",
+		Scope:       "user-1",
+		Method:      "POST",
+		Path:        "/api/orders",
+		Fingerprint: "abc",
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+}
+
+func TestIdempotencyPostgres_Reserve_Created(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := idempotency.NewIdempotencyPostgres(db)
+	record := newRecord()
+
+	mock.ExpectQuery(`INSERT INTO idempotency_keys`).
+		WithArgs("user-1", "key-1", "POST", "/api/orders", "abc", record.ExpiresAt, int64(60000)).
+		WillReturnRows(sqlmock.NewRows([]string{"locked_at", "created_at"}).AddRow(time.Now(), time.Now()))
+
+	existing, created, err := repo.Reserve(context.Background(), record, time.Minute)
+
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Nil(t, existing)
+	assert.Equal(t, entity.IdempotencyStatusInProgress, record.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyPostgres_Reserve_ExistingKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := idempotency.NewIdempotencyPostgres(db)
+	record := newRecord()
+
+	// La clé est déjà détenue : l'INSERT ... ON CONFLICT ne renvoie rien
+	mock.ExpectQuery(`INSERT INTO idempotency_keys`).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`SELECT scope, idempotency_key`).
+		WithArgs("user-1", "key-1").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"scope", "idempotency_key", "method", "path", "fingerprint", "status",
+			"response_status", "response_content_type", "response_headers", "response_body",
+			"locked_at", "created_at", "expires_at",
+		}).AddRow("user-1", "key-1", "POST", "/api/orders", "abc", "COMPLETED",
+			201, "application/json", []byte(`{"Location":["/api/orders/order-1"]}`), []byte(`{"id":"order-1"}`),
+			time.Now(), time.Now(), record.ExpiresAt))
+
+	existing, created, err := repo.Reserve(context.Background(), record, time.Minute)
+
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.True(t, existing.IsCompleted())
+	assert.Equal(t, 201, existing.ResponseStatus)
+	assert.Equal(t, `{"id":"order-1"}`, string(existing.ResponseBody))
+	assert.Equal(t, []string{"/api/orders/order-1"}, existing.ResponseHeaders["Location"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyPostgres_Complete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := idempotency.NewIdempotencyPostgres(db)
+
+	record := newRecord()
+	record.ResponseStatus = 201
+	record.ResponseContentType = "application/json"
+	record.ResponseHeaders = map[string][]string{"Location": {"/api/orders/order-1"}}
+	record.ResponseBody = []byte(`{"id":"order-1"}`)
+
+	mock.ExpectExec(`UPDATE idempotency_keys`).
+		WithArgs(201, "application/json", []byte(`{"Location":["/api/orders/order-1"]}`), []byte(`{"id":"order-1"}`), "user-1", "key-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// Sans header à rejouer : colonne NULL
+	mock.ExpectExec(`UPDATE idempotency_keys`).
+		WithArgs(204, "", []byte(nil), []byte(nil), "user-1", "key-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.Complete(context.Background(), record))
+	assert.NoError(t, repo.Complete(context.Background(), &entity.IdempotencyRecord{Key: "key-1", Scope: "user-1", ResponseStatus: 204}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyPostgres_Complete_NotInProgress(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := idempotency.NewIdempotencyPostgres(db)
+
+	mock.ExpectExec(`UPDATE idempotency_keys`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Complete(context.Background(), newRecord())
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyPostgres_Heartbeat(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := idempotency.NewIdempotencyPostgres(db)
+
+	mock.ExpectExec(`UPDATE idempotency_keys SET locked_at = NOW\(\) WHERE scope = \$1 AND idempotency_key = \$2 AND status = 'IN_PROGRESS'`).
+		WithArgs("user-1", "key-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// Clé terminée ou libérée entre-temps
+	mock.ExpectExec(`UPDATE idempotency_keys SET locked_at`).
+		WithArgs("user-1", "key-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.Heartbeat(context.Background(), "user-1", "key-1"))
+	assert.ErrorIs(t, repo.Heartbeat(context.Background(), "user-1", "key-1"), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
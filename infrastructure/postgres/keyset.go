@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"fmt"
+	"slices"
+
+	paginationdto "Goshop/application/dto/pagination_dto"
+)
+
+// KeysetClause traduit une page par curseur en condition et ORDER BY sur
+// (created_at, id). `alias` préfixe les colonnes (ex. "o." pour "orders o"),
+// `argPos` est la position du premier paramètre ($n) disponible.
+//
+// En lecture arrière la requête remonte le temps (ORDER BY ASC) : les lignes
+// doivent ensuite être remises dans l'ordre d'affichage avec RestoreOrder.
+func KeysetClause(page paginationdto.CursorPage, alias string, argPos int) (condition, orderBy string, args []interface{}) {
+	comparator := "<"
+	if page.Backward {
+		comparator = ">"
+	}
+
+	orderBy = KeysetOrderBy(page, alias)
+
+	if page.After == nil {
+		return "", orderBy, nil
+	}
+
+	condition = fmt.Sprintf("(%[1]screated_at, %[1]sid) %[2]s ($%[3]d, $%[4]d)", alias, comparator, argPos, argPos+1)
+	return condition, orderBy, []interface{}{page.After.CreatedAt, page.After.ID}
+}
+
+// KeysetOrderBy renvoie l'ORDER BY correspondant au sens de lecture de la page
+func KeysetOrderBy(page paginationdto.CursorPage, alias string) string {
+	direction := "DESC"
+	if page.Backward {
+		direction = "ASC"
+	}
+	return fmt.Sprintf(" ORDER BY %[1]screated_at %[2]s, %[1]sid %[2]s", alias, direction)
+}
+
+// RestoreOrder remet les lignes d'une lecture arrière dans l'ordre d'affichage
+// (created_at DESC, id DESC).
+func RestoreOrder[T any](items []T, page paginationdto.CursorPage) {
+	if page.Backward {
+		slices.Reverse(items)
+	}
+}
@@ -0,0 +1,92 @@
+package loginattempt
+
+import (
+	authentity "Goshop/domain/auth_entity"
+	authrepository "Goshop/domain/repository/auth_repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// LoginAttemptPostgres : compteurs d'échecs de connexion en base, utilisés
+// quand Redis est absent ou indisponible. Les lignes expirées sont ignorées
+// en lecture et réinitialisées au prochain échec.
+type LoginAttemptPostgres struct {
+	db *sql.DB
+}
+
+func NewLoginAttemptPostgres(db *sql.DB) authrepository.LoginAttemptRepository {
+	return &LoginAttemptPostgres{db: db}
+}
+
+func (p *LoginAttemptPostgres) Get(ctx context.Context, key string) (authentity.LoginAttempts, error) {
+	query := `
+	SELECT failures, last_failure_at, locked_until
+	FROM login_attempts
+	WHERE key = $1 AND expires_at > NOW()
+	`
+	attempts, err := scanAttempts(p.db.QueryRowContext(ctx, query, key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return authentity.LoginAttempts{}, nil
+	}
+	if err != nil {
+		return authentity.LoginAttempts{}, fmt.Errorf("failed to load login attempts: %w", err)
+	}
+	return attempts, nil
+}
+
+// RegisterFailure : l'upsert est atomique, deux échecs simultanés comptent double
+func (p *LoginAttemptPostgres) RegisterFailure(ctx context.Context, key string, window time.Duration) (authentity.LoginAttempts, error) {
+	query := `
+	INSERT INTO login_attempts (key, failures, last_failure_at, expires_at)
+	VALUES ($1, 1, NOW(), NOW() + $2 * INTERVAL '1 millisecond')
+	ON CONFLICT (key) DO UPDATE
+	SET failures = CASE WHEN login_attempts.expires_at <= NOW() THEN 1 ELSE login_attempts.failures + 1 END,
+	    locked_until = CASE WHEN login_attempts.expires_at <= NOW() THEN NULL ELSE login_attempts.locked_until END,
+	    last_failure_at = NOW(),
+	    expires_at = GREATEST(EXCLUDED.expires_at, login_attempts.locked_until)
+	RETURNING failures, last_failure_at, locked_until
+	`
+	attempts, err := scanAttempts(p.db.QueryRowContext(ctx, query, key, window.Milliseconds()))
+	if err != nil {
+		return authentity.LoginAttempts{}, fmt.Errorf("failed to register login failure: %w", err)
+	}
+	return attempts, nil
+}
+
+func (p *LoginAttemptPostgres) Lock(ctx context.Context, key string, until time.Time) error {
+	query := `
+	INSERT INTO login_attempts (key, failures, last_failure_at, locked_until, expires_at)
+	VALUES ($1, 0, NOW(), $2, $2)
+	ON CONFLICT (key) DO UPDATE
+	SET locked_until = EXCLUDED.locked_until,
+	    expires_at = GREATEST(login_attempts.expires_at, EXCLUDED.locked_until)
+	`
+	if _, err := p.db.ExecContext(ctx, query, key, until); err != nil {
+		return fmt.Errorf("failed to lock login key: %w", err)
+	}
+	return nil
+}
+
+// Reset supprime aussi les lignes expirées pour borner la taille de la table
+func (p *LoginAttemptPostgres) Reset(ctx context.Context, key string) error {
+	query := `
+	DELETE FROM login_attempts WHERE key = $1 OR expires_at < NOW()
+	`
+	if _, err := p.db.ExecContext(ctx, query, key); err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+	return nil
+}
+
+func scanAttempts(row *sql.Row) (authentity.LoginAttempts, error) {
+	var attempts authentity.LoginAttempts
+	var lockedUntil sql.NullTime
+	if err := row.Scan(&attempts.Failures, &attempts.LastFailure, &lockedUntil); err != nil {
+		return authentity.LoginAttempts{}, err
+	}
+	attempts.LockedUntil = lockedUntil.Time
+	return attempts, nil
+}
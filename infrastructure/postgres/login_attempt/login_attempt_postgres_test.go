@@ -0,0 +1,71 @@
+package loginattempt_test
+
+import (
+	loginattempt "Goshop/infrastructure/postgres/login_attempt"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginAttemptPostgres_Get_NoRecentFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := loginattempt.NewLoginAttemptPostgres(db)
+
+	mock.ExpectQuery(`SELECT failures, last_failure_at, locked_until\s+FROM login_attempts`).
+		WithArgs("account:alice@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"failures", "last_failure_at", "locked_until"}))
+
+	attempts, err := repo.Get(context.Background(), "account:alice@example.com")
+
+	assert.NoError(t, err)
+	assert.Zero(t, attempts.Failures)
+	assert.True(t, attempts.LockedUntil.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginAttemptPostgres_RegisterFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := loginattempt.NewLoginAttemptPostgres(db)
+	last := time.Now()
+
+	mock.ExpectQuery(`INSERT INTO login_attempts`).
+		WithArgs("ip:203.0.113.7", int64(900000)).
+		WillReturnRows(sqlmock.NewRows([]string{"failures", "last_failure_at", "locked_until"}).AddRow(4, last, nil))
+
+	attempts, err := repo.RegisterFailure(context.Background(), "ip:203.0.113.7", 15*time.Minute)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, attempts.Failures)
+	assert.Equal(t, last, attempts.LastFailure)
+	assert.False(t, attempts.LockedAt(time.Now()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginAttemptPostgres_LockAndReset(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := loginattempt.NewLoginAttemptPostgres(db)
+	until := time.Now().Add(15 * time.Minute)
+
+	mock.ExpectExec(`INSERT INTO login_attempts .* ON CONFLICT \(key\) DO UPDATE\s+SET locked_until`).
+		WithArgs("account:alice@example.com", until).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM login_attempts WHERE key = \$1 OR expires_at < NOW\(\)`).
+		WithArgs("account:alice@example.com").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.Lock(context.Background(), "account:alice@example.com", until))
+	assert.NoError(t, repo.Reset(context.Background(), "account:alice@example.com"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,140 @@
+package mfa
+
+import (
+	authentity "Goshop/domain/auth_entity"
+	authrepository "Goshop/domain/repository/auth_repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type MFAPostgres struct {
+	db *sql.DB
+}
+
+func NewMFAPostgres(db *sql.DB) authrepository.MFARepository {
+	return &MFAPostgres{db: db}
+}
+
+func (p *MFAPostgres) Find(ctx context.Context, userID string) (*authentity.UserMFA, error) {
+	query := `
+	SELECT user_id, secret, enabled_at, last_used_step, created_at
+	FROM user_mfa
+	WHERE user_id = $1
+	`
+	var m authentity.UserMFA
+	var enabledAt sql.NullTime
+	err := p.db.QueryRowContext(ctx, query, userID).Scan(
+		&m.UserID, &m.Secret, &enabledAt, &m.LastUsedStep, &m.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, authrepository.ErrMFANotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find mfa: %w", err)
+	}
+	if enabledAt.Valid {
+		m.EnabledAt = enabledAt.Time
+	}
+	return &m, nil
+}
+
+// SavePending : la clause WHERE de l'upsert empêche de remplacer le secret
+// d'un 2FA actif (aucune ligne écrite)
+func (p *MFAPostgres) SavePending(ctx context.Context, userID, secret string) error {
+	query := `
+	INSERT INTO user_mfa (user_id, secret)
+	VALUES ($1, $2)
+	ON CONFLICT (user_id) DO UPDATE
+	SET secret = EXCLUDED.secret, last_used_step = 0, created_at = NOW()
+	WHERE user_mfa.enabled_at IS NULL
+	`
+	res, err := p.db.ExecContext(ctx, query, userID, secret)
+	if err != nil {
+		return fmt.Errorf("failed to save pending mfa: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return authrepository.ErrMFAAlreadyEnabled
+	}
+	return nil
+}
+
+// Enable : activation et nouveaux codes de récupération dans la même transaction
+func (p *MFAPostgres) Enable(ctx context.Context, userID string, step int64, recoveryCodeHashes []string) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin mfa activation: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+	UPDATE user_mfa SET enabled_at = NOW(), last_used_step = $2
+	WHERE user_id = $1 AND enabled_at IS NULL
+	`, userID, step)
+	if err != nil {
+		return fmt.Errorf("failed to enable mfa: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return authrepository.ErrMFANotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	for _, hash := range recoveryCodeHashes {
+		if _, err := tx.ExecContext(ctx, `
+		INSERT INTO mfa_recovery_codes (code_hash, user_id) VALUES ($1, $2)
+		`, hash, userID); err != nil {
+			return fmt.Errorf("failed to store recovery code: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UseStep : la condition last_used_step < $2 rend l'UPDATE atomique, un code
+// TOTP ne sert qu'une fois même sous requêtes concurrentes
+func (p *MFAPostgres) UseStep(ctx context.Context, userID string, step int64) error {
+	res, err := p.db.ExecContext(ctx, `
+	UPDATE user_mfa SET last_used_step = $2
+	WHERE user_id = $1 AND enabled_at IS NOT NULL AND last_used_step < $2
+	`, userID, step)
+	if err != nil {
+		return fmt.Errorf("failed to record totp step: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return authrepository.ErrMFAStepReused
+	}
+	return nil
+}
+
+func (p *MFAPostgres) ConsumeRecoveryCode(ctx context.Context, userID, codeHash string) error {
+	res, err := p.db.ExecContext(ctx, `
+	UPDATE mfa_recovery_codes SET used_at = NOW()
+	WHERE code_hash = $1 AND user_id = $2 AND used_at IS NULL
+	`, codeHash, userID)
+	if err != nil {
+		return fmt.Errorf("failed to consume recovery code: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return authrepository.ErrRecoveryCodeInvalid
+	}
+	return nil
+}
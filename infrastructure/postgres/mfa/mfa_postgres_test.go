@@ -0,0 +1,153 @@
+package mfa_test
+
+import (
+	authrepository "Goshop/domain/repository/auth_repository"
+	"Goshop/infrastructure/postgres/mfa"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMFAPostgres_Find(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := mfa.NewMFAPostgres(db)
+	created := time.Now()
+
+	mock.ExpectQuery(`SELECT user_id, secret, enabled_at, last_used_step, created_at\s+FROM user_mfa`).
+		WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "secret", "enabled_at", "last_used_step", "created_at"}).
+			AddRow("user-1", "JBSWY3DPEHPK3PXP", nil, 0, created))
+
+	m, err := repo.Find(context.Background(), "user-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", m.Secret)
+	assert.False(t, m.Enabled())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMFAPostgres_Find_NotEnrolled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM user_mfa`).WithArgs("user-1").WillReturnError(sql.ErrNoRows)
+
+	_, err = mfa.NewMFAPostgres(db).Find(context.Background(), "user-1")
+
+	assert.ErrorIs(t, err, authrepository.ErrMFANotFound)
+}
+
+func TestMFAPostgres_SavePending_AlreadyEnabled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(`INSERT INTO user_mfa .+ON CONFLICT \(user_id\) DO UPDATE.+WHERE user_mfa.enabled_at IS NULL`).
+		WithArgs("user-1", "JBSWY3DPEHPK3PXP").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = mfa.NewMFAPostgres(db).SavePending(context.Background(), "user-1", "JBSWY3DPEHPK3PXP")
+
+	assert.ErrorIs(t, err, authrepository.ErrMFAAlreadyEnabled)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMFAPostgres_Enable(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE user_mfa SET enabled_at = NOW\(\), last_used_step = \$2\s+WHERE user_id = \$1 AND enabled_at IS NULL`).
+		WithArgs("user-1", int64(57)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM mfa_recovery_codes WHERE user_id = \$1`).
+		WithArgs("user-1").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`INSERT INTO mfa_recovery_codes`).WithArgs("hash-1", "user-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO mfa_recovery_codes`).WithArgs("hash-2", "user-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = mfa.NewMFAPostgres(db).Enable(context.Background(), "user-1", 57, []string{"hash-1", "hash-2"})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMFAPostgres_Enable_NoPendingEnrolment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE user_mfa SET enabled_at`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = mfa.NewMFAPostgres(db).Enable(context.Background(), "user-1", 57, []string{"hash-1"})
+
+	assert.ErrorIs(t, err, authrepository.ErrMFANotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMFAPostgres_UseStep(t *testing.T) {
+	tests := []struct {
+		name    string
+		rows    int64
+		wantErr error
+	}{
+		{"nouveau pas de temps", 1, nil},
+		{"pas déjà utilisé", 0, authrepository.ErrMFAStepReused},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectExec(`UPDATE user_mfa SET last_used_step = \$2\s+WHERE user_id = \$1 AND enabled_at IS NOT NULL AND last_used_step < \$2`).
+				WithArgs("user-1", int64(58)).
+				WillReturnResult(sqlmock.NewResult(0, tt.rows))
+
+			err = mfa.NewMFAPostgres(db).UseStep(context.Background(), "user-1", 58)
+
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMFAPostgres_ConsumeRecoveryCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := mfa.NewMFAPostgres(db)
+
+	mock.ExpectExec(`UPDATE mfa_recovery_codes SET used_at = NOW\(\)\s+WHERE code_hash = \$1 AND user_id = \$2 AND used_at IS NULL`).
+		WithArgs("hash-1", "user-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE mfa_recovery_codes`).
+		WithArgs("hash-1", "user-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE mfa_recovery_codes`).
+		WithArgs("hash-2", "user-1").
+		WillReturnError(errors.New("connection reset"))
+
+	assert.NoError(t, repo.ConsumeRecoveryCode(context.Background(), "user-1", "hash-1"))
+	assert.ErrorIs(t, repo.ConsumeRecoveryCode(context.Background(), "user-1", "hash-1"), authrepository.ErrRecoveryCodeInvalid)
+	assert.Error(t, repo.ConsumeRecoveryCode(context.Background(), "user-1", "hash-2"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,55 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var nonIdentifierChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Create écrit une paire de fichiers up/down vides dans dir, avec le numéro de
+// version suivant. Renvoie les chemins créés.
+func Create(dir, name string) (upPath, downPath string, err error) {
+	name = strings.Trim(nonIdentifierChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", "", fmt.Errorf("migration name must contain letters or digits")
+	}
+
+	existing, err := Load(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+
+	var version int64 = 1
+	if len(existing) > 0 {
+		version = existing[len(existing)-1].Version + 1
+	}
+
+	base := fmt.Sprintf("%03d_%s", version, name)
+	upPath = filepath.Join(dir, base+".up.sql")
+	downPath = filepath.Join(dir, base+".down.sql")
+
+	files := map[string]string{
+		upPath:   fmt.Sprintf("-- migrations/%s.up.sql\n\n", base),
+		downPath: fmt.Sprintf("-- migrations/%s.down.sql\n\n", base),
+	}
+	for path, content := range files {
+		// O_EXCL : ne jamais écraser une migration existante
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to create %s: %w", path, err)
+		}
+		if _, err := f.WriteString(content); err != nil {
+			f.Close()
+			return "", "", fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		if err := f.Close(); err != nil {
+			return "", "", err
+		}
+	}
+
+	return upPath, downPath, nil
+}
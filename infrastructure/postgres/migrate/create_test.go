@@ -0,0 +1,44 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreate_NextVersion(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "001_init.up.sql"), []byte("SELECT 1;"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "002_orders.up.sql"), []byte("SELECT 1;"), 0o644))
+
+	upPath, downPath, err := Create(dir, "Add Product Categories!")
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "003_add_product_categories.up.sql"), upPath)
+	assert.Equal(t, filepath.Join(dir, "003_add_product_categories.down.sql"), downPath)
+
+	content, err := os.ReadFile(upPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "-- migrations/003_add_product_categories.up.sql")
+
+	// Les nouveaux fichiers sont chargeables
+	migrations, err := Load(os.DirFS(dir))
+	require.NoError(t, err)
+	assert.Len(t, migrations, 3)
+}
+
+func TestCreate_EmptyDirectoryStartsAtOne(t *testing.T) {
+	upPath, _, err := Create(t.TempDir(), "init")
+
+	require.NoError(t, err)
+	assert.Equal(t, "001_init.up.sql", filepath.Base(upPath))
+}
+
+func TestCreate_InvalidName(t *testing.T) {
+	_, _, err := Create(t.TempDir(), "  --- ")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,347 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// advisoryLockID identifie le verrou pg_advisory_lock partagé par tous les
+// process de migration (pods du Job k8s, tests, CLI)
+const advisoryLockID int64 = 7_310_245_118
+
+const createSchemaMigrationsTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name TEXT NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);`
+
+// États renvoyés par Status
+const (
+	StateApplied  = "applied"
+	StatePending  = "pending"
+	StateModified = "modified" // fichier up modifié après application
+	StateMissing  = "missing"  // appliquée en base mais absente des fichiers
+)
+
+var (
+	ErrChecksumMismatch = errors.New("applied migration has been modified")
+	ErrMissingMigration = errors.New("applied migration not found in migration files")
+	ErrIrreversible     = errors.New("migration has no down script")
+)
+
+// MigrationStatus décrit l'état d'une version du schéma
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	State     string
+	AppliedAt *time.Time
+}
+
+type appliedMigration struct {
+	Version   int64
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New charge les migrations de fsys (en général migrations.FS)
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Migrations renvoie les migrations connues, triées par version
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Up applique toutes les migrations en attente, dans l'ordre des versions
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.verifiedApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := m.runUp(ctx, conn, mig); err != nil {
+				return err
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down annule les n dernières migrations appliquées
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("down: n must be at least 1, got %d", n)
+	}
+
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		targets, err := m.lastApplied(ctx, conn, n)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range targets {
+			if err := m.runDown(ctx, conn, mig); err != nil {
+				return err
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Redo annule puis réapplique la dernière migration appliquée
+func (m *Migrator) Redo(ctx context.Context) (*Migration, error) {
+	var redone *Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		targets, err := m.lastApplied(ctx, conn, 1)
+		if err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			return nil
+		}
+
+		mig := targets[0]
+		if err := m.runDown(ctx, conn, mig); err != nil {
+			return err
+		}
+		if err := m.runUp(ctx, conn, mig); err != nil {
+			return err
+		}
+		redone = &mig
+		return nil
+	})
+	return redone, err
+}
+
+// Status renvoie l'état de chaque migration, fichiers et base confondus
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if _, err := m.db.ExecContext(ctx, createSchemaMigrationsTable); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	applied, err := m.applied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	known := map[int64]bool{}
+	for _, mig := range m.migrations {
+		known[mig.Version] = true
+		status := MigrationStatus{Version: mig.Version, Name: mig.Name, State: StatePending}
+		if a, ok := applied[mig.Version]; ok {
+			appliedAt := a.AppliedAt
+			status.AppliedAt = &appliedAt
+			status.State = StateApplied
+			if a.Checksum != mig.Checksum {
+				status.State = StateModified
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	for version, a := range applied {
+		if known[version] {
+			continue
+		}
+		appliedAt := a.AppliedAt
+		statuses = append(statuses, MigrationStatus{
+			Version:   version,
+			Name:      a.Name,
+			State:     StateMissing,
+			AppliedAt: &appliedAt,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+// withLock exécute fn sur une connexion dédiée qui détient le verrou consultatif :
+// deux process lancés en même temps s'exécutent l'un après l'autre.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	logger := zerolog.Ctx(ctx)
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	start := time.Now()
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, advisoryLockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, advisoryLockID); err != nil {
+			logger.Error().Err(err).Msg("Failed to release migration lock")
+		}
+	}()
+
+	logger.Debug().
+		Dur("wait_duration_ms", time.Since(start)).
+		Msg("Migration lock acquired")
+
+	if _, err := conn.ExecContext(ctx, createSchemaMigrationsTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *Migrator) applied(ctx context.Context, q queryer) (map[int64]appliedMigration, error) {
+	rows, err := q.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[int64]appliedMigration{}
+	for rows.Next() {
+		var a appliedMigration
+		if err := rows.Scan(&a.Version, &a.Name, &a.Checksum, &a.AppliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations: %w", err)
+		}
+		applied[a.Version] = a
+	}
+	return applied, rows.Err()
+}
+
+// verifiedApplied refuse de continuer si un script déjà appliqué a changé
+func (m *Migrator) verifiedApplied(ctx context.Context, conn *sql.Conn) (map[int64]appliedMigration, error) {
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, mig := range m.migrations {
+		if a, ok := applied[mig.Version]; ok && a.Checksum != mig.Checksum {
+			return nil, fmt.Errorf("%s: %w (applied %s, file %s)", mig, ErrChecksumMismatch, a.Checksum[:12], mig.Checksum[:12])
+		}
+	}
+	return applied, nil
+}
+
+// lastApplied renvoie les n dernières migrations appliquées, de la plus récente à la plus ancienne
+func (m *Migrator) lastApplied(ctx context.Context, conn *sql.Conn, n int) ([]Migration, error) {
+	applied, err := m.verifiedApplied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]int64, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+	if len(versions) > n {
+		versions = versions[:n]
+	}
+
+	byVersion := make(map[int64]Migration, len(m.migrations))
+	for _, mig := range m.migrations {
+		byVersion[mig.Version] = mig
+	}
+
+	targets := make([]Migration, 0, len(versions))
+	for _, version := range versions {
+		mig, ok := byVersion[version]
+		if !ok {
+			return nil, fmt.Errorf("version %d (%s): %w", version, applied[version].Name, ErrMissingMigration)
+		}
+		if !mig.HasDown() {
+			return nil, fmt.Errorf("%s: %w", mig, ErrIrreversible)
+		}
+		targets = append(targets, mig)
+	}
+	return targets, nil
+}
+
+func (m *Migrator) runUp(ctx context.Context, conn *sql.Conn, mig Migration) error {
+	return m.run(ctx, conn, mig, "up", mig.UpSQL,
+		`INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+		mig.Version, mig.Name, mig.Checksum)
+}
+
+func (m *Migrator) runDown(ctx context.Context, conn *sql.Conn, mig Migration) error {
+	return m.run(ctx, conn, mig, "down", mig.DownSQL,
+		`DELETE FROM schema_migrations WHERE version = $1`,
+		mig.Version)
+}
+
+// run exécute le script puis met à jour schema_migrations, dans une même
+// transaction sauf si le script porte la directive no-transaction
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, mig Migration, direction, script, bookkeeping string, args ...interface{}) error {
+	logger := zerolog.Ctx(ctx)
+	start := time.Now()
+
+	if usesTransaction(script) {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("%s %s: failed to begin transaction: %w", direction, mig, err)
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.ExecContext(ctx, script); err != nil {
+			return fmt.Errorf("%s %s: %w", direction, mig, err)
+		}
+		if _, err := tx.ExecContext(ctx, bookkeeping, args...); err != nil {
+			return fmt.Errorf("%s %s: failed to update schema_migrations: %w", direction, mig, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("%s %s: failed to commit: %w", direction, mig, err)
+		}
+	} else {
+		if _, err := conn.ExecContext(ctx, script); err != nil {
+			return fmt.Errorf("%s %s: %w", direction, mig, err)
+		}
+		if _, err := conn.ExecContext(ctx, bookkeeping, args...); err != nil {
+			return fmt.Errorf("%s %s: failed to update schema_migrations: %w", direction, mig, err)
+		}
+	}
+
+	logger.Info().
+		Int64("version", mig.Version).
+		Str("name", mig.Name).
+		Str("direction", direction).
+		Dur("duration_ms", time.Since(start)).
+		Msg("Migration applied")
+	return nil
+}
@@ -0,0 +1,176 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFS = fstest.MapFS{
+	"001_init.up.sql":       {Data: []byte("CREATE TABLE users ();")},
+	"001_init.down.sql":     {Data: []byte("DROP TABLE users;")},
+	"002_orders.up.sql":     {Data: []byte("CREATE TABLE orders ();")},
+	"002_orders.down.sql":   {Data: []byte("DROP TABLE orders;")},
+	"003_no_down.up.sql":    {Data: []byte("CREATE TABLE logs ();")},
+	"notes_not_a_migration": {Data: []byte("ignored")},
+}
+
+func newTestMigrator(t *testing.T) (*Migrator, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := New(db, testFS)
+	require.NoError(t, err)
+	return migrator, mock
+}
+
+func expectLock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_lock($1)`)).
+		WithArgs(advisoryLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock($1)`)).
+		WithArgs(advisoryLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func appliedRows(m *Migrator, versions ...int64) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"version", "name", "checksum", "applied_at"})
+	for _, mig := range m.Migrations() {
+		for _, v := range versions {
+			if mig.Version == v {
+				rows.AddRow(mig.Version, mig.Name, mig.Checksum, time.Now())
+			}
+		}
+	}
+	return rows
+}
+
+func TestMigrator_Up_AppliesPendingInOrder(t *testing.T) {
+	migrator, mock := newTestMigrator(t)
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, name, checksum, applied_at FROM schema_migrations`).
+		WillReturnRows(appliedRows(migrator, 1))
+
+	for _, mig := range migrator.Migrations()[1:] {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(mig.UpSQL)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO schema_migrations`).
+			WithArgs(mig.Version, mig.Name, mig.Checksum).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+	expectUnlock(mock)
+
+	applied, err := migrator.Up(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, applied, 2)
+	assert.Equal(t, int64(2), applied[0].Version)
+	assert.Equal(t, int64(3), applied[1].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Up_RefusesModifiedMigration(t *testing.T) {
+	migrator, mock := newTestMigrator(t)
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, name, checksum, applied_at FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "name", "checksum", "applied_at"}).
+			AddRow(1, "init", "0000000000000000000000000000000000000000000000000000000000000000", time.Now()))
+	expectUnlock(mock)
+
+	applied, err := migrator.Up(context.Background())
+
+	assert.True(t, errors.Is(err, ErrChecksumMismatch))
+	assert.Empty(t, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Up_FailedScriptRollsBack(t *testing.T) {
+	migrator, mock := newTestMigrator(t)
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, name, checksum, applied_at FROM schema_migrations`).
+		WillReturnRows(appliedRows(migrator))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE users ();")).WillReturnError(errors.New("syntax error"))
+	mock.ExpectRollback()
+	expectUnlock(mock)
+
+	applied, err := migrator.Up(context.Background())
+
+	assert.ErrorContains(t, err, "up 001_init: syntax error")
+	assert.Empty(t, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down_RevertsMostRecentFirst(t *testing.T) {
+	migrator, mock := newTestMigrator(t)
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, name, checksum, applied_at FROM schema_migrations`).
+		WillReturnRows(appliedRows(migrator, 1, 2))
+	for _, v := range []int64{2, 1} {
+		mig := migrator.Migrations()[v-1]
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(mig.DownSQL)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DELETE FROM schema_migrations`).WithArgs(v).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+	expectUnlock(mock)
+
+	reverted, err := migrator.Down(context.Background(), 5)
+
+	require.NoError(t, err)
+	require.Len(t, reverted, 2)
+	assert.Equal(t, int64(2), reverted[0].Version)
+	assert.Equal(t, int64(1), reverted[1].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down_IrreversibleMigration(t *testing.T) {
+	migrator, mock := newTestMigrator(t)
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, name, checksum, applied_at FROM schema_migrations`).
+		WillReturnRows(appliedRows(migrator, 1, 2, 3))
+	expectUnlock(mock)
+
+	_, err := migrator.Down(context.Background(), 1)
+
+	assert.ErrorIs(t, err, ErrIrreversible)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Status(t *testing.T) {
+	migrator, mock := newTestMigrator(t)
+
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := appliedRows(migrator, 1).
+		AddRow(2, "orders", "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", time.Now()).
+		AddRow(9, "removed", "abc", time.Now())
+	mock.ExpectQuery(`SELECT version, name, checksum, applied_at FROM schema_migrations`).WillReturnRows(rows)
+
+	statuses, err := migrator.Status(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, statuses, 4)
+	assert.Equal(t, StateApplied, statuses[0].State)
+	assert.Equal(t, StateModified, statuses[1].State)
+	assert.Equal(t, StatePending, statuses[2].State)
+	assert.Nil(t, statuses[2].AppliedAt)
+	assert.Equal(t, StateMissing, statuses[3].State)
+	assert.Equal(t, "removed", statuses[3].Name)
+}
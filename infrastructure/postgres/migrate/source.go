@@ -0,0 +1,120 @@
+// Package migrate applique les migrations SQL versionnées (migrations/*.sql)
+// et trace leur état dans la table schema_migrations.
+package migrate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// noTransactionDirective désactive la transaction pour une migration
+// (ex : CREATE INDEX CONCURRENTLY). À placer dans les commentaires d'en-tête.
+const noTransactionDirective = "-- migrate:no-transaction"
+
+// NNN_description.up.sql ou NNN_description.down.sql
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration est une version du schéma avec ses scripts up et down
+type Migration struct {
+	Version  int64
+	Name     string
+	UpSQL    string
+	DownSQL  string
+	Checksum string // sha256 du script up
+}
+
+// HasDown indique si la migration peut être annulée
+func (m Migration) HasDown() bool {
+	return strings.TrimSpace(m.DownSQL) != ""
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%03d_%s", m.Version, m.Name)
+}
+
+func usesTransaction(script string) bool {
+	for _, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "--") {
+			return true // fin de l'en-tête
+		}
+		if line == noTransactionDirective {
+			return false
+		}
+	}
+	return true
+}
+
+// Load lit les migrations d'un fs.FS (embed.FS en production, MapFS en test)
+// et les renvoie triées par version. Les fichiers qui ne suivent pas la
+// convention de nommage sont ignorés.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+		name, direction := match[2], match[3]
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration version %d used by both %q and %q", version, m.Name, name)
+		}
+
+		if direction == "up" {
+			m.UpSQL = string(content)
+			m.Checksum = checksum(content)
+		} else {
+			m.DownSQL = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.UpSQL == "" {
+			return nil, fmt.Errorf("migration %s has no up script", m)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,86 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"Goshop/migrations"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_SortsAndPairsFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"002_add_orders.up.sql":   {Data: []byte("CREATE TABLE orders ();")},
+		"002_add_orders.down.sql": {Data: []byte("DROP TABLE orders;")},
+		"001_init.up.sql":         {Data: []byte("CREATE TABLE users ();")},
+		"010_seed.up.sql":         {Data: []byte("INSERT INTO users DEFAULT VALUES;")},
+		"migrations.go":           {Data: []byte("package migrations")},
+		"README.md":               {Data: []byte("ignored")},
+	}
+
+	migrations, err := Load(fsys)
+
+	require.NoError(t, err)
+	require.Len(t, migrations, 3)
+	assert.Equal(t, []int64{1, 2, 10}, []int64{migrations[0].Version, migrations[1].Version, migrations[2].Version})
+	assert.Equal(t, "add_orders", migrations[1].Name)
+	assert.Equal(t, "DROP TABLE orders;", migrations[1].DownSQL)
+	assert.True(t, migrations[1].HasDown())
+	assert.False(t, migrations[2].HasDown())
+	assert.Equal(t, "002_add_orders", migrations[1].String())
+}
+
+func TestLoad_ChecksumTracksUpScript(t *testing.T) {
+	load := func(up, down string) Migration {
+		migrations, err := Load(fstest.MapFS{
+			"001_init.up.sql":   {Data: []byte(up)},
+			"001_init.down.sql": {Data: []byte(down)},
+		})
+		require.NoError(t, err)
+		return migrations[0]
+	}
+
+	original := load("CREATE TABLE users ();", "DROP TABLE users;")
+
+	assert.Len(t, original.Checksum, 64)
+	assert.Equal(t, original.Checksum, load("CREATE TABLE users ();", "-- autre down").Checksum)
+	assert.NotEqual(t, original.Checksum, load("CREATE TABLE users (id INT);", "DROP TABLE users;").Checksum)
+}
+
+func TestLoad_MissingUpScript(t *testing.T) {
+	_, err := Load(fstest.MapFS{
+		"001_init.down.sql": {Data: []byte("DROP TABLE users;")},
+	})
+
+	assert.ErrorContains(t, err, "has no up script")
+}
+
+func TestLoad_DuplicateVersion(t *testing.T) {
+	_, err := Load(fstest.MapFS{
+		"003_products.up.sql":  {Data: []byte("SELECT 1;")},
+		"003_customers.up.sql": {Data: []byte("SELECT 1;")},
+	})
+
+	assert.ErrorContains(t, err, "migration version 3 used by both")
+}
+
+func TestUsesTransaction(t *testing.T) {
+	assert.True(t, usesTransaction("-- migrations/004_x.up.sql\n\nCREATE INDEX idx ON t(c);"))
+	assert.False(t, usesTransaction("-- migrations/004_x.up.sql\n-- migrate:no-transaction\n\nCREATE INDEX CONCURRENTLY idx ON t(c);"))
+	// La directive n'est lue que dans l'en-tête
+	assert.True(t, usesTransaction("SELECT 1;\n-- migrate:no-transaction\n"))
+}
+
+// Les migrations du dépôt doivent toutes se charger et être réversibles
+func TestLoad_EmbeddedMigrations(t *testing.T) {
+	loaded, err := Load(migrations.FS)
+
+	require.NoError(t, err)
+	require.NotEmpty(t, loaded)
+	for i, m := range loaded {
+		assert.Equal(t, int64(i+1), m.Version, "versions contiguës")
+		assert.True(t, m.HasDown(), "%s doit avoir un script down", m)
+	}
+}
@@ -44,14 +44,16 @@ func (ori *OrderItemPostgresInfra) execContext(ctx context.Context, query string
 // ✅ Create un article de commande
 func (ori *OrderItemPostgresInfra) Create(ctx context.Context, orderItem *entity.OrderItem) (*entity.OrderItem, error) {
 	query := `
-	INSERT INTO order_items (order_id, product_id, quantity, price_cents, subtotal_cents)
-	VALUES ($1, $2, $3, $4, $5)
+	INSERT INTO order_items (order_id, product_id, variant_id, quantity, price_cents, subtotal_cents)
+	VALUES ($1, $2, $3, $4, $5, $6)
 	RETURNING id, order_id, product_id, quantity, price_cents, subtotal_cents
 	`
 
+	variantID := sql.NullString{String: orderItem.VariantID, Valid: orderItem.VariantID != ""}
 	err := ori.queryRowContext(ctx, query,
 		orderItem.OrderID,
 		orderItem.ProductID,
+		variantID,
 		orderItem.Quantity,
 		orderItem.PriceCents,
 		orderItem.SubTotal_Cents,
@@ -73,17 +75,19 @@ func (ori *OrderItemPostgresInfra) Create(ctx context.Context, orderItem *entity
 // ✅ Trouver un article par son ID
 func (ori *OrderItemPostgresInfra) FindByID(ctx context.Context, id string) (*entity.OrderItem, error) {
 	query := `
-	SELECT id, order_id, product_id, quantity, price_cents, subtotal_cents
+	SELECT id, order_id, product_id, variant_id, quantity, price_cents, subtotal_cents
 	FROM order_items
 	WHERE id = $1
 	`
 
 	orderItem := &entity.OrderItem{}
+	var variantID sql.NullString
 
 	err := ori.queryRowContext(ctx, query, id).Scan(
 		&orderItem.ID,
 		&orderItem.OrderID,
 		&orderItem.ProductID,
+		&variantID,
 		&orderItem.Quantity,
 		&orderItem.PriceCents,
 		&orderItem.SubTotal_Cents,
@@ -94,6 +98,7 @@ func (ori *OrderItemPostgresInfra) FindByID(ctx context.Context, id string) (*en
 	if err != nil {
 		return nil, fmt.Errorf("failed to find order item: %w", err)
 	}
+	orderItem.VariantID = variantID.String
 
 	return orderItem, nil
 }
@@ -101,7 +106,7 @@ func (ori *OrderItemPostgresInfra) FindByID(ctx context.Context, id string) (*en
 // ✅ Récupérer tous les articles
 func (ori *OrderItemPostgresInfra) FindAll(ctx context.Context) ([]*entity.OrderItem, error) {
 	query := `
-	SELECT id, order_id, product_id, quantity, price_cents, subtotal_cents
+	SELECT id, order_id, product_id, variant_id, quantity, price_cents, subtotal_cents
 	FROM order_items
 	ORDER BY order_id DESC
 	`
@@ -116,16 +121,19 @@ func (ori *OrderItemPostgresInfra) FindAll(ctx context.Context) ([]*entity.Order
 
 	for rows.Next() {
 		orderItem := &entity.OrderItem{}
+		var variantID sql.NullString
 		if err := rows.Scan(
 			&orderItem.ID,
 			&orderItem.OrderID,
 			&orderItem.ProductID,
+			&variantID,
 			&orderItem.Quantity,
 			&orderItem.PriceCents,
 			&orderItem.SubTotal_Cents,
 		); err != nil {
 			return nil, err
 		}
+		orderItem.VariantID = variantID.String
 		orderItems = append(orderItems, orderItem)
 	}
 
@@ -2,8 +2,10 @@ package order
 
 import (
 	orderdto "Goshop/application/dto/order_dto"
+	paginationdto "Goshop/application/dto/pagination_dto"
 	"Goshop/domain/entity"
 	"Goshop/domain/repository"
+	"Goshop/infrastructure/postgres"
 	"context"
 	"database/sql"
 	"fmt"
@@ -37,8 +39,8 @@ func (or *OrderPostgresInfra) CountByCustomerID(ctx context.Context, customerID
 	return count, nil
 }
 
-func (or *OrderPostgresInfra) CountAll(ctx context.Context, filter orderdto.OrderFilter) (int, error) {
-	baseQuery := `SELECT COUNT(DISTINCT o.id) FROM orders o`
+// buildOrderWhere construit la clause WHERE (colonnes préfixées par "o.")
+func buildOrderWhere(filter orderdto.OrderFilter) (string, []interface{}) {
 	conditions := []string{}
 	args := []interface{}{}
 	argPos := 1
@@ -52,15 +54,17 @@ func (or *OrderPostgresInfra) CountAll(ctx context.Context, filter orderdto.Orde
 	if filter.CustomerID != nil {
 		conditions = append(conditions, fmt.Sprintf("o.customer_id = $%d", argPos))
 		args = append(args, *filter.CustomerID)
-		argPos++
 	}
 
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = " WHERE " + strings.Join(conditions, " AND ")
+	if len(conditions) == 0 {
+		return "", args
 	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
 
-	query := baseQuery + whereClause
+func (or *OrderPostgresInfra) CountAll(ctx context.Context, filter orderdto.OrderFilter) (int, error) {
+	whereClause, args := buildOrderWhere(filter)
+	query := `SELECT COUNT(DISTINCT o.id) FROM orders o` + whereClause
 
 	var total int
 	err := or.queryRowContext(ctx, query, args...).Scan(&total)
@@ -71,61 +75,64 @@ func (or *OrderPostgresInfra) CountAll(ctx context.Context, filter orderdto.Orde
 	return total, nil
 }
 
-func (or *OrderPostgresInfra) FindAllWithPagination(ctx context.Context, limit, offset int, filter orderdto.OrderFilter) ([]*entity.Order, error) {
-	// Base query
-	baseQuery := `
-		SELECT 
-			o.id AS order_id,
-			o.customer_id,
-			o.total_cents,
-			o.status,
-			o.created_at,
-			o.updated_at,
+func (or *OrderPostgresInfra) FindAllByCursor(ctx context.Context, page paginationdto.CursorPage, filter orderdto.OrderFilter) ([]*entity.Order, error) {
+	whereClause, args := buildOrderWhere(filter)
+
+	keyset, orderBy, keysetArgs := postgres.KeysetClause(page, "o.", len(args)+1)
+	if keyset != "" {
+		if whereClause == "" {
+			whereClause = " WHERE " + keyset
+		} else {
+			whereClause += " AND " + keyset
+		}
+		args = append(args, keysetArgs...)
+	}
+
+	// La limite s'applique aux commandes (CTE), pas aux lignes de la jointure
+	query := `
+		WITH p AS (
+			SELECT o.id, o.customer_id, o.total_cents, o.status, o.created_at, o.updated_at
+			FROM orders o` + whereClause + orderBy + `
+			LIMIT $` + strconv.Itoa(len(args)+1) + `
+		)
+		SELECT
+			p.id AS order_id,
+			p.customer_id,
+			p.total_cents,
+			p.status,
+			p.created_at,
+			p.updated_at,
 			oi.id AS item_id,
 			oi.product_id,
+			oi.variant_id,
 			oi.quantity,
 			oi.price_cents,
 			oi.subtotal_cents
-		FROM orders o
-		LEFT JOIN order_items oi ON o.id = oi.order_id
-	`
-
-	// Conditions dynamiques
-	conditions := []string{}
-	args := []interface{}{}
-	argPos := 1 // position des $1, $2, etc.
-
-	if filter.Status != nil {
-		conditions = append(conditions, fmt.Sprintf("o.status = $%d", argPos))
-		args = append(args, *filter.Status)
-		argPos++
-	}
+		FROM p
+		LEFT JOIN order_items oi ON p.id = oi.order_id` + postgres.KeysetOrderBy(page, "p.") + `, oi.id`
+	args = append(args, page.Limit)
 
-	if filter.CustomerID != nil {
-		conditions = append(conditions, fmt.Sprintf("o.customer_id = $%d", argPos))
-		args = append(args, *filter.CustomerID)
-		argPos++
+	rows, err := or.queryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch orders page: %w", err)
 	}
+	defer rows.Close()
 
-	// Construire la clause WHERE
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = " WHERE " + strings.Join(conditions, " AND ")
+	orders, err := scanOrdersWithItems(rows)
+	if err != nil {
+		return nil, err
 	}
 
-	// Ajouter ORDER + LIMIT/OFFSET
-	query := baseQuery + whereClause + " ORDER BY o.created_at DESC LIMIT $" + strconv.Itoa(argPos) + " OFFSET $" + strconv.Itoa(argPos+1)
-	args = append(args, limit, offset)
+	postgres.RestoreOrder(orders, page)
+	return orders, nil
+}
 
-	// Exécuter
-	rows, err := or.queryContext(ctx, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch paginated orders: %w", err)
-	}
-	defer rows.Close()
+// scanOrdersWithItems regroupe les lignes orders ⟕ order_items par commande
+// en conservant l'ordre de la requête.
+func scanOrdersWithItems(rows *sql.Rows) ([]*entity.Order, error) {
+	orders := []*entity.Order{}
+	byID := make(map[string]*entity.Order)
 
-	// Mapper les résultats (inchangé)
-	orderMap := make(map[string]*entity.Order)
 	for rows.Next() {
 		var (
 			orderID       string
@@ -136,6 +143,7 @@ func (or *OrderPostgresInfra) FindAllWithPagination(ctx context.Context, limit,
 			updatedAt     time.Time
 			itemID        sql.NullString
 			productID     sql.NullString
+			variantID     sql.NullString
 			quantity      sql.NullInt64
 			priceCents    sql.NullInt64
 			subTotalCents sql.NullInt64
@@ -150,6 +158,7 @@ func (or *OrderPostgresInfra) FindAllWithPagination(ctx context.Context, limit,
 			&updatedAt,
 			&itemID,
 			&productID,
+			&variantID,
 			&quantity,
 			&priceCents,
 			&subTotalCents,
@@ -158,7 +167,7 @@ func (or *OrderPostgresInfra) FindAllWithPagination(ctx context.Context, limit,
 			return nil, fmt.Errorf("failed to scan order row: %w", err)
 		}
 
-		order, exists := orderMap[orderID]
+		order, exists := byID[orderID]
 		if !exists {
 			order = &entity.Order{
 				ID:         orderID,
@@ -169,32 +178,68 @@ func (or *OrderPostgresInfra) FindAllWithPagination(ctx context.Context, limit,
 				UpdatedAt:  updatedAt,
 				Items:      []*entity.OrderItem{},
 			}
-			orderMap[orderID] = order
+			byID[orderID] = order
+			orders = append(orders, order)
 		}
 
 		if itemID.Valid {
-			item := &entity.OrderItem{
+			order.Items = append(order.Items, &entity.OrderItem{
 				ID:             itemID.String,
 				OrderID:        orderID,
 				ProductID:      productID.String,
+				VariantID:      variantID.String,
 				Quantity:       int(quantity.Int64),
 				PriceCents:     priceCents.Int64,
 				SubTotal_Cents: subTotalCents.Int64,
-			}
-			order.Items = append(order.Items, item)
+			})
 		}
 	}
 
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("row iteration error: %w", err)
 	}
 
-	orders := make([]*entity.Order, 0, len(orderMap))
-	for _, order := range orderMap {
-		orders = append(orders, order)
+	return orders, nil
+}
+
+// FindAllWithPagination pagine sur les commandes puis joint leurs articles :
+// LIMIT/OFFSET appliqués à la jointure couperaient une commande en deux pages.
+func (or *OrderPostgresInfra) FindAllWithPagination(ctx context.Context, limit, offset int, filter orderdto.OrderFilter) ([]*entity.Order, error) {
+	whereClause, args := buildOrderWhere(filter)
+	argPos := len(args) + 1
+
+	query := `
+		WITH p AS (
+			SELECT o.id, o.customer_id, o.total_cents, o.status, o.created_at, o.updated_at
+			FROM orders o` + whereClause + `
+			ORDER BY o.created_at DESC, o.id DESC
+			LIMIT $` + strconv.Itoa(argPos) + ` OFFSET $` + strconv.Itoa(argPos+1) + `
+		)
+		SELECT
+			p.id AS order_id,
+			p.customer_id,
+			p.total_cents,
+			p.status,
+			p.created_at,
+			p.updated_at,
+			oi.id AS item_id,
+			oi.product_id,
+			oi.variant_id,
+			oi.quantity,
+			oi.price_cents,
+			oi.subtotal_cents
+		FROM p
+		LEFT JOIN order_items oi ON p.id = oi.order_id
+		ORDER BY p.created_at DESC, p.id DESC, oi.id`
+	args = append(args, limit, offset)
+
+	rows, err := or.queryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch paginated orders: %w", err)
 	}
+	defer rows.Close()
 
-	return orders, nil
+	return scanOrdersWithItems(rows)
 }
 
 func (or *OrderPostgresInfra) queryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
@@ -264,12 +309,12 @@ func (or *OrderPostgresInfra) FindByID(ctx context.Context, id string) (*entity.
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("order %s not found", id)
+			return nil, fmt.Errorf("order %s not found: %w", id, err)
 		}
 		return nil, fmt.Errorf("failed to fetch order: %w", err)
 	}
 
-	queryItem := `SELECT id, order_id, product_id, quantity, price_cents, subtotal_cents FROM order_items
+	queryItem := `SELECT id, order_id, product_id, variant_id, quantity, price_cents, subtotal_cents FROM order_items
 	WHERE order_id = $1`
 
 	rows, err := or.queryContext(ctx, queryItem, id)
@@ -281,10 +326,12 @@ func (or *OrderPostgresInfra) FindByID(ctx context.Context, id string) (*entity.
 
 	for rows.Next() {
 		item := &entity.OrderItem{}
+		var variantID sql.NullString
 		err := rows.Scan(
 			&item.ID,
 			&item.OrderID,
 			&item.ProductID,
+			&variantID,
 			&item.Quantity,
 			&item.PriceCents,
 			&item.SubTotal_Cents,
@@ -292,6 +339,7 @@ func (or *OrderPostgresInfra) FindByID(ctx context.Context, id string) (*entity.
 		if err != nil {
 			return nil, fmt.Errorf("failled scan order item %w", err)
 		}
+		item.VariantID = variantID.String
 		order.Items = append(order.Items, item)
 
 	}
@@ -311,12 +359,13 @@ func (or *OrderPostgresInfra) FindAll(ctx context.Context) ([]*entity.Order, err
 			o.updated_at,
 			oi.id AS item_id,
 			oi.product_id,
+			oi.variant_id,
 			oi.quantity,
 			oi.price_cents,
 			oi.subtotal_cents
 		FROM orders o
 		LEFT JOIN order_items oi ON o.id = oi.order_id
-		ORDER BY o.created_at DESC
+		ORDER BY o.created_at DESC, o.id DESC, oi.id
 	`
 
 	rows, err := or.queryContext(ctx, query)
@@ -325,75 +374,79 @@ func (or *OrderPostgresInfra) FindAll(ctx context.Context) ([]*entity.Order, err
 	}
 	defer rows.Close()
 
-	orderMap := make(map[string]*entity.Order)
+	return scanOrdersWithItems(rows)
+}
 
-	for rows.Next() {
+func (or *OrderPostgresInfra) UpdateStatus(ctx context.Context, id, from, to string) (*entity.Order, error) {
+	// La condition sur le statut courant protège contre les transitions concurrentes :
+	// si une autre requête a déjà modifié la commande, aucune ligne n'est mise à jour.
+	query := `UPDATE orders
+	SET status = $1, updated_at = NOW()
+	WHERE id = $2 AND status = $3
+	RETURNING id, customer_id, total_cents, status, created_at, updated_at`
 
-		var (
-			orderID       string
-			customerID    string
-			totalCents    int64
-			status        string
-			createdAt     time.Time
-			updatedAt     time.Time
-			itemID        sql.NullString
-			productID     sql.NullString
-			quantity      sql.NullInt64
-			priceCents    sql.NullInt64
-			subTotalCents sql.NullInt64
-		)
-
-		err := rows.Scan(
-			&orderID,
-			&customerID,
-			&totalCents,
-			&status,
-			&createdAt,
-			&updatedAt,
-			&itemID,
-			&productID,
-			&quantity,
-			&priceCents,
-			&subTotalCents,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan order: %w", err)
+	order := &entity.Order{}
+	err := or.queryRowContext(ctx, query, to, id, from).Scan(
+		&order.ID,
+		&order.CustomerID,
+		&order.TotalCents,
+		&order.Status,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("order %s not in status %s: %w", id, from, err)
 		}
+		return nil, fmt.Errorf("failed to update order status: %w", err)
+	}
 
-		// Récupérer l'ordre existant ou le créer
-		order, exists := orderMap[orderID]
-		if !exists {
-			order = &entity.Order{
-				ID:         orderID,
-				CustomerID: customerID,
-				TotalCents: totalCents,
-				Status:     status,
-				CreatedAt:  createdAt,
-				UpdatedAt:  updatedAt,
-				Items:      []*entity.OrderItem{},
-			}
-			orderMap[orderID] = order
-		}
+	return order, nil
+}
 
-		// Ajouter l'item si valable
-		if itemID.Valid {
-			item := &entity.OrderItem{
-				ID:             itemID.String,
-				OrderID:        orderID,
-				ProductID:      productID.String,
-				Quantity:       int(quantity.Int64),
-				PriceCents:     priceCents.Int64,
-				SubTotal_Cents: subTotalCents.Int64,
-			}
-			order.Items = append(order.Items, item)
+func (or *OrderPostgresInfra) AddStatusHistory(ctx context.Context, history *entity.OrderStatusHistory) error {
+	query := `INSERT INTO order_status_history (order_id, from_status, to_status, changed_by, reason)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id, created_at`
+
+	err := or.queryRowContext(ctx, query,
+		history.OrderID,
+		history.FromStatus,
+		history.ToStatus,
+		history.ChangedBy,
+		history.Reason,
+	).Scan(&history.ID, &history.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert order status history: %w", err)
+	}
+
+	return nil
+}
+
+func (or *OrderPostgresInfra) FindStatusHistory(ctx context.Context, orderID string) ([]*entity.OrderStatusHistory, error) {
+	query := `SELECT id, order_id, from_status, to_status, changed_by, reason, created_at
+	FROM order_status_history
+	WHERE order_id = $1
+	ORDER BY created_at ASC, id ASC`
+
+	rows, err := or.queryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order status history: %w", err)
+	}
+	defer rows.Close()
+
+	history := []*entity.OrderStatusHistory{}
+	for rows.Next() {
+		h := &entity.OrderStatusHistory{}
+		if err := rows.Scan(&h.ID, &h.OrderID, &h.FromStatus, &h.ToStatus, &h.ChangedBy, &h.Reason, &h.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan order status history: %w", err)
 		}
+		history = append(history, h)
 	}
 
-	// Convertir map → slice
-	orders := make([]*entity.Order, 0, len(orderMap))
-	for _, ord := range orderMap {
-		orders = append(orders, ord)
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
 	}
 
-	return orders, nil
+	return history, nil
 }
@@ -1,9 +1,12 @@
 package order_test
 
 import (
+	orderdto "Goshop/application/dto/order_dto"
+	paginationdto "Goshop/application/dto/pagination_dto"
 	"Goshop/domain/entity"
 	"Goshop/infrastructure/postgres/order"
 	"context"
+	"database/sql"
 	"regexp"
 	"testing"
 	"time"
@@ -66,13 +69,13 @@ func TestOrderRepository_FindByID(t *testing.T) {
 
 	// 2️⃣ Requête secondaire : order_items
 	itemRows := sqlmock.NewRows([]string{
-		"id", "order_id", "product_id", "quantity", "price_cents", "subtotal_cents",
+		"id", "order_id", "product_id", "variant_id", "quantity", "price_cents", "subtotal_cents",
 	}).AddRow(
-		"item-1", "order-1", "prod-99", int64(2), int64(50000), int64(100000),
+		"item-1", "order-1", "prod-99", "var-99", int64(2), int64(50000), int64(100000),
 	)
 
 	mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT id, order_id, product_id, quantity, price_cents, subtotal_cents 
+		SELECT id, order_id, product_id, variant_id, quantity, price_cents, subtotal_cents 
 		FROM order_items
 		WHERE order_id = $1`)).
 		WithArgs("order-1").
@@ -87,6 +90,7 @@ func TestOrderRepository_FindByID(t *testing.T) {
 	assert.Equal(t, int64(100000), result.TotalCents)
 	assert.Len(t, result.Items, 1)
 	assert.Equal(t, "prod-99", result.Items[0].ProductID)
+	assert.Equal(t, "var-99", result.Items[0].VariantID)
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -103,14 +107,14 @@ func TestOrderRepository_FindAll(t *testing.T) {
 
 	rows := sqlmock.NewRows([]string{
 		"order_id", "customer_id", "total_cents", "status", "created_at", "updated_at",
-		"item_id", "product_id", "quantity", "price_cents", "subtotal_cents",
+		"item_id", "product_id", "variant_id", "quantity", "price_cents", "subtotal_cents",
 	}).
 		AddRow("order-1", "cust-1", 200000, "PENDING", date1, date1,
-			"item-1", "prod-1", 1, 100000, 100000).
+			"item-1", "prod-1", "var-1", 1, 100000, 100000).
 		AddRow("order-1", "cust-1", 200000, "PENDING", date1, date1,
-			"item-2", "prod-2", 1, 100000, 100000).
+			"item-2", "prod-2", "var-2", 1, 100000, 100000).
 		AddRow("order-2", "cust-2", 50000, "PENDING", date2, date2,
-			nil, nil, nil, nil, nil)
+			nil, nil, nil, nil, nil, nil)
 
 	mock.ExpectQuery(regexp.QuoteMeta(`
 		SELECT 
@@ -122,12 +126,13 @@ func TestOrderRepository_FindAll(t *testing.T) {
 			o.updated_at,
 			oi.id AS item_id,
 			oi.product_id,
+			oi.variant_id,
 			oi.quantity,
 			oi.price_cents,
 			oi.subtotal_cents
 		FROM orders o
 		LEFT JOIN order_items oi ON o.id = oi.order_id
-		ORDER BY o.created_at DESC
+		ORDER BY o.created_at DESC, o.id DESC, oi.id
 	`)).WillReturnRows(rows)
 
 	results, err := repo.FindAll(context.Background())
@@ -144,3 +149,152 @@ func TestOrderRepository_FindAll(t *testing.T) {
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestOrderRepository_UpdateStatus(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := order.NewOrderPostgresInfra(db)
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{
+		"id", "customer_id", "total_cents", "status", "created_at", "updated_at",
+	}).AddRow("order-1", "cust-1", 1000, "PAID", now, now)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE orders
+	SET status = $1, updated_at = NOW()
+	WHERE id = $2 AND status = $3`)).
+		WithArgs("PAID", "order-1", "PENDING").
+		WillReturnRows(rows)
+
+	result, err := repo.UpdateStatus(context.Background(), "order-1", "PENDING", "PAID")
+	assert.NoError(t, err)
+	assert.Equal(t, "PAID", result.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_UpdateStatus_StaleStatus(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := order.NewOrderPostgresInfra(db)
+
+	// Aucune ligne : la commande n'est plus au statut attendu
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE orders`)).
+		WithArgs("PAID", "order-1", "PENDING").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "customer_id", "total_cents", "status", "created_at", "updated_at",
+		}))
+
+	result, err := repo.UpdateStatus(context.Background(), "order-1", "PENDING", "PAID")
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_FindAllByCursor(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := order.NewOrderPostgresInfra(db)
+
+	after := &paginationdto.Cursor{CreatedAt: time.Now(), ID: "order-0"}
+	status := "PENDING"
+	date1 := after.CreatedAt.Add(-time.Minute)
+	date2 := after.CreatedAt.Add(-time.Hour)
+
+	rows := sqlmock.NewRows([]string{
+		"order_id", "customer_id", "total_cents", "status", "created_at", "updated_at",
+		"item_id", "product_id", "variant_id", "quantity", "price_cents", "subtotal_cents",
+	}).
+		AddRow("order-1", "cust-1", 200000, "PENDING", date1, date1, "item-1", "prod-1", "var-1", 1, 100000, 100000).
+		AddRow("order-1", "cust-1", 200000, "PENDING", date1, date1, "item-2", "prod-2", "var-2", 1, 100000, 100000).
+		AddRow("order-2", "cust-2", 50000, "PENDING", date2, date2, nil, nil, nil, nil, nil, nil)
+
+	// La limite porte sur les commandes (CTE), pas sur les lignes jointes
+	mock.ExpectQuery(`WITH p AS \(\s*SELECT .* FROM orders o WHERE o\.status = \$1 AND \(o\.created_at, o\.id\) < \(\$2, \$3\) ORDER BY o\.created_at DESC, o\.id DESC\s+LIMIT \$4\s*\).*LEFT JOIN order_items oi ON p\.id = oi\.order_id ORDER BY p\.created_at DESC, p\.id DESC, oi\.id`).
+		WithArgs(status, after.CreatedAt, after.ID, 2).
+		WillReturnRows(rows)
+
+	results, err := repo.FindAllByCursor(context.Background(), paginationdto.CursorPage{Limit: 2, After: after}, orderdto.OrderFilter{Status: &status})
+
+	assert.NoError(t, err)
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, "order-1", results[0].ID)
+		assert.Len(t, results[0].Items, 2)
+		assert.Equal(t, "order-2", results[1].ID)
+		assert.Empty(t, results[1].Items)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+var orderRowColumns = []string{
+	"order_id", "customer_id", "total_cents", "status", "created_at", "updated_at",
+	"item_id", "product_id", "variant_id", "quantity", "price_cents", "subtotal_cents",
+}
+
+// La pagination offset porte sur les commandes : une commande de 3 articles
+// reste entière et deux pages successives ne se chevauchent pas.
+func TestOrderRepository_FindAllWithPagination_MultiItemOrdersAcrossPages(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := order.NewOrderPostgresInfra(db)
+
+	now := time.Now()
+	query := `WITH p AS \(\s*SELECT .* FROM orders o\s+ORDER BY o\.created_at DESC, o\.id DESC\s+LIMIT \$1 OFFSET \$2\s*\).*FROM p\s+LEFT JOIN order_items oi ON p\.id = oi\.order_id\s+ORDER BY p\.created_at DESC, p\.id DESC, oi\.id`
+
+	// Page 1 : 2 commandes mais 5 lignes jointes
+	mock.ExpectQuery(query).
+		WithArgs(2, 0).
+		WillReturnRows(sqlmock.NewRows(orderRowColumns).
+			AddRow("order-3", "cust-1", 3000, "PENDING", now, now, "item-31", "prod-1", "var-1", 1, 1000, 1000).
+			AddRow("order-3", "cust-1", 3000, "PENDING", now, now, "item-32", "prod-2", "var-2", 1, 1000, 1000).
+			AddRow("order-3", "cust-1", 3000, "PENDING", now, now, "item-33", "prod-3", "var-3", 1, 1000, 1000).
+			AddRow("order-2", "cust-1", 2000, "PAID", now.Add(-time.Minute), now, "item-21", "prod-1", "var-1", 1, 1000, 1000).
+			AddRow("order-2", "cust-1", 2000, "PAID", now.Add(-time.Minute), now, "item-22", "prod-2", "var-2", 1, 1000, 1000))
+
+	// Page 2 : la dernière commande, sans article
+	mock.ExpectQuery(query).
+		WithArgs(2, 2).
+		WillReturnRows(sqlmock.NewRows(orderRowColumns).
+			AddRow("order-1", "cust-2", 0, "CANCELLED", now.Add(-time.Hour), now, nil, nil, nil, nil, nil, nil))
+
+	first, err := repo.FindAllWithPagination(context.Background(), 2, 0, orderdto.OrderFilter{})
+	assert.NoError(t, err)
+	second, err := repo.FindAllWithPagination(context.Background(), 2, 2, orderdto.OrderFilter{})
+	assert.NoError(t, err)
+
+	if assert.Len(t, first, 2, "la limite s'applique aux commandes, pas aux lignes") {
+		assert.Equal(t, "order-3", first[0].ID)
+		assert.Len(t, first[0].Items, 3)
+		assert.Equal(t, "item-31", first[0].Items[0].ID)
+		assert.Equal(t, "order-2", first[1].ID)
+		assert.Len(t, first[1].Items, 2)
+	}
+	if assert.Len(t, second, 1) {
+		assert.Equal(t, "order-1", second[0].ID)
+		assert.Empty(t, second[0].Items)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_FindAllWithPagination_Filters(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := order.NewOrderPostgresInfra(db)
+
+	status := "PAID"
+	customerID := "cust-1"
+
+	mock.ExpectQuery(`WITH p AS \(\s*SELECT .* FROM orders o WHERE o\.status = \$1 AND o\.customer_id = \$2\s+ORDER BY o\.created_at DESC, o\.id DESC\s+LIMIT \$3 OFFSET \$4\s*\)`).
+		WithArgs(status, customerID, 10, 20).
+		WillReturnRows(sqlmock.NewRows(orderRowColumns))
+
+	results, err := repo.FindAllWithPagination(context.Background(), 10, 20, orderdto.OrderFilter{Status: &status, CustomerID: &customerID})
+
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
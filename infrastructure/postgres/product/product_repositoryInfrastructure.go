@@ -1,11 +1,17 @@
 package product
 
 import (
+	paginationdto "Goshop/application/dto/pagination_dto"
+	productdto "Goshop/application/dto/product_dto"
 	"Goshop/domain/entity"
 	"Goshop/domain/repository"
+	"Goshop/infrastructure/postgres"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 type ProductRepositoryInfrastructure struct {
@@ -53,95 +59,308 @@ func (pr *ProductRepositoryInfrastructure) Create(ctx context.Context, product *
 
 }
 
-func (pr *ProductRepositoryInfrastructure) FindByID(ctx context.Context, id string) (*entity.Product, error) {
+// Colonnes lues par scanProduct, dans l'ordre
+const productColumns = `id, name, description, price_cents, stock, created_at, updated_at, deleted_at`
 
-	query := `SELECT id, name, description, price_cents, stock, created_at, updated_at
-	FROM products WHERE id= $1;`
-	product := &entity.Product{}
-	err := pr.queryRowContext(ctx, query, id).Scan(&product.ID, &product.Name, &product.Description, &product.PriceCents, &product.Stock, &product.CreatedAt, &product.UpdatedAt)
-	if err != nil {
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanProduct(row rowScanner) (*entity.Product, error) {
+	p := &entity.Product{}
+	var deletedAt sql.NullTime
+	if err := row.Scan(&p.ID, &p.Name, &p.Description, &p.PriceCents, &p.Stock, &p.CreatedAt, &p.UpdatedAt, &deletedAt); err != nil {
 		return nil, err
 	}
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("product with id %s not found", id)
+	if deletedAt.Valid {
+		p.DeletedAt = &deletedAt.Time
 	}
+	return p, nil
+}
 
-	return product, nil
+func (pr *ProductRepositoryInfrastructure) FindByID(ctx context.Context, id string) (*entity.Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products WHERE id = $1 AND deleted_at IS NULL;`
+	return scanProduct(pr.queryRowContext(ctx, query, id))
 }
 
-// infrastructure/postgres/product/product_repository_infrastructure.go
+func (pr *ProductRepositoryInfrastructure) FindByIDIncludingDeleted(ctx context.Context, id string) (*entity.Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products WHERE id = $1;`
+	return scanProduct(pr.queryRowContext(ctx, query, id))
+}
 
-func (pr *ProductRepositoryInfrastructure) FindAll(ctx context.Context, limit, offset int) ([]*entity.Product, error) {
-	// ⚠️ AJOUTE CES LOGS ⚠️
-	fmt.Printf("🚨 [DEBUG] Repository.FindAll appelé avec limit=%d, offset=%d\n", limit, offset)
+// Expressions ORDER BY autorisées : la clé de tri n'est jamais concaténée telle quelle
+var productSortColumns = map[string]string{
+	productdto.ProductSortCreatedAt: "created_at",
+	productdto.ProductSortPrice:     "price_cents",
+	productdto.ProductSortName:      "name",
+}
+
+// buildProductWhere construit la clause WHERE à partir du filtre. Le paramètre
+// de la recherche plein texte est toujours $1 quand il existe, pour pouvoir
+// être réutilisé dans le tri par pertinence.
+func buildProductWhere(filter productdto.ProductFilter) (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+	argPos := 1
+
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+
+	if filter.HasQuery() {
+		conditions = append(conditions, fmt.Sprintf("search_vector @@ websearch_to_tsquery('simple', $%d)", argPos))
+		args = append(args, strings.TrimSpace(*filter.Query))
+		argPos++
+	}
 
+	if filter.MinPriceCents != nil {
+		conditions = append(conditions, fmt.Sprintf("price_cents >= $%d", argPos))
+		args = append(args, *filter.MinPriceCents)
+		argPos++
+	}
+
+	if filter.MaxPriceCents != nil {
+		conditions = append(conditions, fmt.Sprintf("price_cents <= $%d", argPos))
+		args = append(args, *filter.MaxPriceCents)
+		argPos++
+	}
+
+	if filter.InStock {
+		conditions = append(conditions, "stock > 0")
+	}
+
+	if filter.CreatedAfter != nil {
+		conditions = append(conditions, fmt.Sprintf("created_at > $%d", argPos))
+		args = append(args, *filter.CreatedAfter)
+		argPos++
+	}
+
+	// Catégorie demandée et tous ses descendants (préfixe du path)
+	if filter.CategorySlug != nil {
+		conditions = append(conditions, fmt.Sprintf("id IN (SELECT pc.product_id FROM product_categories pc"+
+			" JOIN categories c ON c.id = pc.category_id JOIN categories root ON root.slug = $%d"+
+			" WHERE c.path = root.path OR c.path LIKE root.path || '/%%')", argPos))
+		args = append(args, *filter.CategorySlug)
+		argPos++
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// buildProductOrderBy traduit le tri du filtre en ORDER BY, avec l'id en
+// départage pour que la pagination soit stable.
+func buildProductOrderBy(filter productdto.ProductFilter) string {
+	key, desc := filter.EffectiveSort()
+
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+
+	if key == productdto.ProductSortRelevance && filter.HasQuery() {
+		return fmt.Sprintf(" ORDER BY ts_rank(search_vector, websearch_to_tsquery('simple', $1)) %s, created_at DESC, id DESC", direction)
+	}
+
+	column, ok := productSortColumns[key]
+	if !ok {
+		column, direction = "created_at", "DESC"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, id %s", column, direction, direction)
+}
+
+func (pr *ProductRepositoryInfrastructure) FindAll(ctx context.Context, limit, offset int, filter productdto.ProductFilter) ([]*entity.Product, error) {
 	if limit <= 0 {
 		limit = 50
-		fmt.Printf("⚠️ [DEBUG] Repository: Limit corrigé à %d\n", limit)
 	}
 	if limit > 100 {
 		limit = 100
-		fmt.Printf("⚠️ [DEBUG] Repository: Limit limité à %d\n", limit)
 	}
 	if offset < 0 {
 		offset = 0
-		fmt.Printf("⚠️ [DEBUG] Repository: Offset corrigé à %d\n", offset)
 	}
 
-	query := `SELECT id, name, description, price_cents, stock, created_at, updated_at 
-              FROM products 
-              ORDER BY created_at DESC 
-              LIMIT $1 OFFSET $2`
+	whereClause, args := buildProductWhere(filter)
+	query := `SELECT ` + productColumns + `
+	FROM products` + whereClause + buildProductOrderBy(filter) +
+		" LIMIT $" + strconv.Itoa(len(args)+1) + " OFFSET $" + strconv.Itoa(len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := pr.queryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch products: %w", err)
+	}
+	defer rows.Close()
+
+	return scanProducts(rows)
+}
+
+func (pr *ProductRepositoryInfrastructure) FindAllByCursor(ctx context.Context, page paginationdto.CursorPage, filter productdto.ProductFilter) ([]*entity.Product, error) {
+	whereClause, args := buildProductWhere(filter)
+
+	keyset, orderBy, keysetArgs := postgres.KeysetClause(page, "", len(args)+1)
+	if keyset != "" {
+		if whereClause == "" {
+			whereClause = " WHERE " + keyset
+		} else {
+			whereClause += " AND " + keyset
+		}
+		args = append(args, keysetArgs...)
+	}
+
+	query := `SELECT ` + productColumns + `
+	FROM products` + whereClause + orderBy + " LIMIT $" + strconv.Itoa(len(args)+1)
+	args = append(args, page.Limit)
 
-	// ⚠️ AJOUTE CES LOGS ⚠️
-	fmt.Printf("📋 [DEBUG] Repository: Requête SQL: %s\n", query)
-	fmt.Printf("📋 [DEBUG] Repository: Paramètres SQL: limit=%d, offset=%d\n", limit, offset)
+	rows, err := pr.queryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch products page: %w", err)
+	}
+	defer rows.Close()
 
-	rows, err := pr.queryContext(ctx, query, limit, offset)
+	products, err := scanProducts(rows)
 	if err != nil {
-		fmt.Printf("❌ [DEBUG] Repository: Erreur queryContext: %v\n", err)
 		return nil, err
 	}
-	defer rows.Close()
 
-	var products []*entity.Product
-	count := 0
+	postgres.RestoreOrder(products, page)
+	return products, nil
+}
+
+func scanProducts(rows *sql.Rows) ([]*entity.Product, error) {
+	products := []*entity.Product{}
 	for rows.Next() {
-		count++
-		p := &entity.Product{}
-		err := rows.Scan(&p.ID, &p.Name, &p.Description, &p.PriceCents,
-			&p.Stock, &p.CreatedAt, &p.UpdatedAt)
+		p, err := scanProduct(rows)
 		if err != nil {
-			fmt.Printf("❌ [DEBUG] Repository: Erreur Scan ligne %d: %v\n", count, err)
-			return nil, err
+			return nil, fmt.Errorf("failed to scan product row: %w", err)
 		}
 		products = append(products, p)
 	}
 
-	fmt.Printf("✅ [DEBUG] Repository: Retourne %d produits (lues %d lignes)\n", len(products), count)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
 	return products, nil
 }
 
+func (pr *ProductRepositoryInfrastructure) CountAll(ctx context.Context, filter productdto.ProductFilter) (int, error) {
+	whereClause, args := buildProductWhere(filter)
+	query := `SELECT COUNT(*) FROM products` + whereClause
+
+	var total int
+	if err := pr.queryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to count products: %w", err)
+	}
+	return total, nil
+}
+
 func (pr *ProductRepositoryInfrastructure) Update(ctx context.Context, product *entity.Product) (*entity.Product, error) {
 	query := `
 	UPDATE products
 	SET name = $1, description = $2, price_cents = $3, stock = $4, updated_at = NOW()
-	WHERE id=$5
-	RETURNING id, name, description, price_cents, stock, created_at, updated_at;`
+	WHERE id=$5 AND deleted_at IS NULL
+	RETURNING ` + productColumns + `;`
 
-	updated := &entity.Product{}
-	err := pr.queryRowContext(ctx, query, product.Name, product.Description, product.PriceCents, product.Stock, product.ID).
-		Scan(&updated.ID, &updated.Name, &updated.Description, &updated.PriceCents, &updated.Stock, &updated.CreatedAt, &updated.UpdatedAt)
+	return scanProduct(pr.queryRowContext(ctx, query, product.Name, product.Description, product.PriceCents, product.Stock, product.ID))
+}
 
+func (pr *ProductRepositoryInfrastructure) Delete(ctx context.Context, id string) error {
+	// Archivage : la ligne reste référencée par les articles de commande
+	query := `UPDATE products SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+	res, err := pr.execContext(ctx, query, id)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to archive product %s: %w", id, err)
 	}
 
-	return updated, nil
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("product %s not found: %w", id, sql.ErrNoRows)
+	}
+	return nil
 }
 
-func (pr *ProductRepositoryInfrastructure) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM products WHERE id =$1`
-	_, err := pr.execContext(ctx, query, id)
-	return err
+func (pr *ProductRepositoryInfrastructure) Restore(ctx context.Context, id string) (*entity.Product, error) {
+	query := `
+	UPDATE products
+	SET deleted_at = NULL, updated_at = NOW()
+	WHERE id = $1 AND deleted_at IS NOT NULL
+	RETURNING ` + productColumns + `;`
+
+	return scanProduct(pr.queryRowContext(ctx, query, id))
+}
+
+func (pr *ProductRepositoryInfrastructure) Purge(ctx context.Context, id string) (bool, error) {
+	// Les articles de commande gardent leur produit : un produit commandé
+	// n'est jamais supprimé
+	query := `
+	DELETE FROM products p
+	WHERE p.id = $1 AND p.deleted_at IS NOT NULL
+	  AND NOT EXISTS (SELECT 1 FROM order_items oi WHERE oi.product_id = p.id)`
+	res, err := pr.execContext(ctx, query, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to purge product %s: %w", id, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	return affected > 0, nil
+}
+
+func (pr *ProductRepositoryInfrastructure) ReserveStock(ctx context.Context, id string, quantity int) (*entity.Product, error) {
+	// Décrément conditionnel : le contrôle du stock et l'écriture sont atomiques,
+	// deux commandes concurrentes ne peuvent pas passer sous zéro.
+	// Un produit archivé ne se commande plus
+	query := `
+	UPDATE products
+	SET stock = stock - $1, updated_at = NOW()
+	WHERE id = $2 AND stock >= $1 AND deleted_at IS NULL
+	RETURNING ` + productColumns + `;`
+
+	reserved, err := scanProduct(pr.queryRowContext(ctx, query, quantity, id))
+	if err == nil {
+		return reserved, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to reserve stock for product %s: %w", id, err)
+	}
+
+	// Aucune ligne : produit absent ou stock insuffisant
+	var available int
+	err = pr.queryRowContext(ctx, `SELECT stock FROM products WHERE id = $1 AND deleted_at IS NULL`, id).Scan(&available)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("product %s not found: %w", id, sql.ErrNoRows)
+		}
+		return nil, fmt.Errorf("failed to read stock for product %s: %w", id, err)
+	}
+
+	return nil, fmt.Errorf("product %s has %d in stock, %d requested: %w", id, available, quantity, repository.ErrInsufficientStock)
+}
+
+func (pr *ProductRepositoryInfrastructure) RestoreStock(ctx context.Context, id string, quantity int) error {
+	// Incrément relatif : pas de lecture préalable, donc pas de "last writer wins".
+	// Produit archivé compris : l'annulation d'une commande reste possible.
+	query := `UPDATE products SET stock = stock + $1, updated_at = NOW() WHERE id = $2`
+	res, err := pr.execContext(ctx, query, quantity, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore stock for product %s: %w", id, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("product %s not found: %w", id, sql.ErrNoRows)
+	}
+
+	return nil
 }
@@ -0,0 +1,258 @@
+package product_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	paginationdto "Goshop/application/dto/pagination_dto"
+	productdto "Goshop/application/dto/product_dto"
+	"Goshop/infrastructure/postgres/product"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var productColumns = []string{"id", "name", "description", "price_cents", "stock", "created_at", "updated_at", "deleted_at"}
+
+func TestProductRepository_FindAll_DefaultSort(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := product.NewProductRepositoryInfrastructure(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM products WHERE deleted_at IS NULL ORDER BY created_at DESC, id DESC LIMIT $1 OFFSET $2`)).
+		WithArgs(50, 0).
+		WillReturnRows(sqlmock.NewRows(productColumns).
+			AddRow("p1", "Shoe", "Running shoe", 4500, 3, time.Now(), time.Now(), nil))
+
+	products, err := repo.FindAll(context.Background(), 0, -5, productdto.ProductFilter{})
+
+	require.NoError(t, err)
+	assert.Len(t, products, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_FindAll_WithFilter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := product.NewProductRepositoryInfrastructure(db)
+
+	q := "running shoes"
+	minPrice, maxPrice := int64(1000), int64(5000)
+	createdAfter := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	filter := productdto.ProductFilter{
+		Query:         &q,
+		MinPriceCents: &minPrice,
+		MaxPriceCents: &maxPrice,
+		InStock:       true,
+		CreatedAfter:  &createdAfter,
+	}
+	require.NoError(t, filter.SetSort("-price"))
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM products WHERE deleted_at IS NULL AND search_vector @@ websearch_to_tsquery('simple', $1) AND price_cents >= $2 AND price_cents <= $3 AND stock > 0 AND created_at > $4 ORDER BY price_cents DESC, id DESC LIMIT $5 OFFSET $6`)).
+		WithArgs(q, minPrice, maxPrice, createdAfter, 20, 40).
+		WillReturnRows(sqlmock.NewRows(productColumns))
+
+	products, err := repo.FindAll(context.Background(), 20, 40, filter)
+
+	require.NoError(t, err)
+	assert.Empty(t, products)
+	assert.NotNil(t, products, "une page vide doit être un tableau vide")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_FindAll_RelevanceByDefaultWithQuery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := product.NewProductRepositoryInfrastructure(db)
+
+	q := "shoes"
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE deleted_at IS NULL AND search_vector @@ websearch_to_tsquery('simple', $1) ORDER BY ts_rank(search_vector, websearch_to_tsquery('simple', $1)) DESC, created_at DESC, id DESC LIMIT $2 OFFSET $3`)).
+		WithArgs(q, 10, 0).
+		WillReturnRows(sqlmock.NewRows(productColumns))
+
+	_, err = repo.FindAll(context.Background(), 10, 0, productdto.ProductFilter{Query: &q})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_CountAll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := product.NewProductRepositoryInfrastructure(db)
+
+	minPrice := int64(1000)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM products WHERE deleted_at IS NULL AND price_cents >= $1 AND stock > 0`)).
+		WithArgs(minPrice).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+	total, err := repo.CountAll(context.Background(), productdto.ProductFilter{MinPriceCents: &minPrice, InStock: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, 42, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_CountAll_CategoryIncludesDescendants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := product.NewProductRepositoryInfrastructure(db)
+
+	minPrice := int64(1000)
+	slug := "electronique"
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM products WHERE deleted_at IS NULL AND price_cents >= $1 AND id IN (SELECT pc.product_id FROM product_categories pc JOIN categories c ON c.id = pc.category_id JOIN categories root ON root.slug = $2 WHERE c.path = root.path OR c.path LIKE root.path || '/%')`)).
+		WithArgs(minPrice, slug).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+
+	total, err := repo.CountAll(context.Background(), productdto.ProductFilter{MinPriceCents: &minPrice, CategorySlug: &slug})
+
+	require.NoError(t, err)
+	assert.Equal(t, 7, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_FindAllByCursor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := product.NewProductRepositoryInfrastructure(db)
+
+	after := &paginationdto.Cursor{CreatedAt: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), ID: "p5"}
+	minPrice := int64(1000)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM products WHERE deleted_at IS NULL AND price_cents >= $1 AND (created_at, id) < ($2, $3) ORDER BY created_at DESC, id DESC LIMIT $4`)).
+		WithArgs(minPrice, after.CreatedAt, after.ID, 21).
+		WillReturnRows(sqlmock.NewRows(productColumns))
+
+	_, err = repo.FindAllByCursor(context.Background(), paginationdto.CursorPage{Limit: 21, After: after}, productdto.ProductFilter{MinPriceCents: &minPrice})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_FindAllByCursor_Backward(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := product.NewProductRepositoryInfrastructure(db)
+
+	after := &paginationdto.Cursor{CreatedAt: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), ID: "p5"}
+	older, newer := after.CreatedAt.Add(time.Minute), after.CreatedAt.Add(time.Hour)
+
+	// La requête remonte le temps : du plus proche du curseur au plus récent
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM products WHERE deleted_at IS NULL AND (created_at, id) > ($1, $2) ORDER BY created_at ASC, id ASC LIMIT $3`)).
+		WithArgs(after.CreatedAt, after.ID, 2).
+		WillReturnRows(sqlmock.NewRows(productColumns).
+			AddRow("p4", "B", "", 100, 1, older, older, nil).
+			AddRow("p3", "A", "", 100, 1, newer, newer, nil))
+
+	products, err := repo.FindAllByCursor(context.Background(), paginationdto.CursorPage{Limit: 2, After: after, Backward: true}, productdto.ProductFilter{})
+
+	require.NoError(t, err)
+	require.Len(t, products, 2)
+	assert.Equal(t, "p3", products[0].ID, "page remise dans l'ordre d'affichage")
+	assert.Equal(t, "p4", products[1].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_FindAll_IncludeDeleted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := product.NewProductRepositoryInfrastructure(db)
+
+	deletedAt := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM products ORDER BY created_at DESC, id DESC LIMIT $1 OFFSET $2`)).
+		WithArgs(50, 0).
+		WillReturnRows(sqlmock.NewRows(productColumns).
+			AddRow("p1", "Shoe", "", 4500, 3, time.Now(), time.Now(), deletedAt))
+
+	products, err := repo.FindAll(context.Background(), 50, 0, productdto.ProductFilter{IncludeDeleted: true})
+
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	require.NotNil(t, products[0].DeletedAt)
+	assert.Equal(t, deletedAt, *products[0].DeletedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_Delete_Archives(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := product.NewProductRepositoryInfrastructure(db)
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE products SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`)).
+		WithArgs("p1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE products SET deleted_at = NOW()`)).
+		WithArgs("p1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	require.NoError(t, repo.Delete(context.Background(), "p1"))
+	// Déjà archivé
+	assert.ErrorIs(t, repo.Delete(context.Background(), "p1"), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_Restore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := product.NewProductRepositoryInfrastructure(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SET deleted_at = NULL, updated_at = NOW()
+	WHERE id = $1 AND deleted_at IS NOT NULL`)).
+		WithArgs("p1").
+		WillReturnRows(sqlmock.NewRows(productColumns).
+			AddRow("p1", "Shoe", "", 4500, 3, time.Now(), time.Now(), nil))
+
+	restored, err := repo.Restore(context.Background(), "p1")
+
+	require.NoError(t, err)
+	assert.Nil(t, restored.DeletedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_Purge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := product.NewProductRepositoryInfrastructure(db)
+
+	purge := regexp.QuoteMeta(`DELETE FROM products p
+	WHERE p.id = $1 AND p.deleted_at IS NOT NULL
+	  AND NOT EXISTS (SELECT 1 FROM order_items oi WHERE oi.product_id = p.id)`)
+	mock.ExpectExec(purge).WithArgs("p1").WillReturnResult(sqlmock.NewResult(0, 1))
+	// Produit commandé (ou actif) : ligne conservée
+	mock.ExpectExec(purge).WithArgs("p2").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	deleted, err := repo.Purge(context.Background(), "p1")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	deleted, err = repo.Purge(context.Background(), "p2")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,221 @@
+package productimage
+
+import (
+	"Goshop/domain/entity"
+	"Goshop/domain/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+const imageColumns = `id, product_id, blob_key, content_type, size_bytes, position, created_at`
+
+type ProductImagePostgres struct {
+	db *sql.DB
+	tx repository.Tx
+}
+
+func NewProductImagePostgres(db *sql.DB) repository.ProductImageRepository {
+	return &ProductImagePostgres{db: db}
+}
+
+func (ip *ProductImagePostgres) WithTX(tx repository.Tx) repository.ProductImageRepository {
+	return &ProductImagePostgres{tx: tx, db: ip.db}
+}
+
+func (ip *ProductImagePostgres) queryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if ip.tx != nil {
+		return ip.tx.QueryRowContext(ctx, query, args...)
+	}
+	return ip.db.QueryRowContext(ctx, query, args...)
+}
+
+func (ip *ProductImagePostgres) queryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if ip.tx != nil {
+		return ip.tx.QueryContext(ctx, query, args...)
+	}
+	return ip.db.QueryContext(ctx, query, args...)
+}
+
+func (ip *ProductImagePostgres) execContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if ip.tx != nil {
+		return ip.tx.ExecContext(ctx, query, args...)
+	}
+	return ip.db.ExecContext(ctx, query, args...)
+}
+
+func (ip *ProductImagePostgres) LockProduct(ctx context.Context, productID string) error {
+	var id string
+	err := ip.queryRowContext(ctx, `SELECT id FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, productID).Scan(&id)
+	if isInvalidUUID(err) {
+		return fmt.Errorf("product %s not found: %w", productID, sql.ErrNoRows)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock product %s: %w", productID, err)
+	}
+	return nil
+}
+
+func (ip *ProductImagePostgres) Create(ctx context.Context, image *entity.ProductImage) error {
+	// Position suivant la dernière image : le produit est verrouillé par
+	// l'appelant, deux ajouts simultanés ne calculent pas la même
+	query := `
+	INSERT INTO product_images (id, product_id, blob_key, content_type, size_bytes, position)
+	VALUES ($1, $2, $3, $4, $5,
+		(SELECT COALESCE(MAX(position) + 1, 0) FROM product_images WHERE product_id = $2))
+	RETURNING position, created_at;`
+	err := ip.queryRowContext(ctx, query, image.ID, image.ProductID, image.BlobKey, image.ContentType, image.SizeBytes).
+		Scan(&image.Position, &image.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create product image: %w", err)
+	}
+	return nil
+}
+
+func (ip *ProductImagePostgres) FindByID(ctx context.Context, id string) (*entity.ProductImage, error) {
+	image, err := scanImage(ip.queryRowContext(ctx, `SELECT `+imageColumns+` FROM product_images WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) || isInvalidUUID(err) {
+		return nil, repository.ErrImageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find product image: %w", err)
+	}
+	return image, nil
+}
+
+func (ip *ProductImagePostgres) FindByProduct(ctx context.Context, productID string) ([]*entity.ProductImage, error) {
+	query := `SELECT ` + imageColumns + ` FROM product_images WHERE product_id = $1 ORDER BY position`
+	rows, err := ip.queryContext(ctx, query, productID)
+	if err != nil {
+		if isInvalidUUID(err) {
+			return []*entity.ProductImage{}, nil
+		}
+		return nil, fmt.Errorf("failed to fetch product images: %w", err)
+	}
+	return scanImages(rows)
+}
+
+func (ip *ProductImagePostgres) FindPrimary(ctx context.Context, productIDs []string) ([]*entity.ProductImage, error) {
+	if len(productIDs) == 0 {
+		return []*entity.ProductImage{}, nil
+	}
+
+	query := `SELECT DISTINCT ON (product_id) ` + imageColumns + ` FROM product_images
+	WHERE product_id = ANY($1::uuid[])
+	ORDER BY product_id, position`
+	rows, err := ip.queryContext(ctx, query, pq.Array(productIDs))
+	if err != nil {
+		if isInvalidUUID(err) {
+			return []*entity.ProductImage{}, nil
+		}
+		return nil, fmt.Errorf("failed to fetch primary images: %w", err)
+	}
+	return scanImages(rows)
+}
+
+func (ip *ProductImagePostgres) Delete(ctx context.Context, productID, id string) error {
+	var position int
+	err := ip.queryRowContext(ctx,
+		`DELETE FROM product_images WHERE id = $1 AND product_id = $2 RETURNING position`, id, productID).
+		Scan(&position)
+	if errors.Is(err, sql.ErrNoRows) || isInvalidUUID(err) {
+		return repository.ErrImageNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete product image: %w", err)
+	}
+
+	// Les images suivantes remontent d'un rang
+	query := `UPDATE product_images SET position = position - 1 WHERE product_id = $1 AND position > $2`
+	if _, err := ip.execContext(ctx, query, productID, position); err != nil {
+		return fmt.Errorf("failed to shift product images: %w", err)
+	}
+	return nil
+}
+
+func (ip *ProductImagePostgres) DeleteByProduct(ctx context.Context, productID string) ([]string, error) {
+	rows, err := ip.queryContext(ctx,
+		`DELETE FROM product_images WHERE product_id = $1 RETURNING blob_key`, productID)
+	if isInvalidUUID(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete product images: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []string{}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan image blob key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (ip *ProductImagePostgres) Reorder(ctx context.Context, productID string, imageIDs []string) error {
+	// Unicité (product_id, position) différée : les positions s'échangent
+	// en une seule requête
+	query := `
+	UPDATE product_images AS pi
+	SET position = o.ord - 1
+	FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, ord)
+	WHERE pi.id = o.id AND pi.product_id = $1`
+	res, err := ip.execContext(ctx, query, productID, pq.Array(imageIDs))
+	if isInvalidUUID(err) {
+		return repository.ErrImageNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reorder product images: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected != int64(len(imageIDs)) {
+		return repository.ErrImageNotFound
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanImage(row rowScanner) (*entity.ProductImage, error) {
+	image := &entity.ProductImage{}
+	err := row.Scan(&image.ID, &image.ProductID, &image.BlobKey, &image.ContentType, &image.SizeBytes, &image.Position, &image.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+func scanImages(rows *sql.Rows) ([]*entity.ProductImage, error) {
+	defer rows.Close()
+
+	images := []*entity.ProductImage{}
+	for rows.Next() {
+		image, err := scanImage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product image row: %w", err)
+		}
+		images = append(images, image)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return images, nil
+}
+
+// isInvalidUUID : identifiant qui n'est pas un UUID (invalid_text_representation)
+func isInvalidUUID(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "22P02"
+}
@@ -0,0 +1,117 @@
+package productimage_test
+
+import (
+	"Goshop/domain/entity"
+	"Goshop/domain/repository"
+	productimage "Goshop/infrastructure/postgres/product_image"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var imageColumns = []string{"id", "product_id", "blob_key", "content_type", "size_bytes", "position", "created_at"}
+
+func TestProductImagePostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := productimage.NewProductImagePostgres(db)
+	now := time.Now()
+
+	mock.ExpectQuery(`INSERT INTO product_images .*SELECT COALESCE\(MAX\(position\) \+ 1, 0\)`).
+		WithArgs("img-2", "p-1", "products/p-1/img-2.png", "image/png", int64(1200)).
+		WillReturnRows(sqlmock.NewRows([]string{"position", "created_at"}).AddRow(1, now))
+
+	image := &entity.ProductImage{ID: "img-2", ProductID: "p-1", BlobKey: "products/p-1/img-2.png", ContentType: "image/png", SizeBytes: 1200}
+	require.NoError(t, repo.Create(context.Background(), image))
+	assert.Equal(t, 1, image.Position)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductImagePostgres_FindPrimary(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := productimage.NewProductImagePostgres(db)
+	now := time.Now()
+	mock.ExpectQuery(`SELECT DISTINCT ON \(product_id\) .* FROM product_images\s+WHERE product_id = ANY\(\$1::uuid\[\]\)\s+ORDER BY product_id, position`).
+		WithArgs(pq.Array([]string{"p-1", "p-2"})).
+		WillReturnRows(sqlmock.NewRows(imageColumns).
+			AddRow("img-1", "p-1", "products/p-1/img-1.jpg", "image/jpeg", int64(5000), 0, now))
+
+	images, err := repo.FindPrimary(context.Background(), []string{"p-1", "p-2"})
+	require.NoError(t, err)
+	require.Len(t, images, 1)
+	assert.Equal(t, "img-1", images[0].ID)
+
+	empty, err := repo.FindPrimary(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductImagePostgres_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := productimage.NewProductImagePostgres(db)
+	mock.ExpectQuery(`DELETE FROM product_images WHERE id = \$1 AND product_id = \$2 RETURNING position`).
+		WithArgs("img-1", "p-1").
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(0))
+	mock.ExpectExec(`UPDATE product_images SET position = position - 1 WHERE product_id = \$1 AND position > \$2`).
+		WithArgs("p-1", 0).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	// Image d'un autre produit
+	mock.ExpectQuery(`DELETE FROM product_images WHERE id = \$1 AND product_id = \$2 RETURNING position`).
+		WithArgs("img-9", "p-1").
+		WillReturnRows(sqlmock.NewRows([]string{"position"}))
+
+	require.NoError(t, repo.Delete(context.Background(), "p-1", "img-1"))
+	assert.ErrorIs(t, repo.Delete(context.Background(), "p-1", "img-9"), repository.ErrImageNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductImagePostgres_DeleteByProduct(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := productimage.NewProductImagePostgres(db)
+	mock.ExpectQuery(`DELETE FROM product_images WHERE product_id = \$1 RETURNING blob_key`).
+		WithArgs("p-1").
+		WillReturnRows(sqlmock.NewRows([]string{"blob_key"}).
+			AddRow("products/p-1/img-1.png").
+			AddRow("products/p-1/img-2.gif"))
+
+	keys, err := repo.DeleteByProduct(context.Background(), "p-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"products/p-1/img-1.png", "products/p-1/img-2.gif"}, keys)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductImagePostgres_Reorder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := productimage.NewProductImagePostgres(db)
+	mock.ExpectExec(`UPDATE product_images AS pi\s+SET position = o.ord - 1\s+FROM unnest\(\$2::uuid\[\]\) WITH ORDINALITY`).
+		WithArgs("p-1", pq.Array([]string{"img-2", "img-1"})).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`UPDATE product_images AS pi`).
+		WithArgs("p-1", pq.Array([]string{"img-2", "img-7"})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.Reorder(context.Background(), "p-1", []string{"img-2", "img-1"}))
+	assert.ErrorIs(t, repo.Reorder(context.Background(), "p-1", []string{"img-2", "img-7"}), repository.ErrImageNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
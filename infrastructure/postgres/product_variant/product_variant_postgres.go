@@ -0,0 +1,326 @@
+package productvariant
+
+import (
+	"Goshop/domain/entity"
+	"Goshop/domain/repository"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+const variantColumns = `id, product_id, sku, options, price_cents, stock, is_default, created_at, updated_at`
+
+// Contrainte d'unicité (product_id, options) de la migration 015 ; toute
+// autre violation d'unicité porte sur le SKU
+const optionsConstraint = "product_variants_options_key"
+
+type ProductVariantPostgres struct {
+	db *sql.DB
+	tx repository.Tx
+}
+
+func NewProductVariantPostgres(db *sql.DB) repository.ProductVariantRepository {
+	return &ProductVariantPostgres{db: db}
+}
+
+func (vp *ProductVariantPostgres) WithTX(tx repository.Tx) repository.ProductVariantRepository {
+	return &ProductVariantPostgres{tx: tx, db: vp.db}
+}
+
+func (vp *ProductVariantPostgres) queryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if vp.tx != nil {
+		return vp.tx.QueryRowContext(ctx, query, args...)
+	}
+	return vp.db.QueryRowContext(ctx, query, args...)
+}
+
+func (vp *ProductVariantPostgres) queryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if vp.tx != nil {
+		return vp.tx.QueryContext(ctx, query, args...)
+	}
+	return vp.db.QueryContext(ctx, query, args...)
+}
+
+func (vp *ProductVariantPostgres) execContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if vp.tx != nil {
+		return vp.tx.ExecContext(ctx, query, args...)
+	}
+	return vp.db.ExecContext(ctx, query, args...)
+}
+
+func (vp *ProductVariantPostgres) Create(ctx context.Context, v *entity.ProductVariant) error {
+	options, err := marshalOptions(v.Options)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO product_variants (product_id, sku, options, price_cents, stock, is_default)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING id, created_at, updated_at;`
+	err = vp.queryRowContext(ctx, query, v.ProductID, v.SKU, options, nullInt64(v.PriceCents), v.Stock, v.IsDefault).
+		Scan(&v.ID, &v.CreatedAt, &v.UpdatedAt)
+	if conflict := uniqueViolation(err); conflict != nil {
+		return conflict
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create product variant: %w", err)
+	}
+	return nil
+}
+
+func (vp *ProductVariantPostgres) FindByID(ctx context.Context, id string) (*entity.ProductVariant, error) {
+	query := `SELECT ` + variantColumns + ` FROM product_variants WHERE id = $1`
+	return vp.findOne(ctx, query, id)
+}
+
+func (vp *ProductVariantPostgres) FindDefault(ctx context.Context, productID string) (*entity.ProductVariant, error) {
+	query := `SELECT ` + variantColumns + ` FROM product_variants WHERE product_id = $1 AND is_default`
+	return vp.findOne(ctx, query, productID)
+}
+
+func (vp *ProductVariantPostgres) findOne(ctx context.Context, query string, arg string) (*entity.ProductVariant, error) {
+	v, err := scanVariant(vp.queryRowContext(ctx, query, arg))
+	if errors.Is(err, sql.ErrNoRows) || isInvalidUUID(err) {
+		return nil, repository.ErrVariantNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find product variant: %w", err)
+	}
+	return v, nil
+}
+
+func (vp *ProductVariantPostgres) FindByProducts(ctx context.Context, productIDs []string) ([]*entity.ProductVariant, error) {
+	if len(productIDs) == 0 {
+		return []*entity.ProductVariant{}, nil
+	}
+
+	query := `SELECT ` + variantColumns + ` FROM product_variants
+	WHERE product_id = ANY($1::uuid[])
+	ORDER BY product_id, is_default DESC, created_at, id`
+	rows, err := vp.queryContext(ctx, query, pq.Array(productIDs))
+	if err != nil {
+		if isInvalidUUID(err) {
+			return []*entity.ProductVariant{}, nil
+		}
+		return nil, fmt.Errorf("failed to fetch product variants: %w", err)
+	}
+	defer rows.Close()
+
+	variants := []*entity.ProductVariant{}
+	for rows.Next() {
+		v, err := scanVariant(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product variant row: %w", err)
+		}
+		variants = append(variants, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return variants, nil
+}
+
+func (vp *ProductVariantPostgres) Update(ctx context.Context, v *entity.ProductVariant) error {
+	options, err := marshalOptions(v.Options)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	UPDATE product_variants
+	SET sku = $1, options = $2, price_cents = $3, stock = $4, updated_at = NOW()
+	WHERE id = $5
+	RETURNING updated_at;`
+	err = vp.queryRowContext(ctx, query, v.SKU, options, nullInt64(v.PriceCents), v.Stock, v.ID).Scan(&v.UpdatedAt)
+	if conflict := uniqueViolation(err); conflict != nil {
+		return conflict
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.ErrVariantNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update product variant: %w", err)
+	}
+	return nil
+}
+
+func (vp *ProductVariantPostgres) Delete(ctx context.Context, id string) error {
+	res, err := vp.execContext(ctx, `DELETE FROM product_variants WHERE id = $1`, id)
+	if isForeignKeyViolation(err) {
+		return repository.ErrVariantInUse
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete product variant: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return repository.ErrVariantNotFound
+	}
+	return nil
+}
+
+func (vp *ProductVariantPostgres) SetDefaultStock(ctx context.Context, productID string, stock int) error {
+	query := `UPDATE product_variants SET stock = $1, updated_at = NOW()
+	WHERE product_id = $2 AND is_default`
+	res, err := vp.execContext(ctx, query, stock, productID)
+	if err != nil {
+		return fmt.Errorf("failed to set default variant stock for product %s: %w", productID, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return repository.ErrVariantNotFound
+	}
+	return nil
+}
+
+func (vp *ProductVariantPostgres) ReserveStock(ctx context.Context, productID, id string, quantity int) (*entity.ProductVariant, error) {
+	// Décrément conditionnel, comme pour les produits. La condition sur
+	// product_id évite de verrouiller la variante d'un produit non verrouillé.
+	query := `
+	UPDATE product_variants
+	SET stock = stock - $1, updated_at = NOW()
+	WHERE id = $2 AND product_id = $3 AND stock >= $1
+	RETURNING ` + variantColumns
+	reserved, err := scanVariant(vp.queryRowContext(ctx, query, quantity, id, productID))
+	if err == nil {
+		return reserved, nil
+	}
+	if isInvalidUUID(err) {
+		return nil, repository.ErrVariantNotFound
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to reserve stock for variant %s: %w", id, err)
+	}
+
+	// Aucune ligne : variante absente ou stock insuffisant
+	var available int
+	err = vp.queryRowContext(ctx, `SELECT stock FROM product_variants WHERE id = $1 AND product_id = $2`, id, productID).Scan(&available)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrVariantNotFound
+		}
+		return nil, fmt.Errorf("failed to read stock for variant %s: %w", id, err)
+	}
+
+	return nil, fmt.Errorf("variant %s has %d in stock, %d requested: %w", id, available, quantity, repository.ErrInsufficientStock)
+}
+
+func (vp *ProductVariantPostgres) RestoreStock(ctx context.Context, id string, quantity int) error {
+	query := `UPDATE product_variants SET stock = stock + $1, updated_at = NOW() WHERE id = $2`
+	res, err := vp.execContext(ctx, query, quantity, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore stock for variant %s: %w", id, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return repository.ErrVariantNotFound
+	}
+	return nil
+}
+
+func (vp *ProductVariantPostgres) LockProduct(ctx context.Context, productID string) error {
+	var id string
+	err := vp.queryRowContext(ctx, `SELECT id FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, productID).Scan(&id)
+	if isInvalidUUID(err) {
+		return fmt.Errorf("product %s not found: %w", productID, sql.ErrNoRows)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock product %s: %w", productID, err)
+	}
+	return nil
+}
+
+func (vp *ProductVariantPostgres) RefreshProductStock(ctx context.Context, productID string) (int, error) {
+	query := `
+	UPDATE products
+	SET stock = (SELECT COALESCE(SUM(stock), 0) FROM product_variants WHERE product_id = $1),
+		updated_at = NOW()
+	WHERE id = $1
+	RETURNING stock;`
+
+	var stock int
+	if err := vp.queryRowContext(ctx, query, productID).Scan(&stock); err != nil {
+		return 0, fmt.Errorf("failed to refresh stock of product %s: %w", productID, err)
+	}
+	return stock, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanVariant(row rowScanner) (*entity.ProductVariant, error) {
+	v := &entity.ProductVariant{}
+	var (
+		options []byte
+		price   sql.NullInt64
+	)
+	err := row.Scan(&v.ID, &v.ProductID, &v.SKU, &options, &price, &v.Stock, &v.IsDefault, &v.CreatedAt, &v.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(options, &v.Options); err != nil {
+		return nil, fmt.Errorf("invalid options for variant %s: %w", v.ID, err)
+	}
+	if price.Valid {
+		v.PriceCents = &price.Int64
+	}
+	return v, nil
+}
+
+func marshalOptions(options map[string]string) ([]byte, error) {
+	if options == nil {
+		options = map[string]string{}
+	}
+	raw, err := json.Marshal(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode variant options: %w", err)
+	}
+	return raw, nil
+}
+
+func nullInt64(v *int64) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *v, Valid: true}
+}
+
+// uniqueViolation traduit une violation d'unicité en erreur du domaine (nil sinon)
+func uniqueViolation(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != "23505" {
+		return nil
+	}
+	if pqErr.Constraint == optionsConstraint {
+		return repository.ErrVariantOptionsTaken
+	}
+	return repository.ErrVariantSKUTaken
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
+
+// isInvalidUUID : identifiant qui n'est pas un UUID (invalid_text_representation)
+func isInvalidUUID(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "22P02"
+}
@@ -0,0 +1,149 @@
+package productvariant_test
+
+import (
+	"Goshop/domain/entity"
+	"Goshop/domain/repository"
+	productvariant "Goshop/infrastructure/postgres/product_variant"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var variantColumns = []string{"id", "product_id", "sku", "options", "price_cents", "stock", "is_default", "created_at", "updated_at"}
+
+func TestProductVariantPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := productvariant.NewProductVariantPostgres(db)
+	now := time.Now()
+	price := int64(2500)
+
+	mock.ExpectQuery(`INSERT INTO product_variants`).
+		WithArgs("p-1", "TSHIRT-M-BLEU", []byte(`{"colour":"bleu","size":"M"}`), sql.NullInt64{Int64: 2500, Valid: true}, 4, false).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow("v-2", now, now))
+	mock.ExpectQuery(`INSERT INTO product_variants`).
+		WithArgs("p-1", "TSHIRT-M-BLEU-2", []byte(`{"colour":"bleu","size":"M"}`), sql.NullInt64{}, 1, false).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "product_variants_options_key"})
+	mock.ExpectQuery(`INSERT INTO product_variants`).
+		WithArgs("p-1", "TSHIRT-M-BLEU", []byte(`{}`), sql.NullInt64{}, 0, true).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "product_variants_sku_key"})
+
+	v := &entity.ProductVariant{ProductID: "p-1", SKU: "TSHIRT-M-BLEU", Options: map[string]string{"size": "M", "colour": "bleu"}, PriceCents: &price, Stock: 4}
+	require.NoError(t, repo.Create(context.Background(), v))
+	assert.Equal(t, "v-2", v.ID)
+
+	err = repo.Create(context.Background(), &entity.ProductVariant{ProductID: "p-1", SKU: "TSHIRT-M-BLEU-2", Options: map[string]string{"size": "M", "colour": "bleu"}, Stock: 1})
+	assert.ErrorIs(t, err, repository.ErrVariantOptionsTaken)
+
+	err = repo.Create(context.Background(), &entity.ProductVariant{ProductID: "p-1", SKU: "TSHIRT-M-BLEU", IsDefault: true})
+	assert.ErrorIs(t, err, repository.ErrVariantSKUTaken)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductVariantPostgres_FindByProducts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := productvariant.NewProductVariantPostgres(db)
+	now := time.Now()
+	mock.ExpectQuery(`FROM product_variants\s+WHERE product_id = ANY\(\$1::uuid\[\]\)`).
+		WithArgs(pq.Array([]string{"p-1"})).
+		WillReturnRows(sqlmock.NewRows(variantColumns).
+			AddRow("v-1", "p-1", "SKU-p-1", []byte(`{}`), nil, 3, true, now, now).
+			AddRow("v-2", "p-1", "TSHIRT-L", []byte(`{"size":"L"}`), int64(2900), 2, false, now, now))
+
+	variants, err := repo.FindByProducts(context.Background(), []string{"p-1"})
+	require.NoError(t, err)
+	require.Len(t, variants, 2)
+	assert.Nil(t, variants[0].PriceCents)
+	assert.Empty(t, variants[0].Options)
+	assert.Equal(t, map[string]string{"size": "L"}, variants[1].Options)
+	assert.Equal(t, int64(2900), variants[1].EffectivePrice(2000))
+
+	empty, err := repo.FindByProducts(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductVariantPostgres_ReserveStock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := productvariant.NewProductVariantPostgres(db)
+	now := time.Now()
+
+	mock.ExpectQuery(`UPDATE product_variants\s+SET stock = stock - \$1`).
+		WithArgs(2, "v-1", "p-1").
+		WillReturnRows(sqlmock.NewRows(variantColumns).AddRow("v-1", "p-1", "SKU-p-1", []byte(`{}`), nil, 1, true, now, now))
+	// Stock insuffisant
+	mock.ExpectQuery(`UPDATE product_variants\s+SET stock = stock - \$1`).
+		WithArgs(5, "v-1", "p-1").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`SELECT stock FROM product_variants WHERE id = \$1 AND product_id = \$2`).
+		WithArgs("v-1", "p-1").
+		WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(1))
+	// Variante d'un autre produit
+	mock.ExpectQuery(`UPDATE product_variants\s+SET stock = stock - \$1`).
+		WithArgs(1, "v-9", "p-1").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`SELECT stock FROM product_variants WHERE id = \$1 AND product_id = \$2`).
+		WithArgs("v-9", "p-1").
+		WillReturnError(sql.ErrNoRows)
+
+	v, err := repo.ReserveStock(context.Background(), "p-1", "v-1", 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, v.Stock)
+	assert.Equal(t, "p-1", v.ProductID)
+
+	_, err = repo.ReserveStock(context.Background(), "p-1", "v-1", 5)
+	assert.ErrorIs(t, err, repository.ErrInsufficientStock)
+
+	_, err = repo.ReserveStock(context.Background(), "p-1", "v-9", 1)
+	assert.ErrorIs(t, err, repository.ErrVariantNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductVariantPostgres_DeleteInUse(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := productvariant.NewProductVariantPostgres(db)
+	mock.ExpectExec(`DELETE FROM product_variants WHERE id = \$1`).
+		WithArgs("v-2").
+		WillReturnError(&pq.Error{Code: "23503"})
+	mock.ExpectExec(`DELETE FROM product_variants WHERE id = \$1`).
+		WithArgs("v-9").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, repo.Delete(context.Background(), "v-2"), repository.ErrVariantInUse)
+	assert.ErrorIs(t, repo.Delete(context.Background(), "v-9"), repository.ErrVariantNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductVariantPostgres_RefreshProductStock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := productvariant.NewProductVariantPostgres(db)
+	mock.ExpectQuery(`UPDATE products\s+SET stock = \(SELECT COALESCE\(SUM\(stock\), 0\) FROM product_variants WHERE product_id = \$1\)`).
+		WithArgs("p-1").
+		WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(7))
+
+	stock, err := repo.RefreshProductStock(context.Background(), "p-1")
+	require.NoError(t, err)
+	assert.Equal(t, 7, stock)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
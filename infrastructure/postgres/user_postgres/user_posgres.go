@@ -5,9 +5,23 @@ import (
 	userrepository "Goshop/domain/repository/user_repository"
 	"database/sql"
 	"errors"
+	"fmt"
 	"strings"
+
+	"github.com/lib/pq"
 )
 
+// userAccessColumns charge les rôles et les permissions avec l'utilisateur
+const userAccessColumns = `
+            ARRAY(SELECT ur.role FROM user_roles ur WHERE ur.user_id = u.id ORDER BY ur.role) AS roles,
+            ARRAY(
+                SELECT DISTINCT rp.permission
+                FROM user_roles ur
+                JOIN role_permissions rp ON rp.role = ur.role
+                WHERE ur.user_id = u.id
+                ORDER BY rp.permission
+            ) AS permissions`
+
 type UserPostgres struct {
 	db *sql.DB
 }
@@ -18,18 +32,38 @@ func NewUserPostgres(db *sql.DB) userrepository.UserRepository {
 
 func (ur *UserPostgres) CreateUser(user *userentity.UserEntity) (*userentity.UserEntity, error) {
 
+	// L'utilisateur, son rôle par défaut et son profil client sont créés dans
+	// la même instruction. Le profil est toujours neuf : un client existant de
+	// même email n'est rattaché qu'après confirmation (MarkEmailVerified).
 	query := `
-        INSERT INTO users (id, email, password)
-        VALUES ($1, $2, $3)
-        RETURNING id, email, password
+        WITH u AS (
+            INSERT INTO users (id, email, password)
+            VALUES ($1, $2, $3)
+            RETURNING id, email, password
+        ), r AS (
+            INSERT INTO user_roles (user_id, role)
+            SELECT id, $4 FROM u
+        ), c AS (
+            INSERT INTO customers (first_name, last_name, email, user_id)
+            SELECT $5, $6, email, id FROM u
+            RETURNING id
+        )
+        SELECT u.id, u.email, u.password, (SELECT id FROM c) FROM u
     `
-	row := ur.db.QueryRow(query, user.ID, user.Email, user.Password)
+	tx, err := ur.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin user creation: %w", err)
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRow(query, user.ID, user.Email, user.Password, userentity.DefaultRole, user.FirstName, user.LastName)
 
 	var out userentity.UserEntity
-	err := row.Scan(&out.ID, &out.Email, &out.Password)
+	var customerID sql.NullString
+	err = row.Scan(&out.ID, &out.Email, &out.Password, &customerID)
 	if err != nil {
 
-		// email déjà utilisé → contrainte UNIQUE
+		// email déjà utilisé → contrainte UNIQUE (compte, ou profil client rattaché)
 		if strings.Contains(err.Error(), "users_email_key") ||
 			strings.Contains(err.Error(), "duplicate key") {
 			return nil, userrepository.ErrUserAlreadyExists
@@ -37,6 +71,16 @@ func (ur *UserPostgres) CreateUser(user *userentity.UserEntity) (*userentity.Use
 
 		return nil, err
 	}
+	// Un compte sans profil client ne pourrait pas commander
+	if !customerID.Valid {
+		return nil, fmt.Errorf("customer profile not created for user %s", out.ID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit user creation: %w", err)
+	}
+	out.Roles = []string{userentity.DefaultRole}
+	out.FirstName, out.LastName = user.FirstName, user.LastName
 
 	return &out, nil
 }
@@ -44,14 +88,14 @@ func (ur *UserPostgres) CreateUser(user *userentity.UserEntity) (*userentity.Use
 func (ur *UserPostgres) FindUserByEmail(email string) (*userentity.UserEntity, error) {
 
 	query := `
-        SELECT id, email, password
-        FROM users
-        WHERE email = $1
+        SELECT u.id, u.email, u.password,` + userAccessColumns + `
+        FROM users u
+        WHERE LOWER(u.email) = LOWER($1)
     `
 	row := ur.db.QueryRow(query, email)
 
 	var out userentity.UserEntity
-	err := row.Scan(&out.ID, &out.Email, &out.Password)
+	err := row.Scan(&out.ID, &out.Email, &out.Password, pq.Array(&out.Roles), pq.Array(&out.Permissions))
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -66,14 +110,14 @@ func (ur *UserPostgres) FindUserByEmail(email string) (*userentity.UserEntity, e
 func (ur *UserPostgres) FindUserByID(id string) (*userentity.UserEntity, error) {
 
 	query := `
-        SELECT id, email, password
-        FROM users
-        WHERE id = $1
+        SELECT u.id, u.email, u.password,` + userAccessColumns + `
+        FROM users u
+        WHERE u.id = $1
     `
 	row := ur.db.QueryRow(query, id)
 
 	var out userentity.UserEntity
-	err := row.Scan(&out.ID, &out.Email, &out.Password)
+	err := row.Scan(&out.ID, &out.Email, &out.Password, pq.Array(&out.Roles), pq.Array(&out.Permissions))
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -84,3 +128,178 @@ func (ur *UserPostgres) FindUserByID(id string) (*userentity.UserEntity, error)
 
 	return &out, nil
 }
+
+func (ur *UserPostgres) GrantRole(userID, role, grantedBy string) error {
+	query := `
+        INSERT INTO user_roles (user_id, role, granted_by)
+        VALUES ($1, $2, NULLIF($3, ''))
+        ON CONFLICT (user_id, role) DO NOTHING
+    `
+	_, err := ur.db.Exec(query, userID, role, grantedBy)
+	if err != nil {
+		// Violation de clé étrangère : rôle ou utilisateur inconnu
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			if pqErr.Constraint == "user_roles_role_fkey" {
+				return userrepository.ErrRoleNotFound
+			}
+			return userrepository.ErrUserNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (ur *UserPostgres) RevokeRole(userID, role string) error {
+	query := `DELETE FROM user_roles WHERE user_id = $1 AND role = $2`
+	_, err := ur.db.Exec(query, userID, role)
+	return err
+}
+
+func (ur *UserPostgres) UpdatePassword(userID, passwordHash string) error {
+	query := `UPDATE users SET password = $2, updated_at = NOW() WHERE id = $1`
+	return execOnUser(ur.db, query, userID, passwordHash)
+}
+
+// MarkEmailVerified enregistre la confirmation dans une transaction et
+// rattache au compte le client non rattaché de même email (créé par le
+// back-office) : le profil créé à l'inscription lui cède ses
+// commandes puis est supprimé. Le rattachement exige donc la preuve que
+// l'utilisateur détient l'email.
+func (ur *UserPostgres) MarkEmailVerified(userID string) error {
+	tx, err := ur.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin email verification: %w", err)
+	}
+	defer tx.Rollback()
+
+	var email string
+	err = tx.QueryRow(`
+        UPDATE users
+        SET email_verified_at = COALESCE(email_verified_at, NOW()), updated_at = NOW()
+        WHERE id = $1
+        RETURNING email
+    `, userID).Scan(&email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return userrepository.ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to mark email verified: %w", err)
+	}
+
+	if err := linkCustomerByEmail(tx, userID, email); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// linkCustomerByEmail rattache au compte le client actif, non rattaché, de
+// même email (sans tenir compte de la casse). Sans client à rattacher, rien
+// ne change.
+func linkCustomerByEmail(tx *sql.Tx, userID, email string) error {
+	var existingID string
+	err := tx.QueryRow(`
+        SELECT id FROM customers
+        WHERE user_id IS NULL AND deleted_at IS NULL AND LOWER(email) = LOWER($1)
+        ORDER BY created_at
+        LIMIT 1
+        FOR UPDATE
+    `, email).Scan(&existingID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find customer to link: %w", err)
+	}
+
+	// Profil créé à l'inscription : ses commandes passent au client existant
+	var profileID string
+	err = tx.QueryRow(`SELECT id FROM customers WHERE user_id = $1 FOR UPDATE`, userID).Scan(&profileID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return fmt.Errorf("failed to find customer profile: %w", err)
+	default:
+		if _, err := tx.Exec(`UPDATE orders SET customer_id = $1, updated_at = NOW() WHERE customer_id = $2`, existingID, profileID); err != nil {
+			return fmt.Errorf("failed to move orders to linked customer: %w", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM customers WHERE id = $1`, profileID); err != nil {
+			return fmt.Errorf("failed to delete registration customer profile: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE customers SET user_id = $1, updated_at = NOW() WHERE id = $2`, userID, existingID); err != nil {
+		return fmt.Errorf("failed to link customer: %w", err)
+	}
+	return nil
+}
+
+// DeleteUser efface le compte dans une transaction :
+//   - le profil client est anonymisé et détaché ; ses commandes restent
+//     (obligations comptables) ;
+//   - sessions, compteurs de login et réponses idempotentes mémorisées
+//     (données personnelles sans clé étrangère) sont supprimés ;
+//   - rôles, jetons email et second facteur suivent l'utilisateur (ON DELETE CASCADE).
+func (ur *UserPostgres) DeleteUser(userID string) error {
+	tx, err := ur.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin account deletion: %w", err)
+	}
+	defer tx.Rollback()
+
+	cleanup := []struct {
+		name  string
+		query string
+	}{
+		{"customer", `
+        UPDATE customers
+        SET first_name = 'Deleted', last_name = 'User',
+            email = 'deleted-' || id || '@anonymized.invalid',
+            phone = NULL, user_id = NULL, updated_at = NOW()
+        WHERE user_id = $1
+    `},
+		{"login attempts", `
+        DELETE FROM login_attempts
+        WHERE key = 'account:' || LOWER((SELECT email FROM users WHERE id = $1))
+    `},
+		{"refresh sessions", `DELETE FROM refresh_sessions WHERE user_id = $1`},
+		{"idempotency keys", `DELETE FROM idempotency_keys WHERE scope = $1`},
+	}
+	for _, step := range cleanup {
+		if _, err := tx.Exec(step.query, userID); err != nil {
+			return fmt.Errorf("failed to erase %s: %w", step.name, err)
+		}
+	}
+
+	res, err := tx.Exec(`DELETE FROM users WHERE id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return userrepository.ErrUserNotFound
+	}
+
+	return tx.Commit()
+}
+
+// execOnUser exécute une mise à jour ciblant un utilisateur ; aucune ligne
+// modifiée → ErrUserNotFound
+func execOnUser(db *sql.DB, query string, args ...any) error {
+	res, err := db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return userrepository.ErrUserNotFound
+	}
+	return nil
+}
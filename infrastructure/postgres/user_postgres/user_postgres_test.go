@@ -0,0 +1,157 @@
+package userpostgres_test
+
+import (
+	userentity "Goshop/domain/entity/user_entity"
+	userrepository "Goshop/domain/repository/user_repository"
+	userpostgres "Goshop/infrastructure/postgres/user_postgres"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserPostgres_DeleteUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := userpostgres.NewUserPostgres(db)
+
+	mock.ExpectBegin()
+	// Le client est anonymisé, pas supprimé : ses commandes restent
+	mock.ExpectExec(`UPDATE customers\s+SET first_name = 'Deleted'`).WithArgs("user-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM login_attempts`).WithArgs("user-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM refresh_sessions WHERE user_id = \$1`).WithArgs("user-1").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`DELETE FROM idempotency_keys WHERE scope = \$1`).WithArgs("user-1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM users WHERE id = \$1`).WithArgs("user-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.DeleteUser("user-1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserPostgres_DeleteUser_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := userpostgres.NewUserPostgres(db)
+
+	mock.ExpectBegin()
+	for i := 0; i < 4; i++ {
+		mock.ExpectExec(`.`).WithArgs("ghost").WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	mock.ExpectExec(`DELETE FROM users`).WithArgs("ghost").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, repo.DeleteUser("ghost"), userrepository.ErrUserNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserPostgres_DeleteUser_RollbackOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := userpostgres.NewUserPostgres(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE customers`).WithArgs("user-1").WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	assert.Error(t, repo.DeleteUser("user-1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserPostgres_CreateUser_AlwaysCreatesProfile(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := userpostgres.NewUserPostgres(db)
+
+	// Aucun ON CONFLICT : un client existant de même email n'est pas rattaché
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO customers \(first_name, last_name, email, user_id\)\s+SELECT \$5, \$6, email, id FROM u\s+RETURNING id\s+\)`).
+		WithArgs("user-1", "new@mail.com", "hash", userentity.DefaultRole, "New", "User").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password", "customer_id"}).
+			AddRow("user-1", "new@mail.com", "hash", "cust-1"))
+	mock.ExpectCommit()
+
+	user, err := repo.CreateUser(&userentity.UserEntity{ID: "user-1", Email: "new@mail.com", Password: "hash", FirstName: "New", LastName: "User"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", user.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserPostgres_CreateUser_ProfileMissing(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := userpostgres.NewUserPostgres(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`WITH u AS`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password", "customer_id"}).
+			AddRow("user-1", "new@mail.com", "hash", nil))
+	mock.ExpectRollback()
+
+	_, err = repo.CreateUser(&userentity.UserEntity{ID: "user-1", Email: "new@mail.com", Password: "hash"})
+
+	assert.ErrorContains(t, err, "customer profile not created")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserPostgres_MarkEmailVerified_LinksExistingCustomer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := userpostgres.NewUserPostgres(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE users\s+SET email_verified_at`).WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"email"}).AddRow("bob@x.com"))
+	mock.ExpectQuery(`SELECT id FROM customers\s+WHERE user_id IS NULL AND deleted_at IS NULL AND LOWER\(email\) = LOWER\(\$1\)`).
+		WithArgs("bob@x.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("cust-old"))
+	mock.ExpectQuery(`SELECT id FROM customers WHERE user_id = \$1 FOR UPDATE`).WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("cust-new"))
+	mock.ExpectExec(`UPDATE orders SET customer_id = \$1`).WithArgs("cust-old", "cust-new").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`DELETE FROM customers WHERE id = \$1`).WithArgs("cust-new").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE customers SET user_id = \$1`).WithArgs("user-1", "cust-old").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.MarkEmailVerified("user-1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserPostgres_MarkEmailVerified_NothingToLink(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := userpostgres.NewUserPostgres(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE users`).WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"email"}).AddRow("bob@x.com"))
+	mock.ExpectQuery(`SELECT id FROM customers`).WithArgs("bob@x.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.MarkEmailVerified("user-1"))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE users`).WithArgs("ghost").WillReturnRows(sqlmock.NewRows([]string{"email"}))
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, repo.MarkEmailVerified("ghost"), userrepository.ErrUserNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
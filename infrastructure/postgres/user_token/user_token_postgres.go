@@ -0,0 +1,65 @@
+package usertoken
+
+import (
+	authentity "Goshop/domain/auth_entity"
+	authrepository "Goshop/domain/repository/auth_repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type UserTokenPostgres struct {
+	db *sql.DB
+}
+
+func NewUserTokenPostgres(db *sql.DB) authrepository.UserTokenRepository {
+	return &UserTokenPostgres{db: db}
+}
+
+func (p *UserTokenPostgres) Create(ctx context.Context, token *authentity.UserToken) error {
+	query := `
+	INSERT INTO user_tokens (token_hash, user_id, purpose, expires_at)
+	VALUES ($1, $2, $3, $4)
+	RETURNING created_at
+	`
+	err := p.db.QueryRowContext(ctx, query, token.TokenHash, token.UserID, token.Purpose, token.ExpiresAt).
+		Scan(&token.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create user token: %w", err)
+	}
+	return nil
+}
+
+// Consume : la condition used_at IS NULL rend l'UPDATE atomique, un jeton ne
+// sert qu'une fois même sous requêtes concurrentes
+func (p *UserTokenPostgres) Consume(ctx context.Context, tokenHash, purpose string) (*authentity.UserToken, error) {
+	query := `
+	UPDATE user_tokens SET used_at = NOW()
+	WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+	RETURNING token_hash, user_id, purpose, expires_at, used_at, created_at
+	`
+	var token authentity.UserToken
+	err := p.db.QueryRowContext(ctx, query, tokenHash, purpose).Scan(
+		&token.TokenHash, &token.UserID, &token.Purpose, &token.ExpiresAt, &token.UsedAt, &token.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, authrepository.ErrUserTokenInvalid
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume user token: %w", err)
+	}
+	return &token, nil
+}
+
+// RevokeForUser : les jetons révoqués sont marqués utilisés, comme après Consume
+func (p *UserTokenPostgres) RevokeForUser(ctx context.Context, userID, purpose string) error {
+	query := `
+	UPDATE user_tokens SET used_at = NOW()
+	WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+	`
+	if _, err := p.db.ExecContext(ctx, query, userID, purpose); err != nil {
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package usertoken_test
+
+import (
+	authentity "Goshop/domain/auth_entity"
+	authrepository "Goshop/domain/repository/auth_repository"
+	usertoken "Goshop/infrastructure/postgres/user_token"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+const tokenHash = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+func TestUserTokenPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := usertoken.NewUserTokenPostgres(db)
+	expires := time.Now().Add(time.Hour)
+	created := time.Now()
+
+	mock.ExpectQuery(`INSERT INTO user_tokens`).
+		WithArgs(tokenHash, "user-1", authentity.UserTokenPasswordReset, expires).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(created))
+
+	token := &authentity.UserToken{TokenHash: tokenHash, UserID: "user-1", Purpose: authentity.UserTokenPasswordReset, ExpiresAt: expires}
+	err = repo.Create(context.Background(), token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, created, token.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserTokenPostgres_Consume(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := usertoken.NewUserTokenPostgres(db)
+	now := time.Now()
+
+	mock.ExpectQuery(`UPDATE user_tokens SET used_at = NOW\(\)\s+WHERE token_hash = \$1 AND purpose = \$2 AND used_at IS NULL AND expires_at > NOW\(\)`).
+		WithArgs(tokenHash, authentity.UserTokenEmailVerification).
+		WillReturnRows(sqlmock.NewRows([]string{"token_hash", "user_id", "purpose", "expires_at", "used_at", "created_at"}).
+			AddRow(tokenHash, "user-1", authentity.UserTokenEmailVerification, now.Add(time.Hour), now, now.Add(-time.Hour)))
+
+	token, err := repo.Consume(context.Background(), tokenHash, authentity.UserTokenEmailVerification)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", token.UserID)
+	assert.Equal(t, now, token.UsedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserTokenPostgres_Consume_InvalidToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := usertoken.NewUserTokenPostgres(db)
+
+	// Inconnu, expiré ou déjà utilisé : aucune ligne mise à jour
+	mock.ExpectQuery(`UPDATE user_tokens SET used_at`).
+		WithArgs(tokenHash, authentity.UserTokenPasswordReset).
+		WillReturnRows(sqlmock.NewRows([]string{"token_hash", "user_id", "purpose", "expires_at", "used_at", "created_at"}))
+
+	_, err = repo.Consume(context.Background(), tokenHash, authentity.UserTokenPasswordReset)
+
+	assert.ErrorIs(t, err, authrepository.ErrUserTokenInvalid)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserTokenPostgres_RevokeForUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := usertoken.NewUserTokenPostgres(db)
+
+	mock.ExpectExec(`UPDATE user_tokens SET used_at = NOW\(\)\s+WHERE user_id = \$1 AND purpose = \$2 AND used_at IS NULL`).
+		WithArgs("user-1", authentity.UserTokenPasswordReset).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	assert.NoError(t, repo.RevokeForUser(context.Background(), "user-1", authentity.UserTokenPasswordReset))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,141 @@
+// Package redisinfra regroupe les implémentations Redis des repositories.
+package redisinfra
+
+import (
+	authentity "Goshop/domain/auth_entity"
+	authrepository "Goshop/domain/repository/auth_repository"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+// Un accès Redis ne doit pas ralentir le login : au-delà, on passe par le repli
+const loginAttemptRedisTimeout = 100 * time.Millisecond
+
+// Champs du hash "login:attempts:<clé>" (dates en millisecondes Unix)
+const (
+	fieldFailures    = "failures"
+	fieldLastFailure = "last_failure"
+	fieldLockedUntil = "locked_until"
+)
+
+// registerFailureScript : incrément et expiration atomiques. Le hash expire
+// `window` après le dernier échec, jamais avant la fin d'un verrouillage.
+var registerFailureScript = redis.NewScript(`
+local failures = redis.call('HINCRBY', KEYS[1], 'failures', 1)
+redis.call('HSET', KEYS[1], 'last_failure', ARGV[1])
+local locked = tonumber(redis.call('HGET', KEYS[1], 'locked_until') or '0')
+local expires = tonumber(ARGV[1]) + tonumber(ARGV[2])
+if locked > expires then expires = locked end
+redis.call('PEXPIREAT', KEYS[1], expires)
+return {failures, locked}
+`)
+
+// lockScript : le verrouillage prolonge l'expiration du hash si nécessaire
+var lockScript = redis.NewScript(`
+redis.call('HSET', KEYS[1], 'locked_until', ARGV[1])
+local ttl = redis.call('PTTL', KEYS[1])
+local wanted = tonumber(ARGV[1]) - tonumber(ARGV[2])
+if ttl < wanted then redis.call('PEXPIRE', KEYS[1], wanted) end
+return 1
+`)
+
+// LoginAttemptRedis : compteurs partagés entre les replicas. Redis en erreur
+// → l'opération passe par le repli (Postgres) pour ne pas désactiver la protection.
+type LoginAttemptRedis struct {
+	rdb      *redis.Client
+	fallback authrepository.LoginAttemptRepository
+	now      func() time.Time
+}
+
+// NewLoginAttemptRedis retourne directement le repli si Redis n'est pas configuré
+func NewLoginAttemptRedis(rdb *redis.Client, fallback authrepository.LoginAttemptRepository) authrepository.LoginAttemptRepository {
+	if rdb == nil {
+		return fallback
+	}
+	return &LoginAttemptRedis{rdb: rdb, fallback: fallback, now: time.Now}
+}
+
+func loginAttemptKey(key string) string {
+	return "login:attempts:" + key
+}
+
+func (r *LoginAttemptRedis) Get(ctx context.Context, key string) (authentity.LoginAttempts, error) {
+	rctx, cancel := context.WithTimeout(ctx, loginAttemptRedisTimeout)
+	defer cancel()
+
+	fields, err := r.rdb.HGetAll(rctx, loginAttemptKey(key)).Result()
+	if err != nil {
+		r.degraded(ctx, err, "get")
+		return r.fallback.Get(ctx, key)
+	}
+
+	failures, _ := strconv.Atoi(fields[fieldFailures])
+	return authentity.LoginAttempts{
+		Failures:    failures,
+		LastFailure: fromMillis(fields[fieldLastFailure]),
+		LockedUntil: fromMillis(fields[fieldLockedUntil]),
+	}, nil
+}
+
+func (r *LoginAttemptRedis) RegisterFailure(ctx context.Context, key string, window time.Duration) (authentity.LoginAttempts, error) {
+	rctx, cancel := context.WithTimeout(ctx, loginAttemptRedisTimeout)
+	defer cancel()
+
+	now := r.now()
+	res, err := registerFailureScript.Run(rctx, r.rdb, []string{loginAttemptKey(key)},
+		now.UnixMilli(), window.Milliseconds()).Int64Slice()
+	if err != nil || len(res) != 2 {
+		r.degraded(ctx, err, "register_failure")
+		return r.fallback.RegisterFailure(ctx, key, window)
+	}
+
+	attempts := authentity.LoginAttempts{Failures: int(res[0]), LastFailure: now}
+	if res[1] > 0 {
+		attempts.LockedUntil = time.UnixMilli(res[1])
+	}
+	return attempts, nil
+}
+
+func (r *LoginAttemptRedis) Lock(ctx context.Context, key string, until time.Time) error {
+	rctx, cancel := context.WithTimeout(ctx, loginAttemptRedisTimeout)
+	defer cancel()
+
+	err := lockScript.Run(rctx, r.rdb, []string{loginAttemptKey(key)},
+		until.UnixMilli(), r.now().UnixMilli()).Err()
+	if err != nil {
+		r.degraded(ctx, err, "lock")
+		return r.fallback.Lock(ctx, key, until)
+	}
+	return nil
+}
+
+// Reset efface aussi la clé côté repli : elle a pu y être écrite pendant une
+// indisponibilité de Redis
+func (r *LoginAttemptRedis) Reset(ctx context.Context, key string) error {
+	rctx, cancel := context.WithTimeout(ctx, loginAttemptRedisTimeout)
+	defer cancel()
+
+	if err := r.rdb.Del(rctx, loginAttemptKey(key)).Err(); err != nil {
+		r.degraded(ctx, err, "reset")
+	}
+	return r.fallback.Reset(ctx, key)
+}
+
+func (r *LoginAttemptRedis) degraded(ctx context.Context, err error, operation string) {
+	zerolog.Ctx(ctx).Warn().
+		Err(err).
+		Str("operation", "login_attempts_"+operation).
+		Msg("Redis indisponible, compteurs de connexion stockés en base")
+}
+
+func fromMillis(raw string) time.Time {
+	ms, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
@@ -0,0 +1,117 @@
+package redisinfra
+
+import (
+	"Goshop/domain/entity"
+	"Goshop/domain/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Une lecture du cache plus lente que la base n'a pas d'intérêt : au-delà,
+// l'appelant lit en base
+const productCacheRedisTimeout = 50 * time.Millisecond
+
+// ProductCacheRedis : produits sérialisés en JSON sous "product:<id>".
+// Toute erreur Redis est retournée telle quelle : l'appelant retombe sur la
+// base, le cache n'est jamais indispensable.
+type ProductCacheRedis struct {
+	rdb *redis.Client
+}
+
+// NewProductCacheRedis retourne nil si Redis n'est pas configuré (pas de cache)
+func NewProductCacheRedis(rdb *redis.Client) repository.ProductCache {
+	if rdb == nil {
+		return nil
+	}
+	return &ProductCacheRedis{rdb: rdb}
+}
+
+// cachedProduct : format stocké, découplé de l'entité
+type cachedProduct struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	PriceCents  int64     `json:"price_cents"`
+	Stock       int       `json:"stock"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func productCacheKey(id string) string {
+	return "product:" + id
+}
+
+func (c *ProductCacheRedis) Get(ctx context.Context, id string) (*entity.Product, error) {
+	rctx, cancel := context.WithTimeout(ctx, productCacheRedisTimeout)
+	defer cancel()
+
+	data, err := c.rdb.Get(rctx, productCacheKey(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, repository.ErrCacheMiss
+	}
+	if err != nil {
+		return nil, fmt.Errorf("product cache get: %w", err)
+	}
+	return decodeProduct(data)
+}
+
+func (c *ProductCacheRedis) Set(ctx context.Context, product *entity.Product, expirationSeconds int) error {
+	data, err := encodeProduct(product)
+	if err != nil {
+		return err
+	}
+
+	rctx, cancel := context.WithTimeout(ctx, productCacheRedisTimeout)
+	defer cancel()
+
+	ttl := time.Duration(expirationSeconds) * time.Second
+	if err := c.rdb.Set(rctx, productCacheKey(product.ID), data, ttl).Err(); err != nil {
+		return fmt.Errorf("product cache set: %w", err)
+	}
+	return nil
+}
+
+func (c *ProductCacheRedis) Delete(ctx context.Context, id string) error {
+	rctx, cancel := context.WithTimeout(ctx, productCacheRedisTimeout)
+	defer cancel()
+
+	if err := c.rdb.Del(rctx, productCacheKey(id)).Err(); err != nil {
+		return fmt.Errorf("product cache delete: %w", err)
+	}
+	return nil
+}
+
+func encodeProduct(p *entity.Product) ([]byte, error) {
+	return json.Marshal(cachedProduct{
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		PriceCents:  p.PriceCents,
+		Stock:       p.Stock,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	})
+}
+
+// decodeProduct : une entrée illisible (format d'une ancienne version) est
+// traitée comme absente et sera réécrite
+func decodeProduct(data []byte) (*entity.Product, error) {
+	var cp cachedProduct
+	if err := json.Unmarshal(data, &cp); err != nil || cp.ID == "" {
+		return nil, repository.ErrCacheMiss
+	}
+	return &entity.Product{
+		ID:          cp.ID,
+		Name:        cp.Name,
+		Description: cp.Description,
+		PriceCents:  cp.PriceCents,
+		Stock:       cp.Stock,
+		CreatedAt:   cp.CreatedAt,
+		UpdatedAt:   cp.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,58 @@
+package redisinfra
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"Goshop/domain/entity"
+	"Goshop/domain/repository"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductCache_EncodeDecode(t *testing.T) {
+	p := &entity.Product{
+		ID:          "p-1",
+		Name:        "MacBook Pro",
+		Description: "M3 Max",
+		PriceCents:  350000,
+		Stock:       5,
+		CreatedAt:   time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC),
+		UpdatedAt:   time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+	}
+
+	data, err := encodeProduct(p)
+	require.NoError(t, err)
+
+	decoded, err := decodeProduct(data)
+	require.NoError(t, err)
+	assert.Equal(t, p, decoded)
+}
+
+func TestProductCache_UnreadableEntryIsAMiss(t *testing.T) {
+	for _, data := range []string{"not json", `{"name":"no id"}`} {
+		_, err := decodeProduct([]byte(data))
+		assert.ErrorIs(t, err, repository.ErrCacheMiss, data)
+	}
+}
+
+func TestProductCache_NoRedis(t *testing.T) {
+	assert.Nil(t, NewProductCacheRedis(nil))
+}
+
+func TestProductCache_RedisDown(t *testing.T) {
+	// Port fermé : chaque opération échoue vite, sans être prise pour un miss
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer rdb.Close()
+	cache := NewProductCacheRedis(rdb)
+	ctx := context.Background()
+
+	_, err := cache.Get(ctx, "p-1")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, repository.ErrCacheMiss)
+	assert.Error(t, cache.Set(ctx, &entity.Product{ID: "p-1"}, 60))
+	assert.Error(t, cache.Delete(ctx, "p-1"))
+}
@@ -0,0 +1,86 @@
+package redisinfra
+
+import (
+	authrepository "Goshop/domain/repository/auth_repository"
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+// Un compteur ne doit pas ralentir la requête : au-delà, on passe par le repli
+const rateCounterRedisTimeout = 50 * time.Millisecond
+
+// RateCounterRedis : compteurs partagés entre les replicas, une clé Redis par
+// fenêtre ("ratelimit:<clé>:<début de fenêtre>"). Redis en erreur → compteur
+// en mémoire du replica : la limite reste appliquée, par replica.
+type RateCounterRedis struct {
+	rdb   *redis.Client
+	local *localRateCounter
+	now   func() time.Time
+}
+
+// NewRateCounterRedis : sans Redis configuré, seuls les compteurs en mémoire servent
+func NewRateCounterRedis(rdb *redis.Client) authrepository.RateCounter {
+	return &RateCounterRedis{rdb: rdb, local: newLocalRateCounter(), now: time.Now}
+}
+
+func (r *RateCounterRedis) Incr(ctx context.Context, key string, window time.Duration) (int64, error) {
+	start := r.now().Truncate(window)
+	if r.rdb == nil {
+		return r.local.incr(key, start, window), nil
+	}
+
+	rctx, cancel := context.WithTimeout(ctx, rateCounterRedisTimeout)
+	defer cancel()
+
+	redisKey := "ratelimit:" + key + ":" + strconv.FormatInt(start.Unix(), 10)
+	pipe := r.rdb.TxPipeline()
+	incr := pipe.Incr(rctx, redisKey)
+	pipe.ExpireAt(rctx, redisKey, start.Add(window))
+	if _, err := pipe.Exec(rctx); err != nil {
+		zerolog.Ctx(ctx).Warn().
+			Err(err).
+			Str("operation", "rate_counter_incr").
+			Msg("Redis indisponible, limite de débit appliquée par replica")
+		return r.local.incr(key, start, window), nil
+	}
+	return incr.Val(), nil
+}
+
+// localRateCounter : repli en mémoire ; les fenêtres passées sont purgées au fil de l'eau
+type localRateCounter struct {
+	mu      sync.Mutex
+	buckets map[string]*localRateBucket
+}
+
+type localRateBucket struct {
+	start time.Time
+	count int64
+}
+
+func newLocalRateCounter() *localRateCounter {
+	return &localRateCounter{buckets: make(map[string]*localRateBucket)}
+}
+
+func (c *localRateCounter) incr(key string, start time.Time, window time.Duration) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, ok := c.buckets[key]
+	if !ok || !b.start.Equal(start) {
+		// Nouvelle fenêtre : occasion de purger les compteurs expirés
+		for k, old := range c.buckets {
+			if old.start.Add(window).Before(start) {
+				delete(c.buckets, k)
+			}
+		}
+		b = &localRateBucket{start: start}
+		c.buckets[key] = b
+	}
+	b.count++
+	return b.count
+}
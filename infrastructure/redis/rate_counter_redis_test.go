@@ -0,0 +1,35 @@
+package redisinfra
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateCounter_LocalFixedWindow(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 10, 0, time.UTC)
+	counter := &RateCounterRedis{local: newLocalRateCounter(), now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	for want := int64(1); want <= 3; want++ {
+		n, err := counter.Incr(ctx, "apikey:a", time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, want, n)
+	}
+
+	// Compteurs indépendants par clé
+	n, _ := counter.Incr(ctx, "apikey:b", time.Minute)
+	assert.Equal(t, int64(1), n)
+
+	// Fenêtre suivante (alignée sur la minute) : remise à zéro
+	now = now.Add(50 * time.Second)
+	n, _ = counter.Incr(ctx, "apikey:a", time.Minute)
+	assert.Equal(t, int64(1), n)
+
+	// Les fenêtres périmées sont purgées
+	now = now.Add(5 * time.Minute)
+	counter.Incr(ctx, "apikey:a", time.Minute)
+	assert.NotContains(t, counter.local.buckets, "apikey:b")
+}
@@ -0,0 +1,135 @@
+package storageinfra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"Goshop/domain/storage"
+)
+
+// S3Config : bucket d'un stockage compatible S3 (AWS S3, MinIO, R2...)
+type S3Config struct {
+	// Endpoint : "https://s3.eu-west-3.amazonaws.com", "http://minio:9000"
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string // sensible
+}
+
+// S3BlobStore parle directement l'API REST S3 (PUT / GET / DELETE d'objet),
+// requêtes signées en AWS Signature V4. Les URLs sont en "path style"
+// (endpoint/bucket/clé), acceptées par AWS comme par MinIO.
+type S3BlobStore struct {
+	cfg    S3Config
+	base   *url.URL
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewS3BlobStore(cfg S3Config) (*S3BlobStore, error) {
+	if cfg.Endpoint == "" || cfg.Region == "" || cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, errors.New("s3 storage requires endpoint, region, bucket, access key and secret key")
+	}
+	base, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+
+	return &S3BlobStore{
+		cfg:    cfg,
+		base:   base,
+		client: &http.Client{Timeout: 30 * time.Second},
+		now:    time.Now,
+	}, nil
+}
+
+func (s *S3BlobStore) Put(ctx context.Context, key, contentType string, body io.Reader, size int64) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	// Corps non signé : il est envoyé en flux, sans être lu deux fois
+	resp, err := s.do(req, unsignedPayload)
+	if err != nil {
+		return fmt.Errorf("put blob %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("put blob %s: %w", key, responseError(resp))
+	}
+	return nil
+}
+
+func (s *S3BlobStore) Get(ctx context.Context, key string) (*storage.Blob, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, fmt.Errorf("get blob %s: %w", key, err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return &storage.Blob{Body: resp.Body, ContentType: resp.Header.Get("Content-Type"), Size: resp.ContentLength}, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, storage.ErrBlobNotFound
+	default:
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("get blob %s: %w", key, responseError(resp))
+	}
+}
+
+func (s *S3BlobStore) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return fmt.Errorf("delete blob %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	// S3 répond 204 même pour une clé absente, d'autres implémentations 404
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("delete blob %s: %w", key, responseError(resp))
+	}
+	return nil
+}
+
+func (s *S3BlobStore) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	if !storage.IsValidKey(key) {
+		return nil, fmt.Errorf("invalid blob key %q", key)
+	}
+
+	target := *s.base
+	target.Path = strings.TrimRight(s.base.Path, "/") + "/" + s.cfg.Bucket + "/" + key
+	return http.NewRequestWithContext(ctx, method, target.String(), body)
+}
+
+func (s *S3BlobStore) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	signV4(req, payloadHash, s.cfg.AccessKey, s.cfg.SecretKey, s.cfg.Region, s.now())
+	return s.client.Do(req)
+}
+
+// responseError résume une réponse d'erreur S3 (code HTTP et début du XML)
+func responseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("s3 responded %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
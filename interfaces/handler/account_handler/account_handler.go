@@ -0,0 +1,220 @@
+// interfaces/handler/account_handler/account_handler.go
+package accounthandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	userdto "Goshop/application/dto/user_dto"
+	"Goshop/interfaces/utils"
+
+	"github.com/rs/zerolog"
+)
+
+type PasswordResetUseCase interface {
+	RequestReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+}
+
+type EmailVerificationUseCase interface {
+	Verify(ctx context.Context, token string) error
+}
+
+type ChangePasswordUseCase interface {
+	Execute(ctx context.Context, userID, currentPassword, newPassword, refreshToken string) (int, error)
+}
+
+type DeleteAccountUseCase interface {
+	Execute(ctx context.Context, userID, password string) error
+}
+
+// AccountHandler : gestion du compte.
+//   - liens envoyés par email (mot de passe oublié, vérification de l'email) :
+//     routes publiques, le jeton du lien tient lieu d'authentification ;
+//   - changement de mot de passe et effacement du compte : routes protégées
+//     par AuthMiddleware, le mot de passe actuel est exigé.
+type AccountHandler struct {
+	passwordReset     PasswordResetUseCase
+	emailVerification EmailVerificationUseCase
+	changePassword    ChangePasswordUseCase
+	deleteAccount     DeleteAccountUseCase
+}
+
+func NewAccountHandler(
+	passwordReset PasswordResetUseCase,
+	emailVerification EmailVerificationUseCase,
+	changePassword ChangePasswordUseCase,
+	deleteAccount DeleteAccountUseCase,
+) *AccountHandler {
+	return &AccountHandler{
+		passwordReset:     passwordReset,
+		emailVerification: emailVerification,
+		changePassword:    changePassword,
+		deleteAccount:     deleteAccount,
+	}
+}
+
+// @Summary Forgot password
+// @Description Email a single-use password reset link. The response is the same whether or not the email is registered.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body userdto.ForgotPasswordRequest true "Account email"
+// @Success 202 {object} map[string]string "{'message': 'if this email is registered, a reset link has been sent'}"
+// @Failure 400 {object} utils.AppError "Invalid payload or email"
+// @Router /auth/password/forgot [post]
+func (h *AccountHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	var req userdto.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Invalid forgot password payload")
+		return utils.ErrInvalidPayload
+	}
+	if err := req.Validate(); err != nil {
+		return utils.ErrValidationFailed
+	}
+
+	if err := h.passwordReset.RequestReset(ctx, req.Email); err != nil {
+		return err
+	}
+
+	utils.WriteJSON(w, http.StatusAccepted, map[string]string{
+		"message": "if this email is registered, a reset link has been sent",
+	})
+	return nil
+}
+
+// @Summary Reset password
+// @Description Set a new password with the token of a reset link. Every refresh session of the account is revoked.
+// @Tags Authentication
+// @Accept json
+// @Param request body userdto.ResetPasswordRequest true "Reset token and new password"
+// @Success 204 "Password changed"
+// @Failure 400 {object} utils.AppError "Invalid payload, or invalid, expired or already used token"
+// @Router /auth/password/reset [post]
+func (h *AccountHandler) ResetPassword(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	var req userdto.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Invalid reset password payload")
+		return utils.ErrInvalidPayload
+	}
+	if err := req.Validate(); err != nil {
+		return utils.ErrValidationFailed
+	}
+
+	if err := h.passwordReset.ResetPassword(ctx, req.Token, req.Password); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// @Summary Verify email
+// @Description Confirm the account email with the token of a verification link
+// @Tags Authentication
+// @Accept json
+// @Param request body userdto.VerifyEmailRequest true "Verification token"
+// @Success 204 "Email verified"
+// @Failure 400 {object} utils.AppError "Invalid payload, or invalid, expired or already used token"
+// @Router /auth/email/verify [post]
+func (h *AccountHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	var req userdto.VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Invalid verify email payload")
+		return utils.ErrInvalidPayload
+	}
+	if err := req.Validate(); err != nil {
+		return utils.ErrValidationFailed
+	}
+
+	if err := h.emailVerification.Verify(ctx, req.Token); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// @Summary Change password
+// @Description Change the password of the current user. The session of the given refresh token (body or X-Refresh-Token header) is kept, every other session is revoked.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body userdto.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} map[string]int "{'revoked_sessions': 2}"
+// @Failure 400 {object} utils.AppError "Invalid payload or new password equal to the current one"
+// @Failure 401 {object} utils.AppError "Unauthorized"
+// @Failure 403 {object} utils.AppError "Wrong current password"
+// @Security ApiKeyAuth
+// @Router /auth/me/password [put]
+func (h *AccountHandler) ChangePassword(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return utils.ErrUnauthorized
+	}
+
+	var req userdto.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Invalid change password payload")
+		return utils.ErrInvalidPayload
+	}
+	if err := req.Validate(); err != nil {
+		return utils.ErrValidationFailed
+	}
+	if req.RefreshToken == "" {
+		req.RefreshToken = r.Header.Get("X-Refresh-Token")
+	}
+
+	revoked, err := h.changePassword.Execute(ctx, userID, req.CurrentPassword, req.NewPassword, req.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	utils.WriteJSON(w, http.StatusOK, map[string]int{"revoked_sessions": revoked})
+	return nil
+}
+
+// @Summary Delete account
+// @Description Erase the current user's account (GDPR). The linked customer profile is anonymised and its orders are kept for accounting; sessions are deleted.
+// @Tags Authentication
+// @Accept json
+// @Param request body userdto.DeleteAccountRequest true "Password confirmation"
+// @Success 204 "Account deleted"
+// @Failure 400 {object} utils.AppError "Invalid payload"
+// @Failure 401 {object} utils.AppError "Unauthorized"
+// @Failure 403 {object} utils.AppError "Wrong password"
+// @Security ApiKeyAuth
+// @Router /auth/me [delete]
+func (h *AccountHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return utils.ErrUnauthorized
+	}
+
+	var req userdto.DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Invalid delete account payload")
+		return utils.ErrInvalidPayload
+	}
+	if err := req.Validate(); err != nil {
+		return utils.ErrValidationFailed
+	}
+
+	if err := h.deleteAccount.Execute(ctx, userID, req.Password); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
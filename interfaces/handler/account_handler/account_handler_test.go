@@ -0,0 +1,183 @@
+package accounthandler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	accounthandler "Goshop/interfaces/handler/account_handler"
+	"Goshop/interfaces/middl"
+	"Goshop/interfaces/utils"
+)
+
+type fakePasswordReset struct {
+	requested []string
+	resetErr  error
+	token     string
+	password  string
+}
+
+func (f *fakePasswordReset) RequestReset(_ context.Context, email string) error {
+	f.requested = append(f.requested, email)
+	return nil
+}
+
+func (f *fakePasswordReset) ResetPassword(_ context.Context, token, newPassword string) error {
+	f.token, f.password = token, newPassword
+	return f.resetErr
+}
+
+type fakeEmailVerification struct {
+	verified []string
+	err      error
+}
+
+func (f *fakeEmailVerification) Verify(_ context.Context, token string) error {
+	f.verified = append(f.verified, token)
+	return f.err
+}
+
+type fakeChangePassword struct {
+	calls        []string
+	refreshToken string
+	err          error
+}
+
+func (f *fakeChangePassword) Execute(_ context.Context, userID, current, next, refreshToken string) (int, error) {
+	f.calls = append(f.calls, userID+" "+current+" "+next)
+	f.refreshToken = refreshToken
+	return 2, f.err
+}
+
+type fakeDeleteAccount struct {
+	deleted []string
+	err     error
+}
+
+func (f *fakeDeleteAccount) Execute(_ context.Context, userID, password string) error {
+	f.deleted = append(f.deleted, userID+" "+password)
+	return f.err
+}
+
+func newHandler(reset *fakePasswordReset, verification *fakeEmailVerification, change *fakeChangePassword, del *fakeDeleteAccount) *accounthandler.AccountHandler {
+	if change == nil {
+		change = &fakeChangePassword{}
+	}
+	if del == nil {
+		del = &fakeDeleteAccount{}
+	}
+	return accounthandler.NewAccountHandler(reset, verification, change, del)
+}
+
+func serve(h middl.HandlerWriteError, body string) *httptest.ResponseRecorder {
+	return serveAs(h, "", body, nil)
+}
+
+// serveAs exécute la requête pour l'utilisateur connecté userID ("" : anonyme)
+func serveAs(h middl.HandlerWriteError, userID, body string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if userID != "" {
+		req = req.WithContext(utils.WithUserID(req.Context(), userID))
+	}
+	w := httptest.NewRecorder()
+	middl.ErrorHandler(h).ServeHTTP(w, req)
+	return w
+}
+
+func TestForgotPassword(t *testing.T) {
+	reset := &fakePasswordReset{}
+	h := newHandler(reset, &fakeEmailVerification{}, nil, nil)
+
+	w := serve(h.ForgotPassword, `{"email":"alice@example.com"}`)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, []string{"alice@example.com"}, reset.requested)
+
+	w = serve(h.ForgotPassword, `{"email":"not-an-email"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Len(t, reset.requested, 1)
+}
+
+func TestResetPassword(t *testing.T) {
+	reset := &fakePasswordReset{}
+	h := newHandler(reset, &fakeEmailVerification{}, nil, nil)
+
+	w := serve(h.ResetPassword, `{"token":"raw-token","password":"n3w-passw0rd"}`)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "raw-token", reset.token)
+	assert.Equal(t, "n3w-passw0rd", reset.password)
+
+	// Mot de passe trop court : le usecase n'est pas appelé
+	reset.token = ""
+	w = serve(h.ResetPassword, `{"token":"raw-token","password":"123"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, reset.token)
+
+	reset.resetErr = utils.ErrResetTokenInvalid
+	w = serve(h.ResetPassword, `{"token":"used-token","password":"n3w-passw0rd"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "RESET_TOKEN_INVALID")
+}
+
+func TestVerifyEmail(t *testing.T) {
+	verification := &fakeEmailVerification{}
+	h := newHandler(&fakePasswordReset{}, verification, nil, nil)
+
+	w := serve(h.VerifyEmail, `{"token":"raw-token"}`)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, []string{"raw-token"}, verification.verified)
+
+	w = serve(h.VerifyEmail, `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	verification.err = utils.ErrEmailVerificationTokenInvalid
+	w = serve(h.VerifyEmail, `{"token":"expired"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestChangePassword(t *testing.T) {
+	change := &fakeChangePassword{}
+	h := newHandler(&fakePasswordReset{}, &fakeEmailVerification{}, change, nil)
+	body := `{"current_password":"old-pass","new_password":"new-pass"}`
+
+	// Refresh token de la session à conserver, lu dans l'en-tête à défaut du body
+	w := serveAs(h.ChangePassword, "user-1", body, http.Header{"X-Refresh-Token": {"refresh-1"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"revoked_sessions":2}`, w.Body.String())
+	assert.Equal(t, []string{"user-1 old-pass new-pass"}, change.calls)
+	assert.Equal(t, "refresh-1", change.refreshToken)
+
+	w = serveAs(h.ChangePassword, "", body, nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = serveAs(h.ChangePassword, "user-1", `{"current_password":"old-pass","new_password":"123"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	change.err = utils.ErrCurrentPasswordInvalid
+	w = serveAs(h.ChangePassword, "user-1", body, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Len(t, change.calls, 2)
+}
+
+func TestDeleteAccount(t *testing.T) {
+	del := &fakeDeleteAccount{}
+	h := newHandler(&fakePasswordReset{}, &fakeEmailVerification{}, nil, del)
+
+	w := serveAs(h.DeleteAccount, "user-1", `{"password":"pass123"}`, nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, []string{"user-1 pass123"}, del.deleted)
+
+	// Confirmation par mot de passe obligatoire
+	w = serveAs(h.DeleteAccount, "user-1", `{}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serveAs(h.DeleteAccount, "", `{"password":"pass123"}`, nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Len(t, del.deleted, 1)
+}
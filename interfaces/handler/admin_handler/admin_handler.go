@@ -0,0 +1,145 @@
+// interfaces/handler/admin_handler/admin_handler.go
+package adminhandler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	userdto "Goshop/application/dto/user_dto"
+	userusecase "Goshop/application/usecase/user_usecase"
+	userentity "Goshop/domain/entity/user_entity"
+	userrepository "Goshop/domain/repository/user_repository"
+	"Goshop/interfaces/utils"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+)
+
+type AdminHandler struct {
+	manageRolesUc   *userusecase.ManageRolesUsecase
+	unlockAccountUc *userusecase.UnlockAccountUsecase
+}
+
+func NewAdminHandler(repo userrepository.UserRepository, unlocker userusecase.AccountUnlocker) *AdminHandler {
+	return &AdminHandler{
+		manageRolesUc:   userusecase.NewManageRolesUsecase(repo),
+		unlockAccountUc: userusecase.NewUnlockAccountUsecase(repo, unlocker),
+	}
+}
+
+// @Summary Get user roles
+// @Description Roles and effective permissions of a user (admin only)
+// @Tags Admin
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} userdto.UserRolesResponse
+// @Failure 403 {object} utils.AppError "Forbidden"
+// @Failure 404 {object} utils.AppError "User not found"
+// @Security ApiKeyAuth
+// @Router /api/admin/users/{id}/roles [get]
+func (h *AdminHandler) GetUserRoles(w http.ResponseWriter, r *http.Request) error {
+	user, err := h.manageRolesUc.GetAccess(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	utils.WriteJSON(w, http.StatusOK, toUserRolesResponse(user))
+	return nil
+}
+
+// @Summary Grant a role
+// @Description Grant a role to a user (admin only). Takes effect on the user's next access token.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body userdto.GrantRoleRequest true "Role to grant"
+// @Success 200 {object} userdto.UserRolesResponse
+// @Failure 400 {object} utils.AppError "Unknown role"
+// @Failure 403 {object} utils.AppError "Forbidden"
+// @Failure 404 {object} utils.AppError "User not found"
+// @Security ApiKeyAuth
+// @Router /api/admin/users/{id}/roles [post]
+func (h *AdminHandler) GrantRole(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	logger := zerolog.Ctx(ctx)
+
+	var req userdto.GrantRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn().Err(err).Msg("Invalid grant role payload")
+		return utils.ErrInvalidPayload
+	}
+	if err := req.Validate(); err != nil {
+		return utils.ErrValidationFailed
+	}
+
+	actorID, _ := utils.GetUserID(ctx)
+	user, err := h.manageRolesUc.Grant(ctx, actorID, chi.URLParam(r, "id"), req.Role)
+	if err != nil {
+		return err
+	}
+
+	utils.WriteJSON(w, http.StatusOK, toUserRolesResponse(user))
+	return nil
+}
+
+// @Summary Revoke a role
+// @Description Revoke a role from a user (admin only)
+// @Tags Admin
+// @Produce json
+// @Param id path string true "User ID"
+// @Param role path string true "Role"
+// @Success 200 {object} userdto.UserRolesResponse
+// @Failure 403 {object} utils.AppError "Forbidden"
+// @Failure 404 {object} utils.AppError "User not found"
+// @Failure 409 {object} utils.AppError "Cannot revoke own admin role"
+// @Security ApiKeyAuth
+// @Router /api/admin/users/{id}/roles/{role} [delete]
+func (h *AdminHandler) RevokeRole(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	actorID, _ := utils.GetUserID(ctx)
+	user, err := h.manageRolesUc.Revoke(ctx, actorID, chi.URLParam(r, "id"), chi.URLParam(r, "role"))
+	if err != nil {
+		return err
+	}
+
+	utils.WriteJSON(w, http.StatusOK, toUserRolesResponse(user))
+	return nil
+}
+
+// @Summary Unlock an account
+// @Description Clear the failed login attempts and the temporary lockout of a user (admin only)
+// @Tags Admin
+// @Param id path string true "User ID"
+// @Success 204 "Account unlocked"
+// @Failure 403 {object} utils.AppError "Forbidden"
+// @Failure 404 {object} utils.AppError "User not found"
+// @Security ApiKeyAuth
+// @Router /api/admin/users/{id}/unlock [post]
+func (h *AdminHandler) UnlockAccount(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	actorID, _ := utils.GetUserID(ctx)
+	if err := h.unlockAccountUc.Execute(ctx, actorID, chi.URLParam(r, "id")); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func toUserRolesResponse(user *userentity.UserEntity) userdto.UserRolesResponse {
+	resp := userdto.UserRolesResponse{
+		UserID:      user.ID,
+		Roles:       user.Roles,
+		Permissions: user.Permissions,
+	}
+	if resp.Roles == nil {
+		resp.Roles = []string{}
+	}
+	if resp.Permissions == nil {
+		resp.Permissions = []string{}
+	}
+	return resp
+}
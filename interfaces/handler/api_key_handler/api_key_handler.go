@@ -0,0 +1,147 @@
+// interfaces/handler/api_key_handler/api_key_handler.go
+package apikeyhandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	userdto "Goshop/application/dto/user_dto"
+	authusecase "Goshop/application/usecase/auth_usecase"
+	authentity "Goshop/domain/auth_entity"
+	"Goshop/interfaces/utils"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+)
+
+type APIKeyUseCase interface {
+	Create(ctx context.Context, createdBy string, in authusecase.NewAPIKey) (*authentity.APIKey, string, error)
+	List(ctx context.Context) ([]*authentity.APIKey, error)
+	Revoke(ctx context.Context, actorID, id string) error
+}
+
+// APIKeyHandler : gestion des clés d'API par les administrateurs (routes
+// /api/admin, protégées par le rôle admin)
+type APIKeyHandler struct {
+	keys APIKeyUseCase
+}
+
+func NewAPIKeyHandler(keys APIKeyUseCase) *APIKeyHandler {
+	return &APIKeyHandler{keys: keys}
+}
+
+// @Summary Create an API key
+// @Description Create an API key for a machine client (admin only). The key is returned only once; send it in the X-API-Key header or as "Authorization: ApiKey <key>".
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body userdto.CreateAPIKeyRequest true "API key"
+// @Success 201 {object} userdto.CreateAPIKeyResponse
+// @Failure 400 {object} utils.AppError "Invalid payload or unknown scope"
+// @Failure 403 {object} utils.AppError "Forbidden"
+// @Security ApiKeyAuth
+// @Router /api/admin/api-keys [post]
+func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	var req userdto.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Invalid API key payload")
+		return utils.ErrInvalidPayload
+	}
+	if err := req.Validate(); err != nil {
+		return utils.ErrValidationFailed
+	}
+
+	in := authusecase.NewAPIKey{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		RateLimit: req.RateLimit,
+	}
+	if req.ExpiresAt != nil {
+		in.ExpiresAt = *req.ExpiresAt
+	}
+
+	actorID, _ := utils.GetUserID(ctx)
+	key, raw, err := h.keys.Create(ctx, actorID, in)
+	if err != nil {
+		return err
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, userdto.CreateAPIKeyResponse{
+		APIKeyResponse: toAPIKeyResponse(key),
+		Key:            raw,
+	})
+	return nil
+}
+
+// @Summary List API keys
+// @Description List all API keys, revoked ones included (admin only). Secrets are never returned.
+// @Tags Admin
+// @Produce json
+// @Success 200 {array} userdto.APIKeyResponse
+// @Failure 403 {object} utils.AppError "Forbidden"
+// @Security ApiKeyAuth
+// @Router /api/admin/api-keys [get]
+func (h *APIKeyHandler) List(w http.ResponseWriter, r *http.Request) error {
+	keys, err := h.keys.List(r.Context())
+	if err != nil {
+		return err
+	}
+
+	resp := make([]userdto.APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		resp = append(resp, toAPIKeyResponse(k))
+	}
+	utils.WriteJSON(w, http.StatusOK, resp)
+	return nil
+}
+
+// @Summary Revoke an API key
+// @Description Revoke an API key immediately (admin only)
+// @Tags Admin
+// @Param id path string true "API key ID"
+// @Success 204 "API key revoked"
+// @Failure 403 {object} utils.AppError "Forbidden"
+// @Failure 404 {object} utils.AppError "API key not found or already revoked"
+// @Security ApiKeyAuth
+// @Router /api/admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	actorID, _ := utils.GetUserID(ctx)
+	if err := h.keys.Revoke(ctx, actorID, chi.URLParam(r, "id")); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func toAPIKeyResponse(k *authentity.APIKey) userdto.APIKeyResponse {
+	resp := userdto.APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		RateLimit:  k.RateLimit,
+		ExpiresAt:  optionalTime(k.ExpiresAt),
+		LastUsedAt: optionalTime(k.LastUsedAt),
+		RevokedAt:  optionalTime(k.RevokedAt),
+		CreatedAt:  k.CreatedAt,
+	}
+	if resp.Scopes == nil {
+		resp.Scopes = []string{}
+	}
+	return resp
+}
+
+// optionalTime : nil pour une date absente (valeur zéro)
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
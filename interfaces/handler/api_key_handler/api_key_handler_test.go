@@ -0,0 +1,129 @@
+package apikeyhandler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authusecase "Goshop/application/usecase/auth_usecase"
+	authentity "Goshop/domain/auth_entity"
+	apikeyhandler "Goshop/interfaces/handler/api_key_handler"
+	"Goshop/interfaces/middl"
+	"Goshop/interfaces/utils"
+)
+
+var createdAt = time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+type fakeAPIKeys struct {
+	created []authusecase.NewAPIKey
+	revoked []string // "actorID|id"
+}
+
+func (f *fakeAPIKeys) Create(_ context.Context, createdBy string, in authusecase.NewAPIKey) (*authentity.APIKey, string, error) {
+	f.created = append(f.created, in)
+	if len(in.Scopes) > 0 && in.Scopes[0] == "everything" {
+		return nil, "", utils.ErrAPIKeyScopeInvalid
+	}
+	return &authentity.APIKey{
+		ID:        "key-1",
+		Name:      in.Name,
+		Prefix:    "0123456789ab",
+		Scopes:    in.Scopes,
+		RateLimit: 600,
+		ExpiresAt: in.ExpiresAt,
+		CreatedBy: createdBy,
+		CreatedAt: createdAt,
+	}, "gsk_0123456789ab_secret", nil
+}
+
+func (f *fakeAPIKeys) List(context.Context) ([]*authentity.APIKey, error) {
+	return []*authentity.APIKey{
+		{ID: "key-2", Name: "revoked", Prefix: "ba9876543210", RevokedAt: createdAt, CreatedAt: createdAt},
+		{ID: "key-1", Name: "sync", Prefix: "0123456789ab", Scopes: []string{"products:write"}, CreatedAt: createdAt},
+	}, nil
+}
+
+func (f *fakeAPIKeys) Revoke(_ context.Context, actorID, id string) error {
+	f.revoked = append(f.revoked, actorID+"|"+id)
+	if id != "key-1" {
+		return utils.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// serveAdmin exécute la requête pour l'administrateur admin-1
+func serveAdmin(h middl.HandlerWriteError, req *http.Request) *httptest.ResponseRecorder {
+	req = req.WithContext(utils.WithUserID(req.Context(), "admin-1"))
+	w := httptest.NewRecorder()
+	middl.ErrorHandler(h).ServeHTTP(w, req)
+	return w
+}
+
+func TestCreate(t *testing.T) {
+	keys := &fakeAPIKeys{}
+	h := apikeyhandler.NewAPIKeyHandler(keys)
+
+	body := `{"name":"catalog sync","scopes":["products:write"],"expires_at":"2027-01-01T00:00:00Z"}`
+	w := serveAdmin(h.Create, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var resp map[string]any
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "gsk_0123456789ab_secret", resp["key"])
+	assert.Equal(t, "key-1", resp["id"])
+	assert.Equal(t, "2027-01-01T00:00:00Z", resp["expires_at"])
+	assert.NotContains(t, resp, "last_used_at")
+	assert.NotContains(t, resp, "secret_hash")
+
+	require.Len(t, keys.created, 1)
+	assert.Equal(t, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), keys.created[0].ExpiresAt)
+}
+
+func TestCreate_Invalid(t *testing.T) {
+	h := apikeyhandler.NewAPIKeyHandler(&fakeAPIKeys{})
+
+	for _, body := range []string{`not json`, `{"name":" "}`, `{"name":"k","rate_limit":-1}`, `{"name":"k","scopes":["everything"]}`} {
+		w := serveAdmin(h.Create, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestList(t *testing.T) {
+	h := apikeyhandler.NewAPIKeyHandler(&fakeAPIKeys{})
+
+	w := serveAdmin(h.List, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp []map[string]any
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp, 2)
+	assert.Equal(t, "2026-10-17T12:00:00Z", resp[0]["revoked_at"])
+	assert.Equal(t, []any{}, resp[0]["scopes"])
+	assert.NotContains(t, resp[1], "revoked_at")
+	assert.NotContains(t, resp[1], "key")
+}
+
+func TestRevoke(t *testing.T) {
+	keys := &fakeAPIKeys{}
+	h := apikeyhandler.NewAPIKeyHandler(keys)
+
+	revoke := func(id string) int {
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", id)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		return serveAdmin(h.Revoke, req).Code
+	}
+
+	assert.Equal(t, http.StatusNoContent, revoke("key-1"))
+	assert.Equal(t, http.StatusNotFound, revoke("key-9"))
+	assert.Equal(t, []string{"admin-1|key-1", "admin-1|key-9"}, keys.revoked)
+}
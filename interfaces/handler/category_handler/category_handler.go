@@ -0,0 +1,163 @@
+// interfaces/handler/category_handler/category_handler.go
+package categoryhandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	dto "Goshop/application/dto/product_dto"
+	categoryusecase "Goshop/application/usecase/category_usecase"
+	"Goshop/domain/entity"
+	"Goshop/interfaces/utils"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+)
+
+type CategoryUseCase interface {
+	Create(ctx context.Context, req dto.CategoryRequest) (*entity.Category, error)
+	List(ctx context.Context) ([]*entity.Category, error)
+	Get(ctx context.Context, slug string) (*categoryusecase.CategoryDetails, error)
+	Update(ctx context.Context, slug string, req dto.CategoryRequest) (*entity.Category, error)
+	Delete(ctx context.Context, slug string) error
+}
+
+// CategoryHandler : navigation dans l'arbre des catégories (lecture pour
+// tout utilisateur connecté) et gestion par le back-office
+type CategoryHandler struct {
+	categories CategoryUseCase
+}
+
+func NewCategoryHandler(categories CategoryUseCase) *CategoryHandler {
+	return &CategoryHandler{categories: categories}
+}
+
+// @Summary Create a category
+// @Description Create a category, at the root or under parent_id
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Param request body dto.CategoryRequest true "Category"
+// @Success 201 {object} dto.CategoryResponse
+// @Failure 400 {object} utils.AppError "Invalid payload or unknown parent"
+// @Failure 409 {object} utils.AppError "Slug already used"
+// @Security ApiKeyAuth
+// @Router /api/categories [post]
+func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	var req dto.CategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Invalid category payload")
+		return utils.ErrInvalidPayload
+	}
+	if err := req.Validate(); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Category validation failed")
+		return utils.ErrValidationFailed
+	}
+
+	category, err := h.categories.Create(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, dto.NewCategoryResponse(category))
+	return nil
+}
+
+// @Summary List categories
+// @Description Full category tree: root categories with nested children
+// @Tags Categories
+// @Produce json
+// @Success 200 {array} dto.CategoryResponse
+// @Security ApiKeyAuth
+// @Router /api/categories [get]
+func (h *CategoryHandler) List(w http.ResponseWriter, r *http.Request) error {
+	categories, err := h.categories.List(r.Context())
+	if err != nil {
+		return err
+	}
+
+	utils.WriteJSON(w, http.StatusOK, dto.NewCategoryTree(categories))
+	return nil
+}
+
+// @Summary Get a category
+// @Description Category with its breadcrumb (root first) and direct children
+// @Tags Categories
+// @Produce json
+// @Param slug path string true "Category slug"
+// @Success 200 {object} dto.CategoryResponse
+// @Failure 404 {object} utils.AppError "Category not found"
+// @Security ApiKeyAuth
+// @Router /api/categories/{slug} [get]
+func (h *CategoryHandler) Get(w http.ResponseWriter, r *http.Request) error {
+	details, err := h.categories.Get(r.Context(), chi.URLParam(r, "slug"))
+	if err != nil {
+		return err
+	}
+
+	resp := dto.NewCategoryResponse(details.Category)
+	resp.Breadcrumb = dto.NewCategoryRefs(details.Breadcrumb)
+	resp.Children = make([]*dto.CategoryResponse, 0, len(details.Children))
+	for _, child := range details.Children {
+		resp.Children = append(resp.Children, dto.NewCategoryResponse(child))
+	}
+
+	utils.WriteJSON(w, http.StatusOK, resp)
+	return nil
+}
+
+// @Summary Update a category
+// @Description Rename and/or move a category; its subcategories follow
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Param slug path string true "Category slug"
+// @Param request body dto.CategoryRequest true "Category"
+// @Success 200 {object} dto.CategoryResponse
+// @Failure 400 {object} utils.AppError "Invalid payload or parent"
+// @Failure 404 {object} utils.AppError "Category not found"
+// @Failure 409 {object} utils.AppError "Slug already used"
+// @Security ApiKeyAuth
+// @Router /api/categories/{slug} [put]
+func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	var req dto.CategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Invalid category payload")
+		return utils.ErrInvalidPayload
+	}
+	if err := req.Validate(); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Category validation failed")
+		return utils.ErrValidationFailed
+	}
+
+	category, err := h.categories.Update(ctx, chi.URLParam(r, "slug"), req)
+	if err != nil {
+		return err
+	}
+
+	utils.WriteJSON(w, http.StatusOK, dto.NewCategoryResponse(category))
+	return nil
+}
+
+// @Summary Delete a category
+// @Description Delete a category without subcategories; its products are only unlinked
+// @Tags Categories
+// @Param slug path string true "Category slug"
+// @Success 204 "Category deleted"
+// @Failure 404 {object} utils.AppError "Category not found"
+// @Failure 409 {object} utils.AppError "Category has subcategories"
+// @Security ApiKeyAuth
+// @Router /api/categories/{slug} [delete]
+func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) error {
+	if err := h.categories.Delete(r.Context(), chi.URLParam(r, "slug")); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
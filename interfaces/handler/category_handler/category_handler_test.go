@@ -0,0 +1,136 @@
+package categoryhandler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dto "Goshop/application/dto/product_dto"
+	categoryusecase "Goshop/application/usecase/category_usecase"
+	"Goshop/domain/entity"
+	categoryhandler "Goshop/interfaces/handler/category_handler"
+	"Goshop/interfaces/middl"
+	"Goshop/interfaces/utils"
+)
+
+var (
+	electronics = &entity.Category{ID: "cat-1", Name: "Électronique", Slug: "electronique", Path: "electronique"}
+	laptops     = &entity.Category{ID: "cat-2", Name: "Portables", Slug: "portables", ParentID: "cat-1", Path: "electronique/portables"}
+	phones      = &entity.Category{ID: "cat-3", Name: "Téléphones", Slug: "telephones", ParentID: "cat-1", Path: "electronique/telephones"}
+	books       = &entity.Category{ID: "cat-4", Name: "Livres", Slug: "livres", Path: "livres"}
+)
+
+type fakeCategories struct {
+	created []dto.CategoryRequest
+}
+
+func (f *fakeCategories) Create(_ context.Context, req dto.CategoryRequest) (*entity.Category, error) {
+	f.created = append(f.created, req)
+	if req.Slug == "electronique" {
+		return nil, utils.ErrCategorySlugTaken
+	}
+	return &entity.Category{ID: "cat-9", Name: req.Name, Slug: req.Slug, ParentID: req.ParentID, Path: req.Slug}, nil
+}
+
+func (f *fakeCategories) List(context.Context) ([]*entity.Category, error) {
+	return []*entity.Category{electronics, laptops, phones, books}, nil
+}
+
+func (f *fakeCategories) Get(_ context.Context, slug string) (*categoryusecase.CategoryDetails, error) {
+	if slug != "electronique" {
+		return nil, utils.ErrCategoryNotFound
+	}
+	return &categoryusecase.CategoryDetails{
+		Category:   electronics,
+		Breadcrumb: []*entity.Category{electronics},
+		Children:   []*entity.Category{laptops, phones},
+	}, nil
+}
+
+func (f *fakeCategories) Update(_ context.Context, slug string, req dto.CategoryRequest) (*entity.Category, error) {
+	return &entity.Category{ID: "cat-2", Name: req.Name, Slug: req.Slug, ParentID: req.ParentID}, nil
+}
+
+func (f *fakeCategories) Delete(_ context.Context, slug string) error {
+	if slug == "electronique" {
+		return utils.ErrCategoryHasChildren
+	}
+	return nil
+}
+
+func serve(h middl.HandlerWriteError, req *http.Request, slug string) *httptest.ResponseRecorder {
+	if slug != "" {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("slug", slug)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+	w := httptest.NewRecorder()
+	middl.ErrorHandler(h).ServeHTTP(w, req)
+	return w
+}
+
+func TestCreate(t *testing.T) {
+	categories := &fakeCategories{}
+	h := categoryhandler.NewCategoryHandler(categories)
+
+	body := `{"name":"Portables","slug":"portables","parent_id":"cat-1"}`
+	w := serve(h.Create, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)), "")
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "cat-1", categories.created[0].ParentID)
+
+	w = serve(h.Create, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Électronique","slug":"electronique"}`)), "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// Slug invalide : rejeté avant le usecase
+	w = serve(h.Create, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Jeux","slug":"Jeux Vidéo"}`)), "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Len(t, categories.created, 2)
+}
+
+func TestListReturnsTree(t *testing.T) {
+	h := categoryhandler.NewCategoryHandler(&fakeCategories{})
+
+	w := serve(h.List, httptest.NewRequest(http.MethodGet, "/", nil), "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var tree []dto.CategoryResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&tree))
+	require.Len(t, tree, 2)
+	assert.Equal(t, "electronique", tree[0].Slug)
+	assert.Nil(t, tree[0].ParentID)
+	require.Len(t, tree[0].Children, 2)
+	assert.Equal(t, "portables", tree[0].Children[0].Slug)
+	assert.Empty(t, tree[1].Children)
+}
+
+func TestGet(t *testing.T) {
+	h := categoryhandler.NewCategoryHandler(&fakeCategories{})
+
+	w := serve(h.Get, httptest.NewRequest(http.MethodGet, "/", nil), "electronique")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.CategoryResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, []dto.CategoryRef{{ID: "cat-1", Name: "Électronique", Slug: "electronique"}}, resp.Breadcrumb)
+	assert.Len(t, resp.Children, 2)
+
+	w = serve(h.Get, httptest.NewRequest(http.MethodGet, "/", nil), "inconnue")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDelete(t *testing.T) {
+	h := categoryhandler.NewCategoryHandler(&fakeCategories{})
+
+	w := serve(h.Delete, httptest.NewRequest(http.MethodDelete, "/", nil), "portables")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = serve(h.Delete, httptest.NewRequest(http.MethodDelete, "/", nil), "electronique")
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -26,6 +27,7 @@ type CustomerHandler struct {
 	getCustomerByIdUsecase *customerusecase.GetCustomerByIdUsecase
 	updateCustomerUsecase  *customerusecase.UpdateCustomerUsecase
 	deleteCustomerUsecase  *customerusecase.DeleteCustomerUsecase
+	restoreCustomerUsecase *customerusecase.RestoreCustomerUsecase
 	//logger                 *setupLogging.Logger
 }
 
@@ -40,6 +42,7 @@ func NewCustomerHandler(
 		getCustomerByIdUsecase: customerusecase.NewGetCustomerByIdUsecase(repo, txManager),
 		updateCustomerUsecase:  customerusecase.NewUpdateCustomerUsecase(repo, txManager),
 		deleteCustomerUsecase:  customerusecase.NewDeleteCustomerUsecase(repo, txManager),
+		restoreCustomerUsecase: customerusecase.NewRestoreCustomerUsecase(repo, txManager),
 		//logger:                 logger.WithComponent("customer_handler"),
 	}
 }
@@ -159,9 +162,23 @@ func (h *CustomerHandler) GetCustomerByIdHandler(w http.ResponseWriter, r *http.
 		return utils.ErrInvalidPayload
 	}
 
+	// ?include_deleted=true : client archivé compris (customers:read:any)
+	includeDeleted, err := utils.ParseIncludeDeleted(r)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Invalid include_deleted parameter")
+		return utils.ErrCustomerInvalidFilter
+	}
+	get := h.getCustomerByIdUsecase.Execute
+	if includeDeleted {
+		get = h.getCustomerByIdUsecase.ExecuteIncludingDeleted
+	}
+
 	logger.Debug().Msg("Executing get customer by ID usecase")
-	customer, err := h.getCustomerByIdUsecase.Execute(ctx, id)
+	customer, err := get(ctx, id)
 	if err != nil {
+		if errors.Is(err, utils.ErrForbidden) {
+			return utils.ErrForbidden
+		}
 		// CORRECTION : Vérifier explicitement sql.ErrNoRows
 		if errors.Is(err, sql.ErrNoRows) || strings.Contains(strings.ToLower(err.Error()), "not found") {
 			logger.Warn().
@@ -208,6 +225,21 @@ func (h *CustomerHandler) GetAllCustomersHandler(w http.ResponseWriter, r *http.
 		Str("query_params", r.URL.RawQuery).
 		Msg("Retrieving all customers")
 
+	// Pagination par curseur (enveloppe) ou par offset (tableau + X-Total-Count)
+	filter, err := getCustomerFilter(r)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Invalid customer filter")
+		return utils.ErrCustomerInvalidFilter
+	}
+	limit, offset := getPaginationParams(r, logger)
+	if utils.IsCursorRequest(r) {
+		return h.listCustomersByCursor(w, r, limit, filter)
+	}
+	if isPaginatedRequest(r) {
+		return h.listCustomersByOffset(w, r, limit, offset, filter)
+	}
+
+	// Sans paramètre : comportement historique, tous les clients
 	logger.Debug().Msg("Executing get all customers usecase")
 	customers, err := h.getAllCustomersUsecase.Execute(ctx)
 	if err != nil {
@@ -238,6 +270,100 @@ func (h *CustomerHandler) GetAllCustomersHandler(w http.ResponseWriter, r *http.
 	return nil
 }
 
+const customersCursorResource = "customers"
+
+func (h *CustomerHandler) listCustomersByCursor(w http.ResponseWriter, r *http.Request, limit int, filter dto.CustomerFilter) error {
+	ctx := r.Context()
+	logger := zerolog.Ctx(ctx)
+
+	page, err := utils.GetCursorPage(r, customersCursorResource, limit)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Invalid pagination cursor")
+		return err
+	}
+
+	result, err := h.getAllCustomersUsecase.ExecuteCursor(ctx, page, filter)
+	if err != nil {
+		logger.Error().Err(err).Stack().Msg("Failed to retrieve customers page")
+		return utils.ErrInternalServer
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.NewPage(customersCursorResource, page, result, dto.ToCustomerResponses(result.Items)))
+	return nil
+}
+
+func (h *CustomerHandler) listCustomersByOffset(w http.ResponseWriter, r *http.Request, limit, offset int, filter dto.CustomerFilter) error {
+	ctx := r.Context()
+	logger := zerolog.Ctx(ctx)
+
+	customers, total, err := h.getAllCustomersUsecase.ExecuteWithPagination(ctx, limit, offset, filter)
+	if err != nil {
+		logger.Error().Err(err).Stack().Msg("Failed to retrieve customers page")
+		return utils.ErrInternalServer
+	}
+
+	w.Header().Set(utils.TOTAL_COUNT_HEADER, strconv.Itoa(total))
+	utils.WriteJSON(w, http.StatusOK, dto.ToCustomerResponses(customers))
+	return nil
+}
+
+// getCustomerFilter lit ?name= et ?email= (recherche partielle) et
+// ?include_deleted=true (clients archivés compris)
+func getCustomerFilter(r *http.Request) (dto.CustomerFilter, error) {
+	includeDeleted, err := utils.ParseIncludeDeleted(r)
+	if err != nil {
+		return dto.CustomerFilter{}, err
+	}
+	filter := dto.CustomerFilter{IncludeDeleted: includeDeleted}
+	if name := strings.TrimSpace(r.URL.Query().Get("name")); name != "" {
+		filter.Name = &name
+	}
+	if email := strings.TrimSpace(r.URL.Query().Get("email")); email != "" {
+		filter.Email = &email
+	}
+	return filter, nil
+}
+
+// isPaginatedRequest : un paramètre de pagination ou de filtre active le mode offset
+func isPaginatedRequest(r *http.Request) bool {
+	query := r.URL.Query()
+	for _, param := range []string{"limit", "offset", "name", "email", utils.INCLUDE_DELETED_PARAM} {
+		if query.Has(param) {
+			return true
+		}
+	}
+	return false
+}
+
+func getPaginationParams(r *http.Request, logger *zerolog.Logger) (limit, offset int) {
+	limit = 50
+	offset = 0
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		} else {
+			logger.Warn().
+				Str("limit", limitStr).
+				Err(err).
+				Msg("Invalid limit parameter, using default")
+		}
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		} else {
+			logger.Warn().
+				Str("offset", offsetStr).
+				Err(err).
+				Msg("Invalid offset parameter, using default")
+		}
+	}
+
+	return limit, offset
+}
+
 func (h *CustomerHandler) UpdateCustomerHandler(w http.ResponseWriter, r *http.Request) error {
 	ctx := r.Context()
 	start := time.Now()
@@ -424,3 +550,49 @@ func isValidEmail(email string) bool {
 	}
 	return strings.Contains(email, "@") && strings.Contains(email, ".")
 }
+
+// RestoreCustomerHandler annule l'archivage d'un client
+func (h *CustomerHandler) RestoreCustomerHandler(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	start := time.Now()
+	id := chi.URLParam(r, "id")
+	logger := zerolog.Ctx(ctx)
+
+	logger.Info().
+		Str("customer_id", id).
+		Str("method", r.Method).
+		Str("path", r.URL.Path).
+		Msg("Starting customer restore")
+
+	if id == "" {
+		logger.Warn().Msg("Empty customer ID provided")
+		return utils.ErrInvalidPayload
+	}
+
+	customer, err := h.restoreCustomerUsecase.Execute(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || strings.Contains(strings.ToLower(err.Error()), "not found") {
+			logger.Warn().
+				Err(err).
+				Dur("duration_before_error", time.Since(start)).
+				Msg("Customer not found for restore")
+			return utils.ErrCustomerNotFound
+		}
+
+		logger.Error().
+			Err(err).
+			Stack().
+			Dur("duration_before_error", time.Since(start)).
+			Msg("Failed to restore customer")
+		return utils.ErrCustomerRestoreFail
+	}
+
+	logger.Info().
+		Str("customer_id", customer.ID).
+		Dur("duration", time.Since(start)).
+		Int("http_status", http.StatusOK).
+		Msg("Customer restore completed")
+
+	utils.WriteJSON(w, http.StatusOK, dto.ToCustomerResponse(customer))
+	return nil
+}
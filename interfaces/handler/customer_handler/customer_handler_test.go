@@ -13,11 +13,15 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	dto "Goshop/application/dto/customer_dto"
+	paginationdto "Goshop/application/dto/pagination_dto"
 	"Goshop/domain/entity"
 	customerhandler "Goshop/interfaces/handler/customer_handler"
 	"Goshop/interfaces/middl"
+	"Goshop/interfaces/utils"
 	mockrepo "Goshop/mocks/repository"
 )
 
@@ -327,6 +331,77 @@ func TestGetAllCustomersHandler_Empty(t *testing.T) {
 	assert.Empty(t, response)
 }
 
+func TestGetAllCustomersHandler_OffsetPagination(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+	handler := customerhandler.NewCustomerHandler(mockRepo, mockrepo.NewMockTxManager(ctrl))
+
+	mockRepo.EXPECT().FindAllCustomersWithPagination(gomock.Any(), 5, 0, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, limit, offset int, filter dto.CustomerFilter) ([]*entity.Customer, error) {
+			require.NotNil(t, filter.Email)
+			assert.Equal(t, "example.com", *filter.Email)
+			return []*entity.Customer{createTestCustomer("cust-1", "john1@example.com")}, nil
+		})
+	mockRepo.EXPECT().CountAllCustomers(gomock.Any(), gomock.Any()).Return(1, nil)
+
+	w := httptest.NewRecorder()
+	middl.ErrorHandler(handler.GetAllCustomersHandler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/customers?limit=5&email=example.com", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get(utils.TOTAL_COUNT_HEADER))
+
+	var response []map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Len(t, response, 1)
+}
+
+func TestCustomerHandlers_InvalidIncludeDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Valeur invalide : 400 comme pour les produits, sans appel au repository
+	mockRepo := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+	handler := customerhandler.NewCustomerHandler(mockRepo, mockrepo.NewMockTxManager(ctrl))
+
+	w := httptest.NewRecorder()
+	middl.ErrorHandler(handler.GetAllCustomersHandler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/customers?include_deleted=yes", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "INVALID_CUSTOMER_FILTER", response["code"])
+
+	w = httptest.NewRecorder()
+	req := setupChiContext(httptest.NewRequest(http.MethodGet, "/customers/cust-1?include_deleted=yes", nil), "cust-1")
+	middl.ErrorHandler(handler.GetCustomerByIdHandler).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetAllCustomersHandler_CursorPagination(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+	handler := customerhandler.NewCustomerHandler(mockRepo, mockrepo.NewMockTxManager(ctrl))
+
+	mockRepo.EXPECT().FindAllCustomersByCursor(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]*entity.Customer{createTestCustomer("cust-1", "john1@example.com")}, nil)
+
+	w := httptest.NewRecorder()
+	middl.ErrorHandler(handler.GetAllCustomersHandler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/customers?cursor=", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response paginationdto.Page[map[string]interface{}]
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Len(t, response.Data, 1)
+	assert.False(t, response.Pagination.HasMore)
+	assert.Empty(t, response.Pagination.NextCursor)
+	assert.Empty(t, response.Pagination.PrevCursor)
+}
+
 func TestUpdateCustomerHandler_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -0,0 +1,225 @@
+// interfaces/handler/image_handler/image_handler.go
+package imagehandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	dto "Goshop/application/dto/product_dto"
+	"Goshop/domain/storage"
+	"Goshop/interfaces/utils"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+)
+
+// Marge accordée au corps multipart au-delà de l'image (délimiteurs,
+// en-têtes des parties, champs annexes)
+const multipartOverhead = 64 << 10
+
+type ImageUseCase interface {
+	MaxBytes() int64
+	List(ctx context.Context, productID string) ([]dto.ImageResponse, error)
+	Upload(ctx context.Context, productID string, data []byte) (*dto.ImageResponse, error)
+	Open(ctx context.Context, productID, imageID string) (*storage.Blob, error)
+	Reorder(ctx context.Context, productID string, req dto.ReorderImagesRequest) ([]dto.ImageResponse, error)
+	Delete(ctx context.Context, productID, imageID string) error
+}
+
+// ImageHandler : images d'un produit (lecture pour tout utilisateur connecté,
+// écriture par le back-office)
+type ImageHandler struct {
+	images ImageUseCase
+}
+
+func NewImageHandler(images ImageUseCase) *ImageHandler {
+	return &ImageHandler{images: images}
+}
+
+// @Summary List product images
+// @Description Images of a product, primary image first
+// @Tags Products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {array} dto.ImageResponse
+// @Failure 404 {object} utils.AppError "Product not found"
+// @Security ApiKeyAuth
+// @Router /api/products/{id}/images [get]
+func (h *ImageHandler) List(w http.ResponseWriter, r *http.Request) error {
+	images, err := h.images.List(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	utils.WriteJSON(w, http.StatusOK, images)
+	return nil
+}
+
+// @Summary Upload a product image
+// @Description Multipart upload (field "file"); JPEG, PNG, GIF or WebP detected from the content, appended after the existing images
+// @Tags Products
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param file formData file true "Image"
+// @Success 201 {object} dto.ImageResponse
+// @Failure 400 {object} utils.AppError "Missing file"
+// @Failure 404 {object} utils.AppError "Product not found"
+// @Failure 409 {object} utils.AppError "Too many images"
+// @Failure 413 {object} utils.AppError "Image too large"
+// @Failure 415 {object} utils.AppError "Unsupported image type"
+// @Security ApiKeyAuth
+// @Router /api/products/{id}/images [post]
+func (h *ImageHandler) Upload(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	maxBytes := h.images.MaxBytes()
+
+	if r.ContentLength > maxBytes+multipartOverhead {
+		zerolog.Ctx(ctx).Warn().Int64("content_length", r.ContentLength).Msg("Image upload too large")
+		return utils.ErrImageTooLarge
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes+multipartOverhead)
+
+	data, err := readImagePart(r, maxBytes)
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Invalid image upload")
+		return err
+	}
+
+	image, err := h.images.Upload(ctx, chi.URLParam(r, "id"), data)
+	if err != nil {
+		return err
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, image)
+	return nil
+}
+
+// @Summary Get a product image
+// @Description Image content
+// @Tags Products
+// @Produce image/jpeg,image/png,image/gif,image/webp
+// @Param id path string true "Product ID"
+// @Param imageId path string true "Image ID"
+// @Success 200 {file} binary
+// @Failure 404 {object} utils.AppError "Image not found"
+// @Security ApiKeyAuth
+// @Router /api/products/{id}/images/{imageId} [get]
+func (h *ImageHandler) Content(w http.ResponseWriter, r *http.Request) error {
+	blob, err := h.images.Open(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "imageId"))
+	if err != nil {
+		return err
+	}
+	defer blob.Body.Close()
+
+	w.Header().Set("Content-Type", blob.ContentType)
+	if blob.Size >= 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(blob.Size, 10))
+	}
+	// Le contenu d'une image ne change jamais : un nouvel envoi crée une
+	// nouvelle image
+	w.Header().Set("Cache-Control", "private, max-age=86400, immutable")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, blob.Body); err != nil {
+		zerolog.Ctx(r.Context()).Warn().Err(err).Msg("Image response interrupted")
+	}
+	return nil
+}
+
+// @Summary Reorder product images
+// @Description Set the order of all the images of a product; the first one becomes the primary image
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param request body dto.ReorderImagesRequest true "Image IDs in the new order"
+// @Success 200 {array} dto.ImageResponse
+// @Failure 400 {object} utils.AppError "Invalid order"
+// @Failure 404 {object} utils.AppError "Product not found"
+// @Security ApiKeyAuth
+// @Router /api/products/{id}/images/order [put]
+func (h *ImageHandler) Reorder(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	var req dto.ReorderImagesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Invalid image order payload")
+		return utils.ErrInvalidPayload
+	}
+	if err := req.Validate(); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Image order validation failed")
+		return utils.ErrImageInvalidOrder
+	}
+
+	images, err := h.images.Reorder(ctx, chi.URLParam(r, "id"), req)
+	if err != nil {
+		return err
+	}
+
+	utils.WriteJSON(w, http.StatusOK, images)
+	return nil
+}
+
+// @Summary Delete a product image
+// @Description Delete an image and its content; the following images move up
+// @Tags Products
+// @Param id path string true "Product ID"
+// @Param imageId path string true "Image ID"
+// @Success 204 "Image deleted"
+// @Failure 404 {object} utils.AppError "Product or image not found"
+// @Security ApiKeyAuth
+// @Router /api/products/{id}/images/{imageId} [delete]
+func (h *ImageHandler) Delete(w http.ResponseWriter, r *http.Request) error {
+	if err := h.images.Delete(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "imageId")); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// readImagePart lit le champ "file" du corps multipart, sans dépasser maxBytes
+func readImagePart(r *http.Request, maxBytes int64) ([]byte, error) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, utils.ErrInvalidPayload
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, utils.ErrImageMissing
+		}
+		if err != nil {
+			return nil, bodyError(err)
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		data, err := io.ReadAll(io.LimitReader(part, maxBytes+1))
+		part.Close()
+		if err != nil {
+			return nil, bodyError(err)
+		}
+		if int64(len(data)) > maxBytes {
+			return nil, utils.ErrImageTooLarge
+		}
+		return data, nil
+	}
+}
+
+// bodyError : corps tronqué par MaxBytesReader ou multipart mal formé
+func bodyError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return utils.ErrImageTooLarge
+	}
+	return utils.ErrInvalidPayload
+}
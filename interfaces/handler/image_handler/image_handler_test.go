@@ -0,0 +1,167 @@
+package imagehandler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dto "Goshop/application/dto/product_dto"
+	"Goshop/domain/storage"
+	imagehandler "Goshop/interfaces/handler/image_handler"
+	"Goshop/interfaces/middl"
+	"Goshop/interfaces/utils"
+)
+
+var pngData = []byte("\x89PNG\r\n\x1a\nfake")
+
+type fakeImages struct {
+	uploaded  [][]byte
+	reordered []dto.ReorderImagesRequest
+}
+
+func (f *fakeImages) MaxBytes() int64 { return 32 }
+
+func (f *fakeImages) List(_ context.Context, productID string) ([]dto.ImageResponse, error) {
+	if productID != "p-1" {
+		return nil, utils.ErrProductNotFound
+	}
+	return []dto.ImageResponse{{ID: "img-1", Position: 0}, {ID: "img-2", Position: 1}}, nil
+}
+
+func (f *fakeImages) Upload(_ context.Context, productID string, data []byte) (*dto.ImageResponse, error) {
+	f.uploaded = append(f.uploaded, data)
+	return &dto.ImageResponse{ID: "img-3", ContentType: "image/png", SizeBytes: int64(len(data)), Position: 2}, nil
+}
+
+func (f *fakeImages) Open(_ context.Context, productID, imageID string) (*storage.Blob, error) {
+	if imageID != "img-1" {
+		return nil, utils.ErrImageNotFound
+	}
+	return &storage.Blob{Body: io.NopCloser(bytes.NewReader(pngData)), ContentType: "image/png", Size: int64(len(pngData))}, nil
+}
+
+func (f *fakeImages) Reorder(_ context.Context, productID string, req dto.ReorderImagesRequest) ([]dto.ImageResponse, error) {
+	f.reordered = append(f.reordered, req)
+	return []dto.ImageResponse{{ID: req.ImageIDs[0], Position: 0}}, nil
+}
+
+func (f *fakeImages) Delete(_ context.Context, productID, imageID string) error {
+	if imageID != "img-1" {
+		return utils.ErrImageNotFound
+	}
+	return nil
+}
+
+func serve(h middl.HandlerWriteError, req *http.Request, productID, imageID string) *httptest.ResponseRecorder {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", productID)
+	if imageID != "" {
+		rctx.URLParams.Add("imageId", imageID)
+	}
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	middl.ErrorHandler(h).ServeHTTP(w, req)
+	return w
+}
+
+func multipartRequest(t *testing.T, field string, data []byte) *http.Request {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	require.NoError(t, mw.WriteField("caption", "ignoré"))
+	part, err := mw.CreateFormFile(field, "photo.png")
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestList(t *testing.T) {
+	h := imagehandler.NewImageHandler(&fakeImages{})
+
+	w := serve(h.List, httptest.NewRequest(http.MethodGet, "/", nil), "p-1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var images []dto.ImageResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&images))
+	assert.Len(t, images, 2)
+
+	w = serve(h.List, httptest.NewRequest(http.MethodGet, "/", nil), "p-9", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestUpload(t *testing.T) {
+	images := &fakeImages{}
+	h := imagehandler.NewImageHandler(images)
+
+	w := serve(h.Upload, multipartRequest(t, "file", pngData), "p-1", "")
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Len(t, images.uploaded, 1)
+	assert.Equal(t, pngData, images.uploaded[0])
+
+	// Champ "file" absent
+	w = serve(h.Upload, multipartRequest(t, "photo", pngData), "p-1", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Au-delà de MaxBytes : refusé sans appeler le usecase
+	w = serve(h.Upload, multipartRequest(t, "file", bytes.Repeat([]byte("a"), 33)), "p-1", "")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	// Pas du multipart
+	w = serve(h.Upload, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`)), "p-1", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	assert.Len(t, images.uploaded, 1)
+}
+
+func TestContent(t *testing.T) {
+	h := imagehandler.NewImageHandler(&fakeImages{})
+
+	w := serve(h.Content, httptest.NewRequest(http.MethodGet, "/", nil), "p-1", "img-1")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, "12", w.Header().Get("Content-Length"))
+	assert.Equal(t, pngData, w.Body.Bytes())
+
+	w = serve(h.Content, httptest.NewRequest(http.MethodGet, "/", nil), "p-1", "img-9")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestReorder(t *testing.T) {
+	images := &fakeImages{}
+	h := imagehandler.NewImageHandler(images)
+
+	w := serve(h.Reorder, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"image_ids":["img-2","img-1"]}`)), "p-1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"img-2", "img-1"}, images.reordered[0].ImageIDs)
+
+	// Rejetés avant le usecase : liste vide, doublon
+	for _, body := range []string{`{"image_ids":[]}`, `{"image_ids":["img-1","img-1"]}`} {
+		w = serve(h.Reorder, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body)), "p-1", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	assert.Len(t, images.reordered, 1)
+}
+
+func TestDelete(t *testing.T) {
+	h := imagehandler.NewImageHandler(&fakeImages{})
+
+	w := serve(h.Delete, httptest.NewRequest(http.MethodDelete, "/", nil), "p-1", "img-1")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = serve(h.Delete, httptest.NewRequest(http.MethodDelete, "/", nil), "p-1", "img-9")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,37 @@
+// interfaces/handler/jwks_handler/jwks_handler.go
+package jwkshandler
+
+import (
+	"net/http"
+	"time"
+
+	"Goshop/interfaces/utils"
+)
+
+// KeyPublisher : clés publiques à exposer (utils.KeySet)
+type KeyPublisher interface {
+	JWKS(now time.Time) utils.JWKSet
+}
+
+// JWKSHandler expose les clés publiques de vérification des tokens GoShop
+// pour les autres services (RFC 7517)
+type JWKSHandler struct {
+	keys KeyPublisher
+}
+
+func NewJWKSHandler(keys KeyPublisher) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+// @Summary JSON Web Key Set
+// @Description Public keys verifying GoShop tokens, including keys scheduled for rotation
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} utils.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (h *JWKSHandler) JWKS(w http.ResponseWriter, r *http.Request) error {
+	// Cache court : une clé planifiée est publiée bien avant son activation
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	utils.WriteJSON(w, http.StatusOK, h.keys.JWKS(time.Now()))
+	return nil
+}
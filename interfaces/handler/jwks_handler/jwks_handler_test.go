@@ -0,0 +1,35 @@
+package jwkshandler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jwkshandler "Goshop/interfaces/handler/jwks_handler"
+	"Goshop/interfaces/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWKSHandler(t *testing.T) {
+	keys, err := utils.NewEphemeralKeySet()
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+
+	require.NoError(t, jwkshandler.NewJWKSHandler(keys).JWKS(w, req))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+
+	var set utils.JWKSet
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &set))
+	require.Len(t, set.Keys, 1)
+	assert.Equal(t, "OKP", set.Keys[0].Kty)
+	assert.Equal(t, "EdDSA", set.Keys[0].Alg)
+	assert.NotEmpty(t, set.Keys[0].X)
+}
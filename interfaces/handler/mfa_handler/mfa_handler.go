@@ -0,0 +1,97 @@
+// interfaces/handler/mfa_handler/mfa_handler.go
+package mfahandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	userdto "Goshop/application/dto/user_dto"
+	authusecase "Goshop/application/usecase/auth_usecase"
+	"Goshop/interfaces/utils"
+
+	"github.com/rs/zerolog"
+)
+
+type MFAUseCase interface {
+	Enroll(ctx context.Context, userID string) (*authusecase.MFAEnrollment, error)
+	Confirm(ctx context.Context, userID, code string) ([]string, error)
+}
+
+// MFAHandler : activation de l'authentification à deux facteurs de
+// l'utilisateur connecté (routes protégées par AuthMiddleware). Le login à
+// deux facteurs lui-même est servi par UserHandler.LoginMFA.
+type MFAHandler struct {
+	mfa MFAUseCase
+}
+
+func NewMFAHandler(mfa MFAUseCase) *MFAHandler {
+	return &MFAHandler{mfa: mfa}
+}
+
+// @Summary Start two-factor enrolment
+// @Description Generate a TOTP secret for the current user. Scan the otpauth URI (QR code) in an authenticator app, then confirm with a first code. Calling it again replaces a pending, unconfirmed secret.
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} userdto.MFAEnrollResponse
+// @Failure 401 {object} utils.AppError "Unauthorized"
+// @Failure 409 {object} utils.AppError "Two-factor authentication already enabled"
+// @Security ApiKeyAuth
+// @Router /auth/me/mfa [post]
+func (h *MFAHandler) Enroll(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return utils.ErrUnauthorized
+	}
+
+	enrollment, err := h.mfa.Enroll(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	utils.WriteJSON(w, http.StatusOK, userdto.MFAEnrollResponse{
+		Secret:     enrollment.Secret,
+		OTPAuthURI: enrollment.URI,
+	})
+	return nil
+}
+
+// @Summary Confirm two-factor enrolment
+// @Description Enable two-factor authentication with a first TOTP code. The response holds single-use recovery codes, shown only once.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body userdto.ConfirmMFARequest true "TOTP code"
+// @Success 200 {object} userdto.MFAConfirmResponse
+// @Failure 400 {object} utils.AppError "Invalid payload or wrong code"
+// @Failure 401 {object} utils.AppError "Unauthorized"
+// @Failure 409 {object} utils.AppError "No pending enrolment, or already enabled"
+// @Security ApiKeyAuth
+// @Router /auth/me/mfa/confirm [post]
+func (h *MFAHandler) Confirm(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return utils.ErrUnauthorized
+	}
+
+	var req userdto.ConfirmMFARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Invalid MFA confirmation payload")
+		return utils.ErrInvalidPayload
+	}
+	if err := req.Validate(); err != nil {
+		return utils.ErrValidationFailed
+	}
+
+	codes, err := h.mfa.Confirm(ctx, userID, req.Code)
+	if err != nil {
+		return err
+	}
+
+	utils.WriteJSON(w, http.StatusOK, userdto.MFAConfirmResponse{RecoveryCodes: codes})
+	return nil
+}
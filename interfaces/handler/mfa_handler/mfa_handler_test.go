@@ -0,0 +1,83 @@
+package mfahandler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	authusecase "Goshop/application/usecase/auth_usecase"
+	mfahandler "Goshop/interfaces/handler/mfa_handler"
+	"Goshop/interfaces/middl"
+	"Goshop/interfaces/utils"
+)
+
+type fakeMFA struct {
+	enrollErr error
+	confirmed []string // "userID|code"
+}
+
+func (f *fakeMFA) Enroll(_ context.Context, userID string) (*authusecase.MFAEnrollment, error) {
+	if f.enrollErr != nil {
+		return nil, f.enrollErr
+	}
+	return &authusecase.MFAEnrollment{Secret: "JBSWY3DPEHPK3PXP", URI: "otpauth://totp/GoShop:" + userID}, nil
+}
+
+func (f *fakeMFA) Confirm(_ context.Context, userID, code string) ([]string, error) {
+	f.confirmed = append(f.confirmed, userID+"|"+code)
+	if code != "123456" {
+		return nil, utils.ErrMFAConfirmationFailed
+	}
+	return []string{"abcd-efgh", "ijkl-mnop"}, nil
+}
+
+// serveAs exécute la requête pour l'utilisateur connecté userID ("" : anonyme)
+func serveAs(h middl.HandlerWriteError, userID, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	if userID != "" {
+		req = req.WithContext(utils.WithUserID(req.Context(), userID))
+	}
+	w := httptest.NewRecorder()
+	middl.ErrorHandler(h).ServeHTTP(w, req)
+	return w
+}
+
+func TestEnroll(t *testing.T) {
+	h := mfahandler.NewMFAHandler(&fakeMFA{})
+
+	w := serveAs(h.Enroll, "user-1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var body map[string]string
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", body["secret"])
+	assert.Equal(t, "otpauth://totp/GoShop:user-1", body["otpauth_uri"])
+
+	assert.Equal(t, http.StatusUnauthorized, serveAs(h.Enroll, "", "").Code)
+
+	h = mfahandler.NewMFAHandler(&fakeMFA{enrollErr: utils.ErrMFAAlreadyEnabled})
+	assert.Equal(t, http.StatusConflict, serveAs(h.Enroll, "user-1", "").Code)
+}
+
+func TestConfirm(t *testing.T) {
+	mfa := &fakeMFA{}
+	h := mfahandler.NewMFAHandler(mfa)
+
+	w := serveAs(h.Confirm, "user-1", `{"code":"123456"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var body map[string][]string
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.Equal(t, []string{"abcd-efgh", "ijkl-mnop"}, body["recovery_codes"])
+
+	assert.Equal(t, http.StatusBadRequest, serveAs(h.Confirm, "user-1", `{"code":"000000"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serveAs(h.Confirm, "user-1", `{}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serveAs(h.Confirm, "user-1", `not json`).Code)
+	assert.Equal(t, http.StatusUnauthorized, serveAs(h.Confirm, "", `{"code":"123456"}`).Code)
+	assert.Equal(t, []string{"user-1|123456", "user-1|000000"}, mfa.confirmed)
+}
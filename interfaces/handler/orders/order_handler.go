@@ -11,19 +11,25 @@ import (
 	"Goshop/interfaces/utils"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 )
 
 type OrderHandler struct {
-	createOrderUsecase  *orderusecase.CreateOrderUsecase
-	getOrderByIdUsecase *orderusecase.GetOrderByIdUsecase
-	getAllOrderUsecase  *orderusecase.GetAllOrderUsecase
-	productRepo         repository.ProductRepository
+	createOrderUsecase           *orderusecase.CreateOrderUsecase
+	getOrderByIdUsecase          *orderusecase.GetOrderByIdUsecase
+	getAllOrderUsecase           *orderusecase.GetAllOrderUsecase
+	updateOrderStatusUsecase     *orderusecase.UpdateOrderStatusUsecase
+	getOrderStatusHistoryUsecase *orderusecase.GetOrderStatusHistoryUsecase
+	cancelOrderUsecase           *orderusecase.CancelOrderUsecase
+	productRepo                  repository.ProductRepository
 	//logger              *setupLogging.Logger
 }
 
@@ -37,14 +43,33 @@ func NewOrderHandler(
 	//logger *setupLogging.Logger,
 ) *OrderHandler {
 	return &OrderHandler{
-		createOrderUsecase:  orderusecase.NewCreateOrderUsecase(txManager, productRepo, customerRepo, orderItemRepo, orderRepo),
-		getOrderByIdUsecase: orderusecase.NewGetOrderByIdUsecase(orderRepo, txManager),
-		getAllOrderUsecase:  orderusecase.NewGetAllOrderUsecase(orderRepo, txManager),
-		productRepo:         productRepo,
+		createOrderUsecase:           orderusecase.NewCreateOrderUsecase(txManager, productRepo, customerRepo, orderItemRepo, orderRepo),
+		getOrderByIdUsecase:          orderusecase.NewGetOrderByIdUsecase(orderRepo, txManager),
+		getAllOrderUsecase:           orderusecase.NewGetAllOrderUsecase(orderRepo, txManager),
+		updateOrderStatusUsecase:     orderusecase.NewUpdateOrderStatusUsecase(orderRepo, txManager),
+		getOrderStatusHistoryUsecase: orderusecase.NewGetOrderStatusHistoryUsecase(orderRepo),
+		cancelOrderUsecase:           orderusecase.NewCancelOrderUsecase(txManager, orderRepo, productRepo),
+		productRepo:                  productRepo,
 		//logger:              logger.WithComponent("order_handler"),
 	}
 }
 
+// WithProductCache : commandes et annulations invalident le cache des
+// produits dont elles changent le stock
+func (h *OrderHandler) WithProductCache(cache repository.ProductCache) *OrderHandler {
+	h.createOrderUsecase.WithProductCache(cache)
+	h.cancelOrderUsecase.WithProductCache(cache)
+	return h
+}
+
+// WithVariants : commandes et annulations portent aussi sur le stock des
+// variantes (variant_id des articles)
+func (h *OrderHandler) WithVariants(variants repository.ProductVariantRepository) *OrderHandler {
+	h.createOrderUsecase.WithVariants(variants)
+	h.cancelOrderUsecase.WithVariants(variants)
+	return h
+}
+
 // ------------------------------------------------------------
 //
 //	CREATE ORDER
@@ -76,6 +101,13 @@ func (h *OrderHandler) CreateOrderHandler(w http.ResponseWriter, r *http.Request
 		return utils.ErrInvalidPayload
 	}
 
+	// customer_id omis : la commande est passée pour le client connecté
+	if req.CustomerID == "" {
+		if customerID, ok := utils.CustomerIDFromContext(ctx); ok {
+			req.CustomerID = customerID
+		}
+	}
+
 	logger.Debug().
 		Str("customer_id", req.CustomerID).
 		Int("items_count", len(req.Items)).
@@ -127,7 +159,8 @@ func (h *OrderHandler) CreateOrderHandler(w http.ResponseWriter, r *http.Request
 			return utils.ErrInternalServer
 		}
 
-		// Vérification du stock
+		// Vérification rapide du stock (indicative : la réservation atomique
+		// est faite par le usecase, dans la transaction)
 		if product.Stock < int(itemReq.Quantity) {
 			itemLogger.Warn().
 				Int("available_stock", product.Stock).
@@ -165,7 +198,7 @@ func (h *OrderHandler) CreateOrderHandler(w http.ResponseWriter, r *http.Request
 	orderEntity := &entity.Order{
 		CustomerID: req.CustomerID,
 		TotalCents: totalCents,
-		Status:     "pending",
+		Status:     entity.OrderStatusPending,
 		Items:      items,
 	}
 
@@ -188,6 +221,11 @@ func (h *OrderHandler) CreateOrderHandler(w http.ResponseWriter, r *http.Request
 				"status":      orderEntity.Status,
 			}).
 			Msg("Failed to create order")
+
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
 		return utils.ErrOrderCreateFail
 	}
 
@@ -298,10 +336,28 @@ func (h *OrderHandler) GetAllOrderHandler(w http.ResponseWriter, r *http.Request
 		Int("offset", offset).
 		Msg("Pagination parameters")
 
-	// Appel du usecase
+	filter, err := getOrderFilter(r)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Invalid order filter")
+		return err
+	}
+
+	// Pagination par curseur (enveloppe) ou par offset (tableau + X-Total-Count)
+	if utils.IsCursorRequest(r) {
+		return h.listOrdersByCursor(w, r, limit, filter)
+	}
+	if isPaginatedRequest(r) {
+		return h.listOrdersByOffset(w, r, limit, offset, filter)
+	}
+
+	// Sans paramètre : comportement historique, toutes les commandes
 	logger.Debug().Msg("Executing get all orders usecase")
 	orders, err := h.getAllOrderUsecase.Execute(ctx)
 	if err != nil {
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
 		logger.Error().
 			Err(err).
 			Stack().
@@ -338,6 +394,284 @@ func (h *OrderHandler) GetAllOrderHandler(w http.ResponseWriter, r *http.Request
 	return nil
 }
 
+// ------------------------------------------------------------
+//
+//	UPDATE ORDER STATUS
+//
+// ------------------------------------------------------------
+func (h *OrderHandler) UpdateOrderStatusHandler(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	start := time.Now()
+
+	id := chi.URLParam(r, "id")
+	logger := zerolog.Ctx(ctx)
+
+	logger.Info().
+		Str("order_id", id).
+		Str("method", r.Method).
+		Str("path", r.URL.Path).
+		Msg("Updating order status")
+
+	var req orderdto.UpdateOrderStatusRequestDto
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Str("content_type", r.Header.Get("Content-Type")).
+			Msg("Failed to decode JSON payload")
+		return utils.ErrInvalidPayload
+	}
+
+	if err := req.Validate(); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("requested_status", req.Status).
+			Msg("Order status validation failed")
+		return utils.ErrOrderInvalidStatus
+	}
+
+	// Une annulation doit remettre le stock en place
+	var order *entity.Order
+	var err error
+	if entity.NormalizeOrderStatus(req.Status) == entity.OrderStatusCancelled {
+		order, err = h.cancelOrderUsecase.Execute(ctx, id, req.Reason)
+	} else {
+		order, err = h.updateOrderStatusUsecase.Execute(ctx, id, req.Status, req.Reason)
+	}
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Str("requested_status", req.Status).
+			Dur("duration_before_error", time.Since(start)).
+			Msg("Failed to update order status")
+
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+		return utils.ErrOrderUpdateFail
+	}
+
+	response := mapper.ToOrderResponse(order)
+
+	logger.Info().
+		Str("order_id", response.ID).
+		Str("order_status", response.Status).
+		Dur("total_duration", time.Since(start)).
+		Int("http_status", http.StatusOK).
+		Msg("Order status updated successfully")
+
+	utils.WriteJSON(w, http.StatusOK, response)
+	return nil
+}
+
+// ------------------------------------------------------------
+//
+//	CANCEL ORDER
+//
+// ------------------------------------------------------------
+func (h *OrderHandler) CancelOrderHandler(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	start := time.Now()
+
+	id := chi.URLParam(r, "id")
+	logger := zerolog.Ctx(ctx)
+
+	logger.Info().
+		Str("order_id", id).
+		Str("method", r.Method).
+		Str("path", r.URL.Path).
+		Msg("Cancelling order")
+
+	// Le corps est optionnel : un POST vide annule sans motif
+	var req orderdto.CancelOrderRequestDto
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			logger.Error().
+				Err(err).
+				Str("content_type", r.Header.Get("Content-Type")).
+				Msg("Failed to decode JSON payload")
+			return utils.ErrInvalidPayload
+		}
+	}
+
+	if err := req.Validate(); err != nil {
+		logger.Warn().Err(err).Msg("Cancel request validation failed")
+		return utils.ErrValidationFailed
+	}
+
+	order, err := h.cancelOrderUsecase.Execute(ctx, id, req.Reason)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Dur("duration_before_error", time.Since(start)).
+			Msg("Failed to cancel order")
+
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+		return utils.ErrOrderCancelFail
+	}
+
+	response := mapper.ToOrderResponse(order)
+
+	logger.Info().
+		Str("order_id", response.ID).
+		Str("order_status", response.Status).
+		Dur("total_duration", time.Since(start)).
+		Int("http_status", http.StatusOK).
+		Msg("Order cancelled successfully")
+
+	utils.WriteJSON(w, http.StatusOK, response)
+	return nil
+}
+
+// ------------------------------------------------------------
+//
+//	GET ORDER STATUS HISTORY
+//
+// ------------------------------------------------------------
+func (h *OrderHandler) GetOrderStatusHistoryHandler(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	logger := zerolog.Ctx(ctx)
+
+	history, err := h.getOrderStatusHistoryUsecase.Execute(ctx, id)
+	if err != nil {
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+		logger.Error().Err(err).Str("order_id", id).Msg("Failed to retrieve order status history")
+		return utils.ErrInternalServer
+	}
+
+	utils.WriteJSON(w, http.StatusOK, mapper.ToOrderStatusHistoryResponse(history))
+	return nil
+}
+
+// ------------------------------------------------------------
+//
+//	GET MY ORDERS
+//
+// ------------------------------------------------------------
+
+// GetMyOrdersHandler liste les commandes du client rattaché à l'utilisateur
+// connecté (GET /api/me/orders). Mêmes paramètres que GET /api/orders
+// (limit, offset, cursor, status) ; le filtre customer_id est forcé sur le
+// client courant.
+func (h *OrderHandler) GetMyOrdersHandler(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	logger := zerolog.Ctx(ctx)
+
+	customerID, ok := utils.CustomerIDFromContext(ctx)
+	if !ok {
+		logger.Warn().Msg("No customer profile linked to the current user")
+		return utils.ErrCustomerProfileMissing
+	}
+
+	limit, offset := extractPaginationParams(r, logger)
+
+	filter, err := getOrderFilter(r)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Invalid order filter")
+		return err
+	}
+	filter.CustomerID = &customerID
+
+	if utils.IsCursorRequest(r) {
+		return h.listOrdersByCursor(w, r, limit, filter)
+	}
+	return h.listOrdersByOffset(w, r, limit, offset, filter)
+}
+
+const ordersCursorResource = "orders"
+
+func (h *OrderHandler) listOrdersByCursor(w http.ResponseWriter, r *http.Request, limit int, filter orderdto.OrderFilter) error {
+	ctx := r.Context()
+	logger := zerolog.Ctx(ctx)
+
+	page, err := utils.GetCursorPage(r, ordersCursorResource, limit)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Invalid pagination cursor")
+		return err
+	}
+
+	result, err := h.getAllOrderUsecase.ExecuteCursor(ctx, page, filter)
+	if err != nil {
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+		logger.Error().Err(err).Stack().Msg("Failed to retrieve orders page")
+		return utils.ErrInternalServer
+	}
+
+	response := make([]*orderdto.OrderResponseDto, len(result.Items))
+	for i, ord := range result.Items {
+		response[i] = mapper.ToOrderResponse(ord)
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.NewPage(ordersCursorResource, page, result, response))
+	return nil
+}
+
+func (h *OrderHandler) listOrdersByOffset(w http.ResponseWriter, r *http.Request, limit, offset int, filter orderdto.OrderFilter) error {
+	ctx := r.Context()
+	logger := zerolog.Ctx(ctx)
+
+	orders, total, err := h.getAllOrderUsecase.ExecuteWithPagination(ctx, limit, offset, filter)
+	if err != nil {
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+		logger.Error().Err(err).Stack().Msg("Failed to retrieve orders page")
+		return utils.ErrInternalServer
+	}
+
+	response := make([]*orderdto.OrderResponseDto, len(orders))
+	for i, ord := range orders {
+		response[i] = mapper.ToOrderResponse(ord)
+	}
+
+	w.Header().Set(utils.TOTAL_COUNT_HEADER, strconv.Itoa(total))
+	utils.WriteJSON(w, http.StatusOK, response)
+	return nil
+}
+
+// getOrderFilter lit ?status= et ?customer_id=
+func getOrderFilter(r *http.Request) (orderdto.OrderFilter, error) {
+	filter := orderdto.OrderFilter{}
+
+	if status := r.URL.Query().Get("status"); status != "" {
+		status = entity.NormalizeOrderStatus(status)
+		if !entity.IsValidOrderStatus(status) {
+			return filter, utils.ErrOrderInvalidStatus
+		}
+		filter.Status = &status
+	}
+	if customerID := r.URL.Query().Get("customer_id"); customerID != "" {
+		if _, err := uuid.Parse(customerID); err != nil {
+			return filter, utils.ErrValidationFailed
+		}
+		filter.CustomerID = &customerID
+	}
+
+	return filter, nil
+}
+
+// isPaginatedRequest : un paramètre de pagination ou de filtre active le mode offset
+func isPaginatedRequest(r *http.Request) bool {
+	query := r.URL.Query()
+	for _, param := range []string{"limit", "offset", "status", "customer_id"} {
+		if query.Has(param) {
+			return true
+		}
+	}
+	return false
+}
+
 // Helper: extract pagination params — now expects *setupLogging.Logger
 func extractPaginationParams(r *http.Request, logger *zerolog.Logger) (limit, offset int) {
 	limit = 50 // default
@@ -3,10 +3,12 @@
 import (
 	orderitemdto "Goshop/application/dto/orderItem_dto"
 	orderdto "Goshop/application/dto/order_dto"
+	paginationdto "Goshop/application/dto/pagination_dto"
 	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -18,8 +20,10 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"Goshop/domain/entity"
+	domainrepo "Goshop/domain/repository"
 	orderhandler "Goshop/interfaces/handler/orders"
 	"Goshop/interfaces/middl"
+	"Goshop/interfaces/utils"
 	"Goshop/mocks/repository"
 )
 
@@ -141,13 +145,12 @@ func TestOrderHandler_CreateOrder_Success(t *testing.T) {
 	// Customer check
 	mockCustomerRepoWithTX.EXPECT().FindByCustomerID(gomock.Any(), "customer-123").Return(customer, nil)
 
-	// Product check and stock update (avec transaction)
-	mockProductRepoWithTX.EXPECT().FindByID(gomock.Any(), "product-123").Return(product, nil)
-	mockProductRepoWithTX.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
-		func(ctx context.Context, p *entity.Product) (*entity.Product, error) {
-			// Stock should be reduced by 2
-			assert.Equal(t, 8, p.Stock)
-			return p, nil
+	// Réservation atomique du stock (avec transaction)
+	mockProductRepoWithTX.EXPECT().ReserveStock(gomock.Any(), "product-123", 2).DoAndReturn(
+		func(ctx context.Context, id string, quantity int) (*entity.Product, error) {
+			reserved := *product
+			reserved.Stock -= quantity
+			return &reserved, nil
 		})
 
 	// Order creation
@@ -472,6 +475,72 @@ func TestOrderHandler_CreateOrder_InsufficientStock(t *testing.T) {
 	assert.Equal(t, "PRODUCT_OUT_OF_STOCK", resp["code"])
 }
 
+// Le contrôle rapide du handler passe, mais une commande concurrente a vidé le
+// stock entre-temps : la réservation atomique du usecase doit refuser.
+func TestOrderHandler_CreateOrder_StockReservedConcurrently(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxMgr := repository.NewMockTxManager(ctrl)
+	mockTx := repository.NewMockTx(ctrl)
+	mockOrderRepo := repository.NewMockOrderRepository(ctrl)
+	mockOrderRepoWithTX := repository.NewMockOrderRepository(ctrl)
+	mockProductRepo := repository.NewMockProductRepository(ctrl)
+	mockProductRepoWithTX := repository.NewMockProductRepository(ctrl)
+	mockCustomerRepo := repository.NewMockCustomerRepositoryInterface(ctrl)
+	mockCustomerRepoWithTX := repository.NewMockCustomerRepositoryInterface(ctrl)
+	mockOrderItemRepo := repository.NewMockOrderItemRepository(ctrl)
+	mockOrderItemRepoWithTX := repository.NewMockOrderItemRepository(ctrl)
+
+	var db *sql.DB // placeholder
+
+	handler := orderhandler.NewOrderHandler(
+		db,
+		mockTxMgr,
+		mockOrderRepo,
+		mockProductRepo,
+		mockCustomerRepo,
+		mockOrderItemRepo,
+	)
+
+	reqBody := orderdto.OrderRequestDto{
+		CustomerID: "customer-123",
+		Items: []*orderitemdto.OrderItemRequestDto{
+			{ProductID: "product-123", Quantity: 1},
+		},
+	}
+
+	mockProductRepo.EXPECT().FindByID(gomock.Any(), "product-123").Return(createTestProduct("product-123", 1), nil)
+
+	mockTxMgr.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockProductRepo.EXPECT().WithTX(mockTx).Return(mockProductRepoWithTX)
+	mockCustomerRepo.EXPECT().WithTX(mockTx).Return(mockCustomerRepoWithTX)
+	mockOrderItemRepo.EXPECT().WithTX(mockTx).Return(mockOrderItemRepoWithTX)
+	mockOrderRepo.EXPECT().WithTX(mockTx).Return(mockOrderRepoWithTX)
+	mockCustomerRepoWithTX.EXPECT().FindByCustomerID(gomock.Any(), "customer-123").Return(createTestCustomer("customer-123"), nil)
+	mockProductRepoWithTX.EXPECT().ReserveStock(gomock.Any(), "product-123", 1).
+		Return(nil, fmt.Errorf("product product-123 has 0 in stock, 1 requested: %w", domainrepo.ErrInsufficientStock))
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	// Act
+	jsonBody, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/orders", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	httpHandler := middl.ErrorHandler(handler.CreateOrderHandler)
+	httpHandler.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp map[string]interface{}
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	require.NoError(t, err)
+	assert.Equal(t, "ORDER_INSUFFICIENT_STOCK", resp["code"])
+}
+
 func TestOrderHandler_GetOrderById_Success(t *testing.T) {
 	// Arrange
 	ctrl := gomock.NewController(t)
@@ -714,3 +783,334 @@ func TestOrderHandler_GetAllOrders_Empty(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, resp)
 }
+
+func TestOrderHandler_GetAllOrders_OffsetPagination(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := repository.NewMockOrderRepository(ctrl)
+	handler := orderhandler.NewOrderHandler(
+		nil,
+		repository.NewMockTxManager(ctrl),
+		mockOrderRepo,
+		repository.NewMockProductRepository(ctrl),
+		repository.NewMockCustomerRepositoryInterface(ctrl),
+		repository.NewMockOrderItemRepository(ctrl),
+	)
+
+	mockOrderRepo.EXPECT().FindAllWithPagination(gomock.Any(), 10, 20, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, limit, offset int, filter orderdto.OrderFilter) ([]*entity.Order, error) {
+			require.NotNil(t, filter.Status)
+			assert.Equal(t, "PAID", *filter.Status)
+			return []*entity.Order{createTestOrder("order-1", "customer-1", 1000)}, nil
+		})
+	mockOrderRepo.EXPECT().CountAll(gomock.Any(), gomock.Any()).Return(21, nil)
+
+	w := httptest.NewRecorder()
+	middl.ErrorHandler(handler.GetAllOrderHandler).ServeHTTP(w, httptest.NewRequest("GET", "/orders?limit=10&offset=20&status=paid", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "21", w.Header().Get(utils.TOTAL_COUNT_HEADER))
+
+	var resp []map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Len(t, resp, 1)
+}
+
+func TestOrderHandler_GetAllOrders_CursorPagination(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := repository.NewMockOrderRepository(ctrl)
+	handler := orderhandler.NewOrderHandler(
+		nil,
+		repository.NewMockTxManager(ctrl),
+		mockOrderRepo,
+		repository.NewMockProductRepository(ctrl),
+		repository.NewMockCustomerRepositoryInterface(ctrl),
+		repository.NewMockOrderItemRepository(ctrl),
+	)
+
+	mockOrderRepo.EXPECT().FindAllByCursor(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, page paginationdto.CursorPage, filter orderdto.OrderFilter) ([]*entity.Order, error) {
+			assert.Equal(t, 2, page.Limit, "une commande de plus pour détecter la page suivante")
+			return []*entity.Order{
+				createTestOrder("order-1", "customer-1", 1000),
+				createTestOrder("order-2", "customer-1", 2000),
+			}, nil
+		})
+
+	w := httptest.NewRecorder()
+	middl.ErrorHandler(handler.GetAllOrderHandler).ServeHTTP(w, httptest.NewRequest("GET", "/orders?cursor=&limit=1", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp paginationdto.Page[map[string]interface{}]
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "order-1", resp.Data[0]["id"])
+	assert.True(t, resp.Pagination.HasMore)
+	assert.NotEmpty(t, resp.Pagination.NextCursor)
+
+	// Un curseur de commandes n'est pas accepté sur une autre liste
+	_, _, err := utils.DecodeCursor("products", resp.Pagination.NextCursor)
+	assert.Error(t, err)
+}
+
+func TestOrderHandler_GetAllOrders_InvalidStatusFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := orderhandler.NewOrderHandler(
+		nil,
+		repository.NewMockTxManager(ctrl),
+		repository.NewMockOrderRepository(ctrl),
+		repository.NewMockProductRepository(ctrl),
+		repository.NewMockCustomerRepositoryInterface(ctrl),
+		repository.NewMockOrderItemRepository(ctrl),
+	)
+
+	w := httptest.NewRecorder()
+	middl.ErrorHandler(handler.GetAllOrderHandler).ServeHTTP(w, httptest.NewRequest("GET", "/orders?status=LOST", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestOrderHandler_UpdateOrderStatus_Success(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxMgr := repository.NewMockTxManager(ctrl)
+	mockTx := repository.NewMockTx(ctrl)
+	mockOrderRepo := repository.NewMockOrderRepository(ctrl)
+	mockOrderRepoWithTX := repository.NewMockOrderRepository(ctrl)
+	mockProductRepo := repository.NewMockProductRepository(ctrl)
+	mockCustomerRepo := repository.NewMockCustomerRepositoryInterface(ctrl)
+	mockOrderItemRepo := repository.NewMockOrderItemRepository(ctrl)
+
+	var db *sql.DB // placeholder
+
+	handler := orderhandler.NewOrderHandler(
+		db,
+		mockTxMgr,
+		mockOrderRepo,
+		mockProductRepo,
+		mockCustomerRepo,
+		mockOrderItemRepo,
+	)
+
+	order := createTestOrder("order-123", "customer-123", 2000)
+	paid := createTestOrder("order-123", "customer-123", 2000)
+	paid.Status = entity.OrderStatusPaid
+
+	mockTxMgr.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockOrderRepo.EXPECT().WithTX(mockTx).Return(mockOrderRepoWithTX)
+	mockOrderRepoWithTX.EXPECT().FindByID(gomock.Any(), "order-123").Return(order, nil)
+	mockOrderRepoWithTX.EXPECT().UpdateStatus(gomock.Any(), "order-123", entity.OrderStatusPending, entity.OrderStatusPaid).Return(paid, nil)
+	mockOrderRepoWithTX.EXPECT().AddStatusHistory(gomock.Any(), gomock.Any()).Return(nil)
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Rollback().Return(nil).AnyTimes()
+
+	// Act
+	body, _ := json.Marshal(orderdto.UpdateOrderStatusRequestDto{Status: "PAID", Reason: "payment received"})
+	req := httptest.NewRequest("PATCH", "/orders/order-123/status", bytes.NewBuffer(body))
+	req = setupChiContext(req, "order-123")
+	w := httptest.NewRecorder()
+
+	httpHandler := middl.ErrorHandler(handler.UpdateOrderStatusHandler)
+	httpHandler.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]interface{}
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	require.NoError(t, err)
+	assert.Equal(t, "order-123", resp["id"])
+	assert.Equal(t, "PAID", resp["status"])
+}
+
+func TestOrderHandler_UpdateOrderStatus_UnknownStatus(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var db *sql.DB // placeholder
+
+	handler := orderhandler.NewOrderHandler(
+		db,
+		repository.NewMockTxManager(ctrl),
+		repository.NewMockOrderRepository(ctrl),
+		repository.NewMockProductRepository(ctrl),
+		repository.NewMockCustomerRepositoryInterface(ctrl),
+		repository.NewMockOrderItemRepository(ctrl),
+	)
+
+	// Act
+	body, _ := json.Marshal(orderdto.UpdateOrderStatusRequestDto{Status: "TELEPORTED"})
+	req := httptest.NewRequest("PATCH", "/orders/order-123/status", bytes.NewBuffer(body))
+	req = setupChiContext(req, "order-123")
+	w := httptest.NewRecorder()
+
+	httpHandler := middl.ErrorHandler(handler.UpdateOrderStatusHandler)
+	httpHandler.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp map[string]interface{}
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	require.NoError(t, err)
+	assert.Equal(t, "INVALID_ORDER_STATUS", resp["code"])
+}
+
+func TestOrderHandler_UpdateOrderStatus_InvalidTransition(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxMgr := repository.NewMockTxManager(ctrl)
+	mockTx := repository.NewMockTx(ctrl)
+	mockOrderRepo := repository.NewMockOrderRepository(ctrl)
+	mockOrderRepoWithTX := repository.NewMockOrderRepository(ctrl)
+
+	var db *sql.DB // placeholder
+
+	handler := orderhandler.NewOrderHandler(
+		db,
+		mockTxMgr,
+		mockOrderRepo,
+		repository.NewMockProductRepository(ctrl),
+		repository.NewMockCustomerRepositoryInterface(ctrl),
+		repository.NewMockOrderItemRepository(ctrl),
+	)
+
+	delivered := createTestOrder("order-123", "customer-123", 2000)
+	delivered.Status = entity.OrderStatusDelivered
+
+	mockTxMgr.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockOrderRepo.EXPECT().WithTX(mockTx).Return(mockOrderRepoWithTX)
+	mockOrderRepoWithTX.EXPECT().FindByID(gomock.Any(), "order-123").Return(delivered, nil)
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	// Act : une commande livrée ne peut pas repasser en PENDING
+	body, _ := json.Marshal(orderdto.UpdateOrderStatusRequestDto{Status: "PENDING"})
+	req := httptest.NewRequest("PATCH", "/orders/order-123/status", bytes.NewBuffer(body))
+	req = setupChiContext(req, "order-123")
+	w := httptest.NewRecorder()
+
+	httpHandler := middl.ErrorHandler(handler.UpdateOrderStatusHandler)
+	httpHandler.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var resp map[string]interface{}
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	require.NoError(t, err)
+	assert.Equal(t, "INVALID_ORDER_TRANSITION", resp["code"])
+}
+
+func TestOrderHandler_CancelOrder_Success(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxMgr := repository.NewMockTxManager(ctrl)
+	mockTx := repository.NewMockTx(ctrl)
+	mockOrderRepo := repository.NewMockOrderRepository(ctrl)
+	mockOrderRepoWithTX := repository.NewMockOrderRepository(ctrl)
+	mockProductRepo := repository.NewMockProductRepository(ctrl)
+	mockProductRepoWithTX := repository.NewMockProductRepository(ctrl)
+
+	var db *sql.DB // placeholder
+
+	handler := orderhandler.NewOrderHandler(
+		db,
+		mockTxMgr,
+		mockOrderRepo,
+		mockProductRepo,
+		repository.NewMockCustomerRepositoryInterface(ctrl),
+		repository.NewMockOrderItemRepository(ctrl),
+	)
+
+	order := createTestOrder("order-123", "customer-123", 2000)
+	cancelled := createTestOrder("order-123", "customer-123", 2000)
+	cancelled.Status = entity.OrderStatusCancelled
+
+	mockTxMgr.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockOrderRepo.EXPECT().WithTX(mockTx).Return(mockOrderRepoWithTX)
+	mockProductRepo.EXPECT().WithTX(mockTx).Return(mockProductRepoWithTX)
+	mockOrderRepoWithTX.EXPECT().FindByID(gomock.Any(), "order-123").Return(order, nil)
+	mockOrderRepoWithTX.EXPECT().UpdateStatus(gomock.Any(), "order-123", entity.OrderStatusPending, entity.OrderStatusCancelled).Return(cancelled, nil)
+	mockProductRepoWithTX.EXPECT().RestoreStock(gomock.Any(), "product-1", 2).Return(nil)
+	mockOrderRepoWithTX.EXPECT().AddStatusHistory(gomock.Any(), gomock.Any()).Return(nil)
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Rollback().Return(nil).AnyTimes()
+
+	// Act
+	body, _ := json.Marshal(orderdto.CancelOrderRequestDto{Reason: "out of budget"})
+	req := httptest.NewRequest("POST", "/orders/order-123/cancel", bytes.NewBuffer(body))
+	req = setupChiContext(req, "order-123")
+	w := httptest.NewRecorder()
+
+	httpHandler := middl.ErrorHandler(handler.CancelOrderHandler)
+	httpHandler.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]interface{}
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	require.NoError(t, err)
+	assert.Equal(t, "CANCELLED", resp["status"])
+}
+
+func TestOrderHandler_CancelOrder_NotCancellable(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxMgr := repository.NewMockTxManager(ctrl)
+	mockTx := repository.NewMockTx(ctrl)
+	mockOrderRepo := repository.NewMockOrderRepository(ctrl)
+	mockOrderRepoWithTX := repository.NewMockOrderRepository(ctrl)
+	mockProductRepo := repository.NewMockProductRepository(ctrl)
+
+	var db *sql.DB // placeholder
+
+	handler := orderhandler.NewOrderHandler(
+		db,
+		mockTxMgr,
+		mockOrderRepo,
+		mockProductRepo,
+		repository.NewMockCustomerRepositoryInterface(ctrl),
+		repository.NewMockOrderItemRepository(ctrl),
+	)
+
+	alreadyCancelled := createTestOrder("order-123", "customer-123", 2000)
+	alreadyCancelled.Status = entity.OrderStatusCancelled
+
+	mockTxMgr.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil)
+	mockOrderRepo.EXPECT().WithTX(mockTx).Return(mockOrderRepoWithTX)
+	mockProductRepo.EXPECT().WithTX(mockTx).Return(repository.NewMockProductRepository(ctrl))
+	mockOrderRepoWithTX.EXPECT().FindByID(gomock.Any(), "order-123").Return(alreadyCancelled, nil)
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	// Act : corps vide autorisé
+	req := httptest.NewRequest("POST", "/orders/order-123/cancel", nil)
+	req = setupChiContext(req, "order-123")
+	w := httptest.NewRecorder()
+
+	httpHandler := middl.ErrorHandler(handler.CancelOrderHandler)
+	httpHandler.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var resp map[string]interface{}
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	require.NoError(t, err)
+	assert.Equal(t, "ORDER_NOT_CANCELLABLE", resp["code"])
+}
@@ -5,24 +5,36 @@ import (
 	dto "Goshop/application/dto/product_dto"
 	productuscase "Goshop/application/usecase/product_uscase"
 	"Goshop/domain/entity"
+	userentity "Goshop/domain/entity/user_entity"
 	"Goshop/domain/repository"
+	"Goshop/domain/storage"
 	"Goshop/interfaces/utils"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog"
 )
 
+// Ressource signée dans les curseurs de pagination
+const productsCursorResource = "products"
+
 type ProductHandler struct {
 	createProductUsecase  *productuscase.CreateProductUsecase
 	listProductUsecase    *productuscase.ListProductUsecase
 	getProductByIdUsecase *productuscase.GetProductByIdUsecase
 	updateProductUsecase  *productuscase.UpdateProductUsecase
 	deleteProductUsecase  *productuscase.DeleteProductUsecase
+	restoreProductUsecase *productuscase.RestoreProductUsecase
+	purgeProductUsecase   *productuscase.PurgeProductUsecase
+	categories            repository.CategoryRepository
+	variants              repository.ProductVariantRepository
+	images                repository.ProductImageRepository
 	//logger                *setupLogging.Logger
 }
 
@@ -37,10 +49,55 @@ func NewProductHandler(
 		getProductByIdUsecase: productuscase.NewGetProductByIdUsecase(repo, txManager),
 		updateProductUsecase:  productuscase.NewUpdateProductUsecase(repo, txManager),
 		deleteProductUsecase:  productuscase.NewDeleteProductUsecase(repo, txManager),
+		restoreProductUsecase: productuscase.NewRestoreProductUsecase(repo, txManager),
+		purgeProductUsecase:   productuscase.NewPurgeProductUsecase(repo, txManager),
 		//logger:                logger.WithComponent("product_handler"),
 	}
 }
 
+// WithProductCache : lecture de GET /api/products/{id} à travers le cache,
+// invalidé par les mises à jour, suppressions, restaurations et purges
+func (ph *ProductHandler) WithProductCache(cache repository.ProductCache) *ProductHandler {
+	ph.getProductByIdUsecase.WithCache(cache)
+	ph.updateProductUsecase.WithCache(cache)
+	ph.deleteProductUsecase.WithCache(cache)
+	ph.restoreProductUsecase.WithCache(cache)
+	ph.purgeProductUsecase.WithCache(cache)
+	return ph
+}
+
+// WithCategories : rattachement des produits aux catégories (category_ids),
+// fil d'Ariane dans les réponses et listing par catégorie
+func (ph *ProductHandler) WithCategories(categories repository.CategoryRepository) *ProductHandler {
+	ph.categories = categories
+	ph.createProductUsecase.WithCategories(categories)
+	ph.listProductUsecase.WithCategories(categories)
+	ph.getProductByIdUsecase.WithCategories(categories)
+	ph.updateProductUsecase.WithCategories(categories)
+	return ph
+}
+
+// WithVariants : variante par défaut créée avec le produit, variantes dans
+// les réponses et stock du produit égal à la somme de ses variantes
+func (ph *ProductHandler) WithVariants(variants repository.ProductVariantRepository) *ProductHandler {
+	ph.variants = variants
+	ph.createProductUsecase.WithVariants(variants)
+	ph.listProductUsecase.WithVariants(variants)
+	ph.getProductByIdUsecase.WithVariants(variants)
+	ph.updateProductUsecase.WithVariants(variants)
+	return ph
+}
+
+// WithImages : image principale dans les réponses ; la purge d'un produit
+// supprime ses images et leur contenu
+func (ph *ProductHandler) WithImages(images repository.ProductImageRepository, blobs storage.BlobStore) *ProductHandler {
+	ph.images = images
+	ph.listProductUsecase.WithImages(images)
+	ph.getProductByIdUsecase.WithImages(images)
+	ph.purgeProductUsecase.WithImages(images, blobs)
+	return ph
+}
+
 func (ph *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) error {
 	ctx := r.Context()
 	start := time.Now()
@@ -89,6 +146,22 @@ func (ph *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request)
 }
 
 func (ph *ProductHandler) GetAllProducts(w http.ResponseWriter, r *http.Request) error {
+	return ph.listProducts(w, r, "")
+}
+
+// GetProductsByCategory liste les produits de la catégorie {slug} et de
+// toutes ses sous-catégories, avec les mêmes filtres et paginations que
+// GET /api/products
+func (ph *ProductHandler) GetProductsByCategory(w http.ResponseWriter, r *http.Request) error {
+	slug := chi.URLParam(r, "slug")
+	if !entity.IsValidCategorySlug(slug) {
+		return utils.ErrCategoryNotFound
+	}
+	return ph.listProducts(w, r, slug)
+}
+
+// listProducts : categorySlug non vide restreint la liste à cette catégorie
+func (ph *ProductHandler) listProducts(w http.ResponseWriter, r *http.Request, categorySlug string) error {
 	ctx := r.Context()
 	start := time.Now()
 	//logger := ph.logger.WithOperation("list_products")
@@ -102,12 +175,32 @@ func (ph *ProductHandler) GetAllProducts(w http.ResponseWriter, r *http.Request)
 
 	limit, offset := getPaginationParams(r, logger)
 
+	filter, err := getProductFilter(r)
+	if err != nil {
+		logger.Warn().Err(err).Str("query", r.URL.RawQuery).Msg("Invalid product filter")
+		return utils.ErrProductInvalidFilter
+	}
+
+	if categorySlug != "" {
+		filter.CategorySlug = &categorySlug
+	}
+
+	if filter.IncludeDeleted && !utils.HasPermission(ctx, userentity.PermProductsWrite) {
+		logger.Warn().Msg("include_deleted requires products:write")
+		return utils.ErrForbidden
+	}
+
+	if utils.IsCursorRequest(r) {
+		return ph.listProductsByCursor(w, r, limit, filter)
+	}
+
 	logger.Debug().
 		Int("limit", limit).
 		Int("offset", offset).
+		Interface("filter", filter).
 		Msg("Pagination parameters")
 
-	products, err := ph.listProductUsecase.Execute(ctx, limit, offset)
+	products, total, err := ph.listProductUsecase.Execute(ctx, limit, offset, filter)
 	if err != nil {
 		logger.Error().
 			Err(err).
@@ -124,13 +217,47 @@ func (ph *ProductHandler) GetAllProducts(w http.ResponseWriter, r *http.Request)
 
 	logger.Info().
 		Int("count", len(products)).
+		Int("total", total).
 		Dur("duration", time.Since(start)).
 		Msg("Products listed successfully")
 
+	w.Header().Set(utils.TOTAL_COUNT_HEADER, strconv.Itoa(total))
 	utils.WriteJSON(w, http.StatusOK, products)
 	return nil
 }
 
+// listProductsByCursor répond avec l'enveloppe { data, pagination }.
+// La pagination par curseur suit toujours l'ordre created_at DESC.
+func (ph *ProductHandler) listProductsByCursor(w http.ResponseWriter, r *http.Request, limit int, filter dto.ProductFilter) error {
+	ctx := r.Context()
+	logger := zerolog.Ctx(ctx)
+
+	if filter.SortBy != "" && (filter.SortBy != dto.ProductSortCreatedAt || !filter.SortDesc) {
+		logger.Warn().Str("sort", filter.SortBy).Msg("Sort not supported with cursor pagination")
+		return utils.ErrProductInvalidFilter
+	}
+
+	page, err := utils.GetCursorPage(r, productsCursorResource, limit)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Invalid pagination cursor")
+		return err
+	}
+
+	result, err := ph.listProductUsecase.ExecuteCursor(ctx, page, filter)
+	if err != nil {
+		logger.Error().Err(err).Int("limit", limit).Msg("Failed to list products by cursor")
+
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+		return utils.ErrInternalServer
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.NewPage(productsCursorResource, page, result, result.Items))
+	return nil
+}
+
 func getPaginationParams(r *http.Request, logger *zerolog.Logger) (limit, offset int) {
 	limit = 50
 	offset = 0
@@ -160,6 +287,73 @@ func getPaginationParams(r *http.Request, logger *zerolog.Logger) (limit, offset
 	return limit, offset
 }
 
+// getProductFilter lit les critères de recherche :
+// ?q=running+shoes&min_price=1000&max_price=5000&in_stock=true&created_after=2025-01-01&category=chaussures&sort=-price
+func getProductFilter(r *http.Request) (dto.ProductFilter, error) {
+	query := r.URL.Query()
+	filter := dto.ProductFilter{}
+
+	if q := strings.TrimSpace(query.Get("q")); q != "" {
+		filter.Query = &q
+	}
+
+	for param, target := range map[string]**int64{
+		"min_price": &filter.MinPriceCents,
+		"max_price": &filter.MaxPriceCents,
+	} {
+		raw := query.Get(param)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("%s must be an integer amount in cents", param)
+		}
+		*target = &v
+	}
+
+	if raw := query.Get("in_stock"); raw != "" {
+		inStock, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filter, errors.New("in_stock must be a boolean")
+		}
+		filter.InStock = inStock
+	}
+
+	if raw := query.Get("created_after"); raw != "" {
+		createdAfter, err := parseFilterDate(raw)
+		if err != nil {
+			return filter, errors.New("created_after must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+		}
+		filter.CreatedAfter = &createdAfter
+	}
+
+	if category := strings.TrimSpace(query.Get("category")); category != "" {
+		filter.CategorySlug = &category
+	}
+
+	includeDeleted, err := utils.ParseIncludeDeleted(r)
+	if err != nil {
+		return filter, err
+	}
+	filter.IncludeDeleted = includeDeleted
+
+	if raw := query.Get("sort"); raw != "" {
+		if err := filter.SetSort(raw); err != nil {
+			return filter, err
+		}
+	}
+
+	return filter, filter.Validate()
+}
+
+func parseFilterDate(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", raw)
+}
+
 func (ph *ProductHandler) GetProductById(w http.ResponseWriter, r *http.Request) error {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
@@ -170,7 +364,22 @@ func (ph *ProductHandler) GetProductById(w http.ResponseWriter, r *http.Request)
 		Str("product_id", id).
 		Msg("Getting product by ID")
 
-	product, err := ph.getProductByIdUsecase.Execute(ctx, id)
+	includeDeleted, err := utils.ParseIncludeDeleted(r)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Invalid include_deleted parameter")
+		return utils.ErrProductInvalidFilter
+	}
+
+	get := ph.getProductByIdUsecase.Execute
+	if includeDeleted {
+		if !utils.HasPermission(ctx, userentity.PermProductsWrite) {
+			logger.Warn().Msg("include_deleted requires products:write")
+			return utils.ErrForbidden
+		}
+		get = ph.getProductByIdUsecase.ExecuteIncludingDeleted
+	}
+
+	product, err := get(ctx, id)
 	if err != nil {
 		// Vérifier si c'est une erreur "produit non trouvé"
 		var appErr *utils.AppError
@@ -229,7 +438,7 @@ func (ph *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request)
 		Stock:       req.Stock,
 	}
 
-	updated, err := ph.updateProductUsecase.Execute(ctx, product)
+	updated, err := ph.updateProductUsecase.ExecuteWithCategories(ctx, product, req.CategoryIDs)
 	if err != nil {
 		// Vérifier si c'est une erreur "produit non trouvé"
 		var appErr *utils.AppError
@@ -251,14 +460,15 @@ func (ph *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request)
 		return utils.ErrProductUpdateFail
 	}
 
-	response := dto.ProductResponse{
-		ID:          updated.ID,
-		Name:        updated.Name,
-		Description: updated.Description,
-		PriceCents:  updated.PriceCents,
-		Stock:       updated.Stock,
-		CreatedAt:   updated.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt:   updated.UpdatedAt.Format("2006-01-02 15:04:05"),
+	response := dto.NewProductResponse(updated)
+	if err := productuscase.LoadProductCategories(ctx, ph.categories, response); err != nil {
+		logger.Warn().Err(err).Msg("Failed to load product categories for response")
+	}
+	if err := productuscase.LoadProductVariants(ctx, ph.variants, response); err != nil {
+		logger.Warn().Err(err).Msg("Failed to load product variants for response")
+	}
+	if err := productuscase.LoadPrimaryImages(ctx, ph.images, response); err != nil {
+		logger.Warn().Err(err).Msg("Failed to load product image for response")
 	}
 
 	logger.Info().
@@ -311,3 +521,68 @@ func (ph *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusNoContent)
 	return nil
 }
+
+// RestoreProduct remet au catalogue un produit archivé par DELETE
+func (ph *ProductHandler) RestoreProduct(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	logger := zerolog.Ctx(ctx)
+
+	logger.Info().
+		Str("product_id", id).
+		Msg("Restoring product")
+
+	restored, err := ph.restoreProductUsecase.Execute(ctx, id)
+	if err != nil {
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+		logger.Error().
+			Err(err).
+			Msg("Failed to restore product")
+		return utils.ErrProductRestoreFail
+	}
+
+	response := dto.NewProductResponse(restored)
+	if err := productuscase.LoadProductCategories(ctx, ph.categories, response); err != nil {
+		logger.Warn().Err(err).Msg("Failed to load product categories for response")
+	}
+	if err := productuscase.LoadProductVariants(ctx, ph.variants, response); err != nil {
+		logger.Warn().Err(err).Msg("Failed to load product variants for response")
+	}
+	if err := productuscase.LoadPrimaryImages(ctx, ph.images, response); err != nil {
+		logger.Warn().Err(err).Msg("Failed to load product image for response")
+	}
+
+	utils.WriteJSON(w, http.StatusOK, response)
+	return nil
+}
+
+// PurgeProduct supprime définitivement un produit archivé. La réponse
+// indique si la ligne a été supprimée : un produit commandé reste archivé
+// pour l'historique des commandes, seules ses images sont supprimées.
+func (ph *ProductHandler) PurgeProduct(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	logger := zerolog.Ctx(ctx)
+
+	logger.Info().
+		Str("product_id", id).
+		Msg("Purging product")
+
+	deleted, err := ph.purgeProductUsecase.Execute(ctx, id)
+	if err != nil {
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+		logger.Error().
+			Err(err).
+			Msg("Failed to purge product")
+		return utils.ErrProductPurgeFail
+	}
+
+	utils.WriteJSON(w, http.StatusOK, map[string]bool{"product_deleted": deleted})
+	return nil
+}
@@ -16,10 +16,13 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	paginationdto "Goshop/application/dto/pagination_dto"
 	dto "Goshop/application/dto/product_dto"
 	"Goshop/domain/entity"
+	userentity "Goshop/domain/entity/user_entity"
 	producthandler "Goshop/interfaces/handler/product"
 	"Goshop/interfaces/middl"
+	"Goshop/interfaces/utils"
 	mockrepo "Goshop/mocks/repository"
 )
 
@@ -323,12 +326,14 @@ func TestProductHandler_GetAllProducts_Success(t *testing.T) {
 	}
 
 	// ✅ GET ALL n'utilise PAS de transaction
-	mockRepo.EXPECT().FindAll(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
-		func(ctx context.Context, limit, offset int) ([]*entity.Product, error) {
+	mockRepo.EXPECT().FindAll(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, limit, offset int, filter dto.ProductFilter) ([]*entity.Product, error) {
 			assert.Equal(t, 50, limit) // Default limit
 			assert.Equal(t, 0, offset) // Default offset
+			assert.Equal(t, dto.ProductFilter{}, filter)
 			return products, nil
 		})
+	mockRepo.EXPECT().CountAll(gomock.Any(), gomock.Any()).Return(3, nil)
 	// ❌ NE PAS mocker: BeginTx, WithTX, Commit, Rollback
 
 	req := httptest.NewRequest("GET", "/products", nil)
@@ -339,12 +344,199 @@ func TestProductHandler_GetAllProducts_Success(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 
+	assert.Equal(t, "3", w.Header().Get(utils.TOTAL_COUNT_HEADER))
+
 	var resp []dto.ProductResponse
 	err := json.NewDecoder(w.Body).Decode(&resp)
 	require.NoError(t, err)
 	assert.Len(t, resp, 3)
 }
 
+func TestProductHandler_GetAllProducts_WithFilters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockrepo.NewMockProductRepository(ctrl)
+	mockTxMgr := mockrepo.NewMockTxManager(ctrl)
+	handler := producthandler.NewProductHandler(mockRepo, mockTxMgr)
+
+	var captured dto.ProductFilter
+	mockRepo.EXPECT().FindAll(gomock.Any(), 20, 40, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, limit, offset int, filter dto.ProductFilter) ([]*entity.Product, error) {
+			captured = filter
+			return []*entity.Product{createTestProduct("1")}, nil
+		})
+	mockRepo.EXPECT().CountAll(gomock.Any(), gomock.Any()).Return(41, nil)
+
+	req := httptest.NewRequest("GET", "/products?q=running+shoes&min_price=1000&max_price=5000&in_stock=true&created_after=2025-01-01&sort=-price&limit=20&offset=40", nil)
+	w := httptest.NewRecorder()
+
+	middl.ErrorHandler(handler.GetAllProducts).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "41", w.Header().Get(utils.TOTAL_COUNT_HEADER))
+
+	require.NotNil(t, captured.Query)
+	assert.Equal(t, "running shoes", *captured.Query)
+	require.NotNil(t, captured.MinPriceCents)
+	assert.Equal(t, int64(1000), *captured.MinPriceCents)
+	require.NotNil(t, captured.MaxPriceCents)
+	assert.Equal(t, int64(5000), *captured.MaxPriceCents)
+	assert.True(t, captured.InStock)
+	require.NotNil(t, captured.CreatedAfter)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), *captured.CreatedAfter)
+	assert.Equal(t, dto.ProductSortPrice, captured.SortBy)
+	assert.True(t, captured.SortDesc)
+}
+
+func TestProductHandler_GetAllProducts_InvalidFilter(t *testing.T) {
+	cases := map[string]string{
+		"unknown sort":      "/products?sort=stock%3BDROP+TABLE+products",
+		"relevance without": "/products?sort=relevance",
+		"price not integer": "/products?min_price=10.5",
+		"price range":       "/products?min_price=5000&max_price=1000",
+		"negative price":    "/products?max_price=-1",
+		"in_stock":          "/products?in_stock=maybe",
+		"created_after":     "/products?created_after=yesterday",
+		"category":          "/products?category=Not+A+Slug",
+		"include_deleted":   "/products?include_deleted=maybe",
+	}
+
+	for name, target := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Le repository ne doit pas être appelé
+			mockRepo := mockrepo.NewMockProductRepository(ctrl)
+			handler := producthandler.NewProductHandler(mockRepo, mockrepo.NewMockTxManager(ctrl))
+
+			w := httptest.NewRecorder()
+			middl.ErrorHandler(handler.GetAllProducts).ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var resp map[string]interface{}
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			assert.Equal(t, "INVALID_PRODUCT_FILTER", resp["code"])
+		})
+	}
+}
+
+func TestProductHandler_GetProductsByCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockrepo.NewMockProductRepository(ctrl)
+	mockCategories := mockrepo.NewMockCategoryRepository(ctrl)
+	handler := producthandler.NewProductHandler(mockRepo, mockrepo.NewMockTxManager(ctrl)).WithCategories(mockCategories)
+
+	electronics := &entity.Category{ID: "cat-1", Name: "Électronique", Slug: "electronique", Path: "electronique"}
+	mockCategories.EXPECT().FindBySlug(gomock.Any(), "electronique").Return(electronics, nil)
+	mockRepo.EXPECT().FindAll(gomock.Any(), 50, 0, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, limit, offset int, filter dto.ProductFilter) ([]*entity.Product, error) {
+			require.NotNil(t, filter.CategorySlug)
+			assert.Equal(t, "electronique", *filter.CategorySlug)
+			assert.True(t, filter.InStock)
+			return []*entity.Product{createTestProduct("1")}, nil
+		})
+	mockRepo.EXPECT().CountAll(gomock.Any(), gomock.Any()).Return(1, nil)
+	mockCategories.EXPECT().FindProductCategories(gomock.Any(), []string{"1"}).Return([]*entity.ProductCategory{
+		{ProductID: "1", Category: electronics, Breadcrumb: []*entity.Category{electronics}},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/categories/electronique/products?in_stock=true", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("slug", "electronique")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	middl.ErrorHandler(handler.GetProductsByCategory).ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var products []dto.ProductResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&products))
+	require.Len(t, products, 1)
+	require.Len(t, products[0].Categories, 1)
+	assert.Equal(t, "electronique", products[0].Categories[0].Breadcrumb[0].Slug)
+}
+
+func TestProductHandler_GetAllProducts_CursorPagination(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockrepo.NewMockProductRepository(ctrl)
+	handler := producthandler.NewProductHandler(mockRepo, mockrepo.NewMockTxManager(ctrl))
+
+	// Première page : 2 demandés, le repository en renvoie 3 (limit+1)
+	page1 := []*entity.Product{createTestProduct("1"), createTestProduct("2"), createTestProduct("3")}
+	mockRepo.EXPECT().FindAllByCursor(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, page paginationdto.CursorPage, filter dto.ProductFilter) ([]*entity.Product, error) {
+			assert.Equal(t, 3, page.Limit)
+			assert.Nil(t, page.After)
+			return page1, nil
+		})
+
+	w := httptest.NewRecorder()
+	middl.ErrorHandler(handler.GetAllProducts).ServeHTTP(w, httptest.NewRequest("GET", "/products?cursor=&limit=2", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var first paginationdto.Page[dto.ProductResponse]
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&first))
+	assert.Len(t, first.Data, 2)
+	assert.True(t, first.Pagination.HasMore)
+	assert.Empty(t, first.Pagination.PrevCursor)
+	require.NotEmpty(t, first.Pagination.NextCursor)
+
+	// Page suivante : le curseur pointe sur le dernier produit affiché
+	mockRepo.EXPECT().FindAllByCursor(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, page paginationdto.CursorPage, filter dto.ProductFilter) ([]*entity.Product, error) {
+			require.NotNil(t, page.After)
+			assert.Equal(t, "2", page.After.ID)
+			assert.True(t, page1[1].CreatedAt.Equal(page.After.CreatedAt))
+			assert.False(t, page.Backward)
+			return page1[2:], nil
+		})
+
+	w = httptest.NewRecorder()
+	middl.ErrorHandler(handler.GetAllProducts).ServeHTTP(w, httptest.NewRequest("GET", "/products?limit=2&cursor="+first.Pagination.NextCursor, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var second paginationdto.Page[dto.ProductResponse]
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&second))
+	assert.Len(t, second.Data, 1)
+	assert.False(t, second.Pagination.HasMore)
+	assert.NotEmpty(t, second.Pagination.PrevCursor)
+}
+
+func TestProductHandler_GetAllProducts_CursorRejected(t *testing.T) {
+	cases := map[string]struct {
+		target string
+		code   string
+	}{
+		"invalid cursor":     {"/products?cursor=not-a-cursor", "INVALID_CURSOR"},
+		"unsupported sort":   {"/products?cursor=&sort=price", "INVALID_PRODUCT_FILTER"},
+		"ascending creation": {"/products?cursor=&sort=created_at", "INVALID_PRODUCT_FILTER"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			handler := producthandler.NewProductHandler(mockrepo.NewMockProductRepository(ctrl), mockrepo.NewMockTxManager(ctrl))
+
+			w := httptest.NewRecorder()
+			middl.ErrorHandler(handler.GetAllProducts).ServeHTTP(w, httptest.NewRequest("GET", tc.target, nil))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var resp map[string]interface{}
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			assert.Equal(t, tc.code, resp["code"])
+		})
+	}
+}
+
 // ========================================
 // VALIDATION TESTS
 // ========================================
@@ -375,3 +567,91 @@ func TestProductHandler_CreateProduct_ValidationError(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+func TestProductHandler_IncludeDeleted_RequiresProductsWrite(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Le repository ne doit pas être appelé
+	mockRepo := mockrepo.NewMockProductRepository(ctrl)
+	handler := producthandler.NewProductHandler(mockRepo, mockrepo.NewMockTxManager(ctrl))
+
+	ctx := utils.WithUserPermissions(context.Background(), []string{userentity.PermOrdersWrite})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/products?include_deleted=true", nil).WithContext(ctx)
+	middl.ErrorHandler(handler.GetAllProducts).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	req = setupChiContext(httptest.NewRequest("GET", "/products/123?include_deleted=true", nil).WithContext(ctx), "123")
+	middl.ErrorHandler(handler.GetProductById).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestProductHandler_GetProductById_IncludeDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockrepo.NewMockProductRepository(ctrl)
+	handler := producthandler.NewProductHandler(mockRepo, mockrepo.NewMockTxManager(ctrl))
+
+	archived := createTestProduct("123")
+	deletedAt := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	archived.DeletedAt = &deletedAt
+	mockRepo.EXPECT().FindByIDIncludingDeleted(gomock.Any(), "123").Return(archived, nil)
+
+	ctx := utils.WithUserPermissions(context.Background(), []string{userentity.PermProductsWrite})
+	req := setupChiContext(httptest.NewRequest("GET", "/products/123?include_deleted=true", nil).WithContext(ctx), "123")
+	w := httptest.NewRecorder()
+	middl.ErrorHandler(handler.GetProductById).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.ProductResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "2025-03-01 09:00:00", resp.DeletedAt)
+}
+
+func TestProductHandler_PurgeProduct(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockrepo.NewMockProductRepository(ctrl)
+	mockTxMgr := mockrepo.NewMockTxManager(ctrl)
+	mockTx := mockrepo.NewMockTx(ctrl)
+	mockRepoWithTX := mockrepo.NewMockProductRepository(ctrl)
+
+	handler := producthandler.NewProductHandler(mockRepo, mockTxMgr)
+
+	archived := createTestProduct("123")
+	deletedAt := time.Now()
+	archived.DeletedAt = &deletedAt
+	mockTxMgr.EXPECT().BeginTx(gomock.Any()).Return(mockTx, nil).Times(2)
+	mockRepo.EXPECT().WithTX(mockTx).Return(mockRepoWithTX).Times(2)
+	mockRepoWithTX.EXPECT().FindByIDIncludingDeleted(gomock.Any(), "123").Return(archived, nil)
+	mockRepoWithTX.EXPECT().Purge(gomock.Any(), "123").Return(true, nil)
+	mockTx.EXPECT().Commit().Return(nil)
+
+	req := setupChiContext(httptest.NewRequest("DELETE", "/products/123/purge", nil), "123")
+	w := httptest.NewRecorder()
+	middl.ErrorHandler(handler.PurgeProduct).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]bool
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.True(t, resp["product_deleted"])
+
+	// Produit actif : il doit d'abord être archivé
+	mockRepoWithTX.EXPECT().FindByIDIncludingDeleted(gomock.Any(), "456").Return(createTestProduct("456"), nil)
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	req = setupChiContext(httptest.NewRequest("DELETE", "/products/456/purge", nil), "456")
+	w = httptest.NewRecorder()
+	middl.ErrorHandler(handler.PurgeProduct).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var errResp map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&errResp))
+	assert.Equal(t, "PRODUCT_NOT_ARCHIVED", errResp["code"])
+}
@@ -6,6 +6,7 @@ import (
 	"context" // ← AJOUTÉ
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/rs/zerolog" // ← AJOUTÉ
@@ -87,7 +88,7 @@ func (h *RefreshHandler) Refresh(w http.ResponseWriter, r *http.Request) error {
 		"access_token":  access,
 		"refresh_token": refresh,
 		"token_type":    "Bearer",
-		"expires_in":    "3600",
+		"expires_in":    strconv.Itoa(int(utils.AccessTokenTTL().Seconds())),
 	}
 
 	duration := time.Since(startTime)
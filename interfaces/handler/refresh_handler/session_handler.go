@@ -0,0 +1,180 @@
+// interfaces/handler/refresh_handler/session_handler.go
+package refreshhandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+
+	authentity "Goshop/domain/auth_entity"
+	"Goshop/interfaces/utils"
+)
+
+type SessionUseCase interface {
+	Logout(ctx context.Context, userID, refreshToken string) error
+	LogoutAll(ctx context.Context, userID string) (int, error)
+	ListSessions(ctx context.Context, userID string) ([]*authentity.RefreshSession, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+}
+
+// SessionHandler : sessions actives de l'utilisateur et logout.
+// Routes protégées par AuthMiddleware.
+type SessionHandler struct {
+	uc SessionUseCase
+}
+
+func NewSessionHandler(uc SessionUseCase) *SessionHandler {
+	return &SessionHandler{uc: uc}
+}
+
+// @Summary Logout
+// @Description Revoke the refresh session of the given refresh token (body or X-Refresh-Token header)
+// @Tags Authentication
+// @Accept json
+// @Param request body refreshRequest false "Refresh token"
+// @Success 204 "Session revoked"
+// @Failure 400 {object} utils.AppError "Missing refresh token"
+// @Failure 401 {object} utils.AppError "Invalid refresh token"
+// @Security ApiKeyAuth
+// @Router /auth/logout [post]
+func (h *SessionHandler) Logout(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	logger := zerolog.Ctx(ctx).With().Str("operation", "logout").Logger()
+
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return utils.ErrUnauthorized
+	}
+
+	var req refreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		logger.Warn().Err(err).Msg("Échec du décodage JSON")
+		return utils.ErrInvalidPayload
+	}
+	if req.RefreshToken == "" {
+		req.RefreshToken = r.Header.Get("X-Refresh-Token")
+	}
+	if req.RefreshToken == "" {
+		logger.Warn().Msg("Refresh token manquant")
+		return utils.ErrValidationFailed
+	}
+
+	if err := h.uc.Logout(ctx, userID, req.RefreshToken); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// @Summary Logout everywhere
+// @Description Revoke every refresh session of the current user
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} map[string]int "{'revoked_sessions': 3}"
+// @Failure 401 {object} utils.AppError "Unauthorized"
+// @Security ApiKeyAuth
+// @Router /auth/logout-all [post]
+func (h *SessionHandler) LogoutAll(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return utils.ErrUnauthorized
+	}
+
+	revoked, err := h.uc.LogoutAll(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	utils.WriteJSON(w, http.StatusOK, map[string]int{"revoked_sessions": revoked})
+	return nil
+}
+
+// sessionResponse : un appareil connecté. L'ID est celui de la famille de
+// rotation, inchangé par les refresh.
+type sessionResponse struct {
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+}
+
+func toSessionResponse(s *authentity.RefreshSession) sessionResponse {
+	id := s.FamilyID
+	if id == "" {
+		id = s.ID
+	}
+	return sessionResponse{
+		ID:         id,
+		CreatedAt:  s.CreatedAt,
+		LastUsedAt: s.LastUsedAt,
+		ExpiresAt:  s.ExpiresAt,
+		UserAgent:  s.UserAgent,
+		IPAddress:  s.IPAddress,
+	}
+}
+
+// @Summary List active sessions
+// @Description Active refresh sessions of the current user (one per logged-in device), most recently used first
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} map[string][]sessionResponse "{'sessions': [...]}"
+// @Failure 401 {object} utils.AppError "Unauthorized"
+// @Security ApiKeyAuth
+// @Router /auth/sessions [get]
+func (h *SessionHandler) ListSessions(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return utils.ErrUnauthorized
+	}
+
+	sessions, err := h.uc.ListSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]sessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		resp = append(resp, toSessionResponse(s))
+	}
+
+	utils.WriteJSON(w, http.StatusOK, map[string][]sessionResponse{"sessions": resp})
+	return nil
+}
+
+// @Summary Revoke a session
+// @Description Revoke one device session of the current user
+// @Tags Authentication
+// @Param id path string true "Session ID"
+// @Success 204 "Session revoked"
+// @Failure 401 {object} utils.AppError "Unauthorized"
+// @Failure 404 {object} utils.AppError "Session not found"
+// @Security ApiKeyAuth
+// @Router /auth/sessions/{id} [delete]
+func (h *SessionHandler) RevokeSession(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return utils.ErrUnauthorized
+	}
+
+	if err := h.uc.RevokeSession(ctx, userID, chi.URLParam(r, "id")); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
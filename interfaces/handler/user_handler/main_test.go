@@ -0,0 +1,21 @@
+package userhandler_test
+
+import (
+	"log"
+	"os"
+	"testing"
+
+	"Goshop/interfaces/utils"
+)
+
+// TestMain charge un trousseau JWT éphémère : le handler de login signe de
+// vrais tokens
+func TestMain(m *testing.M) {
+	keys, err := utils.NewEphemeralKeySet()
+	if err != nil {
+		log.Fatalf("❌ Génération des clés JWT de test: %v", err)
+	}
+	utils.InitJWTKeys(keys)
+
+	os.Exit(m.Run())
+}
@@ -6,11 +6,14 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	userdto "Goshop/application/dto/user_dto"
 	userusecase "Goshop/application/usecase/user_usecase"
 	"Goshop/config/setupLogging"
+	userentity "Goshop/domain/entity/user_entity"
+	authrepository "Goshop/domain/repository/auth_repository"
 	userrepository "Goshop/domain/repository/user_repository"
 	"Goshop/interfaces/utils"
 
@@ -26,11 +29,11 @@ type UserHandler struct {
 
 // NewUserHandler — maintenant reçoit un logger
 // NewUserHandler — maintenant reçoit un logger et le passe aux use cases
-func NewUserHandler(repo userrepository.UserRepository, logger *setupLogging.Logger) *UserHandler {
+func NewUserHandler(repo userrepository.UserRepository, sessions authrepository.RefreshSessionRepository, guard userusecase.LoginGuard, mfa userusecase.MFAVerifier, verifier userusecase.EmailVerifier, logger *setupLogging.Logger) *UserHandler {
 	handlerLogger := logger.WithComponent("user_handler")
 	return &UserHandler{
-		registerUc:   userusecase.NewRegisterUsecase(repo, handlerLogger),
-		loginUc:      userusecase.NewLoginUsecase(repo, handlerLogger),
+		registerUc:   userusecase.NewRegisterUsecase(repo, verifier, handlerLogger),
+		loginUc:      userusecase.NewLoginUsecase(repo, sessions, guard, mfa, handlerLogger),
 		getProfileUc: userusecase.NewGetProfileUsecase(repo),
 		//logger:       handlerLogger,
 	}
@@ -41,7 +44,7 @@ func NewUserHandler(repo userrepository.UserRepository, logger *setupLogging.Log
 // -----------------------
 
 // @Summary User Registration
-// @Description Register a new user account
+// @Description Register a new user account and the customer profile linked to it
 // @Tags Authentication
 // @Accept json
 // @Produce json
@@ -103,7 +106,8 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) error {
 		Str("user_email", req.Email).
 		Msg("✅ Validation réussie, tentative création utilisateur")
 
-	user, err := h.registerUc.Execute(ctx, req.Email, req.Password)
+	firstName, lastName := req.ProfileNames()
+	user, err := h.registerUc.ExecuteWithProfile(ctx, req.Email, req.Password, firstName, lastName)
 	if err != nil {
 		logger.Error().
 			Err(err).
@@ -130,14 +134,17 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) error {
 // -----------------------
 
 // @Summary User Login
-// @Description Authenticate user and return JWT token
+// @Description Authenticate user, open a refresh session and return an access / refresh token pair.
+// @Description When two-factor authentication is enabled, only a short-lived mfa_token is returned: exchange it on /login/mfa.
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param request body userdto.LoginRequest true "Login credentials"
-// @Success 200 {object} map[string]string "{'token': 'jwt_token'}"
+// @Success 200 {object} map[string]string "{'access_token': 'jwt', 'refresh_token': 'jwt', 'token_type': 'Bearer', 'expires_in': '900'} or {'mfa_required': true, 'mfa_token': 'jwt', 'expires_in': '300'}"
 // @Failure 400 {object} utils.AppError "Invalid request payload"
 // @Failure 401 {object} utils.AppError "Invalid credentials"
+// @Failure 423 {object} utils.AppError "Account temporarily locked (Retry-After header)"
+// @Failure 429 {object} utils.AppError "Too many failed attempts (Retry-After header)"
 // @Failure 500 {object} utils.AppError "Internal server error"
 // @Router /login [post]
 func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) error {
@@ -186,7 +193,7 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) error {
 
 	logger.Info().Str("user_email", req.Email).Msg("🔑 Authentification en cours")
 
-	token, err := h.loginUc.Execute(ctx, req.Email, req.Password)
+	res, err := h.loginUc.Execute(ctx, req.Email, req.Password)
 	if err != nil {
 		logger.Warn().
 			Err(err).
@@ -196,15 +203,76 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) error {
 		return err
 	}
 
+	if res.MFARequired() {
+		logger.Info().
+			Str("user_email", req.Email).
+			Msg("🔐 Second facteur requis")
+
+		utils.WriteJSON(w, http.StatusOK, map[string]any{
+			"mfa_required": true,
+			"mfa_token":    res.MFAToken,
+			"expires_in":   strconv.Itoa(int(utils.MFA_TOKEN_TTL.Seconds())),
+		})
+		return nil
+	}
+
 	logger.Info().
 		Str("user_email", req.Email).
-		Int("token_length", len(token)).
+		Int("token_length", len(res.AccessToken)).
 		Msg("✅ Connexion réussie, token généré")
 
+	writeTokens(w, res)
+	return nil
+}
+
+// @Summary Two-factor login
+// @Description Exchange the mfa_token returned by /login and a TOTP code (or a recovery code) for an access / refresh token pair
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body userdto.LoginMFARequest true "MFA token and code"
+// @Success 200 {object} map[string]string "{'access_token': 'jwt', 'refresh_token': 'jwt', 'token_type': 'Bearer', 'expires_in': '900'}"
+// @Failure 400 {object} utils.AppError "Invalid request payload"
+// @Failure 401 {object} utils.AppError "Invalid or expired mfa_token, or invalid code"
+// @Failure 423 {object} utils.AppError "Account temporarily locked (Retry-After header)"
+// @Failure 429 {object} utils.AppError "Too many failed attempts (Retry-After header)"
+// @Router /login/mfa [post]
+func (h *UserHandler) LoginMFA(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	logger := zerolog.Ctx(ctx)
+
+	var req userdto.LoginMFARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn().Err(err).Str("error_type", "invalid_json").Msg("❌ Échec décodage JSON login MFA")
+		return utils.ErrInvalidPayload
+	}
+	if err := req.Validate(); err != nil {
+		return utils.ErrValidationFailed
+	}
+
+	res, err := h.loginUc.ExecuteMFA(ctx, req.MFAToken, req.Code)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Str("error_type", "mfa_failed").
+			Msg("❌ Échec second facteur")
+		return err
+	}
+
+	writeTokens(w, res)
+	return nil
+}
+
+// writeTokens : réponse d'un login réussi ("token" est conservé pour les
+// clients existants)
+func writeTokens(w http.ResponseWriter, res *userusecase.LoginResult) {
 	utils.WriteJSON(w, http.StatusOK, map[string]string{
-		"token": token,
+		"token":         res.AccessToken,
+		"access_token":  res.AccessToken,
+		"refresh_token": res.RefreshToken,
+		"token_type":    "Bearer",
+		"expires_in":    strconv.Itoa(int(utils.AccessTokenTTL().Seconds())),
 	})
-	return nil
 }
 
 // -----------------------
@@ -255,8 +323,11 @@ func (h *UserHandler) Me(w http.ResponseWriter, r *http.Request) error {
 	}
 
 	response := userdto.MeResponse{
-		ID:    userID,
-		Email: maskEmail(user.Email),
+		ID:          userID,
+		Email:       maskEmail(user.Email),
+		Role:        userentity.PrimaryRole(user.Roles),
+		Roles:       user.Roles,
+		Permissions: user.Permissions,
 	}
 
 	logger.Info().
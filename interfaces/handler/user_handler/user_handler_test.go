@@ -2,6 +2,7 @@
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -25,7 +26,7 @@ func TestRegister_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mockrepo.NewMockUserRepository(ctrl)
-	handler := userhandler.NewUserHandler(mockRepo, setupLogging.GetTestLogger())
+	handler := userhandler.NewUserHandler(mockRepo, mockrepo.NewMockRefreshSessionRepository(ctrl), nil, nil, nil, setupLogging.GetTestLogger())
 
 	// GIVEN: payload
 	body := map[string]string{
@@ -71,7 +72,8 @@ func TestLoginHandler_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mockrepo.NewMockUserRepository(ctrl)
-	handler := userhandler.NewUserHandler(repo, setupLogging.GetTestLogger())
+	sessions := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	handler := userhandler.NewUserHandler(repo, sessions, nil, nil, nil, setupLogging.GetTestLogger())
 
 	// 1. PrÃ©paration du mot de passe hashÃ©
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("pwd123"), bcrypt.DefaultCost)
@@ -85,6 +87,8 @@ func TestLoginHandler_Success(t *testing.T) {
 			Password: string(hashedPassword), // Hash rÃ©el
 		}, nil)
 
+	sessions.EXPECT().Create(gomock.Any()).Return(nil)
+
 	// 3. RequÃªte HTTP
 	body := map[string]string{
 		"email":    "test@example.com",
@@ -109,6 +113,8 @@ func TestLoginHandler_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Contains(t, response, "token")
 	assert.NotEmpty(t, response["token"])
+	assert.NotEmpty(t, response["refresh_token"])
+	assert.Equal(t, response["token"], response["access_token"])
 }
 
 func TestLoginHandler_InvalidCredentials(t *testing.T) {
@@ -116,7 +122,8 @@ func TestLoginHandler_InvalidCredentials(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mockrepo.NewMockUserRepository(ctrl)
-	handler := userhandler.NewUserHandler(repo, setupLogging.GetTestLogger())
+	sessions := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	handler := userhandler.NewUserHandler(repo, sessions, nil, nil, nil, setupLogging.GetTestLogger())
 
 	// Mock : utilisateur non trouvÃ©
 	repo.EXPECT().
@@ -148,7 +155,8 @@ func TestMeHandler_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mockrepo.NewMockUserRepository(ctrl)
-	handler := userhandler.NewUserHandler(repo, setupLogging.GetTestLogger())
+	sessions := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	handler := userhandler.NewUserHandler(repo, sessions, nil, nil, nil, setupLogging.GetTestLogger())
 
 	// GIVEN: Un utilisateur existant
 	expectedUser := &userentity.UserEntity{
@@ -191,7 +199,8 @@ func TestMeHandler_Unauthorized(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mockrepo.NewMockUserRepository(ctrl)
-	handler := userhandler.NewUserHandler(repo, setupLogging.GetTestLogger())
+	sessions := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	handler := userhandler.NewUserHandler(repo, sessions, nil, nil, nil, setupLogging.GetTestLogger())
 
 	// GIVEN: RequÃªte SANS userID dans le contexte
 	req := httptest.NewRequest(http.MethodGet, "/auth/me", nil)
@@ -210,7 +219,8 @@ func TestMeHandler_UserNotFound(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mockrepo.NewMockUserRepository(ctrl)
-	handler := userhandler.NewUserHandler(repo, setupLogging.GetTestLogger())
+	sessions := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	handler := userhandler.NewUserHandler(repo, sessions, nil, nil, nil, setupLogging.GetTestLogger())
 
 	// GIVEN: UserID existe mais utilisateur pas en base
 	repo.EXPECT().
@@ -238,7 +248,8 @@ func TestMeHandler_InternalServerError(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mockrepo.NewMockUserRepository(ctrl)
-	handler := userhandler.NewUserHandler(repo, setupLogging.GetTestLogger())
+	sessions := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	handler := userhandler.NewUserHandler(repo, sessions, nil, nil, nil, setupLogging.GetTestLogger())
 
 	// GIVEN: Erreur interne du repository
 	repo.EXPECT().
@@ -266,3 +277,76 @@ func TestMeHandler_InternalServerError(t *testing.T) {
 	// Si ton GetProfileUsecase transforme en utils.ErrInternalServer :
 	assert.Equal(t, utils.ErrInternalServer, err)
 }
+
+// fakeMFA : second facteur actif pour tous les utilisateurs
+type fakeMFA struct{}
+
+func (fakeMFA) Enabled(ctx context.Context, userID string) (bool, error) { return true, nil }
+
+func (fakeMFA) Verify(ctx context.Context, userID, code string) error {
+	if code != "123456" {
+		return utils.ErrMFACodeInvalid
+	}
+	return nil
+}
+
+func TestLoginHandler_MFARequired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockrepo.NewMockUserRepository(ctrl)
+	sessions := mockrepo.NewMockRefreshSessionRepository(ctrl)
+	handler := userhandler.NewUserHandler(repo, sessions, nil, fakeMFA{}, nil, setupLogging.GetTestLogger())
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("pwd123"), bcrypt.MinCost)
+	user := &userentity.UserEntity{ID: "123", Email: "test@example.com", Password: string(hashedPassword)}
+	repo.EXPECT().FindUserByEmail("test@example.com").Return(user, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(`{"email":"test@example.com","password":"pwd123"}`))
+	w := httptest.NewRecorder()
+	assert.NoError(t, handler.Login(w, req))
+
+	// Pas de tokens avant le second facteur
+	var pending map[string]any
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&pending))
+	assert.Equal(t, true, pending["mfa_required"])
+	assert.Equal(t, "300", pending["expires_in"])
+	assert.NotContains(t, pending, "access_token")
+	mfaToken, _ := pending["mfa_token"].(string)
+	assert.NotEmpty(t, mfaToken)
+
+	// Mauvais code
+	repo.EXPECT().FindUserByID("123").Return(user, nil)
+	req = httptest.NewRequest(http.MethodPost, "/login/mfa", bytes.NewBufferString(`{"mfa_token":"`+mfaToken+`","code":"000000"}`))
+	err := handler.LoginMFA(httptest.NewRecorder(), req)
+	assert.ErrorIs(t, err, utils.ErrMFACodeInvalid)
+
+	// Bon code : paire de tokens
+	repo.EXPECT().FindUserByID("123").Return(user, nil)
+	sessions.EXPECT().Create(gomock.Any()).Return(nil)
+	req = httptest.NewRequest(http.MethodPost, "/login/mfa", bytes.NewBufferString(`{"mfa_token":"`+mfaToken+`","code":"123456"}`))
+	w = httptest.NewRecorder()
+	assert.NoError(t, handler.LoginMFA(w, req))
+
+	var tokens map[string]string
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&tokens))
+	assert.NotEmpty(t, tokens["access_token"])
+	assert.NotEmpty(t, tokens["refresh_token"])
+}
+
+func TestLoginMFAHandler_InvalidPayload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := userhandler.NewUserHandler(mockrepo.NewMockUserRepository(ctrl), mockrepo.NewMockRefreshSessionRepository(ctrl), nil, fakeMFA{}, nil, setupLogging.GetTestLogger())
+
+	for body, want := range map[string]error{
+		`not json`:                          utils.ErrInvalidPayload,
+		`{"mfa_token":"x"}`:                 utils.ErrValidationFailed,
+		`{"mfa_token":"x","code":"123456"}`: utils.ErrMFATokenInvalid,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/login/mfa", bytes.NewBufferString(body))
+		err := handler.LoginMFA(httptest.NewRecorder(), req)
+		assert.ErrorIs(t, err, want, body)
+	}
+}
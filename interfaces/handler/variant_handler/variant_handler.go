@@ -0,0 +1,145 @@
+// interfaces/handler/variant_handler/variant_handler.go
+package varianthandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	dto "Goshop/application/dto/product_dto"
+	"Goshop/interfaces/utils"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+)
+
+type VariantUseCase interface {
+	List(ctx context.Context, productID string) ([]dto.VariantResponse, error)
+	Create(ctx context.Context, productID string, req dto.VariantRequest) (*dto.VariantResponse, error)
+	Update(ctx context.Context, productID, variantID string, req dto.VariantRequest) (*dto.VariantResponse, error)
+	Delete(ctx context.Context, productID, variantID string) error
+}
+
+// VariantHandler : variantes d'un produit (lecture pour tout utilisateur
+// connecté, écriture par le back-office)
+type VariantHandler struct {
+	variants VariantUseCase
+}
+
+func NewVariantHandler(variants VariantUseCase) *VariantHandler {
+	return &VariantHandler{variants: variants}
+}
+
+// @Summary List product variants
+// @Description Variants of a product, default variant first
+// @Tags Products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {array} dto.VariantResponse
+// @Failure 404 {object} utils.AppError "Product not found"
+// @Security ApiKeyAuth
+// @Router /api/products/{id}/variants [get]
+func (h *VariantHandler) List(w http.ResponseWriter, r *http.Request) error {
+	variants, err := h.variants.List(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	utils.WriteJSON(w, http.StatusOK, variants)
+	return nil
+}
+
+// @Summary Create a product variant
+// @Description Add a variant with its own SKU, options, stock and optional price
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param request body dto.VariantRequest true "Variant"
+// @Success 201 {object} dto.VariantResponse
+// @Failure 400 {object} utils.AppError "Invalid payload"
+// @Failure 404 {object} utils.AppError "Product not found"
+// @Failure 409 {object} utils.AppError "SKU or options already used"
+// @Security ApiKeyAuth
+// @Router /api/products/{id}/variants [post]
+func (h *VariantHandler) Create(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	req, err := decodeVariantRequest(r)
+	if err != nil {
+		return err
+	}
+
+	variant, err := h.variants.Create(ctx, chi.URLParam(r, "id"), req)
+	if err != nil {
+		return err
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, variant)
+	return nil
+}
+
+// @Summary Update a product variant
+// @Description Replace SKU, options, price and stock of a variant
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param variantId path string true "Variant ID"
+// @Param request body dto.VariantRequest true "Variant"
+// @Success 200 {object} dto.VariantResponse
+// @Failure 400 {object} utils.AppError "Invalid payload"
+// @Failure 404 {object} utils.AppError "Product or variant not found"
+// @Failure 409 {object} utils.AppError "SKU or options already used"
+// @Security ApiKeyAuth
+// @Router /api/products/{id}/variants/{variantId} [put]
+func (h *VariantHandler) Update(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	req, err := decodeVariantRequest(r)
+	if err != nil {
+		return err
+	}
+
+	variant, err := h.variants.Update(ctx, chi.URLParam(r, "id"), chi.URLParam(r, "variantId"), req)
+	if err != nil {
+		return err
+	}
+
+	utils.WriteJSON(w, http.StatusOK, variant)
+	return nil
+}
+
+// @Summary Delete a product variant
+// @Description Delete a variant that no order references; the default variant cannot be deleted
+// @Tags Products
+// @Param id path string true "Product ID"
+// @Param variantId path string true "Variant ID"
+// @Success 204 "Variant deleted"
+// @Failure 404 {object} utils.AppError "Product or variant not found"
+// @Failure 409 {object} utils.AppError "Default variant or variant referenced by orders"
+// @Security ApiKeyAuth
+// @Router /api/products/{id}/variants/{variantId} [delete]
+func (h *VariantHandler) Delete(w http.ResponseWriter, r *http.Request) error {
+	if err := h.variants.Delete(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "variantId")); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func decodeVariantRequest(r *http.Request) (dto.VariantRequest, error) {
+	ctx := r.Context()
+
+	var req dto.VariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Invalid variant payload")
+		return req, utils.ErrInvalidPayload
+	}
+	if err := req.Validate(); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Variant validation failed")
+		return req, utils.ErrValidationFailed
+	}
+	return req, nil
+}
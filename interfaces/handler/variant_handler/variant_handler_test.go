@@ -0,0 +1,128 @@
+package varianthandler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dto "Goshop/application/dto/product_dto"
+	varianthandler "Goshop/interfaces/handler/variant_handler"
+	"Goshop/interfaces/middl"
+	"Goshop/interfaces/utils"
+)
+
+type fakeVariants struct {
+	created []dto.VariantRequest
+}
+
+func (f *fakeVariants) List(_ context.Context, productID string) ([]dto.VariantResponse, error) {
+	if productID != "p-1" {
+		return nil, utils.ErrProductNotFound
+	}
+	return []dto.VariantResponse{
+		{ID: "v-1", SKU: "SKU-p-1", Options: map[string]string{}, PriceCents: 2000, Stock: 3, IsDefault: true},
+		{ID: "v-2", SKU: "TSHIRT-L", Options: map[string]string{"size": "L"}, PriceCents: 2000, Stock: 2},
+	}, nil
+}
+
+func (f *fakeVariants) Create(_ context.Context, productID string, req dto.VariantRequest) (*dto.VariantResponse, error) {
+	f.created = append(f.created, req)
+	if req.SKU == "TSHIRT-L" {
+		return nil, utils.ErrVariantSKUTaken
+	}
+	return &dto.VariantResponse{ID: "v-3", SKU: req.SKU, Options: req.Options, Stock: req.Stock}, nil
+}
+
+func (f *fakeVariants) Update(_ context.Context, productID, variantID string, req dto.VariantRequest) (*dto.VariantResponse, error) {
+	if variantID != "v-2" {
+		return nil, utils.ErrVariantNotFound
+	}
+	return &dto.VariantResponse{ID: variantID, SKU: req.SKU, Options: req.Options, Stock: req.Stock}, nil
+}
+
+func (f *fakeVariants) Delete(_ context.Context, productID, variantID string) error {
+	if variantID == "v-1" {
+		return utils.ErrVariantIsDefault
+	}
+	return nil
+}
+
+func serve(h middl.HandlerWriteError, req *http.Request, productID, variantID string) *httptest.ResponseRecorder {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", productID)
+	if variantID != "" {
+		rctx.URLParams.Add("variantId", variantID)
+	}
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	middl.ErrorHandler(h).ServeHTTP(w, req)
+	return w
+}
+
+func TestList(t *testing.T) {
+	h := varianthandler.NewVariantHandler(&fakeVariants{})
+
+	w := serve(h.List, httptest.NewRequest(http.MethodGet, "/", nil), "p-1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var variants []dto.VariantResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&variants))
+	require.Len(t, variants, 2)
+	assert.True(t, variants[0].IsDefault)
+
+	w = serve(h.List, httptest.NewRequest(http.MethodGet, "/", nil), "p-9", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCreate(t *testing.T) {
+	variants := &fakeVariants{}
+	h := varianthandler.NewVariantHandler(variants)
+
+	body := `{"sku":"TSHIRT-M","options":{"size":"M","colour":"bleu"},"price_cents":2500,"stock":4}`
+	w := serve(h.Create, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)), "p-1", "")
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NotNil(t, variants.created[0].PriceCents)
+	assert.Equal(t, int64(2500), *variants.created[0].PriceCents)
+
+	w = serve(h.Create, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"sku":"TSHIRT-L","options":{"size":"L"}}`)), "p-1", "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// Rejetés avant le usecase : SKU invalide, option mal nommée, stock négatif
+	for _, body := range []string{
+		`{"sku":"T SHIRT","options":{"size":"S"}}`,
+		`{"sku":"TSHIRT-S","options":{"Size":"S"}}`,
+		`{"sku":"TSHIRT-S","options":{"size":"S"},"stock":-1}`,
+	} {
+		w = serve(h.Create, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)), "p-1", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	assert.Len(t, variants.created, 2)
+}
+
+func TestUpdate(t *testing.T) {
+	h := varianthandler.NewVariantHandler(&fakeVariants{})
+
+	body := `{"sku":"TSHIRT-L","options":{"size":"L"},"stock":10}`
+	w := serve(h.Update, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body)), "p-1", "v-2")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = serve(h.Update, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body)), "p-1", "v-9")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDelete(t *testing.T) {
+	h := varianthandler.NewVariantHandler(&fakeVariants{})
+
+	w := serve(h.Delete, httptest.NewRequest(http.MethodDelete, "/", nil), "p-1", "v-2")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = serve(h.Delete, httptest.NewRequest(http.MethodDelete, "/", nil), "p-1", "v-1")
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
@@ -0,0 +1,32 @@
+// interfaces/middl/client_info.go
+package middl
+
+import (
+	"net"
+	"net/http"
+
+	"Goshop/interfaces/utils"
+)
+
+// Un User-Agent plus long est tronqué avant d'être stocké avec la session
+const maxUserAgentLength = 512
+
+// ClientInfo injecte le User-Agent et l'IP du client dans le contexte
+// (utils.ClientInfoFromContext), enregistrés avec les sessions de refresh.
+// L'IP est celle de la connexion : les en-têtes X-Forwarded-For ne sont pas
+// pris en compte tant qu'aucun proxy de confiance n'est configuré.
+func ClientInfo(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+
+		userAgent := r.UserAgent()
+		if len(userAgent) > maxUserAgentLength {
+			userAgent = userAgent[:maxUserAgentLength]
+		}
+
+		next.ServeHTTP(w, r.WithContext(utils.WithClientInfo(r.Context(), userAgent, ip)))
+	})
+}
@@ -0,0 +1,45 @@
+package middl_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"Goshop/interfaces/middl"
+	"Goshop/interfaces/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientInfo(t *testing.T) {
+	var userAgent, ip string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent, ip = utils.ClientInfoFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	req.RemoteAddr = "203.0.113.7:52100"
+	req.Header.Set("User-Agent", "GoShop-iOS/2.1")
+	// Non fiable sans proxy de confiance : ignoré
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+	middl.ClientInfo(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "GoShop-iOS/2.1", userAgent)
+	assert.Equal(t, "203.0.113.7", ip)
+}
+
+func TestClientInfo_TruncatesUserAgent(t *testing.T) {
+	var userAgent string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent, _ = utils.ClientInfoFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	req.Header.Set("User-Agent", strings.Repeat("a", 2000))
+
+	middl.ClientInfo(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Len(t, userAgent, 512)
+}
@@ -20,12 +20,12 @@ func CORS(origin string) func(http.Handler) http.Handler {
 
 			// Headers autorisés
 			w.Header().Set("Access-Control-Allow-Headers",
-				"Authorization, Content-Type, Accept, X-Requested-With",
+				"Authorization, Content-Type, Accept, X-Requested-With, Idempotency-Key",
 			)
 
 			// Expose certains headers au client
 			w.Header().Set("Access-Control-Expose-Headers",
-				"Authorization, Content-Type",
+				"Authorization, Content-Type, Idempotent-Replayed, Retry-After, X-Total-Count",
 			)
 
 			// Si c’est une requête preflight → on répond 200
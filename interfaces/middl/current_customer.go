@@ -0,0 +1,50 @@
+// interfaces/middl/current_customer.go
+package middl
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"Goshop/domain/repository"
+	"Goshop/interfaces/utils"
+
+	"github.com/rs/zerolog"
+)
+
+// CurrentCustomer résout le profil client de l'utilisateur connecté (sub du
+// JWT) et l'injecte dans le contexte via utils.WithCustomerID. Les usecases
+// s'en servent pour limiter un utilisateur sans permission ":any" à ses
+// propres données. Un compte sans profil client passe, sans client associé,
+// de même qu'une requête authentifiée par clé d'API.
+//
+// Le middleware doit être monté après AuthMiddleware.
+func CurrentCustomer(customers repository.CustomerRepositoryInterface) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			userID, ok := utils.GetUserID(ctx)
+			if _, isAPIKey := utils.APIKeyIDFromContext(ctx); !ok || isAPIKey {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			customer, err := customers.FindByUserID(ctx, userID)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					next.ServeHTTP(w, r)
+					return
+				}
+				zerolog.Ctx(ctx).Error().
+					Err(err).
+					Str("component", "current_customer").
+					Msg("Failed to resolve current customer")
+				utils.WriteAppError(w, utils.ErrInternalServer)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(utils.WithCustomerID(ctx, customer.ID)))
+		})
+	}
+}
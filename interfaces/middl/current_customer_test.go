@@ -0,0 +1,116 @@
+package middl_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Goshop/domain/entity"
+	"Goshop/interfaces/middl"
+	"Goshop/interfaces/utils"
+	mockrepo "Goshop/mocks/repository"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// serveCurrentCustomer exécute le middleware et retourne le code HTTP et le
+// client injecté dans le contexte ("" si aucun)
+func serveCurrentCustomer(t *testing.T, repo *mockrepo.MockCustomerRepositoryInterface, ctx context.Context) (int, string) {
+	t.Helper()
+
+	var injected string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		injected, _ = utils.CustomerIDFromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/orders", nil).WithContext(ctx)
+	middl.CurrentCustomer(repo)(next).ServeHTTP(w, req)
+	return w.Code, injected
+}
+
+func TestCurrentCustomer_InjectsLinkedCustomer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+
+	repo.EXPECT().FindByUserID(gomock.Any(), "user-1").Return(&entity.Customer{ID: "cust-1"}, nil)
+
+	code, customerID := serveCurrentCustomer(t, repo, utils.WithUserID(context.Background(), "user-1"))
+
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Equal(t, "cust-1", customerID)
+}
+
+func TestCurrentCustomer_NoLinkedCustomer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+
+	repo.EXPECT().FindByUserID(gomock.Any(), "user-1").Return(nil, sql.ErrNoRows)
+
+	code, customerID := serveCurrentCustomer(t, repo, utils.WithUserID(context.Background(), "user-1"))
+
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Empty(t, customerID)
+}
+
+func TestCurrentCustomer_Anonymous(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+
+	// Aucun appel au repository sans utilisateur
+	code, customerID := serveCurrentCustomer(t, repo, context.Background())
+
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Empty(t, customerID)
+}
+
+func TestCurrentCustomer_APIKeyPrincipal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+
+	// Une clé d'API n'a pas de profil client : pas d'appel au repository
+	ctx := utils.WithUserID(context.Background(), utils.APIKeyPrincipal("key-1"))
+	code, customerID := serveCurrentCustomer(t, repo, utils.WithAPIKeyID(ctx, "key-1"))
+
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Empty(t, customerID)
+}
+
+func TestCurrentCustomer_RepositoryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockCustomerRepositoryInterface(ctrl)
+
+	repo.EXPECT().FindByUserID(gomock.Any(), "user-1").Return(nil, errors.New("connection refused"))
+
+	code, _ := serveCurrentCustomer(t, repo, utils.WithUserID(context.Background(), "user-1"))
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+}
+
+func TestCanAccessCustomer(t *testing.T) {
+	customerCtx := utils.WithCustomerID(authenticated([]string{"customer"}, []string{"orders:write"}), "cust-1")
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		customerID string
+		expected   bool
+	}{
+		{"sans utilisateur (appel interne)", context.Background(), "cust-2", true},
+		{"son propre client", customerCtx, "cust-1", true},
+		{"autre client", customerCtx, "cust-2", false},
+		{"sans profil client", authenticated([]string{"customer"}, nil), "cust-1", false},
+		{"permission :any", authenticated([]string{"manager"}, []string{"orders:read:any"}), "cust-2", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, utils.CanAccessCustomer(tt.ctx, tt.customerID, "orders:read:any"))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package middl
 
 import (
 	"Goshop/interfaces/utils"
+	"errors"
 	"log"
 	"net/http"
 )
@@ -50,6 +51,14 @@ func ErrorHandler(next HandlerWriteError) http.HandlerFunc {
 			return
 		}
 
+		// AppError avec délai de nouvel essai (blocage du login, ...)
+		var retryErr *utils.RetryAfterError
+		if errors.As(err, &retryErr) {
+			w.Header().Set("Retry-After", retryErr.RetryAfterSeconds())
+			utils.WriteAppError(w, retryErr.Err)
+			return
+		}
+
 		// ------------------------
 		// 4. Erreur inconnue → 500 générique
 		// ------------------------
@@ -0,0 +1,30 @@
+package middl_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"Goshop/interfaces/middl"
+	"Goshop/interfaces/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorHandler_RetryAfter(t *testing.T) {
+	handler := middl.ErrorHandler(func(w http.ResponseWriter, r *http.Request) error {
+		return utils.WithRetryAfter(utils.ErrAccountLocked, 90*time.Second+time.Millisecond)
+	})
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/login", nil))
+
+	assert.Equal(t, http.StatusLocked, rec.Code)
+	assert.Equal(t, "91", rec.Header().Get("Retry-After"))
+
+	var body map[string]any
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.Equal(t, "ACCOUNT_LOCKED", body["code"])
+}
@@ -0,0 +1,401 @@
+// interfaces/middl/idempotency.go
+package middl
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"Goshop/application/metrics"
+	"Goshop/domain/entity"
+	"Goshop/domain/repository"
+	"Goshop/interfaces/utils"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	IDEMPOTENCY_HEADER        = "Idempotency-Key"
+	IDEMPOTENCY_REPLAY_HEADER = "Idempotent-Replayed"
+
+	// Durée de conservation d'une réponse rejouable
+	IDEMPOTENCY_TTL = 24 * time.Hour
+
+	// Une clé IN_PROGRESS dont locked_at n'a pas bougé depuis
+	// IDEMPOTENCY_LOCK_TIMEOUT est reprise par la requête suivante. Tant que le
+	// handler s'exécute, locked_at est rafraîchi toutes les
+	// IDEMPOTENCY_HEARTBEAT_INTERVAL : seule une requête dont le process s'est
+	// arrêté (crash, kill) perd sa clé, un handler lent n'est jamais exécuté deux
+	// fois, même au-delà du WriteTimeout du serveur (30 s, cmd/api) qui ne
+	// l'interrompt pas. Le timeout doit rester de plusieurs intervalles pour
+	// qu'un heartbeat retardé (Postgres lent) ne suffise pas à libérer la clé.
+	IDEMPOTENCY_LOCK_TIMEOUT       = 1 * time.Minute
+	IDEMPOTENCY_HEARTBEAT_INTERVAL = IDEMPOTENCY_LOCK_TIMEOUT / 4
+
+	IDEMPOTENCY_MAX_KEY_LENGTH = 255
+	IDEMPOTENCY_MAX_BODY_BYTES = 1 << 20
+
+	// Les accès Redis ne doivent jamais ralentir la requête : au-delà, on passe par Postgres
+	idempotencyRedisTimeout = 100 * time.Millisecond
+)
+
+// idempotencyReplayedHeaders : headers posés par les handlers et rejoués avec
+// la réponse mémorisée. Les headers propres à chaque requête (X-Request-ID,
+// CORS, sécurité, rate limit) sont reposés par les autres middlewares ; les
+// cookies ne sont jamais stockés.
+var idempotencyReplayedHeaders = []string{
+	"Location",
+	utils.TOTAL_COUNT_HEADER,
+	"Link",
+	"ETag",
+	"Last-Modified",
+	"Cache-Control",
+	"Content-Disposition",
+	"Content-Language",
+}
+
+// IdempotencyConfig : reprise des clés abandonnées (voir IDEMPOTENCY_LOCK_TIMEOUT)
+type IdempotencyConfig struct {
+	LockTimeout       time.Duration
+	HeartbeatInterval time.Duration
+}
+
+func DefaultIdempotencyConfig() IdempotencyConfig {
+	return IdempotencyConfig{
+		LockTimeout:       IDEMPOTENCY_LOCK_TIMEOUT,
+		HeartbeatInterval: IDEMPOTENCY_HEARTBEAT_INTERVAL,
+	}
+}
+
+// idempotencyRecorder transmet la réponse au client tout en la copiant
+// pour pouvoir la rejouer.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rw *idempotencyRecorder) WriteHeader(code int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.statusCode = code
+	rw.wroteHeader = true
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *idempotencyRecorder) Write(b []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
+
+// Idempotency rend rejouables les requêtes mutantes (POST, PUT, PATCH, DELETE)
+// envoyées avec un header Idempotency-Key :
+//   - première requête : exécutée, réponse mémorisée (sauf 5xx, la clé est alors libérée)
+//   - même clé, même requête : réponse rejouée, avec le header Idempotent-Replayed
+//   - même clé, requête différente : 409 IDEMPOTENCY_KEY_REUSED
+//   - même clé pendant que la première s'exécute : 409 + Retry-After
+//
+// Les clés sont propres à chaque utilisateur : le middleware doit être monté
+// après AuthMiddleware. Les requêtes sans header passent sans contrôle.
+func Idempotency(store repository.IdempotencyRepository, config ...IdempotencyConfig) func(http.Handler) http.Handler {
+	cfg := DefaultIdempotencyConfig()
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IDEMPOTENCY_HEADER)
+			if key == "" || !isMutatingMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			logger := zerolog.Ctx(ctx).With().
+				Str("component", "idempotency").
+				Str("idempotency_key", key).
+				Logger()
+
+			if !isValidIdempotencyKey(key) {
+				utils.WriteAppError(w, utils.ErrIdempotencyKeyInvalid)
+				return
+			}
+
+			// 1. Lire le corps pour l'empreinte, puis le remettre en place
+			body, err := io.ReadAll(io.LimitReader(r.Body, IDEMPOTENCY_MAX_BODY_BYTES+1))
+			if err != nil {
+				logger.Warn().Err(err).Msg("Failed to read request body")
+				utils.WriteAppError(w, utils.ErrInvalidPayload)
+				return
+			}
+			if len(body) > IDEMPOTENCY_MAX_BODY_BYTES {
+				utils.WriteAppError(w, utils.ErrIdempotencyPayloadTooBig)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			scope, ok := utils.GetUserID(ctx)
+			if !ok {
+				scope = "anonymous"
+			}
+
+			record := &entity.IdempotencyRecord{
+				Key:         key,
+				Scope:       scope,
+				Method:      r.Method,
+				Path:        r.URL.RequestURI(),
+				Fingerprint: requestFingerprint(r.Method, r.URL.RequestURI(), body),
+				ExpiresAt:   time.Now().Add(IDEMPOTENCY_TTL),
+			}
+
+			// 2. Réponse déjà en cache Redis ?
+			if cached, ok := getCachedIdempotency(ctx, scope, key); ok {
+				replayOrReject(w, cached, record, logger)
+				return
+			}
+
+			// 3. Réserver la clé (atomique côté Postgres)
+			existing, created, err := store.Reserve(ctx, record, cfg.LockTimeout)
+			if err != nil {
+				logger.Error().Err(err).Stack().Msg("Failed to reserve idempotency key")
+				utils.WriteAppError(w, utils.ErrIdempotencyUnavailable)
+				return
+			}
+			if !created {
+				if existing.IsCompleted() {
+					setCachedIdempotency(ctx, existing, logger)
+				}
+				replayOrReject(w, existing, record, logger)
+				return
+			}
+
+			// 4. Première exécution, clé rafraîchie jusqu'à la fin du handler
+			stopHeartbeat := startIdempotencyHeartbeat(ctx, store, scope, key, cfg.HeartbeatInterval, logger)
+			rw := &idempotencyRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+			completed := false
+			defer func() {
+				stopHeartbeat()
+				// Panic ou réponse 5xx : la clé est libérée pour permettre un nouvel essai
+				if !completed {
+					if err := store.Release(context.WithoutCancel(ctx), scope, key); err != nil {
+						logger.Error().Err(err).Msg("Failed to release idempotency key")
+					}
+				}
+			}()
+
+			next.ServeHTTP(rw, r)
+			stopHeartbeat()
+
+			if rw.statusCode >= http.StatusInternalServerError {
+				logger.Warn().
+					Int("status", rw.statusCode).
+					Msg("Server error, idempotency key released")
+				return
+			}
+
+			record.ResponseStatus = rw.statusCode
+			record.ResponseContentType = rw.Header().Get("Content-Type")
+			record.ResponseHeaders = replayableHeaders(rw.Header())
+			record.ResponseBody = rw.body.Bytes()
+
+			if err := store.Complete(context.WithoutCancel(ctx), record); err != nil {
+				// La réponse est déjà partie : on libère la clé plutôt que de la bloquer
+				logger.Error().Err(err).Stack().Msg("Failed to store idempotent response")
+				return
+			}
+			completed = true
+
+			setCachedIdempotency(ctx, record, logger)
+			metrics.IdempotentRequestsTotal.WithLabelValues("executed").Inc()
+		})
+	}
+}
+
+// startIdempotencyHeartbeat rafraîchit locked_at toutes les `interval` tant
+// que la requête s'exécute. La fonction renvoyée arrête le heartbeat et
+// attend sa fin ; elle peut être appelée plusieurs fois.
+func startIdempotencyHeartbeat(ctx context.Context, store repository.IdempotencyRepository, scope, key string, interval time.Duration, logger zerolog.Logger) func() {
+	if interval <= 0 {
+		return func() {}
+	}
+
+	// Le client peut se déconnecter pendant que le handler continue
+	ctx = context.WithoutCancel(ctx)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				hctx, cancel := context.WithTimeout(ctx, interval)
+				err := store.Heartbeat(hctx, scope, key)
+				cancel()
+				if err != nil {
+					logger.Warn().Err(err).Msg("Failed to refresh idempotency key")
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			<-done
+		})
+	}
+}
+
+// replayOrReject répond à une requête dont la clé est déjà connue
+func replayOrReject(w http.ResponseWriter, existing, incoming *entity.IdempotencyRecord, logger zerolog.Logger) {
+	if existing.Fingerprint != incoming.Fingerprint {
+		logger.Warn().
+			Str("method", incoming.Method).
+			Str("path", incoming.Path).
+			Msg("Idempotency key reused with a different request")
+		metrics.IdempotentRequestsTotal.WithLabelValues("mismatch").Inc()
+		utils.WriteAppError(w, utils.ErrIdempotencyKeyReused)
+		return
+	}
+
+	if !existing.IsCompleted() {
+		logger.Info().Msg("Request with same idempotency key still in progress")
+		metrics.IdempotentRequestsTotal.WithLabelValues("in_progress").Inc()
+		w.Header().Set("Retry-After", "1")
+		utils.WriteAppError(w, utils.ErrIdempotencyInProgress)
+		return
+	}
+
+	logger.Info().
+		Int("status", existing.ResponseStatus).
+		Msg("Replaying stored idempotent response")
+	metrics.IdempotentRequestsTotal.WithLabelValues("replayed").Inc()
+
+	for name, values := range existing.ResponseHeaders {
+		w.Header()[http.CanonicalHeaderKey(name)] = values
+	}
+	if existing.ResponseContentType != "" {
+		w.Header().Set("Content-Type", existing.ResponseContentType)
+	}
+	w.Header().Set(IDEMPOTENCY_REPLAY_HEADER, "true")
+	w.WriteHeader(existing.ResponseStatus)
+	w.Write(existing.ResponseBody)
+}
+
+// replayableHeaders copie les headers de idempotencyReplayedHeaders présents
+// dans la réponse ; nil s'il n'y en a aucun
+func replayableHeaders(header http.Header) map[string][]string {
+	var kept map[string][]string
+	for _, name := range idempotencyReplayedHeaders {
+		values := header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		if kept == nil {
+			kept = make(map[string][]string)
+		}
+		kept[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+	}
+	return kept
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isValidIdempotencyKey accepte 1 à 255 caractères ASCII imprimables
+func isValidIdempotencyKey(key string) bool {
+	if len(key) == 0 || len(key) > IDEMPOTENCY_MAX_KEY_LENGTH {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func requestFingerprint(method, uri string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{'\n'})
+	h.Write([]byte(uri))
+	h.Write([]byte{'\n'})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// -------------------------
+// Cache Redis (optionnel)
+// -------------------------
+
+func idempotencyCacheKey(scope, key string) string {
+	return "idem:" + scope + ":" + key
+}
+
+// getCachedIdempotency ne renvoie que des réponses terminées. Redis absent ou
+// en erreur → cache miss, Postgres reste la source de vérité.
+func getCachedIdempotency(ctx context.Context, scope, key string) (*entity.IdempotencyRecord, bool) {
+	if utils.Rdb == nil {
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, idempotencyRedisTimeout)
+	defer cancel()
+
+	raw, err := utils.Rdb.Get(ctx, idempotencyCacheKey(scope, key)).Bytes()
+	if err != nil {
+		return nil, false
+	}
+
+	record := &entity.IdempotencyRecord{}
+	if err := json.Unmarshal(raw, record); err != nil || !record.IsCompleted() {
+		return nil, false
+	}
+	return record, true
+}
+
+func setCachedIdempotency(ctx context.Context, record *entity.IdempotencyRecord, logger zerolog.Logger) {
+	if utils.Rdb == nil {
+		return
+	}
+
+	ttl := time.Until(record.ExpiresAt)
+	if ttl <= 0 {
+		return
+	}
+
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), idempotencyRedisTimeout)
+	defer cancel()
+
+	if err := utils.Rdb.Set(ctx, idempotencyCacheKey(record.Scope, record.Key), raw, ttl).Err(); err != nil {
+		logger.Debug().Err(err).Msg("Redis unavailable, idempotent response not cached")
+	}
+}
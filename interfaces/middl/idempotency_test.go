@@ -0,0 +1,333 @@
+package middl_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"Goshop/domain/entity"
+	"Goshop/interfaces/middl"
+	"Goshop/interfaces/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryIdempotencyStore reproduit la sémantique de la table idempotency_keys
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*entity.IdempotencyRecord
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: map[string]*entity.IdempotencyRecord{}}
+}
+
+func (s *memoryIdempotencyStore) Reserve(ctx context.Context, record *entity.IdempotencyRecord, lockTimeout time.Duration) (*entity.IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := record.Scope + "|" + record.Key
+	if existing, ok := s.records[id]; ok {
+		abandoned := !existing.IsCompleted() && time.Since(existing.LockedAt) > lockTimeout
+		if !abandoned {
+			snapshot := *existing
+			return &snapshot, false, nil
+		}
+	}
+
+	stored := *record
+	stored.Status = entity.IdempotencyStatusInProgress
+	stored.LockedAt = time.Now()
+	s.records[id] = &stored
+	return nil, true, nil
+}
+
+func (s *memoryIdempotencyStore) Heartbeat(ctx context.Context, scope, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.records[scope+"|"+key]
+	if !ok || stored.IsCompleted() {
+		return sql.ErrNoRows
+	}
+	stored.LockedAt = time.Now()
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Complete(ctx context.Context, record *entity.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.records[record.Scope+"|"+record.Key]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	stored.Status = entity.IdempotencyStatusCompleted
+	stored.ResponseStatus = record.ResponseStatus
+	stored.ResponseContentType = record.ResponseContentType
+	stored.ResponseHeaders = record.ResponseHeaders
+	stored.ResponseBody = append([]byte(nil), record.ResponseBody...)
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(ctx context.Context, scope, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := scope + "|" + key
+	if stored, ok := s.records[id]; ok && stored.Status == entity.IdempotencyStatusInProgress {
+		delete(s.records, id)
+	}
+	return nil
+}
+
+func newIdempotentRequest(key, userID, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/orders", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(middl.IDEMPOTENCY_HEADER, key)
+	}
+	return req.WithContext(utils.WithUserID(req.Context(), userID))
+}
+
+func decodeErrorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var resp map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	return resp["code"].(string)
+}
+
+func TestIdempotency_ReplaysStoredResponse(t *testing.T) {
+	var calls int32
+	handler := middl.Idempotency(newMemoryIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		utils.WriteJSON(w, http.StatusCreated, map[string]interface{}{"order": n})
+	}))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, newIdempotentRequest("key-1", "user-1", `{"customer_id":"c1"}`))
+
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, newIdempotentRequest("key-1", "user-1", `{"customer_id":"c1"}`))
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "le handler ne doit être exécuté qu'une fois")
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+	assert.Equal(t, "true", second.Header().Get(middl.IDEMPOTENCY_REPLAY_HEADER))
+	assert.Empty(t, first.Header().Get(middl.IDEMPOTENCY_REPLAY_HEADER))
+}
+
+func TestIdempotency_ReplaysResponseHeaders(t *testing.T) {
+	handler := middl.Idempotency(newMemoryIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "/api/orders/order-1")
+		w.Header().Set(utils.TOTAL_COUNT_HEADER, "3")
+		w.Header().Add("Link", `</api/orders?page=2>; rel="next"`)
+		w.Header().Add("Link", `</api/orders?page=3>; rel="last"`)
+		w.Header().Set("Set-Cookie", "session=secret")
+		utils.WriteJSON(w, http.StatusCreated, map[string]string{"id": "order-1"})
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("key-1", "user-1", `{}`))
+
+	replay := httptest.NewRecorder()
+	handler.ServeHTTP(replay, newIdempotentRequest("key-1", "user-1", `{}`))
+
+	assert.Equal(t, "true", replay.Header().Get(middl.IDEMPOTENCY_REPLAY_HEADER))
+	assert.Equal(t, "/api/orders/order-1", replay.Header().Get("Location"))
+	assert.Equal(t, "3", replay.Header().Get(utils.TOTAL_COUNT_HEADER))
+	assert.Len(t, replay.Header().Values("Link"), 2)
+	// Seuls les headers de la liste sont mémorisés
+	assert.Empty(t, replay.Header().Get("Set-Cookie"))
+}
+
+func TestIdempotency_KeyReusedWithDifferentBody(t *testing.T) {
+	handler := middl.Idempotency(newMemoryIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		utils.WriteJSON(w, http.StatusCreated, map[string]string{"ok": "true"})
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("key-1", "user-1", `{"quantity":1}`))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newIdempotentRequest("key-1", "user-1", `{"quantity":2}`))
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "IDEMPOTENCY_KEY_REUSED", decodeErrorCode(t, w))
+}
+
+func TestIdempotency_KeysAreScopedPerUser(t *testing.T) {
+	var calls int32
+	handler := middl.Idempotency(newMemoryIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("key-1", "user-1", `{}`))
+	handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("key-1", "user-2", `{}`))
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestIdempotency_ConcurrentRequestInProgress(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls int32
+
+	handler := middl.Idempotency(newMemoryIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		close(started)
+		<-release
+		utils.WriteJSON(w, http.StatusCreated, map[string]string{"id": "order-1"})
+	}))
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(first, newIdempotentRequest("key-1", "user-1", `{}`))
+		close(done)
+	}()
+	<-started
+
+	// Même clé pendant que la première requête s'exécute
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, newIdempotentRequest("key-1", "user-1", `{}`))
+
+	assert.Equal(t, http.StatusConflict, second.Code)
+	assert.Equal(t, "1", second.Header().Get("Retry-After"))
+	assert.Equal(t, "IDEMPOTENCY_REQUEST_IN_PROGRESS", decodeErrorCode(t, second))
+
+	close(release)
+	<-done
+	assert.Equal(t, http.StatusCreated, first.Code)
+
+	// Une fois terminée, la réponse est rejouée
+	third := httptest.NewRecorder()
+	handler.ServeHTTP(third, newIdempotentRequest("key-1", "user-1", `{}`))
+	assert.Equal(t, http.StatusCreated, third.Code)
+	assert.Equal(t, first.Body.String(), third.Body.String())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestIdempotency_SlowHandlerKeepsKey(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls int32
+
+	// Handler bien plus long que le timeout : le heartbeat garde la clé
+	config := middl.IdempotencyConfig{LockTimeout: 40 * time.Millisecond, HeartbeatInterval: 10 * time.Millisecond}
+	handler := middl.Idempotency(newMemoryIdempotencyStore(), config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+			<-release
+		}
+		utils.WriteJSON(w, http.StatusCreated, map[string]string{"id": "order-1"})
+	}))
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(first, newIdempotentRequest("key-1", "user-1", `{}`))
+		close(done)
+	}()
+	<-started
+	time.Sleep(150 * time.Millisecond)
+
+	retry := httptest.NewRecorder()
+	handler.ServeHTTP(retry, newIdempotentRequest("key-1", "user-1", `{}`))
+	assert.Equal(t, http.StatusConflict, retry.Code)
+	assert.Equal(t, "IDEMPOTENCY_REQUEST_IN_PROGRESS", decodeErrorCode(t, retry))
+
+	close(release)
+	<-done
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestIdempotency_AbandonedKeyTakenOver(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	var calls int32
+	handler := middl.Idempotency(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	// Clé réservée par un process arrêté : plus aucun heartbeat depuis une heure
+	_, created, err := store.Reserve(context.Background(), &entity.IdempotencyRecord{Scope: "user-1", Key: "key-1"}, time.Minute)
+	require.NoError(t, err)
+	require.True(t, created)
+	store.records["user-1|key-1"].LockedAt = time.Now().Add(-time.Hour)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newIdempotentRequest("key-1", "user-1", `{}`))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestIdempotency_ServerErrorReleasesKey(t *testing.T) {
+	var calls int32
+	handler := middl.Idempotency(newMemoryIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			utils.WriteAppError(w, utils.ErrInternalServer)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, newIdempotentRequest("key-1", "user-1", `{}`))
+	assert.Equal(t, http.StatusInternalServerError, first.Code)
+
+	// Le client peut réessayer avec la même clé
+	retry := httptest.NewRecorder()
+	handler.ServeHTTP(retry, newIdempotentRequest("key-1", "user-1", `{}`))
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestIdempotency_HandlerReadsOriginalBody(t *testing.T) {
+	var received string
+	handler := middl.Idempotency(newMemoryIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(r.Body)
+		received = buf.String()
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("key-1", "user-1", `{"customer_id":"c1"}`))
+
+	assert.Equal(t, `{"customer_id":"c1"}`, received)
+}
+
+func TestIdempotency_WithoutHeaderPassesThrough(t *testing.T) {
+	var calls int32
+	handler := middl.Idempotency(newMemoryIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("", "user-1", `{}`))
+	handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("", "user-1", `{}`))
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestIdempotency_InvalidKey(t *testing.T) {
+	handler := middl.Idempotency(newMemoryIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("le handler ne doit pas être appelé")
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newIdempotentRequest(string(bytes.Repeat([]byte("k"), 256)), "user-1", `{}`))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "IDEMPOTENCY_KEY_INVALID", decodeErrorCode(t, w))
+}
@@ -3,36 +3,66 @@ package middl
 import (
 	"Goshop/interfaces/utils"
 	"net/http"
+
+	"github.com/rs/zerolog"
 )
 
 // RequireRoles : middleware RBAC.
 // Exemple : r.Use(RequireRoles("admin", "manager"))
 //
 // Il nécessite que AuthMiddleware ait déjà injecté
-// les claims (UserID + Role).
+// les claims (UserID + Roles).
 
 func RequireRoles(allowedRoles ...string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
-			// 1. Extraction du rôle utilisateur
-			role, ok := utils.UserRoleFromContext(r.Context())
-			if !ok {
+			// 1. Utilisateur authentifié ?
+			if _, ok := utils.GetUserID(r.Context()); !ok {
 				utils.WriteAppError(w, utils.ErrUnauthorized)
 				return
 			}
 
 			// 2. Vérification rôle autorisé
-			for _, allowed := range allowedRoles {
-				if role == allowed {
-					next.ServeHTTP(w, r)
-					return
-				}
+			if utils.HasAnyRole(r.Context(), allowedRoles...) {
+				next.ServeHTTP(w, r)
+				return
 			}
 
 			// 3. Sinon refus
+			zerolog.Ctx(r.Context()).Warn().
+				Strs("required_roles", allowedRoles).
+				Strs("user_roles", utils.UserRolesFromContext(r.Context())).
+				Msg("Access denied: missing role")
 			utils.WriteAppError(w, utils.ErrForbidden)
 		})
 	}
 }
+
+// RequirePermissions exige TOUTES les permissions listées.
+// Exemple : r.With(RequirePermissions("products:write")).Post(...)
+func RequirePermissions(required ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			if _, ok := utils.GetUserID(r.Context()); !ok {
+				utils.WriteAppError(w, utils.ErrUnauthorized)
+				return
+			}
+
+			for _, permission := range required {
+				if !utils.HasPermission(r.Context(), permission) {
+					zerolog.Ctx(r.Context()).Warn().
+						Str("required_permission", permission).
+						Msg("Access denied: missing permission")
+					utils.WriteAppError(w, utils.ErrForbidden)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package middl_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Goshop/interfaces/middl"
+	"Goshop/interfaces/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func rbacRequest(ctx context.Context) *http.Request {
+	return httptest.NewRequest(http.MethodPost, "/api/products", nil).WithContext(ctx)
+}
+
+func okHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func authenticated(roles, permissions []string) context.Context {
+	ctx := utils.WithUserID(context.Background(), "user-1")
+	ctx = utils.WithUserRoles(ctx, roles)
+	return utils.WithUserPermissions(ctx, permissions)
+}
+
+func TestRequireRoles(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected int
+	}{
+		{"anonyme", context.Background(), http.StatusUnauthorized},
+		{"sans rôle", authenticated(nil, nil), http.StatusForbidden},
+		{"mauvais rôle", authenticated([]string{"customer"}, nil), http.StatusForbidden},
+		{"un des rôles", authenticated([]string{"customer", "manager"}, nil), http.StatusNoContent},
+		{"rôle unique (WithUserRole)", utils.WithUser(context.Background(), "user-1", "admin"), http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			middl.RequireRoles("admin", "manager")(okHandler()).ServeHTTP(w, rbacRequest(tt.ctx))
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
+
+func TestRequirePermissions(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected int
+	}{
+		{"anonyme", context.Background(), http.StatusUnauthorized},
+		{"aucune permission", authenticated([]string{"customer"}, []string{"orders:write"}), http.StatusForbidden},
+		{"une seule des deux", authenticated([]string{"manager"}, []string{"products:write"}), http.StatusForbidden},
+		{"toutes", authenticated([]string{"admin"}, []string{"products:write", "orders:read:any"}), http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			middl.RequirePermissions("products:write", "orders:read:any")(okHandler()).ServeHTTP(w, rbacRequest(tt.ctx))
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
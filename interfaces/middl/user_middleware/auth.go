@@ -1,22 +1,34 @@
 package middleware
 
 import (
+	authentity "Goshop/domain/auth_entity"
+	userentity "Goshop/domain/entity/user_entity"
 	"Goshop/interfaces/utils"
+	"context"
+	"errors"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// APIKeyAuthenticator vérifie une clé d'API (authusecase.APIKeyUsecase)
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, raw string) (*authentity.APIKey, error)
+}
+
 // AuthMiddlewareConfig permet d'injecter un faux validateur en tests.
+// APIKeys, si renseigné, accepte aussi les clés d'API (header X-API-Key ou
+// "Authorization: ApiKey <clé>") en plus des JWT.
 type AuthMiddlewareConfig struct {
 	JWTValidator utils.JWTValidator
+	APIKeys      APIKeyAuthenticator
 }
 
 // NewAuthMiddleware crée un middleware propre et testable.
 func NewAuthMiddleware(config ...AuthMiddlewareConfig) func(http.Handler) http.Handler {
 	var validator utils.JWTValidator
+	var apiKeys APIKeyAuthenticator
 
 	// Choix entre validateur custom (tests) ou celui de utils
 	if len(config) > 0 && config[0].JWTValidator != nil {
@@ -24,12 +36,23 @@ func NewAuthMiddleware(config ...AuthMiddlewareConfig) func(http.Handler) http.H
 	} else {
 		validator = defaultJWTValidator{}
 	}
+	if len(config) > 0 {
+		apiKeys = config[0].APIKeys
+	}
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
 			auth := r.Header.Get("Authorization")
 
+			// 0. Client machine : clé d'API au lieu d'un JWT
+			if apiKeys != nil {
+				if rawKey, ok := apiKeyFromRequest(r); ok {
+					serveWithAPIKey(apiKeys, rawKey, w, r, next)
+					return
+				}
+			}
+
 			// 1. Header manquant
 			if auth == "" {
 				utils.WriteAppError(w, utils.ErrTokenMissing) // "missing Authorization header"
@@ -49,9 +72,13 @@ func NewAuthMiddleware(config ...AuthMiddlewareConfig) func(http.Handler) http.H
 				return
 			}
 
-			// 4. Validation via le validateur
+			// 4. Validation via le validateur (signature, iss, aud, exp, nbf avec tolérance)
 			claims, err := validator.ValidateToken(tokenString)
 			if err != nil {
+				if errors.Is(err, jwt.ErrTokenExpired) {
+					utils.WriteAppError(w, utils.ErrAccessTokenExpired)
+					return
+				}
 				// CORRECTION ICI : Utiliser ErrTokenMalformed au lieu de ErrUnauthorized
 				utils.WriteAppError(w, utils.ErrTokenMalformed) // "invalid or corrupted token"
 				return
@@ -64,32 +91,62 @@ func NewAuthMiddleware(config ...AuthMiddlewareConfig) func(http.Handler) http.H
 				return
 			}
 
-			// 6. Vérification expiration
-			exp, ok := claims["exp"].(float64)
-			if !ok {
-				utils.WriteAppError(w, utils.ErrTokenMalformed)
-				return
-			}
-			if int64(exp) < time.Now().Unix() {
-				utils.WriteAppError(w, utils.ErrAccessTokenExpired)
-				return
-			}
-
-			// 7. Extraction du user ID (sub)
+			// 6. Extraction du user ID (sub)
 			userID, ok := claims["sub"].(string)
 			if !ok || userID == "" {
 				utils.WriteAppError(w, utils.ErrTokenSubjectInvalid)
 				return
 			}
 
-			// 8. Injection dans le context
-			ctx := utils.WithUserID(r.Context(), userID)
+			// 7. Injection dans le context (identité + RBAC)
+			roles := utils.ClaimStrings(claims, utils.CLAIM_ROLES)
+			ctx := utils.WithUser(r.Context(), userID, userentity.PrimaryRole(roles))
+			ctx = utils.WithUserRoles(ctx, roles)
+			ctx = utils.WithUserPermissions(ctx, utils.ClaimStrings(claims, utils.CLAIM_PERMISSIONS))
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// apiKeyFromRequest extrait la clé du header X-API-Key ou "Authorization: ApiKey <clé>"
+func apiKeyFromRequest(r *http.Request) (string, bool) {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key, true
+	}
+	if key, ok := strings.CutPrefix(r.Header.Get("Authorization"), "ApiKey "); ok {
+		return key, true
+	}
+	return "", false
+}
+
+// serveWithAPIKey authentifie la clé et injecte son principal : pas de rôle,
+// les permissions sont les scopes de la clé
+func serveWithAPIKey(apiKeys APIKeyAuthenticator, rawKey string, w http.ResponseWriter, r *http.Request, next http.Handler) {
+	key, err := apiKeys.Authenticate(r.Context(), rawKey)
+	if err != nil {
+		var retryErr *utils.RetryAfterError
+		var appErr *utils.AppError
+		switch {
+		case errors.As(err, &retryErr):
+			w.Header().Set("Retry-After", retryErr.RetryAfterSeconds())
+			utils.WriteAppError(w, retryErr.Err)
+		case errors.As(err, &appErr):
+			utils.WriteAppError(w, appErr)
+		default:
+			utils.WriteAppError(w, utils.ErrInternalServer)
+		}
+		return
+	}
+
+	ctx := utils.WithUser(r.Context(), utils.APIKeyPrincipal(key.ID), "")
+	ctx = utils.WithUserRoles(ctx, []string{})
+	ctx = utils.WithUserPermissions(ctx, key.Scopes)
+	ctx = utils.WithAPIKeyID(ctx, key.ID)
+
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
 // Version par défaut (production)
 type defaultJWTValidator struct{}
 
@@ -1,6 +1,8 @@
 package middleware_test
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -10,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
+	authentity "Goshop/domain/auth_entity"
 	mw "Goshop/interfaces/middl/user_middleware"
 	iutils "Goshop/interfaces/utils" // Pour GetUserID et autres fonctions utilitaires
 	mockutils "Goshop/mocks/utils"   // Mock de JWTValidator
@@ -138,14 +141,9 @@ func TestNewAuthMiddleware_ExpiredToken(t *testing.T) {
 
 	mockValidator := mockutils.NewMockJWTValidator(ctrl)
 
-	// Claims expirés
-	expiredClaims := jwt.MapClaims{
-		"sub":  "user-123",
-		"type": "access",
-		"exp":  float64(time.Now().Add(-1 * time.Hour).Unix()), // Expiré
-	}
-
-	mockValidator.EXPECT().ValidateToken("expired.token").Return(expiredClaims, nil)
+	// L'expiration (avec tolérance) est vérifiée par le validateur
+	mockValidator.EXPECT().ValidateToken("expired.token").
+		Return(nil, fmt.Errorf("invalid token: %w", jwt.ErrTokenExpired))
 
 	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("Handler should not be called")
@@ -205,6 +203,86 @@ func TestNewAuthMiddleware_MissingSubject(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "invalid token subject")
 }
 
+func TestNewAuthMiddleware_InjectsRolesAndPermissions(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockValidator := mockutils.NewMockJWTValidator(ctrl)
+
+	// Les claims décodés du JSON arrivent en []interface{}
+	claims := jwt.MapClaims{
+		"sub":   "user-123",
+		"type":  "access",
+		"exp":   float64(time.Now().Add(1 * time.Hour).Unix()),
+		"roles": []interface{}{"customer", "admin"},
+		"perms": []interface{}{"products:write", "users:roles:write"},
+	}
+
+	mockValidator.EXPECT().ValidateToken("rbac.token").Return(claims, nil)
+
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		role, ok := iutils.UserRoleFromContext(r.Context())
+		assert.True(t, ok)
+		assert.Equal(t, "admin", role, "le rôle principal est le plus privilégié")
+		assert.Equal(t, []string{"customer", "admin"}, iutils.UserRolesFromContext(r.Context()))
+		assert.True(t, iutils.HasPermission(r.Context(), "products:write"))
+		assert.False(t, iutils.HasPermission(r.Context(), "orders:read:any"))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	middleware := mw.NewAuthMiddleware(mw.AuthMiddlewareConfig{
+		JWTValidator: mockValidator,
+	})
+
+	req := httptest.NewRequest("GET", "/protected", nil)
+	req.Header.Set("Authorization", "Bearer rbac.token")
+	w := httptest.NewRecorder()
+
+	// Act
+	middleware(testHandler).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestNewAuthMiddleware_TokenWithoutRoles(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockValidator := mockutils.NewMockJWTValidator(ctrl)
+
+	// Token émis avant le RBAC : authentifié, mais sans aucun droit
+	claims := jwt.MapClaims{
+		"sub":  "user-123",
+		"type": "access",
+		"exp":  float64(time.Now().Add(1 * time.Hour).Unix()),
+	}
+
+	mockValidator.EXPECT().ValidateToken("legacy.token").Return(claims, nil)
+
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, iutils.UserRolesFromContext(r.Context()))
+		assert.Empty(t, iutils.UserPermissionsFromContext(r.Context()))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	middleware := mw.NewAuthMiddleware(mw.AuthMiddlewareConfig{
+		JWTValidator: mockValidator,
+	})
+
+	req := httptest.NewRequest("GET", "/protected", nil)
+	req.Header.Set("Authorization", "Bearer legacy.token")
+	w := httptest.NewRecorder()
+
+	// Act
+	middleware(testHandler).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
 // ========================================
 // Tests avec l'ancienne version (compatibilité)
 // ========================================
@@ -268,3 +346,108 @@ func TestAuthMiddleware_EmptyToken(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 	// ValidateToken sera appelé avec une string vide et échouera
 }
+
+// ========================================
+// Clés d'API (clients machine)
+// ========================================
+
+// fakeAPIKeys : authentificateur de clés d'API de test
+type fakeAPIKeys func(ctx context.Context, raw string) (*authentity.APIKey, error)
+
+func (f fakeAPIKeys) Authenticate(ctx context.Context, raw string) (*authentity.APIKey, error) {
+	return f(ctx, raw)
+}
+
+func acceptKey(t *testing.T, want string) fakeAPIKeys {
+	return func(_ context.Context, raw string) (*authentity.APIKey, error) {
+		assert.Equal(t, want, raw)
+		return &authentity.APIKey{ID: "key-1", Scopes: []string{"products:write"}}, nil
+	}
+}
+
+func TestNewAuthMiddleware_APIKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		value  string
+	}{
+		{"header X-API-Key", "X-API-Key", "gsk_abc_secret"},
+		{"schéma ApiKey", "Authorization", "ApiKey gsk_abc_secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx := r.Context()
+				userID, _ := iutils.GetUserID(ctx)
+				keyID, ok := iutils.APIKeyIDFromContext(ctx)
+				assert.Equal(t, "apikey:key-1", userID)
+				assert.True(t, ok)
+				assert.Equal(t, "key-1", keyID)
+				assert.Empty(t, iutils.UserRolesFromContext(ctx))
+				assert.Equal(t, []string{"products:write"}, iutils.UserPermissionsFromContext(ctx))
+				w.WriteHeader(http.StatusOK)
+			})
+
+			middleware := mw.NewAuthMiddleware(mw.AuthMiddlewareConfig{APIKeys: acceptKey(t, "gsk_abc_secret")})
+
+			req := httptest.NewRequest("GET", "/api/products", nil)
+			req.Header.Set(tt.header, tt.value)
+			w := httptest.NewRecorder()
+
+			middleware(testHandler).ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+		})
+	}
+}
+
+func TestNewAuthMiddleware_APIKeyRejected(t *testing.T) {
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Handler should not be called")
+	})
+
+	tests := []struct {
+		name       string
+		err        error
+		status     int
+		retryAfter string
+	}{
+		{"clé invalide", iutils.ErrAPIKeyInvalid, http.StatusUnauthorized, ""},
+		{"limite atteinte", iutils.WithRetryAfter(iutils.ErrAPIKeyRateLimited, 15*time.Second), http.StatusTooManyRequests, "15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := fakeAPIKeys(func(context.Context, string) (*authentity.APIKey, error) {
+				return nil, tt.err
+			})
+			middleware := mw.NewAuthMiddleware(mw.AuthMiddlewareConfig{APIKeys: keys})
+
+			req := httptest.NewRequest("GET", "/api/products", nil)
+			req.Header.Set("X-API-Key", "gsk_abc_secret")
+			w := httptest.NewRecorder()
+
+			middleware(testHandler).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.retryAfter, w.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestAuthMiddleware_APIKeyNotAccepted(t *testing.T) {
+	// Sans authentificateur configuré, une clé d'API n'ouvre pas l'accès
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Next handler should not be called with an API key")
+	})
+
+	req := httptest.NewRequest("GET", "/auth/me", nil)
+	req.Header.Set("Authorization", "ApiKey gsk_abc_secret")
+	w := httptest.NewRecorder()
+
+	mw.AuthMiddleware(nextHandler).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid token format")
+}
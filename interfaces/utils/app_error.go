@@ -3,6 +3,8 @@ package utils
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type AppError struct {
@@ -32,3 +34,32 @@ func WriteAppError(w http.ResponseWriter, appErr *AppError) {
 		"status":  appErr.Status,
 	})
 }
+
+// RetryAfterError : AppError assortie du délai avant lequel le client ne doit
+// pas réessayer. middl.ErrorHandler écrit l'AppError et l'en-tête Retry-After.
+type RetryAfterError struct {
+	Err   *AppError
+	After time.Duration
+}
+
+func WithRetryAfter(err *AppError, after time.Duration) *RetryAfterError {
+	return &RetryAfterError{Err: err, After: after}
+}
+
+func (e *RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap : errors.Is(err, ErrXxx) reste vrai
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// RetryAfterSeconds arrondit le délai à la seconde supérieure (minimum 1)
+func (e *RetryAfterError) RetryAfterSeconds() string {
+	seconds := int((e.After + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return strconv.Itoa(seconds)
+}
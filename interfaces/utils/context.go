@@ -8,6 +8,14 @@ type contextKey string
 const (
 	userIDKey   contextKey = "user_id"
 	userRoleKey contextKey = "user_role"
+
+	userRolesKey       contextKey = "user_roles"
+	userPermissionsKey contextKey = "user_permissions"
+	customerIDKey      contextKey = "customer_id"
+	apiKeyIDKey        contextKey = "api_key_id"
+
+	userAgentKey contextKey = "user_agent"
+	clientIPKey  contextKey = "client_ip"
 )
 
 // GetUserID récupère l'ID utilisateur du contexte
@@ -49,6 +57,38 @@ func WithUser(ctx context.Context, userID, role string) context.Context {
 	return context.WithValue(ctx, userRoleKey, role)
 }
 
+// Injecte tous les rôles de l'utilisateur
+func WithUserRoles(ctx context.Context, roles []string) context.Context {
+	return context.WithValue(ctx, userRolesKey, roles)
+}
+
+// Injecte les permissions de l'utilisateur
+func WithUserPermissions(ctx context.Context, permissions []string) context.Context {
+	return context.WithValue(ctx, userPermissionsKey, permissions)
+}
+
+// Injecte l'ID du client rattaché à l'utilisateur connecté
+func WithCustomerID(ctx context.Context, customerID string) context.Context {
+	return context.WithValue(ctx, customerIDKey, customerID)
+}
+
+// APIKeyPrincipal : identifiant injecté comme UserID pour une requête
+// authentifiée par clé d'API ("apikey:<id>"), distinct de tout utilisateur
+func APIKeyPrincipal(keyID string) string {
+	return "apikey:" + keyID
+}
+
+// Injecte l'ID de la clé d'API qui authentifie la requête
+func WithAPIKeyID(ctx context.Context, keyID string) context.Context {
+	return context.WithValue(ctx, apiKeyIDKey, keyID)
+}
+
+// Injecte le User-Agent et l'IP du client HTTP
+func WithClientInfo(ctx context.Context, userAgent, ip string) context.Context {
+	ctx = context.WithValue(ctx, userAgentKey, userAgent)
+	return context.WithValue(ctx, clientIPKey, ip)
+}
+
 // ---------- GETTERS (pour récupérer depuis le context) ----------
 
 // Récupère UserID
@@ -62,3 +102,81 @@ func UserRoleFromContext(ctx context.Context) (string, bool) {
 	role, ok := ctx.Value(userRoleKey).(string)
 	return role, ok
 }
+
+// Récupère tous les rôles (à défaut, le rôle unique injecté par WithUserRole)
+func UserRolesFromContext(ctx context.Context) []string {
+	if roles, ok := ctx.Value(userRolesKey).([]string); ok {
+		return roles
+	}
+	if role, ok := UserRoleFromContext(ctx); ok && role != "" {
+		return []string{role}
+	}
+	return nil
+}
+
+// Récupère les permissions
+func UserPermissionsFromContext(ctx context.Context) []string {
+	permissions, _ := ctx.Value(userPermissionsKey).([]string)
+	return permissions
+}
+
+// HasAnyRole indique si l'utilisateur possède au moins un des rôles
+func HasAnyRole(ctx context.Context, roles ...string) bool {
+	for _, owned := range UserRolesFromContext(ctx) {
+		for _, role := range roles {
+			if owned == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasPermission indique si l'utilisateur possède la permission
+func HasPermission(ctx context.Context, permission string) bool {
+	for _, owned := range UserPermissionsFromContext(ctx) {
+		if owned == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Récupère l'ID du client rattaché à l'utilisateur connecté
+func CustomerIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(customerIDKey).(string)
+	return id, ok && id != ""
+}
+
+// Récupère l'ID de la clé d'API (requête d'un client machine)
+func APIKeyIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(apiKeyIDKey).(string)
+	return id, ok && id != ""
+}
+
+// Récupère le User-Agent et l'IP du client ("" hors requête HTTP)
+func ClientInfoFromContext(ctx context.Context) (userAgent, ip string) {
+	userAgent, _ = ctx.Value(userAgentKey).(string)
+	ip, _ = ctx.Value(clientIPKey).(string)
+	return userAgent, ip
+}
+
+// ---------- PROPRIÉTÉ DES DONNÉES ----------
+
+// OwnCustomerScope indique si l'accès doit être limité au client de
+// l'utilisateur connecté : c'est le cas dès qu'un utilisateur est présent
+// dans le contexte sans la permission `anyPermission`. Sans utilisateur
+// (appel interne, tâche de fond), aucune restriction ne s'applique.
+func OwnCustomerScope(ctx context.Context, anyPermission string) (customerID string, restricted bool) {
+	if _, ok := GetUserID(ctx); !ok || HasPermission(ctx, anyPermission) {
+		return "", false
+	}
+	customerID, _ = CustomerIDFromContext(ctx)
+	return customerID, true
+}
+
+// CanAccessCustomer : l'utilisateur est le client `customerID` ou possède `anyPermission`
+func CanAccessCustomer(ctx context.Context, customerID, anyPermission string) bool {
+	own, restricted := OwnCustomerScope(ctx, anyPermission)
+	return !restricted || (own != "" && own == customerID)
+}
@@ -0,0 +1,145 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	paginationdto "Goshop/application/dto/pagination_dto"
+)
+
+const CURSOR_PARAM = "cursor"
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// Clé HMAC des curseurs. Sans CURSOR_SECRET, une clé aléatoire est générée au
+// démarrage : les curseurs restent valides tant que l'instance tourne.
+var cursorSecret = randomCursorSecret()
+
+func InitCursorSecret(secret string) {
+	if secret != "" {
+		cursorSecret = []byte(secret)
+	}
+}
+
+func randomCursorSecret() []byte {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic("unable to generate cursor secret: " + err.Error())
+	}
+	return secret
+}
+
+// cursorPayload est le contenu signé d'un curseur. Le champ R lie le curseur
+// à une ressource : un curseur de produits est refusé sur /api/orders.
+type cursorPayload struct {
+	R string    `json:"r"`
+	T time.Time `json:"t"`
+	I string    `json:"i"`
+	B bool      `json:"b,omitempty"`
+}
+
+// EncodeCursor produit un jeton opaque "<payload>.<signature>" en base64url
+func EncodeCursor(resource string, cursor paginationdto.Cursor, backward bool) string {
+	payload, _ := json.Marshal(cursorPayload{
+		R: resource,
+		T: cursor.CreatedAt.UTC(),
+		I: cursor.ID,
+		B: backward,
+	})
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(signCursor(encoded))
+}
+
+// DecodeCursor vérifie la signature et la ressource d'un jeton
+func DecodeCursor(resource, token string) (*paginationdto.Cursor, bool, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, false, errInvalidCursor
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, signCursor(encoded)) {
+		return nil, false, errInvalidCursor
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, false, errInvalidCursor
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, false, errInvalidCursor
+	}
+	if payload.R != resource || payload.I == "" || payload.T.IsZero() {
+		return nil, false, errInvalidCursor
+	}
+
+	return &paginationdto.Cursor{CreatedAt: payload.T, ID: payload.I}, payload.B, nil
+}
+
+func signCursor(encoded string) []byte {
+	mac := hmac.New(sha256.New, cursorSecret)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
+
+// IsCursorRequest indique si le client a choisi la pagination par curseur.
+// `?cursor=` (vide) demande la première page.
+func IsCursorRequest(r *http.Request) bool {
+	return r.URL.Query().Has(CURSOR_PARAM)
+}
+
+// GetCursorPage lit le paramètre `cursor` d'une requête pour la ressource donnée
+func GetCursorPage(r *http.Request, resource string, limit int) (paginationdto.CursorPage, error) {
+	page := paginationdto.CursorPage{Limit: limit}
+
+	token := r.URL.Query().Get(CURSOR_PARAM)
+	if token == "" {
+		return page, nil
+	}
+
+	after, backward, err := DecodeCursor(resource, token)
+	if err != nil {
+		return page, ErrInvalidCursor
+	}
+	page.After = after
+	page.Backward = backward
+	return page, nil
+}
+
+// NewPage construit l'enveloppe de réponse et les curseurs voisins
+func NewPage[T, R any](resource string, req paginationdto.CursorPage, result *paginationdto.CursorResult[T], data []R) paginationdto.Page[R] {
+	info := paginationdto.PageInfo{Limit: req.Limit}
+
+	if len(data) > 0 && result.First != nil && result.Last != nil {
+		if req.Backward {
+			// On vient d'une page plus ancienne : elle existe forcément
+			info.NextCursor = EncodeCursor(resource, *result.Last, false)
+			if result.HasMore {
+				info.PrevCursor = EncodeCursor(resource, *result.First, true)
+			}
+		} else {
+			if result.HasMore {
+				info.NextCursor = EncodeCursor(resource, *result.Last, false)
+			}
+			if req.After != nil {
+				info.PrevCursor = EncodeCursor(resource, *result.First, true)
+			}
+		}
+	}
+	info.HasMore = info.NextCursor != ""
+
+	if data == nil {
+		data = []R{}
+	}
+	return paginationdto.Page[R]{Data: data, Pagination: info}
+}
@@ -0,0 +1,127 @@
+package utils_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	paginationdto "Goshop/application/dto/pagination_dto"
+	"Goshop/interfaces/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	utils.InitCursorSecret("test-secret")
+	position := paginationdto.Cursor{
+		CreatedAt: time.Date(2025, 3, 14, 15, 9, 26, 535897000, time.UTC),
+		ID:        "0b6c7d1e-0000-4000-8000-000000000001",
+	}
+
+	token := utils.EncodeCursor("products", position, true)
+	decoded, backward, err := utils.DecodeCursor("products", token)
+
+	require.NoError(t, err)
+	assert.True(t, backward)
+	assert.Equal(t, position.ID, decoded.ID)
+	assert.True(t, position.CreatedAt.Equal(decoded.CreatedAt), "précision microseconde conservée")
+}
+
+func TestCursor_Rejected(t *testing.T) {
+	utils.InitCursorSecret("test-secret")
+	token := utils.EncodeCursor("products", paginationdto.Cursor{CreatedAt: time.Now(), ID: "p1"}, false)
+	payload, signature, _ := strings.Cut(token, ".")
+
+	cases := map[string]struct {
+		resource string
+		token    string
+	}{
+		"autre ressource":   {"orders", token},
+		"payload modifié":   {"products", "x" + payload + "." + signature},
+		"signature absente": {"products", payload},
+		"signature fausse":  {"products", payload + ".AAAA"},
+		"jeton illisible":   {"products", "%%%"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := utils.DecodeCursor(tc.resource, tc.token)
+			assert.Error(t, err)
+		})
+	}
+
+	// Une autre clé invalide les curseurs existants
+	utils.InitCursorSecret("rotated-secret")
+	_, _, err := utils.DecodeCursor("products", token)
+	assert.Error(t, err)
+}
+
+func TestGetCursorPage(t *testing.T) {
+	utils.InitCursorSecret("test-secret")
+
+	page, err := utils.GetCursorPage(httptest.NewRequest("GET", "/api/products?cursor=", nil), "products", 20)
+	require.NoError(t, err)
+	assert.Nil(t, page.After, "cursor vide = première page")
+	assert.Equal(t, 20, page.Limit)
+
+	_, err = utils.GetCursorPage(httptest.NewRequest("GET", "/api/products?cursor=bogus", nil), "products", 20)
+	assert.ErrorIs(t, err, utils.ErrInvalidCursor)
+}
+
+func TestNewPage_Cursors(t *testing.T) {
+	utils.InitCursorSecret("test-secret")
+	first := &paginationdto.Cursor{CreatedAt: time.Now(), ID: "first"}
+	last := &paginationdto.Cursor{CreatedAt: time.Now().Add(-time.Hour), ID: "last"}
+	data := []string{"first", "last"}
+
+	decode := func(token string) (string, bool) {
+		require.NotEmpty(t, token)
+		c, backward, err := utils.DecodeCursor("products", token)
+		require.NoError(t, err)
+		return c.ID, backward
+	}
+
+	t.Run("première page", func(t *testing.T) {
+		page := utils.NewPage("products", paginationdto.CursorPage{Limit: 2},
+			&paginationdto.CursorResult[string]{Items: data, First: first, Last: last, HasMore: true}, data)
+
+		assert.True(t, page.Pagination.HasMore)
+		assert.Empty(t, page.Pagination.PrevCursor)
+		id, backward := decode(page.Pagination.NextCursor)
+		assert.Equal(t, "last", id)
+		assert.False(t, backward)
+	})
+
+	t.Run("dernière page", func(t *testing.T) {
+		page := utils.NewPage("products", paginationdto.CursorPage{Limit: 2, After: first},
+			&paginationdto.CursorResult[string]{Items: data, First: first, Last: last}, data)
+
+		assert.False(t, page.Pagination.HasMore)
+		assert.Empty(t, page.Pagination.NextCursor)
+		id, backward := decode(page.Pagination.PrevCursor)
+		assert.Equal(t, "first", id)
+		assert.True(t, backward)
+	})
+
+	t.Run("retour arrière", func(t *testing.T) {
+		page := utils.NewPage("products", paginationdto.CursorPage{Limit: 2, After: last, Backward: true},
+			&paginationdto.CursorResult[string]{Items: data, First: first, Last: last, HasMore: true}, data)
+
+		id, _ := decode(page.Pagination.NextCursor)
+		assert.Equal(t, "last", id)
+		id, backward := decode(page.Pagination.PrevCursor)
+		assert.Equal(t, "first", id)
+		assert.True(t, backward)
+	})
+
+	t.Run("page vide", func(t *testing.T) {
+		page := utils.NewPage[string, string]("products", paginationdto.CursorPage{Limit: 2},
+			&paginationdto.CursorResult[string]{}, nil)
+
+		assert.NotNil(t, page.Data, "data doit être sérialisé en []")
+		assert.Empty(t, page.Pagination.NextCursor)
+		assert.Empty(t, page.Pagination.PrevCursor)
+	})
+}
@@ -30,12 +30,24 @@ var (
 		http.StatusUnauthorized,
 	)
 
+	ErrRefreshTokenReused = NewAppError(
+		"REFRESH_TOKEN_REUSED",
+		"refresh token reuse detected, please log in again",
+		http.StatusUnauthorized,
+	)
+
 	ErrRefreshTokenNotFound = NewAppError(
 		"REFRESH_TOKEN_NOT_FOUND",
 		"refresh token not found",
 		http.StatusUnauthorized,
 	)
 
+	ErrSessionNotFound = NewAppError(
+		"SESSION_NOT_FOUND",
+		"session not found",
+		http.StatusNotFound,
+	)
+
 	ErrTokenMalformed = NewAppError(
 		"TOKEN_MALFORMED",
 		"invalid or corrupted token", // ✅ MODIFIÉ
@@ -85,24 +97,55 @@ var (
 	// Erreurs générales
 	ErrInvalidPayload   = NewAppError("INVALID_PAYLOAD", "invalid request body", http.StatusBadRequest)
 	ErrValidationFailed = NewAppError("VALIDATION_FAILED", "invalid fields in request", http.StatusBadRequest)
+	ErrInvalidCursor    = NewAppError("INVALID_CURSOR", "pagination cursor is invalid or belongs to another list", http.StatusBadRequest)
 	ErrInternalServer   = NewAppError("INTERNAL_SERVER_ERROR", "unexpected server error", http.StatusInternalServerError)
 	ErrNotFound         = NewAppError("NOT_FOUND", "resource not found", http.StatusNotFound)
 
 	// Customer errors
-	ErrCustomerNotFound   = NewAppError("CUSTOMER_NOT_FOUND", "customer not found", http.StatusNotFound)
-	ErrCustomerCreateFail = NewAppError("CUSTOMER_CREATION_FAILED", "unable to create customer", http.StatusInternalServerError)
-	ErrCustomerUpdateFail = NewAppError("CUSTOMER_UPDATE_FAILED", "unable to update customer", http.StatusInternalServerError)
-	ErrCustomerDeleteFail = NewAppError("CUSTOMER_DELETE_FAILED", "unable to delete customer", http.StatusInternalServerError)
+	ErrCustomerNotFound       = NewAppError("CUSTOMER_NOT_FOUND", "customer not found", http.StatusNotFound)
+	ErrCustomerCreateFail     = NewAppError("CUSTOMER_CREATION_FAILED", "unable to create customer", http.StatusInternalServerError)
+	ErrCustomerUpdateFail     = NewAppError("CUSTOMER_UPDATE_FAILED", "unable to update customer", http.StatusInternalServerError)
+	ErrCustomerDeleteFail     = NewAppError("CUSTOMER_DELETE_FAILED", "unable to delete customer", http.StatusInternalServerError)
+	ErrCustomerRestoreFail    = NewAppError("CUSTOMER_RESTORE_FAILED", "unable to restore customer", http.StatusInternalServerError)
+	ErrCustomerInvalidFilter  = NewAppError("INVALID_CUSTOMER_FILTER", "invalid search or filter parameter", http.StatusBadRequest)
+	ErrCustomerProfileMissing = NewAppError("CUSTOMER_PROFILE_MISSING", "no customer profile is linked to this account", http.StatusForbidden)
 
 	// Product errors
 	ErrProductNotFound          = NewAppError("PRODUCT_NOT_FOUND", "product not found", http.StatusNotFound)
 	ErrProductCreateFail        = NewAppError("PRODUCT_CREATION_FAILED", "unable to create product", http.StatusInternalServerError)
 	ErrProductUpdateFail        = NewAppError("PRODUCT_UPDATE_FAILED", "unable to update product", http.StatusInternalServerError)
 	ErrProductDeleteFail        = NewAppError("PRODUCT_DELETE_FAILED", "unable to delete product", http.StatusInternalServerError)
+	ErrProductRestoreFail       = NewAppError("PRODUCT_RESTORE_FAILED", "unable to restore product", http.StatusInternalServerError)
+	ErrProductPurgeFail         = NewAppError("PRODUCT_PURGE_FAILED", "unable to purge product", http.StatusInternalServerError)
+	ErrProductNotArchived       = NewAppError("PRODUCT_NOT_ARCHIVED", "only archived products can be purged, delete the product first", http.StatusConflict)
 	ErrProductInsufficientStock = NewAppError("INSUFFICIENT_STOCK", "product stock is insufficient", http.StatusBadRequest)
 	ErrProductInvalidPrice      = NewAppError("INVALID_PRICE", "product price must be greater than 0", http.StatusBadRequest)
 	ErrProductInvalidStock      = NewAppError("INVALID_STOCK", "product stock cannot be negative", http.StatusBadRequest)
 	ErrProductInvalidName       = NewAppError("INVALID_NAME", "product name is required", http.StatusBadRequest)
+	ErrProductInvalidFilter     = NewAppError("INVALID_PRODUCT_FILTER", "invalid search, filter or sort parameter", http.StatusBadRequest)
+	ErrProductInvalidCategory   = NewAppError("INVALID_PRODUCT_CATEGORY", "one or more categories do not exist", http.StatusBadRequest)
+
+	// Category errors (arbre du catalogue)
+	ErrCategoryNotFound      = NewAppError("CATEGORY_NOT_FOUND", "category not found", http.StatusNotFound)
+	ErrCategorySlugTaken     = NewAppError("CATEGORY_SLUG_TAKEN", "category slug is already used", http.StatusConflict)
+	ErrCategoryInvalidParent = NewAppError("CATEGORY_INVALID_PARENT", "parent category does not exist or is the category itself or one of its subcategories", http.StatusBadRequest)
+	ErrCategoryHasChildren   = NewAppError("CATEGORY_HAS_CHILDREN", "category still has subcategories, move or delete them first", http.StatusConflict)
+
+	// Variant errors (déclinaisons d'un produit)
+	ErrVariantNotFound     = NewAppError("VARIANT_NOT_FOUND", "product variant not found", http.StatusNotFound)
+	ErrVariantSKUTaken     = NewAppError("VARIANT_SKU_TAKEN", "variant sku is already used", http.StatusConflict)
+	ErrVariantOptionsTaken = NewAppError("VARIANT_OPTIONS_TAKEN", "the product already has a variant with these options", http.StatusConflict)
+	ErrVariantInUse        = NewAppError("VARIANT_IN_USE", "variant is referenced by orders and cannot be deleted", http.StatusConflict)
+	ErrVariantIsDefault    = NewAppError("VARIANT_IS_DEFAULT", "the default variant cannot be deleted", http.StatusConflict)
+
+	// Image errors (images des produits)
+	ErrImageNotFound        = NewAppError("IMAGE_NOT_FOUND", "product image not found", http.StatusNotFound)
+	ErrImageMissing         = NewAppError("IMAGE_MISSING", "multipart field \"file\" is required", http.StatusBadRequest)
+	ErrImageTooLarge        = NewAppError("IMAGE_TOO_LARGE", "image exceeds the maximum upload size", http.StatusRequestEntityTooLarge)
+	ErrImageUnsupportedType = NewAppError("IMAGE_UNSUPPORTED_TYPE", "only JPEG, PNG, GIF and WebP images are accepted", http.StatusUnsupportedMediaType)
+	ErrImageLimitReached    = NewAppError("IMAGE_LIMIT_REACHED", "the product already has the maximum number of images", http.StatusConflict)
+	ErrImageInvalidOrder    = NewAppError("INVALID_IMAGE_ORDER", "image_ids must list every image of the product exactly once", http.StatusBadRequest)
+	ErrImageUploadFail      = NewAppError("IMAGE_UPLOAD_FAILED", "unable to store image", http.StatusInternalServerError)
 
 	// Order errors
 	ErrOrderNotFound          = NewAppError("ORDER_NOT_FOUND", "order not found", http.StatusNotFound)
@@ -114,8 +157,13 @@ var (
 	ErrOrderInvalidCustomer   = NewAppError("INVALID_CUSTOMER", "customer does not exist", http.StatusBadRequest)
 	ErrOrderInvalidProduct    = NewAppError("INVALID_PRODUCT", "one or more products do not exist", http.StatusBadRequest)
 	ErrOrderInsufficientStock = NewAppError("ORDER_INSUFFICIENT_STOCK", "insufficient stock for one or more products", http.StatusBadRequest)
+	ErrOrderInvalidVariant    = NewAppError("INVALID_VARIANT", "one or more variants do not exist or belong to another product", http.StatusBadRequest)
 	ErrOrderTotalMismatch     = NewAppError("ORDER_TOTAL_MISMATCH", "order total calculation mismatch", http.StatusInternalServerError)
 	ErrOrderAlreadyProcessed  = NewAppError("ORDER_ALREADY_PROCESSED", "order has already been processed and cannot be modified", http.StatusConflict)
+	ErrOrderInvalidTransition = NewAppError("INVALID_ORDER_TRANSITION", "order status transition is not allowed", http.StatusConflict)
+	ErrOrderStatusConflict    = NewAppError("ORDER_STATUS_CONFLICT", "order status was modified concurrently, retry", http.StatusConflict)
+	ErrOrderNotCancellable    = NewAppError("ORDER_NOT_CANCELLABLE", "order can no longer be cancelled", http.StatusConflict)
+	ErrOrderCancelFail        = NewAppError("ORDER_CANCEL_FAILED", "unable to cancel order", http.StatusInternalServerError)
 
 	// Order Item errors
 	ErrOrderItemInvalidQuantity = NewAppError("INVALID_QUANTITY", "item quantity must be greater than 0", http.StatusBadRequest)
@@ -127,15 +175,50 @@ var (
 	ErrTransactionCommit   = NewAppError("TRANSACTION_COMMIT_FAILED", "failed to commit transaction", http.StatusInternalServerError)
 	ErrTransactionRollback = NewAppError("TRANSACTION_ROLLBACK_FAILED", "failed to rollback transaction", http.StatusInternalServerError)
 
+	// Idempotency errors
+	ErrIdempotencyKeyInvalid    = NewAppError("IDEMPOTENCY_KEY_INVALID", "Idempotency-Key header must be 1 to 255 printable characters", http.StatusBadRequest)
+	ErrIdempotencyKeyReused     = NewAppError("IDEMPOTENCY_KEY_REUSED", "Idempotency-Key was already used with a different request", http.StatusConflict)
+	ErrIdempotencyInProgress    = NewAppError("IDEMPOTENCY_REQUEST_IN_PROGRESS", "a request with this Idempotency-Key is still being processed, retry later", http.StatusConflict)
+	ErrIdempotencyPayloadTooBig = NewAppError("IDEMPOTENCY_PAYLOAD_TOO_LARGE", "request body too large for an idempotent request", http.StatusRequestEntityTooLarge)
+	ErrIdempotencyUnavailable   = NewAppError("IDEMPOTENCY_UNAVAILABLE", "idempotency store unavailable, retry later", http.StatusServiceUnavailable)
+
 	// User errors
 	ErrUserNotFound       = NewAppError("USER_NOT_FOUND", "user not found", http.StatusNotFound)
 	ErrUserCreateFail     = NewAppError("USER_CREATION_FAILED", "unable to create user", http.StatusInternalServerError)
 	ErrUserAlreadyExists  = NewAppError("USER_ALREADY_EXISTS", "email already registered", http.StatusBadRequest)
 	ErrInvalidCredentials = NewAppError("INVALID_CREDENTIALS", "email or password incorrect", http.StatusUnauthorized)
+	ErrAccountLocked      = NewAppError("ACCOUNT_LOCKED", "account temporarily locked after too many failed login attempts", http.StatusLocked)
+	ErrLoginThrottled     = NewAppError("TOO_MANY_LOGIN_ATTEMPTS", "too many failed login attempts, retry later", http.StatusTooManyRequests)
+
+	// Password change errors
+	ErrCurrentPasswordInvalid = NewAppError("CURRENT_PASSWORD_INVALID", "current password is incorrect", http.StatusForbidden)
+	ErrPasswordUnchanged      = NewAppError("PASSWORD_UNCHANGED", "new password must differ from the current one", http.StatusBadRequest)
+
+	// Account token errors (liens envoyés par email)
+	ErrResetTokenInvalid             = NewAppError("RESET_TOKEN_INVALID", "password reset link is invalid, expired or already used", http.StatusBadRequest)
+	ErrEmailVerificationTokenInvalid = NewAppError("EMAIL_VERIFICATION_TOKEN_INVALID", "email verification link is invalid, expired or already used", http.StatusBadRequest)
+
+	// Two-factor authentication errors (TOTP)
+	ErrMFAAlreadyEnabled     = NewAppError("MFA_ALREADY_ENABLED", "two-factor authentication is already enabled", http.StatusConflict)
+	ErrMFANotEnrolled        = NewAppError("MFA_NOT_ENROLLED", "start two-factor enrolment before confirming it", http.StatusConflict)
+	ErrMFAConfirmationFailed = NewAppError("MFA_CONFIRMATION_FAILED", "code does not match the enrolled authenticator", http.StatusBadRequest)
+	ErrMFATokenInvalid       = NewAppError("MFA_TOKEN_INVALID", "two-factor login session is invalid or expired, log in again", http.StatusUnauthorized)
+	ErrMFACodeInvalid        = NewAppError("MFA_CODE_INVALID", "invalid or already used two-factor code", http.StatusUnauthorized)
+
+	// API key errors (clients machine)
+	ErrAPIKeyInvalid      = NewAppError("API_KEY_INVALID", "invalid, expired or revoked API key", http.StatusUnauthorized)
+	ErrAPIKeyRateLimited  = NewAppError("API_KEY_RATE_LIMITED", "API key rate limit exceeded, retry later", http.StatusTooManyRequests)
+	ErrAPIKeyNotFound     = NewAppError("API_KEY_NOT_FOUND", "API key not found or already revoked", http.StatusNotFound)
+	ErrAPIKeyScopeInvalid = NewAppError("API_KEY_SCOPE_INVALID", "unknown API key scope", http.StatusBadRequest)
 
 	ErrUnauthorized = NewAppError("UNAUTHORIZED", "unauthorized", http.StatusUnauthorized)
 
-	ErrForbidden = NewAppError("FORBIDDEN", "...", http.StatusForbidden)
+	ErrForbidden = NewAppError("FORBIDDEN", "you do not have permission to perform this action", http.StatusForbidden)
+
+	// Role errors
+	ErrRoleNotFound   = NewAppError("ROLE_NOT_FOUND", "role does not exist", http.StatusBadRequest)
+	ErrRoleSelfRevoke = NewAppError("ROLE_SELF_REVOKE", "administrators cannot revoke their own admin role", http.StatusConflict)
+	ErrRoleUpdateFail = NewAppError("ROLE_UPDATE_FAILED", "unable to update user roles", http.StatusInternalServerError)
 )
 
 var (
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -14,46 +15,171 @@ type JWTValidator interface {
 	ValidateToken(tokenString string) (jwt.MapClaims, error)
 }
 
-var jwtSecret []byte // remplir depuis config.Init()
+// Claims RBAC embarqués dans les access tokens
+const (
+	CLAIM_ROLES       = "roles"
+	CLAIM_PERMISSIONS = "perms"
+)
+
+// Valeurs par défaut de JWTConfig (surchargées par la config au démarrage)
+const (
+	ACCESS_TOKEN_TTL   = 15 * time.Minute
+	REFRESH_TOKEN_TTL  = 7 * 24 * time.Hour
+	DEFAULT_JWT_LEEWAY = 30 * time.Second
+	DEFAULT_JWT_ISSUER = "goshop"
+	DEFAULT_JWT_AUD    = "goshop-api"
+)
+
+// Durée de vie du token intermédiaire d'un login à deux facteurs : le temps
+// de saisir le code TOTP
+const MFA_TOKEN_TTL = 5 * time.Minute
+
+// JWTConfig : claims standards émis et exigés (iss, aud), tolérance de
+// décalage d'horloge sur exp / nbf / iat et durées de vie des tokens. La
+// session de refresh stockée en base expire en même temps que le refresh token.
+type JWTConfig struct {
+	Issuer     string
+	Audience   string
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+	Leeway     time.Duration
+}
+
+func DefaultJWTConfig() JWTConfig {
+	return JWTConfig{
+		Issuer:     DEFAULT_JWT_ISSUER,
+		Audience:   DEFAULT_JWT_AUD,
+		AccessTTL:  ACCESS_TOKEN_TTL,
+		RefreshTTL: REFRESH_TOKEN_TTL,
+		Leeway:     DEFAULT_JWT_LEEWAY,
+	}
+}
+
+var jwtConfig = DefaultJWTConfig()
+
+// InitJWTConfig applique la configuration des tokens (au démarrage, avant
+// la construction des usecases qui lisent les durées de vie)
+func InitJWTConfig(cfg JWTConfig) error {
+	switch {
+	case cfg.Issuer == "" || cfg.Audience == "":
+		return errors.New("jwt issuer and audience are required")
+	case cfg.AccessTTL <= 0 || cfg.RefreshTTL <= 0:
+		return errors.New("jwt token ttls must be positive")
+	case cfg.RefreshTTL <= cfg.AccessTTL:
+		return fmt.Errorf("refresh token ttl (%s) must exceed access token ttl (%s)", cfg.RefreshTTL, cfg.AccessTTL)
+	case cfg.Leeway < 0 || cfg.Leeway >= cfg.AccessTTL:
+		return fmt.Errorf("jwt leeway (%s) must be between 0 and the access token ttl", cfg.Leeway)
+	}
+	jwtConfig = cfg
+	return nil
+}
+
+// CurrentJWTConfig retourne la configuration appliquée
+func CurrentJWTConfig() JWTConfig {
+	return jwtConfig
+}
+
+// AccessTokenTTL : durée de vie d'un access token (expires_in)
+func AccessTokenTTL() time.Duration {
+	return jwtConfig.AccessTTL
+}
+
+// RefreshTokenTTL : durée de vie d'un refresh token et de sa session
+func RefreshTokenTTL() time.Duration {
+	return jwtConfig.RefreshTTL
+}
 
-func InitJWT(secret string) {
-	jwtSecret = []byte(secret)
+// registeredClaims : iss / aud / iat / nbf / exp communs à tous les tokens
+func registeredClaims(userID string, ttl time.Duration) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss": jwtConfig.Issuer,
+		"aud": jwtConfig.Audience,
+		"sub": userID,
+		"iat": now.Unix(),
+		"nbf": now.Unix(),
+		"exp": now.Add(ttl).Unix(),
+	}
 }
 
-// Generate access token (short lived)
-func GenerateAccessToken(userID string) (string, error) {
-	claims := jwt.MapClaims{
-		"sub":  userID,
-		"iat":  time.Now().Unix(),
-		"exp":  time.Now().Add(15 * time.Minute).Unix(),
-		"type": "access",
+// Generate access token (short lived) avec les rôles et permissions de l'utilisateur
+func GenerateAccessToken(userID string, roles, permissions []string) (string, error) {
+	if roles == nil {
+		roles = []string{}
+	}
+	if permissions == nil {
+		permissions = []string{}
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	claims := registeredClaims(userID, jwtConfig.AccessTTL)
+	claims["type"] = "access"
+	claims[CLAIM_ROLES] = roles
+	claims[CLAIM_PERMISSIONS] = permissions
+	return signToken(claims)
 }
 
 // Generate refresh token (longer lived) with jti
 func GenerateRefreshToken(userID, jti string) (string, error) {
-	claims := jwt.MapClaims{
-		"sub":  userID,
-		"iat":  time.Now().Unix(),
-		"exp":  time.Now().Add(7 * 24 * time.Hour).Unix(),
-		"jti":  jti,
-		"type": "refresh",
+	claims := registeredClaims(userID, jwtConfig.RefreshTTL)
+	claims["jti"] = jti
+	claims["type"] = "refresh"
+	return signToken(claims)
+}
+
+// GenerateMFAToken : token "mfa_pending" émis après un mot de passe correct
+// quand le 2FA est actif. Il ne donne accès qu'à POST /login/mfa (l'auth
+// middleware exige le type access) et s'échange contre la paire de tokens
+// avec un code TOTP valide.
+func GenerateMFAToken(userID string) (string, error) {
+	claims := registeredClaims(userID, MFA_TOKEN_TTL)
+	claims["type"] = "mfa_pending"
+	return signToken(claims)
+}
+
+// signToken signe avec la clé active du trousseau ; le kid permet de
+// retrouver la clé de vérification après une rotation
+func signToken(claims jwt.MapClaims) (string, error) {
+	if jwtKeys == nil {
+		return "", ErrJWTKeysNotLoaded
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	key, err := jwtKeys.SigningKey(time.Now())
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(key.method(), claims)
+	token.Header["kid"] = key.KID
+	return token.SignedString(key.Private)
 }
 
-// Validate token and return claims
+// Validate token and return claims. Signature, iss, aud, exp (obligatoire),
+// nbf et iat sont vérifiés avec la tolérance Leeway ; un token expiré donne
+// une erreur qui enveloppe jwt.ErrTokenExpired.
 func ValidateToken(tokenString string) (jwt.MapClaims, error) {
 	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-		if t.Method != jwt.SigningMethodHS256 {
+		if jwtKeys == nil {
+			return nil, ErrJWTKeysNotLoaded
+		}
+		kid, _ := t.Header["kid"].(string)
+		key, err := jwtKeys.VerificationKey(kid, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		// L'algorithme est imposé par la clé, pas par l'en-tête du token
+		if t.Method.Alg() != key.Algorithm {
 			return nil, errors.New("unexpected signing method")
 		}
-		return jwtSecret, nil
-	})
-	if err != nil || !token.Valid {
+		return key.Public, nil
+	},
+		jwt.WithValidMethods([]string{JWT_ALG_RS256, JWT_ALG_EDDSA}),
+		jwt.WithIssuer(jwtConfig.Issuer),
+		jwt.WithAudience(jwtConfig.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(jwtConfig.Leeway),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	if !token.Valid {
 		return nil, errors.New("invalid token")
 	}
 	claims, ok := token.Claims.(jwt.MapClaims)
@@ -87,3 +213,19 @@ func ValidateTokenMap(tokenString string) (map[string]interface{}, error) {
 	}
 	return map[string]interface{}(claims), nil
 }
+
+// ClaimStrings lit un claim de type liste de chaînes (roles, perms).
+// Un claim absent ou mal formé donne une liste vide.
+func ClaimStrings(claims jwt.MapClaims, key string) []string {
+	raw, ok := claims[key].([]interface{})
+	if !ok {
+		return []string{}
+	}
+	out := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if s, ok := v.(string); ok && s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
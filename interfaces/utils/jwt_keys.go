@@ -0,0 +1,327 @@
+// interfaces/utils/jwt_keys.go
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Algorithmes de signature supportés (en-tête "alg")
+const (
+	JWT_ALG_RS256 = "RS256"
+	JWT_ALG_EDDSA = "EdDSA"
+)
+
+// Taille minimale d'une clé RSA acceptée
+const minRSAKeyBits = 2048
+
+var (
+	ErrJWTKeysNotLoaded = errors.New("jwt keys not loaded")
+	ErrJWTNoSigningKey  = errors.New("no active jwt signing key")
+	ErrJWTUnknownKey    = errors.New("unknown or retired jwt key")
+)
+
+// SigningKey : clé de signature JWT identifiée par son kid (en-tête "kid").
+// Sans clé privée, la clé ne sert qu'à vérifier (clé d'une rotation
+// précédente conservée pendant la période de grâce).
+type SigningKey struct {
+	KID        string
+	Algorithm  string           // JWT_ALG_RS256 ou JWT_ALG_EDDSA
+	Private    crypto.Signer    // nil : vérification seule
+	Public     crypto.PublicKey // *rsa.PublicKey ou ed25519.PublicKey
+	ActiveFrom time.Time        // début de la signature (zéro : dès le démarrage)
+}
+
+func (k *SigningKey) method() jwt.SigningMethod {
+	if k.Algorithm == JWT_ALG_EDDSA {
+		return jwt.SigningMethodEdDSA
+	}
+	return jwt.SigningMethodRS256
+}
+
+// KeySet : trousseau des clés JWT.
+//
+// Rotation : la clé signante est la plus récente clé privée dont ActiveFrom
+// est passé. Une clé remplacée par une clé plus récente reste valide en
+// vérification pendant `grace` après l'activation de celle-ci (au moins la
+// durée de vie d'un refresh token). Une clé planifiée est publiée dans le
+// JWKS avant son activation pour que les autres services la connaissent.
+type KeySet struct {
+	keys  []*SigningKey // triées par ActiveFrom croissant
+	grace time.Duration
+}
+
+// NewKeySet valide les clés : kid unique, algorithme cohérent avec la clé et
+// au moins une clé privée active maintenant
+func NewKeySet(grace time.Duration, keys ...*SigningKey) (*KeySet, error) {
+	seen := make(map[string]bool, len(keys))
+	sorted := make([]*SigningKey, 0, len(keys))
+	for _, k := range keys {
+		if k.KID == "" {
+			return nil, errors.New("jwt key without kid")
+		}
+		if seen[k.KID] {
+			return nil, fmt.Errorf("duplicate jwt kid %q", k.KID)
+		}
+		seen[k.KID] = true
+
+		if err := checkKeyAlgorithm(k); err != nil {
+			return nil, fmt.Errorf("jwt key %q: %w", k.KID, err)
+		}
+		sorted = append(sorted, k)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ActiveFrom.Before(sorted[j].ActiveFrom)
+	})
+
+	ks := &KeySet{keys: sorted, grace: grace}
+	if _, err := ks.SigningKey(time.Now()); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+func checkKeyAlgorithm(k *SigningKey) error {
+	switch pub := k.Public.(type) {
+	case *rsa.PublicKey:
+		if k.Algorithm != JWT_ALG_RS256 {
+			return fmt.Errorf("rsa key cannot be used with %s", k.Algorithm)
+		}
+		if pub.N.BitLen() < minRSAKeyBits {
+			return fmt.Errorf("rsa key too short (%d bits, minimum %d)", pub.N.BitLen(), minRSAKeyBits)
+		}
+	case ed25519.PublicKey:
+		if k.Algorithm != JWT_ALG_EDDSA {
+			return fmt.Errorf("ed25519 key cannot be used with %s", k.Algorithm)
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", k.Public)
+	}
+	return nil
+}
+
+// SigningKey retourne la clé qui signe les tokens à l'instant `now`
+func (ks *KeySet) SigningKey(now time.Time) (*SigningKey, error) {
+	for i := len(ks.keys) - 1; i >= 0; i-- {
+		k := ks.keys[i]
+		if k.Private != nil && !k.ActiveFrom.After(now) {
+			return k, nil
+		}
+	}
+	return nil, ErrJWTNoSigningKey
+}
+
+// VerificationKey retourne la clé `kid` si elle n'est pas retirée à `now`
+func (ks *KeySet) VerificationKey(kid string, now time.Time) (*SigningKey, error) {
+	for i, k := range ks.keys {
+		if k.KID == kid && !ks.retired(i, now) {
+			return k, nil
+		}
+	}
+	return nil, ErrJWTUnknownKey
+}
+
+// retired : la clé suivante est active depuis plus que la période de grâce
+func (ks *KeySet) retired(i int, now time.Time) bool {
+	for _, next := range ks.keys[i+1:] {
+		if next.ActiveFrom.After(ks.keys[i].ActiveFrom) {
+			return now.After(next.ActiveFrom.Add(ks.grace))
+		}
+	}
+	return false
+}
+
+// ---------- JWKS ----------
+
+// JWK : clé publique au format RFC 7517 (RSA ou OKP/Ed25519)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS publie les clés non retirées à `now`, y compris les clés planifiées
+func (ks *KeySet) JWKS(now time.Time) JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	if ks == nil {
+		return set
+	}
+	for i, k := range ks.keys {
+		if ks.retired(i, now) {
+			continue
+		}
+		jwk := JWK{Kid: k.KID, Use: "sig", Alg: k.Algorithm}
+		switch pub := k.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// ---------- CHARGEMENT ----------
+
+// Trousseau global, chargé au démarrage (cmd/api) via InitJWTKeys
+var jwtKeys *KeySet
+
+func InitJWTKeys(ks *KeySet) {
+	jwtKeys = ks
+}
+
+// JWTKeys retourne le trousseau chargé (nil avant InitJWTKeys)
+func JWTKeys() *KeySet {
+	return jwtKeys
+}
+
+// LoadJWTKeys lit la variable JWT_KEYS : entrées séparées par des virgules
+// de la forme `kid:source[@activation]`.
+//   - source : chemin d'un fichier PEM, ou `env:NOM_VARIABLE` (PEM dans la variable)
+//   - activation : date RFC 3339 à partir de laquelle la clé signe (rotation planifiée)
+//
+// Une clé publique seule (PEM "PUBLIC KEY") est une clé de vérification.
+// Toute entrée illisible est une erreur : l'API ne démarre pas sans ses clés.
+func LoadJWTKeys(spec string, grace time.Duration) (*KeySet, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, errors.New("JWT_KEYS is empty: at least one signing key is required")
+	}
+
+	var keys []*SigningKey
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kid, source, ok := strings.Cut(entry, ":")
+		if !ok || kid == "" || source == "" {
+			return nil, fmt.Errorf("invalid JWT_KEYS entry %q (expected kid:source[@activation])", entry)
+		}
+
+		var activeFrom time.Time
+		if at := strings.LastIndex(source, "@"); at >= 0 {
+			t, err := time.Parse(time.RFC3339, source[at+1:])
+			if err != nil {
+				return nil, fmt.Errorf("jwt key %q: invalid activation date: %w", kid, err)
+			}
+			activeFrom = t
+			source = source[:at]
+		}
+
+		pemBytes, err := readKeySource(source)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %q: %w", kid, err)
+		}
+
+		key, err := ParseSigningKey(kid, pemBytes)
+		if err != nil {
+			return nil, err
+		}
+		key.ActiveFrom = activeFrom
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(grace, keys...)
+}
+
+func readKeySource(source string) ([]byte, error) {
+	if name, ok := strings.CutPrefix(source, "env:"); ok {
+		value := os.Getenv(name)
+		if value == "" {
+			return nil, fmt.Errorf("environment variable %s is empty", name)
+		}
+		return []byte(value), nil
+	}
+	return os.ReadFile(source)
+}
+
+// ParseSigningKey décode une clé PEM RSA ou Ed25519 (privée PKCS#1/PKCS#8 ou
+// publique PKIX/PKCS#1) ; l'algorithme est déduit du type de clé
+func ParseSigningKey(kid string, pemBytes []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("jwt key %q: no PEM block found", kid)
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("jwt key %q: unsupported PEM type %q", kid, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwt key %q: %w", kid, err)
+	}
+
+	key := &SigningKey{KID: kid}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Algorithm, key.Private, key.Public = JWT_ALG_RS256, k, &k.PublicKey
+	case ed25519.PrivateKey:
+		key.Algorithm, key.Private, key.Public = JWT_ALG_EDDSA, k, k.Public()
+	case *rsa.PublicKey:
+		key.Algorithm, key.Public = JWT_ALG_RS256, k
+	case ed25519.PublicKey:
+		key.Algorithm, key.Public = JWT_ALG_EDDSA, k
+	default:
+		return nil, fmt.Errorf("jwt key %q: unsupported key type %T", kid, parsed)
+	}
+
+	if err := checkKeyAlgorithm(key); err != nil {
+		return nil, fmt.Errorf("jwt key %q: %w", kid, err)
+	}
+	return key, nil
+}
+
+// NewEphemeralKeySet génère une clé Ed25519 en mémoire : tests uniquement,
+// les tokens ne survivent pas au processus
+func NewEphemeralKeySet() (*KeySet, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeySet(REFRESH_TOKEN_TTL, &SigningKey{
+		KID:       "ephemeral-" + hex.EncodeToString(pub[:4]),
+		Algorithm: JWT_ALG_EDDSA,
+		Private:   priv,
+		Public:    pub,
+	})
+}
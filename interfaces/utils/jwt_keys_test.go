@@ -0,0 +1,228 @@
+package utils_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"Goshop/interfaces/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ed25519Key(t *testing.T, kid string, activeFrom time.Time) *utils.SigningKey {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return &utils.SigningKey{KID: kid, Algorithm: utils.JWT_ALG_EDDSA, Private: priv, Public: pub, ActiveFrom: activeFrom}
+}
+
+func privatePEM(t *testing.T, key any) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+// useKeys installe le trousseau global le temps du test
+func useKeys(t *testing.T, ks *utils.KeySet) {
+	t.Helper()
+	previous := utils.JWTKeys()
+	utils.InitJWTKeys(ks)
+	t.Cleanup(func() { utils.InitJWTKeys(previous) })
+}
+
+func TestJWT_SignAndValidate(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		key  *utils.SigningKey
+	}{
+		{"RS256", &utils.SigningKey{KID: "rsa-1", Algorithm: utils.JWT_ALG_RS256, Private: rsaKey, Public: &rsaKey.PublicKey}},
+		{"EdDSA", ed25519Key(t, "ed-1", time.Time{})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ks, err := utils.NewKeySet(time.Hour, tt.key)
+			require.NoError(t, err)
+			useKeys(t, ks)
+
+			token, err := utils.GenerateAccessToken("user-1", []string{"customer"}, nil)
+			require.NoError(t, err)
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.key.KID, parsed.Header["kid"])
+			assert.Equal(t, tt.name, parsed.Header["alg"])
+
+			claims, err := utils.ValidateToken(token)
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", claims["sub"])
+		})
+	}
+}
+
+func TestJWT_RejectsForeignAndUnsignedTokens(t *testing.T) {
+	ks, err := utils.NewKeySet(time.Hour, ed25519Key(t, "ed-1", time.Time{}))
+	require.NoError(t, err)
+
+	// Token signé par une autre clé portant le même kid
+	useKeys(t, mustKeySet(t, ed25519Key(t, "ed-1", time.Time{})))
+	foreign, err := utils.GenerateRefreshToken("user-1", "jti-1")
+	require.NoError(t, err)
+
+	hmac := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user-1"})
+	hmac.Header["kid"] = "ed-1"
+	hmacToken, err := hmac.SignedString([]byte(""))
+	require.NoError(t, err)
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"sub": "user-1"}).
+		SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	utils.InitJWTKeys(ks)
+	for name, token := range map[string]string{"autre clé": foreign, "HS256": hmacToken, "alg none": unsigned} {
+		_, err := utils.ValidateToken(token)
+		assert.Error(t, err, name)
+	}
+}
+
+func mustKeySet(t *testing.T, keys ...*utils.SigningKey) *utils.KeySet {
+	t.Helper()
+	ks, err := utils.NewKeySet(time.Hour, keys...)
+	require.NoError(t, err)
+	return ks
+}
+
+func TestKeySet_ScheduledRotationWithGrace(t *testing.T) {
+	now := time.Now()
+	rotation := now.Add(24 * time.Hour)
+	grace := 7 * 24 * time.Hour
+
+	ks, err := utils.NewKeySet(grace,
+		ed25519Key(t, "2026-10", now.Add(-time.Hour)),
+		ed25519Key(t, "2026-11", rotation),
+	)
+	require.NoError(t, err)
+
+	// Avant la rotation : l'ancienne clé signe, la suivante est déjà publiée
+	signing, err := ks.SigningKey(now)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10", signing.KID)
+	assert.Len(t, ks.JWKS(now).Keys, 2)
+
+	// Après la rotation : la nouvelle clé signe, l'ancienne vérifie encore
+	signing, err = ks.SigningKey(rotation.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, "2026-11", signing.KID)
+	_, err = ks.VerificationKey("2026-10", rotation.Add(grace-time.Minute))
+	assert.NoError(t, err)
+
+	// Fin de la période de grâce : l'ancienne clé est retirée
+	afterGrace := rotation.Add(grace + time.Minute)
+	_, err = ks.VerificationKey("2026-10", afterGrace)
+	assert.ErrorIs(t, err, utils.ErrJWTUnknownKey)
+	jwks := ks.JWKS(afterGrace)
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "2026-11", jwks.Keys[0].Kid)
+}
+
+func TestKeySet_Validation(t *testing.T) {
+	smallRSA, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	verifyOnly := ed25519Key(t, "old", time.Time{})
+	verifyOnly.Private = nil
+
+	tests := []struct {
+		name string
+		keys []*utils.SigningKey
+	}{
+		{"aucune clé", nil},
+		{"kid en double", []*utils.SigningKey{ed25519Key(t, "k", time.Time{}), ed25519Key(t, "k", time.Time{})}},
+		{"clé RSA trop courte", []*utils.SigningKey{{KID: "rsa", Algorithm: utils.JWT_ALG_RS256, Private: smallRSA, Public: &smallRSA.PublicKey}}},
+		{"algorithme incohérent", []*utils.SigningKey{{KID: "ed", Algorithm: utils.JWT_ALG_RS256, Public: verifyOnly.Public}}},
+		{"clé de vérification seule", []*utils.SigningKey{verifyOnly}},
+		{"seule clé pas encore active", []*utils.SigningKey{ed25519Key(t, "next", time.Now().Add(time.Hour))}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := utils.NewKeySet(time.Hour, tt.keys...)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadJWTKeys(t *testing.T) {
+	dir := t.TempDir()
+
+	_, current, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	currentPath := filepath.Join(dir, "current.pem")
+	require.NoError(t, os.WriteFile(currentPath, privatePEM(t, current), 0o600))
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	t.Setenv("GOSHOP_TEST_NEXT_KEY", string(privatePEM(t, rsaKey)))
+
+	// Clé d'une rotation précédente : publique seule
+	_, old, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(old.Public())
+	require.NoError(t, err)
+	oldPath := filepath.Join(dir, "old.pub.pem")
+	require.NoError(t, os.WriteFile(oldPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	rotation := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	spec := "old:" + oldPath + "@2020-01-01T00:00:00Z," +
+		"current:" + currentPath + "@2021-01-01T00:00:00Z, " +
+		"next:env:GOSHOP_TEST_NEXT_KEY@" + rotation.Format(time.RFC3339)
+
+	ks, err := utils.LoadJWTKeys(spec, time.Hour)
+	require.NoError(t, err)
+
+	signing, err := ks.SigningKey(time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "current", signing.KID)
+	assert.Equal(t, utils.JWT_ALG_EDDSA, signing.Algorithm)
+
+	next, err := ks.SigningKey(rotation)
+	require.NoError(t, err)
+	assert.Equal(t, "next", next.KID)
+	assert.Equal(t, utils.JWT_ALG_RS256, next.Algorithm)
+
+	// "old" est retirée depuis l'activation de "current" + 1h
+	kids := []string{}
+	for _, k := range ks.JWKS(time.Now()).Keys {
+		kids = append(kids, k.Kid)
+	}
+	assert.Equal(t, []string{"current", "next"}, kids)
+}
+
+func TestLoadJWTKeys_Errors(t *testing.T) {
+	tests := map[string]string{
+		"variable vide":      "",
+		"entrée sans source": "kid-only",
+		"fichier manquant":   "k:/nonexistent/key.pem",
+		"variable absente":   "k:env:GOSHOP_TEST_MISSING_KEY",
+		"date invalide":      "k:env:GOSHOP_TEST_MISSING_KEY@demain",
+	}
+
+	for name, spec := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := utils.LoadJWTKeys(spec, time.Hour)
+			assert.Error(t, err)
+		})
+	}
+}
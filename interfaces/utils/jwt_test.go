@@ -0,0 +1,148 @@
+package utils_test
+
+import (
+	"testing"
+	"time"
+
+	"Goshop/interfaces/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useJWTConfig applique la configuration le temps du test
+func useJWTConfig(t *testing.T, cfg utils.JWTConfig) {
+	t.Helper()
+	previous := utils.CurrentJWTConfig()
+	require.NoError(t, utils.InitJWTConfig(cfg))
+	t.Cleanup(func() { _ = utils.InitJWTConfig(previous) })
+}
+
+// signClaims signe des claims arbitraires avec la clé active du trousseau
+func signClaims(t *testing.T, ks *utils.KeySet, claims jwt.MapClaims) string {
+	t.Helper()
+	key, err := ks.SigningKey(time.Now())
+	require.NoError(t, err)
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = key.KID
+	signed, err := token.SignedString(key.Private)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestGenerateAccessToken_RegisteredClaims(t *testing.T) {
+	useKeys(t, mustKeySet(t, ed25519Key(t, "ed-1", time.Time{})))
+	useJWTConfig(t, utils.JWTConfig{
+		Issuer:     "https://auth.goshop.test",
+		Audience:   "goshop-orders",
+		AccessTTL:  5 * time.Minute,
+		RefreshTTL: time.Hour,
+		Leeway:     10 * time.Second,
+	})
+
+	token, err := utils.GenerateAccessToken("user-1", nil, nil)
+	require.NoError(t, err)
+
+	claims, err := utils.ValidateToken(token)
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://auth.goshop.test", claims["iss"])
+	assert.Equal(t, "goshop-orders", claims["aud"])
+	assert.Equal(t, claims["iat"], claims["nbf"])
+	assert.Equal(t, float64(5*60), claims["exp"].(float64)-claims["iat"].(float64))
+	assert.Equal(t, 5*time.Minute, utils.AccessTokenTTL())
+	assert.Equal(t, time.Hour, utils.RefreshTokenTTL())
+}
+
+func TestGenerateMFAToken(t *testing.T) {
+	useKeys(t, mustKeySet(t, ed25519Key(t, "ed-1", time.Time{})))
+
+	token, err := utils.GenerateMFAToken("user-1")
+	require.NoError(t, err)
+
+	claims, err := utils.ValidateToken(token)
+	require.NoError(t, err)
+
+	assert.Equal(t, "mfa_pending", claims["type"])
+	assert.Equal(t, "user-1", claims["sub"])
+	assert.Equal(t, utils.MFA_TOKEN_TTL.Seconds(), claims["exp"].(float64)-claims["iat"].(float64))
+	assert.NotContains(t, claims, utils.CLAIM_ROLES)
+}
+
+func TestValidateToken_StandardClaims(t *testing.T) {
+	ks := mustKeySet(t, ed25519Key(t, "ed-1", time.Time{}))
+	useKeys(t, ks)
+	useJWTConfig(t, utils.DefaultJWTConfig())
+
+	now := time.Now()
+	base := func(overrides jwt.MapClaims) jwt.MapClaims {
+		claims := jwt.MapClaims{
+			"iss": utils.DEFAULT_JWT_ISSUER,
+			"aud": utils.DEFAULT_JWT_AUD,
+			"sub": "user-1",
+			"iat": now.Unix(),
+			"nbf": now.Unix(),
+			"exp": now.Add(time.Minute).Unix(),
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(claims, k)
+				continue
+			}
+			claims[k] = v
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name    string
+		claims  jwt.MapClaims
+		wantErr error
+	}{
+		{"claims valides", base(nil), nil},
+		{"audience en liste", base(jwt.MapClaims{"aud": []string{"billing", utils.DEFAULT_JWT_AUD}}), nil},
+		{"expiré dans la tolérance", base(jwt.MapClaims{"exp": now.Add(-10 * time.Second).Unix()}), nil},
+		{"nbf dans la tolérance", base(jwt.MapClaims{"nbf": now.Add(10 * time.Second).Unix()}), nil},
+		{"expiré", base(jwt.MapClaims{"exp": now.Add(-time.Minute).Unix()}), jwt.ErrTokenExpired},
+		{"pas encore valide", base(jwt.MapClaims{"nbf": now.Add(time.Minute).Unix()}), jwt.ErrTokenNotValidYet},
+		{"émis dans le futur", base(jwt.MapClaims{"iat": now.Add(time.Minute).Unix()}), jwt.ErrTokenUsedBeforeIssued},
+		{"autre émetteur", base(jwt.MapClaims{"iss": "evil"}), jwt.ErrTokenInvalidIssuer},
+		{"autre audience", base(jwt.MapClaims{"aud": "billing"}), jwt.ErrTokenInvalidAudience},
+		{"sans audience", base(jwt.MapClaims{"aud": nil}), jwt.ErrTokenRequiredClaimMissing},
+		{"sans exp", base(jwt.MapClaims{"exp": nil}), jwt.ErrTokenRequiredClaimMissing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := utils.ValidateToken(signClaims(t, ks, tt.claims))
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestInitJWTConfig_Validation(t *testing.T) {
+	valid := utils.DefaultJWTConfig()
+
+	tests := map[string]func(cfg *utils.JWTConfig){
+		"sans émetteur":                  func(cfg *utils.JWTConfig) { cfg.Issuer = "" },
+		"sans audience":                  func(cfg *utils.JWTConfig) { cfg.Audience = "" },
+		"durée nulle":                    func(cfg *utils.JWTConfig) { cfg.AccessTTL = 0 },
+		"refresh plus court que l'accès": func(cfg *utils.JWTConfig) { cfg.RefreshTTL = cfg.AccessTTL },
+		"tolérance négative":             func(cfg *utils.JWTConfig) { cfg.Leeway = -time.Second },
+		"tolérance supérieure à l'accès": func(cfg *utils.JWTConfig) { cfg.Leeway = cfg.AccessTTL },
+	}
+
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := valid
+			mutate(&cfg)
+			assert.Error(t, utils.InitJWTConfig(cfg))
+			assert.Equal(t, valid, utils.CurrentJWTConfig())
+		})
+	}
+}
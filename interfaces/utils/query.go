@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+const INCLUDE_DELETED_PARAM = "include_deleted"
+
+// ParseIncludeDeleted lit ?include_deleted=true (lignes archivées incluses).
+// Absent : false ; une valeur qui n'est pas un booléen est une erreur, à
+// renvoyer en 400 plutôt que d'être ignorée.
+func ParseIncludeDeleted(r *http.Request) (bool, error) {
+	raw := r.URL.Query().Get(INCLUDE_DELETED_PARAM)
+	if raw == "" {
+		return false, nil
+	}
+	includeDeleted, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, errors.New("include_deleted must be a boolean")
+	}
+	return includeDeleted, nil
+}
@@ -2,15 +2,16 @@ package utils
 
 import (
 	"context"
-	"os"
 
 	"github.com/redis/go-redis/v9"
 )
 
 var Rdb *redis.Client
 
-func InitRedis() error {
-	addr := os.Getenv("REDIS_ADDR")
+// InitRedis crée le client et vérifie la connexion. Le client est conservé
+// même si Redis ne répond pas encore : les appelants se replient sur
+// Postgres ou la mémoire tant qu'il est indisponible.
+func InitRedis(addr string) error {
 	if addr == "" {
 		addr = "localhost:6379"
 	}
@@ -16,3 +16,6 @@ func WriteError(w http.ResponseWriter, status int, message string) {
 		"error": message,
 	})
 }
+
+// TOTAL_COUNT_HEADER porte le nombre total d'éléments d'une liste paginée par offset
+const TOTAL_COUNT_HEADER = "X-Total-Count"
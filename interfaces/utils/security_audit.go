@@ -0,0 +1,31 @@
+// interfaces/utils/security_audit.go
+package utils
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+// Événements d'audit sécurité (champ "security_event")
+const (
+	AUDIT_REFRESH_TOKEN_REUSE = "refresh_token_reuse"
+	AUDIT_ACCOUNT_LOCKED      = "account_locked"
+	AUDIT_LOGIN_IP_BLOCKED    = "login_ip_blocked"
+	AUDIT_ACCOUNT_UNLOCKED    = "account_unlocked"
+	AUDIT_PASSWORD_RESET      = "password_reset"
+	AUDIT_PASSWORD_CHANGED    = "password_changed"
+	AUDIT_ACCOUNT_DELETED     = "account_deleted"
+	AUDIT_MFA_ENABLED         = "mfa_enabled"
+	AUDIT_MFA_RECOVERY_USED   = "mfa_recovery_code_used"
+	AUDIT_API_KEY_CREATED     = "api_key_created"
+	AUDIT_API_KEY_REVOKED     = "api_key_revoked"
+)
+
+// SecurityAudit ouvre un log d'audit sécurité. Les événements portent
+// audit=security pour être filtrés et alertés à part des logs applicatifs.
+func SecurityAudit(ctx context.Context, event string) *zerolog.Event {
+	return zerolog.Ctx(ctx).Warn().
+		Str("audit", "security").
+		Str("security_event", event)
+}
@@ -5,6 +5,8 @@ package app
 import (
 	"database/sql"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -14,21 +16,46 @@ import (
 
 	"Goshop/application/metrics"
 	authusecase "Goshop/application/usecase/auth_usecase"
+	categoryusecase "Goshop/application/usecase/category_usecase"
+	productuscase "Goshop/application/usecase/product_uscase"
+	userusecase "Goshop/application/usecase/user_usecase"
+	userentity "Goshop/domain/entity/user_entity"
+	"Goshop/domain/mailer"
+	"Goshop/domain/storage"
+	mailerinfra "Goshop/infrastructure/mailer"
+	apikey "Goshop/infrastructure/postgres/api_key"
 	authrefreshrepositoryinfra "Goshop/infrastructure/postgres/auth_refresh_repository_infra"
+	"Goshop/infrastructure/postgres/category"
 	"Goshop/infrastructure/postgres/customer"
+	"Goshop/infrastructure/postgres/idempotency"
+	loginattempt "Goshop/infrastructure/postgres/login_attempt"
+	"Goshop/infrastructure/postgres/mfa"
 	"Goshop/infrastructure/postgres/order"
 	"Goshop/infrastructure/postgres/product"
+	productimage "Goshop/infrastructure/postgres/product_image"
+	productvariant "Goshop/infrastructure/postgres/product_variant"
 	txmanager "Goshop/infrastructure/postgres/tx_manager"
 	userpostgres "Goshop/infrastructure/postgres/user_postgres"
+	usertoken "Goshop/infrastructure/postgres/user_token"
+	redisinfra "Goshop/infrastructure/redis"
+	storageinfra "Goshop/infrastructure/storage"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	handlers "Goshop/interfaces/handler"
+	accounthandler "Goshop/interfaces/handler/account_handler"
+	adminhandler "Goshop/interfaces/handler/admin_handler"
+	apikeyhandler "Goshop/interfaces/handler/api_key_handler"
+	categoryhandler "Goshop/interfaces/handler/category_handler"
 	customerhandler "Goshop/interfaces/handler/customer_handler"
+	imagehandler "Goshop/interfaces/handler/image_handler"
+	jwkshandler "Goshop/interfaces/handler/jwks_handler"
+	mfahandler "Goshop/interfaces/handler/mfa_handler"
 	"Goshop/interfaces/handler/orders"
 	productHandler "Goshop/interfaces/handler/product"
 	refreshhandler "Goshop/interfaces/handler/refresh_handler"
 	userhandler "Goshop/interfaces/handler/user_handler"
+	varianthandler "Goshop/interfaces/handler/variant_handler"
 	middleware "Goshop/interfaces/middl/user_middleware"
 
 	"Goshop/config/setupLogging"
@@ -45,14 +72,47 @@ type App struct {
 	Router *chi.Mux
 	DB     *sql.DB
 	Logger *setupLogging.Logger
+	Mail   MailConfig
+	Media  MediaConfig
 }
 
+// MailConfig : envoi des emails transactionnels (réinitialisation du mot de
+// passe, vérification de l'email)
+type MailConfig struct {
+	Mailer    mailer.Mailer // nil : emails seulement logués
+	PublicURL string        // base des liens envoyés ("https://shop.example.com")
+}
+
+// MediaConfig : stockage du contenu des images produit
+type MediaConfig struct {
+	Blobs         storage.BlobStore // nil : fichiers locaux sous DefaultBlobDir
+	MaxImageBytes int64             // 0 : entity.DefaultMaxImageBytes
+}
+
+// DefaultBlobDir : répertoire des images quand aucun stockage n'est configuré
+const DefaultBlobDir = "tmp/blobs"
+
 // NewApp crée une nouvelle instance de l'application avec logging
-func NewApp(db *sql.DB, logger *setupLogging.Logger) *App {
+func NewApp(db *sql.DB, logger *setupLogging.Logger, mail MailConfig, media MediaConfig) *App {
 	metrics.RegisterMetrics()
+	if mail.Mailer == nil {
+		mail.Mailer = mailerinfra.NewLogMailer()
+	}
+	if mail.PublicURL == "" {
+		mail.PublicURL = "http://localhost:8080"
+	}
+	if media.Blobs == nil {
+		blobs, err := storageinfra.NewLocalBlobStore(DefaultBlobDir)
+		if err != nil {
+			logger.Fatal().Err(err).Str("dir", DefaultBlobDir).Msg("Failed to create blob directory")
+		}
+		media.Blobs = blobs
+	}
 	app := &App{
 		DB:     db,
 		Logger: logger.WithComponent("app"),
+		Mail:   mail,
+		Media:  media,
 	}
 	app.setupRouter()
 	return app
@@ -75,6 +135,7 @@ func (a *App) setupRouter() {
 	r.Use(middl.RequestLoggerMiddleware) // ← 4ème: logue la requête
 	r.Use(middl.Recovery)
 	r.Use(middl.SecureHeaders)
+	r.Use(middl.ClientInfo) // User-Agent / IP enregistrés avec les sessions de refresh
 
 	// ============ 2. INITIALISATION ============
 	hh := handlers.HealthHandler{
@@ -86,31 +147,77 @@ func (a *App) setupRouter() {
 	// -- Repositories
 	txmanagerRepo := txmanager.NewTxManagerPostgresInfra(a.DB)
 	postgreProductRepo := product.NewProductRepositoryInfrastructure(a.DB)
+	categoryRepo := category.NewCategoryPostgres(a.DB)
+	variantRepo := productvariant.NewProductVariantPostgres(a.DB)
+	imageRepo := productimage.NewProductImagePostgres(a.DB)
 	postgresCustomerRepo := customer.NewCustomerRepoInfrastructurePostgres(a.DB)
 	postgresOrderRepo := order.NewOrderPostgresInfra(a.DB)
 	postgresOrderItem := order.NewOrderItemPostgresInfra(a.DB)
 	postgresUserRepo := userpostgres.NewUserPostgres(a.DB)
 	refreshSessionRepo := authrefreshrepositoryinfra.NewRefreshSessionPostgres(a.DB)
+	idempotencyRepo := idempotency.NewIdempotencyPostgres(a.DB)
+	// Échecs de login : Redis (partagé entre replicas), Postgres en repli
+	loginAttemptRepo := redisinfra.NewLoginAttemptRedis(utils.Rdb, loginattempt.NewLoginAttemptPostgres(a.DB))
+	userTokenRepo := usertoken.NewUserTokenPostgres(a.DB)
+	mfaRepo := mfa.NewMFAPostgres(a.DB)
+	apiKeyRepo := apikey.NewAPIKeyPostgres(a.DB)
+	// Limites par clé d'API : compteurs Redis partagés, compteurs locaux en repli
+	rateCounter := redisinfra.NewRateCounterRedis(utils.Rdb)
+	// Cache des fiches produit (nil sans Redis : lecture directe en base)
+	productCache := redisinfra.NewProductCacheRedis(utils.Rdb)
 
 	// -- Usecases
 	refreshUsecase := authusecase.NewRefreshUsecase(
 		refreshSessionRepo,
+		postgresUserRepo,
 		utils.ValidateToken,
 		utils.GenerateAccessToken,
 		utils.GenerateRefreshToken,
 		time.Now,
 		uuid.NewString,
-		30*24*time.Hour,
+		utils.RefreshTokenTTL(),
+	)
+	sessionUsecase := authusecase.NewSessionUsecase(refreshSessionRepo, utils.ValidateToken)
+	loginGuard := authusecase.NewLoginGuard(loginAttemptRepo, authusecase.DefaultLoginGuardConfig(), time.Now)
+	mfaUsecase := authusecase.NewMFAUsecase(postgresUserRepo, mfaRepo, time.Now)
+	apiKeyUsecase := authusecase.NewAPIKeyUsecase(apiKeyRepo, rateCounter, time.Now)
+	passwordResetUsecase := authusecase.NewPasswordResetUsecase(
+		postgresUserRepo,
+		userTokenRepo,
+		refreshSessionRepo,
+		a.Mail.Mailer,
+		a.Mail.PublicURL,
+		time.Now,
+	)
+	emailVerificationUsecase := authusecase.NewEmailVerificationUsecase(
+		postgresUserRepo,
+		userTokenRepo,
+		a.Mail.Mailer,
+		a.Mail.PublicURL,
+		time.Now,
 	)
 
 	// -- Handlers
 	refreshHandler := refreshhandler.NewRefreshHandler(
 		refreshUsecase,
 	)
+	sessionHandler := refreshhandler.NewSessionHandler(sessionUsecase)
 
 	productHandler := productHandler.NewProductHandler(
 		postgreProductRepo,
 		txmanagerRepo,
+	).WithProductCache(productCache).WithCategories(categoryRepo).WithVariants(variantRepo).WithImages(imageRepo, a.Media.Blobs)
+
+	variantHandler := varianthandler.NewVariantHandler(
+		productuscase.NewVariantUsecase(postgreProductRepo, variantRepo, txmanagerRepo).WithCache(productCache),
+	)
+
+	imageHandler := imagehandler.NewImageHandler(
+		productuscase.NewImageUsecase(postgreProductRepo, imageRepo, a.Media.Blobs, txmanagerRepo, a.Media.MaxImageBytes),
+	)
+
+	categoryHandler := categoryhandler.NewCategoryHandler(
+		categoryusecase.NewCategoryUsecase(categoryRepo, txmanagerRepo),
 	)
 
 	customerHandler := customerhandler.NewCustomerHandler(
@@ -125,12 +232,29 @@ func (a *App) setupRouter() {
 		postgreProductRepo,
 		postgresCustomerRepo,
 		postgresOrderItem,
-	)
+	).WithProductCache(productCache).WithVariants(variantRepo)
 
 	userHandler := userhandler.NewUserHandler(
 		postgresUserRepo,
+		refreshSessionRepo,
+		loginGuard,
+		mfaUsecase,
+		emailVerificationUsecase,
 		a.Logger.WithComponent("user_handler"),
 	)
+	accountHandler := accounthandler.NewAccountHandler(
+		passwordResetUsecase,
+		emailVerificationUsecase,
+		authusecase.NewChangePasswordUsecase(postgresUserRepo, userTokenRepo, refreshSessionRepo, utils.ValidateToken),
+		userusecase.NewDeleteAccountUsecase(postgresUserRepo),
+	)
+
+	mfaHandler := mfahandler.NewMFAHandler(mfaUsecase)
+
+	adminHandler := adminhandler.NewAdminHandler(postgresUserRepo, loginGuard)
+	apiKeyHandler := apikeyhandler.NewAPIKeyHandler(apiKeyUsecase)
+
+	jwksHandler := jwkshandler.NewJWKSHandler(utils.JWTKeys())
 
 	// ============ 3. ROUTES PUBLIQUES ============
 	r.Use(middl.PrometheusMiddleware)
@@ -139,9 +263,15 @@ func (a *App) setupRouter() {
 	r.Get("/health/live", hh.Live)
 	r.Get("/health/ready", hh.Ready)
 
+	r.Get("/.well-known/jwks.json", middl.ErrorHandler(jwksHandler.JWKS))
+
 	r.Post("/auth/refresh", middl.ErrorHandler(refreshHandler.Refresh))
 	r.Post("/register", middl.ErrorHandler(userHandler.Register))
 	r.Post("/login", middl.ErrorHandler(userHandler.Login))
+	r.Post("/login/mfa", middl.ErrorHandler(userHandler.LoginMFA))
+	r.Post("/auth/password/forgot", middl.ErrorHandler(accountHandler.ForgotPassword))
+	r.Post("/auth/password/reset", middl.ErrorHandler(accountHandler.ResetPassword))
+	r.Post("/auth/email/verify", middl.ErrorHandler(accountHandler.VerifyEmail))
 
 	r.Get("/help", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("Goshop API est en ligne !"))
@@ -151,36 +281,109 @@ func (a *App) setupRouter() {
 	r.Get("/swagger/*", httpSwagger.Handler())
 
 	// ============ 4. ROUTE PROTÉGÉE ============
-	r.With(middleware.AuthMiddleware).
-		Get("/auth/me", middl.ErrorHandler(userHandler.Me))
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+		r.Get("/auth/me", middl.ErrorHandler(userHandler.Me))
+		r.Put("/auth/me/password", middl.ErrorHandler(accountHandler.ChangePassword))
+		r.Delete("/auth/me", middl.ErrorHandler(accountHandler.DeleteAccount))
+		r.Post("/auth/me/mfa", middl.ErrorHandler(mfaHandler.Enroll))
+		r.Post("/auth/me/mfa/confirm", middl.ErrorHandler(mfaHandler.Confirm))
+		r.Post("/auth/logout", middl.ErrorHandler(sessionHandler.Logout))
+		r.Post("/auth/logout-all", middl.ErrorHandler(sessionHandler.LogoutAll))
+		r.Get("/auth/sessions", middl.ErrorHandler(sessionHandler.ListSessions))
+		r.Delete("/auth/sessions/{id}", middl.ErrorHandler(sessionHandler.RevokeSession))
+	})
 
 	// ============ 5. ROUTES API PROTÉGÉES ============
+	// JWT ou clé d'API (clients machine, droits limités à leurs scopes)
 	r.Route("/api", func(r chi.Router) {
-		r.Use(middleware.AuthMiddleware)
+		r.Use(middleware.NewAuthMiddleware(middleware.AuthMiddlewareConfig{APIKeys: apiKeyUsecase}))
+		r.Use(middl.Idempotency(idempotencyRepo)) // après l'auth : clés propres à chaque utilisateur
+		r.Use(middl.CurrentCustomer(postgresCustomerRepo))
 
-		// Products
+		// Products : lecture pour tout utilisateur connecté, écriture réservée au back-office
 		r.Route("/products", func(r chi.Router) {
-			r.Post("/", middl.ErrorHandler(productHandler.CreateProduct))
 			r.Get("/", middl.ErrorHandler(productHandler.GetAllProducts))
 			r.Get("/{id}", middl.ErrorHandler(productHandler.GetProductById))
-			r.Put("/{id}", middl.ErrorHandler(productHandler.UpdateProduct))
-			r.Delete("/{id}", middl.ErrorHandler(productHandler.DeleteProduct))
+			r.Get("/{id}/variants", middl.ErrorHandler(variantHandler.List))
+			r.Get("/{id}/images", middl.ErrorHandler(imageHandler.List))
+			r.Get("/{id}/images/{imageId}", middl.ErrorHandler(imageHandler.Content))
+
+			r.Group(func(r chi.Router) {
+				r.Use(middl.RequirePermissions(userentity.PermProductsWrite))
+				r.Post("/", middl.ErrorHandler(productHandler.CreateProduct))
+				r.Put("/{id}", middl.ErrorHandler(productHandler.UpdateProduct))
+				r.Delete("/{id}", middl.ErrorHandler(productHandler.DeleteProduct))
+				r.Post("/{id}/restore", middl.ErrorHandler(productHandler.RestoreProduct))
+				r.Delete("/{id}/purge", middl.ErrorHandler(productHandler.PurgeProduct))
+				r.Post("/{id}/variants", middl.ErrorHandler(variantHandler.Create))
+				r.Put("/{id}/variants/{variantId}", middl.ErrorHandler(variantHandler.Update))
+				r.Delete("/{id}/variants/{variantId}", middl.ErrorHandler(variantHandler.Delete))
+				r.Post("/{id}/images", middl.ErrorHandler(imageHandler.Upload))
+				r.Put("/{id}/images/order", middl.ErrorHandler(imageHandler.Reorder))
+				r.Delete("/{id}/images/{imageId}", middl.ErrorHandler(imageHandler.Delete))
+			})
+		})
+
+		// Catégories : navigation pour tout utilisateur connecté, arbre géré
+		// par le back-office comme le reste du catalogue
+		r.Route("/categories", func(r chi.Router) {
+			r.Get("/", middl.ErrorHandler(categoryHandler.List))
+			r.Get("/{slug}", middl.ErrorHandler(categoryHandler.Get))
+			r.Get("/{slug}/products", middl.ErrorHandler(productHandler.GetProductsByCategory))
+
+			r.Group(func(r chi.Router) {
+				r.Use(middl.RequirePermissions(userentity.PermProductsWrite))
+				r.Post("/", middl.ErrorHandler(categoryHandler.Create))
+				r.Put("/{slug}", middl.ErrorHandler(categoryHandler.Update))
+				r.Delete("/{slug}", middl.ErrorHandler(categoryHandler.Delete))
+			})
 		})
 
-		// Customers
+		// Customers : un client sans permission ":any" ne voit et ne modifie que
+		// son propre profil (contrôle dans les usecases)
 		r.Route("/customers", func(r chi.Router) {
-			r.Post("/", middl.ErrorHandler(customerHandler.CreateCustomerHandler))
-			r.Get("/", middl.ErrorHandler(customerHandler.GetAllCustomersHandler))
+			r.With(middl.RequirePermissions(userentity.PermCustomersWrite)).
+				Post("/", middl.ErrorHandler(customerHandler.CreateCustomerHandler))
+			r.With(middl.RequirePermissions(userentity.PermCustomersReadAny)).
+				Get("/", middl.ErrorHandler(customerHandler.GetAllCustomersHandler))
 			r.Get("/{id}", middl.ErrorHandler(customerHandler.GetCustomerByIdHandler))
 			r.Put("/{id}", middl.ErrorHandler(customerHandler.UpdateCustomerHandler))
-			r.Delete("/{id}", middl.ErrorHandler(customerHandler.DeleteCustomerHandler))
+			r.With(middl.RequirePermissions(userentity.PermCustomersWrite)).
+				Delete("/{id}", middl.ErrorHandler(customerHandler.DeleteCustomerHandler))
+			r.With(middl.RequirePermissions(userentity.PermCustomersWrite)).
+				Post("/{id}/restore", middl.ErrorHandler(customerHandler.RestoreCustomerHandler))
 		})
 
-		// Orders
+		// Orders : sans orders:read:any, la liste est limitée aux commandes du client courant
 		r.Route("/orders", func(r chi.Router) {
 			r.Get("/", middl.ErrorHandler(orderHandler.GetAllOrderHandler))
-			r.Post("/", middl.ErrorHandler(orderHandler.CreateOrderHandler))
+			r.With(middl.RequirePermissions(userentity.PermOrdersWrite)).
+				Post("/", middl.ErrorHandler(orderHandler.CreateOrderHandler))
 			r.Get("/{id}", middl.ErrorHandler(orderHandler.GetOrderByIdHandler))
+			r.With(middl.RequirePermissions(userentity.PermOrdersWriteAny)).
+				Patch("/{id}/status", middl.ErrorHandler(orderHandler.UpdateOrderStatusHandler))
+			r.With(middl.RequirePermissions(userentity.PermOrdersWrite)).
+				Post("/{id}/cancel", middl.ErrorHandler(orderHandler.CancelOrderHandler))
+			r.Get("/{id}/history", middl.ErrorHandler(orderHandler.GetOrderStatusHistoryHandler))
+		})
+
+		// Espace personnel de l'utilisateur connecté
+		r.Get("/me/orders", middl.ErrorHandler(orderHandler.GetMyOrdersHandler))
+
+		// Administration
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(middl.RequireRoles(userentity.RoleAdmin))
+			r.Use(middl.RequirePermissions(userentity.PermUsersRolesWrite))
+
+			r.Get("/users/{id}/roles", middl.ErrorHandler(adminHandler.GetUserRoles))
+			r.Post("/users/{id}/roles", middl.ErrorHandler(adminHandler.GrantRole))
+			r.Delete("/users/{id}/roles/{role}", middl.ErrorHandler(adminHandler.RevokeRole))
+			r.Post("/users/{id}/unlock", middl.ErrorHandler(adminHandler.UnlockAccount))
+
+			r.Post("/api-keys", middl.ErrorHandler(apiKeyHandler.Create))
+			r.Get("/api-keys", middl.ErrorHandler(apiKeyHandler.List))
+			r.Delete("/api-keys/{id}", middl.ErrorHandler(apiKeyHandler.Revoke))
 		})
 	})
 
@@ -286,6 +489,11 @@ func NewRouter(db *sql.DB) http.Handler {
 	}
 	logger := setupLogging.NewLogger(loggingConfig)
 
-	app := NewApp(db, logger)
+	blobs, err := storageinfra.NewLocalBlobStore(filepath.Join(os.TempDir(), "goshop-test-blobs"))
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to create test blob directory")
+	}
+
+	app := NewApp(db, logger, MailConfig{}, MediaConfig{Blobs: blobs})
 	return app.Handler()
 }
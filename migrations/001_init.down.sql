@@ -0,0 +1,8 @@
+-- migrations/001_init.down.sql
+
+DROP TABLE IF EXISTS order_items;
+DROP TABLE IF EXISTS orders;
+DROP TABLE IF EXISTS products;
+DROP TABLE IF EXISTS customers;
+DROP TABLE IF EXISTS refresh_sessions;
+DROP TABLE IF EXISTS users;
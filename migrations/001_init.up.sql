@@ -1,4 +1,4 @@
--- migrations/001_init.sql
+-- migrations/001_init.up.sql
 
 -- Table users (comme dans ton \d)
 CREATE TABLE IF NOT EXISTS users (
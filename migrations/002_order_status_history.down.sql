@@ -0,0 +1,6 @@
+-- migrations/002_order_status_history.down.sql
+
+-- Les statuts restent en majuscules : la version précédente les acceptait aussi
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+
+DROP TABLE IF EXISTS order_status_history;
@@ -0,0 +1,29 @@
+-- migrations/002_order_status_history.up.sql
+
+-- Historique des changements de statut des commandes (qui, quand, pourquoi)
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    from_status VARCHAR(50) NOT NULL,
+    to_status VARCHAR(50) NOT NULL,
+    changed_by VARCHAR(255) NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_status_history_order_id
+    ON order_status_history(order_id, created_at);
+
+-- Les statuts possibles sont ceux de la machine à états (domain/entity/order_status.go)
+UPDATE orders SET status = UPPER(status) WHERE status <> UPPER(status);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'orders_status_check'
+    ) THEN
+        ALTER TABLE orders ADD CONSTRAINT orders_status_check CHECK (
+            status IN ('PENDING', 'PAID', 'FULFILLED', 'SHIPPED', 'DELIVERED', 'CANCELLED', 'REFUNDED')
+        );
+    END IF;
+END $$;
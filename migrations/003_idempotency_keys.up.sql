@@ -0,0 +1,22 @@
+-- migrations/003_idempotency_keys.up.sql
+
+-- Réponses mémorisées des requêtes envoyées avec un header Idempotency-Key
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    scope VARCHAR(255) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    path TEXT NOT NULL,
+    fingerprint CHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'IN_PROGRESS'
+        CHECK (status IN ('IN_PROGRESS', 'COMPLETED')),
+    response_status INTEGER,
+    response_content_type VARCHAR(255) NOT NULL DEFAULT '',
+    response_body BYTEA,
+    locked_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (scope, idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at
+    ON idempotency_keys(expires_at);
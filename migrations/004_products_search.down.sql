@@ -0,0 +1,6 @@
+-- migrations/004_products_search.down.sql
+
+DROP INDEX IF EXISTS idx_products_price_cents;
+DROP INDEX IF EXISTS idx_products_search_vector;
+
+ALTER TABLE products DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,17 @@
+-- migrations/004_products_search.up.sql
+
+-- Recherche plein texte du catalogue : le nom pèse plus que la description.
+-- Configuration 'simple' : le catalogue mélange français et anglais, pas de
+-- racinisation propre à une langue.
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_products_search_vector
+    ON products USING GIN (search_vector);
+
+-- Filtre et tri par prix (created_at et stock sont déjà indexés)
+CREATE INDEX IF NOT EXISTS idx_products_price_cents ON products(price_cents);
@@ -0,0 +1,10 @@
+-- migrations/005_keyset_pagination_indexes.down.sql
+
+DROP INDEX IF EXISTS idx_order_items_order_id;
+DROP INDEX IF EXISTS idx_orders_status_created_at_id;
+DROP INDEX IF EXISTS idx_orders_customer_created_at_id;
+DROP INDEX IF EXISTS idx_orders_created_at_id;
+DROP INDEX IF EXISTS idx_customers_created_at_id;
+
+CREATE INDEX IF NOT EXISTS idx_products_created_at_desc ON products(created_at DESC);
+DROP INDEX IF EXISTS idx_products_created_at_id;
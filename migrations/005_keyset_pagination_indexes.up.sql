@@ -0,0 +1,16 @@
+-- migrations/005_keyset_pagination_indexes.up.sql
+
+-- Pagination par curseur : (created_at, id) en index composite pour que
+-- "WHERE (created_at, id) < (...) ORDER BY created_at DESC, id DESC LIMIT n"
+-- soit un simple parcours d'index, quelle que soit la profondeur de la page.
+CREATE INDEX IF NOT EXISTS idx_products_created_at_id ON products(created_at DESC, id DESC);
+DROP INDEX IF EXISTS idx_products_created_at_desc;
+
+CREATE INDEX IF NOT EXISTS idx_customers_created_at_id ON customers(created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_orders_created_at_id ON orders(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_orders_customer_created_at_id ON orders(customer_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_orders_status_created_at_id ON orders(status, created_at DESC, id DESC);
+
+-- Chargement des lignes d'une page de commandes
+CREATE INDEX IF NOT EXISTS idx_order_items_order_id ON order_items(order_id);
@@ -0,0 +1,67 @@
+-- migrations/006_rbac.up.sql
+
+-- Rôles et permissions : les permissions sont attachées aux rôles,
+-- les rôles aux utilisateurs. Les access tokens embarquent les deux.
+CREATE TABLE IF NOT EXISTS roles (
+    name VARCHAR(50) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    name VARCHAR(100) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR(50) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL REFERENCES permissions(name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(50) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    granted_by VARCHAR(36),
+    granted_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, role)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_roles_role ON user_roles(role);
+
+INSERT INTO roles (name, description) VALUES
+    ('admin', 'Accès complet, gestion des rôles'),
+    ('manager', 'Gestion du catalogue, des clients et des commandes'),
+    ('customer', 'Passe et consulte ses propres commandes')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO permissions (name, description) VALUES
+    ('products:write', 'Créer, modifier et supprimer des produits'),
+    ('customers:read:any', 'Consulter tous les clients'),
+    ('customers:write', 'Créer, modifier et supprimer des clients'),
+    ('orders:read:any', 'Consulter toutes les commandes'),
+    ('orders:write', 'Passer et annuler des commandes'),
+    ('orders:write:any', 'Changer le statut de n''importe quelle commande'),
+    ('users:roles:write', 'Attribuer et retirer des rôles')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'products:write'),
+    ('admin', 'customers:read:any'),
+    ('admin', 'customers:write'),
+    ('admin', 'orders:read:any'),
+    ('admin', 'orders:write'),
+    ('admin', 'orders:write:any'),
+    ('admin', 'users:roles:write'),
+    ('manager', 'products:write'),
+    ('manager', 'customers:read:any'),
+    ('manager', 'customers:write'),
+    ('manager', 'orders:read:any'),
+    ('manager', 'orders:write'),
+    ('manager', 'orders:write:any'),
+    ('customer', 'orders:write')
+ON CONFLICT (role, permission) DO NOTHING;
+
+-- Les comptes existants deviennent des clients
+INSERT INTO user_roles (user_id, role)
+SELECT id, 'customer' FROM users
+ON CONFLICT (user_id, role) DO NOTHING;
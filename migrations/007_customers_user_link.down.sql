@@ -0,0 +1,4 @@
+-- migrations/007_customers_user_link.down.sql
+
+DROP INDEX IF EXISTS idx_customers_user_id;
+ALTER TABLE customers DROP COLUMN IF EXISTS user_id;
@@ -0,0 +1,16 @@
+-- migrations/007_customers_user_link.up.sql
+
+-- Chaque compte utilisateur possède au plus un profil client
+ALTER TABLE customers
+    ADD COLUMN IF NOT EXISTS user_id VARCHAR(36) REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_user_id
+    ON customers(user_id) WHERE user_id IS NOT NULL;
+
+-- Rattache les clients existants au compte de même email
+UPDATE customers c
+SET user_id = u.id
+FROM users u
+WHERE c.user_id IS NULL
+  AND LOWER(c.email) = LOWER(u.email)
+  AND NOT EXISTS (SELECT 1 FROM customers other WHERE other.user_id = u.id);
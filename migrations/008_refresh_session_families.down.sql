@@ -0,0 +1,7 @@
+-- migrations/008_refresh_session_families.down.sql
+
+DROP INDEX IF EXISTS idx_refresh_sessions_family_id;
+ALTER TABLE refresh_sessions
+    DROP COLUMN IF EXISTS replaced_by,
+    DROP COLUMN IF EXISTS parent_id,
+    DROP COLUMN IF EXISTS family_id;
@@ -0,0 +1,16 @@
+-- migrations/008_refresh_session_families.up.sql
+
+-- Famille de sessions : toutes les sessions issues d'un même login partagent
+-- le family_id (JTI de la session racine). parent_id / replaced_by tracent la
+-- chaîne de rotation pour détecter le rejeu d'un token déjà tourné.
+ALTER TABLE refresh_sessions
+    ADD COLUMN IF NOT EXISTS family_id TEXT,
+    ADD COLUMN IF NOT EXISTS parent_id TEXT,
+    ADD COLUMN IF NOT EXISTS replaced_by TEXT;
+
+-- Les sessions existantes deviennent chacune la racine de leur famille
+UPDATE refresh_sessions SET family_id = id WHERE family_id IS NULL;
+
+ALTER TABLE refresh_sessions ALTER COLUMN family_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_sessions_family_id ON refresh_sessions(family_id);
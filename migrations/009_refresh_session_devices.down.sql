@@ -0,0 +1,6 @@
+-- migrations/009_refresh_session_devices.down.sql
+
+ALTER TABLE refresh_sessions
+    DROP COLUMN IF EXISTS last_used_at,
+    DROP COLUMN IF EXISTS ip_address,
+    DROP COLUMN IF EXISTS user_agent;
@@ -0,0 +1,20 @@
+-- migrations/009_refresh_session_devices.up.sql
+
+-- Appareil à l'origine de la session, capturé au login et à chaque refresh
+ALTER TABLE refresh_sessions
+    ADD COLUMN IF NOT EXISTS user_agent TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS ip_address TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMPTZ;
+
+UPDATE refresh_sessions SET last_used_at = created_at WHERE last_used_at IS NULL;
+
+-- created_at devient la date du login de la famille (conservée à la rotation)
+UPDATE refresh_sessions s
+SET created_at = root.created_at
+FROM refresh_sessions root
+WHERE root.id = s.family_id
+  AND s.id <> s.family_id;
+
+ALTER TABLE refresh_sessions
+    ALTER COLUMN last_used_at SET NOT NULL,
+    ALTER COLUMN last_used_at SET DEFAULT NOW();
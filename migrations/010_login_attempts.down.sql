@@ -0,0 +1,3 @@
+-- migrations/010_login_attempts.down.sql
+
+DROP TABLE IF EXISTS login_attempts;
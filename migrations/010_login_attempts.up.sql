@@ -0,0 +1,12 @@
+-- migrations/010_login_attempts.up.sql
+
+-- Échecs de connexion par compte ou par IP (repli quand Redis est indisponible)
+CREATE TABLE IF NOT EXISTS login_attempts (
+    key             TEXT PRIMARY KEY,
+    failures        INTEGER NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until    TIMESTAMPTZ,
+    expires_at      TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_expires_at ON login_attempts (expires_at);
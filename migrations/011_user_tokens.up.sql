@@ -0,0 +1,17 @@
+-- migrations/011_user_tokens.up.sql
+
+-- Date de confirmation de l'email (NULL : jamais confirmé)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+
+-- Jetons à usage unique envoyés par email (réinitialisation du mot de passe,
+-- vérification de l'email). Seul le hash SHA-256 du jeton est stocké.
+CREATE TABLE IF NOT EXISTS user_tokens (
+    token_hash CHAR(64) PRIMARY KEY,
+    user_id    VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose    TEXT NOT NULL CHECK (purpose IN ('password_reset', 'email_verification')),
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_tokens_user_purpose ON user_tokens (user_id, purpose);
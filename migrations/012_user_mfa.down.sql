@@ -0,0 +1,4 @@
+-- migrations/012_user_mfa.down.sql
+
+DROP TABLE IF EXISTS mfa_recovery_codes;
+DROP TABLE IF EXISTS user_mfa;
@@ -0,0 +1,23 @@
+-- migrations/012_user_mfa.up.sql
+
+-- Second facteur TOTP (RFC 6238). Le secret est créé à l'enrôlement ;
+-- enabled_at reste NULL tant que l'utilisateur n'a pas confirmé un premier
+-- code. last_used_step : dernier pas de temps accepté, un code ne sert qu'une fois.
+CREATE TABLE IF NOT EXISTS user_mfa (
+    user_id        VARCHAR(36) PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret         TEXT NOT NULL,
+    enabled_at     TIMESTAMPTZ,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Codes de récupération (usage unique), remplacés à chaque activation.
+-- Seul le hash SHA-256 du code est stocké.
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+    code_hash  CHAR(64) PRIMARY KEY,
+    user_id    VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    used_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_mfa_recovery_codes_user ON mfa_recovery_codes (user_id);
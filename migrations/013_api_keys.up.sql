@@ -0,0 +1,19 @@
+-- migrations/013_api_keys.up.sql
+
+-- Clés d'API des clients machine (scripts du back-office, intégration
+-- entrepôt). La clé complète "gsk_<prefix>_<secret>" n'est montrée qu'à la
+-- création : prefix identifie la clé, seul le hash SHA-256 du secret est stocké.
+-- scopes : permissions accordées (mêmes noms que la table permissions).
+CREATE TABLE IF NOT EXISTS api_keys (
+    id           VARCHAR(36) PRIMARY KEY,
+    name         VARCHAR(100) NOT NULL,
+    prefix       VARCHAR(32) NOT NULL UNIQUE,
+    secret_hash  CHAR(64) NOT NULL,
+    scopes       TEXT[] NOT NULL DEFAULT '{}',
+    rate_limit   INTEGER NOT NULL CHECK (rate_limit > 0), -- requêtes par minute
+    expires_at   TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    revoked_at   TIMESTAMPTZ,
+    created_by   VARCHAR(36) REFERENCES users(id) ON DELETE SET NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,4 @@
+-- migrations/014_categories.down.sql
+
+DROP TABLE IF EXISTS product_categories;
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,28 @@
+-- migrations/014_categories.up.sql
+
+-- Arbre des catégories du catalogue. path matérialise la position dans
+-- l'arbre : slugs des ancêtres puis de la catégorie, séparés par "/"
+-- ("electronique/ordinateurs/portables"). Les descendants d'une catégorie
+-- sont les lignes dont le path commence par "<path>/" ; les slugs ne
+-- contiennent que [a-z0-9-], le préfixe s'utilise donc tel quel dans un LIKE.
+CREATE TABLE IF NOT EXISTS categories (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name       VARCHAR(100) NOT NULL,
+    slug       VARCHAR(100) NOT NULL UNIQUE,
+    parent_id  UUID REFERENCES categories(id) ON DELETE RESTRICT,
+    path       TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
+CREATE INDEX IF NOT EXISTS idx_categories_path ON categories(path text_pattern_ops);
+
+-- Un produit peut appartenir à plusieurs catégories
+CREATE TABLE IF NOT EXISTS product_categories (
+    product_id  UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    PRIMARY KEY (product_id, category_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_categories_category_id ON product_categories(category_id);
@@ -0,0 +1,4 @@
+-- migrations/015_product_variants.down.sql
+
+ALTER TABLE order_items DROP COLUMN IF EXISTS variant_id;
+DROP TABLE IF EXISTS product_variants;
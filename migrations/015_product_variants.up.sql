@@ -0,0 +1,41 @@
+-- migrations/015_product_variants.up.sql
+
+-- Variantes vendables d'un produit (taille, couleur...). Chaque produit a
+-- exactement une variante par défaut, celle des commandes sans variant_id.
+-- products.stock devient le stock total du produit : la somme des stocks de
+-- ses variantes, maintenue par l'application dans la même transaction.
+CREATE TABLE IF NOT EXISTS product_variants (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id  UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    sku         VARCHAR(64) NOT NULL UNIQUE,
+    -- Valeurs d'options : {"size": "M", "colour": "bleu"}
+    options     JSONB NOT NULL DEFAULT '{}'::jsonb,
+    -- NULL : la variante est vendue au prix du produit
+    price_cents BIGINT CHECK (price_cents > 0),
+    stock       INT NOT NULL DEFAULT 0 CHECK (stock >= 0),
+    is_default  BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT product_variants_options_key UNIQUE (product_id, options)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_variants_default
+    ON product_variants(product_id) WHERE is_default;
+
+-- Produits existants : une variante par défaut qui reprend tout le stock
+INSERT INTO product_variants (product_id, sku, stock, is_default)
+SELECT p.id, 'SKU-' || p.id::text, p.stock, TRUE
+FROM products p
+WHERE NOT EXISTS (
+    SELECT 1 FROM product_variants v WHERE v.product_id = p.id AND v.is_default
+);
+
+-- Les articles de commande référencent la variante vendue
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_id UUID REFERENCES product_variants(id);
+
+UPDATE order_items oi
+SET variant_id = v.id
+FROM product_variants v
+WHERE v.product_id = oi.product_id AND v.is_default AND oi.variant_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_order_items_variant_id ON order_items(variant_id);
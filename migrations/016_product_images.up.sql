@@ -0,0 +1,16 @@
+-- migrations/016_product_images.up.sql
+
+-- Images des produits. Le contenu est dans le stockage de fichiers (BlobStore,
+-- clé blob_key) ; la table garde les métadonnées et l'ordre d'affichage.
+-- La position 0 est l'image principale.
+CREATE TABLE IF NOT EXISTS product_images (
+    id           UUID PRIMARY KEY,
+    product_id   UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    blob_key     VARCHAR(512) NOT NULL UNIQUE,
+    content_type VARCHAR(50) NOT NULL,
+    size_bytes   BIGINT NOT NULL CHECK (size_bytes > 0),
+    position     INT NOT NULL CHECK (position >= 0),
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    -- Différée : une réorganisation échange les positions en une requête
+    CONSTRAINT product_images_position_key UNIQUE (product_id, position) DEFERRABLE INITIALLY DEFERRED
+);
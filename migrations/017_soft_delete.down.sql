@@ -0,0 +1,12 @@
+-- migrations/017_soft_delete.down.sql
+
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_customer_id_fkey;
+ALTER TABLE orders ADD CONSTRAINT orders_customer_id_fkey
+    FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE CASCADE;
+
+DROP INDEX IF EXISTS idx_customers_active_created_at_id;
+DROP INDEX IF EXISTS idx_products_active_created_at_id;
+
+-- Les lignes archivées redeviennent visibles
+ALTER TABLE customers DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE products DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,20 @@
+-- migrations/017_soft_delete.up.sql
+
+-- Archivage des produits et des clients : DELETE renseigne deleted_at au lieu
+-- de supprimer la ligne. Les commandes gardent ainsi leurs articles (la clé
+-- étrangère order_items.product_id interdisait la suppression d'un produit
+-- commandé) et leur client.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+-- Listes par défaut : lignes non archivées uniquement
+CREATE INDEX IF NOT EXISTS idx_products_active_created_at_id
+    ON products(created_at DESC, id DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_customers_active_created_at_id
+    ON customers(created_at DESC, id DESC) WHERE deleted_at IS NULL;
+
+-- Un client n'est plus supprimé : une suppression en base ne doit plus
+-- emporter ses commandes en cascade
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_customer_id_fkey;
+ALTER TABLE orders ADD CONSTRAINT orders_customer_id_fkey
+    FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE RESTRICT;
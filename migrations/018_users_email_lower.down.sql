@@ -0,0 +1,3 @@
+-- migrations/018_users_email_lower.down.sql
+
+DROP INDEX IF EXISTS idx_users_email_lower;
@@ -0,0 +1,6 @@
+-- migrations/018_users_email_lower.up.sql
+
+-- Les emails sont enregistrés en minuscules depuis l'inscription, mais les
+-- comptes plus anciens peuvent avoir été saisis avec des majuscules :
+-- FindUserByEmail compare LOWER(email), cet index lui évite un parcours complet.
+CREATE INDEX IF NOT EXISTS idx_users_email_lower ON users (LOWER(email));
@@ -0,0 +1,8 @@
+-- migrations/019_customer_email_link_on_verify.down.sql
+
+DROP INDEX IF EXISTS idx_customers_email_lower;
+DROP INDEX IF EXISTS idx_customers_email_linked;
+DROP INDEX IF EXISTS idx_customers_email_unlinked;
+
+-- Échoue si un compte non confirmé et un client partagent encore un email
+ALTER TABLE customers ADD CONSTRAINT customers_email_key UNIQUE (email);
@@ -0,0 +1,16 @@
+-- migrations/019_customer_email_link_on_verify.up.sql
+
+-- L'inscription crée toujours son propre profil client : un client existant de
+-- même email (créé par le back-office) n'est rattaché au compte qu'une fois
+-- l'email confirmé. Entre-temps les deux lignes partagent l'email, l'unicité
+-- porte donc séparément sur les clients rattachés et non rattachés.
+ALTER TABLE customers DROP CONSTRAINT IF EXISTS customers_email_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_email_unlinked
+    ON customers(email) WHERE user_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_email_linked
+    ON customers(email) WHERE user_id IS NOT NULL;
+
+-- Recherche du client à rattacher à la confirmation (LOWER(email))
+CREATE INDEX IF NOT EXISTS idx_customers_email_lower
+    ON customers(LOWER(email)) WHERE user_id IS NULL;
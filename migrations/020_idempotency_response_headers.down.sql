@@ -0,0 +1,3 @@
+-- migrations/020_idempotency_response_headers.down.sql
+
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS response_headers;
@@ -0,0 +1,6 @@
+-- migrations/020_idempotency_response_headers.up.sql
+
+-- Headers de la réponse mémorisée (Location, X-Total-Count...) rejoués avec
+-- elle ; Content-Type reste dans response_content_type. NULL pour les clés
+-- enregistrées avant cette migration.
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS response_headers JSONB;
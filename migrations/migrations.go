@@ -0,0 +1,10 @@
+// Package migrations embarque les fichiers SQL versionnés du schéma.
+//
+// Convention de nommage : NNN_description.up.sql / NNN_description.down.sql.
+// Les fichiers sont appliqués par infrastructure/postgres/migrate (cmd/migrate).
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
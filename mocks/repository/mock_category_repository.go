@@ -0,0 +1,232 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: Goshop/domain/repository (interfaces: CategoryRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../mocks/repository/mock_category_repository.go -package=repository . CategoryRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	entity "Goshop/domain/entity"
+	repository "Goshop/domain/repository"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCategoryRepository is a mock of CategoryRepository interface.
+type MockCategoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCategoryRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockCategoryRepositoryMockRecorder is the mock recorder for MockCategoryRepository.
+type MockCategoryRepositoryMockRecorder struct {
+	mock *MockCategoryRepository
+}
+
+// NewMockCategoryRepository creates a new mock instance.
+func NewMockCategoryRepository(ctrl *gomock.Controller) *MockCategoryRepository {
+	mock := &MockCategoryRepository{ctrl: ctrl}
+	mock.recorder = &MockCategoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCategoryRepository) EXPECT() *MockCategoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCategoryRepository) Create(ctx context.Context, category *entity.Category) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCategoryRepositoryMockRecorder) Create(ctx, category any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCategoryRepository)(nil).Create), ctx, category)
+}
+
+// Delete mocks base method.
+func (m *MockCategoryRepository) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCategoryRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCategoryRepository)(nil).Delete), ctx, id)
+}
+
+// FindAll mocks base method.
+func (m *MockCategoryRepository) FindAll(ctx context.Context) ([]*entity.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx)
+	ret0, _ := ret[0].([]*entity.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockCategoryRepositoryMockRecorder) FindAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockCategoryRepository)(nil).FindAll), ctx)
+}
+
+// FindBreadcrumb mocks base method.
+func (m *MockCategoryRepository) FindBreadcrumb(ctx context.Context, path string) ([]*entity.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBreadcrumb", ctx, path)
+	ret0, _ := ret[0].([]*entity.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBreadcrumb indicates an expected call of FindBreadcrumb.
+func (mr *MockCategoryRepositoryMockRecorder) FindBreadcrumb(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBreadcrumb", reflect.TypeOf((*MockCategoryRepository)(nil).FindBreadcrumb), ctx, path)
+}
+
+// FindByID mocks base method.
+func (m *MockCategoryRepository) FindByID(ctx context.Context, id string) (*entity.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockCategoryRepositoryMockRecorder) FindByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockCategoryRepository)(nil).FindByID), ctx, id)
+}
+
+// FindByIDs mocks base method.
+func (m *MockCategoryRepository) FindByIDs(ctx context.Context, ids []string) ([]*entity.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByIDs", ctx, ids)
+	ret0, _ := ret[0].([]*entity.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIDs indicates an expected call of FindByIDs.
+func (mr *MockCategoryRepositoryMockRecorder) FindByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIDs", reflect.TypeOf((*MockCategoryRepository)(nil).FindByIDs), ctx, ids)
+}
+
+// FindBySlug mocks base method.
+func (m *MockCategoryRepository) FindBySlug(ctx context.Context, slug string) (*entity.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBySlug", ctx, slug)
+	ret0, _ := ret[0].(*entity.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBySlug indicates an expected call of FindBySlug.
+func (mr *MockCategoryRepositoryMockRecorder) FindBySlug(ctx, slug any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBySlug", reflect.TypeOf((*MockCategoryRepository)(nil).FindBySlug), ctx, slug)
+}
+
+// FindChildren mocks base method.
+func (m *MockCategoryRepository) FindChildren(ctx context.Context, parentID string) ([]*entity.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindChildren", ctx, parentID)
+	ret0, _ := ret[0].([]*entity.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindChildren indicates an expected call of FindChildren.
+func (mr *MockCategoryRepositoryMockRecorder) FindChildren(ctx, parentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindChildren", reflect.TypeOf((*MockCategoryRepository)(nil).FindChildren), ctx, parentID)
+}
+
+// FindProductCategories mocks base method.
+func (m *MockCategoryRepository) FindProductCategories(ctx context.Context, productIDs []string) ([]*entity.ProductCategory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindProductCategories", ctx, productIDs)
+	ret0, _ := ret[0].([]*entity.ProductCategory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindProductCategories indicates an expected call of FindProductCategories.
+func (mr *MockCategoryRepositoryMockRecorder) FindProductCategories(ctx, productIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindProductCategories", reflect.TypeOf((*MockCategoryRepository)(nil).FindProductCategories), ctx, productIDs)
+}
+
+// LockTree mocks base method.
+func (m *MockCategoryRepository) LockTree(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockTree", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockTree indicates an expected call of LockTree.
+func (mr *MockCategoryRepositoryMockRecorder) LockTree(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockTree", reflect.TypeOf((*MockCategoryRepository)(nil).LockTree), ctx)
+}
+
+// SetProductCategories mocks base method.
+func (m *MockCategoryRepository) SetProductCategories(ctx context.Context, productID string, categoryIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProductCategories", ctx, productID, categoryIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetProductCategories indicates an expected call of SetProductCategories.
+func (mr *MockCategoryRepositoryMockRecorder) SetProductCategories(ctx, productID, categoryIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProductCategories", reflect.TypeOf((*MockCategoryRepository)(nil).SetProductCategories), ctx, productID, categoryIDs)
+}
+
+// Update mocks base method.
+func (m *MockCategoryRepository) Update(ctx context.Context, category *entity.Category, oldPath string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, category, oldPath)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCategoryRepositoryMockRecorder) Update(ctx, category, oldPath any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCategoryRepository)(nil).Update), ctx, category, oldPath)
+}
+
+// WithTX mocks base method.
+func (m *MockCategoryRepository) WithTX(tx repository.Tx) repository.CategoryRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTX", tx)
+	ret0, _ := ret[0].(repository.CategoryRepository)
+	return ret0
+}
+
+// WithTX indicates an expected call of WithTX.
+func (mr *MockCategoryRepositoryMockRecorder) WithTX(tx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTX", reflect.TypeOf((*MockCategoryRepository)(nil).WithTX), tx)
+}
@@ -11,6 +11,7 @@ package repository
 
 import (
 	dto "Goshop/application/dto/customer_dto"
+	paginationdto "Goshop/application/dto/pagination_dto"
 	entity "Goshop/domain/entity"
 	repository "Goshop/domain/repository"
 	context "context"
@@ -102,6 +103,21 @@ func (mr *MockCustomerRepositoryInterfaceMockRecorder) FindAllCustomers(ctx any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllCustomers", reflect.TypeOf((*MockCustomerRepositoryInterface)(nil).FindAllCustomers), ctx)
 }
 
+// FindAllCustomersByCursor mocks base method.
+func (m *MockCustomerRepositoryInterface) FindAllCustomersByCursor(ctx context.Context, page paginationdto.CursorPage, filter dto.CustomerFilter) ([]*entity.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllCustomersByCursor", ctx, page, filter)
+	ret0, _ := ret[0].([]*entity.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAllCustomersByCursor indicates an expected call of FindAllCustomersByCursor.
+func (mr *MockCustomerRepositoryInterfaceMockRecorder) FindAllCustomersByCursor(ctx, page, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllCustomersByCursor", reflect.TypeOf((*MockCustomerRepositoryInterface)(nil).FindAllCustomersByCursor), ctx, page, filter)
+}
+
 // FindAllCustomersWithPagination mocks base method.
 func (m *MockCustomerRepositoryInterface) FindAllCustomersWithPagination(ctx context.Context, limit, offset int, filter dto.CustomerFilter) ([]*entity.Customer, error) {
 	m.ctrl.T.Helper()
@@ -147,6 +163,21 @@ func (mr *MockCustomerRepositoryInterfaceMockRecorder) FindByCustomerID(ctx, id
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCustomerID", reflect.TypeOf((*MockCustomerRepositoryInterface)(nil).FindByCustomerID), ctx, id)
 }
 
+// FindByCustomerIDIncludingDeleted mocks base method.
+func (m *MockCustomerRepositoryInterface) FindByCustomerIDIncludingDeleted(ctx context.Context, id string) (*entity.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCustomerIDIncludingDeleted", ctx, id)
+	ret0, _ := ret[0].(*entity.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCustomerIDIncludingDeleted indicates an expected call of FindByCustomerIDIncludingDeleted.
+func (mr *MockCustomerRepositoryInterfaceMockRecorder) FindByCustomerIDIncludingDeleted(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCustomerIDIncludingDeleted", reflect.TypeOf((*MockCustomerRepositoryInterface)(nil).FindByCustomerIDIncludingDeleted), ctx, id)
+}
+
 // FindByEmail mocks base method.
 func (m *MockCustomerRepositoryInterface) FindByEmail(ctx context.Context, email string) (*entity.Customer, error) {
 	m.ctrl.T.Helper()
@@ -162,6 +193,36 @@ func (mr *MockCustomerRepositoryInterfaceMockRecorder) FindByEmail(ctx, email an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByEmail", reflect.TypeOf((*MockCustomerRepositoryInterface)(nil).FindByEmail), ctx, email)
 }
 
+// FindByUserID mocks base method.
+func (m *MockCustomerRepositoryInterface) FindByUserID(ctx context.Context, userID string) (*entity.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserID", ctx, userID)
+	ret0, _ := ret[0].(*entity.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserID indicates an expected call of FindByUserID.
+func (mr *MockCustomerRepositoryInterfaceMockRecorder) FindByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockCustomerRepositoryInterface)(nil).FindByUserID), ctx, userID)
+}
+
+// RestoreCustomer mocks base method.
+func (m *MockCustomerRepositoryInterface) RestoreCustomer(ctx context.Context, id string) (*entity.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreCustomer", ctx, id)
+	ret0, _ := ret[0].(*entity.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreCustomer indicates an expected call of RestoreCustomer.
+func (mr *MockCustomerRepositoryInterfaceMockRecorder) RestoreCustomer(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreCustomer", reflect.TypeOf((*MockCustomerRepositoryInterface)(nil).RestoreCustomer), ctx, id)
+}
+
 // UpdateCustomer mocks base method.
 func (m *MockCustomerRepositoryInterface) UpdateCustomer(ctx context.Context, customer *entity.Customer) (*entity.Customer, error) {
 	m.ctrl.T.Helper()
@@ -11,6 +11,7 @@ package repository
 
 import (
 	orderdto "Goshop/application/dto/order_dto"
+	paginationdto "Goshop/application/dto/pagination_dto"
 	entity "Goshop/domain/entity"
 	repository "Goshop/domain/repository"
 	context "context"
@@ -43,6 +44,20 @@ func (m *MockOrderRepository) EXPECT() *MockOrderRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddStatusHistory mocks base method.
+func (m *MockOrderRepository) AddStatusHistory(ctx context.Context, history *entity.OrderStatusHistory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddStatusHistory", ctx, history)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddStatusHistory indicates an expected call of AddStatusHistory.
+func (mr *MockOrderRepositoryMockRecorder) AddStatusHistory(ctx, history any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddStatusHistory", reflect.TypeOf((*MockOrderRepository)(nil).AddStatusHistory), ctx, history)
+}
+
 // CountAll mocks base method.
 func (m *MockOrderRepository) CountAll(ctx context.Context, filter orderdto.OrderFilter) (int, error) {
 	m.ctrl.T.Helper()
@@ -103,6 +118,21 @@ func (mr *MockOrderRepositoryMockRecorder) FindAll(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockOrderRepository)(nil).FindAll), ctx)
 }
 
+// FindAllByCursor mocks base method.
+func (m *MockOrderRepository) FindAllByCursor(ctx context.Context, page paginationdto.CursorPage, filter orderdto.OrderFilter) ([]*entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllByCursor", ctx, page, filter)
+	ret0, _ := ret[0].([]*entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAllByCursor indicates an expected call of FindAllByCursor.
+func (mr *MockOrderRepositoryMockRecorder) FindAllByCursor(ctx, page, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllByCursor", reflect.TypeOf((*MockOrderRepository)(nil).FindAllByCursor), ctx, page, filter)
+}
+
 // FindAllWithPagination mocks base method.
 func (m *MockOrderRepository) FindAllWithPagination(ctx context.Context, limit, offset int, filter orderdto.OrderFilter) ([]*entity.Order, error) {
 	m.ctrl.T.Helper()
@@ -133,6 +163,36 @@ func (mr *MockOrderRepositoryMockRecorder) FindByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockOrderRepository)(nil).FindByID), ctx, id)
 }
 
+// FindStatusHistory mocks base method.
+func (m *MockOrderRepository) FindStatusHistory(ctx context.Context, orderID string) ([]*entity.OrderStatusHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindStatusHistory", ctx, orderID)
+	ret0, _ := ret[0].([]*entity.OrderStatusHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindStatusHistory indicates an expected call of FindStatusHistory.
+func (mr *MockOrderRepositoryMockRecorder) FindStatusHistory(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindStatusHistory", reflect.TypeOf((*MockOrderRepository)(nil).FindStatusHistory), ctx, orderID)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockOrderRepository) UpdateStatus(ctx context.Context, id, from, to string) (*entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, from, to)
+	ret0, _ := ret[0].(*entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockOrderRepositoryMockRecorder) UpdateStatus(ctx, id, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockOrderRepository)(nil).UpdateStatus), ctx, id, from, to)
+}
+
 // WithTX mocks base method.
 func (m *MockOrderRepository) WithTX(tx repository.Tx) repository.OrderRepository {
 	m.ctrl.T.Helper()
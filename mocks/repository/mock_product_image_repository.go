@@ -0,0 +1,173 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: Goshop/domain/repository (interfaces: ProductImageRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../mocks/repository/mock_product_image_repository.go -package=repository . ProductImageRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	entity "Goshop/domain/entity"
+	repository "Goshop/domain/repository"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProductImageRepository is a mock of ProductImageRepository interface.
+type MockProductImageRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductImageRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockProductImageRepositoryMockRecorder is the mock recorder for MockProductImageRepository.
+type MockProductImageRepositoryMockRecorder struct {
+	mock *MockProductImageRepository
+}
+
+// NewMockProductImageRepository creates a new mock instance.
+func NewMockProductImageRepository(ctrl *gomock.Controller) *MockProductImageRepository {
+	mock := &MockProductImageRepository{ctrl: ctrl}
+	mock.recorder = &MockProductImageRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductImageRepository) EXPECT() *MockProductImageRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockProductImageRepository) Create(ctx context.Context, image *entity.ProductImage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, image)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockProductImageRepositoryMockRecorder) Create(ctx, image any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductImageRepository)(nil).Create), ctx, image)
+}
+
+// Delete mocks base method.
+func (m *MockProductImageRepository) Delete(ctx context.Context, productID, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, productID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockProductImageRepositoryMockRecorder) Delete(ctx, productID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductImageRepository)(nil).Delete), ctx, productID, id)
+}
+
+// DeleteByProduct mocks base method.
+func (m *MockProductImageRepository) DeleteByProduct(ctx context.Context, productID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByProduct", ctx, productID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByProduct indicates an expected call of DeleteByProduct.
+func (mr *MockProductImageRepositoryMockRecorder) DeleteByProduct(ctx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByProduct", reflect.TypeOf((*MockProductImageRepository)(nil).DeleteByProduct), ctx, productID)
+}
+
+// FindByID mocks base method.
+func (m *MockProductImageRepository) FindByID(ctx context.Context, id string) (*entity.ProductImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*entity.ProductImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockProductImageRepositoryMockRecorder) FindByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockProductImageRepository)(nil).FindByID), ctx, id)
+}
+
+// FindByProduct mocks base method.
+func (m *MockProductImageRepository) FindByProduct(ctx context.Context, productID string) ([]*entity.ProductImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByProduct", ctx, productID)
+	ret0, _ := ret[0].([]*entity.ProductImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByProduct indicates an expected call of FindByProduct.
+func (mr *MockProductImageRepositoryMockRecorder) FindByProduct(ctx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByProduct", reflect.TypeOf((*MockProductImageRepository)(nil).FindByProduct), ctx, productID)
+}
+
+// FindPrimary mocks base method.
+func (m *MockProductImageRepository) FindPrimary(ctx context.Context, productIDs []string) ([]*entity.ProductImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPrimary", ctx, productIDs)
+	ret0, _ := ret[0].([]*entity.ProductImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPrimary indicates an expected call of FindPrimary.
+func (mr *MockProductImageRepositoryMockRecorder) FindPrimary(ctx, productIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPrimary", reflect.TypeOf((*MockProductImageRepository)(nil).FindPrimary), ctx, productIDs)
+}
+
+// LockProduct mocks base method.
+func (m *MockProductImageRepository) LockProduct(ctx context.Context, productID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockProduct", ctx, productID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockProduct indicates an expected call of LockProduct.
+func (mr *MockProductImageRepositoryMockRecorder) LockProduct(ctx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockProduct", reflect.TypeOf((*MockProductImageRepository)(nil).LockProduct), ctx, productID)
+}
+
+// Reorder mocks base method.
+func (m *MockProductImageRepository) Reorder(ctx context.Context, productID string, imageIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", ctx, productID, imageIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockProductImageRepositoryMockRecorder) Reorder(ctx, productID, imageIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockProductImageRepository)(nil).Reorder), ctx, productID, imageIDs)
+}
+
+// WithTX mocks base method.
+func (m *MockProductImageRepository) WithTX(tx repository.Tx) repository.ProductImageRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTX", tx)
+	ret0, _ := ret[0].(repository.ProductImageRepository)
+	return ret0
+}
+
+// WithTX indicates an expected call of WithTX.
+func (mr *MockProductImageRepositoryMockRecorder) WithTX(tx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTX", reflect.TypeOf((*MockProductImageRepository)(nil).WithTX), tx)
+}
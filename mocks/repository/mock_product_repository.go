@@ -10,6 +10,8 @@
 package repository
 
 import (
+	paginationdto "Goshop/application/dto/pagination_dto"
+	dto "Goshop/application/dto/product_dto"
 	entity "Goshop/domain/entity"
 	repository "Goshop/domain/repository"
 	context "context"
@@ -42,6 +44,21 @@ func (m *MockProductRepository) EXPECT() *MockProductRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CountAll mocks base method.
+func (m *MockProductRepository) CountAll(ctx context.Context, filter dto.ProductFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAll", ctx, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAll indicates an expected call of CountAll.
+func (mr *MockProductRepositoryMockRecorder) CountAll(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAll", reflect.TypeOf((*MockProductRepository)(nil).CountAll), ctx, filter)
+}
+
 // Create mocks base method.
 func (m *MockProductRepository) Create(ctx context.Context, product *entity.Product) error {
 	m.ctrl.T.Helper()
@@ -71,18 +88,33 @@ func (mr *MockProductRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
 }
 
 // FindAll mocks base method.
-func (m *MockProductRepository) FindAll(ctx context.Context, limit, offset int) ([]*entity.Product, error) {
+func (m *MockProductRepository) FindAll(ctx context.Context, limit, offset int, filter dto.ProductFilter) ([]*entity.Product, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAll", ctx, limit, offset)
+	ret := m.ctrl.Call(m, "FindAll", ctx, limit, offset, filter)
 	ret0, _ := ret[0].([]*entity.Product)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindAll indicates an expected call of FindAll.
-func (mr *MockProductRepositoryMockRecorder) FindAll(ctx, limit, offset any) *gomock.Call {
+func (mr *MockProductRepositoryMockRecorder) FindAll(ctx, limit, offset, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockProductRepository)(nil).FindAll), ctx, limit, offset, filter)
+}
+
+// FindAllByCursor mocks base method.
+func (m *MockProductRepository) FindAllByCursor(ctx context.Context, page paginationdto.CursorPage, filter dto.ProductFilter) ([]*entity.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllByCursor", ctx, page, filter)
+	ret0, _ := ret[0].([]*entity.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAllByCursor indicates an expected call of FindAllByCursor.
+func (mr *MockProductRepositoryMockRecorder) FindAllByCursor(ctx, page, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockProductRepository)(nil).FindAll), ctx, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllByCursor", reflect.TypeOf((*MockProductRepository)(nil).FindAllByCursor), ctx, page, filter)
 }
 
 // FindByID mocks base method.
@@ -100,6 +132,80 @@ func (mr *MockProductRepositoryMockRecorder) FindByID(ctx, id any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockProductRepository)(nil).FindByID), ctx, id)
 }
 
+// FindByIDIncludingDeleted mocks base method.
+func (m *MockProductRepository) FindByIDIncludingDeleted(ctx context.Context, id string) (*entity.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByIDIncludingDeleted", ctx, id)
+	ret0, _ := ret[0].(*entity.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIDIncludingDeleted indicates an expected call of FindByIDIncludingDeleted.
+func (mr *MockProductRepositoryMockRecorder) FindByIDIncludingDeleted(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIDIncludingDeleted", reflect.TypeOf((*MockProductRepository)(nil).FindByIDIncludingDeleted), ctx, id)
+}
+
+// Purge mocks base method.
+func (m *MockProductRepository) Purge(ctx context.Context, id string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockProductRepositoryMockRecorder) Purge(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockProductRepository)(nil).Purge), ctx, id)
+}
+
+// ReserveStock mocks base method.
+func (m *MockProductRepository) ReserveStock(ctx context.Context, id string, quantity int) (*entity.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveStock", ctx, id, quantity)
+	ret0, _ := ret[0].(*entity.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveStock indicates an expected call of ReserveStock.
+func (mr *MockProductRepositoryMockRecorder) ReserveStock(ctx, id, quantity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveStock", reflect.TypeOf((*MockProductRepository)(nil).ReserveStock), ctx, id, quantity)
+}
+
+// Restore mocks base method.
+func (m *MockProductRepository) Restore(ctx context.Context, id string) (*entity.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(*entity.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockProductRepositoryMockRecorder) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockProductRepository)(nil).Restore), ctx, id)
+}
+
+// RestoreStock mocks base method.
+func (m *MockProductRepository) RestoreStock(ctx context.Context, id string, quantity int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreStock", ctx, id, quantity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreStock indicates an expected call of RestoreStock.
+func (mr *MockProductRepositoryMockRecorder) RestoreStock(ctx, id, quantity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreStock", reflect.TypeOf((*MockProductRepository)(nil).RestoreStock), ctx, id, quantity)
+}
+
 // Update mocks base method.
 func (m *MockProductRepository) Update(ctx context.Context, product *entity.Product) (*entity.Product, error) {
 	m.ctrl.T.Helper()
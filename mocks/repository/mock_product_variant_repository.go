@@ -0,0 +1,216 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: Goshop/domain/repository (interfaces: ProductVariantRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../mocks/repository/mock_product_variant_repository.go -package=repository . ProductVariantRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	entity "Goshop/domain/entity"
+	repository "Goshop/domain/repository"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProductVariantRepository is a mock of ProductVariantRepository interface.
+type MockProductVariantRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductVariantRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockProductVariantRepositoryMockRecorder is the mock recorder for MockProductVariantRepository.
+type MockProductVariantRepositoryMockRecorder struct {
+	mock *MockProductVariantRepository
+}
+
+// NewMockProductVariantRepository creates a new mock instance.
+func NewMockProductVariantRepository(ctrl *gomock.Controller) *MockProductVariantRepository {
+	mock := &MockProductVariantRepository{ctrl: ctrl}
+	mock.recorder = &MockProductVariantRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductVariantRepository) EXPECT() *MockProductVariantRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockProductVariantRepository) Create(ctx context.Context, variant *entity.ProductVariant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, variant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockProductVariantRepositoryMockRecorder) Create(ctx, variant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductVariantRepository)(nil).Create), ctx, variant)
+}
+
+// Delete mocks base method.
+func (m *MockProductVariantRepository) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockProductVariantRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductVariantRepository)(nil).Delete), ctx, id)
+}
+
+// FindByID mocks base method.
+func (m *MockProductVariantRepository) FindByID(ctx context.Context, id string) (*entity.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*entity.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockProductVariantRepositoryMockRecorder) FindByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockProductVariantRepository)(nil).FindByID), ctx, id)
+}
+
+// FindByProducts mocks base method.
+func (m *MockProductVariantRepository) FindByProducts(ctx context.Context, productIDs []string) ([]*entity.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByProducts", ctx, productIDs)
+	ret0, _ := ret[0].([]*entity.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByProducts indicates an expected call of FindByProducts.
+func (mr *MockProductVariantRepositoryMockRecorder) FindByProducts(ctx, productIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByProducts", reflect.TypeOf((*MockProductVariantRepository)(nil).FindByProducts), ctx, productIDs)
+}
+
+// FindDefault mocks base method.
+func (m *MockProductVariantRepository) FindDefault(ctx context.Context, productID string) (*entity.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDefault", ctx, productID)
+	ret0, _ := ret[0].(*entity.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDefault indicates an expected call of FindDefault.
+func (mr *MockProductVariantRepositoryMockRecorder) FindDefault(ctx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDefault", reflect.TypeOf((*MockProductVariantRepository)(nil).FindDefault), ctx, productID)
+}
+
+// LockProduct mocks base method.
+func (m *MockProductVariantRepository) LockProduct(ctx context.Context, productID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockProduct", ctx, productID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockProduct indicates an expected call of LockProduct.
+func (mr *MockProductVariantRepositoryMockRecorder) LockProduct(ctx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockProduct", reflect.TypeOf((*MockProductVariantRepository)(nil).LockProduct), ctx, productID)
+}
+
+// RefreshProductStock mocks base method.
+func (m *MockProductVariantRepository) RefreshProductStock(ctx context.Context, productID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshProductStock", ctx, productID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshProductStock indicates an expected call of RefreshProductStock.
+func (mr *MockProductVariantRepositoryMockRecorder) RefreshProductStock(ctx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshProductStock", reflect.TypeOf((*MockProductVariantRepository)(nil).RefreshProductStock), ctx, productID)
+}
+
+// ReserveStock mocks base method.
+func (m *MockProductVariantRepository) ReserveStock(ctx context.Context, productID, id string, quantity int) (*entity.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveStock", ctx, productID, id, quantity)
+	ret0, _ := ret[0].(*entity.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveStock indicates an expected call of ReserveStock.
+func (mr *MockProductVariantRepositoryMockRecorder) ReserveStock(ctx, productID, id, quantity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveStock", reflect.TypeOf((*MockProductVariantRepository)(nil).ReserveStock), ctx, productID, id, quantity)
+}
+
+// RestoreStock mocks base method.
+func (m *MockProductVariantRepository) RestoreStock(ctx context.Context, id string, quantity int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreStock", ctx, id, quantity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreStock indicates an expected call of RestoreStock.
+func (mr *MockProductVariantRepositoryMockRecorder) RestoreStock(ctx, id, quantity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreStock", reflect.TypeOf((*MockProductVariantRepository)(nil).RestoreStock), ctx, id, quantity)
+}
+
+// SetDefaultStock mocks base method.
+func (m *MockProductVariantRepository) SetDefaultStock(ctx context.Context, productID string, stock int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDefaultStock", ctx, productID, stock)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDefaultStock indicates an expected call of SetDefaultStock.
+func (mr *MockProductVariantRepositoryMockRecorder) SetDefaultStock(ctx, productID, stock any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefaultStock", reflect.TypeOf((*MockProductVariantRepository)(nil).SetDefaultStock), ctx, productID, stock)
+}
+
+// Update mocks base method.
+func (m *MockProductVariantRepository) Update(ctx context.Context, variant *entity.ProductVariant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, variant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockProductVariantRepositoryMockRecorder) Update(ctx, variant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProductVariantRepository)(nil).Update), ctx, variant)
+}
+
+// WithTX mocks base method.
+func (m *MockProductVariantRepository) WithTX(tx repository.Tx) repository.ProductVariantRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTX", tx)
+	ret0, _ := ret[0].(repository.ProductVariantRepository)
+	return ret0
+}
+
+// WithTX indicates an expected call of WithTX.
+func (mr *MockProductVariantRepositoryMockRecorder) WithTX(tx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTX", reflect.TypeOf((*MockProductVariantRepository)(nil).WithTX), tx)
+}
@@ -0,0 +1,158 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: auth_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../../mocks/repository/mock_refresh_session_repository.go -package=repository -source=auth_repository.go RefreshSessionRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	authentity "Goshop/domain/auth_entity"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRefreshSessionRepository is a mock of RefreshSessionRepository interface.
+type MockRefreshSessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefreshSessionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRefreshSessionRepositoryMockRecorder is the mock recorder for MockRefreshSessionRepository.
+type MockRefreshSessionRepositoryMockRecorder struct {
+	mock *MockRefreshSessionRepository
+}
+
+// NewMockRefreshSessionRepository creates a new mock instance.
+func NewMockRefreshSessionRepository(ctrl *gomock.Controller) *MockRefreshSessionRepository {
+	mock := &MockRefreshSessionRepository{ctrl: ctrl}
+	mock.recorder = &MockRefreshSessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefreshSessionRepository) EXPECT() *MockRefreshSessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRefreshSessionRepository) Create(session *authentity.RefreshSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRefreshSessionRepositoryMockRecorder) Create(session any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRefreshSessionRepository)(nil).Create), session)
+}
+
+// FindByID mocks base method.
+func (m *MockRefreshSessionRepository) FindByID(id string) (*authentity.RefreshSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", id)
+	ret0, _ := ret[0].(*authentity.RefreshSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockRefreshSessionRepositoryMockRecorder) FindByID(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockRefreshSessionRepository)(nil).FindByID), id)
+}
+
+// ListActiveForUser mocks base method.
+func (m *MockRefreshSessionRepository) ListActiveForUser(userID string) ([]*authentity.RefreshSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveForUser", userID)
+	ret0, _ := ret[0].([]*authentity.RefreshSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveForUser indicates an expected call of ListActiveForUser.
+func (mr *MockRefreshSessionRepositoryMockRecorder) ListActiveForUser(userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveForUser", reflect.TypeOf((*MockRefreshSessionRepository)(nil).ListActiveForUser), userID)
+}
+
+// Revoke mocks base method.
+func (m *MockRefreshSessionRepository) Revoke(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockRefreshSessionRepositoryMockRecorder) Revoke(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockRefreshSessionRepository)(nil).Revoke), id)
+}
+
+// RevokeAllForUser mocks base method.
+func (m *MockRefreshSessionRepository) RevokeAllForUser(userID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAllForUser", userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAllForUser indicates an expected call of RevokeAllForUser.
+func (mr *MockRefreshSessionRepositoryMockRecorder) RevokeAllForUser(userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAllForUser", reflect.TypeOf((*MockRefreshSessionRepository)(nil).RevokeAllForUser), userID)
+}
+
+// RevokeFamily mocks base method.
+func (m *MockRefreshSessionRepository) RevokeFamily(familyID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeFamily", familyID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeFamily indicates an expected call of RevokeFamily.
+func (mr *MockRefreshSessionRepositoryMockRecorder) RevokeFamily(familyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeFamily", reflect.TypeOf((*MockRefreshSessionRepository)(nil).RevokeFamily), familyID)
+}
+
+// RevokeOtherSessions mocks base method.
+func (m *MockRefreshSessionRepository) RevokeOtherSessions(userID, keepFamilyID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOtherSessions", userID, keepFamilyID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeOtherSessions indicates an expected call of RevokeOtherSessions.
+func (mr *MockRefreshSessionRepositoryMockRecorder) RevokeOtherSessions(userID, keepFamilyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOtherSessions", reflect.TypeOf((*MockRefreshSessionRepository)(nil).RevokeOtherSessions), userID, keepFamilyID)
+}
+
+// Rotate mocks base method.
+func (m *MockRefreshSessionRepository) Rotate(oldID string, next *authentity.RefreshSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", oldID, next)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rotate indicates an expected call of Rotate.
+func (mr *MockRefreshSessionRepositoryMockRecorder) Rotate(oldID, next any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockRefreshSessionRepository)(nil).Rotate), oldID, next)
+}
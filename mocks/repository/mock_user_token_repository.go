@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: user_token_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../../mocks/repository/mock_user_token_repository.go -package=repository -source=user_token_repository.go UserTokenRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	authentity "Goshop/domain/auth_entity"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserTokenRepository is a mock of UserTokenRepository interface.
+type MockUserTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserTokenRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockUserTokenRepositoryMockRecorder is the mock recorder for MockUserTokenRepository.
+type MockUserTokenRepositoryMockRecorder struct {
+	mock *MockUserTokenRepository
+}
+
+// NewMockUserTokenRepository creates a new mock instance.
+func NewMockUserTokenRepository(ctrl *gomock.Controller) *MockUserTokenRepository {
+	mock := &MockUserTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockUserTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserTokenRepository) EXPECT() *MockUserTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockUserTokenRepository) Consume(ctx context.Context, tokenHash, purpose string) (*authentity.UserToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, tokenHash, purpose)
+	ret0, _ := ret[0].(*authentity.UserToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockUserTokenRepositoryMockRecorder) Consume(ctx, tokenHash, purpose any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockUserTokenRepository)(nil).Consume), ctx, tokenHash, purpose)
+}
+
+// Create mocks base method.
+func (m *MockUserTokenRepository) Create(ctx context.Context, token *authentity.UserToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUserTokenRepositoryMockRecorder) Create(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserTokenRepository)(nil).Create), ctx, token)
+}
+
+// RevokeForUser mocks base method.
+func (m *MockUserTokenRepository) RevokeForUser(ctx context.Context, userID, purpose string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeForUser", ctx, userID, purpose)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeForUser indicates an expected call of RevokeForUser.
+func (mr *MockUserTokenRepositoryMockRecorder) RevokeForUser(ctx, userID, purpose any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeForUser", reflect.TypeOf((*MockUserTokenRepository)(nil).RevokeForUser), ctx, userID, purpose)
+}
@@ -0,0 +1,108 @@
+// tests/e2e/account_e2e_test.go
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"Goshop/tests/testutilitis"
+)
+
+// loginTokens ouvre une nouvelle session et retourne son refresh token
+func loginTokens(t *testing.T, client *testutilitis.HTTPClient, email, password string) string {
+	t.Helper()
+	resp := client.MustDoRequest(t, "POST", "/login", map[string]string{"email": email, "password": password})
+	testutilitis.AssertStatus(t, resp, http.StatusOK)
+	var body struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	testutilitis.ParseJSONBody(t, resp, &body)
+	resp.Body.Close()
+	return body.RefreshToken
+}
+
+func TestChangePasswordE2E(t *testing.T) {
+	server := testutilitis.NewTestServer(t)
+	email := fmt.Sprintf("change.%d@example.com", time.Now().UnixNano())
+	newPassword := "N3w-Password!"
+
+	client := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, client, server.DB, email)
+
+	current := loginTokens(t, client, email, testutilitis.DefaultTestPassword)
+	other := loginTokens(t, testutilitis.NewHTTPClient(server.URL), email, testutilitis.DefaultTestPassword)
+
+	t.Run("Mot de passe actuel faux", func(t *testing.T) {
+		resp := client.MustDoRequest(t, "PUT", "/auth/me/password", map[string]string{
+			"current_password": "wrong-password",
+			"new_password":     newPassword,
+		})
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusForbidden)
+	})
+
+	t.Run("Seule la session courante survit", func(t *testing.T) {
+		resp := client.MustDoRequest(t, "PUT", "/auth/me/password", map[string]string{
+			"current_password": testutilitis.DefaultTestPassword,
+			"new_password":     newPassword,
+			"refresh_token":    current,
+		})
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		resp.Body.Close()
+
+		resp = client.MustDoRequest(t, "POST", "/auth/refresh", map[string]string{"refresh_token": other})
+		testutilitis.AssertStatus(t, resp, http.StatusUnauthorized)
+		resp.Body.Close()
+
+		resp = client.MustDoRequest(t, "POST", "/auth/refresh", map[string]string{"refresh_token": current})
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		resp.Body.Close()
+
+		loginTokens(t, client, email, newPassword)
+	})
+}
+
+func TestDeleteAccountE2E(t *testing.T) {
+	server := testutilitis.NewTestServer(t)
+	email := fmt.Sprintf("erase.%d@example.com", time.Now().UnixNano())
+
+	client := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, client, server.DB, email)
+
+	customerID := testutilitis.CustomerIDByEmail(t, server.DB, email)
+	if _, err := server.DB.Exec(`INSERT INTO orders (customer_id, total_cents) VALUES ($1, 4200)`, customerID); err != nil {
+		t.Fatalf("❌ Création de la commande: %v", err)
+	}
+
+	resp := client.MustDoRequest(t, "DELETE", "/auth/me", map[string]string{"password": testutilitis.DefaultTestPassword})
+	testutilitis.AssertStatus(t, resp, http.StatusNoContent)
+	resp.Body.Close()
+
+	t.Run("Le compte n'existe plus", func(t *testing.T) {
+		resp := client.MustDoRequest(t, "POST", "/login", map[string]string{
+			"email":    email,
+			"password": testutilitis.DefaultTestPassword,
+		})
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusUnauthorized)
+	})
+
+	t.Run("Le client est anonymisé, ses commandes conservées", func(t *testing.T) {
+		var customerEmail string
+		var orders int
+		err := server.DB.QueryRow(`
+			SELECT c.email, (SELECT COUNT(*) FROM orders o WHERE o.customer_id = c.id)
+			FROM customers c WHERE c.id = $1`, customerID).Scan(&customerEmail, &orders)
+		if err != nil {
+			t.Fatalf("❌ Client introuvable: %v", err)
+		}
+		if customerEmail == email {
+			t.Errorf("❌ Email du client non anonymisé")
+		}
+		if orders != 1 {
+			t.Errorf("❌ %d commande(s) conservée(s), 1 attendue", orders)
+		}
+	})
+}
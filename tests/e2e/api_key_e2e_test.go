@@ -0,0 +1,173 @@
+// tests/e2e/api_key_e2e_test.go
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"Goshop/tests/testutilitis"
+)
+
+// doWithAPIKey envoie une requête authentifiée par clé d'API (header X-API-Key)
+func doWithAPIKey(t *testing.T, baseURL, key, method, path string, body interface{}) *http.Response {
+	t.Helper()
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatalf("❌ Encodage du body: %v", err)
+		}
+	}
+	req, err := http.NewRequest(method, baseURL+path, &payload)
+	if err != nil {
+		t.Fatalf("❌ Création de la requête: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", key)
+
+	resp, err := testutilitis.NewHTTPClient(baseURL).DoRequestRaw(req)
+	if err != nil {
+		t.Fatalf("❌ Request error %s %s: %v", method, path, err)
+	}
+	return resp
+}
+
+// createAPIKey crée une clé via l'administration et retourne son ID et sa valeur
+func createAPIKey(t *testing.T, admin *testutilitis.HTTPClient, body map[string]interface{}) (string, string) {
+	t.Helper()
+	resp := admin.MustDoRequest(t, "POST", "/api/admin/api-keys", body)
+	testutilitis.AssertStatus(t, resp, http.StatusCreated)
+	var created struct {
+		ID  string `json:"id"`
+		Key string `json:"key"`
+	}
+	testutilitis.ParseJSONBody(t, resp, &created)
+	resp.Body.Close()
+	if created.ID == "" || created.Key == "" {
+		t.Fatalf("❌ Clé incomplète: %+v", created)
+	}
+	return created.ID, created.Key
+}
+
+func TestAPIKeyE2E(t *testing.T) {
+	server := testutilitis.NewTestServer(t)
+	suffix := time.Now().UnixNano()
+
+	adminClient := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, adminClient, server.DB, fmt.Sprintf("apikey.admin.%d@example.com", suffix), "admin")
+
+	customerClient := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, customerClient, server.DB, fmt.Sprintf("apikey.customer.%d@example.com", suffix))
+
+	t.Run("Un client ne gère pas les clés d'API", func(t *testing.T) {
+		resp := customerClient.MustDoRequest(t, "POST", "/api/admin/api-keys", map[string]interface{}{"name": "x"})
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusForbidden)
+	})
+
+	t.Run("Scope inconnu refusé", func(t *testing.T) {
+		resp := adminClient.MustDoRequest(t, "POST", "/api/admin/api-keys", map[string]interface{}{
+			"name":   "bad",
+			"scopes": []string{"everything"},
+		})
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusBadRequest)
+	})
+
+	keyID, key := createAPIKey(t, adminClient, map[string]interface{}{
+		"name":   "catalog sync",
+		"scopes": []string{"products:write"},
+	})
+
+	t.Run("Une clé agit dans la limite de ses scopes", func(t *testing.T) {
+		resp := doWithAPIKey(t, server.URL, key, "POST", "/api/products", testutilitis.ProductFixture())
+		testutilitis.AssertStatus(t, resp, http.StatusCreated)
+		resp.Body.Close()
+
+		resp = doWithAPIKey(t, server.URL, key, "GET", "/api/customers", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusForbidden)
+		resp.Body.Close()
+
+		// Pas de rôle : l'administration reste fermée
+		resp = doWithAPIKey(t, server.URL, key, "GET", "/api/admin/api-keys", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusForbidden)
+		resp.Body.Close()
+	})
+
+	t.Run("Schéma Authorization ApiKey", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", server.URL+"/api/products", nil)
+		req.Header.Set("Authorization", "ApiKey "+key)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("❌ Request error: %v", err)
+		}
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+	})
+
+	t.Run("Les routes du compte restent réservées aux JWT", func(t *testing.T) {
+		resp := doWithAPIKey(t, server.URL, key, "GET", "/auth/me", nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusUnauthorized)
+	})
+
+	t.Run("Listing sans secret, dernière utilisation enregistrée", func(t *testing.T) {
+		resp := adminClient.MustDoRequest(t, "GET", "/api/admin/api-keys", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		var keys []map[string]interface{}
+		testutilitis.ParseJSONBody(t, resp, &keys)
+		resp.Body.Close()
+
+		for _, k := range keys {
+			if k["id"] != keyID {
+				continue
+			}
+			if _, ok := k["key"]; ok {
+				t.Error("❌ Le listing ne doit pas exposer la clé")
+			}
+			if k["last_used_at"] == nil {
+				t.Error("❌ last_used_at attendu après utilisation")
+			}
+			return
+		}
+		t.Fatalf("❌ Clé %s absente du listing", keyID)
+	})
+
+	t.Run("Limite de requêtes par clé", func(t *testing.T) {
+		_, limited := createAPIKey(t, adminClient, map[string]interface{}{"name": "limited", "rate_limit": 2})
+
+		for i := 0; i < 2; i++ {
+			resp := doWithAPIKey(t, server.URL, limited, "GET", "/api/products", nil)
+			testutilitis.AssertStatus(t, resp, http.StatusOK)
+			resp.Body.Close()
+		}
+		resp := doWithAPIKey(t, server.URL, limited, "GET", "/api/products", nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusTooManyRequests)
+		if resp.Header.Get("Retry-After") == "" {
+			t.Error("❌ Header Retry-After attendu")
+		}
+
+		// Compteur propre à chaque clé
+		other := doWithAPIKey(t, server.URL, key, "GET", "/api/products", nil)
+		defer other.Body.Close()
+		testutilitis.AssertStatus(t, other, http.StatusOK)
+	})
+
+	t.Run("Une clé révoquée est refusée", func(t *testing.T) {
+		resp := adminClient.MustDoRequest(t, "DELETE", "/api/admin/api-keys/"+keyID, nil)
+		testutilitis.AssertStatus(t, resp, http.StatusNoContent)
+		resp.Body.Close()
+
+		resp = doWithAPIKey(t, server.URL, key, "GET", "/api/products", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusUnauthorized)
+		resp.Body.Close()
+
+		resp = adminClient.MustDoRequest(t, "DELETE", "/api/admin/api-keys/"+keyID, nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,150 @@
+// tests/e2e/category_e2e_test.go
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"Goshop/tests/testutilitis"
+)
+
+// createCategory crée une catégorie via le back-office et retourne son ID
+func createCategory(t *testing.T, admin *testutilitis.HTTPClient, name, slug, parentID string) string {
+	t.Helper()
+	body := map[string]interface{}{"name": name, "slug": slug}
+	if parentID != "" {
+		body["parent_id"] = parentID
+	}
+	resp := admin.MustDoRequest(t, "POST", "/api/categories", body)
+	testutilitis.AssertStatus(t, resp, http.StatusCreated)
+	defer resp.Body.Close()
+	return testutilitis.ExtractID(t, resp)
+}
+
+func TestCategoryE2E(t *testing.T) {
+	server := testutilitis.NewTestServer(t)
+	suffix := time.Now().UnixNano()
+
+	adminClient := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, adminClient, server.DB, fmt.Sprintf("category.admin.%d@example.com", suffix), "admin")
+
+	customerClient := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, customerClient, server.DB, fmt.Sprintf("category.customer.%d@example.com", suffix))
+
+	electronicsID := createCategory(t, adminClient, "Électronique", "electronique", "")
+	computersID := createCategory(t, adminClient, "Ordinateurs", "ordinateurs", electronicsID)
+	laptopsID := createCategory(t, adminClient, "Portables", "portables", computersID)
+	booksID := createCategory(t, adminClient, "Livres", "livres", "")
+
+	t.Run("Un client ne gère pas les catégories", func(t *testing.T) {
+		resp := customerClient.MustDoRequest(t, "POST", "/api/categories", map[string]interface{}{"name": "Jeux", "slug": "jeux"})
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusForbidden)
+	})
+
+	t.Run("Slug déjà utilisé", func(t *testing.T) {
+		resp := adminClient.MustDoRequest(t, "POST", "/api/categories", map[string]interface{}{"name": "Livres", "slug": "livres"})
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusConflict)
+	})
+
+	product := testutilitis.ProductFixture()
+	product["category_ids"] = []string{laptopsID, booksID}
+	resp := adminClient.MustDoRequest(t, "POST", "/api/products", product)
+	testutilitis.AssertStatus(t, resp, http.StatusCreated)
+	productID := testutilitis.ExtractID(t, resp)
+	resp.Body.Close()
+
+	t.Run("Fil d'Ariane dans la fiche produit", func(t *testing.T) {
+		resp := customerClient.MustDoRequest(t, "GET", "/api/products/"+productID, nil)
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		var got struct {
+			Categories []struct {
+				Slug       string `json:"slug"`
+				Breadcrumb []struct {
+					Slug string `json:"slug"`
+				} `json:"breadcrumb"`
+			} `json:"categories"`
+		}
+		testutilitis.ParseJSONBody(t, resp, &got)
+		resp.Body.Close()
+
+		if len(got.Categories) != 2 {
+			t.Fatalf("❌ 2 catégories attendues, obtenu %d", len(got.Categories))
+		}
+		for _, c := range got.Categories {
+			if c.Slug == "portables" && len(c.Breadcrumb) != 3 {
+				t.Errorf("❌ Fil d'Ariane attendu électronique > ordinateurs > portables, obtenu %+v", c.Breadcrumb)
+			}
+		}
+	})
+
+	t.Run("Produits d'une catégorie et de ses descendants", func(t *testing.T) {
+		for _, slug := range []string{"electronique", "ordinateurs", "portables", "livres"} {
+			resp := customerClient.MustDoRequest(t, "GET", "/api/categories/"+slug+"/products", nil)
+			testutilitis.AssertStatus(t, resp, http.StatusOK)
+			if total := resp.Header.Get("X-Total-Count"); total != "1" {
+				t.Errorf("❌ %s : 1 produit attendu, X-Total-Count=%s", slug, total)
+			}
+			resp.Body.Close()
+		}
+
+		resp := customerClient.MustDoRequest(t, "GET", "/api/categories/inconnue/products", nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusNotFound)
+	})
+
+	t.Run("Déplacer une catégorie déplace ses descendants", func(t *testing.T) {
+		resp := adminClient.MustDoRequest(t, "PUT", "/api/categories/ordinateurs", map[string]interface{}{
+			"name": "Ordinateurs", "slug": "ordinateurs", "parent_id": booksID,
+		})
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		resp.Body.Close()
+
+		resp = customerClient.MustDoRequest(t, "GET", "/api/categories/portables", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		var got struct {
+			Path string `json:"path"`
+		}
+		testutilitis.ParseJSONBody(t, resp, &got)
+		resp.Body.Close()
+		if got.Path != "livres/ordinateurs/portables" {
+			t.Errorf("❌ Path attendu livres/ordinateurs/portables, obtenu %s", got.Path)
+		}
+
+		resp = customerClient.MustDoRequest(t, "GET", "/api/categories/electronique/products", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		if total := resp.Header.Get("X-Total-Count"); total != "0" {
+			t.Errorf("❌ Plus aucun produit attendu sous électronique, X-Total-Count=%s", total)
+		}
+		resp.Body.Close()
+	})
+
+	t.Run("Pas de cycle", func(t *testing.T) {
+		resp := adminClient.MustDoRequest(t, "PUT", "/api/categories/livres", map[string]interface{}{
+			"name": "Livres", "slug": "livres", "parent_id": laptopsID,
+		})
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusBadRequest)
+	})
+
+	t.Run("Suppression", func(t *testing.T) {
+		resp := adminClient.MustDoRequest(t, "DELETE", "/api/categories/livres", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusConflict)
+		resp.Body.Close()
+
+		resp = adminClient.MustDoRequest(t, "DELETE", "/api/categories/portables", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusNoContent)
+		resp.Body.Close()
+
+		// Le produit reste, détaché de la catégorie supprimée
+		resp = customerClient.MustDoRequest(t, "GET", "/api/categories/ordinateurs/products", nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		if total := resp.Header.Get("X-Total-Count"); total != "0" {
+			t.Errorf("❌ X-Total-Count=0 attendu, obtenu %s", total)
+		}
+	})
+}
@@ -25,26 +25,8 @@ func TestCreateOrderE2E(t *testing.T) {
 	// 🔥 ÉTAPE 0 : S'authentifier avant toute opération
 	uniqueEmail := fmt.Sprintf("test.order.%d.%s@example.com", time.Now().UnixNano(), uuid.New().String()[:6])
 
-	// Inscription
-	userData := map[string]interface{}{
-		"email":    uniqueEmail,
-		"password": "Password123!",
-	}
-	resp := client.MustDoRequest(t, "POST", "/register", userData)
-	resp.Body.Close()
-
-	// Connexion
-	loginData := map[string]interface{}{
-		"email":    uniqueEmail,
-		"password": "Password123!",
-	}
-	resp = client.MustDoRequest(t, "POST", "/login", loginData)
-	var loginResp struct {
-		Token string `json:"token"`
-	}
-	testutilitis.ParseJSONBody(t, resp, &loginResp)
-	resp.Body.Close()
-	client.SetToken(loginResp.Token) // 🔥 Active l'authentification
+	// Inscription + connexion : le scénario crée produits et clients, réservés au back-office
+	testutilitis.RegisterAndLogin(t, client, server.DB, uniqueEmail, "manager")
 
 	// ======================================
 	// ÉTAPE 1 : Créer un customer
@@ -52,7 +34,7 @@ func TestCreateOrderE2E(t *testing.T) {
 	customerReq := testutilitis.CustomerFixture()
 	t.Logf("📋 Customer: %s %s", customerReq["first_name"], customerReq["last_name"])
 
-	resp = client.MustDoRequest(t, "POST", "/api/customers", customerReq)
+	resp := client.MustDoRequest(t, "POST", "/api/customers", customerReq)
 	defer resp.Body.Close()
 	testutilitis.AssertStatus(t, resp, http.StatusCreated)
 
@@ -0,0 +1,178 @@
+// tests/e2e/image_e2e_test.go
+package e2e
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"Goshop/tests/testutilitis"
+)
+
+var pngBytes = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00")
+
+type imageBody struct {
+	ID       string `json:"id"`
+	URL      string `json:"url"`
+	Position int    `json:"position"`
+}
+
+func uploadImage(t *testing.T, client *testutilitis.HTTPClient, productID string, data []byte) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", "photo.png")
+	if err != nil {
+		t.Fatalf("❌ Multipart: %v", err)
+	}
+	part.Write(data)
+	mw.Close()
+
+	req, err := http.NewRequest("POST", client.BaseURL+"/api/products/"+productID+"/images", &body)
+	if err != nil {
+		t.Fatalf("❌ Requête: %v", err)
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	resp, err := client.DoRequestRaw(req)
+	if err != nil {
+		t.Fatalf("❌ Upload: %v", err)
+	}
+	return resp
+}
+
+func TestImageE2E(t *testing.T) {
+	server := testutilitis.NewTestServer(t)
+	suffix := time.Now().UnixNano()
+
+	adminClient := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, adminClient, server.DB, fmt.Sprintf("image.admin.%d@example.com", suffix), "admin")
+
+	customerClient := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, customerClient, server.DB, fmt.Sprintf("image.customer.%d@example.com", suffix))
+
+	resp := adminClient.MustDoRequest(t, "POST", "/api/products", testutilitis.ProductFixture())
+	testutilitis.AssertStatus(t, resp, http.StatusCreated)
+	productID := testutilitis.ExtractID(t, resp)
+	resp.Body.Close()
+
+	t.Run("Un client n'envoie pas d'image", func(t *testing.T) {
+		resp := uploadImage(t, customerClient, productID, pngBytes)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusForbidden)
+	})
+
+	t.Run("Type non supporté", func(t *testing.T) {
+		resp := uploadImage(t, adminClient, productID, []byte("%PDF-1.4 pas une image"))
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusUnsupportedMediaType)
+	})
+
+	var first, second imageBody
+	for _, image := range []*imageBody{&first, &second} {
+		resp := uploadImage(t, adminClient, productID, pngBytes)
+		testutilitis.AssertStatus(t, resp, http.StatusCreated)
+		testutilitis.ParseJSONBody(t, resp, image)
+		resp.Body.Close()
+	}
+	if first.Position != 0 || second.Position != 1 {
+		t.Fatalf("❌ Positions 0 et 1 attendues, obtenu %d et %d", first.Position, second.Position)
+	}
+
+	t.Run("Contenu", func(t *testing.T) {
+		resp := customerClient.MustDoRequest(t, "GET", first.URL, nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		if ct := resp.Header.Get("Content-Type"); ct != "image/png" {
+			t.Errorf("❌ Content-Type image/png attendu, obtenu %q", ct)
+		}
+		data, _ := io.ReadAll(resp.Body)
+		if !bytes.Equal(data, pngBytes) {
+			t.Errorf("❌ Contenu de l'image différent de l'envoi")
+		}
+	})
+
+	t.Run("Image principale et ordre", func(t *testing.T) {
+		resp := adminClient.MustDoRequest(t, "PUT", "/api/products/"+productID+"/images/order", map[string]interface{}{
+			"image_ids": []string{second.ID, first.ID},
+		})
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		resp.Body.Close()
+
+		resp = customerClient.MustDoRequest(t, "GET", "/api/products/"+productID, nil)
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		var product struct {
+			PrimaryImage *imageBody `json:"primary_image"`
+		}
+		testutilitis.ParseJSONBody(t, resp, &product)
+		resp.Body.Close()
+		if product.PrimaryImage == nil || product.PrimaryImage.ID != second.ID {
+			t.Errorf("❌ Image principale %s attendue, obtenu %+v", second.ID, product.PrimaryImage)
+		}
+
+		// Liste incomplète
+		resp = adminClient.MustDoRequest(t, "PUT", "/api/products/"+productID+"/images/order", map[string]interface{}{
+			"image_ids": []string{first.ID},
+		})
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusBadRequest)
+	})
+
+	t.Run("Suppression d'une image", func(t *testing.T) {
+		resp := adminClient.MustDoRequest(t, "DELETE", "/api/products/"+productID+"/images/"+second.ID, nil)
+		testutilitis.AssertStatus(t, resp, http.StatusNoContent)
+		resp.Body.Close()
+
+		resp = customerClient.MustDoRequest(t, "GET", "/api/products/"+productID+"/images", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		var images []imageBody
+		testutilitis.ParseJSONBody(t, resp, &images)
+		resp.Body.Close()
+		if len(images) != 1 || images[0].ID != first.ID || images[0].Position != 0 {
+			t.Errorf("❌ Seule l'image %s attendue, en position 0 : %+v", first.ID, images)
+		}
+	})
+
+	t.Run("Archivage du produit", func(t *testing.T) {
+		resp := adminClient.MustDoRequest(t, "DELETE", "/api/products/"+productID, nil)
+		testutilitis.AssertStatus(t, resp, http.StatusNoContent)
+		resp.Body.Close()
+
+		// Images conservées pour une restauration
+		entries, err := os.ReadDir(filepath.Join(server.BlobDir, "products", productID))
+		if err != nil || len(entries) == 0 {
+			t.Errorf("❌ Contenus des images supprimés à l'archivage (%v)", err)
+		}
+
+		resp = uploadImage(t, adminClient, productID, pngBytes)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusNotFound)
+	})
+
+	t.Run("Purge du produit archivé", func(t *testing.T) {
+		resp := adminClient.MustDoRequest(t, "DELETE", "/api/products/"+productID+"/purge", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		var purge struct {
+			ProductDeleted bool `json:"product_deleted"`
+		}
+		testutilitis.ParseJSONBody(t, resp, &purge)
+		resp.Body.Close()
+		if !purge.ProductDeleted {
+			t.Errorf("❌ Produit jamais commandé : suppression attendue")
+		}
+
+		entries, err := os.ReadDir(filepath.Join(server.BlobDir, "products", productID))
+		if err == nil && len(entries) > 0 {
+			t.Errorf("❌ Contenus des images toujours présents : %d fichier(s)", len(entries))
+		}
+
+		resp = adminClient.MustDoRequest(t, "GET", "/api/products/"+productID+"?include_deleted=true", nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,69 @@
+// tests/e2e/login_guard_e2e_test.go
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"Goshop/tests/testutilitis"
+)
+
+func TestLoginBruteForceE2E(t *testing.T) {
+	server := testutilitis.NewTestServer(t)
+	suffix := time.Now().UnixNano()
+
+	email := fmt.Sprintf("bruteforce.%d@example.com", suffix)
+	adminEmail := fmt.Sprintf("bruteforce.admin.%d@example.com", suffix)
+
+	client := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, client, server.DB, email)
+
+	adminClient := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, adminClient, server.DB, adminEmail, "admin")
+
+	login := func(password string) *http.Response {
+		return client.MustDoRequest(t, "POST", "/login", map[string]string{
+			"email":    email,
+			"password": password,
+		})
+	}
+
+	t.Run("Les premiers échecs répondent 401", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			resp := login("wrong-password")
+			testutilitis.AssertStatus(t, resp, http.StatusUnauthorized)
+			resp.Body.Close()
+		}
+	})
+
+	t.Run("Une nouvelle tentative immédiate est retardée, même avec le bon mot de passe", func(t *testing.T) {
+		resp := login(testutilitis.DefaultTestPassword)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusTooManyRequests)
+		if resp.Header.Get("Retry-After") == "" {
+			t.Error("❌ En-tête Retry-After attendu")
+		}
+	})
+
+	t.Run("Un email inconnu est traité comme un compte existant", func(t *testing.T) {
+		resp := client.MustDoRequest(t, "POST", "/login", map[string]string{
+			"email":    fmt.Sprintf("nobody.%d@example.com", suffix),
+			"password": "wrong-password",
+		})
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusUnauthorized)
+	})
+
+	t.Run("Un admin déverrouille le compte", func(t *testing.T) {
+		userID := testutilitis.UserIDByEmail(t, server.DB, email)
+		resp := adminClient.MustDoRequest(t, "POST", "/api/admin/users/"+userID+"/unlock", nil)
+		resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusNoContent)
+
+		resp = login(testutilitis.DefaultTestPassword)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+	})
+}
@@ -0,0 +1,120 @@
+// tests/e2e/mfa_e2e_test.go
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"Goshop/tests/testutilitis"
+)
+
+// loginPendingMFA : login par mot de passe d'un compte à deux facteurs
+func loginPendingMFA(t *testing.T, client *testutilitis.HTTPClient, email string) string {
+	t.Helper()
+	resp := client.MustDoRequest(t, "POST", "/login", map[string]string{"email": email, "password": testutilitis.DefaultTestPassword})
+	testutilitis.AssertStatus(t, resp, http.StatusOK)
+	var body struct {
+		MFARequired bool   `json:"mfa_required"`
+		MFAToken    string `json:"mfa_token"`
+		AccessToken string `json:"access_token"`
+	}
+	testutilitis.ParseJSONBody(t, resp, &body)
+	resp.Body.Close()
+	if !body.MFARequired || body.MFAToken == "" || body.AccessToken != "" {
+		t.Fatalf("❌ Second facteur attendu, réponse: %+v", body)
+	}
+	return body.MFAToken
+}
+
+func TestMFAE2E(t *testing.T) {
+	server := testutilitis.NewTestServer(t)
+	email := fmt.Sprintf("mfa.%d@example.com", time.Now().UnixNano())
+
+	client := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, client, server.DB, email)
+
+	// 1. Enrôlement
+	resp := client.MustDoRequest(t, "POST", "/auth/me/mfa", nil)
+	testutilitis.AssertStatus(t, resp, http.StatusOK)
+	var enrollment struct {
+		Secret     string `json:"secret"`
+		OTPAuthURI string `json:"otpauth_uri"`
+	}
+	testutilitis.ParseJSONBody(t, resp, &enrollment)
+	resp.Body.Close()
+	if enrollment.Secret == "" || enrollment.OTPAuthURI == "" {
+		t.Fatalf("❌ Enrôlement incomplet: %+v", enrollment)
+	}
+
+	// Tant que l'enrôlement n'est pas confirmé, le mot de passe suffit
+	loginTokens(t, testutilitis.NewHTTPClient(server.URL), email, testutilitis.DefaultTestPassword)
+
+	// 2. Confirmation avec un premier code
+	resp = client.MustDoRequest(t, "POST", "/auth/me/mfa/confirm", map[string]string{"code": "000000"})
+	testutilitis.AssertStatus(t, resp, http.StatusBadRequest)
+	resp.Body.Close()
+
+	resp = client.MustDoRequest(t, "POST", "/auth/me/mfa/confirm", map[string]string{
+		"code": testutilitis.TOTPCode(t, enrollment.Secret, time.Now()),
+	})
+	testutilitis.AssertStatus(t, resp, http.StatusOK)
+	var confirmed struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+	testutilitis.ParseJSONBody(t, resp, &confirmed)
+	resp.Body.Close()
+	if len(confirmed.RecoveryCodes) != 10 {
+		t.Fatalf("❌ 10 codes de récupération attendus, reçu %d", len(confirmed.RecoveryCodes))
+	}
+
+	t.Run("Login en deux étapes", func(t *testing.T) {
+		anon := testutilitis.NewHTTPClient(server.URL)
+		mfaToken := loginPendingMFA(t, anon, email)
+
+		// Le token intermédiaire n'ouvre pas les routes protégées
+		anon.SetToken(mfaToken)
+		resp := anon.MustDoRequest(t, "GET", "/auth/me", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusUnauthorized)
+		resp.Body.Close()
+		anon.SetToken("")
+
+		resp = anon.MustDoRequest(t, "POST", "/login/mfa", map[string]string{"mfa_token": mfaToken, "code": "000000"})
+		testutilitis.AssertStatus(t, resp, http.StatusUnauthorized)
+		resp.Body.Close()
+
+		// Le code de la confirmation a déjà servi : celui de la période suivante
+		// est accepté (tolérance d'une période)
+		code := testutilitis.TOTPCode(t, enrollment.Secret, time.Now().Add(30*time.Second))
+		resp = anon.MustDoRequest(t, "POST", "/login/mfa", map[string]string{"mfa_token": mfaToken, "code": code})
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		resp.Body.Close()
+
+		// Rejeu du même code refusé
+		resp = anon.MustDoRequest(t, "POST", "/login/mfa", map[string]string{"mfa_token": mfaToken, "code": code})
+		testutilitis.AssertStatus(t, resp, http.StatusUnauthorized)
+		resp.Body.Close()
+	})
+
+	t.Run("Code de récupération à usage unique", func(t *testing.T) {
+		anon := testutilitis.NewHTTPClient(server.URL)
+		recovery := confirmed.RecoveryCodes[0]
+
+		mfaToken := loginPendingMFA(t, anon, email)
+		resp := anon.MustDoRequest(t, "POST", "/login/mfa", map[string]string{"mfa_token": mfaToken, "code": recovery})
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		resp.Body.Close()
+
+		mfaToken = loginPendingMFA(t, anon, email)
+		resp = anon.MustDoRequest(t, "POST", "/login/mfa", map[string]string{"mfa_token": mfaToken, "code": recovery})
+		testutilitis.AssertStatus(t, resp, http.StatusUnauthorized)
+		resp.Body.Close()
+	})
+
+	t.Run("Nouvel enrôlement refusé une fois actif", func(t *testing.T) {
+		resp := client.MustDoRequest(t, "POST", "/auth/me/mfa", nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusConflict)
+	})
+}
@@ -0,0 +1,159 @@
+// tests/e2e/ownership_e2e_test.go
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"Goshop/tests/testutilitis"
+)
+
+func TestOwnershipE2E(t *testing.T) {
+	server := testutilitis.NewTestServer(t)
+	suffix := time.Now().UnixNano()
+
+	aliceEmail := fmt.Sprintf("owner.alice.%d@example.com", suffix)
+	bobEmail := fmt.Sprintf("owner.bob.%d@example.com", suffix)
+	managerEmail := fmt.Sprintf("owner.manager.%d@example.com", suffix)
+
+	alice := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, alice, server.DB, aliceEmail)
+	bob := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, bob, server.DB, bobEmail)
+	manager := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, manager, server.DB, managerEmail, "manager")
+
+	// L'inscription crée le profil client rattaché au compte
+	aliceCustomerID := testutilitis.CustomerIDByEmail(t, server.DB, aliceEmail)
+
+	resp := manager.MustDoRequest(t, "POST", "/api/products", testutilitis.ProductFixture())
+	testutilitis.AssertStatus(t, resp, http.StatusCreated)
+	productID := testutilitis.ExtractID(t, resp)
+	resp.Body.Close()
+
+	var orderID string
+	t.Run("Une commande sans customer_id est passée pour le client connecté", func(t *testing.T) {
+		resp := alice.MustDoRequest(t, "POST", "/api/orders", map[string]interface{}{
+			"items": []map[string]interface{}{{"product_id": productID, "quantity": 1}},
+		})
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusCreated)
+
+		var order map[string]interface{}
+		testutilitis.ParseJSONBody(t, resp, &order)
+		orderID, _ = order["id"].(string)
+		if order["customer_id"] != aliceCustomerID {
+			t.Errorf("❌ customer_id attendu %s, obtenu %v", aliceCustomerID, order["customer_id"])
+		}
+	})
+
+	t.Run("Un client ne commande pas pour un autre", func(t *testing.T) {
+		resp := bob.MustDoRequest(t, "POST", "/api/orders", testutilitis.OrderFixture(aliceCustomerID, productID))
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusForbidden)
+	})
+
+	t.Run("La commande d'un autre client est invisible", func(t *testing.T) {
+		resp := bob.MustDoRequest(t, "GET", "/api/orders/"+orderID, nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusNotFound)
+	})
+
+	t.Run("Le profil d'un autre client est invisible", func(t *testing.T) {
+		resp := bob.MustDoRequest(t, "GET", "/api/customers/"+aliceCustomerID, nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusNotFound)
+	})
+
+	t.Run("GET /api/me/orders ne liste que ses commandes", func(t *testing.T) {
+		for client, expected := range map[*testutilitis.HTTPClient]int{alice: 1, bob: 0} {
+			resp := client.MustDoRequest(t, "GET", "/api/me/orders", nil)
+			testutilitis.AssertStatus(t, resp, http.StatusOK)
+			var orders []map[string]interface{}
+			testutilitis.ParseJSONBody(t, resp, &orders)
+			resp.Body.Close()
+			if len(orders) != expected {
+				t.Errorf("❌ %d commande(s) attendue(s), obtenu %d", expected, len(orders))
+			}
+		}
+	})
+
+	t.Run("GET /api/orders est limité au client connecté", func(t *testing.T) {
+		resp := bob.MustDoRequest(t, "GET", "/api/orders?limit=10", nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		if total := resp.Header.Get("X-Total-Count"); total != "0" {
+			t.Errorf("❌ X-Total-Count attendu 0, obtenu %s", total)
+		}
+	})
+
+	t.Run("Un manager voit toutes les commandes", func(t *testing.T) {
+		resp := manager.MustDoRequest(t, "GET", "/api/orders/"+orderID, nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+	})
+}
+
+func TestCustomerLinkOnEmailVerificationE2E(t *testing.T) {
+	server := testutilitis.NewTestServer(t)
+	suffix := time.Now().UnixNano()
+	email := fmt.Sprintf("link.customer.%d@example.com", suffix)
+
+	manager := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, manager, server.DB, fmt.Sprintf("link.manager.%d@example.com", suffix), "manager")
+
+	// Client créé par le back-office, avec une commande
+	resp := manager.MustDoRequest(t, "POST", "/api/customers", testutilitis.CustomerFixtureWithEmail(email))
+	testutilitis.AssertStatus(t, resp, http.StatusCreated)
+	customerID := testutilitis.ExtractID(t, resp)
+	resp.Body.Close()
+
+	resp = manager.MustDoRequest(t, "POST", "/api/products", testutilitis.ProductFixture())
+	testutilitis.AssertStatus(t, resp, http.StatusCreated)
+	productID := testutilitis.ExtractID(t, resp)
+	resp.Body.Close()
+
+	resp = manager.MustDoRequest(t, "POST", "/api/orders", testutilitis.OrderFixture(customerID, productID))
+	testutilitis.AssertStatus(t, resp, http.StatusCreated)
+	resp.Body.Close()
+
+	owner := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, owner, server.DB, email)
+
+	t.Run("Email non confirmé : le client existant reste inaccessible", func(t *testing.T) {
+		resp := owner.MustDoRequest(t, "GET", "/api/customers/"+customerID, nil)
+		testutilitis.AssertStatus(t, resp, http.StatusNotFound)
+		resp.Body.Close()
+
+		resp = owner.MustDoRequest(t, "GET", "/api/me/orders", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		var orders []map[string]interface{}
+		testutilitis.ParseJSONBody(t, resp, &orders)
+		resp.Body.Close()
+		if len(orders) != 0 {
+			t.Errorf("❌ Aucune commande attendue avant confirmation, obtenu %d", len(orders))
+		}
+	})
+
+	t.Run("Email confirmé : le client existant est rattaché", func(t *testing.T) {
+		token := testutilitis.MailToken(t, server.MailDir, email, "/verify-email")
+		resp := owner.MustDoRequest(t, "POST", "/auth/email/verify", map[string]string{"token": token})
+		testutilitis.AssertStatus(t, resp, http.StatusNoContent)
+		resp.Body.Close()
+
+		if linked := testutilitis.CustomerIDByEmail(t, server.DB, email); linked != customerID {
+			t.Errorf("❌ Client %s attendu, profil rattaché %s", customerID, linked)
+		}
+
+		resp = owner.MustDoRequest(t, "GET", "/api/me/orders", nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		var orders []map[string]interface{}
+		testutilitis.ParseJSONBody(t, resp, &orders)
+		if len(orders) != 1 {
+			t.Errorf("❌ 1 commande attendue après rattachement, obtenu %d", len(orders))
+		}
+	})
+}
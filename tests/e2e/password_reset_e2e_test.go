@@ -0,0 +1,121 @@
+// tests/e2e/password_reset_e2e_test.go
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"Goshop/tests/testutilitis"
+)
+
+func TestPasswordResetE2E(t *testing.T) {
+	server := testutilitis.NewTestServer(t)
+	email := fmt.Sprintf("reset.%d@example.com", time.Now().UnixNano())
+	newPassword := "N3w-Password!"
+
+	client := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, client, server.DB, email)
+
+	// Refresh token de la session ouverte avant la réinitialisation
+	resp := client.MustDoRequest(t, "POST", "/login", map[string]string{
+		"email":    email,
+		"password": testutilitis.DefaultTestPassword,
+	})
+	testutilitis.AssertStatus(t, resp, http.StatusOK)
+	var login struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	testutilitis.ParseJSONBody(t, resp, &login)
+	resp.Body.Close()
+
+	t.Run("Mot de passe oublié : même réponse pour un email inconnu", func(t *testing.T) {
+		for _, target := range []string{email, "nobody." + email} {
+			resp := client.MustDoRequest(t, "POST", "/auth/password/forgot", map[string]string{"email": target})
+			testutilitis.AssertStatus(t, resp, http.StatusAccepted)
+			resp.Body.Close()
+		}
+	})
+
+	token := testutilitis.MailToken(t, server.MailDir, email, "/reset-password")
+
+	t.Run("Le lien change le mot de passe et révoque les sessions", func(t *testing.T) {
+		resp := client.MustDoRequest(t, "POST", "/auth/password/reset", map[string]string{
+			"token":    token,
+			"password": newPassword,
+		})
+		testutilitis.AssertStatus(t, resp, http.StatusNoContent)
+		resp.Body.Close()
+
+		resp = client.MustDoRequest(t, "POST", "/auth/refresh", map[string]string{"refresh_token": login.RefreshToken})
+		testutilitis.AssertStatus(t, resp, http.StatusUnauthorized)
+		resp.Body.Close()
+
+		resp = client.MustDoRequest(t, "POST", "/login", map[string]string{"email": email, "password": newPassword})
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		resp.Body.Close()
+	})
+
+	t.Run("Le lien ne sert qu'une fois", func(t *testing.T) {
+		resp := client.MustDoRequest(t, "POST", "/auth/password/reset", map[string]string{
+			"token":    token,
+			"password": "Another-Passw0rd",
+		})
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusBadRequest)
+	})
+}
+
+func TestPasswordResetMixedCaseEmailE2E(t *testing.T) {
+	server := testutilitis.NewTestServer(t)
+	typed := fmt.Sprintf("Reset.Mixed.%d@Example.COM", time.Now().UnixNano())
+	stored := strings.ToLower(typed)
+
+	client := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, client, server.DB, typed)
+
+	// Demande faite avec une autre casse que celle de l'inscription
+	resp := client.MustDoRequest(t, "POST", "/auth/password/forgot", map[string]string{"email": strings.ToUpper(typed)})
+	testutilitis.AssertStatus(t, resp, http.StatusAccepted)
+	resp.Body.Close()
+
+	token := testutilitis.MailToken(t, server.MailDir, stored, "/reset-password")
+
+	resp = client.MustDoRequest(t, "POST", "/auth/password/reset", map[string]string{
+		"token":    token,
+		"password": "N3w-Password!",
+	})
+	testutilitis.AssertStatus(t, resp, http.StatusNoContent)
+	resp.Body.Close()
+
+	resp = client.MustDoRequest(t, "POST", "/login", map[string]string{"email": typed, "password": "N3w-Password!"})
+	defer resp.Body.Close()
+	testutilitis.AssertStatus(t, resp, http.StatusOK)
+}
+
+func TestEmailVerificationE2E(t *testing.T) {
+	server := testutilitis.NewTestServer(t)
+	email := fmt.Sprintf("verify.%d@example.com", time.Now().UnixNano())
+
+	client := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, client, server.DB, email)
+
+	// Le lien est envoyé à l'inscription
+	token := testutilitis.MailToken(t, server.MailDir, email, "/verify-email")
+
+	resp := client.MustDoRequest(t, "POST", "/auth/email/verify", map[string]string{"token": token})
+	testutilitis.AssertStatus(t, resp, http.StatusNoContent)
+	resp.Body.Close()
+
+	var verified bool
+	err := server.DB.QueryRow(`SELECT email_verified_at IS NOT NULL FROM users WHERE email = $1`, email).Scan(&verified)
+	if err != nil || !verified {
+		t.Fatalf("❌ Email non marqué vérifié (err=%v)", err)
+	}
+
+	resp = client.MustDoRequest(t, "POST", "/auth/email/verify", map[string]string{"token": token})
+	defer resp.Body.Close()
+	testutilitis.AssertStatus(t, resp, http.StatusBadRequest)
+}
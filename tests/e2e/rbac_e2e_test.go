@@ -0,0 +1,97 @@
+// tests/e2e/rbac_e2e_test.go
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"Goshop/tests/testutilitis"
+)
+
+func TestRBACE2E(t *testing.T) {
+	server := testutilitis.NewTestServer(t)
+	suffix := time.Now().UnixNano()
+
+	customerEmail := fmt.Sprintf("rbac.customer.%d@example.com", suffix)
+	adminEmail := fmt.Sprintf("rbac.admin.%d@example.com", suffix)
+
+	customerClient := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, customerClient, server.DB, customerEmail)
+
+	adminClient := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, adminClient, server.DB, adminEmail, "admin")
+
+	customerID := testutilitis.UserIDByEmail(t, server.DB, customerEmail)
+	rolesPath := "/api/admin/users/" + customerID + "/roles"
+
+	t.Run("Un client ne peut pas créer de produit", func(t *testing.T) {
+		resp := customerClient.MustDoRequest(t, "POST", "/api/products", testutilitis.ProductFixture())
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusForbidden)
+	})
+
+	t.Run("Un client peut consulter le catalogue", func(t *testing.T) {
+		resp := customerClient.MustDoRequest(t, "GET", "/api/products", nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+	})
+
+	t.Run("Un client n'accède pas à l'administration", func(t *testing.T) {
+		resp := customerClient.MustDoRequest(t, "GET", rolesPath, nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusForbidden)
+	})
+
+	t.Run("Un admin crée un produit", func(t *testing.T) {
+		resp := adminClient.MustDoRequest(t, "POST", "/api/products", testutilitis.ProductFixture())
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusCreated)
+	})
+
+	t.Run("Un admin promeut le client manager", func(t *testing.T) {
+		resp := adminClient.MustDoRequest(t, "POST", rolesPath, map[string]string{"role": "manager"})
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+
+		var roles struct {
+			Roles       []string `json:"roles"`
+			Permissions []string `json:"permissions"`
+		}
+		testutilitis.ParseJSONBody(t, resp, &roles)
+		if len(roles.Roles) != 2 {
+			t.Errorf("❌ Rôles attendus: customer + manager, obtenus: %v", roles.Roles)
+		}
+	})
+
+	t.Run("Le nouveau rôle s'applique au prochain login", func(t *testing.T) {
+		resp := customerClient.MustDoRequest(t, "POST", "/login", map[string]string{
+			"email":    customerEmail,
+			"password": testutilitis.DefaultTestPassword,
+		})
+		var loginResp struct {
+			Token string `json:"token"`
+		}
+		testutilitis.ParseJSONBody(t, resp, &loginResp)
+		resp.Body.Close()
+		customerClient.SetToken(loginResp.Token)
+
+		resp = customerClient.MustDoRequest(t, "POST", "/api/products", testutilitis.ProductFixture())
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusCreated)
+	})
+
+	t.Run("Rôle inconnu", func(t *testing.T) {
+		resp := adminClient.MustDoRequest(t, "POST", rolesPath, map[string]string{"role": "superuser"})
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusBadRequest)
+	})
+
+	t.Run("Un admin ne peut pas retirer son propre rôle admin", func(t *testing.T) {
+		adminID := testutilitis.UserIDByEmail(t, server.DB, adminEmail)
+		resp := adminClient.MustDoRequest(t, "DELETE", "/api/admin/users/"+adminID+"/roles/admin", nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusConflict)
+	})
+}
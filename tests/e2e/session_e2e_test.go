@@ -0,0 +1,193 @@
+// tests/e2e/session_e2e_test.go
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"Goshop/tests/testutilitis"
+)
+
+type tokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// loginPair se connecte et retourne la paire access / refresh
+func loginPair(t *testing.T, client *testutilitis.HTTPClient, email string) tokenPair {
+	t.Helper()
+
+	resp := client.MustDoRequest(t, "POST", "/login", map[string]string{
+		"email":    email,
+		"password": testutilitis.DefaultTestPassword,
+	})
+	defer resp.Body.Close()
+	testutilitis.AssertStatus(t, resp, http.StatusOK)
+
+	var pair tokenPair
+	testutilitis.ParseJSONBody(t, resp, &pair)
+	if pair.AccessToken == "" || pair.RefreshToken == "" {
+		t.Fatalf("❌ Paire de tokens incomplète: %+v", pair)
+	}
+	return pair
+}
+
+func TestSessionLogoutE2E(t *testing.T) {
+	server := testutilitis.NewTestServer(t)
+	email := fmt.Sprintf("session.%d@example.com", time.Now().UnixNano())
+
+	client := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, client, server.DB, email)
+
+	refresh := func(token string) int {
+		resp := client.MustDoRequest(t, "POST", "/auth/refresh", map[string]string{"refresh_token": token})
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	t.Run("Le login ouvre une session utilisable par /auth/refresh", func(t *testing.T) {
+		pair := loginPair(t, client, email)
+		if code := refresh(pair.RefreshToken); code != http.StatusOK {
+			t.Errorf("❌ Refresh attendu 200, obtenu %d", code)
+		}
+	})
+
+	t.Run("Le rejeu d'un refresh token tourné révoque toute la famille", func(t *testing.T) {
+		pair := loginPair(t, client, email)
+
+		resp := client.MustDoRequest(t, "POST", "/auth/refresh", map[string]string{"refresh_token": pair.RefreshToken})
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		var rotated tokenPair
+		testutilitis.ParseJSONBody(t, resp, &rotated)
+		resp.Body.Close()
+
+		// L'ancien token est rejoué (vol supposé)
+		if code := refresh(pair.RefreshToken); code != http.StatusUnauthorized {
+			t.Errorf("❌ Rejeu attendu 401, obtenu %d", code)
+		}
+		// La session issue de la rotation est révoquée avec sa famille
+		if code := refresh(rotated.RefreshToken); code != http.StatusUnauthorized {
+			t.Errorf("❌ Refresh de la famille révoquée attendu 401, obtenu %d", code)
+		}
+	})
+
+	t.Run("Logout révoque la session courante", func(t *testing.T) {
+		pair := loginPair(t, client, email)
+		client.SetToken(pair.AccessToken)
+
+		resp := client.MustDoRequest(t, "POST", "/auth/logout", map[string]string{"refresh_token": pair.RefreshToken})
+		resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusNoContent)
+
+		if code := refresh(pair.RefreshToken); code != http.StatusUnauthorized {
+			t.Errorf("❌ Refresh après logout attendu 401, obtenu %d", code)
+		}
+	})
+
+	t.Run("Logout-all révoque toutes les sessions", func(t *testing.T) {
+		first := loginPair(t, client, email)
+		second := loginPair(t, client, email)
+		client.SetToken(second.AccessToken)
+
+		resp := client.MustDoRequest(t, "POST", "/auth/logout-all", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		var body struct {
+			RevokedSessions int `json:"revoked_sessions"`
+		}
+		testutilitis.ParseJSONBody(t, resp, &body)
+		resp.Body.Close()
+		if body.RevokedSessions < 2 {
+			t.Errorf("❌ Au moins 2 sessions révoquées attendues, obtenu %d", body.RevokedSessions)
+		}
+
+		for _, pair := range []tokenPair{first, second} {
+			if code := refresh(pair.RefreshToken); code != http.StatusUnauthorized {
+				t.Errorf("❌ Refresh après logout-all attendu 401, obtenu %d", code)
+			}
+		}
+	})
+}
+
+func TestActiveSessionsE2E(t *testing.T) {
+	server := testutilitis.NewTestServer(t)
+	email := fmt.Sprintf("devices.%d@example.com", time.Now().UnixNano())
+	otherEmail := fmt.Sprintf("devices.other.%d@example.com", time.Now().UnixNano())
+
+	client := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, client, server.DB, otherEmail)
+	other := loginPair(t, client, otherEmail)
+	testutilitis.RegisterAndLogin(t, client, server.DB, email)
+
+	type session struct {
+		ID         string    `json:"id"`
+		LastUsedAt time.Time `json:"last_used_at"`
+		UserAgent  string    `json:"user_agent"`
+		IPAddress  string    `json:"ip_address"`
+	}
+	listSessions := func(token string) []session {
+		client.SetToken(token)
+		resp := client.MustDoRequest(t, "GET", "/auth/sessions", nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		var body struct {
+			Sessions []session `json:"sessions"`
+		}
+		testutilitis.ParseJSONBody(t, resp, &body)
+		return body.Sessions
+	}
+
+	first := loginPair(t, client, email)
+	second := loginPair(t, client, email)
+
+	sessions := listSessions(second.AccessToken)
+	if len(sessions) < 2 {
+		t.Fatalf("❌ Au moins 2 sessions actives attendues, obtenu %d", len(sessions))
+	}
+	for _, s := range sessions {
+		if s.UserAgent == "" || s.IPAddress == "" || s.LastUsedAt.IsZero() {
+			t.Errorf("❌ Appareil non renseigné pour la session %s: %+v", s.ID, s)
+		}
+	}
+
+	t.Run("Une session d'un autre utilisateur est introuvable", func(t *testing.T) {
+		otherSessions := listSessions(other.AccessToken)
+		if len(otherSessions) == 0 {
+			t.Fatal("❌ Aucune session pour l'autre utilisateur")
+		}
+		client.SetToken(second.AccessToken)
+		resp := client.MustDoRequest(t, "DELETE", "/auth/sessions/"+otherSessions[0].ID, nil)
+		resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusNotFound)
+	})
+
+	t.Run("DELETE révoque l'appareil ciblé uniquement", func(t *testing.T) {
+		// La session la plus récente est celle du second login
+		target := listSessions(second.AccessToken)[1].ID
+
+		client.SetToken(second.AccessToken)
+		resp := client.MustDoRequest(t, "DELETE", "/auth/sessions/"+target, nil)
+		resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusNoContent)
+
+		for _, s := range listSessions(second.AccessToken) {
+			if s.ID == target {
+				t.Errorf("❌ Session %s toujours listée après révocation", target)
+			}
+		}
+
+		resp = client.MustDoRequest(t, "POST", "/auth/refresh", map[string]string{"refresh_token": first.RefreshToken})
+		resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusUnauthorized)
+
+		resp = client.MustDoRequest(t, "POST", "/auth/refresh", map[string]string{"refresh_token": second.RefreshToken})
+		resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+
+		client.SetToken(second.AccessToken)
+		resp = client.MustDoRequest(t, "DELETE", "/auth/sessions/"+target, nil)
+		resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,164 @@
+// tests/e2e/soft_delete_e2e_test.go
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"Goshop/tests/testutilitis"
+)
+
+type archivedBody struct {
+	ID        string  `json:"id"`
+	DeletedAt *string `json:"deleted_at"`
+}
+
+func TestSoftDeleteE2E(t *testing.T) {
+	server := testutilitis.NewTestServer(t)
+	suffix := time.Now().UnixNano()
+
+	adminClient := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, adminClient, server.DB, fmt.Sprintf("archive.admin.%d@example.com", suffix), "admin")
+
+	customerEmail := fmt.Sprintf("archive.customer.%d@example.com", suffix)
+	customerClient := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, customerClient, server.DB, customerEmail)
+	customerID := testutilitis.CustomerIDByEmail(t, server.DB, customerEmail)
+
+	resp := adminClient.MustDoRequest(t, "POST", "/api/products", testutilitis.ProductFixture())
+	testutilitis.AssertStatus(t, resp, http.StatusCreated)
+	productID := testutilitis.ExtractID(t, resp)
+	resp.Body.Close()
+
+	resp = customerClient.MustDoRequest(t, "POST", "/api/orders", map[string]interface{}{
+		"items": []map[string]interface{}{{"product_id": productID, "quantity": 1}},
+	})
+	testutilitis.AssertStatus(t, resp, http.StatusCreated)
+	orderID := testutilitis.ExtractID(t, resp)
+	resp.Body.Close()
+
+	t.Run("Archivage d'un produit commandé", func(t *testing.T) {
+		resp := adminClient.MustDoRequest(t, "DELETE", "/api/products/"+productID, nil)
+		testutilitis.AssertStatus(t, resp, http.StatusNoContent)
+		resp.Body.Close()
+
+		resp = customerClient.MustDoRequest(t, "GET", "/api/products/"+productID, nil)
+		testutilitis.AssertStatus(t, resp, http.StatusNotFound)
+		resp.Body.Close()
+
+		resp = adminClient.MustDoRequest(t, "DELETE", "/api/products/"+productID, nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusNotFound)
+	})
+
+	t.Run("Produit archivé : plus de commande", func(t *testing.T) {
+		resp := customerClient.MustDoRequest(t, "POST", "/api/orders", map[string]interface{}{
+			"items": []map[string]interface{}{{"product_id": productID, "quantity": 1}},
+		})
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusNotFound)
+	})
+
+	t.Run("include_deleted réservé au back-office", func(t *testing.T) {
+		resp := customerClient.MustDoRequest(t, "GET", "/api/products?include_deleted=true", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusForbidden)
+		resp.Body.Close()
+
+		resp = adminClient.MustDoRequest(t, "GET", "/api/products/"+productID+"?include_deleted=true", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		var product archivedBody
+		testutilitis.ParseJSONBody(t, resp, &product)
+		resp.Body.Close()
+		if product.DeletedAt == nil {
+			t.Errorf("❌ deleted_at attendu pour un produit archivé")
+		}
+
+		resp = adminClient.MustDoRequest(t, "GET", "/api/products?include_deleted=true&limit=100", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		var products []archivedBody
+		testutilitis.ParseJSONBody(t, resp, &products)
+		resp.Body.Close()
+		found := false
+		for _, p := range products {
+			found = found || p.ID == productID
+		}
+		if !found {
+			t.Errorf("❌ Produit archivé absent de la liste include_deleted=true")
+		}
+	})
+
+	t.Run("Restauration du produit", func(t *testing.T) {
+		resp := adminClient.MustDoRequest(t, "POST", "/api/products/"+productID+"/restore", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		resp.Body.Close()
+
+		resp = customerClient.MustDoRequest(t, "GET", "/api/products/"+productID, nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+	})
+
+	t.Run("Archivage d'un client : commandes conservées", func(t *testing.T) {
+		resp := adminClient.MustDoRequest(t, "DELETE", "/api/customers/"+customerID, nil)
+		testutilitis.AssertStatus(t, resp, http.StatusNoContent)
+		resp.Body.Close()
+
+		resp = adminClient.MustDoRequest(t, "GET", "/api/customers/"+customerID, nil)
+		testutilitis.AssertStatus(t, resp, http.StatusNotFound)
+		resp.Body.Close()
+
+		resp = adminClient.MustDoRequest(t, "GET", "/api/customers/"+customerID+"?include_deleted=true", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		var customer archivedBody
+		testutilitis.ParseJSONBody(t, resp, &customer)
+		resp.Body.Close()
+		if customer.DeletedAt == nil {
+			t.Errorf("❌ deleted_at attendu pour un client archivé")
+		}
+
+		resp = adminClient.MustDoRequest(t, "GET", "/api/orders/"+orderID, nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+	})
+
+	t.Run("Restauration du client", func(t *testing.T) {
+		resp := adminClient.MustDoRequest(t, "POST", "/api/customers/"+customerID+"/restore", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		resp.Body.Close()
+
+		resp = customerClient.MustDoRequest(t, "GET", "/api/customers/"+customerID, nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+	})
+
+	t.Run("Purge d'un produit commandé : ligne conservée", func(t *testing.T) {
+		// Un produit actif doit d'abord être archivé
+		resp := adminClient.MustDoRequest(t, "DELETE", "/api/products/"+productID+"/purge", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusConflict)
+		resp.Body.Close()
+
+		resp = adminClient.MustDoRequest(t, "DELETE", "/api/products/"+productID, nil)
+		testutilitis.AssertStatus(t, resp, http.StatusNoContent)
+		resp.Body.Close()
+
+		resp = adminClient.MustDoRequest(t, "DELETE", "/api/products/"+productID+"/purge", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		var purge struct {
+			ProductDeleted bool `json:"product_deleted"`
+		}
+		testutilitis.ParseJSONBody(t, resp, &purge)
+		resp.Body.Close()
+		if purge.ProductDeleted {
+			t.Errorf("❌ Un produit commandé ne doit pas être supprimé")
+		}
+
+		resp = adminClient.MustDoRequest(t, "GET", "/api/products/"+productID+"?include_deleted=true", nil)
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		resp.Body.Close()
+
+		resp = adminClient.MustDoRequest(t, "GET", "/api/orders/"+orderID, nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+	})
+}
@@ -0,0 +1,157 @@
+// tests/e2e/variant_e2e_test.go
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"Goshop/tests/testutilitis"
+)
+
+type variantBody struct {
+	ID         string `json:"id"`
+	SKU        string `json:"sku"`
+	PriceCents int64  `json:"price_cents"`
+	Stock      int    `json:"stock"`
+	IsDefault  bool   `json:"is_default"`
+}
+
+type productWithVariants struct {
+	Stock    int           `json:"stock"`
+	Variants []variantBody `json:"variants"`
+}
+
+func getProductWithVariants(t *testing.T, client *testutilitis.HTTPClient, productID string) productWithVariants {
+	t.Helper()
+	resp := client.MustDoRequest(t, "GET", "/api/products/"+productID, nil)
+	testutilitis.AssertStatus(t, resp, http.StatusOK)
+	defer resp.Body.Close()
+
+	var got productWithVariants
+	testutilitis.ParseJSONBody(t, resp, &got)
+	return got
+}
+
+func TestVariantE2E(t *testing.T) {
+	server := testutilitis.NewTestServer(t)
+	suffix := time.Now().UnixNano()
+
+	adminClient := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, adminClient, server.DB, fmt.Sprintf("variant.admin.%d@example.com", suffix), "admin")
+
+	customerClient := testutilitis.NewHTTPClient(server.URL)
+	testutilitis.RegisterAndLogin(t, customerClient, server.DB, fmt.Sprintf("variant.customer.%d@example.com", suffix))
+
+	product := testutilitis.ProductFixture()
+	product["sku"] = fmt.Sprintf("TSHIRT-%d", suffix)
+	product["price_cents"] = 2000
+	product["stock"] = 3
+	resp := adminClient.MustDoRequest(t, "POST", "/api/products", product)
+	testutilitis.AssertStatus(t, resp, http.StatusCreated)
+	productID := testutilitis.ExtractID(t, resp)
+	resp.Body.Close()
+
+	created := getProductWithVariants(t, customerClient, productID)
+	if len(created.Variants) != 1 || !created.Variants[0].IsDefault || created.Variants[0].Stock != 3 {
+		t.Fatalf("❌ Variante par défaut avec tout le stock attendue, obtenu %+v", created.Variants)
+	}
+	defaultID := created.Variants[0].ID
+
+	t.Run("Un client ne gère pas les variantes", func(t *testing.T) {
+		resp := customerClient.MustDoRequest(t, "POST", "/api/products/"+productID+"/variants", map[string]interface{}{
+			"sku": fmt.Sprintf("TSHIRT-S-%d", suffix), "options": map[string]string{"size": "S"},
+		})
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusForbidden)
+	})
+
+	resp = adminClient.MustDoRequest(t, "POST", "/api/products/"+productID+"/variants", map[string]interface{}{
+		"sku": fmt.Sprintf("TSHIRT-L-%d", suffix), "options": map[string]string{"size": "L"}, "price_cents": 2500, "stock": 4,
+	})
+	testutilitis.AssertStatus(t, resp, http.StatusCreated)
+	largeID := testutilitis.ExtractID(t, resp)
+	resp.Body.Close()
+
+	t.Run("Options déjà utilisées", func(t *testing.T) {
+		resp := adminClient.MustDoRequest(t, "POST", "/api/products/"+productID+"/variants", map[string]interface{}{
+			"sku": fmt.Sprintf("TSHIRT-L2-%d", suffix), "options": map[string]string{"size": "L"},
+		})
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusConflict)
+	})
+
+	if got := getProductWithVariants(t, customerClient, productID); got.Stock != 7 {
+		t.Fatalf("❌ Stock total 7 attendu, obtenu %d", got.Stock)
+	}
+
+	var orderID string
+	t.Run("Commande d'une variante", func(t *testing.T) {
+		resp := customerClient.MustDoRequest(t, "POST", "/api/orders", map[string]interface{}{
+			"items": []map[string]interface{}{
+				{"product_id": productID, "variant_id": largeID, "quantity": 2},
+				{"product_id": productID, "quantity": 1},
+			},
+		})
+		testutilitis.AssertStatus(t, resp, http.StatusCreated)
+		var order struct {
+			ID         string `json:"id"`
+			TotalCents int64  `json:"total_cents"`
+		}
+		testutilitis.ParseJSONBody(t, resp, &order)
+		resp.Body.Close()
+		orderID = order.ID
+
+		if order.TotalCents != 2*2500+2000 {
+			t.Errorf("❌ Total %d attendu (prix de la variante), obtenu %d", 2*2500+2000, order.TotalCents)
+		}
+
+		got := getProductWithVariants(t, customerClient, productID)
+		if got.Stock != 4 {
+			t.Errorf("❌ Stock total 4 attendu, obtenu %d", got.Stock)
+		}
+		for _, v := range got.Variants {
+			if v.ID == largeID && v.Stock != 2 {
+				t.Errorf("❌ Stock 2 attendu pour la variante L, obtenu %d", v.Stock)
+			}
+			if v.ID == defaultID && v.Stock != 2 {
+				t.Errorf("❌ Stock 2 attendu pour la variante par défaut, obtenu %d", v.Stock)
+			}
+		}
+	})
+
+	t.Run("Variante inconnue", func(t *testing.T) {
+		resp := customerClient.MustDoRequest(t, "POST", "/api/orders", map[string]interface{}{
+			"items": []map[string]interface{}{
+				{"product_id": productID, "variant_id": "00000000-0000-0000-0000-000000000000", "quantity": 1},
+			},
+		})
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusBadRequest)
+	})
+
+	t.Run("Annulation", func(t *testing.T) {
+		if orderID == "" {
+			t.Skip("commande non créée")
+		}
+		resp := customerClient.MustDoRequest(t, "POST", "/api/orders/"+orderID+"/cancel", map[string]interface{}{})
+		testutilitis.AssertStatus(t, resp, http.StatusOK)
+		resp.Body.Close()
+
+		if got := getProductWithVariants(t, customerClient, productID); got.Stock != 7 {
+			t.Errorf("❌ Stock total 7 attendu après annulation, obtenu %d", got.Stock)
+		}
+	})
+
+	t.Run("Suppression", func(t *testing.T) {
+		resp := adminClient.MustDoRequest(t, "DELETE", "/api/products/"+productID+"/variants/"+defaultID, nil)
+		testutilitis.AssertStatus(t, resp, http.StatusConflict)
+		resp.Body.Close()
+
+		// Variante commandée : référencée par les articles de la commande
+		resp = adminClient.MustDoRequest(t, "DELETE", "/api/products/"+productID+"/variants/"+largeID, nil)
+		defer resp.Body.Close()
+		testutilitis.AssertStatus(t, resp, http.StatusConflict)
+	})
+}
@@ -0,0 +1,78 @@
+// tests/testutilitis/auth.go
+package testutilitis
+
+import (
+	"database/sql"
+	"testing"
+)
+
+const DefaultTestPassword = "Password123!"
+
+// RegisterAndLogin crée un compte, optionnellement promu avec `roles`,
+// puis active son access token sur le client. Les rôles doivent être
+// accordés avant le login pour figurer dans le token.
+func RegisterAndLogin(t *testing.T, client *HTTPClient, db *sql.DB, email string, roles ...string) {
+	t.Helper()
+
+	credentials := map[string]interface{}{
+		"email":    email,
+		"password": DefaultTestPassword,
+	}
+
+	resp := client.MustDoRequest(t, "POST", "/register", credentials)
+	AssertStatus(t, resp, 201)
+	resp.Body.Close()
+
+	for _, role := range roles {
+		GrantRole(t, db, email, role)
+	}
+
+	resp = client.MustDoRequest(t, "POST", "/login", credentials)
+	AssertStatus(t, resp, 200)
+	var loginResp struct {
+		Token string `json:"token"`
+	}
+	ParseJSONBody(t, resp, &loginResp)
+	resp.Body.Close()
+
+	client.SetToken(loginResp.Token)
+}
+
+// GrantRole attribue un rôle directement en base (bootstrap d'un admin)
+func GrantRole(t *testing.T, db *sql.DB, email, role string) {
+	t.Helper()
+
+	_, err := db.Exec(`
+		INSERT INTO user_roles (user_id, role)
+		SELECT id, $2 FROM users WHERE LOWER(email) = LOWER($1)
+		ON CONFLICT (user_id, role) DO NOTHING`, email, role)
+	if err != nil {
+		t.Fatalf("❌ Attribution du rôle %s échouée: %v", role, err)
+	}
+}
+
+// UserIDByEmail retourne l'ID d'un utilisateur
+func UserIDByEmail(t *testing.T, db *sql.DB, email string) string {
+	t.Helper()
+
+	var id string
+	if err := db.QueryRow(`SELECT id FROM users WHERE LOWER(email) = LOWER($1)`, email).Scan(&id); err != nil {
+		t.Fatalf("❌ Utilisateur %s introuvable: %v", email, err)
+	}
+	return id
+}
+
+// CustomerIDByEmail retourne l'ID du profil client créé à l'inscription
+func CustomerIDByEmail(t *testing.T, db *sql.DB, email string) string {
+	t.Helper()
+
+	var id string
+	err := db.QueryRow(`
+		SELECT c.id FROM customers c
+		JOIN users u ON u.id = c.user_id
+		WHERE LOWER(u.email) = LOWER($1)`, email).Scan(&id)
+	if err != nil {
+		t.Fatalf("❌ Profil client de %s introuvable: %v", email, err)
+	}
+	return id
+}
@@ -0,0 +1,77 @@
+// tests/testutilitis/mail.go
+package testutilitis
+
+import (
+	"io"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// mailWaitTimeout : certains liens (mot de passe oublié) partent en
+// arrière-plan, après la réponse HTTP
+const mailWaitTimeout = 5 * time.Second
+
+// MailToken retourne le jeton du dernier lien `path` ("/reset-password")
+// envoyé à `to`, lu dans les fichiers .eml du TestServer
+func MailToken(t *testing.T, mailDir, to, path string) string {
+	t.Helper()
+
+	link := regexp.MustCompile(regexp.QuoteMeta(path) + `\?token=(\S+)`)
+	deadline := time.Now().Add(mailWaitTimeout)
+	for {
+		if token, ok := findMailToken(t, mailDir, to, link); ok {
+			return token
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("❌ Aucun lien %s envoyé à %s", path, to)
+			return ""
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func findMailToken(t *testing.T, mailDir, to string, link *regexp.Regexp) (string, bool) {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(mailDir, "*.eml"))
+	if err != nil {
+		t.Fatalf("❌ Lecture des emails: %v", err)
+	}
+
+	// Fichiers nommés par date d'envoi : le dernier est le plus récent
+	for i := len(files) - 1; i >= 0; i-- {
+		f, err := os.Open(files[i])
+		if err != nil {
+			t.Fatalf("❌ Lecture de %s: %v", files[i], err)
+		}
+		msg, err := mail.ReadMessage(f)
+		if err != nil {
+			// Email en cours d'écriture : relu au prochain passage
+			f.Close()
+			continue
+		}
+		body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+		f.Close()
+		if err != nil {
+			continue
+		}
+
+		if msg.Header.Get("To") != to {
+			continue
+		}
+		if m := link.FindSubmatch(body); m != nil {
+			token, err := url.QueryUnescape(string(m[1]))
+			if err != nil {
+				t.Fatalf("❌ Jeton mal encodé: %v", err)
+			}
+			return token, true
+		}
+	}
+	return "", false
+}
@@ -2,17 +2,27 @@
 package testutilitis
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"os"
+	"io/fs"
+
+	"Goshop/infrastructure/postgres/migrate"
+	"Goshop/migrations"
 )
 
-// RunMigrations applique les migrations SQL à la base de données de test
+// RunMigrations applique les migrations embarquées à la base de données de test
 func RunMigrations(db *sql.DB) error {
-	sqlFile, err := os.ReadFile("../migrations/001_init.sql")
+	return runMigrations(db, migrations.FS)
+}
+
+func runMigrations(db *sql.DB, fsys fs.FS) error {
+	migrator, err := migrate.New(db, fsys)
 	if err != nil {
-		return fmt.Errorf("lecture migration: %w", err)
+		return fmt.Errorf("lecture migrations: %w", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		return fmt.Errorf("exécution migrations: %w", err)
 	}
-	_, err = db.Exec(string(sqlFile))
-	return err
+	return nil
 }
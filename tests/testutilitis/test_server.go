@@ -3,12 +3,10 @@ package testutilitis
 
 import (
 	"database/sql"
-	"fmt"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
-	"sort"
 	"testing"
 
 	"github.com/joho/godotenv"
@@ -16,7 +14,10 @@ import (
 
 	"Goshop/config"
 	"Goshop/config/setupLogging"
+	mailerinfra "Goshop/infrastructure/mailer"
 	"Goshop/infrastructure/postgres"
+	storageinfra "Goshop/infrastructure/storage"
+	"Goshop/interfaces/utils"
 	"Goshop/internal/app"
 )
 
@@ -31,6 +32,10 @@ type TestServer struct {
 	URL string
 	DB  *sql.DB
 	Srv *httptest.Server
+	// MailDir : emails envoyés par l'application (fichiers .eml)
+	MailDir string
+	// BlobDir : contenu des images produit (stockage local)
+	BlobDir string
 }
 
 // NewTestServer démarre l'application réelle avec la base de test
@@ -53,9 +58,8 @@ func NewTestServer(t *testing.T) *TestServer {
 		t.Fatalf("❌ Connexion à la base de test échouée: %v", err)
 	}
 
-	// 2. Appliquer les migrations depuis le bon répertoire
-	migrationDir := filepath.Join(projectRoot, "migrations")
-	if err := RunMigrationsFromDir(db, migrationDir); err != nil {
+	// 2. Appliquer les migrations embarquées (même moteur que cmd/migrate)
+	if err := RunMigrations(db); err != nil {
 		t.Fatalf("❌ Échec des migrations: %v", err)
 	}
 
@@ -73,48 +77,69 @@ func NewTestServer(t *testing.T) *TestServer {
 		LogLevel:    "warn",
 	})
 
-	appInstance := app.NewApp(db, logger)
-	server := httptest.NewServer(appInstance.Handler())
-	t.Cleanup(server.Close)
+	// Mêmes claims et durées de vie que cmd/api
+	if err := utils.InitJWTConfig(utils.JWTConfig{
+		Issuer:     cfg.JWTIssuer,
+		Audience:   cfg.JWTAudience,
+		AccessTTL:  cfg.AccessTokenTTL,
+		RefreshTTL: cfg.RefreshTokenTTL,
+		Leeway:     cfg.JWTLeeway,
+	}); err != nil {
+		t.Fatalf("❌ Configuration JWT invalide: %v", err)
+	}
 
-	t.Logf("✅ TestServer démarré sur %s", server.URL)
-	return &TestServer{
-		URL: server.URL,
-		DB:  db,
-		Srv: server,
+	// Trousseau JWT éphémère (avant NewApp : le handler JWKS le publie)
+	keys, err := utils.NewEphemeralKeySet()
+	if err != nil {
+		t.Fatalf("❌ Génération des clés JWT de test: %v", err)
 	}
-}
+	utils.InitJWTKeys(keys)
 
-// RunMigrationsFromDir applique les migrations depuis un répertoire spécifique
-func RunMigrationsFromDir(db *sql.DB, migrationDir string) error {
-	files, err := filepath.Glob(filepath.Join(migrationDir, "*.sql"))
+	// Emails écrits sur disque : les tests lisent les liens envoyés
+	mailDir := t.TempDir()
+	fileMailer, err := mailerinfra.NewFileMailer(mailDir)
 	if err != nil {
-		return fmt.Errorf("lecture répertoire migrations: %w", err)
+		t.Fatalf("❌ Mailer de test: %v", err)
 	}
 
-	sort.Strings(files)
+	// Images stockées sur disque, dans un répertoire propre au test
+	blobDir := t.TempDir()
+	blobs, err := storageinfra.NewLocalBlobStore(blobDir)
+	if err != nil {
+		t.Fatalf("❌ Stockage des images de test: %v", err)
+	}
 
-	for _, file := range files {
-		migrationSQL, err := os.ReadFile(file)
-		if err != nil {
-			return fmt.Errorf("lecture migration: %w", err)
-		}
+	appInstance := app.NewApp(db, logger,
+		app.MailConfig{Mailer: fileMailer, PublicURL: "http://goshop.test"},
+		app.MediaConfig{Blobs: blobs},
+	)
+	server := httptest.NewServer(appInstance.Handler())
+	t.Cleanup(server.Close)
 
-		_, err = db.Exec(string(migrationSQL))
-		if err != nil {
-			return fmt.Errorf("exécution migration %s: %w", file, err)
-		}
+	t.Logf("✅ TestServer démarré sur %s", server.URL)
+	return &TestServer{
+		URL:     server.URL,
+		DB:      db,
+		Srv:     server,
+		MailDir: mailDir,
+		BlobDir: blobDir,
 	}
+}
 
-	return nil
+// RunMigrationsFromDir applique les migrations en attente d'un répertoire
+// spécifique, avec le même moteur que cmd/migrate
+func RunMigrationsFromDir(db *sql.DB, migrationDir string) error {
+	return runMigrations(db, os.DirFS(migrationDir))
 }
 
 // truncateTables vide toutes les tables
 func truncateTables(t *testing.T, db *sql.DB) {
 	t.Helper()
 	tables := []string{
-		"order_items", "orders", "products",
-		"customers", "refresh_sessions", "users",
+		"order_status_history", "order_items", "orders", "products",
+		"customers", "refresh_sessions", "user_roles", "users", "idempotency_keys",
+		"login_attempts", "user_tokens", "user_mfa", "mfa_recovery_codes", "api_keys",
+		"product_categories", "categories", "product_variants", "product_images",
 	}
 	for _, table := range tables {
 		_, err := db.Exec("TRUNCATE TABLE " + table + " RESTART IDENTITY CASCADE")